@@ -7,6 +7,7 @@ import (
 	"context"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/pkg/errors"
@@ -171,6 +172,18 @@ func (svc *mainfluxThings) UpdateKey(context.Context, string, string, string) er
 	panic("not implemented")
 }
 
+func (svc *mainfluxThings) RotateKey(context.Context, string, string, string, time.Duration) (string, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) AddKey(context.Context, string, string, things.Key) (things.Key, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) RemoveKey(context.Context, string, string, string) error {
+	panic("not implemented")
+}
+
 func (svc *mainfluxThings) ListThings(context.Context, string, things.PageMetadata) (things.Page, error) {
 	panic("not implemented")
 }
@@ -209,10 +222,26 @@ func (svc *mainfluxThings) ListChannels(context.Context, string, things.PageMeta
 	panic("not implemented")
 }
 
+func (svc *mainfluxThings) RestoreThing(context.Context, string, string) (things.Thing, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) PurgeThings(context.Context, time.Time) ([]string, error) {
+	panic("not implemented")
+}
+
 func (svc *mainfluxThings) RemoveChannel(context.Context, string, string) error {
 	panic("not implemented")
 }
 
+func (svc *mainfluxThings) RestoreChannel(context.Context, string, string) (things.Channel, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) PurgeChannels(context.Context, time.Time) ([]string, error) {
+	panic("not implemented")
+}
+
 func (svc *mainfluxThings) CanAccessByKey(context.Context, string, string) (string, error) {
 	panic("not implemented")
 }
@@ -225,10 +254,22 @@ func (svc *mainfluxThings) IsChannelOwner(context.Context, string, string) error
 	panic("not implemented")
 }
 
+func (svc *mainfluxThings) IsChannelsOwner(context.Context, string, []string) error {
+	panic("not implemented")
+}
+
 func (svc *mainfluxThings) Identify(context.Context, string) (string, error) {
 	panic("not implemented")
 }
 
+func (svc *mainfluxThings) ViewThingByID(context.Context, string) (things.Thing, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ViewChannelByID(context.Context, string) (things.Channel, error) {
+	panic("not implemented")
+}
+
 func (svc *mainfluxThings) ShareThing(ctx context.Context, token, thingID string, actions, userIDs []string) error {
 	panic("not implemented")
 }
@@ -246,3 +287,59 @@ func findIndex(list []string, val string) int {
 func (svc *mainfluxThings) ListMembers(ctx context.Context, token, groupID string, pm things.PageMetadata) (things.Page, error) {
 	panic("not implemented")
 }
+
+func (svc *mainfluxThings) UpdateQuota(context.Context, string, string, things.Quota) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ViewQuota(context.Context, string, string) (things.Quota, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ViewThingQuota(context.Context, string) (things.ThingQuota, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ViewStatus(context.Context, string, string) (things.Status, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) UpdateStatus(context.Context, things.Status) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) CreateProfile(context.Context, string, things.Profile) (things.Profile, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) UpdateProfile(context.Context, string, things.Profile) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ViewProfile(context.Context, string, string) (things.Profile, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ListProfiles(context.Context, string, things.PageMetadata) (things.ProfilesPage, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) RemoveProfile(context.Context, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ImportThings(context.Context, string, []things.Record, bool) ([]things.ImportResult, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ImportChannels(context.Context, string, []things.Record, bool) ([]things.ImportResult, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ExportThings(context.Context, string) ([]things.Record, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ExportChannels(context.Context, string) ([]things.Record, error) {
+	panic("not implemented")
+}
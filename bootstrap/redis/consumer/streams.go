@@ -18,12 +18,12 @@ const (
 	group  = "mainflux.bootstrap"
 
 	thingPrefix     = "thing."
-	thingRemove     = thingPrefix + "remove"
+	thingPurge      = thingPrefix + "purge"
 	thingDisconnect = thingPrefix + "disconnect"
 
 	channelPrefix = "channel."
 	channelUpdate = channelPrefix + "update"
-	channelRemove = channelPrefix + "remove"
+	channelPurge  = channelPrefix + "purge"
 
 	exists = "BUSYGROUP Consumer Group name already exists"
 )
@@ -72,8 +72,10 @@ func (es eventStore) Subscribe(ctx context.Context, subject string) error {
 			event := msg.Values
 
 			var err error
+			// The configs are removed once the things and the channels
+			// are purged, so the restored ones keep their configs.
 			switch event["operation"] {
-			case thingRemove:
+			case thingPurge:
 				rte := decodeRemoveThing(event)
 				err = es.svc.RemoveConfigHandler(ctx, rte.id)
 			case thingDisconnect:
@@ -82,7 +84,7 @@ func (es eventStore) Subscribe(ctx context.Context, subject string) error {
 			case channelUpdate:
 				uce := decodeUpdateChannel(event)
 				err = es.handleUpdateChannel(ctx, uce)
-			case channelRemove:
+			case channelPurge:
 				rce := decodeRemoveChannel(event)
 				err = es.svc.RemoveChannelHandler(ctx, rce.id)
 			}
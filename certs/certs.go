@@ -24,7 +24,8 @@ type Repository interface {
 	// Remove removes certificate from DB for a given thing ID
 	Remove(ctx context.Context, ownerID, thingID string) error
 
-	// RetrieveByThing retrieves issued certificates for a given thing ID
+	// RetrieveByThing retrieves issued certificates for a given thing ID.
+	// Empty owner ID retrieves the certificates regardless of their owner.
 	RetrieveByThing(ctx context.Context, ownerID, thingID string, offset, limit uint64) (Page, error)
 
 	// RetrieveBySerial retrieves a certificate for a given serial ID
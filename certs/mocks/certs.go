@@ -104,6 +104,12 @@ func (c *certsRepoMock) RetrieveByThing(ctx context.Context, ownerID, thingID st
 	}
 
 	cs, ok := c.certsByThingID[ownerID][thingID]
+	if ownerID == "" {
+		for _, tc := range c.certsByThingID {
+			cs = append(cs, tc[thingID]...)
+		}
+		ok = true
+	}
 	if !ok {
 		return certs.Page{}, errors.ErrNotFound
 	}
@@ -117,7 +117,7 @@ func (cr certsRepository) Remove(ctx context.Context, ownerID, serial string) er
 }
 
 func (cr certsRepository) RetrieveByThing(ctx context.Context, ownerID, thingID string, offset, limit uint64) (certs.Page, error) {
-	q := `SELECT thing_id, owner_id, serial, expire FROM certs WHERE owner_id = $1 AND thing_id = $2 ORDER BY expire LIMIT $3 OFFSET $4;`
+	q := `SELECT thing_id, owner_id, serial, expire FROM certs WHERE ($1 = '' OR owner_id = $1) AND thing_id = $2 ORDER BY expire LIMIT $3 OFFSET $4;`
 	rows, err := cr.db.Query(q, ownerID, thingID, limit, offset)
 	if err != nil {
 		cr.log.Error(fmt.Sprintf("Failed to retrieve configs due to %s", err))
@@ -136,7 +136,7 @@ func (cr certsRepository) RetrieveByThing(ctx context.Context, ownerID, thingID
 		certificates = append(certificates, c)
 	}
 
-	q = `SELECT COUNT(*) FROM certs WHERE owner_id = $1 AND thing_id = $2`
+	q = `SELECT COUNT(*) FROM certs WHERE ($1 = '' OR owner_id = $1) AND thing_id = $2`
 	var total uint64
 	if err := cr.db.QueryRow(q, ownerID, thingID).Scan(&total); err != nil {
 		cr.log.Error(fmt.Sprintf("Failed to count certs due to %s", err))
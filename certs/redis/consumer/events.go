@@ -0,0 +1,8 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumer
+
+type purgeEvent struct {
+	id string
+}
@@ -44,6 +44,11 @@ type Service interface {
 
 	// RevokeCert revokes a certificate for a given serial ID
 	RevokeCert(ctx context.Context, token, serialID string) (Revoke, error)
+
+	// RevokeThingCerts revokes all the certificates issued for a given thing
+	// ID, regardless of their owner. It's intended for the internal use by
+	// the event consumer once the thing is purged.
+	RevokeThingCerts(ctx context.Context, thingID string) error
 }
 
 // Config defines the service parameters
@@ -169,6 +174,25 @@ func (cs *certsService) RevokeCert(ctx context.Context, token, thingID string) (
 	return revoke, nil
 }
 
+func (cs *certsService) RevokeThingCerts(ctx context.Context, thingID string) error {
+	offset, limit := uint64(0), uint64(10000)
+	cp, err := cs.certsRepo.RetrieveByThing(ctx, "", thingID, offset, limit)
+	if err != nil {
+		return errors.Wrap(ErrFailedCertRevocation, err)
+	}
+
+	for _, c := range cp.Certs {
+		if _, err := cs.pki.Revoke(c.Serial); err != nil {
+			return errors.Wrap(ErrFailedCertRevocation, err)
+		}
+		if err := cs.certsRepo.Remove(ctx, c.OwnerID, c.Serial); err != nil {
+			return errors.Wrap(errFailedToRemoveCertFromDB, err)
+		}
+	}
+
+	return nil
+}
+
 func (cs *certsService) ListCerts(ctx context.Context, token, thingID string, offset, limit uint64) (Page, error) {
 	u, err := cs.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
@@ -225,6 +225,39 @@ func TestRevokeCert(t *testing.T) {
 
 }
 
+func TestRevokeThingCerts(t *testing.T) {
+	svc, err := newService(map[string]string{token: email})
+	require.Nil(t, err, fmt.Sprintf("unexpected service creation error: %s\n", err))
+
+	ic, err := svc.IssueCert(context.Background(), token, thingID, ttl, keyBits, key)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert creation error: %s\n", err))
+
+	cases := []struct {
+		desc    string
+		thingID string
+		err     error
+	}{
+		{
+			desc:    "revoke certs of thing without certs",
+			thingID: "2",
+			err:     nil,
+		},
+		{
+			desc:    "revoke certs of thing",
+			thingID: thingID,
+			err:     nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RevokeThingCerts(context.Background(), tc.thingID)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.ViewCert(context.Background(), token, ic.Serial)
+	assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("view revoked cert: expected %s got %s\n", errors.ErrNotFound, err))
+}
+
 func TestListCerts(t *testing.T) {
 	svc, err := newService(map[string]string{token: email})
 	require.Nil(t, err, fmt.Sprintf("unexpected service creation error: %s\n", err))
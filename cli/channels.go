@@ -5,6 +5,7 @@ package cli
 
 import (
 	"encoding/json"
+	"os"
 
 	mfxsdk "github.com/mainflux/mainflux/pkg/sdk/go"
 	"github.com/spf13/cobra"
@@ -146,14 +147,69 @@ var cmdChannels = []cobra.Command{
 			logJSON(cl)
 		},
 	},
+	{
+		Use:   "import <file> <user_auth_token>",
+		Short: "Import channels",
+		Long:  `Create or update channels from CSV or NDJSON file, matching them by ID, external ID or name`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Use)
+				return
+			}
+
+			data, ct, err := importFile(args[0])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			report, err := sdk.ImportChannels(data, ct, DryRun, args[1])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			logJSON(report)
+		},
+	},
+	{
+		Use:   "export <file> <user_auth_token>",
+		Short: "Export channels",
+		Long:  `Export channels to CSV or NDJSON file`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Use)
+				return
+			}
+
+			output, err := exportOutput(args[0])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			data, err := sdk.ExportChannels(output, args[1])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			if err := os.WriteFile(args[0], data, 0644); err != nil {
+				logError(err)
+				return
+			}
+
+			logOK()
+		},
+	},
 }
 
 // NewChannelsCmd returns channels command.
 func NewChannelsCmd() *cobra.Command {
 	cmd := cobra.Command{
-		Use:   "channels [create | get | update | delete | connections | not-connected]",
+		Use:   "channels [create | get | update | delete | connections | not-connected | import | export]",
 		Short: "Channels management",
-		Long:  `Channels management: create, get, update or delete Channel and get list of Things connected or not connected to a Channel`,
+		Long:  `Channels management: create, get, update or delete Channel and get list of Things connected or not connected to a Channel, import or export Channels`,
 	}
 
 	for i := range cmdChannels {
@@ -21,6 +21,9 @@ import (
 
 const jsonExt = ".json"
 const csvExt = ".csv"
+const ndjsonExt = ".ndjson"
+
+var errFileExt = errors.New("unsupported file extension")
 
 var cmdProvision = []cobra.Command{
 	{
@@ -344,3 +347,36 @@ func connectionsFromFile(path string) (mfxsdk.ConnectionIDs, error) {
 
 	return connections, nil
 }
+
+// importFile reads the things or channels import file, with the content type
+// determined by its extension.
+func importFile(path string) ([]byte, mfxsdk.ContentType, error) {
+	var ct mfxsdk.ContentType
+	switch filepath.Ext(path) {
+	case csvExt:
+		ct = mfxsdk.CTCSV
+	case ndjsonExt:
+		ct = mfxsdk.CTNDJSON
+	default:
+		return nil, "", errFileExt
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, ct, nil
+}
+
+// exportOutput returns the export output format matching the file extension.
+func exportOutput(path string) (string, error) {
+	switch filepath.Ext(path) {
+	case csvExt:
+		return "csv", nil
+	case ndjsonExt:
+		return "ndjson", nil
+	default:
+		return "", errFileExt
+	}
+}
@@ -5,6 +5,7 @@ package cli
 
 import (
 	"encoding/json"
+	"os"
 
 	mfxsdk "github.com/mainflux/mainflux/pkg/sdk/go"
 	"github.com/spf13/cobra"
@@ -185,14 +186,69 @@ var cmdThings = []cobra.Command{
 			logJSON(cl)
 		},
 	},
+	{
+		Use:   "import <file> <user_auth_token>",
+		Short: "Import things",
+		Long:  `Create or update things from CSV or NDJSON file, matching them by ID, external ID or name, and connect them to the listed channels`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Use)
+				return
+			}
+
+			data, ct, err := importFile(args[0])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			report, err := sdk.ImportThings(data, ct, DryRun, args[1])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			logJSON(report)
+		},
+	},
+	{
+		Use:   "export <file> <user_auth_token>",
+		Short: "Export things",
+		Long:  `Export things with their connections to CSV or NDJSON file`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Use)
+				return
+			}
+
+			output, err := exportOutput(args[0])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			data, err := sdk.ExportThings(output, args[1])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			if err := os.WriteFile(args[0], data, 0644); err != nil {
+				logError(err)
+				return
+			}
+
+			logOK()
+		},
+	},
 }
 
 // NewThingsCmd returns things command.
 func NewThingsCmd() *cobra.Command {
 	cmd := cobra.Command{
-		Use:   "things [create | get | update | delete | connect | disconnect | connections | not-connected]",
+		Use:   "things [create | get | update | delete | connect | disconnect | connections | not-connected | import | export]",
 		Short: "Things management",
-		Long:  `Things management: create, get, update or delete Thing, connect or disconnect Thing from Channel and get the list of Channels connected or disconnected from a Thing`,
+		Long:  `Things management: create, get, update or delete Thing, connect or disconnect Thing from Channel and get the list of Channels connected or disconnected from a Thing, import or export Things`,
 	}
 
 	for i := range cmdThings {
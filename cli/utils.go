@@ -22,6 +22,8 @@ var (
 	ConfigPath string = ""
 	// RawOutput raw output mode
 	RawOutput bool = false
+	// DryRun import dry run mode
+	DryRun bool = false
 )
 
 func logJSON(iList ...interface{}) {
@@ -12,49 +12,101 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/gocql/gocql"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/consumers/batch"
+	"github.com/mainflux/mainflux/consumers/spill"
 	"github.com/mainflux/mainflux/consumers/writers/api"
 	"github.com/mainflux/mainflux/consumers/writers/cassandra"
 	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging/brokers"
 	"github.com/mainflux/mainflux/pkg/messaging/nats"
+	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
+	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 const (
 	svcName = "cassandra-writer"
 	sep     = ","
 
-	defNatsURL    = "nats://localhost:4222"
-	defLogLevel   = "error"
-	defPort       = "8180"
-	defCluster    = "127.0.0.1"
-	defKeyspace   = "mainflux"
-	defDBUser     = "mainflux"
-	defDBPass     = "mainflux"
-	defDBPort     = "9042"
-	defConfigPath = "/config.toml"
-
-	envNatsURL    = "MF_NATS_URL"
-	envLogLevel   = "MF_CASSANDRA_WRITER_LOG_LEVEL"
-	envPort       = "MF_CASSANDRA_WRITER_PORT"
-	envCluster    = "MF_CASSANDRA_WRITER_DB_CLUSTER"
-	envKeyspace   = "MF_CASSANDRA_WRITER_DB_KEYSPACE"
-	envDBUser     = "MF_CASSANDRA_WRITER_DB_USER"
-	envDBPass     = "MF_CASSANDRA_WRITER_DB_PASS"
-	envDBPort     = "MF_CASSANDRA_WRITER_DB_PORT"
-	envConfigPath = "MF_CASSANDRA_WRITER_CONFIG_PATH"
+	defNatsURL       = "nats://localhost:4222"
+	defBrokerType    = "nats"
+	defKafkaURL      = "localhost:9092"
+	defJetStream     = "false"
+	defJSMaxDeliver  = "5"
+	defJSBackoff     = "1s"
+	defJSDeadLetter  = "deadletter"
+	defThingsTLS     = "false"
+	defThingsCACerts = ""
+	defThingsURL     = ""
+	defThingsTimeout = "1s"
+	defLogLevel      = "error"
+	defPort          = "8180"
+	defCluster       = "127.0.0.1"
+	defKeyspace      = "mainflux"
+	defDBUser        = "mainflux"
+	defDBPass        = "mainflux"
+	defDBPort        = "9042"
+	defConfigPath    = "/config.toml"
+	defBatchSize     = "0"
+	defBatchTimeout  = "1s"
+	defBatchRetries  = "3"
+	defBatchBackoff  = "100ms"
+	defSpillPath     = ""
+	defSpillMaxSize  = "1073741824"
+	defSpillRetry    = "5s"
+	defSpillAttempts = "10"
+
+	envNatsURL       = "MF_NATS_URL"
+	envBrokerType    = "MF_BROKER_TYPE"
+	envKafkaURL      = "MF_KAFKA_URL"
+	envJetStream     = "MF_NATS_JETSTREAM"
+	envJSMaxDeliver  = "MF_NATS_JETSTREAM_MAX_DELIVER"
+	envJSBackoff     = "MF_NATS_JETSTREAM_BACKOFF"
+	envJSDeadLetter  = "MF_NATS_JETSTREAM_DEAD_LETTER"
+	envThingsTLS     = "MF_THINGS_CLIENT_TLS"
+	envThingsCACerts = "MF_THINGS_CA_CERTS"
+	envThingsURL     = "MF_THINGS_AUTH_GRPC_URL"
+	envThingsTimeout = "MF_THINGS_AUTH_GRPC_TIMEOUT"
+	envLogLevel      = "MF_CASSANDRA_WRITER_LOG_LEVEL"
+	envPort          = "MF_CASSANDRA_WRITER_PORT"
+	envCluster       = "MF_CASSANDRA_WRITER_DB_CLUSTER"
+	envKeyspace      = "MF_CASSANDRA_WRITER_DB_KEYSPACE"
+	envDBUser        = "MF_CASSANDRA_WRITER_DB_USER"
+	envDBPass        = "MF_CASSANDRA_WRITER_DB_PASS"
+	envDBPort        = "MF_CASSANDRA_WRITER_DB_PORT"
+	envConfigPath    = "MF_CASSANDRA_WRITER_CONFIG_PATH"
+	envBatchSize     = "MF_CASSANDRA_WRITER_BATCH_SIZE"
+	envBatchTimeout  = "MF_CASSANDRA_WRITER_BATCH_TIMEOUT"
+	envBatchRetries  = "MF_CASSANDRA_WRITER_BATCH_MAX_RETRIES"
+	envBatchBackoff  = "MF_CASSANDRA_WRITER_BATCH_BACKOFF"
+	envSpillPath     = "MF_CASSANDRA_WRITER_SPILL_PATH"
+	envSpillMaxSize  = "MF_CASSANDRA_WRITER_SPILL_MAX_SIZE"
+	envSpillRetry    = "MF_CASSANDRA_WRITER_SPILL_RETRY_INTERVAL"
+	envSpillAttempts = "MF_CASSANDRA_WRITER_SPILL_MAX_ATTEMPTS"
 )
 
 type config struct {
-	natsURL    string
-	logLevel   string
-	port       string
-	configPath string
-	dbCfg      cassandra.DBConfig
+	brokerCfg     brokers.Config
+	jetStream     bool
+	jsConfig      nats.JetStreamConfig
+	thingsTLS     bool
+	thingsCACerts string
+	thingsURL     string
+	thingsTimeout time.Duration
+	logLevel      string
+	port          string
+	configPath    string
+	dbCfg         cassandra.DBConfig
+	batchCfg      batch.Config
+	spillCfg      spill.Config
 }
 
 func main() {
@@ -65,9 +117,9 @@ func main() {
 		log.Fatalf(err.Error())
 	}
 
-	pubSub, err := nats.NewPubSub(cfg.natsURL, "", logger)
+	pubSub, err := newPubSub(cfg, logger)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
 		os.Exit(1)
 	}
 	defer pubSub.Close()
@@ -77,7 +129,22 @@ func main() {
 
 	repo := newService(session, logger)
 
-	if err := consumers.Start(pubSub, repo, cfg.configPath, logger); err != nil {
+	repo, spillClose := newSpill(repo, cfg.spillCfg, logger)
+	if spillClose != nil {
+		defer spillClose()
+	}
+
+	repo, batcherClose := newBatcher(repo, cfg, logger)
+	if batcherClose != nil {
+		defer batcherClose()
+	}
+
+	things, thingsClose := connectToThings(cfg, opentracing.NoopTracer{}, logger)
+	if thingsClose != nil {
+		defer thingsClose()
+	}
+
+	if err := consumers.Start(pubSub, repo, cfg.configPath, things, logger); err != nil {
 		logger.Error(fmt.Sprintf("Failed to create Cassandra writer: %s", err))
 	}
 
@@ -96,6 +163,89 @@ func main() {
 }
 
 func loadConfig() config {
+	jetStream, err := strconv.ParseBool(mainflux.Env(envJetStream, defJetStream))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envJetStream)
+	}
+
+	jsMaxDeliver, err := strconv.Atoi(mainflux.Env(envJSMaxDeliver, defJSMaxDeliver))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envJSMaxDeliver, err.Error())
+	}
+
+	jsBackoff, err := time.ParseDuration(mainflux.Env(envJSBackoff, defJSBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envJSBackoff, err.Error())
+	}
+
+	jsConfig := nats.JetStreamConfig{
+		MaxDeliver: jsMaxDeliver,
+		Backoff:    jsBackoff,
+		DeadLetter: mainflux.Env(envJSDeadLetter, defJSDeadLetter),
+	}
+
+	thingsTLS, err := strconv.ParseBool(mainflux.Env(envThingsTLS, defThingsTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envThingsTLS)
+	}
+
+	thingsTimeout, err := time.ParseDuration(mainflux.Env(envThingsTimeout, defThingsTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
+	}
+
+	batchSize, err := strconv.Atoi(mainflux.Env(envBatchSize, defBatchSize))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBatchSize, err.Error())
+	}
+
+	batchTimeout, err := time.ParseDuration(mainflux.Env(envBatchTimeout, defBatchTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBatchTimeout, err.Error())
+	}
+
+	batchRetries, err := strconv.Atoi(mainflux.Env(envBatchRetries, defBatchRetries))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBatchRetries, err.Error())
+	}
+
+	batchBackoff, err := time.ParseDuration(mainflux.Env(envBatchBackoff, defBatchBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBatchBackoff, err.Error())
+	}
+
+	batchCfg := batch.Config{
+		Size:       batchSize,
+		Timeout:    batchTimeout,
+		MaxRetries: batchRetries,
+		Backoff:    batchBackoff,
+	}
+	// Consuming blocks until the batch is written, so the messages are handled
+	// concurrently, and the next batch fills while the previous one is written.
+	jsConfig.MaxInFlight = 2 * batchSize
+
+	spillMaxSize, err := strconv.ParseInt(mainflux.Env(envSpillMaxSize, defSpillMaxSize), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envSpillMaxSize, err.Error())
+	}
+
+	spillRetry, err := time.ParseDuration(mainflux.Env(envSpillRetry, defSpillRetry))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envSpillRetry, err.Error())
+	}
+
+	spillAttempts, err := strconv.Atoi(mainflux.Env(envSpillAttempts, defSpillAttempts))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envSpillAttempts, err.Error())
+	}
+
+	spillCfg := spill.Config{
+		Path:          mainflux.Env(envSpillPath, defSpillPath),
+		MaxSize:       spillMaxSize,
+		RetryInterval: spillRetry,
+		MaxAttempts:   spillAttempts,
+	}
+
 	dbPort, err := strconv.Atoi(mainflux.Env(envDBPort, defDBPort))
 	if err != nil {
 		log.Fatal(err)
@@ -110,11 +260,23 @@ func loadConfig() config {
 	}
 
 	return config{
-		natsURL:    mainflux.Env(envNatsURL, defNatsURL),
-		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
-		port:       mainflux.Env(envPort, defPort),
-		configPath: mainflux.Env(envConfigPath, defConfigPath),
-		dbCfg:      dbCfg,
+		brokerCfg: brokers.Config{
+			Type:     mainflux.Env(envBrokerType, defBrokerType),
+			NatsURL:  mainflux.Env(envNatsURL, defNatsURL),
+			KafkaURL: mainflux.Env(envKafkaURL, defKafkaURL),
+		},
+		jetStream:     jetStream,
+		jsConfig:      jsConfig,
+		thingsTLS:     thingsTLS,
+		thingsCACerts: mainflux.Env(envThingsCACerts, defThingsCACerts),
+		thingsURL:     mainflux.Env(envThingsURL, defThingsURL),
+		thingsTimeout: thingsTimeout,
+		logLevel:      mainflux.Env(envLogLevel, defLogLevel),
+		port:          mainflux.Env(envPort, defPort),
+		configPath:    mainflux.Env(envConfigPath, defConfigPath),
+		batchCfg:      batchCfg,
+		spillCfg:      spillCfg,
+		dbCfg:         dbCfg,
 	}
 }
 
@@ -155,3 +317,104 @@ func startHTTPServer(port string, errs chan error, logger logger.Logger) {
 	logger.Info(fmt.Sprintf("Cassandra writer service started, exposed port %s", port))
 	errs <- http.ListenAndServe(p, api.MakeHandler(svcName))
 }
+
+func newSpill(repo consumers.Consumer, cfg spill.Config, logger logger.Logger) (consumers.Consumer, func() error) {
+	if cfg.Path == "" {
+		logger.Info("Spill buffer path is not set, failed writes are not buffered")
+		return repo, nil
+	}
+
+	b, err := spill.New(repo, cfg, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create spill buffer: %s", err))
+		os.Exit(1)
+	}
+
+	stdprometheus.MustRegister(
+		stdprometheus.NewGaugeFunc(stdprometheus.GaugeOpts{
+			Namespace: "cassandra",
+			Subsystem: "message_writer",
+			Name:      "spill_size_bytes",
+			Help:      "Size of the spill buffer in bytes.",
+		}, func() float64 { return float64(b.Size()) }),
+		stdprometheus.NewGaugeFunc(stdprometheus.GaugeOpts{
+			Namespace: "cassandra",
+			Subsystem: "message_writer",
+			Name:      "spill_pending_writes",
+			Help:      "Number of writes waiting in the spill buffer.",
+		}, func() float64 { return float64(b.Pending()) }),
+	)
+
+	return b, b.Close
+}
+
+func newBatcher(repo consumers.Consumer, cfg config, logger logger.Logger) (consumers.Consumer, func() error) {
+	if cfg.batchCfg.Size < 1 {
+		logger.Info("Batch size is not set, messages are written as they come")
+		return repo, nil
+	}
+	// JetStream hands over the unacknowledged messages concurrently, so the
+	// messages are acknowledged once their batch is written. The other brokers
+	// hand the messages over one at a time, so the batch fills without waiting
+	// for the previous messages to be written.
+	if !cfg.jetStream || cfg.brokerCfg.Type != brokers.NATS {
+		logger.Info("Broker doesn't acknowledge messages, batches are written asynchronously")
+		cfg.batchCfg.Async = true
+	}
+
+	repo = api.BatchMetricsMiddleware(
+		repo,
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "cassandra",
+			Subsystem: "message_writer",
+			Name:      "batch_size",
+			Help:      "Number of messages in written batches.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "cassandra",
+			Subsystem: "message_writer",
+			Name:      "batch_flush_latency_seconds",
+			Help:      "Total duration of batch flushes in seconds.",
+		}, []string{"method"}),
+	)
+	b := batch.New(repo, cfg.batchCfg, logger)
+
+	return b, b.Close
+}
+
+func newPubSub(cfg config, logger logger.Logger) (brokers.PubSub, error) {
+	if cfg.jetStream && cfg.brokerCfg.Type == brokers.NATS {
+		return nats.NewJetStreamPubSub(cfg.brokerCfg.NatsURL, svcName, cfg.jsConfig, logger)
+	}
+	return brokers.NewPubSub(cfg.brokerCfg, "", logger)
+}
+
+func connectToThings(cfg config, tracer opentracing.Tracer, logger logger.Logger) (mainflux.ThingsServiceClient, func() error) {
+	if cfg.thingsURL == "" {
+		logger.Info("Things service URL is not set, thing metadata enrichment is disabled")
+		return nil, nil
+	}
+
+	var opts []grpc.DialOption
+	if cfg.thingsTLS {
+		if cfg.thingsCACerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.thingsCACerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.thingsURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things service: %s", err))
+		os.Exit(1)
+	}
+
+	return thingsapi.NewClient(conn, tracer, cfg.thingsTimeout), conn.Close
+}
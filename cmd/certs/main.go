@@ -2,6 +2,7 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
@@ -24,6 +25,7 @@ import (
 	"github.com/mainflux/mainflux/certs/api"
 	vault "github.com/mainflux/mainflux/certs/pki"
 	"github.com/mainflux/mainflux/certs/postgres"
+	rediscons "github.com/mainflux/mainflux/certs/redis/consumer"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
@@ -58,6 +60,10 @@ const (
 	defJaegerURL     = ""
 	defAuthURL       = "localhost:8181"
 	defAuthTimeout   = "1s"
+	defESURL         = "localhost:6379"
+	defESPass        = ""
+	defESDB          = "0"
+	defESConsumer    = "certs"
 
 	defSignCAPath     = "ca.crt"
 	defSignCAKeyPath  = "ca.key"
@@ -94,6 +100,10 @@ const (
 	envSignCAKey      = "MF_CERTS_SIGN_CA_KEY_PATH"
 	envSignHoursValid = "MF_CERTS_SIGN_HOURS_VALID"
 	envSignRSABits    = "MF_CERTS_SIGN_RSA_BITS"
+	envESURL          = "MF_THINGS_ES_URL"
+	envESPass         = "MF_THINGS_ES_PASS"
+	envESDB           = "MF_THINGS_ES_DB"
+	envESConsumer     = "MF_CERTS_EVENT_CONSUMER"
 
 	envVaultHost       = "MF_CERTS_VAULT_HOST"
 	envVaultPKIIntPath = "MF_VAULT_PKI_INT_PATH"
@@ -126,6 +136,10 @@ type config struct {
 	jaegerURL   string
 	authURL     string
 	authTimeout time.Duration
+	esURL       string
+	esPass      string
+	esDB        string
+	esConsumer  string
 	// Sign and issue certificates without 3rd party PKI
 	signCAPath     string
 	signCAKeyPath  string
@@ -171,10 +185,14 @@ func main() {
 
 	auth := authapi.NewClient(authTracer, authConn, cfg.authTimeout)
 
-	svc := newService(auth, db, logger, nil, tlsCert, caCert, cfg, pkiClient)
+	esClient := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
+	defer esClient.Close()
+
+	svc := newService(auth, db, logger, esClient, tlsCert, caCert, cfg, pkiClient)
 	errs := make(chan error, 2)
 
 	go startHTTPServer(svc, cfg, logger, errs)
+	go subscribeToThingsES(svc, esClient, cfg.esConsumer, logger)
 
 	go func() {
 		c := make(chan os.Signal)
@@ -226,6 +244,10 @@ func loadConfig() config {
 		jaegerURL:   mainflux.Env(envJaegerURL, defJaegerURL),
 		authURL:     mainflux.Env(envAuthURL, defAuthURL),
 		authTimeout: authTimeout,
+		esURL:       mainflux.Env(envESURL, defESURL),
+		esPass:      mainflux.Env(envESPass, defESPass),
+		esDB:        mainflux.Env(envESDB, defESDB),
+		esConsumer:  mainflux.Env(envESConsumer, defESConsumer),
 
 		signCAKeyPath:  mainflux.Env(envSignCAKey, defSignCAKeyPath),
 		signCAPath:     mainflux.Env(envSignCAPath, defSignCAPath),
@@ -375,6 +397,14 @@ func startHTTPServer(svc certs.Service, cfg config, logger mflog.Logger, errs ch
 	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
 }
 
+func subscribeToThingsES(svc certs.Service, client *redis.Client, consumer string, logger mflog.Logger) {
+	eventStore := rediscons.NewEventStore(svc, client, consumer, logger)
+	logger.Info("Subscribed to Redis Event Store")
+	if err := eventStore.Subscribe(context.Background(), "mainflux.things"); err != nil {
+		logger.Warn(fmt.Sprintf("Certs service failed to subscribe to event sourcing: %s", err))
+	}
+}
+
 func loadCertificates(conf config) (tls.Certificate, *x509.Certificate, error) {
 	var tlsCert tls.Certificate
 	var caCert *x509.Certificate
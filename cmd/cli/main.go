@@ -145,6 +145,14 @@ func main() {
 		"Enables raw output mode for easier parsing of output",
 	)
 
+	rootCmd.PersistentFlags().BoolVarP(
+		&cli.DryRun,
+		"dry-run",
+		"d",
+		cli.DryRun,
+		"Only validate the imported things or channels",
+	)
+
 	// Client and Channels Flags
 	rootCmd.PersistentFlags().UintVarP(
 		&cli.Limit,
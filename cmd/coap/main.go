@@ -16,12 +16,18 @@ import (
 	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis/v8"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/coap"
 	"github.com/mainflux/mainflux/coap/api"
 	logger "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging/brokers"
+	presenceredis "github.com/mainflux/mainflux/pkg/presence/redis"
+	"github.com/mainflux/mainflux/pkg/quota"
+	quotaredis "github.com/mainflux/mainflux/pkg/quota/redis"
+	retainedredis "github.com/mainflux/mainflux/pkg/retained/redis"
+	"github.com/mainflux/mainflux/pkg/schema"
 	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
-	broker "github.com/nats-io/nats.go"
 	opentracing "github.com/opentracing/opentracing-go"
 	gocoap "github.com/plgd-dev/go-coap/v2"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
@@ -33,32 +39,72 @@ import (
 const (
 	defPort              = "5683"
 	defNatsURL           = "nats://localhost:4222"
+	defBrokerType        = "nats"
+	defKafkaURL          = "localhost:9092"
 	defLogLevel          = "error"
 	defClientTLS         = "false"
 	defCACerts           = ""
 	defJaegerURL         = ""
 	defThingsAuthURL     = "localhost:8183"
 	defThingsAuthTimeout = "1s"
+	defSchemaTTL         = "1m"
+	defRetainedURL       = "localhost:6379"
+	defRetainedPass      = ""
+	defRetainedDB        = "0"
+	defQuotaURL          = "localhost:6379"
+	defQuotaPass         = ""
+	defQuotaDB           = "0"
+	defQuotaTTL          = "1m"
+	defESURL             = "localhost:6379"
+	defESPass            = ""
+	defESDB              = "0"
+	defPresenceInterval  = "1m"
 
 	envPort              = "MF_COAP_ADAPTER_PORT"
 	envNatsURL           = "MF_NATS_URL"
+	envBrokerType        = "MF_BROKER_TYPE"
+	envKafkaURL          = "MF_KAFKA_URL"
 	envLogLevel          = "MF_COAP_ADAPTER_LOG_LEVEL"
 	envClientTLS         = "MF_COAP_ADAPTER_CLIENT_TLS"
 	envCACerts           = "MF_COAP_ADAPTER_CA_CERTS"
 	envJaegerURL         = "MF_JAEGER_URL"
 	envThingsAuthURL     = "MF_THINGS_AUTH_GRPC_URL"
 	envThingsAuthTimeout = "MF_THINGS_AUTH_GRPC_TIMEOUT"
+	envSchemaTTL         = "MF_COAP_ADAPTER_SCHEMA_TTL"
+	envRetainedURL       = "MF_RETAINED_URL"
+	envRetainedPass      = "MF_RETAINED_PASS"
+	envRetainedDB        = "MF_RETAINED_DB"
+	envQuotaURL          = "MF_QUOTA_URL"
+	envQuotaPass         = "MF_QUOTA_PASS"
+	envQuotaDB           = "MF_QUOTA_DB"
+	envQuotaTTL          = "MF_COAP_ADAPTER_QUOTA_TTL"
+	envESURL             = "MF_COAP_ADAPTER_ES_URL"
+	envESPass            = "MF_COAP_ADAPTER_ES_PASS"
+	envESDB              = "MF_COAP_ADAPTER_ES_DB"
+	envPresenceInterval  = "MF_COAP_ADAPTER_PRESENCE_INTERVAL"
 )
 
 type config struct {
 	port              string
-	natsURL           string
+	brokerCfg         brokers.Config
 	logLevel          string
 	clientTLS         bool
 	caCerts           string
 	jaegerURL         string
 	thingsAuthURL     string
 	thingsAuthTimeout time.Duration
+	schemaTTL         time.Duration
+	retainedURL       string
+	retainedPass      string
+	retainedDB        string
+	quotaURL          string
+	quotaPass         string
+	quotaDB           string
+	quotaTTL          time.Duration
+	esURL             string
+	esPass            string
+	esDB              string
+	presenceInterval  time.Duration
 }
 
 func main() {
@@ -77,13 +123,35 @@ func main() {
 
 	tc := thingsapi.NewClient(conn, thingsTracer, cfg.thingsAuthTimeout)
 
-	nc, err := broker.Connect(cfg.natsURL)
+	ps, err := brokers.NewPubSub(cfg.brokerCfg, "", logger)
 	if err != nil {
-		log.Fatalf(err.Error())
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
+		os.Exit(1)
 	}
-	defer nc.Close()
+	defer ps.Close()
+
+	rc := connectToRedis(cfg.retainedURL, cfg.retainedPass, cfg.retainedDB, logger)
+	defer rc.Close()
+
+	qc := connectToRedis(cfg.quotaURL, cfg.quotaPass, cfg.quotaDB, logger)
+	defer qc.Close()
 
-	svc := coap.New(tc, nc)
+	limiter := quotaredis.NewLimiter(qc, tc, cfg.quotaTTL)
+	limiter = quota.MetricsMiddleware(
+		limiter,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "coap_adapter",
+			Subsystem: "quota",
+			Name:      "throttled_messages",
+			Help:      "Number of messages rejected due to exceeded quotas.",
+		}, []string{"reason"}),
+	)
+
+	ec := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
+	defer ec.Close()
+	tracker := presenceredis.NewTracker(ec, cfg.presenceInterval)
+
+	svc := coap.New(tc, ps, schema.NewValidator(tc, cfg.schemaTTL), retainedredis.NewStore(rc), limiter, tracker)
 
 	svc = api.LoggingMiddleware(svc, logger)
 
@@ -129,8 +197,27 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsAuthTimeout, err.Error())
 	}
 
+	schemaTTL, err := time.ParseDuration(mainflux.Env(envSchemaTTL, defSchemaTTL))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envSchemaTTL, err.Error())
+	}
+
+	quotaTTL, err := time.ParseDuration(mainflux.Env(envQuotaTTL, defQuotaTTL))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envQuotaTTL, err.Error())
+	}
+
+	presenceInterval, err := time.ParseDuration(mainflux.Env(envPresenceInterval, defPresenceInterval))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envPresenceInterval, err.Error())
+	}
+
 	return config{
-		natsURL:           mainflux.Env(envNatsURL, defNatsURL),
+		brokerCfg: brokers.Config{
+			Type:     mainflux.Env(envBrokerType, defBrokerType),
+			NatsURL:  mainflux.Env(envNatsURL, defNatsURL),
+			KafkaURL: mainflux.Env(envKafkaURL, defKafkaURL),
+		},
 		port:              mainflux.Env(envPort, defPort),
 		logLevel:          mainflux.Env(envLogLevel, defLogLevel),
 		clientTLS:         tls,
@@ -138,6 +225,18 @@ func loadConfig() config {
 		jaegerURL:         mainflux.Env(envJaegerURL, defJaegerURL),
 		thingsAuthURL:     mainflux.Env(envThingsAuthURL, defThingsAuthURL),
 		thingsAuthTimeout: authTimeout,
+		schemaTTL:         schemaTTL,
+		retainedURL:       mainflux.Env(envRetainedURL, defRetainedURL),
+		retainedPass:      mainflux.Env(envRetainedPass, defRetainedPass),
+		retainedDB:        mainflux.Env(envRetainedDB, defRetainedDB),
+		quotaURL:          mainflux.Env(envQuotaURL, defQuotaURL),
+		quotaPass:         mainflux.Env(envQuotaPass, defQuotaPass),
+		quotaDB:           mainflux.Env(envQuotaDB, defQuotaDB),
+		quotaTTL:          quotaTTL,
+		esURL:             mainflux.Env(envESURL, defESURL),
+		esPass:            mainflux.Env(envESPass, defESPass),
+		esDB:              mainflux.Env(envESDB, defESDB),
+		presenceInterval:  presenceInterval,
 	}
 }
 
@@ -195,6 +294,20 @@ func startHTTPServer(port string, logger logger.Logger, errs chan error) {
 	errs <- http.ListenAndServe(p, api.MakeHTTPHandler())
 }
 
+func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) *redis.Client {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
+		os.Exit(1)
+	}
+
+	return redis.NewClient(&redis.Options{
+		Addr:     redisURL,
+		Password: redisPass,
+		DB:       db,
+	})
+}
+
 func startCOAPServer(cfg config, svc coap.Service, auth mainflux.ThingsServiceClient, l logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", cfg.port)
 	l.Info(fmt.Sprintf("CoAP adapter service started, exposed port %s", cfg.port))
@@ -18,11 +18,19 @@ import (
 	"google.golang.org/grpc/credentials"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis/v8"
 	"github.com/mainflux/mainflux"
 	adapter "github.com/mainflux/mainflux/http"
 	"github.com/mainflux/mainflux/http/api"
 	"github.com/mainflux/mainflux/logger"
-	"github.com/mainflux/mainflux/pkg/messaging/nats"
+	"github.com/mainflux/mainflux/pkg/messaging/brokers"
+	presenceredis "github.com/mainflux/mainflux/pkg/presence/redis"
+	"github.com/mainflux/mainflux/pkg/quota"
+	quotaredis "github.com/mainflux/mainflux/pkg/quota/redis"
+	"github.com/mainflux/mainflux/pkg/retained"
+	retainedredis "github.com/mainflux/mainflux/pkg/retained/redis"
+	"github.com/mainflux/mainflux/pkg/schema"
+	"github.com/mainflux/mainflux/pkg/uuid"
 	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
 	"github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
@@ -36,22 +44,54 @@ const (
 	defCACerts           = ""
 	defPort              = "8180"
 	defNatsURL           = "nats://localhost:4222"
+	defBrokerType        = "nats"
+	defKafkaURL          = "localhost:9092"
 	defJaegerURL         = ""
 	defThingsAuthURL     = "localhost:8183"
 	defThingsAuthTimeout = "1s"
+	defSchemaTTL         = "1m"
+	defRetainedURL       = "localhost:6379"
+	defRetainedPass      = ""
+	defRetainedDB        = "0"
+	defQuotaURL          = "localhost:6379"
+	defQuotaPass         = ""
+	defQuotaDB           = "0"
+	defQuotaTTL          = "1m"
+	defESURL             = "localhost:6379"
+	defESPass            = ""
+	defESDB              = "0"
+	defPresenceInterval  = "1m"
 
 	envLogLevel          = "MF_HTTP_ADAPTER_LOG_LEVEL"
 	envClientTLS         = "MF_HTTP_ADAPTER_CLIENT_TLS"
 	envCACerts           = "MF_HTTP_ADAPTER_CA_CERTS"
 	envPort              = "MF_HTTP_ADAPTER_PORT"
 	envNatsURL           = "MF_NATS_URL"
+	envBrokerType        = "MF_BROKER_TYPE"
+	envKafkaURL          = "MF_KAFKA_URL"
 	envJaegerURL         = "MF_JAEGER_URL"
 	envThingsAuthURL     = "MF_THINGS_AUTH_GRPC_URL"
 	envThingsAuthTimeout = "MF_THINGS_AUTH_GRPC_TIMEOUT"
+	envSchemaTTL         = "MF_HTTP_ADAPTER_SCHEMA_TTL"
+	envRetainedURL       = "MF_RETAINED_URL"
+	envRetainedPass      = "MF_RETAINED_PASS"
+	envRetainedDB        = "MF_RETAINED_DB"
+	envQuotaURL          = "MF_QUOTA_URL"
+	envQuotaPass         = "MF_QUOTA_PASS"
+	envQuotaDB           = "MF_QUOTA_DB"
+	envQuotaTTL          = "MF_HTTP_ADAPTER_QUOTA_TTL"
+	envESURL             = "MF_HTTP_ADAPTER_ES_URL"
+	envESPass            = "MF_HTTP_ADAPTER_ES_PASS"
+	envESDB              = "MF_HTTP_ADAPTER_ES_DB"
+	envPresenceInterval  = "MF_HTTP_ADAPTER_PRESENCE_INTERVAL"
+
+	// retainedQueue is the broker queue of the adapter instances saving the
+	// messages to the last-value store, so that every message is saved once.
+	retainedQueue = "retained"
 )
 
 type config struct {
-	natsURL           string
+	brokerCfg         brokers.Config
 	logLevel          string
 	port              string
 	clientTLS         bool
@@ -59,6 +99,18 @@ type config struct {
 	jaegerURL         string
 	thingsAuthURL     string
 	thingsAuthTimeout time.Duration
+	schemaTTL         time.Duration
+	retainedURL       string
+	retainedPass      string
+	retainedDB        string
+	quotaURL          string
+	quotaPass         string
+	quotaDB           string
+	quotaTTL          time.Duration
+	esURL             string
+	esPass            string
+	esDB              string
+	presenceInterval  time.Duration
 }
 
 func main() {
@@ -78,15 +130,48 @@ func main() {
 	thingsTracer, thingsCloser := initJaeger("things", cfg.jaegerURL, logger)
 	defer thingsCloser.Close()
 
-	pub, err := nats.NewPublisher(cfg.natsURL)
+	ps, err := brokers.NewPubSub(cfg.brokerCfg, "", logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
+		os.Exit(1)
+	}
+	defer ps.Close()
+
+	rc := connectToRedis(cfg.retainedURL, cfg.retainedPass, cfg.retainedDB, logger)
+	defer rc.Close()
+	store := retainedredis.NewStore(rc)
+
+	rps, err := brokers.NewPubSub(cfg.brokerCfg, retainedQueue, logger)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
 		os.Exit(1)
 	}
-	defer pub.Close()
+	defer rps.Close()
+
+	if err := rps.Subscribe(brokers.SubjectAllChannels, retained.Handler(store)); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to message broker: %s", err))
+		os.Exit(1)
+	}
+
+	qc := connectToRedis(cfg.quotaURL, cfg.quotaPass, cfg.quotaDB, logger)
+	defer qc.Close()
 
 	tc := thingsapi.NewClient(conn, thingsTracer, cfg.thingsAuthTimeout)
-	svc := adapter.New(pub, tc)
+	limiter := quotaredis.NewLimiter(qc, tc, cfg.quotaTTL)
+	limiter = quota.MetricsMiddleware(
+		limiter,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "http_adapter",
+			Subsystem: "quota",
+			Name:      "throttled_messages",
+			Help:      "Number of messages rejected due to exceeded quotas.",
+		}, []string{"reason"}),
+	)
+	ec := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
+	defer ec.Close()
+	tracker := presenceredis.NewTracker(ec, cfg.presenceInterval)
+
+	svc := adapter.New(ps, tc, schema.NewValidator(tc, cfg.schemaTTL), store, limiter, tracker, logger)
 
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
@@ -110,7 +195,7 @@ func main() {
 	go func() {
 		p := fmt.Sprintf(":%s", cfg.port)
 		logger.Info(fmt.Sprintf("HTTP adapter service started on port %s", cfg.port))
-		errs <- http.ListenAndServe(p, api.MakeHandler(svc, tracer))
+		errs <- http.ListenAndServe(p, api.MakeHandler(svc, tracer, uuid.New(), logger))
 	}()
 
 	go func() {
@@ -134,8 +219,27 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsAuthTimeout, err.Error())
 	}
 
+	schemaTTL, err := time.ParseDuration(mainflux.Env(envSchemaTTL, defSchemaTTL))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envSchemaTTL, err.Error())
+	}
+
+	quotaTTL, err := time.ParseDuration(mainflux.Env(envQuotaTTL, defQuotaTTL))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envQuotaTTL, err.Error())
+	}
+
+	presenceInterval, err := time.ParseDuration(mainflux.Env(envPresenceInterval, defPresenceInterval))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envPresenceInterval, err.Error())
+	}
+
 	return config{
-		natsURL:           mainflux.Env(envNatsURL, defNatsURL),
+		brokerCfg: brokers.Config{
+			Type:     mainflux.Env(envBrokerType, defBrokerType),
+			NatsURL:  mainflux.Env(envNatsURL, defNatsURL),
+			KafkaURL: mainflux.Env(envKafkaURL, defKafkaURL),
+		},
 		logLevel:          mainflux.Env(envLogLevel, defLogLevel),
 		port:              mainflux.Env(envPort, defPort),
 		clientTLS:         tls,
@@ -143,6 +247,18 @@ func loadConfig() config {
 		jaegerURL:         mainflux.Env(envJaegerURL, defJaegerURL),
 		thingsAuthURL:     mainflux.Env(envThingsAuthURL, defThingsAuthURL),
 		thingsAuthTimeout: authTimeout,
+		schemaTTL:         schemaTTL,
+		retainedURL:       mainflux.Env(envRetainedURL, defRetainedURL),
+		retainedPass:      mainflux.Env(envRetainedPass, defRetainedPass),
+		retainedDB:        mainflux.Env(envRetainedDB, defRetainedDB),
+		quotaURL:          mainflux.Env(envQuotaURL, defQuotaURL),
+		quotaPass:         mainflux.Env(envQuotaPass, defQuotaPass),
+		quotaDB:           mainflux.Env(envQuotaDB, defQuotaDB),
+		quotaTTL:          quotaTTL,
+		esURL:             mainflux.Env(envESURL, defESURL),
+		esPass:            mainflux.Env(envESPass, defESPass),
+		esDB:              mainflux.Env(envESDB, defESDB),
+		presenceInterval:  presenceInterval,
 	}
 }
 
@@ -193,3 +309,17 @@ func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	}
 	return conn
 }
+
+func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) *redis.Client {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
+		os.Exit(1)
+	}
+
+	return redis.NewClient(&redis.Options{
+		Addr:     redisURL,
+		Password: redisPass,
+		DB:       db,
+	})
+}
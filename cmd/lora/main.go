@@ -21,8 +21,8 @@ import (
 	"github.com/mainflux/mainflux/lora"
 	"github.com/mainflux/mainflux/lora/api"
 	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/messaging/brokers"
 	"github.com/mainflux/mainflux/pkg/messaging/mqtt"
-	"github.com/mainflux/mainflux/pkg/messaging/nats"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/mainflux/mainflux/lora/redis"
@@ -35,6 +35,8 @@ const (
 	defLoraMsgURL     = "tcp://localhost:1883"
 	defSubTimeout     = "30s" // 30 seconds
 	defNatsURL        = "nats://localhost:4222"
+	defBrokerType     = "nats"
+	defKafkaURL       = "localhost:9092"
 	defESURL          = "localhost:6379"
 	defESPass         = ""
 	defESDB           = "0"
@@ -47,6 +49,8 @@ const (
 	envLoraMsgURL     = "MF_LORA_ADAPTER_MESSAGES_URL"
 	envSubTimeout     = "MF_LORA_ADAPTER_SUBSCRIBER_TIMEOUT"
 	envNatsURL        = "MF_NATS_URL"
+	envBrokerType     = "MF_BROKER_TYPE"
+	envKafkaURL       = "MF_KAFKA_URL"
 	envLogLevel       = "MF_LORA_ADAPTER_LOG_LEVEL"
 	envESURL          = "MF_THINGS_ES_URL"
 	envESPass         = "MF_THINGS_ES_PASS"
@@ -66,7 +70,7 @@ const (
 type config struct {
 	httpPort       string
 	loraMsgURL     string
-	natsURL        string
+	brokerCfg      brokers.Config
 	subTimeout     time.Duration
 	logLevel       string
 	esURL          string
@@ -92,9 +96,9 @@ func main() {
 	esConn := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
 	defer esConn.Close()
 
-	pub, err := nats.NewPublisher(cfg.natsURL)
+	pub, err := brokers.NewPublisher(cfg.brokerCfg)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
 		os.Exit(1)
 	}
 	defer pub.Close()
@@ -151,10 +155,14 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envSubTimeout, err.Error())
 	}
 	return config{
-		httpPort:       mainflux.Env(envHTTPPort, defHTTPPort),
-		loraMsgURL:     mainflux.Env(envLoraMsgURL, defLoraMsgURL),
-		subTimeout:     mqttTimeout,
-		natsURL:        mainflux.Env(envNatsURL, defNatsURL),
+		httpPort:   mainflux.Env(envHTTPPort, defHTTPPort),
+		loraMsgURL: mainflux.Env(envLoraMsgURL, defLoraMsgURL),
+		subTimeout: mqttTimeout,
+		brokerCfg: brokers.Config{
+			Type:     mainflux.Env(envBrokerType, defBrokerType),
+			NatsURL:  mainflux.Env(envNatsURL, defNatsURL),
+			KafkaURL: mainflux.Env(envKafkaURL, defKafkaURL),
+		},
 		logLevel:       mainflux.Env(envLogLevel, defLogLevel),
 		esURL:          mainflux.Env(envESURL, defESURL),
 		esPass:         mainflux.Env(envESPass, defESPass),
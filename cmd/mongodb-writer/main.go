@@ -10,48 +10,119 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/mainflux/mainflux"
+	authapi "github.com/mainflux/mainflux/auth/api/grpc"
 	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/consumers/batch"
+	"github.com/mainflux/mainflux/consumers/spill"
 	"github.com/mainflux/mainflux/consumers/writers/api"
 	"github.com/mainflux/mainflux/consumers/writers/mongodb"
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+	retentionapi "github.com/mainflux/mainflux/consumers/writers/retention/api"
 	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging/brokers"
 	"github.com/mainflux/mainflux/pkg/messaging/nats"
+	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
+	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 const (
 	svcName = "mongodb-writer"
 
-	defLogLevel   = "error"
-	defNatsURL    = "nats://localhost:4222"
-	defPort       = "8180"
-	defDB         = "mainflux"
-	defDBHost     = "localhost"
-	defDBPort     = "27017"
-	defConfigPath = "/config.toml"
-
-	envNatsURL    = "MF_NATS_URL"
-	envLogLevel   = "MF_MONGO_WRITER_LOG_LEVEL"
-	envPort       = "MF_MONGO_WRITER_PORT"
-	envDB         = "MF_MONGO_WRITER_DB"
-	envDBHost     = "MF_MONGO_WRITER_DB_HOST"
-	envDBPort     = "MF_MONGO_WRITER_DB_PORT"
-	envConfigPath = "MF_MONGO_WRITER_CONFIG_PATH"
+	defLogLevel      = "error"
+	defNatsURL       = "nats://localhost:4222"
+	defBrokerType    = "nats"
+	defKafkaURL      = "localhost:9092"
+	defJetStream     = "false"
+	defJSMaxDeliver  = "5"
+	defJSBackoff     = "1s"
+	defJSDeadLetter  = "deadletter"
+	defThingsTLS     = "false"
+	defThingsCACerts = ""
+	defThingsURL     = ""
+	defThingsTimeout = "1s"
+	defAuthTLS       = "false"
+	defAuthCACerts   = ""
+	defAuthURL       = ""
+	defAuthTimeout   = "1s"
+	defPort          = "8180"
+	defDB            = "mainflux"
+	defDBHost        = "localhost"
+	defDBPort        = "27017"
+	defConfigPath    = "/config.toml"
+	defBatchSize     = "0"
+	defBatchTimeout  = "1s"
+	defBatchRetries  = "3"
+	defBatchBackoff  = "100ms"
+	defSpillPath     = ""
+	defSpillMaxSize  = "1073741824"
+	defSpillRetry    = "5s"
+	defSpillAttempts = "10"
+	defApplyInterval = "1m"
+
+	envNatsURL       = "MF_NATS_URL"
+	envBrokerType    = "MF_BROKER_TYPE"
+	envKafkaURL      = "MF_KAFKA_URL"
+	envJetStream     = "MF_NATS_JETSTREAM"
+	envJSMaxDeliver  = "MF_NATS_JETSTREAM_MAX_DELIVER"
+	envJSBackoff     = "MF_NATS_JETSTREAM_BACKOFF"
+	envJSDeadLetter  = "MF_NATS_JETSTREAM_DEAD_LETTER"
+	envThingsTLS     = "MF_THINGS_CLIENT_TLS"
+	envThingsCACerts = "MF_THINGS_CA_CERTS"
+	envThingsURL     = "MF_THINGS_AUTH_GRPC_URL"
+	envThingsTimeout = "MF_THINGS_AUTH_GRPC_TIMEOUT"
+	envAuthTLS       = "MF_AUTH_CLIENT_TLS"
+	envAuthCACerts   = "MF_AUTH_CA_CERTS"
+	envAuthURL       = "MF_AUTH_GRPC_URL"
+	envAuthTimeout   = "MF_AUTH_GRPC_TIMEOUT"
+	envLogLevel      = "MF_MONGO_WRITER_LOG_LEVEL"
+	envPort          = "MF_MONGO_WRITER_PORT"
+	envDB            = "MF_MONGO_WRITER_DB"
+	envDBHost        = "MF_MONGO_WRITER_DB_HOST"
+	envDBPort        = "MF_MONGO_WRITER_DB_PORT"
+	envConfigPath    = "MF_MONGO_WRITER_CONFIG_PATH"
+	envBatchSize     = "MF_MONGO_WRITER_BATCH_SIZE"
+	envBatchTimeout  = "MF_MONGO_WRITER_BATCH_TIMEOUT"
+	envBatchRetries  = "MF_MONGO_WRITER_BATCH_MAX_RETRIES"
+	envBatchBackoff  = "MF_MONGO_WRITER_BATCH_BACKOFF"
+	envSpillPath     = "MF_MONGO_WRITER_SPILL_PATH"
+	envSpillMaxSize  = "MF_MONGO_WRITER_SPILL_MAX_SIZE"
+	envSpillRetry    = "MF_MONGO_WRITER_SPILL_RETRY_INTERVAL"
+	envSpillAttempts = "MF_MONGO_WRITER_SPILL_MAX_ATTEMPTS"
+	envApplyInterval = "MF_MONGO_WRITER_RETENTION_INTERVAL"
 )
 
 type config struct {
-	natsURL    string
-	logLevel   string
-	port       string
-	dbName     string
-	dbHost     string
-	dbPort     string
-	configPath string
+	brokerCfg     brokers.Config
+	jetStream     bool
+	jsConfig      nats.JetStreamConfig
+	thingsTLS     bool
+	thingsCACerts string
+	thingsURL     string
+	thingsTimeout time.Duration
+	authTLS       bool
+	authCACerts   string
+	authURL       string
+	authTimeout   time.Duration
+	logLevel      string
+	port          string
+	dbName        string
+	dbHost        string
+	dbPort        string
+	configPath    string
+	batchCfg      batch.Config
+	spillCfg      spill.Config
+	applyInterval time.Duration
 }
 
 func main() {
@@ -62,9 +133,9 @@ func main() {
 		log.Fatal(err)
 	}
 
-	pubSub, err := nats.NewPubSub(cfg.natsURL, "", logger)
+	pubSub, err := newPubSub(cfg, logger)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
 		os.Exit(1)
 	}
 	defer pubSub.Close()
@@ -83,7 +154,27 @@ func main() {
 	repo = api.LoggingMiddleware(repo, logger)
 	repo = api.MetricsMiddleware(repo, counter, latency)
 
-	if err := consumers.Start(pubSub, repo, cfg.configPath, logger); err != nil {
+	repo, spillClose := newSpill(repo, cfg.spillCfg, logger)
+	if spillClose != nil {
+		defer spillClose()
+	}
+
+	repo, batcherClose := newBatcher(repo, cfg, logger)
+	if batcherClose != nil {
+		defer batcherClose()
+	}
+
+	things, thingsClose := connectToThings(cfg, opentracing.NoopTracer{}, logger)
+	if thingsClose != nil {
+		defer thingsClose()
+	}
+
+	auth, authClose := connectToAuth(cfg, opentracing.NoopTracer{}, logger)
+	if authClose != nil {
+		defer authClose()
+	}
+
+	if err := consumers.Start(pubSub, repo, cfg.configPath, things, logger); err != nil {
 		logger.Error(fmt.Sprintf("Failed to start MongoDB writer: %s", err))
 		os.Exit(1)
 	}
@@ -95,21 +186,137 @@ func main() {
 		errs <- fmt.Errorf("%s", <-c)
 	}()
 
-	go startHTTPService(cfg.port, logger, errs)
+	handler := newHandler(cfg, db, auth, things, logger)
+	go startHTTPService(cfg.port, handler, logger, errs)
 
 	err = <-errs
 	logger.Error(fmt.Sprintf("MongoDB writer service terminated: %s", err))
 }
 
 func loadConfigs() config {
+	jetStream, err := strconv.ParseBool(mainflux.Env(envJetStream, defJetStream))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envJetStream)
+	}
+
+	jsMaxDeliver, err := strconv.Atoi(mainflux.Env(envJSMaxDeliver, defJSMaxDeliver))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envJSMaxDeliver, err.Error())
+	}
+
+	jsBackoff, err := time.ParseDuration(mainflux.Env(envJSBackoff, defJSBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envJSBackoff, err.Error())
+	}
+
+	jsConfig := nats.JetStreamConfig{
+		MaxDeliver: jsMaxDeliver,
+		Backoff:    jsBackoff,
+		DeadLetter: mainflux.Env(envJSDeadLetter, defJSDeadLetter),
+	}
+
+	thingsTLS, err := strconv.ParseBool(mainflux.Env(envThingsTLS, defThingsTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envThingsTLS)
+	}
+
+	thingsTimeout, err := time.ParseDuration(mainflux.Env(envThingsTimeout, defThingsTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
+	}
+
+	batchSize, err := strconv.Atoi(mainflux.Env(envBatchSize, defBatchSize))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBatchSize, err.Error())
+	}
+
+	batchTimeout, err := time.ParseDuration(mainflux.Env(envBatchTimeout, defBatchTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBatchTimeout, err.Error())
+	}
+
+	batchRetries, err := strconv.Atoi(mainflux.Env(envBatchRetries, defBatchRetries))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBatchRetries, err.Error())
+	}
+
+	batchBackoff, err := time.ParseDuration(mainflux.Env(envBatchBackoff, defBatchBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBatchBackoff, err.Error())
+	}
+
+	batchCfg := batch.Config{
+		Size:       batchSize,
+		Timeout:    batchTimeout,
+		MaxRetries: batchRetries,
+		Backoff:    batchBackoff,
+	}
+	// Consuming blocks until the batch is written, so the messages are handled
+	// concurrently, and the next batch fills while the previous one is written.
+	jsConfig.MaxInFlight = 2 * batchSize
+
+	spillMaxSize, err := strconv.ParseInt(mainflux.Env(envSpillMaxSize, defSpillMaxSize), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envSpillMaxSize, err.Error())
+	}
+
+	spillRetry, err := time.ParseDuration(mainflux.Env(envSpillRetry, defSpillRetry))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envSpillRetry, err.Error())
+	}
+
+	spillAttempts, err := strconv.Atoi(mainflux.Env(envSpillAttempts, defSpillAttempts))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envSpillAttempts, err.Error())
+	}
+
+	spillCfg := spill.Config{
+		Path:          mainflux.Env(envSpillPath, defSpillPath),
+		MaxSize:       spillMaxSize,
+		RetryInterval: spillRetry,
+		MaxAttempts:   spillAttempts,
+	}
+
+	authTLS, err := strconv.ParseBool(mainflux.Env(envAuthTLS, defAuthTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envAuthTLS)
+	}
+
+	authTimeout, err := time.ParseDuration(mainflux.Env(envAuthTimeout, defAuthTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envAuthTimeout, err.Error())
+	}
+
+	applyInterval, err := time.ParseDuration(mainflux.Env(envApplyInterval, defApplyInterval))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envApplyInterval, err.Error())
+	}
+
 	return config{
-		natsURL:    mainflux.Env(envNatsURL, defNatsURL),
-		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
-		port:       mainflux.Env(envPort, defPort),
-		dbName:     mainflux.Env(envDB, defDB),
-		dbHost:     mainflux.Env(envDBHost, defDBHost),
-		dbPort:     mainflux.Env(envDBPort, defDBPort),
-		configPath: mainflux.Env(envConfigPath, defConfigPath),
+		brokerCfg: brokers.Config{
+			Type:     mainflux.Env(envBrokerType, defBrokerType),
+			NatsURL:  mainflux.Env(envNatsURL, defNatsURL),
+			KafkaURL: mainflux.Env(envKafkaURL, defKafkaURL),
+		},
+		jetStream:     jetStream,
+		jsConfig:      jsConfig,
+		thingsTLS:     thingsTLS,
+		thingsCACerts: mainflux.Env(envThingsCACerts, defThingsCACerts),
+		thingsURL:     mainflux.Env(envThingsURL, defThingsURL),
+		thingsTimeout: thingsTimeout,
+		authTLS:       authTLS,
+		authCACerts:   mainflux.Env(envAuthCACerts, defAuthCACerts),
+		authURL:       mainflux.Env(envAuthURL, defAuthURL),
+		authTimeout:   authTimeout,
+		logLevel:      mainflux.Env(envLogLevel, defLogLevel),
+		port:          mainflux.Env(envPort, defPort),
+		dbName:        mainflux.Env(envDB, defDB),
+		dbHost:        mainflux.Env(envDBHost, defDBHost),
+		dbPort:        mainflux.Env(envDBPort, defDBPort),
+		configPath:    mainflux.Env(envConfigPath, defConfigPath),
+		batchCfg:      batchCfg,
+		spillCfg:      spillCfg,
+		applyInterval: applyInterval,
 	}
 }
 
@@ -131,8 +338,174 @@ func makeMetrics() (*kitprometheus.Counter, *kitprometheus.Summary) {
 	return counter, latency
 }
 
-func startHTTPService(port string, logger logger.Logger, errs chan error) {
+func startHTTPService(port string, handler http.Handler, logger logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", port)
 	logger.Info(fmt.Sprintf("Mongodb writer service started, exposed port %s", p))
-	errs <- http.ListenAndServe(p, api.MakeHandler(svcName))
+	errs <- http.ListenAndServe(p, handler)
+}
+
+func newSpill(repo consumers.Consumer, cfg spill.Config, logger logger.Logger) (consumers.Consumer, func() error) {
+	if cfg.Path == "" {
+		logger.Info("Spill buffer path is not set, failed writes are not buffered")
+		return repo, nil
+	}
+
+	b, err := spill.New(repo, cfg, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create spill buffer: %s", err))
+		os.Exit(1)
+	}
+
+	stdprometheus.MustRegister(
+		stdprometheus.NewGaugeFunc(stdprometheus.GaugeOpts{
+			Namespace: "mongodb",
+			Subsystem: "message_writer",
+			Name:      "spill_size_bytes",
+			Help:      "Size of the spill buffer in bytes.",
+		}, func() float64 { return float64(b.Size()) }),
+		stdprometheus.NewGaugeFunc(stdprometheus.GaugeOpts{
+			Namespace: "mongodb",
+			Subsystem: "message_writer",
+			Name:      "spill_pending_writes",
+			Help:      "Number of writes waiting in the spill buffer.",
+		}, func() float64 { return float64(b.Pending()) }),
+	)
+
+	return b, b.Close
+}
+
+func newBatcher(repo consumers.Consumer, cfg config, logger logger.Logger) (consumers.Consumer, func() error) {
+	if cfg.batchCfg.Size < 1 {
+		logger.Info("Batch size is not set, messages are written as they come")
+		return repo, nil
+	}
+	// JetStream hands over the unacknowledged messages concurrently, so the
+	// messages are acknowledged once their batch is written. The other brokers
+	// hand the messages over one at a time, so the batch fills without waiting
+	// for the previous messages to be written.
+	if !cfg.jetStream || cfg.brokerCfg.Type != brokers.NATS {
+		logger.Info("Broker doesn't acknowledge messages, batches are written asynchronously")
+		cfg.batchCfg.Async = true
+	}
+
+	repo = api.BatchMetricsMiddleware(
+		repo,
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "mongodb",
+			Subsystem: "message_writer",
+			Name:      "batch_size",
+			Help:      "Number of messages in written batches.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "mongodb",
+			Subsystem: "message_writer",
+			Name:      "batch_flush_latency_seconds",
+			Help:      "Total duration of batch flushes in seconds.",
+		}, []string{"method"}),
+	)
+	b := batch.New(repo, cfg.batchCfg, logger)
+
+	return b, b.Close
+}
+
+func newPubSub(cfg config, logger logger.Logger) (brokers.PubSub, error) {
+	if cfg.jetStream && cfg.brokerCfg.Type == brokers.NATS {
+		return nats.NewJetStreamPubSub(cfg.brokerCfg.NatsURL, svcName, cfg.jsConfig, logger)
+	}
+	return brokers.NewPubSub(cfg.brokerCfg, "", logger)
+}
+
+func connectToThings(cfg config, tracer opentracing.Tracer, logger logger.Logger) (mainflux.ThingsServiceClient, func() error) {
+	if cfg.thingsURL == "" {
+		logger.Info("Things service URL is not set, thing metadata enrichment is disabled")
+		return nil, nil
+	}
+
+	var opts []grpc.DialOption
+	if cfg.thingsTLS {
+		if cfg.thingsCACerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.thingsCACerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.thingsURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things service: %s", err))
+		os.Exit(1)
+	}
+
+	return thingsapi.NewClient(conn, tracer, cfg.thingsTimeout), conn.Close
+}
+
+// newHandler returns the HTTP handler of the retention policy API and starts
+// applying the policies, if the auth service URL is set. Otherwise, only the
+// health check and metrics are served.
+func newHandler(cfg config, db *mongo.Database, auth mainflux.AuthServiceClient, things mainflux.ThingsServiceClient, logger logger.Logger) http.Handler {
+	if auth == nil {
+		logger.Info("Auth service URL is not set, retention policies are disabled")
+		return api.MakeHandler(svcName)
+	}
+	if things == nil {
+		logger.Error("Things service URL is required to manage retention policies")
+		os.Exit(1)
+	}
+
+	svc := retention.New(auth, things, mongodb.NewPolicyRepository(db), mongodb.NewRetentionStore(db))
+	svc = retentionapi.LoggingMiddleware(svc, logger)
+	svc = retentionapi.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "mongodb",
+			Subsystem: "retention",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "mongodb",
+			Subsystem: "retention",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	go retention.Run(context.Background(), svc, cfg.applyInterval)
+
+	return retentionapi.MakeHandler(svc, opentracing.NoopTracer{}, svcName)
+}
+
+func connectToAuth(cfg config, tracer opentracing.Tracer, logger logger.Logger) (mainflux.AuthServiceClient, func() error) {
+	if cfg.authURL == "" {
+		return nil, nil
+	}
+
+	var opts []grpc.DialOption
+	if cfg.authTLS {
+		if cfg.authCACerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.authCACerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.authURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to auth service: %s", err))
+		os.Exit(1)
+	}
+
+	return authapi.NewClient(tracer, conn, cfg.authTimeout), conn.Close
 }
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/go-redis/redis/v8"
 	"github.com/mainflux/mainflux"
 	mflog "github.com/mainflux/mainflux/logger"
@@ -21,13 +22,17 @@ import (
 	"github.com/mainflux/mainflux/pkg/auth"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/messaging/brokers"
 	mqttpub "github.com/mainflux/mainflux/pkg/messaging/mqtt"
-	"github.com/mainflux/mainflux/pkg/messaging/nats"
+	"github.com/mainflux/mainflux/pkg/quota"
+	quotaredis "github.com/mainflux/mainflux/pkg/quota/redis"
+	retainedredis "github.com/mainflux/mainflux/pkg/retained/redis"
+	"github.com/mainflux/mainflux/pkg/schema"
 	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
-	mp "github.com/mainflux/mproxy/pkg/mqtt"
 	"github.com/mainflux/mproxy/pkg/session"
-	ws "github.com/mainflux/mproxy/pkg/websocket"
 	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	jconfig "github.com/uber/jaeger-client-go/config"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -43,11 +48,13 @@ const (
 	defMQTTTargetPort        = "1883"
 	defMQTTForwarderTimeout  = "30s" // 30 seconds
 	defMQTTTargetHealthCheck = ""
+	defMQTTMaxPacketSize     = "1048576" // 1 MiB
 	envMQTTPort              = "MF_MQTT_ADAPTER_MQTT_PORT"
 	envMQTTTargetHost        = "MF_MQTT_ADAPTER_MQTT_TARGET_HOST"
 	envMQTTTargetPort        = "MF_MQTT_ADAPTER_MQTT_TARGET_PORT"
 	envMQTTTargetHealthCheck = "MF_MQTT_ADAPTER_MQTT_TARGET_HEALTH_CHECK"
 	envMQTTForwarderTimeout  = "MF_MQTT_ADAPTER_FORWARDER_TIMEOUT"
+	envMQTTMaxPacketSize     = "MF_MQTT_ADAPTER_MAX_PACKET_SIZE"
 	// HTTP
 	defHTTPPort       = "8080"
 	defHTTPTargetHost = "localhost"
@@ -62,9 +69,16 @@ const (
 	defThingsAuthTimeout = "1s"
 	envThingsAuthURL     = "MF_THINGS_AUTH_GRPC_URL"
 	envThingsAuthTimeout = "MF_THINGS_AUTH_GRPC_TIMEOUT"
-	// Nats
-	defNatsURL = "nats://localhost:4222"
-	envNatsURL = "MF_NATS_URL"
+	// Payload schema
+	defSchemaTTL = "1m"
+	envSchemaTTL = "MF_MQTT_ADAPTER_SCHEMA_TTL"
+	// Message broker
+	defNatsURL    = "nats://localhost:4222"
+	defBrokerType = "nats"
+	defKafkaURL   = "localhost:9092"
+	envNatsURL    = "MF_NATS_URL"
+	envBrokerType = "MF_BROKER_TYPE"
+	envKafkaURL   = "MF_KAFKA_URL"
 	// Jaeger
 	defJaegerURL = ""
 	envJaegerURL = "MF_JAEGER_URL"
@@ -90,6 +104,22 @@ const (
 	defAuthcacheURL  = "localhost:6379"
 	defAuthCachePass = ""
 	defAuthCacheDB   = "0"
+	// Retained messages
+	envRetainedURL  = "MF_RETAINED_URL"
+	envRetainedPass = "MF_RETAINED_PASS"
+	envRetainedDB   = "MF_RETAINED_DB"
+	defRetainedURL  = "localhost:6379"
+	defRetainedPass = ""
+	defRetainedDB   = "0"
+	// Quotas
+	envQuotaURL  = "MF_QUOTA_URL"
+	envQuotaPass = "MF_QUOTA_PASS"
+	envQuotaDB   = "MF_QUOTA_DB"
+	envQuotaTTL  = "MF_MQTT_ADAPTER_QUOTA_TTL"
+	defQuotaURL  = "localhost:6379"
+	defQuotaPass = ""
+	defQuotaDB   = "0"
+	defQuotaTTL  = "1m"
 )
 
 type config struct {
@@ -98,6 +128,7 @@ type config struct {
 	mqttTargetPort        string
 	mqttForwarderTimeout  time.Duration
 	mqttTargetHealthCheck string
+	mqttMaxPacketSize     int
 	httpPort              string
 	httpTargetHost        string
 	httpTargetPort        string
@@ -107,7 +138,8 @@ type config struct {
 	thingsURL             string
 	thingsAuthURL         string
 	thingsAuthTimeout     time.Duration
-	natsURL               string
+	schemaTTL             time.Duration
+	brokerCfg             brokers.Config
 	clientTLS             bool
 	caCerts               string
 	instance              string
@@ -117,6 +149,13 @@ type config struct {
 	authURL               string
 	authPass              string
 	authDB                string
+	retainedURL           string
+	retainedPass          string
+	retainedDB            string
+	quotaURL              string
+	quotaPass             string
+	quotaDB               string
+	quotaTTL              time.Duration
 }
 
 func main() {
@@ -145,9 +184,9 @@ func main() {
 	ec := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
 	defer ec.Close()
 
-	nps, err := nats.NewPubSub(cfg.natsURL, "mqtt", logger)
+	nps, err := brokers.NewPubSub(cfg.brokerCfg, "mqtt", logger)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
 		os.Exit(1)
 	}
 	defer nps.Close()
@@ -158,15 +197,15 @@ func main() {
 		os.Exit(1)
 	}
 
-	fwd := mqtt.NewForwarder(nats.SubjectAllChannels, logger)
+	fwd := mqtt.NewForwarder(brokers.SubjectAllChannels, logger)
 	if err := fwd.Forward(nps, mpub); err != nil {
-		logger.Error(fmt.Sprintf("Failed to forward NATS messages: %s", err))
+		logger.Error(fmt.Sprintf("Failed to forward message broker messages: %s", err))
 		os.Exit(1)
 	}
 
-	np, err := nats.NewPublisher(cfg.natsURL)
+	np, err := brokers.NewPublisher(cfg.brokerCfg)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
 		os.Exit(1)
 	}
 	defer np.Close()
@@ -182,8 +221,25 @@ func main() {
 
 	authClient := auth.New(ac, tc)
 
+	rc := connectToRedis(cfg.retainedURL, cfg.retainedPass, cfg.retainedDB, logger)
+	defer rc.Close()
+
+	qc := connectToRedis(cfg.quotaURL, cfg.quotaPass, cfg.quotaDB, logger)
+	defer qc.Close()
+
+	limiter := quotaredis.NewLimiter(qc, tc, cfg.quotaTTL)
+	limiter = quota.MetricsMiddleware(
+		limiter,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "mqtt_adapter",
+			Subsystem: "quota",
+			Name:      "throttled_messages",
+			Help:      "Number of messages rejected due to exceeded quotas.",
+		}, []string{"reason"}),
+	)
+
 	// Event handler for MQTT hooks
-	h := mqtt.NewHandler([]messaging.Publisher{np}, es, logger, authClient)
+	h := mqtt.NewHandler([]messaging.Publisher{np}, es, logger, authClient, schema.NewValidator(tc, cfg.schemaTTL), retainedredis.NewStore(rc), limiter)
 
 	errs := make(chan error, 2)
 
@@ -214,17 +270,33 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsAuthTimeout, err.Error())
 	}
 
+	schemaTTL, err := time.ParseDuration(mainflux.Env(envSchemaTTL, defSchemaTTL))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envSchemaTTL, err.Error())
+	}
+
+	quotaTTL, err := time.ParseDuration(mainflux.Env(envQuotaTTL, defQuotaTTL))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envQuotaTTL, err.Error())
+	}
+
 	mqttTimeout, err := time.ParseDuration(mainflux.Env(envMQTTForwarderTimeout, defMQTTForwarderTimeout))
 	if err != nil {
 		log.Fatalf("Invalid %s value: %s", envMQTTForwarderTimeout, err.Error())
 	}
 
+	mqttMaxPacketSize, err := strconv.Atoi(mainflux.Env(envMQTTMaxPacketSize, defMQTTMaxPacketSize))
+	if err != nil || mqttMaxPacketSize < 0 {
+		log.Fatalf("Invalid value passed for %s\n", envMQTTMaxPacketSize)
+	}
+
 	return config{
 		mqttPort:              mainflux.Env(envMQTTPort, defMQTTPort),
 		mqttTargetHost:        mainflux.Env(envMQTTTargetHost, defMQTTTargetHost),
 		mqttTargetPort:        mainflux.Env(envMQTTTargetPort, defMQTTTargetPort),
 		mqttForwarderTimeout:  mqttTimeout,
 		mqttTargetHealthCheck: mainflux.Env(envMQTTTargetHealthCheck, defMQTTTargetHealthCheck),
+		mqttMaxPacketSize:     mqttMaxPacketSize,
 		httpPort:              mainflux.Env(envHTTPPort, defHTTPPort),
 		httpTargetHost:        mainflux.Env(envHTTPTargetHost, defHTTPTargetHost),
 		httpTargetPort:        mainflux.Env(envHTTPTargetPort, defHTTPTargetPort),
@@ -232,18 +304,31 @@ func loadConfig() config {
 		jaegerURL:             mainflux.Env(envJaegerURL, defJaegerURL),
 		thingsAuthURL:         mainflux.Env(envThingsAuthURL, defThingsAuthURL),
 		thingsAuthTimeout:     authTimeout,
+		schemaTTL:             schemaTTL,
 		thingsURL:             mainflux.Env(envThingsAuthURL, defThingsAuthURL),
-		natsURL:               mainflux.Env(envNatsURL, defNatsURL),
-		logLevel:              mainflux.Env(envLogLevel, defLogLevel),
-		clientTLS:             tls,
-		caCerts:               mainflux.Env(envCACerts, defCACerts),
-		instance:              mainflux.Env(envInstance, defInstance),
-		esURL:                 mainflux.Env(envESURL, defESURL),
-		esPass:                mainflux.Env(envESPass, defESPass),
-		esDB:                  mainflux.Env(envESDB, defESDB),
-		authURL:               mainflux.Env(envAuthCacheURL, defAuthcacheURL),
-		authPass:              mainflux.Env(envAuthCachePass, defAuthCachePass),
-		authDB:                mainflux.Env(envAuthCacheDB, defAuthCacheDB),
+		brokerCfg: brokers.Config{
+			Type:     mainflux.Env(envBrokerType, defBrokerType),
+			NatsURL:  mainflux.Env(envNatsURL, defNatsURL),
+			KafkaURL: mainflux.Env(envKafkaURL, defKafkaURL),
+		},
+		logLevel:  mainflux.Env(envLogLevel, defLogLevel),
+		clientTLS: tls,
+		caCerts:   mainflux.Env(envCACerts, defCACerts),
+		instance:  mainflux.Env(envInstance, defInstance),
+		esURL:     mainflux.Env(envESURL, defESURL),
+		esPass:    mainflux.Env(envESPass, defESPass),
+		esDB:      mainflux.Env(envESDB, defESDB),
+		authURL:   mainflux.Env(envAuthCacheURL, defAuthcacheURL),
+		authPass:  mainflux.Env(envAuthCachePass, defAuthCachePass),
+		authDB:    mainflux.Env(envAuthCacheDB, defAuthCacheDB),
+
+		retainedURL:  mainflux.Env(envRetainedURL, defRetainedURL),
+		retainedPass: mainflux.Env(envRetainedPass, defRetainedPass),
+		retainedDB:   mainflux.Env(envRetainedDB, defRetainedDB),
+		quotaURL:     mainflux.Env(envQuotaURL, defQuotaURL),
+		quotaPass:    mainflux.Env(envQuotaPass, defQuotaPass),
+		quotaDB:      mainflux.Env(envQuotaDB, defQuotaDB),
+		quotaTTL:     quotaTTL,
 	}
 }
 
@@ -312,16 +397,17 @@ func connectToRedis(redisURL, redisPass, redisDB string, logger mflog.Logger) *r
 func proxyMQTT(cfg config, logger mflog.Logger, handler session.Handler, errs chan error) {
 	address := fmt.Sprintf(":%s", cfg.mqttPort)
 	target := fmt.Sprintf("%s:%s", cfg.mqttTargetHost, cfg.mqttTargetPort)
-	mp := mp.New(address, target, handler, logger)
+	mp := mqtt.NewProxy(address, target, cfg.mqttMaxPacketSize, handler, logger)
 
 	errs <- mp.Listen()
 }
 func proxyWS(cfg config, logger mflog.Logger, handler session.Handler, errs chan error) {
 	target := fmt.Sprintf("%s:%s", cfg.httpTargetHost, cfg.httpTargetPort)
-	wp := ws.New(target, cfg.httpTargetPath, "ws", handler, logger)
+	wp := mqtt.NewWSProxy(target, cfg.httpTargetPath, cfg.mqttMaxPacketSize, handler, logger)
 	http.Handle("/mqtt", wp.Handler())
+	http.Handle("/metrics", promhttp.Handler())
 
-	errs <- wp.Listen(cfg.httpPort)
+	errs <- http.ListenAndServe(fmt.Sprintf(":%s", cfg.httpPort), nil)
 }
 
 func healthcheck(cfg config) func() error {
@@ -21,7 +21,7 @@ import (
 	"github.com/mainflux/mainflux/opcua/db"
 	"github.com/mainflux/mainflux/opcua/gopcua"
 	"github.com/mainflux/mainflux/opcua/redis"
-	"github.com/mainflux/mainflux/pkg/messaging/nats"
+	"github.com/mainflux/mainflux/pkg/messaging/brokers"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
@@ -36,6 +36,8 @@ const (
 	defOPCCertFile    = ""
 	defOPCKeyFile     = ""
 	defNatsURL        = "nats://localhost:4222"
+	defBrokerType     = "nats"
+	defKafkaURL       = "localhost:9092"
 	defESURL          = "localhost:6379"
 	defESPass         = ""
 	defESDB           = "0"
@@ -52,6 +54,8 @@ const (
 	envOPCCertFile    = "MF_OPCUA_ADAPTER_CERT_FILE"
 	envOPCKeyFile     = "MF_OPCUA_ADAPTER_KEY_FILE"
 	envNatsURL        = "MF_NATS_URL"
+	envBrokerType     = "MF_BROKER_TYPE"
+	envKafkaURL       = "MF_KAFKA_URL"
 	envESURL          = "MF_THINGS_ES_URL"
 	envESPass         = "MF_THINGS_ES_PASS"
 	envESDB           = "MF_THINGS_ES_DB"
@@ -68,7 +72,7 @@ const (
 type config struct {
 	httpPort       string
 	opcuaConfig    opcua.Config
-	natsURL        string
+	brokerCfg      brokers.Config
 	logLevel       string
 	esURL          string
 	esPass         string
@@ -97,9 +101,9 @@ func main() {
 	esConn := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
 	defer esConn.Close()
 
-	pubSub, err := nats.NewPubSub(cfg.natsURL, "", logger)
+	pubSub, err := brokers.NewPubSub(cfg.brokerCfg, "", logger)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
 		os.Exit(1)
 	}
 	defer pubSub.Close()
@@ -152,9 +156,13 @@ func loadConfig() config {
 		KeyFile:  mainflux.Env(envOPCKeyFile, defOPCKeyFile),
 	}
 	return config{
-		httpPort:       mainflux.Env(envHTTPPort, defHTTPPort),
-		opcuaConfig:    oc,
-		natsURL:        mainflux.Env(envNatsURL, defNatsURL),
+		httpPort:    mainflux.Env(envHTTPPort, defHTTPPort),
+		opcuaConfig: oc,
+		brokerCfg: brokers.Config{
+			Type:     mainflux.Env(envBrokerType, defBrokerType),
+			NatsURL:  mainflux.Env(envNatsURL, defNatsURL),
+			KafkaURL: mainflux.Env(envKafkaURL, defKafkaURL),
+		},
 		logLevel:       mainflux.Env(envLogLevel, defLogLevel),
 		esURL:          mainflux.Env(envESURL, defESURL),
 		esPass:         mainflux.Env(envESPass, defESPass),
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -16,6 +17,7 @@ import (
 	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis/v8"
 	"github.com/jmoiron/sqlx"
 	"github.com/mainflux/mainflux"
 	authapi "github.com/mainflux/mainflux/auth/api/grpc"
@@ -23,12 +25,15 @@ import (
 	"github.com/mainflux/mainflux/consumers/notifiers"
 	"github.com/mainflux/mainflux/consumers/notifiers/api"
 	"github.com/mainflux/mainflux/consumers/notifiers/postgres"
+	rediscons "github.com/mainflux/mainflux/consumers/notifiers/redis/consumer"
 
 	mfsmpp "github.com/mainflux/mainflux/consumers/notifiers/smpp"
 	"github.com/mainflux/mainflux/consumers/notifiers/tracing"
 	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging/brokers"
 	"github.com/mainflux/mainflux/pkg/messaging/nats"
 	"github.com/mainflux/mainflux/pkg/ulid"
+	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
 	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	jconfig "github.com/uber/jaeger-client-go/config"
@@ -54,6 +59,16 @@ const (
 	defFrom          = ""
 	defJaegerURL     = ""
 	defNatsURL       = "nats://localhost:4222"
+	defBrokerType    = "nats"
+	defKafkaURL      = "localhost:9092"
+	defJetStream     = "false"
+	defJSMaxDeliver  = "5"
+	defJSBackoff     = "1s"
+	defJSDeadLetter  = "deadletter"
+	defThingsTLS     = "false"
+	defThingsCACerts = ""
+	defThingsURL     = ""
+	defThingsTimeout = "1s"
 
 	defSmppAddress    = ""
 	defSmppUsername   = ""
@@ -69,6 +84,11 @@ const (
 	defAuthURL     = "localhost:8181"
 	defAuthTimeout = "1s"
 
+	defESURL      = "localhost:6379"
+	defESPass     = ""
+	defESDB       = "0"
+	defESConsumer = "smpp-notifier"
+
 	envLogLevel      = "MF_SMPP_NOTIFIER_LOG_LEVEL"
 	envDBHost        = "MF_SMPP_NOTIFIER_DB_HOST"
 	envDBPort        = "MF_SMPP_NOTIFIER_DB_PORT"
@@ -86,6 +106,16 @@ const (
 	envFrom          = "MF_SMPP_NOTIFIER_SOURCE_ADDR"
 	envJaegerURL     = "MF_JAEGER_URL"
 	envNatsURL       = "MF_NATS_URL"
+	envBrokerType    = "MF_BROKER_TYPE"
+	envKafkaURL      = "MF_KAFKA_URL"
+	envJetStream     = "MF_NATS_JETSTREAM"
+	envJSMaxDeliver  = "MF_NATS_JETSTREAM_MAX_DELIVER"
+	envJSBackoff     = "MF_NATS_JETSTREAM_BACKOFF"
+	envJSDeadLetter  = "MF_NATS_JETSTREAM_DEAD_LETTER"
+	envThingsTLS     = "MF_THINGS_CLIENT_TLS"
+	envThingsCACerts = "MF_THINGS_CA_CERTS"
+	envThingsURL     = "MF_THINGS_AUTH_GRPC_URL"
+	envThingsTimeout = "MF_THINGS_AUTH_GRPC_TIMEOUT"
 
 	envSmppAddress    = "MF_SMPP_ADDRESS"
 	envSmppUsername   = "MF_SMPP_USERNAME"
@@ -100,23 +130,38 @@ const (
 	envAuthCACerts = "MF_AUTH_CA_CERTS"
 	envAuthURL     = "MF_AUTH_GRPC_URL"
 	envAuthTimeout = "MF_AUTH_GRPC_TIMEOUT"
+
+	envESURL      = "MF_THINGS_ES_URL"
+	envESPass     = "MF_THINGS_ES_PASS"
+	envESDB       = "MF_THINGS_ES_DB"
+	envESConsumer = "MF_SMPP_NOTIFIER_EVENT_CONSUMER"
 )
 
 type config struct {
-	natsURL     string
-	configPath  string
-	logLevel    string
-	dbConfig    postgres.Config
-	smppConf    mfsmpp.Config
-	from        string
-	httpPort    string
-	serverCert  string
-	serverKey   string
-	jaegerURL   string
-	authTLS     bool
-	authCACerts string
-	authURL     string
-	authTimeout time.Duration
+	brokerCfg     brokers.Config
+	jetStream     bool
+	jsConfig      nats.JetStreamConfig
+	thingsTLS     bool
+	thingsCACerts string
+	thingsURL     string
+	thingsTimeout time.Duration
+	configPath    string
+	logLevel      string
+	dbConfig      postgres.Config
+	smppConf      mfsmpp.Config
+	from          string
+	httpPort      string
+	serverCert    string
+	serverKey     string
+	jaegerURL     string
+	authTLS       bool
+	authCACerts   string
+	authURL       string
+	authTimeout   time.Duration
+	esURL         string
+	esPass        string
+	esDB          string
+	esConsumer    string
 }
 
 func main() {
@@ -130,9 +175,9 @@ func main() {
 	db := connectToDB(cfg.dbConfig, logger)
 	defer db.Close()
 
-	pubSub, err := nats.NewPubSub(cfg.natsURL, "", logger)
+	pubSub, err := newPubSub(cfg, logger)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
 		os.Exit(1)
 	}
 	defer pubSub.Close()
@@ -154,12 +199,25 @@ func main() {
 	svc := newService(db, dbTracer, auth, cfg, logger)
 	errs := make(chan error, 2)
 
-	if err = consumers.Start(pubSub, svc, cfg.configPath, logger); err != nil {
+	thingsTracer, thingsCloser := initJaeger("things", cfg.jaegerURL, logger)
+	defer thingsCloser.Close()
+
+	things, thingsClose := connectToThings(cfg, thingsTracer, logger)
+	if thingsClose != nil {
+		defer thingsClose()
+	}
+
+	if err = consumers.Start(pubSub, svc, cfg.configPath, things, logger); err != nil {
 		logger.Error(fmt.Sprintf("Failed to create Postgres writer: %s", err))
 	}
 
 	go startHTTPServer(tracer, svc, cfg.httpPort, cfg.serverCert, cfg.serverKey, logger, errs)
 
+	esClient := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
+	defer esClient.Close()
+
+	go subscribeToThingsES(svc, esClient, cfg.esConsumer, logger)
+
 	go func() {
 		c := make(chan os.Signal)
 		signal.Notify(c, syscall.SIGINT)
@@ -171,6 +229,37 @@ func main() {
 }
 
 func loadConfig() config {
+	jetStream, err := strconv.ParseBool(mainflux.Env(envJetStream, defJetStream))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envJetStream)
+	}
+
+	jsMaxDeliver, err := strconv.Atoi(mainflux.Env(envJSMaxDeliver, defJSMaxDeliver))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envJSMaxDeliver, err.Error())
+	}
+
+	jsBackoff, err := time.ParseDuration(mainflux.Env(envJSBackoff, defJSBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envJSBackoff, err.Error())
+	}
+
+	jsConfig := nats.JetStreamConfig{
+		MaxDeliver: jsMaxDeliver,
+		Backoff:    jsBackoff,
+		DeadLetter: mainflux.Env(envJSDeadLetter, defJSDeadLetter),
+	}
+
+	thingsTLS, err := strconv.ParseBool(mainflux.Env(envThingsTLS, defThingsTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envThingsTLS)
+	}
+
+	thingsTimeout, err := time.ParseDuration(mainflux.Env(envThingsTimeout, defThingsTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
+	}
+
 	authTimeout, err := time.ParseDuration(mainflux.Env(envAuthTimeout, defAuthTimeout))
 	if err != nil {
 		log.Fatalf("Invalid %s value: %s", envAuthTimeout, err.Error())
@@ -222,20 +311,34 @@ func loadConfig() config {
 	}
 
 	return config{
-		logLevel:    mainflux.Env(envLogLevel, defLogLevel),
-		natsURL:     mainflux.Env(envNatsURL, defNatsURL),
-		configPath:  mainflux.Env(envConfigPath, defConfigPath),
-		dbConfig:    dbConfig,
-		smppConf:    smppConf,
-		from:        mainflux.Env(envFrom, defFrom),
-		httpPort:    mainflux.Env(envHTTPPort, defHTTPPort),
-		serverCert:  mainflux.Env(envServerCert, defServerCert),
-		serverKey:   mainflux.Env(envServerKey, defServerKey),
-		jaegerURL:   mainflux.Env(envJaegerURL, defJaegerURL),
-		authTLS:     tls,
-		authCACerts: mainflux.Env(envAuthCACerts, defAuthCACerts),
-		authURL:     mainflux.Env(envAuthURL, defAuthURL),
-		authTimeout: authTimeout,
+		logLevel: mainflux.Env(envLogLevel, defLogLevel),
+		brokerCfg: brokers.Config{
+			Type:     mainflux.Env(envBrokerType, defBrokerType),
+			NatsURL:  mainflux.Env(envNatsURL, defNatsURL),
+			KafkaURL: mainflux.Env(envKafkaURL, defKafkaURL),
+		},
+		jetStream:     jetStream,
+		jsConfig:      jsConfig,
+		thingsTLS:     thingsTLS,
+		thingsCACerts: mainflux.Env(envThingsCACerts, defThingsCACerts),
+		thingsURL:     mainflux.Env(envThingsURL, defThingsURL),
+		thingsTimeout: thingsTimeout,
+		configPath:    mainflux.Env(envConfigPath, defConfigPath),
+		dbConfig:      dbConfig,
+		smppConf:      smppConf,
+		from:          mainflux.Env(envFrom, defFrom),
+		httpPort:      mainflux.Env(envHTTPPort, defHTTPPort),
+		serverCert:    mainflux.Env(envServerCert, defServerCert),
+		serverKey:     mainflux.Env(envServerKey, defServerKey),
+		jaegerURL:     mainflux.Env(envJaegerURL, defJaegerURL),
+		authTLS:       tls,
+		authCACerts:   mainflux.Env(envAuthCACerts, defAuthCACerts),
+		authURL:       mainflux.Env(envAuthURL, defAuthURL),
+		authTimeout:   authTimeout,
+		esURL:         mainflux.Env(envESURL, defESURL),
+		esPass:        mainflux.Env(envESPass, defESPass),
+		esDB:          mainflux.Env(envESDB, defESDB),
+		esConsumer:    mainflux.Env(envESConsumer, defESConsumer),
 	}
 
 }
@@ -333,3 +436,62 @@ func startHTTPServer(tracer opentracing.Tracer, svc notifiers.Service, port stri
 		errs <- http.ListenAndServe(p, api.MakeHandler(svc, tracer))
 	}
 }
+
+func newPubSub(cfg config, logger logger.Logger) (brokers.PubSub, error) {
+	if cfg.jetStream && cfg.brokerCfg.Type == brokers.NATS {
+		return nats.NewJetStreamPubSub(cfg.brokerCfg.NatsURL, "smpp-notifier", cfg.jsConfig, logger)
+	}
+	return brokers.NewPubSub(cfg.brokerCfg, "", logger)
+}
+
+func connectToThings(cfg config, tracer opentracing.Tracer, logger logger.Logger) (mainflux.ThingsServiceClient, func() error) {
+	if cfg.thingsURL == "" {
+		logger.Info("Things service URL is not set, thing metadata enrichment is disabled")
+		return nil, nil
+	}
+
+	var opts []grpc.DialOption
+	if cfg.thingsTLS {
+		if cfg.thingsCACerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.thingsCACerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.thingsURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things service: %s", err))
+		os.Exit(1)
+	}
+
+	return thingsapi.NewClient(conn, tracer, cfg.thingsTimeout), conn.Close
+}
+
+func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) *redis.Client {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
+		os.Exit(1)
+	}
+
+	return redis.NewClient(&redis.Options{
+		Addr:     redisURL,
+		Password: redisPass,
+		DB:       db,
+	})
+}
+
+func subscribeToThingsES(svc notifiers.Service, client *redis.Client, consumer string, logger logger.Logger) {
+	eventStore := rediscons.NewEventStore(svc, client, "mainflux.smpp-notifier", consumer, logger)
+	logger.Info("Subscribed to Redis Event Store")
+	if err := eventStore.Subscribe(context.Background(), "mainflux.things"); err != nil {
+		logger.Warn(fmt.Sprintf("SMPP notifier service failed to subscribe to event sourcing: %s", err))
+	}
+}
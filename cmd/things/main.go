@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -30,6 +31,7 @@ import (
 	thhttpapi "github.com/mainflux/mainflux/things/api/things/http"
 	"github.com/mainflux/mainflux/things/postgres"
 	rediscache "github.com/mainflux/mainflux/things/redis"
+	rediscons "github.com/mainflux/mainflux/things/redis/consumer"
 	localusers "github.com/mainflux/mainflux/things/standalone"
 	"github.com/mainflux/mainflux/things/tracing"
 	opentracing "github.com/opentracing/opentracing-go"
@@ -68,6 +70,10 @@ const (
 	defJaegerURL       = ""
 	defAuthURL         = "localhost:8181"
 	defAuthTimeout     = "1s"
+	defESConsumerName  = "things"
+	defPresenceTimeout = "5m"
+	defRestoreWindow   = "168h"
+	defPurgeInterval   = "1h"
 
 	envLogLevel        = "MF_THINGS_LOG_LEVEL"
 	envDBHost          = "MF_THINGS_DB_HOST"
@@ -97,6 +103,10 @@ const (
 	envJaegerURL       = "MF_JAEGER_URL"
 	envAuthURL         = "MF_AUTH_GRPC_URL"
 	envAuthTimeout     = "MF_AUTH_GRPC_TIMEOUT"
+	envESConsumerName  = "MF_THINGS_EVENT_CONSUMER"
+	envPresenceTimeout = "MF_THINGS_PRESENCE_TIMEOUT"
+	envRestoreWindow   = "MF_THINGS_RESTORE_WINDOW"
+	envPurgeInterval   = "MF_THINGS_PURGE_INTERVAL"
 )
 
 type config struct {
@@ -120,6 +130,10 @@ type config struct {
 	jaegerURL       string
 	authURL         string
 	authTimeout     time.Duration
+	esConsumerName  string
+	presenceTimeout time.Duration
+	restoreWindow   time.Duration
+	purgeInterval   time.Duration
 }
 
 func main() {
@@ -160,6 +174,8 @@ func main() {
 	go startHTTPServer(thhttpapi.MakeHandler(thingsTracer, svc), cfg.httpPort, cfg, logger, errs)
 	go startHTTPServer(authhttpapi.MakeHandler(thingsTracer, svc), cfg.authHTTPPort, cfg, logger, errs)
 	go startGRPCServer(svc, thingsTracer, cfg, logger, errs)
+	go subscribeToPresenceES(svc, esClient, cfg.esConsumerName, cfg.presenceTimeout, logger)
+	go purge(svc, cfg.restoreWindow, cfg.purgeInterval, logger)
 
 	go func() {
 		c := make(chan os.Signal)
@@ -182,6 +198,21 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envAuthTimeout, err.Error())
 	}
 
+	presenceTimeout, err := time.ParseDuration(mainflux.Env(envPresenceTimeout, defPresenceTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envPresenceTimeout, err.Error())
+	}
+
+	restoreWindow, err := time.ParseDuration(mainflux.Env(envRestoreWindow, defRestoreWindow))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envRestoreWindow, err.Error())
+	}
+
+	purgeInterval, err := time.ParseDuration(mainflux.Env(envPurgeInterval, defPurgeInterval))
+	if err != nil || purgeInterval <= 0 {
+		log.Fatalf("Invalid %s value: %s", envPurgeInterval, mainflux.Env(envPurgeInterval, defPurgeInterval))
+	}
+
 	dbConfig := postgres.Config{
 		Host:        mainflux.Env(envDBHost, defDBHost),
 		Port:        mainflux.Env(envDBPort, defDBPort),
@@ -215,6 +246,10 @@ func loadConfig() config {
 		jaegerURL:       mainflux.Env(envJaegerURL, defJaegerURL),
 		authURL:         mainflux.Env(envAuthURL, defAuthURL),
 		authTimeout:     authTimeout,
+		esConsumerName:  mainflux.Env(envESConsumerName, defESConsumerName),
+		presenceTimeout: presenceTimeout,
+		restoreWindow:   restoreWindow,
+		purgeInterval:   purgeInterval,
 	}
 }
 
@@ -308,6 +343,15 @@ func newService(auth mainflux.AuthServiceClient, dbTracer opentracing.Tracer, ca
 	channelsRepo := postgres.NewChannelRepository(database)
 	channelsRepo = tracing.ChannelRepositoryMiddleware(dbTracer, channelsRepo)
 
+	profilesRepo := postgres.NewProfileRepository(database)
+	profilesRepo = tracing.ProfileRepositoryMiddleware(dbTracer, profilesRepo)
+
+	quotasRepo := postgres.NewQuotaRepository(database)
+	quotasRepo = tracing.QuotaRepositoryMiddleware(dbTracer, quotasRepo)
+
+	statusRepo := postgres.NewStatusRepository(database)
+	statusRepo = tracing.StatusRepositoryMiddleware(dbTracer, statusRepo)
+
 	chanCache := rediscache.NewChannelCache(cacheClient)
 	chanCache = tracing.ChannelCacheMiddleware(cacheTracer, chanCache)
 
@@ -315,7 +359,7 @@ func newService(auth mainflux.AuthServiceClient, dbTracer opentracing.Tracer, ca
 	thingCache = tracing.ThingCacheMiddleware(cacheTracer, thingCache)
 	idProvider := uuid.New()
 
-	svc := things.New(auth, thingsRepo, channelsRepo, chanCache, thingCache, idProvider)
+	svc := things.New(auth, thingsRepo, channelsRepo, profilesRepo, quotasRepo, statusRepo, chanCache, thingCache, idProvider)
 	svc = rediscache.NewEventStoreMiddleware(svc, esClient)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
@@ -374,3 +418,29 @@ func startGRPCServer(svc things.Service, tracer opentracing.Tracer, cfg config,
 	mainflux.RegisterThingsServiceServer(server, authgrpcapi.NewServer(tracer, svc))
 	errs <- server.Serve(listener)
 }
+
+func subscribeToPresenceES(svc things.Service, client *redis.Client, consumer string, timeout time.Duration, logger logger.Logger) {
+	eventStore := rediscons.NewEventStore(svc, client, consumer, timeout, logger)
+	logger.Info("Subscribed to Redis Event Store")
+	if err := eventStore.Subscribe(context.Background()); err != nil {
+		logger.Warn(fmt.Sprintf("Things service failed to subscribe to event sourcing: %s", err))
+	}
+}
+
+// purge periodically deletes the things and the channels removed longer than
+// the restore window ago. The purge events published to the event store
+// trigger the cleanup in the other services.
+func purge(svc things.Service, window, interval time.Duration, logger logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		before := time.Now().Add(-window)
+		if _, err := svc.PurgeThings(context.Background(), before); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to purge removed things: %s", err))
+		}
+		if _, err := svc.PurgeChannels(context.Background(), before); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to purge removed channels: %s", err))
+		}
+	}
+}
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -21,7 +22,7 @@ import (
 	authapi "github.com/mainflux/mainflux/auth/api/grpc"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/pkg/messaging"
-	"github.com/mainflux/mainflux/pkg/messaging/nats"
+	"github.com/mainflux/mainflux/pkg/messaging/brokers"
 	"github.com/mainflux/mainflux/pkg/uuid"
 	localusers "github.com/mainflux/mainflux/things/standalone"
 	"github.com/mainflux/mainflux/twins"
@@ -29,6 +30,7 @@ import (
 	twapi "github.com/mainflux/mainflux/twins/api/http"
 	twmongodb "github.com/mainflux/mainflux/twins/mongodb"
 	rediscache "github.com/mainflux/mainflux/twins/redis"
+	rediscons "github.com/mainflux/mainflux/twins/redis/consumer"
 	"github.com/mainflux/mainflux/twins/tracing"
 	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
@@ -52,12 +54,18 @@ const (
 	defCacheURL        = "localhost:6379"
 	defCachePass       = ""
 	defCacheDB         = "0"
+	defESURL           = "localhost:6379"
+	defESPass          = ""
+	defESDB            = "0"
+	defESConsumer      = "twins"
 	defStandaloneEmail = ""
 	defStandaloneToken = ""
 	defClientTLS       = "false"
 	defCACerts         = ""
 	defChannelID       = ""
 	defNatsURL         = "nats://localhost:4222"
+	defBrokerType      = "nats"
+	defKafkaURL        = "localhost:9092"
 	defAuthURL         = "localhost:8181"
 	defAuthTimeout     = "1s"
 
@@ -72,12 +80,18 @@ const (
 	envCacheURL        = "MF_TWINS_CACHE_URL"
 	envCachePass       = "MF_TWINS_CACHE_PASS"
 	envCacheDB         = "MF_TWINS_CACHE_DB"
+	envESURL           = "MF_THINGS_ES_URL"
+	envESPass          = "MF_THINGS_ES_PASS"
+	envESDB            = "MF_THINGS_ES_DB"
+	envESConsumer      = "MF_TWINS_EVENT_CONSUMER"
 	envStandaloneEmail = "MF_TWINS_STANDALONE_EMAIL"
 	envStandaloneToken = "MF_TWINS_STANDALONE_TOKEN"
 	envClientTLS       = "MF_TWINS_CLIENT_TLS"
 	envCACerts         = "MF_TWINS_CA_CERTS"
 	envChannelID       = "MF_TWINS_CHANNEL_ID"
 	envNatsURL         = "MF_NATS_URL"
+	envBrokerType      = "MF_BROKER_TYPE"
+	envKafkaURL        = "MF_KAFKA_URL"
 	envAuthURL         = "MF_AUTH_GRPC_URL"
 	envAuthTimeout     = "MF_AUTH_GRPC_TIMEOUT"
 )
@@ -92,12 +106,16 @@ type config struct {
 	cacheURL        string
 	cachePass       string
 	cacheDB         string
+	esURL           string
+	esPass          string
+	esDB            string
+	esConsumer      string
 	standaloneEmail string
 	standaloneToken string
 	clientTLS       bool
 	caCerts         string
 	channelID       string
-	natsURL         string
+	brokerCfg       brokers.Config
 
 	authURL     string
 	authTimeout time.Duration
@@ -127,19 +145,23 @@ func main() {
 	defer authCloser.Close()
 	auth, _ := createAuthClient(cfg, authTracer, logger)
 
-	pubSub, err := nats.NewPubSub(cfg.natsURL, queue, logger)
+	pubSub, err := brokers.NewPubSub(cfg.brokerCfg, queue, logger)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
 		os.Exit(1)
 	}
 	defer pubSub.Close()
 
 	svc := newService(pubSub, cfg.channelID, auth, dbTracer, db, cacheTracer, cacheClient, logger)
 
+	esClient := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
+	defer esClient.Close()
+
 	tracer, closer := initJaeger("twins", cfg.jaegerURL, logger)
 	defer closer.Close()
 	errs := make(chan error, 2)
 	go startHTTPServer(twapi.MakeHandler(tracer, svc), cfg.httpPort, cfg, logger, errs)
+	go subscribeToThingsES(svc, esClient, cfg.esConsumer, logger)
 
 	go func() {
 		c := make(chan os.Signal)
@@ -178,14 +200,22 @@ func loadConfig() config {
 		cacheURL:        mainflux.Env(envCacheURL, defCacheURL),
 		cachePass:       mainflux.Env(envCachePass, defCachePass),
 		cacheDB:         mainflux.Env(envCacheDB, defCacheDB),
+		esURL:           mainflux.Env(envESURL, defESURL),
+		esPass:          mainflux.Env(envESPass, defESPass),
+		esDB:            mainflux.Env(envESDB, defESDB),
+		esConsumer:      mainflux.Env(envESConsumer, defESConsumer),
 		standaloneEmail: mainflux.Env(envStandaloneEmail, defStandaloneEmail),
 		standaloneToken: mainflux.Env(envStandaloneToken, defStandaloneToken),
 		clientTLS:       tls,
 		caCerts:         mainflux.Env(envCACerts, defCACerts),
 		channelID:       mainflux.Env(envChannelID, defChannelID),
-		natsURL:         mainflux.Env(envNatsURL, defNatsURL),
-		authURL:         mainflux.Env(envAuthURL, defAuthURL),
-		authTimeout:     authTimeout,
+		brokerCfg: brokers.Config{
+			Type:     mainflux.Env(envBrokerType, defBrokerType),
+			NatsURL:  mainflux.Env(envNatsURL, defNatsURL),
+			KafkaURL: mainflux.Env(envKafkaURL, defKafkaURL),
+		},
+		authURL:     mainflux.Env(envAuthURL, defAuthURL),
+		authTimeout: authTimeout,
 	}
 }
 
@@ -290,7 +320,7 @@ func newService(ps messaging.PubSub, chanID string, users mainflux.AuthServiceCl
 		}, []string{"method"}),
 	)
 
-	err := ps.Subscribe(nats.SubjectAllChannels, func(msg messaging.Message) error {
+	err := ps.Subscribe(brokers.SubjectAllChannels, func(msg messaging.Message) error {
 		if msg.Channel == chanID {
 			return nil
 		}
@@ -310,6 +340,14 @@ func newService(ps messaging.PubSub, chanID string, users mainflux.AuthServiceCl
 	return svc
 }
 
+func subscribeToThingsES(svc twins.Service, client *redis.Client, consumer string, logger logger.Logger) {
+	eventStore := rediscons.NewEventStore(svc, client, consumer, logger)
+	logger.Info("Subscribed to Redis Event Store")
+	if err := eventStore.Subscribe(context.Background(), "mainflux.things"); err != nil {
+		logger.Warn(fmt.Sprintf("Twins service failed to subscribe to event sourcing: %s", err))
+	}
+}
+
 func startHTTPServer(handler http.Handler, port string, cfg config, logger logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", port)
 	if cfg.serverCert != "" || cfg.serverKey != "" {
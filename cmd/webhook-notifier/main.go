@@ -0,0 +1,521 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis/v8"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	authapi "github.com/mainflux/mainflux/auth/api/grpc"
+	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/consumers/notifiers"
+	"github.com/mainflux/mainflux/consumers/notifiers/api"
+	"github.com/mainflux/mainflux/consumers/notifiers/postgres"
+	rediscons "github.com/mainflux/mainflux/consumers/notifiers/redis/consumer"
+	"github.com/mainflux/mainflux/consumers/notifiers/tracing"
+	"github.com/mainflux/mainflux/consumers/notifiers/webhook"
+	webhookapi "github.com/mainflux/mainflux/consumers/notifiers/webhook/api"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging/brokers"
+	"github.com/mainflux/mainflux/pkg/messaging/nats"
+	"github.com/mainflux/mainflux/pkg/ulid"
+	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	jconfig "github.com/uber/jaeger-client-go/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	defLogLevel      = "error"
+	defDBHost        = "localhost"
+	defDBPort        = "5432"
+	defDBUser        = "mainflux"
+	defDBPass        = "mainflux"
+	defDB            = "subscriptions"
+	defConfigPath    = "/config.toml"
+	defDBSSLMode     = "disable"
+	defDBSSLCert     = ""
+	defDBSSLKey      = ""
+	defDBSSLRootCert = ""
+	defHTTPPort      = "8908"
+	defServerCert    = ""
+	defServerKey     = ""
+	defSecret        = ""
+	defTimeout       = "5s"
+	defMaxAttempts   = "5"
+	defBackoff       = "1s"
+	defWorkers       = "10"
+	defAllowedNets   = ""
+	defJaegerURL     = ""
+	defNatsURL       = "nats://localhost:4222"
+	defBrokerType    = "nats"
+	defKafkaURL      = "localhost:9092"
+	defJetStream     = "false"
+	defJSMaxDeliver  = "5"
+	defJSBackoff     = "1s"
+	defJSDeadLetter  = "deadletter"
+	defThingsTLS     = "false"
+	defThingsCACerts = ""
+	defThingsURL     = ""
+	defThingsTimeout = "1s"
+
+	defAuthTLS     = "false"
+	defAuthCACerts = ""
+	defAuthURL     = "localhost:8181"
+	defAuthTimeout = "1s"
+
+	defESURL      = "localhost:6379"
+	defESPass     = ""
+	defESDB       = "0"
+	defESConsumer = "webhook-notifier"
+
+	envLogLevel      = "MF_WEBHOOK_NOTIFIER_LOG_LEVEL"
+	envDBHost        = "MF_WEBHOOK_NOTIFIER_DB_HOST"
+	envDBPort        = "MF_WEBHOOK_NOTIFIER_DB_PORT"
+	envDBUser        = "MF_WEBHOOK_NOTIFIER_DB_USER"
+	envDBPass        = "MF_WEBHOOK_NOTIFIER_DB_PASS"
+	envDB            = "MF_WEBHOOK_NOTIFIER_DB"
+	envConfigPath    = "MF_WEBHOOK_NOTIFIER_CONFIG_PATH"
+	envDBSSLMode     = "MF_WEBHOOK_NOTIFIER_DB_SSL_MODE"
+	envDBSSLCert     = "MF_WEBHOOK_NOTIFIER_DB_SSL_CERT"
+	envDBSSLKey      = "MF_WEBHOOK_NOTIFIER_DB_SSL_KEY"
+	envDBSSLRootCert = "MF_WEBHOOK_NOTIFIER_DB_SSL_ROOT_CERT"
+	envHTTPPort      = "MF_WEBHOOK_NOTIFIER_PORT"
+	envServerCert    = "MF_WEBHOOK_NOTIFIER_SERVER_CERT"
+	envServerKey     = "MF_WEBHOOK_NOTIFIER_SERVER_KEY"
+	envSecret        = "MF_WEBHOOK_NOTIFIER_SECRET"
+	envTimeout       = "MF_WEBHOOK_NOTIFIER_TIMEOUT"
+	envMaxAttempts   = "MF_WEBHOOK_NOTIFIER_MAX_ATTEMPTS"
+	envBackoff       = "MF_WEBHOOK_NOTIFIER_BACKOFF"
+	envWorkers       = "MF_WEBHOOK_NOTIFIER_WORKERS"
+	envAllowedNets   = "MF_WEBHOOK_NOTIFIER_ALLOWED_NETS"
+	envJaegerURL     = "MF_JAEGER_URL"
+	envNatsURL       = "MF_NATS_URL"
+	envBrokerType    = "MF_BROKER_TYPE"
+	envKafkaURL      = "MF_KAFKA_URL"
+	envJetStream     = "MF_NATS_JETSTREAM"
+	envJSMaxDeliver  = "MF_NATS_JETSTREAM_MAX_DELIVER"
+	envJSBackoff     = "MF_NATS_JETSTREAM_BACKOFF"
+	envJSDeadLetter  = "MF_NATS_JETSTREAM_DEAD_LETTER"
+	envThingsTLS     = "MF_THINGS_CLIENT_TLS"
+	envThingsCACerts = "MF_THINGS_CA_CERTS"
+	envThingsURL     = "MF_THINGS_AUTH_GRPC_URL"
+	envThingsTimeout = "MF_THINGS_AUTH_GRPC_TIMEOUT"
+
+	envAuthTLS     = "MF_AUTH_CLIENT_TLS"
+	envAuthCACerts = "MF_AUTH_CA_CERTS"
+	envAuthURL     = "MF_AUTH_GRPC_URL"
+	envAuthTimeout = "MF_AUTH_GRPC_TIMEOUT"
+
+	envESURL      = "MF_THINGS_ES_URL"
+	envESPass     = "MF_THINGS_ES_PASS"
+	envESDB       = "MF_THINGS_ES_DB"
+	envESConsumer = "MF_WEBHOOK_NOTIFIER_EVENT_CONSUMER"
+)
+
+type config struct {
+	brokerCfg     brokers.Config
+	jetStream     bool
+	jsConfig      nats.JetStreamConfig
+	thingsTLS     bool
+	thingsCACerts string
+	thingsURL     string
+	thingsTimeout time.Duration
+	configPath    string
+	logLevel      string
+	dbConfig      postgres.Config
+	webhookConf   webhook.Config
+	httpPort      string
+	serverCert    string
+	serverKey     string
+	jaegerURL     string
+	authTLS       bool
+	authCACerts   string
+	authURL       string
+	authTimeout   time.Duration
+	esURL         string
+	esPass        string
+	esDB          string
+	esConsumer    string
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	pubSub, err := newPubSub(cfg, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to message broker: %s", err))
+		os.Exit(1)
+	}
+	defer pubSub.Close()
+
+	authTracer, closer := initJaeger("auth", cfg.jaegerURL, logger)
+	defer closer.Close()
+
+	auth, close := connectToAuth(cfg, authTracer, logger)
+	if close != nil {
+		defer close()
+	}
+
+	tracer, closer := initJaeger("webhook-notifier", cfg.jaegerURL, logger)
+	defer closer.Close()
+
+	dbTracer, dbCloser := initJaeger("webhook-notifier_db", cfg.jaegerURL, logger)
+	defer dbCloser.Close()
+
+	svc, whSvc := newService(db, dbTracer, auth, cfg, logger)
+	errs := make(chan error, 2)
+
+	thingsTracer, thingsCloser := initJaeger("things", cfg.jaegerURL, logger)
+	defer thingsCloser.Close()
+
+	things, thingsClose := connectToThings(cfg, thingsTracer, logger)
+	if thingsClose != nil {
+		defer thingsClose()
+	}
+
+	if err = consumers.Start(pubSub, svc, cfg.configPath, things, logger); err != nil {
+		logger.Error(fmt.Sprintf("Failed to create webhook notifier: %s", err))
+	}
+
+	go startHTTPServer(tracer, svc, whSvc, cfg.httpPort, cfg.serverCert, cfg.serverKey, logger, errs)
+
+	esClient := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
+	defer esClient.Close()
+
+	go subscribeToThingsES(svc, esClient, cfg.esConsumer, logger)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Webhook notifier service terminated: %s", err))
+}
+
+func loadConfig() config {
+	jetStream, err := strconv.ParseBool(mainflux.Env(envJetStream, defJetStream))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envJetStream)
+	}
+
+	jsMaxDeliver, err := strconv.Atoi(mainflux.Env(envJSMaxDeliver, defJSMaxDeliver))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envJSMaxDeliver, err.Error())
+	}
+
+	jsBackoff, err := time.ParseDuration(mainflux.Env(envJSBackoff, defJSBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envJSBackoff, err.Error())
+	}
+
+	jsConfig := nats.JetStreamConfig{
+		MaxDeliver: jsMaxDeliver,
+		Backoff:    jsBackoff,
+		DeadLetter: mainflux.Env(envJSDeadLetter, defJSDeadLetter),
+	}
+
+	thingsTLS, err := strconv.ParseBool(mainflux.Env(envThingsTLS, defThingsTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envThingsTLS)
+	}
+
+	thingsTimeout, err := time.ParseDuration(mainflux.Env(envThingsTimeout, defThingsTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
+	}
+
+	authTimeout, err := time.ParseDuration(mainflux.Env(envAuthTimeout, defAuthTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envAuthTimeout, err.Error())
+	}
+
+	tls, err := strconv.ParseBool(mainflux.Env(envAuthTLS, defAuthTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envAuthTLS)
+	}
+
+	dbConfig := postgres.Config{
+		Host:        mainflux.Env(envDBHost, defDBHost),
+		Port:        mainflux.Env(envDBPort, defDBPort),
+		User:        mainflux.Env(envDBUser, defDBUser),
+		Pass:        mainflux.Env(envDBPass, defDBPass),
+		Name:        mainflux.Env(envDB, defDB),
+		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+	}
+
+	timeout, err := time.ParseDuration(mainflux.Env(envTimeout, defTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envTimeout, err.Error())
+	}
+
+	maxAttempts, err := strconv.Atoi(mainflux.Env(envMaxAttempts, defMaxAttempts))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envMaxAttempts, err.Error())
+	}
+
+	backoff, err := time.ParseDuration(mainflux.Env(envBackoff, defBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBackoff, err.Error())
+	}
+
+	workers, err := strconv.Atoi(mainflux.Env(envWorkers, defWorkers))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envWorkers, err.Error())
+	}
+
+	allowedNets, err := httputil.ParseNetworks(mainflux.Env(envAllowedNets, defAllowedNets))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envAllowedNets, err.Error())
+	}
+
+	webhookConf := webhook.Config{
+		Secret:      mainflux.Env(envSecret, defSecret),
+		Timeout:     timeout,
+		MaxAttempts: maxAttempts,
+		Backoff:     backoff,
+		Workers:     workers,
+		AllowedNets: allowedNets,
+	}
+
+	return config{
+		logLevel: mainflux.Env(envLogLevel, defLogLevel),
+		brokerCfg: brokers.Config{
+			Type:     mainflux.Env(envBrokerType, defBrokerType),
+			NatsURL:  mainflux.Env(envNatsURL, defNatsURL),
+			KafkaURL: mainflux.Env(envKafkaURL, defKafkaURL),
+		},
+		jetStream:     jetStream,
+		jsConfig:      jsConfig,
+		thingsTLS:     thingsTLS,
+		thingsCACerts: mainflux.Env(envThingsCACerts, defThingsCACerts),
+		thingsURL:     mainflux.Env(envThingsURL, defThingsURL),
+		thingsTimeout: thingsTimeout,
+		configPath:    mainflux.Env(envConfigPath, defConfigPath),
+		dbConfig:      dbConfig,
+		webhookConf:   webhookConf,
+		httpPort:      mainflux.Env(envHTTPPort, defHTTPPort),
+		serverCert:    mainflux.Env(envServerCert, defServerCert),
+		serverKey:     mainflux.Env(envServerKey, defServerKey),
+		jaegerURL:     mainflux.Env(envJaegerURL, defJaegerURL),
+		authTLS:       tls,
+		authCACerts:   mainflux.Env(envAuthCACerts, defAuthCACerts),
+		authURL:       mainflux.Env(envAuthURL, defAuthURL),
+		authTimeout:   authTimeout,
+		esURL:         mainflux.Env(envESURL, defESURL),
+		esPass:        mainflux.Env(envESPass, defESPass),
+		esDB:          mainflux.Env(envESDB, defESDB),
+		esConsumer:    mainflux.Env(envESConsumer, defESConsumer),
+	}
+
+}
+
+func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer, io.Closer) {
+	if url == "" {
+		return opentracing.NoopTracer{}, ioutil.NopCloser(nil)
+	}
+
+	tracer, closer, err := jconfig.Configuration{
+		ServiceName: svcName,
+		Sampler: &jconfig.SamplerConfig{
+			Type:  "const",
+			Param: 1,
+		},
+		Reporter: &jconfig.ReporterConfig{
+			LocalAgentHostPort: url,
+			LogSpans:           true,
+		},
+	}.NewTracer()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to init Jaeger: %s", err))
+		os.Exit(1)
+	}
+
+	return tracer, closer
+}
+
+func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
+	db, err := postgres.Connect(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func connectToAuth(cfg config, tracer opentracing.Tracer, logger logger.Logger) (mainflux.AuthServiceClient, func() error) {
+	var opts []grpc.DialOption
+	if cfg.authTLS {
+		if cfg.authCACerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.authCACerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.authURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to auth service: %s", err))
+		os.Exit(1)
+	}
+
+	return authapi.NewClient(tracer, conn, cfg.authTimeout), conn.Close
+}
+
+func newService(db *sqlx.DB, tracer opentracing.Tracer, auth mainflux.AuthServiceClient, c config, logger logger.Logger) (notifiers.Service, webhook.Service) {
+	database := postgres.NewDatabase(db)
+	repo := tracing.New(postgres.New(database), tracer)
+	deliveries := tracing.NewDeliveryRepository(postgres.NewDeliveryRepository(database), tracer)
+	idp := ulid.New()
+
+	notifier := webhook.New(c.webhookConf, deliveries, idp, logger)
+	if err := notifier.Resume(context.Background()); err != nil {
+		logger.Error(fmt.Sprintf("Failed to resume pending webhook deliveries: %s", err))
+	}
+	svc := notifiers.New(auth, repo, idp, notifier, "")
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "notifier",
+			Subsystem: "webhook",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "notifier",
+			Subsystem: "webhook",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	whSvc := webhook.NewService(auth, repo, deliveries)
+	whSvc = webhookapi.LoggingMiddleware(whSvc, logger)
+	whSvc = webhookapi.MetricsMiddleware(
+		whSvc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "notifier",
+			Subsystem: "webhook_deliveries",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "notifier",
+			Subsystem: "webhook_deliveries",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc, whSvc
+}
+
+func startHTTPServer(tracer opentracing.Tracer, svc notifiers.Service, whSvc webhook.Service, port string, certFile string, keyFile string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	mux := http.NewServeMux()
+	mux.Handle("/deliveries", webhookapi.MakeHandler(whSvc, tracer))
+	mux.Handle("/", api.MakeHandler(svc, tracer))
+	if certFile != "" || keyFile != "" {
+		logger.Info(fmt.Sprintf("Webhook notifier service started using https, cert %s key %s, exposed port %s", certFile, keyFile, port))
+		errs <- http.ListenAndServeTLS(p, certFile, keyFile, mux)
+	} else {
+		logger.Info(fmt.Sprintf("Webhook notifier service started using http, exposed port %s", port))
+		errs <- http.ListenAndServe(p, mux)
+	}
+}
+
+func newPubSub(cfg config, logger logger.Logger) (brokers.PubSub, error) {
+	if cfg.jetStream && cfg.brokerCfg.Type == brokers.NATS {
+		return nats.NewJetStreamPubSub(cfg.brokerCfg.NatsURL, "webhook-notifier", cfg.jsConfig, logger)
+	}
+	return brokers.NewPubSub(cfg.brokerCfg, "", logger)
+}
+
+func connectToThings(cfg config, tracer opentracing.Tracer, logger logger.Logger) (mainflux.ThingsServiceClient, func() error) {
+	if cfg.thingsURL == "" {
+		logger.Info("Things service URL is not set, thing metadata enrichment is disabled")
+		return nil, nil
+	}
+
+	var opts []grpc.DialOption
+	if cfg.thingsTLS {
+		if cfg.thingsCACerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.thingsCACerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.thingsURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things service: %s", err))
+		os.Exit(1)
+	}
+
+	return thingsapi.NewClient(conn, tracer, cfg.thingsTimeout), conn.Close
+}
+
+func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) *redis.Client {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
+		os.Exit(1)
+	}
+
+	return redis.NewClient(&redis.Options{
+		Addr:     redisURL,
+		Password: redisPass,
+		DB:       db,
+	})
+}
+
+func subscribeToThingsES(svc notifiers.Service, client *redis.Client, consumer string, logger logger.Logger) {
+	eventStore := rediscons.NewEventStore(svc, client, "mainflux.webhook-notifier", consumer, logger)
+	logger.Info("Subscribed to Redis Event Store")
+	if err := eventStore.Subscribe(context.Background(), "mainflux.things"); err != nil {
+		logger.Warn(fmt.Sprintf("Webhook notifier service failed to subscribe to event sourcing: %s", err))
+	}
+}
@@ -11,18 +11,25 @@ import (
 	"fmt"
 	"sync"
 
-	"github.com/gogo/protobuf/proto"
 	"github.com/mainflux/mainflux/pkg/errors"
-	broker "github.com/nats-io/nats.go"
 
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/presence"
+	"github.com/mainflux/mainflux/pkg/quota"
+	"github.com/mainflux/mainflux/pkg/retained"
+	"github.com/mainflux/mainflux/pkg/schema"
 )
 
 const chansPrefix = "channels"
 
-// ErrUnsubscribe indicates an error to unsubscribe
-var ErrUnsubscribe = errors.New("unable to unsubscribe")
+var (
+	// ErrSubscribe indicates an error to subscribe
+	ErrSubscribe = errors.New("unable to subscribe")
+
+	// ErrUnsubscribe indicates an error to unsubscribe
+	ErrUnsubscribe = errors.New("unable to unsubscribe")
+)
 
 // Service specifies CoAP service API.
 type Service interface {
@@ -35,6 +42,10 @@ type Service interface {
 
 	// Unsubscribe method is used to stop observing resource.
 	Unsubscribe(ctx context.Context, key, chanID, subptopic, token string) error
+
+	// Latest returns the last message published to the channel with
+	// specified id and subtopic.
+	Latest(ctx context.Context, key, chanID, subtopic string) (messaging.Message, error)
 }
 
 var _ Service = (*adapterService)(nil)
@@ -42,16 +53,28 @@ var _ Service = (*adapterService)(nil)
 // Observers is a map of maps,
 type adapterService struct {
 	auth      mainflux.ThingsServiceClient
-	conn      *broker.Conn
+	pubsub    messaging.PubSub
+	schemas   schema.Validator
+	store     retained.Store
+	limiter   quota.Limiter
+	tracker   presence.Tracker
 	observers map[string]observers
 	obsLock   sync.Mutex
 }
 
-// New instantiates the CoAP adapter implementation.
-func New(auth mainflux.ThingsServiceClient, nc *broker.Conn) Service {
+// New instantiates the CoAP adapter implementation. The published payloads
+// are validated against the channel schemas using the given validator, and
+// the latest messages are read from the given last-value store. The quotas
+// of the publishing things are enforced using the given limiter, and their
+// activity is recorded using the given presence tracker.
+func New(auth mainflux.ThingsServiceClient, pubsub messaging.PubSub, schemas schema.Validator, store retained.Store, limiter quota.Limiter, tracker presence.Tracker) Service {
 	as := &adapterService{
 		auth:      auth,
-		conn:      nc,
+		pubsub:    pubsub,
+		schemas:   schemas,
+		store:     store,
+		limiter:   limiter,
+		tracker:   tracker,
 		observers: make(map[string]observers),
 		obsLock:   sync.Mutex{},
 	}
@@ -70,17 +93,22 @@ func (svc *adapterService) Publish(ctx context.Context, key string, msg messagin
 	}
 	msg.Publisher = thid.GetValue()
 
-	data, err := proto.Marshal(&msg)
-	if err != nil {
+	if err := svc.limiter.Allow(ctx, msg.Publisher, len(msg.Payload)); err != nil {
 		return err
 	}
 
-	subject := fmt.Sprintf("%s.%s", chansPrefix, msg.Channel)
-	if msg.Subtopic != "" {
-		subject = fmt.Sprintf("%s.%s", subject, msg.Subtopic)
+	if err := svc.schemas.Validate(ctx, msg.Channel, msg.ContentType, msg.Payload); err != nil {
+		return err
+	}
+
+	if err := svc.pubsub.Publish(msg.Channel, msg); err != nil {
+		return err
 	}
 
-	return svc.conn.Publish(subject, data)
+	// The presence is tracked on the best-effort basis, so that the
+	// tracking failure doesn't fail the already published message.
+	svc.tracker.Seen(ctx, msg.Publisher, msg.Protocol)
+	return nil
 }
 
 func (svc *adapterService) Subscribe(ctx context.Context, key, chanID, subtopic string, c Client) error {
@@ -102,12 +130,11 @@ func (svc *adapterService) Subscribe(ctx context.Context, key, chanID, subtopic
 		svc.remove(subject, c.Token())
 	}()
 
-	obs, err := NewObserver(subject, c, svc.conn)
-	if err != nil {
+	if err := svc.put(subject, c.Token(), NewObserver(c)); err != nil {
 		c.Cancel()
 		return err
 	}
-	return svc.put(subject, c.Token(), obs)
+	return nil
 }
 
 func (svc *adapterService) Unsubscribe(ctx context.Context, key, chanID, subtopic, token string) error {
@@ -126,13 +153,29 @@ func (svc *adapterService) Unsubscribe(ctx context.Context, key, chanID, subtopi
 	return svc.remove(subject, token)
 }
 
+func (svc *adapterService) Latest(ctx context.Context, key, chanID, subtopic string) (messaging.Message, error) {
+	ar := &mainflux.AccessByKeyReq{
+		Token:  key,
+		ChanID: chanID,
+	}
+	if _, err := svc.auth.CanAccessByKey(ctx, ar); err != nil {
+		return messaging.Message{}, errors.Wrap(errors.ErrAuthorization, err)
+	}
+
+	return svc.store.Retrieve(ctx, chanID, subtopic)
+}
+
 func (svc *adapterService) put(endpoint, token string, o Observer) error {
 	svc.obsLock.Lock()
 	defer svc.obsLock.Unlock()
 
 	obs, ok := svc.observers[endpoint]
-	// If there are no observers, create map and assign it to the endpoint.
+	// If there are no observers, subscribe to the endpoint, then
+	// create map and assign it to the endpoint.
 	if !ok {
+		if err := svc.pubsub.Subscribe(endpoint, svc.broadcast(endpoint)); err != nil {
+			return errors.Wrap(ErrSubscribe, err)
+		}
 		obs = observers{token: o}
 		svc.observers[endpoint] = obs
 		return nil
@@ -161,9 +204,26 @@ func (svc *adapterService) remove(endpoint, token string) error {
 		}
 	}
 	delete(obs, token)
-	// If there are no observers left for the endpint, remove the map.
+	// If there are no observers left for the endpint, remove the map
+	// and unsubscribe from the endpoint.
 	if len(obs) == 0 {
 		delete(svc.observers, endpoint)
+		if err := svc.pubsub.Unsubscribe(endpoint); err != nil {
+			return errors.Wrap(ErrUnsubscribe, err)
+		}
 	}
 	return nil
 }
+
+func (svc *adapterService) broadcast(endpoint string) messaging.MessageHandler {
+	return func(msg messaging.Message) error {
+		svc.obsLock.Lock()
+		defer svc.obsLock.Unlock()
+
+		// There is no error handling, but the client takes care to log the error.
+		for _, o := range svc.observers[endpoint] {
+			o.Handle(msg)
+		}
+		return nil
+	}
+}
@@ -69,3 +69,20 @@ func (lm *loggingMiddleware) Unsubscribe(ctx context.Context, key, chanID, subto
 
 	return lm.svc.Unsubscribe(ctx, key, chanID, subtopic, token)
 }
+
+func (lm *loggingMiddleware) Latest(ctx context.Context, key, chanID, subtopic string) (msg messaging.Message, err error) {
+	defer func(begin time.Time) {
+		destChannel := chanID
+		if subtopic != "" {
+			destChannel = fmt.Sprintf("%s.%s", destChannel, subtopic)
+		}
+		message := fmt.Sprintf("Method latest for %s took %s to complete", destChannel, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Latest(ctx, key, chanID, subtopic)
+}
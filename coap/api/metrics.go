@@ -57,3 +57,12 @@ func (mm *metricsMiddleware) Unsubscribe(ctx context.Context, key, chanID, subto
 
 	return mm.svc.Unsubscribe(ctx, key, chanID, subtopic, token)
 }
+
+func (mm *metricsMiddleware) Latest(ctx context.Context, key, chanID, subtopic string) (messaging.Message, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "latest").Add(1)
+		mm.latency.With("method", "latest").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Latest(ctx, key, chanID, subtopic)
+}
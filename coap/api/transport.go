@@ -4,6 +4,7 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io/ioutil"
@@ -20,6 +21,11 @@ import (
 	"github.com/mainflux/mainflux/coap"
 	log "github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/presence"
+	"github.com/mainflux/mainflux/pkg/quota"
+	"github.com/mainflux/mainflux/pkg/retained"
+	"github.com/mainflux/mainflux/pkg/schema"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
 	"github.com/plgd-dev/go-coap/v2/message"
 	"github.com/plgd-dev/go-coap/v2/message/codes"
 	"github.com/plgd-dev/go-coap/v2/mux"
@@ -29,11 +35,18 @@ import (
 const (
 	protocol  = "coap"
 	authQuery = "auth"
+
+	// tooManyRequests is the 4.29 response code defined in RFC 8516,
+	// which is not provided by the CoAP library.
+	tooManyRequests codes.Code = 4<<5 | 29
 )
 
 var channelPartRegExp = regexp.MustCompile(`^channels/([\w\-]+)/messages(/[^?]*)?(\?.*)?$`)
 
-var errMalformedSubtopic = errors.New("malformed subtopic")
+var (
+	errMalformedSubtopic        = errors.New("malformed subtopic")
+	errUnsupportedContentFormat = errors.New("unsupported content format")
+)
 
 var (
 	logger  log.Logger
@@ -70,7 +83,13 @@ func handler(w mux.ResponseWriter, m *mux.Message) {
 		Context: m.Context,
 		Options: make(message.Options, 0, 16),
 	}
-	defer sendResp(w, &resp)
+	// The latest message is sent by the client as the response.
+	var sent bool
+	defer func() {
+		if !sent {
+			sendResp(w, &resp)
+		}
+	}()
 	if m.Options == nil {
 		logger.Warn("Nil options")
 		resp.Code = codes.BadOption
@@ -80,6 +99,9 @@ func handler(w mux.ResponseWriter, m *mux.Message) {
 	if err != nil {
 		logger.Warn(fmt.Sprintf("Error decoding message: %s", err))
 		resp.Code = codes.BadRequest
+		if err == errUnsupportedContentFormat {
+			resp.Code = codes.UnsupportedMediaType
+		}
 		return
 	}
 	key, err := parseKey(m)
@@ -92,19 +114,48 @@ func handler(w mux.ResponseWriter, m *mux.Message) {
 	case codes.GET:
 		var obs uint32
 		obs, err = m.Options.Observe()
-		if err != nil {
+		observe := err == nil
+		if err != nil && err != message.ErrOptionNotFound {
 			resp.Code = codes.BadOption
 			logger.Warn(fmt.Sprintf("Error reading observe option: %s", err))
 			return
 		}
-		if obs == 0 {
-			c := coap.NewClient(w.Client(), m.Token, logger)
-			err = service.Subscribe(context.Background(), key, msg.Channel, msg.Subtopic, c)
+		if observe && obs != 0 {
+			service.Unsubscribe(context.Background(), key, msg.Channel, msg.Subtopic, m.Token.String())
 			break
 		}
-		service.Unsubscribe(context.Background(), key, msg.Channel, msg.Subtopic, m.Token.String())
+
+		var accept string
+		accept, err = parseAccept(m)
+		if err != nil {
+			resp.Code = codes.NotAcceptable
+			logger.Warn(fmt.Sprintf("Error reading accept option: %s", err))
+			return
+		}
+		c := coap.NewClient(w.Client(), m.Token, accept, logger)
+		if observe {
+			if err = service.Subscribe(context.Background(), key, msg.Channel, msg.Subtopic, c); err != nil {
+				break
+			}
+		}
+
+		// Respond with the latest message, so that the observer
+		// doesn't wait for the next one to get the current value.
+		var latest messaging.Message
+		latest, err = service.Latest(context.Background(), key, msg.Channel, msg.Subtopic)
+		switch {
+		case err == nil:
+			sent = c.SendMessage(latest) == nil
+		case observe:
+			// The observer is registered regardless of the latest message.
+			if !errors.Contains(err, retained.ErrNotFound) {
+				logger.Warn(fmt.Sprintf("Error retrieving latest message: %s", err))
+			}
+			err = nil
+		}
 	case codes.POST:
-		err = service.Publish(context.Background(), key, msg)
+		ctx := presence.WithClientIP(context.Background(), w.Client().RemoteAddr().String())
+		err = service.Publish(ctx, key, msg)
 	default:
 		resp.Code = codes.NotFound
 		return
@@ -114,8 +165,25 @@ func handler(w mux.ResponseWriter, m *mux.Message) {
 		case errors.Contains(err, errors.ErrAuthorization):
 			resp.Code = codes.Unauthorized
 			return
+		case errors.Contains(err, schema.ErrInvalidPayload):
+			// Respond with the diagnostic payload, as per RFC 7252 section 5.5.2.
+			resp.Code = codes.BadRequest
+			resp.Body = bytes.NewReader([]byte(err.Error()))
+			return
+		case errors.Contains(err, schema.ErrRetrieveSchema):
+			resp.Code = codes.InternalServerError
+		case errors.Contains(err, quota.ErrRateLimited):
+			resp.Code = tooManyRequests
+		case errors.Contains(err, quota.ErrPayloadTooLarge):
+			resp.Code = codes.RequestEntityTooLarge
+		case errors.Contains(err, quota.ErrRetrieveQuota):
+			resp.Code = codes.InternalServerError
 		case errors.Contains(err, coap.ErrUnsubscribe):
 			resp.Code = codes.InternalServerError
+		case errors.Contains(err, retained.ErrNotFound):
+			resp.Code = codes.NotFound
+		case errors.Contains(err, errors.ErrViewEntity):
+			resp.Code = codes.InternalServerError
 		}
 	}
 }
@@ -142,6 +210,12 @@ func decodeMessage(msg *mux.Message) (messaging.Message, error) {
 		Created:  time.Now().UnixNano(),
 	}
 
+	if msg.Code == codes.POST {
+		if ret.ContentType, err = parseContentFormat(msg); err != nil {
+			return ret, err
+		}
+	}
+
 	if msg.Body != nil {
 		buff, err := ioutil.ReadAll(msg.Body)
 		if err != nil {
@@ -172,6 +246,44 @@ func parseKey(msg *mux.Message) (string, error) {
 	return vars[1], nil
 }
 
+// parseContentFormat returns the content type of the published payload. The
+// payloads without SenML content format are treated as SenML JSON.
+func parseContentFormat(msg *mux.Message) (string, error) {
+	cf, err := msg.Options.ContentFormat()
+	if err == message.ErrOptionNotFound {
+		return senml.JSON, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	switch cf {
+	case message.TextPlain, message.AppJSON:
+		return senml.JSON, nil
+	}
+	ct, ok := coap.ContentType(cf)
+	if !ok {
+		return "", errUnsupportedContentFormat
+	}
+	return ct, nil
+}
+
+// parseAccept returns the content type the observer accepts, or empty string
+// if the observer accepts the messages as published.
+func parseAccept(msg *mux.Message) (string, error) {
+	cf, err := msg.Options.Accept()
+	if err == message.ErrOptionNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	ct, ok := coap.ContentType(cf)
+	if !ok {
+		return "", errUnsupportedContentFormat
+	}
+	return ct, nil
+}
+
 func parseSubtopic(subtopic string) (string, error) {
 	if subtopic == "" {
 		return subtopic, nil
@@ -10,6 +10,7 @@ import (
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
 	"github.com/plgd-dev/go-coap/v2/message"
 	"github.com/plgd-dev/go-coap/v2/message/codes"
 	mux "github.com/plgd-dev/go-coap/v2/mux"
@@ -29,17 +30,49 @@ type observers map[string]Observer
 // ErrOption indicates an error when adding an option.
 var ErrOption = errors.New("unable to set option")
 
+// SenML content formats, as registered by RFC 8428.
+const (
+	SenMLJSON message.MediaType = 110
+	SenMLCBOR message.MediaType = 112
+	SenMLXML  message.MediaType = 310
+)
+
+var contentTypes = map[message.MediaType]string{
+	SenMLJSON: senml.JSON,
+	SenMLCBOR: senml.CBOR,
+	SenMLXML:  senml.XML,
+}
+
+// ContentType returns the SenML content type of the CoAP content format.
+func ContentType(cf message.MediaType) (string, bool) {
+	ct, ok := contentTypes[cf]
+	return ct, ok
+}
+
+// ContentFormat returns the CoAP content format of the SenML content type.
+func ContentFormat(ct string) (message.MediaType, bool) {
+	for cf, t := range contentTypes {
+		if t == ct {
+			return cf, true
+		}
+	}
+	return 0, false
+}
+
 type client struct {
 	client mux.Client
 	token  message.Token
+	accept string
 	logger logger.Logger
 }
 
-// NewClient instantiates a new Observer.
-func NewClient(mc mux.Client, token message.Token, l logger.Logger) Client {
+// NewClient instantiates a new Observer. The SenML messages are sent in the
+// accepted content type, or as published if the accept is empty.
+func NewClient(mc mux.Client, token message.Token, accept string, l logger.Logger) Client {
 	return &client{
 		client: mc,
 		token:  token,
+		accept: accept,
 		logger: l,
 	}
 }
@@ -57,19 +90,33 @@ func (c *client) Token() string {
 }
 
 func (c *client) SendMessage(msg messaging.Message) error {
+	payload, ct := msg.Payload, msg.ContentType
+	if _, ok := senml.Format(ct); ok && c.accept != "" && ct != c.accept {
+		p, err := senml.Convert(payload, ct, c.accept)
+		if err != nil {
+			c.logger.Warn(fmt.Sprintf("Can't convert message to %s: %s.", c.accept, err))
+		} else {
+			payload, ct = p, c.accept
+		}
+	}
+	cf, ok := ContentFormat(ct)
+	if !ok {
+		cf = message.TextPlain
+	}
+
 	m := message.Message{
 		Code:    codes.Content,
 		Token:   c.token,
 		Context: c.client.Context(),
-		Body:    bytes.NewReader(msg.Payload),
+		Body:    bytes.NewReader(payload),
 	}
 	var opts message.Options
 	var buff []byte
 
-	opts, n, err := opts.SetContentFormat(buff, message.TextPlain)
+	opts, n, err := opts.SetContentFormat(buff, cf)
 	if err == message.ErrTooSmall {
 		buff = append(buff, make([]byte, n)...)
-		opts, n, err = opts.SetContentFormat(buff, message.TextPlain)
+		opts, n, err = opts.SetContentFormat(buff, cf)
 	}
 	if err != nil {
 		c.logger.Error(fmt.Sprintf("Can't set content format: %s.", err))
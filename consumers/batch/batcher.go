@@ -0,0 +1,263 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package batch
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+	mfjson "github.com/mainflux/mainflux/pkg/transformers/json"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+)
+
+const (
+	defMaxPending = 10
+	defTimeout    = time.Second
+)
+
+// ErrClosed indicates that the batcher doesn't accept messages anymore.
+var ErrClosed = errors.New("batcher is closed")
+
+// Config defines the batching options.
+type Config struct {
+	// Size is the number of messages that triggers the batch flush.
+	Size int
+	// Timeout is the maximal time the batch waits for the flush.
+	Timeout time.Duration
+	// MaxRetries is the number of times the failed flush is retried.
+	MaxRetries int
+	// Backoff is the delay before the first retry, doubled on every retry.
+	Backoff time.Duration
+	// MaxPending is the number of full batches waiting for the flush,
+	// after which consuming blocks until the pending batches are written.
+	MaxPending int
+	// Async makes consuming return as soon as the messages are added to
+	// the batch, instead of waiting for the batch to be written. The write
+	// errors are only logged then, so it's meant for the brokers which
+	// hand the messages over one at a time and don't redeliver them.
+	Async bool
+}
+
+// Batcher is the consumer that accumulates the consumed messages, and writes
+// them using the decorated consumer in batches. Unless the batcher is
+// asynchronous, consuming blocks until the batch containing the messages is
+// written, and returns the write error, so the messages are acknowledged only
+// once they're persisted. Therefore, the synchronous batches fill only if the
+// messages are consumed concurrently. Either way, the batch is written once
+// it's full or once its timeout expires.
+type Batcher interface {
+	consumers.Consumer
+
+	// Close flushes the pending messages and stops the batcher.
+	Close() error
+}
+
+var _ Batcher = (*batcher)(nil)
+
+type batcher struct {
+	cfg      Config
+	consumer consumers.Consumer
+	logger   logger.Logger
+
+	mu      sync.Mutex
+	current batch
+	gen     uint64
+	closed  bool
+	batches chan batch
+	done    chan struct{}
+}
+
+// New returns the batcher which writes messages using the given consumer.
+// SenML messages and JSON messages of the same format are merged into the
+// single write, while the other messages are written as they come. The batch
+// timeout defaults to one second, since the batch that isn't full would never
+// be written otherwise.
+func New(consumer consumers.Consumer, cfg Config, logger logger.Logger) Batcher {
+	if cfg.Size < 1 {
+		cfg.Size = 1
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defTimeout
+	}
+	if cfg.MaxPending < 1 {
+		cfg.MaxPending = defMaxPending
+	}
+
+	b := &batcher{
+		cfg:      cfg,
+		consumer: consumer,
+		logger:   logger,
+		batches:  make(chan batch, cfg.MaxPending),
+		done:     make(chan struct{}),
+	}
+	go b.run()
+
+	return b
+}
+
+func (b *batcher) Consume(msgs interface{}) error {
+	switch msgs.(type) {
+	case []senml.Message, mfjson.Messages:
+	default:
+		return b.consumer.Consume(msgs)
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrClosed
+	}
+	if b.current.size == 0 {
+		b.current.flush = &flush{done: make(chan struct{})}
+		gen := b.gen
+		time.AfterFunc(b.cfg.Timeout, func() {
+			b.timeout(gen)
+		})
+	}
+	b.current.add(msgs)
+	f := b.current.flush
+	if b.current.size >= b.cfg.Size {
+		b.push()
+	}
+	b.mu.Unlock()
+
+	if b.cfg.Async {
+		return nil
+	}
+	<-f.done
+	return f.err
+}
+
+func (b *batcher) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.push()
+	close(b.batches)
+	b.mu.Unlock()
+
+	<-b.done
+	return nil
+}
+
+// timeout pushes the batch of the given generation, unless it has already
+// been pushed because it was full.
+func (b *batcher) timeout(gen uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed || gen != b.gen {
+		return
+	}
+	b.push()
+}
+
+// push hands the current batch over to the writing goroutine. It blocks while
+// there are too many pending batches, and it has to be called with the lock held,
+// which blocks the consumption as well.
+func (b *batcher) push() {
+	if b.current.size == 0 {
+		return
+	}
+	b.batches <- b.current
+	b.current = batch{}
+	b.gen++
+}
+
+func (b *batcher) run() {
+	for bt := range b.batches {
+		for _, msgs := range bt.messages() {
+			if err := b.write(msgs); err != nil {
+				b.logger.Error(fmt.Sprintf("Failed to write batch of %d messages: %s", Size(msgs), err))
+				// The messages written before the failure are written
+				// again once the whole batch is redelivered.
+				if bt.flush.err == nil {
+					bt.flush.err = err
+				}
+			}
+		}
+		close(bt.flush.done)
+	}
+	close(b.done)
+}
+
+func (b *batcher) write(msgs interface{}) error {
+	backoff := b.cfg.Backoff
+	for attempt := 0; ; attempt++ {
+		err := b.consumer.Consume(msgs)
+		if err == nil || attempt >= b.cfg.MaxRetries {
+			return err
+		}
+		b.logger.Warn(fmt.Sprintf("Failed to write batch of %d messages, retrying in %s: %s", Size(msgs), backoff, err))
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// Size returns the number of the messages consumed at once.
+func Size(msgs interface{}) int {
+	switch m := msgs.(type) {
+	case []senml.Message:
+		return len(m)
+	case mfjson.Messages:
+		return len(m.Data)
+	default:
+		return 1
+	}
+}
+
+// flush reports the result of the batch write to the consumers of its messages.
+type flush struct {
+	done chan struct{}
+	err  error
+}
+
+type batch struct {
+	size  int
+	senml []senml.Message
+	json  []mfjson.Messages
+	flush *flush
+}
+
+func (bt *batch) add(msgs interface{}) {
+	switch m := msgs.(type) {
+	case []senml.Message:
+		bt.senml = append(bt.senml, m...)
+	case mfjson.Messages:
+		bt.addJSON(m)
+	}
+	bt.size += Size(msgs)
+}
+
+func (bt *batch) addJSON(msgs mfjson.Messages) {
+	for i := range bt.json {
+		if bt.json[i].Format == msgs.Format {
+			bt.json[i].Data = append(bt.json[i].Data, msgs.Data...)
+			return
+		}
+	}
+	bt.json = append(bt.json, mfjson.Messages{
+		Format: msgs.Format,
+		Data:   append([]mfjson.Message{}, msgs.Data...),
+	})
+}
+
+// messages returns the batch content as the messages consumed at once.
+func (bt batch) messages() []interface{} {
+	var ret []interface{}
+	if len(bt.senml) > 0 {
+		ret = append(ret, bt.senml)
+	}
+	for _, msgs := range bt.json {
+		ret = append(ret, msgs)
+	}
+	return ret
+}
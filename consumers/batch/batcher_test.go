@@ -0,0 +1,270 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package batch_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/consumers/batch"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+	mfjson "github.com/mainflux/mainflux/pkg/transformers/json"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errWrite = errors.New("write failed")
+
+// consumer records the consumed messages, failing the given number of the
+// first writes.
+type consumer struct {
+	mu       sync.Mutex
+	fails    int
+	attempts int
+	writes   []interface{}
+}
+
+func (c *consumer) Consume(msgs interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.attempts++
+	if c.attempts <= c.fails {
+		return errWrite
+	}
+	c.writes = append(c.writes, msgs)
+	return nil
+}
+
+func (c *consumer) written() []interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]interface{}{}, c.writes...)
+}
+
+func senmlMessages(n int) []senml.Message {
+	var msgs []senml.Message
+	for i := 0; i < n; i++ {
+		msgs = append(msgs, senml.Message{Channel: "channel", Name: "temperature", Time: float64(i)})
+	}
+	return msgs
+}
+
+// consume consumes the given messages concurrently, since consuming blocks
+// until the messages are written, and returns the consuming errors.
+func consume(b batch.Batcher, msgs ...interface{}) []error {
+	errs := make([]error, len(msgs))
+	var wg sync.WaitGroup
+	for i, m := range msgs {
+		wg.Add(1)
+		go func(i int, m interface{}) {
+			defer wg.Done()
+			errs[i] = b.Consume(m)
+		}(i, m)
+	}
+	wg.Wait()
+	return errs
+}
+
+func single(msgs []senml.Message) []interface{} {
+	var ret []interface{}
+	for _, m := range msgs {
+		ret = append(ret, []senml.Message{m})
+	}
+	return ret
+}
+
+func TestConsumeSize(t *testing.T) {
+	c := &consumer{}
+	b := batch.New(c, batch.Config{Size: 5, Timeout: time.Hour}, logger.NewMock())
+
+	msgs := senmlMessages(6)
+	done := make(chan []error)
+	go func() {
+		done <- consume(b, single(msgs[:4])...)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, c.written(), "expected no writes before the batch is full")
+
+	err := b.Consume(msgs[4:])
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	for _, err := range <-done {
+		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	writes := c.written()
+	require.Len(t, writes, 1, "expected single write")
+	assert.ElementsMatch(t, msgs, writes[0], fmt.Sprintf("expected written messages %v got %v", msgs, writes[0]))
+
+	err = b.Close()
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+}
+
+func TestConsumeTimeout(t *testing.T) {
+	c := &consumer{}
+	b := batch.New(c, batch.Config{Size: 100, Timeout: 10 * time.Millisecond}, logger.NewMock())
+	defer b.Close()
+
+	msgs := senmlMessages(3)
+	for _, err := range consume(b, single(msgs)...) {
+		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	var written []senml.Message
+	for _, w := range c.written() {
+		written = append(written, w.([]senml.Message)...)
+	}
+	assert.ElementsMatch(t, msgs, written, fmt.Sprintf("expected written messages %v got %v", msgs, written))
+}
+
+func TestConsumeAsync(t *testing.T) {
+	cases := []struct {
+		desc   string
+		cfg    batch.Config
+		msgs   int
+		writes int
+	}{
+		{
+			desc:   "write full batches of sequentially consumed messages",
+			cfg:    batch.Config{Size: 2, Timeout: time.Hour, Async: true},
+			msgs:   4,
+			writes: 2,
+		},
+		{
+			desc:   "write batch of sequentially consumed messages on default timeout",
+			cfg:    batch.Config{Size: 100, Async: true},
+			msgs:   3,
+			writes: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		c := &consumer{}
+		b := batch.New(c, tc.cfg, logger.NewMock())
+
+		msgs := senmlMessages(tc.msgs)
+		for _, m := range single(msgs) {
+			err := b.Consume(m)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		}
+
+		deadline := time.Now().Add(5 * time.Second)
+		for len(c.written()) < tc.writes && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		var written []senml.Message
+		for _, w := range c.written() {
+			written = append(written, w.([]senml.Message)...)
+		}
+		assert.Len(t, c.written(), tc.writes, fmt.Sprintf("%s: expected %d writes", tc.desc, tc.writes))
+		assert.ElementsMatch(t, msgs, written, fmt.Sprintf("%s: expected written messages %v got %v", tc.desc, msgs, written))
+
+		err := b.Close()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+	}
+}
+
+func TestConsumeJSON(t *testing.T) {
+	c := &consumer{}
+	b := batch.New(c, batch.Config{Size: 3, Timeout: time.Hour}, logger.NewMock())
+	defer b.Close()
+
+	first := mfjson.Message{Channel: "channel", Created: 1}
+	second := mfjson.Message{Channel: "channel", Created: 2}
+	other := mfjson.Message{Channel: "channel", Created: 3}
+	msgs := []interface{}{
+		mfjson.Messages{Format: "first", Data: []mfjson.Message{first}},
+		mfjson.Messages{Format: "other", Data: []mfjson.Message{other}},
+		mfjson.Messages{Format: "first", Data: []mfjson.Message{second}},
+	}
+	for _, err := range consume(b, msgs...) {
+		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	expected := map[string][]mfjson.Message{
+		"first": {first, second},
+		"other": {other},
+	}
+	writes := c.written()
+	require.Len(t, writes, len(expected), fmt.Sprintf("expected %d writes", len(expected)))
+	for _, w := range writes {
+		m := w.(mfjson.Messages)
+		assert.ElementsMatch(t, expected[m.Format], m.Data, fmt.Sprintf("expected %s messages %v got %v", m.Format, expected[m.Format], m.Data))
+	}
+}
+
+func TestConsumeRetries(t *testing.T) {
+	cases := []struct {
+		desc   string
+		fails  int
+		writes int
+		err    error
+	}{
+		{
+			desc:   "write batch after retries",
+			fails:  2,
+			writes: 1,
+			err:    nil,
+		},
+		{
+			desc:   "return error after max retries",
+			fails:  3,
+			writes: 0,
+			err:    errWrite,
+		},
+	}
+
+	for _, tc := range cases {
+		c := &consumer{fails: tc.fails}
+		b := batch.New(c, batch.Config{Size: 2, MaxRetries: 2, Backoff: time.Millisecond}, logger.NewMock())
+
+		for _, err := range consume(b, single(senmlMessages(2))...) {
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s", tc.desc, tc.err, err))
+		}
+
+		err := b.Close()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, 3, c.attempts, fmt.Sprintf("%s: expected 3 attempts got %d", tc.desc, c.attempts))
+		assert.Len(t, c.written(), tc.writes, fmt.Sprintf("%s: expected %d writes", tc.desc, tc.writes))
+	}
+}
+
+func TestClose(t *testing.T) {
+	c := &consumer{}
+	b := batch.New(c, batch.Config{Size: 100, Timeout: time.Hour}, logger.NewMock())
+
+	msgs := senmlMessages(2)
+	done := make(chan error)
+	go func() {
+		done <- b.Consume(msgs)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	err := b.Close()
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = <-done
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	writes := c.written()
+	assert.Equal(t, []interface{}{msgs}, writes, fmt.Sprintf("expected writes %v got %v", msgs, writes))
+
+	err = b.Consume(msgs)
+	assert.True(t, errors.Contains(err, batch.ErrClosed), fmt.Sprintf("expected error %s got %s", batch.ErrClosed, err))
+}
+
+func TestConsumeOther(t *testing.T) {
+	c := &consumer{}
+	b := batch.New(c, batch.Config{Size: 100, Timeout: time.Hour}, logger.NewMock())
+	defer b.Close()
+
+	msg := "message"
+	err := b.Consume(msg)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	writes := c.written()
+	assert.Equal(t, []interface{}{msg}, writes, fmt.Sprintf("expected writes %v got %v", msg, writes))
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package batch provides the consumer decorator that writes the consumed
+// messages in batches.
+package batch
@@ -8,38 +8,49 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/pelletier/go-toml"
 
+	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/pkg/messaging"
 	pubsub "github.com/mainflux/mainflux/pkg/messaging/nats"
 	"github.com/mainflux/mainflux/pkg/transformers"
 	"github.com/mainflux/mainflux/pkg/transformers/json"
+	"github.com/mainflux/mainflux/pkg/transformers/pipeline"
 	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/mainflux/mainflux/pkg/transformers/sparkplug"
 )
 
 const (
 	defContentType = "application/senml+json"
 	defFormat      = "senml"
+	defMetadataTTL = "1m"
 )
 
 var (
 	errOpenConfFile  = errors.New("unable to open configuration file")
 	errParseConfFile = errors.New("unable to parse configuration file")
+	errMetadataTTL   = errors.New("invalid metadata TTL")
 )
 
 // Start method starts consuming messages received from NATS.
-// This method transforms messages to SenML format before
-// using MessageRepository to store them.
-func Start(sub messaging.Subscriber, consumer Consumer, configPath string, logger logger.Logger) error {
+// This method transforms messages to SenML format and applies
+// the configured pipeline rules before using MessageRepository
+// to store them. Things client is used to retrieve the thing
+// metadata for the enrich rules and may be nil if there are none.
+func Start(sub messaging.Subscriber, consumer Consumer, configPath string, things mainflux.ThingsServiceClient, logger logger.Logger) error {
 	cfg, err := loadConfig(configPath)
 	if err != nil {
 		logger.Warn(fmt.Sprintf("Failed to load consumer config: %s", err))
 	}
 
-	transformer := makeTransformer(cfg.TransformerCfg, logger)
+	transformer, err := makePipeline(makeTransformer(cfg.TransformerCfg, logger), cfg.PipelineCfg, things, logger)
+	if err != nil {
+		return err
+	}
 
 	for _, subject := range cfg.SubscriberCfg.Subjects {
 		if err := sub.Subscribe(subject, handler(transformer, consumer)); err != nil {
@@ -73,9 +84,15 @@ type transformerConfig struct {
 	TimeFields  []json.TimeField `toml:"time_fields"`
 }
 
+type pipelineConfig struct {
+	MetadataTTL string          `toml:"metadata_ttl"`
+	Rules       []pipeline.Rule `toml:"rules"`
+}
+
 type config struct {
 	SubscriberCfg  subscriberConfig  `toml:"subscriber"`
 	TransformerCfg transformerConfig `toml:"transformer"`
+	PipelineCfg    pipelineConfig    `toml:"pipeline"`
 }
 
 func loadConfig(configPath string) (config, error) {
@@ -87,6 +104,9 @@ func loadConfig(configPath string) (config, error) {
 			Format:      defFormat,
 			ContentType: defContentType,
 		},
+		PipelineCfg: pipelineConfig{
+			MetadataTTL: defMetadataTTL,
+		},
 	}
 
 	data, err := ioutil.ReadFile(configPath)
@@ -105,7 +125,7 @@ func makeTransformer(cfg transformerConfig, logger logger.Logger) transformers.T
 	switch strings.ToUpper(cfg.Format) {
 	case "SENML":
 		logger.Info("Using SenML transformer")
-		return senml.New(cfg.ContentType)
+		return sparkplug.New(senml.New(cfg.ContentType))
 	case "JSON":
 		logger.Info("Using JSON transformer")
 		return json.New(cfg.TimeFields)
@@ -115,3 +135,21 @@ func makeTransformer(cfg transformerConfig, logger logger.Logger) transformers.T
 		return nil
 	}
 }
+
+func makePipeline(t transformers.Transformer, cfg pipelineConfig, things mainflux.ThingsServiceClient, logger logger.Logger) (transformers.Transformer, error) {
+	if len(cfg.Rules) == 0 {
+		return t, nil
+	}
+
+	var md pipeline.Metadata
+	if things != nil {
+		ttl, err := time.ParseDuration(cfg.MetadataTTL)
+		if err != nil {
+			return nil, errors.Wrap(errMetadataTTL, err)
+		}
+		md = pipeline.NewThingsMetadata(things, ttl)
+	}
+
+	logger.Info(fmt.Sprintf("Using transformer pipeline with %d rules", len(cfg.Rules)))
+	return pipeline.New(t, cfg.Rules, md)
+}
@@ -19,6 +19,7 @@ func createSubscriptionEndpoint(svc notifiers.Service) endpoint.Endpoint {
 		sub := notifiers.Subscription{
 			Contact: req.Contact,
 			Topic:   req.Topic,
+			Secret:  req.Secret,
 		}
 		id, err := svc.CreateSubscription(ctx, req.token, sub)
 		if err != nil {
@@ -78,6 +78,19 @@ func (lm *loggingMiddleware) RemoveSubscription(ctx context.Context, token, id s
 	return lm.svc.RemoveSubscription(ctx, token, id)
 }
 
+func (lm *loggingMiddleware) RemoveChannelSubscriptions(ctx context.Context, chanID string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_channel_subscriptions for channel %s took %s to complete", chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveChannelSubscriptions(ctx, chanID)
+}
+
 func (lm *loggingMiddleware) Consume(msg interface{}) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method consume took %s to complete", time.Since(begin))
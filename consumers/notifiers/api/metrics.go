@@ -66,6 +66,15 @@ func (ms *metricsMiddleware) RemoveSubscription(ctx context.Context, token, id s
 	return ms.svc.RemoveSubscription(ctx, token, id)
 }
 
+func (ms *metricsMiddleware) RemoveChannelSubscriptions(ctx context.Context, chanID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_channel_subscriptions").Add(1)
+		ms.latency.With("method", "remove_channel_subscriptions").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveChannelSubscriptions(ctx, chanID)
+}
+
 func (ms *metricsMiddleware) Consume(msg interface{}) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "consume").Add(1)
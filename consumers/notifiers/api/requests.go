@@ -16,6 +16,7 @@ type createSubReq struct {
 	token   string
 	Topic   string `json:"topic,omitempty"`
 	Contact string `json:"contact,omitempty"`
+	Secret  string `json:"secret,omitempty"`
 }
 
 func (req createSubReq) validate() error {
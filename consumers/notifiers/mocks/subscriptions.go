@@ -6,6 +6,7 @@ package mocks
 import (
 	"context"
 	"sort"
+	"strings"
 	"sync"
 
 	notifiers "github.com/mainflux/mainflux/consumers/notifiers"
@@ -126,3 +127,14 @@ func (srm *subRepoMock) Remove(_ context.Context, id string) error {
 	delete(srm.subs, id)
 	return nil
 }
+
+func (srm *subRepoMock) RemoveByChannel(_ context.Context, chanID string) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+	for id, sub := range srm.subs {
+		if sub.Topic == chanID || strings.HasPrefix(sub.Topic, chanID+".") {
+			delete(srm.subs, id)
+		}
+	}
+	return nil
+}
@@ -18,3 +18,11 @@ type Notifier interface {
 	// received message to the provided list of receivers.
 	Notify(from string, to []string, msg messaging.Message) error
 }
+
+// SubscriptionNotifier represents the notifier that sends the notification
+// on behalf of each subscription, rather than to the list of receivers.
+type SubscriptionNotifier interface {
+	// NotifySubscriptions method is used to send notification for the
+	// received message to the contacts of the provided subscriptions.
+	NotifySubscriptions(from string, subs []Subscription, msg messaging.Message) error
+}
@@ -0,0 +1,183 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/consumers/notifiers/webhook"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var _ webhook.DeliveryRepository = (*deliveriesRepo)(nil)
+
+type deliveriesRepo struct {
+	db Database
+}
+
+// NewDeliveryRepository instantiates a PostgreSQL implementation of webhook
+// delivery repository.
+func NewDeliveryRepository(db Database) webhook.DeliveryRepository {
+	return &deliveriesRepo{
+		db: db,
+	}
+}
+
+func (repo deliveriesRepo) Save(ctx context.Context, d webhook.Delivery) error {
+	q := `INSERT INTO deliveries (id, owner_id, contact, channel, subtopic, publisher, status, attempts, status_code, error, body, signature, created, updated)
+		  VALUES (:id, :owner_id, :contact, :channel, :subtopic, :publisher, :status, :attempts, :status_code, :error, :body, :signature, :created, :updated)`
+
+	if _, err := repo.db.NamedExecContext(ctx, q, toDBDelivery(d)); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errDuplicate {
+			return errors.Wrap(errors.ErrConflict, err)
+		}
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (repo deliveriesRepo) Update(ctx context.Context, d webhook.Delivery) error {
+	q := `UPDATE deliveries SET status = :status, attempts = :attempts, status_code = :status_code, error = :error,
+		  body = :body, signature = :signature, updated = :updated WHERE id = :id`
+
+	res, err := repo.db.NamedExecContext(ctx, q, toDBDelivery(d))
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	if cnt == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (repo deliveriesRepo) RetrieveAll(ctx context.Context, pm webhook.PageMetadata) (webhook.DeliveriesPage, error) {
+	condition := `contact = :contact`
+	if pm.Owner != "" {
+		condition = fmt.Sprintf("%s AND owner_id = :owner", condition)
+	}
+	q := fmt.Sprintf(`SELECT id, owner_id, contact, channel, subtopic, publisher, status, attempts, status_code, error, created, updated
+		  FROM deliveries WHERE %s ORDER BY created DESC, id DESC LIMIT :limit OFFSET :offset`, condition)
+	params := map[string]interface{}{
+		"contact": pm.Contact,
+		"owner":   pm.Owner,
+		"limit":   pm.Limit,
+		"offset":  pm.Offset,
+	}
+
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return webhook.DeliveriesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	items := []webhook.Delivery{}
+	for rows.Next() {
+		dbd := dbDelivery{}
+		if err := rows.StructScan(&dbd); err != nil {
+			return webhook.DeliveriesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		items = append(items, fromDBDelivery(dbd))
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM deliveries WHERE %s`, condition)
+	total, err := total(ctx, repo.db, cq, params)
+	if err != nil {
+		return webhook.DeliveriesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	page := webhook.DeliveriesPage{
+		PageMetadata: pm,
+		Deliveries:   items,
+	}
+	page.Total = uint64(total)
+
+	return page, nil
+}
+
+func (repo deliveriesRepo) RetrievePending(ctx context.Context) ([]webhook.Delivery, error) {
+	q := `SELECT id, owner_id, contact, channel, subtopic, publisher, status, attempts, status_code, error, body, signature, created, updated
+		  FROM deliveries WHERE status = :status ORDER BY created`
+
+	rows, err := repo.db.NamedQueryContext(ctx, q, map[string]interface{}{"status": webhook.Pending})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	items := []webhook.Delivery{}
+	for rows.Next() {
+		dbd := dbDelivery{}
+		if err := rows.StructScan(&dbd); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		items = append(items, fromDBDelivery(dbd))
+	}
+
+	return items, nil
+}
+
+type dbDelivery struct {
+	ID         string    `db:"id"`
+	Owner      string    `db:"owner_id"`
+	Contact    string    `db:"contact"`
+	Channel    string    `db:"channel"`
+	Subtopic   string    `db:"subtopic"`
+	Publisher  string    `db:"publisher"`
+	Status     string    `db:"status"`
+	Attempts   int       `db:"attempts"`
+	StatusCode int       `db:"status_code"`
+	Error      string    `db:"error"`
+	Body       []byte    `db:"body"`
+	Signature  string    `db:"signature"`
+	Created    time.Time `db:"created"`
+	Updated    time.Time `db:"updated"`
+}
+
+func toDBDelivery(d webhook.Delivery) dbDelivery {
+	return dbDelivery{
+		ID:         d.ID,
+		Owner:      d.Owner,
+		Contact:    d.Contact,
+		Channel:    d.Channel,
+		Subtopic:   d.Subtopic,
+		Publisher:  d.Publisher,
+		Status:     d.Status,
+		Attempts:   d.Attempts,
+		StatusCode: d.StatusCode,
+		Error:      d.Error,
+		Body:       d.Body,
+		Signature:  d.Signature,
+		Created:    d.Created,
+		Updated:    d.Updated,
+	}
+}
+
+func fromDBDelivery(d dbDelivery) webhook.Delivery {
+	return webhook.Delivery{
+		ID:         d.ID,
+		Owner:      d.Owner,
+		Contact:    d.Contact,
+		Channel:    d.Channel,
+		Subtopic:   d.Subtopic,
+		Publisher:  d.Publisher,
+		Status:     d.Status,
+		Attempts:   d.Attempts,
+		StatusCode: d.StatusCode,
+		Error:      d.Error,
+		Body:       d.Body,
+		Signature:  d.Signature,
+		Created:    d.Created,
+		Updated:    d.Updated,
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/consumers/notifiers/postgres"
+	"github.com/mainflux/mainflux/consumers/notifiers/webhook"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	webhookURL    = "https://example.com/webhook"
+	deliveryOwner = "owner@example.com"
+	numDeliveries = 10
+)
+
+func newDelivery(t *testing.T) webhook.Delivery {
+	id, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now().UTC().Round(time.Millisecond)
+	return webhook.Delivery{
+		ID:        id,
+		Owner:     deliveryOwner,
+		Contact:   webhookURL,
+		Channel:   "channel",
+		Publisher: "publisher",
+		Status:    webhook.Pending,
+		Created:   now,
+		Updated:   now,
+	}
+}
+
+func TestSaveDelivery(t *testing.T) {
+	repo := postgres.NewDeliveryRepository(postgres.NewDatabase(db))
+
+	d := newDelivery(t)
+
+	cases := []struct {
+		desc     string
+		delivery webhook.Delivery
+		err      error
+	}{
+		{
+			desc:     "save delivery",
+			delivery: d,
+			err:      nil,
+		},
+		{
+			desc:     "save duplicate delivery",
+			delivery: d,
+			err:      errors.ErrConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.delivery)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestUpdateDelivery(t *testing.T) {
+	repo := postgres.NewDeliveryRepository(postgres.NewDatabase(db))
+
+	d := newDelivery(t)
+	err := repo.Save(context.Background(), d)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	delivered := d
+	delivered.Status = webhook.Delivered
+	delivered.Attempts = 2
+	delivered.StatusCode = 200
+
+	missing := delivered
+	missing.ID = "non-existing"
+
+	cases := []struct {
+		desc     string
+		delivery webhook.Delivery
+		err      error
+	}{
+		{
+			desc:     "update delivery",
+			delivery: delivered,
+			err:      nil,
+		},
+		{
+			desc:     "update non-existing delivery",
+			delivery: missing,
+			err:      errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Update(context.Background(), tc.delivery)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestRetrieveAllDeliveries(t *testing.T) {
+	repo := postgres.NewDeliveryRepository(postgres.NewDatabase(db))
+
+	contact := "https://example.com/list"
+	for i := 0; i < numDeliveries; i++ {
+		d := newDelivery(t)
+		d.Contact = contact
+		if i%2 == 0 {
+			d.Owner = "other@example.com"
+		}
+		err := repo.Save(context.Background(), d)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc  string
+		pm    webhook.PageMetadata
+		size  int
+		total uint64
+	}{
+		{
+			desc:  "retrieve all deliveries",
+			pm:    webhook.PageMetadata{Offset: 0, Limit: numDeliveries, Contact: contact},
+			size:  numDeliveries,
+			total: numDeliveries,
+		},
+		{
+			desc:  "retrieve a page of deliveries",
+			pm:    webhook.PageMetadata{Offset: 8, Limit: 5, Contact: contact},
+			size:  2,
+			total: numDeliveries,
+		},
+		{
+			desc:  "retrieve deliveries of the owner",
+			pm:    webhook.PageMetadata{Offset: 0, Limit: numDeliveries, Contact: contact, Owner: deliveryOwner},
+			size:  numDeliveries / 2,
+			total: numDeliveries / 2,
+		},
+		{
+			desc:  "retrieve deliveries to unknown contact",
+			pm:    webhook.PageMetadata{Offset: 0, Limit: numDeliveries, Contact: "https://unknown.com"},
+			size:  0,
+			total: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := repo.RetrieveAll(context.Background(), tc.pm)
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.size, len(page.Deliveries), fmt.Sprintf("%s: expected %d deliveries got %d\n", tc.desc, tc.size, len(page.Deliveries)))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.total, page.Total))
+	}
+}
+
+func TestRetrievePendingDeliveries(t *testing.T) {
+	repo := postgres.NewDeliveryRepository(postgres.NewDatabase(db))
+
+	pending := newDelivery(t)
+	pending.Contact = "https://example.com/pending"
+	pending.Attempts = 1
+	pending.Body = []byte(`{"channel":"channel"}`)
+	pending.Signature = "sha256=signature"
+	err := repo.Save(context.Background(), pending)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	delivered := newDelivery(t)
+	delivered.Contact = pending.Contact
+	err = repo.Save(context.Background(), delivered)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	delivered.Status = webhook.Delivered
+	err = repo.Update(context.Background(), delivered)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	ds, err := repo.RetrievePending(context.Background())
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	found := map[string]webhook.Delivery{}
+	for _, d := range ds {
+		assert.Equal(t, webhook.Pending, d.Status, fmt.Sprintf("expected status %s got %s\n", webhook.Pending, d.Status))
+		found[d.ID] = d
+	}
+	assert.Equal(t, pending, found[pending.ID], fmt.Sprintf("expected pending delivery %v got %v\n", pending, found[pending.ID]))
+	_, ok := found[delivered.ID]
+	assert.False(t, ok, "expected delivered delivery not to be retrieved")
+}
@@ -60,6 +60,43 @@ func migrateDB(db *sqlx.DB) error {
 					"DROP TABLE IF EXISTS subscriptions",
 				},
 			},
+			{
+				Id: "subscriptions_2",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS deliveries (
+                        id          VARCHAR(254) PRIMARY KEY,
+                        contact     VARCHAR(254) NOT NULL,
+                        channel     VARCHAR(254),
+                        subtopic    TEXT,
+                        publisher   VARCHAR(254),
+                        status      VARCHAR(16) NOT NULL,
+                        attempts    INTEGER NOT NULL DEFAULT 0,
+                        status_code INTEGER NOT NULL DEFAULT 0,
+                        error       TEXT,
+                        created     TIMESTAMP,
+                        updated     TIMESTAMP
+                    )`,
+					`CREATE INDEX IF NOT EXISTS idx_deliveries_contact ON deliveries (contact, created)`,
+				},
+				Down: []string{
+					"DROP TABLE IF EXISTS deliveries",
+				},
+			},
+			{
+				Id: "subscriptions_3",
+				Up: []string{
+					`ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS secret VARCHAR(254) NOT NULL DEFAULT ''`,
+					`ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS owner_id VARCHAR(254) NOT NULL DEFAULT '',
+                        ADD COLUMN IF NOT EXISTS body BYTEA,
+                        ADD COLUMN IF NOT EXISTS signature VARCHAR(254) NOT NULL DEFAULT ''`,
+					`CREATE INDEX IF NOT EXISTS idx_deliveries_pending ON deliveries (created) WHERE status = 'pending'`,
+				},
+				Down: []string{
+					`DROP INDEX IF EXISTS idx_deliveries_pending`,
+					`ALTER TABLE deliveries DROP COLUMN IF EXISTS owner_id, DROP COLUMN IF EXISTS body, DROP COLUMN IF EXISTS signature`,
+					`ALTER TABLE subscriptions DROP COLUMN IF EXISTS secret`,
+				},
+			},
 		},
 	}
 
@@ -30,13 +30,14 @@ func New(db Database) notifiers.SubscriptionsRepository {
 }
 
 func (repo subscriptionsRepo) Save(ctx context.Context, sub notifiers.Subscription) (string, error) {
-	q := `INSERT INTO subscriptions (id, owner_id, contact, topic) VALUES (:id, :owner_id, :contact, :topic) RETURNING id`
+	q := `INSERT INTO subscriptions (id, owner_id, contact, topic, secret) VALUES (:id, :owner_id, :contact, :topic, :secret) RETURNING id`
 
 	dbSub := dbSubscription{
 		ID:      sub.ID,
 		OwnerID: sub.OwnerID,
 		Contact: sub.Contact,
 		Topic:   sub.Topic,
+		Secret:  sub.Secret,
 	}
 
 	row, err := repo.db.NamedQueryContext(ctx, q, dbSub)
@@ -52,7 +53,7 @@ func (repo subscriptionsRepo) Save(ctx context.Context, sub notifiers.Subscripti
 }
 
 func (repo subscriptionsRepo) Retrieve(ctx context.Context, id string) (notifiers.Subscription, error) {
-	q := `SELECT id, owner_id, contact, topic FROM subscriptions WHERE id = $1`
+	q := `SELECT id, owner_id, contact, topic, secret FROM subscriptions WHERE id = $1`
 	sub := dbSubscription{}
 	if err := repo.db.QueryRowxContext(ctx, q, id).StructScan(&sub); err != nil {
 		if err == sql.ErrNoRows {
@@ -65,7 +66,7 @@ func (repo subscriptionsRepo) Retrieve(ctx context.Context, id string) (notifier
 }
 
 func (repo subscriptionsRepo) RetrieveAll(ctx context.Context, pm notifiers.PageMetadata) (notifiers.Page, error) {
-	q := `SELECT id, owner_id, contact, topic FROM subscriptions`
+	q := `SELECT id, owner_id, contact, topic, secret FROM subscriptions`
 	args := make(map[string]interface{})
 	if pm.Topic != "" {
 		args["topic"] = pm.Topic
@@ -132,6 +133,15 @@ func (repo subscriptionsRepo) Remove(ctx context.Context, id string) error {
 	return nil
 }
 
+func (repo subscriptionsRepo) RemoveByChannel(ctx context.Context, chanID string) error {
+	q := `DELETE FROM subscriptions WHERE topic = :channel OR topic LIKE :channel || '.%'`
+
+	if _, err := repo.db.NamedExecContext(ctx, q, map[string]interface{}{"channel": chanID}); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	return nil
+}
+
 func total(ctx context.Context, db Database, query string, params interface{}) (uint, error) {
 	rows, err := db.NamedQueryContext(ctx, query, params)
 	if err != nil {
@@ -152,6 +162,7 @@ type dbSubscription struct {
 	OwnerID string `db:"owner_id"`
 	Contact string `db:"contact"`
 	Topic   string `db:"topic"`
+	Secret  string `db:"secret"`
 }
 
 func fromDBSub(sub dbSubscription) notifiers.Subscription {
@@ -160,5 +171,6 @@ func fromDBSub(sub dbSubscription) notifiers.Subscription {
 		OwnerID: sub.OwnerID,
 		Contact: sub.Contact,
 		Topic:   sub.Topic,
+		Secret:  sub.Secret,
 	}
 }
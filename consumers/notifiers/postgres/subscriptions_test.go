@@ -259,3 +259,38 @@ func TestRemove(t *testing.T) {
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
+
+func TestRemoveByChannel(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.New(dbMiddleware)
+
+	chanID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got an error creating id: %s", err))
+	topics := map[string]bool{
+		chanID:                    true,
+		chanID + ".subtopic":      true,
+		chanID + "other.subtopic": false,
+	}
+	ids := map[string]string{}
+	for topic := range topics {
+		id, err := idProvider.ID()
+		require.Nil(t, err, fmt.Sprintf("got an error creating id: %s", err))
+		sub := notifiers.Subscription{
+			OwnerID: id,
+			ID:      id,
+			Contact: owner,
+			Topic:   topic,
+		}
+		_, err = repo.Save(context.Background(), sub)
+		require.Nil(t, err, fmt.Sprintf("creating subscription must not fail: %s", err))
+		ids[topic] = id
+	}
+
+	err = repo.RemoveByChannel(context.Background(), chanID)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	for topic, removed := range topics {
+		_, err := repo.Retrieve(context.Background(), ids[topic])
+		assert.Equal(t, removed, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("topic %s: expected removed %t got error %s\n", topic, removed, err))
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	notifiers "github.com/mainflux/mainflux/consumers/notifiers"
+	"github.com/mainflux/mainflux/logger"
+)
+
+const (
+	stream = "mainflux.things"
+
+	channelPrefix = "channel."
+	channelPurge  = channelPrefix + "purge"
+
+	exists = "BUSYGROUP Consumer Group name already exists"
+
+	minBackoff = 100 * time.Millisecond
+	maxBackoff = 10 * time.Second
+)
+
+// Subscriber represents event source for things and channels provisioning.
+type Subscriber interface {
+	// Subscribes to given subject and receives events.
+	Subscribe(context.Context, string) error
+}
+
+type eventStore struct {
+	svc      notifiers.Service
+	client   *redis.Client
+	group    string
+	consumer string
+	logger   logger.Logger
+}
+
+// NewEventStore returns new event store instance. Every notifier service keeps
+// its own subscriptions, so each of them reads the events using its own group.
+func NewEventStore(svc notifiers.Service, client *redis.Client, group, consumer string, log logger.Logger) Subscriber {
+	return eventStore{
+		svc:      svc,
+		client:   client,
+		group:    group,
+		consumer: consumer,
+		logger:   log,
+	}
+}
+
+func (es eventStore) Subscribe(ctx context.Context, subject string) error {
+	err := es.client.XGroupCreateMkStream(ctx, stream, es.group, "$").Err()
+	if err != nil && err.Error() != exists {
+		return err
+	}
+
+	backoff := minBackoff
+	for {
+		streams, err := es.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    es.group,
+			Consumer: es.consumer,
+			Streams:  []string{stream, ">"},
+			Count:    100,
+		}).Result()
+		if err != nil && err != redis.Nil {
+			es.logger.Warn(fmt.Sprintf("Failed to read events, retrying in %s: %s", backoff, err))
+			time.Sleep(backoff)
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+		backoff = minBackoff
+		if len(streams) == 0 {
+			continue
+		}
+
+		for _, msg := range streams[0].Messages {
+			event := msg.Values
+
+			var err error
+			switch event["operation"] {
+			case channelPurge:
+				pce := decodePurgeChannel(event)
+				err = es.svc.RemoveChannelSubscriptions(ctx, pce.id)
+			}
+			if err != nil {
+				es.logger.Warn(fmt.Sprintf("Failed to handle event sourcing: %s", err.Error()))
+				break
+			}
+			es.client.XAck(ctx, stream, es.group, msg.ID)
+		}
+	}
+}
+
+func decodePurgeChannel(event map[string]interface{}) purgeEvent {
+	return purgeEvent{
+		id: read(event, "id", ""),
+	}
+}
+
+func read(event map[string]interface{}, key, def string) string {
+	val, ok := event[key].(string)
+	if !ok {
+		return def
+	}
+
+	return val
+}
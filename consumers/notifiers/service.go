@@ -33,6 +33,11 @@ type Service interface {
 	// RemoveSubscription removes the subscription having the provided identifier.
 	RemoveSubscription(ctx context.Context, token, id string) error
 
+	// RemoveChannelSubscriptions removes all the subscriptions to the channel
+	// with the given ID, regardless of their owner. It's intended for the
+	// internal use by the event consumer once the channel is purged.
+	RemoveChannelSubscriptions(ctx context.Context, chanID string) error
+
 	consumers.Consumer
 }
 
@@ -95,6 +100,10 @@ func (ns *notifierService) RemoveSubscription(ctx context.Context, token, id str
 	return ns.subs.Remove(ctx, id)
 }
 
+func (ns *notifierService) RemoveChannelSubscriptions(ctx context.Context, chanID string) error {
+	return ns.subs.RemoveByChannel(ctx, chanID)
+}
+
 func (ns *notifierService) Consume(message interface{}) error {
 	msg, ok := message.(messaging.Message)
 	if !ok {
@@ -114,6 +123,16 @@ func (ns *notifierService) Consume(message interface{}) error {
 		return err
 	}
 
+	if sn, ok := ns.notifier.(SubscriptionNotifier); ok {
+		if len(page.Subscriptions) == 0 {
+			return nil
+		}
+		if err := sn.NotifySubscriptions(ns.from, page.Subscriptions, msg); err != nil {
+			return errors.Wrap(ErrNotify, err)
+		}
+		return nil
+	}
+
 	var to []string
 	for _, sub := range page.Subscriptions {
 		to = append(to, sub.Contact)
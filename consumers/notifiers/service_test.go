@@ -273,6 +273,50 @@ func TestRemoveSubscription(t *testing.T) {
 	}
 }
 
+func TestRemoveChannelSubscriptions(t *testing.T) {
+	svc := newService()
+	subs := []notifiers.Subscription{
+		{Contact: exampleUser1, Topic: "purged"},
+		{Contact: exampleUser1, Topic: "purged.subtopic"},
+		{Contact: exampleUser2, Topic: "purged.subtopic"},
+		{Contact: exampleUser1, Topic: "purgedother"},
+	}
+	for _, sub := range subs {
+		_, err := svc.CreateSubscription(context.Background(), exampleUser1, sub)
+		require.Nil(t, err, fmt.Sprintf("unexpected error creating subscription: %s", err))
+	}
+
+	err := svc.RemoveChannelSubscriptions(context.Background(), "purged")
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		topic string
+		total uint
+	}{
+		{
+			desc:  "list subscriptions to purged channel",
+			topic: "purged",
+			total: 0,
+		},
+		{
+			desc:  "list subscriptions to purged channel subtopic",
+			topic: "purged.subtopic",
+			total: 0,
+		},
+		{
+			desc:  "list subscriptions to other channel",
+			topic: "purgedother",
+			total: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		page, _ := svc.ListSubscriptions(context.Background(), exampleUser1, notifiers.PageMetadata{Topic: tc.topic, Limit: -1})
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected %d subscriptions got %d\n", tc.desc, tc.total, page.Total))
+	}
+}
+
 func TestConsume(t *testing.T) {
 	svc := newService()
 	sub := notifiers.Subscription{
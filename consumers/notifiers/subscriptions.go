@@ -11,6 +11,9 @@ type Subscription struct {
 	OwnerID string
 	Contact string
 	Topic   string
+	// Secret is the key the notifications of the subscription are signed
+	// with, used by the notifiers that sign the notifications.
+	Secret string
 }
 
 // Page represents page metadata with content.
@@ -43,4 +46,8 @@ type SubscriptionsRepository interface {
 
 	// Remove removes the subscription for the given ID.
 	Remove(ctx context.Context, id string) error
+
+	// RemoveByChannel removes the subscriptions to the channel with the
+	// given ID and to all its subtopics.
+	RemoveByChannel(ctx context.Context, chanID string) error
 }
@@ -0,0 +1,66 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/consumers/notifiers/webhook"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveDeliveryOp       = "save_delivery_op"
+	updateDeliveryOp     = "update_delivery_op"
+	retrieveDeliveriesOp = "retrieve_deliveries_op"
+	retrievePendingOp    = "retrieve_pending_deliveries_op"
+)
+
+var _ webhook.DeliveryRepository = (*deliveryRepositoryMiddleware)(nil)
+
+type deliveryRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   webhook.DeliveryRepository
+}
+
+// NewDeliveryRepository instantiates a new Deliveries repository that
+// tracks request and their latency, and adds spans to context.
+func NewDeliveryRepository(repo webhook.DeliveryRepository, tracer opentracing.Tracer) webhook.DeliveryRepository {
+	return deliveryRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (drm deliveryRepositoryMiddleware) Save(ctx context.Context, d webhook.Delivery) error {
+	span := createSpan(ctx, drm.tracer, saveDeliveryOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return drm.repo.Save(ctx, d)
+}
+
+func (drm deliveryRepositoryMiddleware) Update(ctx context.Context, d webhook.Delivery) error {
+	span := createSpan(ctx, drm.tracer, updateDeliveryOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return drm.repo.Update(ctx, d)
+}
+
+func (drm deliveryRepositoryMiddleware) RetrieveAll(ctx context.Context, pm webhook.PageMetadata) (webhook.DeliveriesPage, error) {
+	span := createSpan(ctx, drm.tracer, retrieveDeliveriesOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return drm.repo.RetrieveAll(ctx, pm)
+}
+
+func (drm deliveryRepositoryMiddleware) RetrievePending(ctx context.Context) ([]webhook.Delivery, error) {
+	span := createSpan(ctx, drm.tracer, retrievePendingOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return drm.repo.RetrievePending(ctx)
+}
@@ -17,6 +17,7 @@ const (
 	retrieveOp    = "retrieve_op"
 	retrieveAllOp = "retrieve_all_op"
 	removeOp      = "remove_op"
+	removeByChOp  = "remove_by_channel_op"
 )
 
 var _ notifiers.SubscriptionsRepository = (*subRepositoryMiddleware)(nil)
@@ -67,6 +68,14 @@ func (urm subRepositoryMiddleware) Remove(ctx context.Context, id string) error
 	return urm.repo.Remove(ctx, id)
 }
 
+func (urm subRepositoryMiddleware) RemoveByChannel(ctx context.Context, chanID string) error {
+	span := createSpan(ctx, urm.tracer, removeByChOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.RemoveByChannel(ctx, chanID)
+}
+
 func createSpan(ctx context.Context, tracer opentracing.Tracer, opName string) opentracing.Span {
 	if parentSpan := opentracing.SpanFromContext(ctx); parentSpan != nil {
 		return tracer.StartSpan(
@@ -0,0 +1,54 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/consumers/notifiers/webhook"
+)
+
+func listDeliveriesEndpoint(svc webhook.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listDeliveriesReq)
+		if err := req.validate(); err != nil {
+			return listDeliveriesRes{}, err
+		}
+
+		pm := webhook.PageMetadata{
+			Offset:  req.offset,
+			Limit:   req.limit,
+			Contact: req.contact,
+		}
+		page, err := svc.ListDeliveries(ctx, req.token, pm)
+		if err != nil {
+			return listDeliveriesRes{}, err
+		}
+
+		res := listDeliveriesRes{
+			Total:      page.Total,
+			Offset:     page.Offset,
+			Limit:      page.Limit,
+			Deliveries: []deliveryRes{},
+		}
+		for _, d := range page.Deliveries {
+			res.Deliveries = append(res.Deliveries, deliveryRes{
+				ID:         d.ID,
+				Contact:    d.Contact,
+				Channel:    d.Channel,
+				Subtopic:   d.Subtopic,
+				Publisher:  d.Publisher,
+				Status:     d.Status,
+				Attempts:   d.Attempts,
+				StatusCode: d.StatusCode,
+				Error:      d.Error,
+				Created:    d.Created,
+				Updated:    d.Updated,
+			})
+		}
+
+		return res, nil
+	}
+}
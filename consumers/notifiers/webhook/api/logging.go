@@ -0,0 +1,40 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux/consumers/notifiers/webhook"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+var _ webhook.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    webhook.Service
+}
+
+// LoggingMiddleware adds logging facilities to the delivery history service.
+func LoggingMiddleware(svc webhook.Service, logger log.Logger) webhook.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) ListDeliveries(ctx context.Context, token string, pm webhook.PageMetadata) (page webhook.DeliveriesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_deliveries for contact %s took %s to complete", pm.Contact, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListDeliveries(ctx, token, pm)
+}
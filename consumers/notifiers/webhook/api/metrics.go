@@ -0,0 +1,40 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/consumers/notifiers/webhook"
+)
+
+var _ webhook.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     webhook.Service
+}
+
+// MetricsMiddleware instruments delivery history service by tracking request count and latency.
+func MetricsMiddleware(svc webhook.Service, counter metrics.Counter, latency metrics.Histogram) webhook.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) ListDeliveries(ctx context.Context, token string, pm webhook.PageMetadata) (webhook.DeliveriesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_deliveries").Add(1)
+		ms.latency.With("method", "list_deliveries").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListDeliveries(ctx, token, pm)
+}
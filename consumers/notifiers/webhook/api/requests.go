@@ -0,0 +1,30 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/pkg/errors"
+
+const maxLimitSize = 100
+
+var errInvalidContact = errors.New("invalid Delivery contact")
+
+type listDeliveriesReq struct {
+	token   string
+	contact string
+	offset  uint64
+	limit   uint64
+}
+
+func (req listDeliveriesReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+	if req.contact == "" {
+		return errInvalidContact
+	}
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return errors.ErrInvalidQueryParams
+	}
+	return nil
+}
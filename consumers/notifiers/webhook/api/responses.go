@@ -0,0 +1,46 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var _ mainflux.Response = (*listDeliveriesRes)(nil)
+
+type deliveryRes struct {
+	ID         string    `json:"id"`
+	Contact    string    `json:"contact"`
+	Channel    string    `json:"channel"`
+	Subtopic   string    `json:"subtopic,omitempty"`
+	Publisher  string    `json:"publisher"`
+	Status     string    `json:"status"`
+	Attempts   int       `json:"attempts"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Created    time.Time `json:"created"`
+	Updated    time.Time `json:"updated"`
+}
+
+type listDeliveriesRes struct {
+	Total      uint64        `json:"total"`
+	Offset     uint64        `json:"offset"`
+	Limit      uint64        `json:"limit"`
+	Deliveries []deliveryRes `json:"deliveries"`
+}
+
+func (res listDeliveriesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listDeliveriesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listDeliveriesRes) Empty() bool {
+	return false
+}
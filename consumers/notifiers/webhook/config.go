@@ -0,0 +1,34 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"net"
+	"time"
+)
+
+// Config represents webhook notifier configuration.
+type Config struct {
+	// Secret is the key of the HMAC signature of the request body.
+	Secret string
+
+	// Timeout is the timeout of a single delivery attempt.
+	Timeout time.Duration
+
+	// MaxAttempts is the number of delivery attempts before the
+	// delivery is marked as failed.
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry, doubled after
+	// every failed attempt.
+	Backoff time.Duration
+
+	// Workers is the number of deliveries sent concurrently.
+	Workers int
+
+	// AllowedNets are the private networks the webhooks may resolve to.
+	// Loopback, private, link-local and multicast addresses outside of
+	// these networks are rejected.
+	AllowedNets []*net.IPNet
+}
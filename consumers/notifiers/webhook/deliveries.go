@@ -0,0 +1,70 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+	"time"
+)
+
+// Delivery statuses.
+const (
+	Pending   = "pending"
+	Delivered = "delivered"
+	Failed    = "failed"
+)
+
+// Delivery represents a delivery of a single message to the webhook URL.
+// Body and Signature hold the signed request of the pending delivery, so
+// that it can be resumed after a restart, and are cleared once the
+// delivery is finished.
+type Delivery struct {
+	ID         string
+	Owner      string
+	Contact    string
+	Channel    string
+	Subtopic   string
+	Publisher  string
+	Status     string
+	Attempts   int
+	StatusCode int
+	Error      string
+	Body       []byte
+	Signature  string
+	Created    time.Time
+	Updated    time.Time
+}
+
+// PageMetadata contains page metadata that helps navigation.
+type PageMetadata struct {
+	Total   uint64
+	Offset  uint64
+	Limit   uint64
+	Contact string
+	Owner   string
+}
+
+// DeliveriesPage contains page related metadata as well as a list of
+// deliveries that belong to this page.
+type DeliveriesPage struct {
+	PageMetadata
+	Deliveries []Delivery
+}
+
+// DeliveryRepository specifies a delivery history persistence API.
+type DeliveryRepository interface {
+	// Save persists the delivery.
+	Save(ctx context.Context, d Delivery) error
+
+	// Update updates the status of the existing delivery.
+	Update(ctx context.Context, d Delivery) error
+
+	// RetrieveAll retrieves the deliveries to the contact, newest first.
+	// If the owner is set, only the deliveries of its subscriptions are
+	// retrieved.
+	RetrieveAll(ctx context.Context, pm PageMetadata) (DeliveriesPage, error)
+
+	// RetrievePending retrieves the deliveries that are not finished yet.
+	RetrievePending(ctx context.Context) ([]Delivery, error)
+}
@@ -0,0 +1,89 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/consumers/notifiers/webhook"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var _ webhook.DeliveryRepository = (*deliveryRepositoryMock)(nil)
+
+type deliveryRepositoryMock struct {
+	mu         sync.Mutex
+	deliveries map[string]webhook.Delivery
+}
+
+// NewDeliveryRepository creates in-memory delivery repository.
+func NewDeliveryRepository() webhook.DeliveryRepository {
+	return &deliveryRepositoryMock{
+		deliveries: make(map[string]webhook.Delivery),
+	}
+}
+
+func (drm *deliveryRepositoryMock) Save(_ context.Context, d webhook.Delivery) error {
+	drm.mu.Lock()
+	defer drm.mu.Unlock()
+
+	if _, ok := drm.deliveries[d.ID]; ok {
+		return errors.ErrConflict
+	}
+	drm.deliveries[d.ID] = d
+
+	return nil
+}
+
+func (drm *deliveryRepositoryMock) Update(_ context.Context, d webhook.Delivery) error {
+	drm.mu.Lock()
+	defer drm.mu.Unlock()
+
+	if _, ok := drm.deliveries[d.ID]; !ok {
+		return errors.ErrNotFound
+	}
+	drm.deliveries[d.ID] = d
+
+	return nil
+}
+
+func (drm *deliveryRepositoryMock) RetrieveAll(_ context.Context, pm webhook.PageMetadata) (webhook.DeliveriesPage, error) {
+	drm.mu.Lock()
+	defer drm.mu.Unlock()
+
+	var all []webhook.Delivery
+	for _, d := range drm.deliveries {
+		if (pm.Contact == "" || d.Contact == pm.Contact) && (pm.Owner == "" || d.Owner == pm.Owner) {
+			all = append(all, d)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID > all[j].ID })
+
+	page := webhook.DeliveriesPage{
+		PageMetadata: pm,
+		Deliveries:   []webhook.Delivery{},
+	}
+	page.Total = uint64(len(all))
+	for i := pm.Offset; i < uint64(len(all)) && i < pm.Offset+pm.Limit; i++ {
+		page.Deliveries = append(page.Deliveries, all[i])
+	}
+
+	return page, nil
+}
+
+func (drm *deliveryRepositoryMock) RetrievePending(_ context.Context) ([]webhook.Delivery, error) {
+	drm.mu.Lock()
+	defer drm.mu.Unlock()
+
+	var pending []webhook.Delivery
+	for _, d := range drm.deliveries {
+		if d.Status == webhook.Pending {
+			pending = append(pending, d)
+		}
+	}
+
+	return pending, nil
+}
@@ -0,0 +1,270 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	notifiers "github.com/mainflux/mainflux/consumers/notifiers"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+const (
+	contentType = "application/json"
+
+	// SignatureHeader contains the hex encoded HMAC-SHA256 signature of
+	// the request body, prefixed with "sha256=".
+	SignatureHeader = "X-Mainflux-Signature"
+
+	// DeliveryHeader contains the delivery ID.
+	DeliveryHeader = "X-Mainflux-Delivery"
+)
+
+var (
+	errStatus = errors.New("unexpected webhook response status")
+
+	errInterrupted = errors.New("delivery interrupted without the request to resume")
+)
+
+// Notifier delivers the messages to the webhooks and resumes the deliveries
+// interrupted by the restart of the service.
+type Notifier interface {
+	notifiers.Notifier
+	notifiers.SubscriptionNotifier
+
+	// Resume resumes the pending deliveries, honouring the backoff of the
+	// attempts made before they were interrupted.
+	Resume(ctx context.Context) error
+}
+
+var _ Notifier = (*notifier)(nil)
+
+type notifier struct {
+	cfg        Config
+	client     *http.Client
+	deliveries DeliveryRepository
+	idp        mainflux.IDProvider
+	logger     logger.Logger
+	queue      chan Delivery
+}
+
+// New instantiates webhook message notifier. Messages are delivered
+// asynchronously by a fixed number of workers and every delivery is
+// recorded in the repository.
+func New(cfg Config, deliveries DeliveryRepository, idp mainflux.IDProvider, logger logger.Logger) Notifier {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+
+	n := &notifier{
+		cfg:        cfg,
+		client:     httputil.NewPublicClient(cfg.Timeout, cfg.AllowedNets),
+		deliveries: deliveries,
+		idp:        idp,
+		logger:     logger,
+		queue:      make(chan Delivery, cfg.Workers),
+	}
+	for i := 0; i < cfg.Workers; i++ {
+		go n.work()
+	}
+
+	return n
+}
+
+type webhookMsg struct {
+	Channel   string `json:"channel"`
+	Subtopic  string `json:"subtopic,omitempty"`
+	Publisher string `json:"publisher"`
+	Protocol  string `json:"protocol"`
+	Created   int64  `json:"created"`
+	Payload   []byte `json:"payload"`
+}
+
+func (n *notifier) Notify(from string, to []string, msg messaging.Message) error {
+	var subs []notifiers.Subscription
+	for _, url := range to {
+		subs = append(subs, notifiers.Subscription{Contact: url})
+	}
+	return n.NotifySubscriptions(from, subs, msg)
+}
+
+func (n *notifier) NotifySubscriptions(from string, subs []notifiers.Subscription, msg messaging.Message) error {
+	body, err := json.Marshal(webhookMsg{
+		Channel:   msg.Channel,
+		Subtopic:  msg.Subtopic,
+		Publisher: msg.Publisher,
+		Protocol:  msg.Protocol,
+		Created:   msg.Created,
+		Payload:   msg.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subs {
+		id, err := n.idp.ID()
+		if err != nil {
+			return err
+		}
+
+		secret := sub.Secret
+		if secret == "" {
+			secret = n.cfg.Secret
+		}
+
+		now := time.Now()
+		d := Delivery{
+			ID:        id,
+			Owner:     sub.OwnerID,
+			Contact:   sub.Contact,
+			Channel:   msg.Channel,
+			Subtopic:  msg.Subtopic,
+			Publisher: msg.Publisher,
+			Status:    Pending,
+			Body:      body,
+			Signature: Sign(secret, body),
+			Created:   now,
+			Updated:   now,
+		}
+		if err := n.deliveries.Save(context.Background(), d); err != nil {
+			return err
+		}
+
+		n.queue <- d
+	}
+
+	return nil
+}
+
+func (n *notifier) Resume(ctx context.Context) error {
+	pending, err := n.deliveries.RetrievePending(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range pending {
+		if len(d.Body) == 0 {
+			// Deliveries saved before the requests were persisted
+			// can't be resumed.
+			n.finish(&d, Failed, errInterrupted)
+			continue
+		}
+		n.schedule(d)
+	}
+
+	return nil
+}
+
+func (n *notifier) work() {
+	for d := range n.queue {
+		n.attempt(d)
+	}
+}
+
+// schedule queues the delivery once the backoff after its last attempt
+// expires, so the resumed delivery keeps its schedule and the waiting
+// deliveries don't occupy the workers.
+func (n *notifier) schedule(d Delivery) {
+	var wait time.Duration
+	if d.Attempts > 0 {
+		backoff := n.cfg.Backoff << uint(d.Attempts-1)
+		wait = time.Until(d.Updated.Add(backoff))
+	}
+	if wait <= 0 {
+		n.queue <- d
+		return
+	}
+	time.AfterFunc(wait, func() { n.queue <- d })
+}
+
+// attempt posts the body to the delivery contact once, and schedules
+// the retry with the exponential backoff until it succeeds or runs out
+// of attempts.
+func (n *notifier) attempt(d Delivery) {
+	d.Attempts++
+	d.StatusCode, d.Error = 0, ""
+	code, err := n.send(d)
+	d.StatusCode = code
+	switch {
+	case err == nil:
+		n.finish(&d, Delivered, nil)
+	case d.Attempts >= n.cfg.MaxAttempts || !retryable(code):
+		n.finish(&d, Failed, err)
+	default:
+		d.Error = err.Error()
+		d.Updated = time.Now()
+		n.update(d)
+		n.schedule(d)
+	}
+}
+
+// finish records the final status of the delivery and drops the request
+// that is not needed anymore.
+func (n *notifier) finish(d *Delivery, status string, err error) {
+	d.Status = status
+	if err != nil {
+		d.Error = err.Error()
+	}
+	d.Body, d.Signature = nil, ""
+	n.update(*d)
+}
+
+func (n *notifier) update(d Delivery) {
+	d.Updated = time.Now()
+	if err := n.deliveries.Update(context.Background(), d); err != nil {
+		n.logger.Warn(fmt.Sprintf("Failed to update delivery %s: %s", d.ID, err))
+	}
+}
+
+func (n *notifier) send(d Delivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, d.Contact, bytes.NewReader(d.Body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(SignatureHeader, d.Signature)
+	req.Header.Set(DeliveryHeader, d.ID)
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return res.StatusCode, errors.Wrap(errStatus, errors.New(res.Status))
+	}
+	return res.StatusCode, nil
+}
+
+// retryable returns true for the transport errors, server errors and
+// the responses asking the client to slow down.
+func retryable(code int) bool {
+	return code == 0 ||
+		code >= http.StatusInternalServerError ||
+		code == http.StatusRequestTimeout ||
+		code == http.StatusTooManyRequests
+}
+
+// Sign returns the value of the signature header for the given body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil)))
+}
@@ -0,0 +1,338 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	notifiers "github.com/mainflux/mainflux/consumers/notifiers"
+	"github.com/mainflux/mainflux/consumers/notifiers/webhook"
+	"github.com/mainflux/mainflux/consumers/notifiers/webhook/mocks"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "secret"
+
+var loopback = []*net.IPNet{{IP: net.IPv4(127, 0, 0, 0), Mask: net.CIDRMask(8, 32)}}
+
+var msg = messaging.Message{
+	Channel:   "channel",
+	Subtopic:  "subtopic",
+	Publisher: "publisher",
+	Protocol:  "http",
+	Payload:   []byte(`[{"n":"temperature","v":30}]`),
+	Created:   time.Now().UnixNano(),
+}
+
+// server responds with the given status codes in order, and with the last
+// status code once it runs out of them.
+type server struct {
+	mu       sync.Mutex
+	codes    []int
+	requests int
+	sigs     []string
+	bodies   [][]byte
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body, _ := ioutil.ReadAll(r.Body)
+	s.bodies = append(s.bodies, body)
+	s.sigs = append(s.sigs, r.Header.Get(webhook.SignatureHeader))
+
+	code := s.codes[len(s.codes)-1]
+	if s.requests < len(s.codes) {
+		code = s.codes[s.requests]
+	}
+	s.requests++
+	w.WriteHeader(code)
+}
+
+func waitFor(t *testing.T, repo webhook.DeliveryRepository, contact string) webhook.Delivery {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		page, err := repo.RetrieveAll(context.Background(), webhook.PageMetadata{Limit: 1, Contact: contact})
+		require.Nil(t, err, fmt.Sprintf("unexpected error retrieving deliveries: %s", err))
+		if len(page.Deliveries) == 1 && page.Deliveries[0].Status != webhook.Pending {
+			return page.Deliveries[0]
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.FailNow(t, "delivery not completed in time")
+	return webhook.Delivery{}
+}
+
+func TestNotify(t *testing.T) {
+	cases := []struct {
+		desc       string
+		codes      []int
+		status     string
+		attempts   int
+		statusCode int
+	}{
+		{
+			desc:       "deliver message",
+			codes:      []int{http.StatusOK},
+			status:     webhook.Delivered,
+			attempts:   1,
+			statusCode: http.StatusOK,
+		},
+		{
+			desc:       "deliver message after retries",
+			codes:      []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusAccepted},
+			status:     webhook.Delivered,
+			attempts:   3,
+			statusCode: http.StatusAccepted,
+		},
+		{
+			desc:       "fail delivery after max attempts",
+			codes:      []int{http.StatusInternalServerError},
+			status:     webhook.Failed,
+			attempts:   3,
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			desc:       "fail delivery without retries on client error",
+			codes:      []int{http.StatusBadRequest},
+			status:     webhook.Failed,
+			attempts:   1,
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		srv := &server{codes: tc.codes}
+		ts := httptest.NewServer(srv)
+
+		repo := mocks.NewDeliveryRepository()
+		cfg := webhook.Config{
+			Secret:      secret,
+			Timeout:     time.Second,
+			MaxAttempts: 3,
+			Backoff:     time.Millisecond,
+			AllowedNets: loopback,
+		}
+		notifier := webhook.New(cfg, repo, uuid.NewMock(), logger.NewMock())
+
+		err := notifier.Notify("", []string{ts.URL}, msg)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		d := waitFor(t, repo, ts.URL)
+		assert.Equal(t, tc.status, d.Status, fmt.Sprintf("%s: expected status %s got %s\n", tc.desc, tc.status, d.Status))
+		assert.Equal(t, tc.attempts, d.Attempts, fmt.Sprintf("%s: expected %d attempts got %d\n", tc.desc, tc.attempts, d.Attempts))
+		assert.Equal(t, tc.statusCode, d.StatusCode, fmt.Sprintf("%s: expected status code %d got %d\n", tc.desc, tc.statusCode, d.StatusCode))
+		assert.Equal(t, msg.Channel, d.Channel, fmt.Sprintf("%s: expected channel %s got %s\n", tc.desc, msg.Channel, d.Channel))
+		if tc.status == webhook.Failed {
+			assert.NotEmpty(t, d.Error, fmt.Sprintf("%s: expected delivery error", tc.desc))
+		}
+
+		srv.mu.Lock()
+		assert.Equal(t, tc.attempts, srv.requests, fmt.Sprintf("%s: expected %d requests got %d\n", tc.desc, tc.attempts, srv.requests))
+		for i, body := range srv.bodies {
+			sig := webhook.Sign(secret, body)
+			assert.Equal(t, sig, srv.sigs[i], fmt.Sprintf("%s: expected signature %s got %s\n", tc.desc, sig, srv.sigs[i]))
+
+			var m map[string]interface{}
+			err := json.Unmarshal(body, &m)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error decoding body: %s", tc.desc, err))
+			assert.Equal(t, msg.Channel, m["channel"], fmt.Sprintf("%s: expected channel %s got %v\n", tc.desc, msg.Channel, m["channel"]))
+		}
+		srv.mu.Unlock()
+
+		ts.Close()
+	}
+}
+
+func TestNotifyUnreachable(t *testing.T) {
+	repo := mocks.NewDeliveryRepository()
+	cfg := webhook.Config{
+		Secret:      secret,
+		Timeout:     time.Second,
+		MaxAttempts: 2,
+		Backoff:     time.Millisecond,
+		AllowedNets: loopback,
+	}
+	notifier := webhook.New(cfg, repo, uuid.NewMock(), logger.NewMock())
+
+	ts := httptest.NewServer(http.NotFoundHandler())
+	url := ts.URL
+	ts.Close()
+
+	err := notifier.Notify("", []string{url}, msg)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	d := waitFor(t, repo, url)
+	assert.Equal(t, webhook.Failed, d.Status, fmt.Sprintf("expected status %s got %s\n", webhook.Failed, d.Status))
+	assert.Equal(t, 2, d.Attempts, fmt.Sprintf("expected 2 attempts got %d\n", d.Attempts))
+	assert.Equal(t, 0, d.StatusCode, fmt.Sprintf("expected no status code got %d\n", d.StatusCode))
+}
+
+func TestNotifyForbiddenAddress(t *testing.T) {
+	srv := &server{codes: []int{http.StatusOK}}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	repo := mocks.NewDeliveryRepository()
+	cfg := webhook.Config{
+		Secret:      secret,
+		Timeout:     time.Second,
+		MaxAttempts: 1,
+		Backoff:     time.Millisecond,
+	}
+	notifier := webhook.New(cfg, repo, uuid.NewMock(), logger.NewMock())
+
+	err := notifier.Notify("", []string{ts.URL}, msg)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	d := waitFor(t, repo, ts.URL)
+	assert.Equal(t, webhook.Failed, d.Status, fmt.Sprintf("expected status %s got %s\n", webhook.Failed, d.Status))
+	assert.Contains(t, d.Error, httputil.ErrForbiddenAddress.Error(), fmt.Sprintf("expected %s got %s\n", httputil.ErrForbiddenAddress, d.Error))
+
+	srv.mu.Lock()
+	assert.Equal(t, 0, srv.requests, fmt.Sprintf("expected no requests got %d\n", srv.requests))
+	srv.mu.Unlock()
+}
+
+func TestNotifySubscriptions(t *testing.T) {
+	srv := &server{codes: []int{http.StatusOK}}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	cases := []struct {
+		desc   string
+		sub    notifiers.Subscription
+		secret string
+	}{
+		{
+			desc:   "sign with subscription secret",
+			sub:    notifiers.Subscription{OwnerID: "owner", Contact: ts.URL + "/own", Secret: "subscription-secret"},
+			secret: "subscription-secret",
+		},
+		{
+			desc:   "sign with default secret",
+			sub:    notifiers.Subscription{OwnerID: "owner", Contact: ts.URL + "/default"},
+			secret: secret,
+		},
+	}
+
+	for _, tc := range cases {
+		repo := mocks.NewDeliveryRepository()
+		cfg := webhook.Config{
+			Secret:      secret,
+			Timeout:     time.Second,
+			MaxAttempts: 1,
+			Backoff:     time.Millisecond,
+			AllowedNets: loopback,
+		}
+		notifier := webhook.New(cfg, repo, uuid.NewMock(), logger.NewMock())
+
+		err := notifier.NotifySubscriptions("", []notifiers.Subscription{tc.sub}, msg)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		d := waitFor(t, repo, tc.sub.Contact)
+		assert.Equal(t, webhook.Delivered, d.Status, fmt.Sprintf("%s: expected status %s got %s\n", tc.desc, webhook.Delivered, d.Status))
+		assert.Equal(t, tc.sub.OwnerID, d.Owner, fmt.Sprintf("%s: expected owner %s got %s\n", tc.desc, tc.sub.OwnerID, d.Owner))
+		assert.Empty(t, d.Body, fmt.Sprintf("%s: expected body of the finished delivery to be cleared", tc.desc))
+
+		srv.mu.Lock()
+		last := len(srv.bodies) - 1
+		sig := webhook.Sign(tc.secret, srv.bodies[last])
+		assert.Equal(t, sig, srv.sigs[last], fmt.Sprintf("%s: expected signature %s got %s\n", tc.desc, sig, srv.sigs[last]))
+		srv.mu.Unlock()
+	}
+}
+
+func TestResume(t *testing.T) {
+	srv := &server{codes: []int{http.StatusOK}}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	body := []byte(`{"channel":"channel"}`)
+	cases := []struct {
+		desc     string
+		delivery webhook.Delivery
+		status   string
+		attempts int
+	}{
+		{
+			desc: "resume interrupted delivery",
+			delivery: webhook.Delivery{
+				ID:        "1",
+				Contact:   ts.URL + "/interrupted",
+				Status:    webhook.Pending,
+				Attempts:  1,
+				Body:      body,
+				Signature: webhook.Sign(secret, body),
+			},
+			status:   webhook.Delivered,
+			attempts: 2,
+		},
+		{
+			desc: "resume delivery without attempts",
+			delivery: webhook.Delivery{
+				ID:        "2",
+				Contact:   ts.URL + "/new",
+				Status:    webhook.Pending,
+				Body:      body,
+				Signature: webhook.Sign(secret, body),
+			},
+			status:   webhook.Delivered,
+			attempts: 1,
+		},
+		{
+			desc: "fail delivery without request to resume",
+			delivery: webhook.Delivery{
+				ID:       "3",
+				Contact:  ts.URL + "/unknown",
+				Status:   webhook.Pending,
+				Attempts: 1,
+			},
+			status:   webhook.Failed,
+			attempts: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		repo := mocks.NewDeliveryRepository()
+		cfg := webhook.Config{
+			Secret:      secret,
+			Timeout:     time.Second,
+			MaxAttempts: 3,
+			Backoff:     time.Millisecond,
+			AllowedNets: loopback,
+		}
+		notifier := webhook.New(cfg, repo, uuid.NewMock(), logger.NewMock())
+
+		tc.delivery.Created = time.Now()
+		tc.delivery.Updated = tc.delivery.Created
+		err := repo.Save(context.Background(), tc.delivery)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error saving delivery: %s", tc.desc, err))
+
+		err = notifier.Resume(context.Background())
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		d := waitFor(t, repo, tc.delivery.Contact)
+		assert.Equal(t, tc.status, d.Status, fmt.Sprintf("%s: expected status %s got %s\n", tc.desc, tc.status, d.Status))
+		assert.Equal(t, tc.attempts, d.Attempts, fmt.Sprintf("%s: expected %d attempts got %d\n", tc.desc, tc.attempts, d.Attempts))
+		if tc.status == webhook.Failed {
+			assert.NotEmpty(t, d.Error, fmt.Sprintf("%s: expected delivery error", tc.desc))
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux"
+	notifiers "github.com/mainflux/mainflux/consumers/notifiers"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// Service specifies an API for inspecting the webhook delivery history.
+type Service interface {
+	// ListDeliveries retrieves the deliveries of the subscriptions of the
+	// user identified by the provided key to the webhook URL.
+	ListDeliveries(ctx context.Context, token string, pm PageMetadata) (DeliveriesPage, error)
+}
+
+var _ Service = (*webhookService)(nil)
+
+type webhookService struct {
+	auth       mainflux.AuthServiceClient
+	subs       notifiers.SubscriptionsRepository
+	deliveries DeliveryRepository
+}
+
+// NewService instantiates the webhook delivery history service implementation.
+func NewService(auth mainflux.AuthServiceClient, subs notifiers.SubscriptionsRepository, deliveries DeliveryRepository) Service {
+	return &webhookService{
+		auth:       auth,
+		subs:       subs,
+		deliveries: deliveries,
+	}
+}
+
+func (ws *webhookService) ListDeliveries(ctx context.Context, token string, pm PageMetadata) (DeliveriesPage, error) {
+	res, err := ws.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return DeliveriesPage{}, errors.Wrap(errors.ErrAuthentication, err)
+	}
+
+	page, err := ws.subs.RetrieveAll(ctx, notifiers.PageMetadata{Contact: pm.Contact, Limit: -1})
+	if err != nil {
+		return DeliveriesPage{}, err
+	}
+	if !subscribed(page.Subscriptions, res.GetId()) {
+		return DeliveriesPage{}, errors.ErrNotFound
+	}
+
+	pm.Owner = res.GetId()
+	return ws.deliveries.RetrieveAll(ctx, pm)
+}
+
+func subscribed(subs []notifiers.Subscription, owner string) bool {
+	for _, sub := range subs {
+		if sub.OwnerID == owner {
+			return true
+		}
+	}
+	return false
+}
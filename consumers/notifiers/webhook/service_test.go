@@ -0,0 +1,123 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	notifiers "github.com/mainflux/mainflux/consumers/notifiers"
+	nmocks "github.com/mainflux/mainflux/consumers/notifiers/mocks"
+	"github.com/mainflux/mainflux/consumers/notifiers/webhook"
+	"github.com/mainflux/mainflux/consumers/notifiers/webhook/mocks"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	owner      = "owner@example.com"
+	other      = "other@example.com"
+	third      = "third@example.com"
+	webhookURL = "https://example.com/webhook"
+	otherURL   = "https://example.com/other"
+)
+
+func TestListDeliveries(t *testing.T) {
+	auth := nmocks.NewAuth(map[string]string{owner: owner, other: other, third: third})
+	subs := nmocks.NewRepo(map[string]notifiers.Subscription{
+		"1": {ID: "1", OwnerID: owner, Contact: webhookURL, Topic: "channel"},
+		"2": {ID: "2", OwnerID: other, Contact: otherURL, Topic: "channel"},
+		"3": {ID: "3", OwnerID: third, Contact: webhookURL, Topic: "other"},
+	})
+	deliveries := mocks.NewDeliveryRepository()
+	svc := webhook.NewService(auth, subs, deliveries)
+
+	n := 5
+	for i := 0; i < n; i++ {
+		d := webhook.Delivery{
+			ID:      fmt.Sprintf("%d", i),
+			Owner:   owner,
+			Contact: webhookURL,
+			Status:  webhook.Delivered,
+			Created: time.Now(),
+		}
+		err := deliveries.Save(context.Background(), d)
+		require.Nil(t, err, fmt.Sprintf("unexpected error saving delivery: %s", err))
+	}
+	m := 2
+	for i := 0; i < m; i++ {
+		d := webhook.Delivery{
+			ID:      fmt.Sprintf("%d", n+i),
+			Owner:   third,
+			Contact: webhookURL,
+			Status:  webhook.Delivered,
+			Created: time.Now(),
+		}
+		err := deliveries.Save(context.Background(), d)
+		require.Nil(t, err, fmt.Sprintf("unexpected error saving delivery: %s", err))
+	}
+
+	cases := []struct {
+		desc  string
+		token string
+		pm    webhook.PageMetadata
+		size  int
+		err   error
+	}{
+		{
+			desc:  "list deliveries",
+			token: owner,
+			pm:    webhook.PageMetadata{Limit: 10, Contact: webhookURL},
+			size:  n,
+			err:   nil,
+		},
+		{
+			desc:  "list a page of deliveries",
+			token: owner,
+			pm:    webhook.PageMetadata{Offset: 3, Limit: 10, Contact: webhookURL},
+			size:  2,
+			err:   nil,
+		},
+		{
+			desc:  "list deliveries with invalid token",
+			token: "invalid",
+			pm:    webhook.PageMetadata{Limit: 10, Contact: webhookURL},
+			size:  0,
+			err:   errors.ErrAuthentication,
+		},
+		{
+			desc:  "list deliveries to URL subscribed by another user",
+			token: other,
+			pm:    webhook.PageMetadata{Limit: 10, Contact: webhookURL},
+			size:  0,
+			err:   errors.ErrNotFound,
+		},
+		{
+			desc:  "list deliveries to URL subscribed by multiple users",
+			token: third,
+			pm:    webhook.PageMetadata{Limit: 10, Contact: webhookURL},
+			size:  m,
+			err:   nil,
+		},
+		{
+			desc:  "list deliveries to URL without subscriptions",
+			token: owner,
+			pm:    webhook.PageMetadata{Limit: 10, Contact: "https://example.com/unknown"},
+			size:  0,
+			err:   errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListDeliveries(context.Background(), tc.token, tc.pm)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(page.Deliveries), fmt.Sprintf("%s: expected %d deliveries got %d\n", tc.desc, tc.size, len(page.Deliveries)))
+		for _, d := range page.Deliveries {
+			assert.Equal(t, tc.token, d.Owner, fmt.Sprintf("%s: expected delivery owner %s got %s\n", tc.desc, tc.token, d.Owner))
+		}
+	}
+}
@@ -0,0 +1,420 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package spill
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+	mfjson "github.com/mainflux/mainflux/pkg/transformers/json"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+)
+
+const (
+	offsetSuffix     = ".offset"
+	deadSuffix       = ".dead"
+	defRetryInterval = 5 * time.Second
+	defMaxAttempts   = 10
+
+	// maxOffset limits the reader of the growing buffer file.
+	maxOffset = 1 << 62
+)
+
+var (
+	// ErrFull indicates that the buffer has no room for the messages.
+	ErrFull = errors.New("spill buffer is full")
+
+	errOpen   = errors.New("failed to open spill buffer")
+	errUpdate = errors.New("failed to update spill buffer")
+)
+
+// Config defines the spill buffer options.
+type Config struct {
+	// Path is the path of the buffer file. The replay offset is kept in the
+	// file of the same path with the ".offset" suffix.
+	Path string
+	// MaxSize is the maximal size of the buffer file in bytes.
+	MaxSize int64
+	// RetryInterval is the delay between the attempts to write the
+	// buffered messages.
+	RetryInterval time.Duration
+	// MaxAttempts is the number of the attempts to write the buffered
+	// messages, after which they're moved to the dead-letter file of the
+	// same path with the ".dead" suffix, so that they don't block the
+	// messages buffered after them. Attempts are counted since the buffer
+	// was opened.
+	MaxAttempts int
+}
+
+// Buffer is the consumer that writes messages using the decorated consumer,
+// and buffers the messages that failed to be written on the local disk.
+// While there are buffered messages, the new ones are appended to the buffer
+// as well, so the messages are written in the order they are consumed.
+type Buffer interface {
+	consumers.Consumer
+
+	// Size returns the size of the buffer file in bytes.
+	Size() int64
+
+	// Pending returns the number of buffered writes.
+	Pending() int
+
+	// Close stops the buffer replay and closes the buffer file.
+	Close() error
+}
+
+var _ Buffer = (*buffer)(nil)
+
+type buffer struct {
+	cfg      Config
+	consumer consumers.Consumer
+	logger   logger.Logger
+
+	// wmu serializes the writes of the decorated consumer, so that the
+	// direct writes can't overtake the replay of the buffered ones.
+	wmu      sync.Mutex
+	mu       sync.Mutex
+	file     *os.File
+	reader   *bufio.Reader
+	size     int64
+	offset   int64
+	pending  int
+	attempts int
+	done     chan struct{}
+	stopped  chan struct{}
+}
+
+// record is the single buffered write.
+type record struct {
+	SenML []senml.Message `json:"senml,omitempty"`
+	JSON  *jsonMessages   `json:"json,omitempty"`
+}
+
+type jsonMessages struct {
+	Format string           `json:"format"`
+	Data   []mfjson.Message `json:"data"`
+}
+
+// New opens the buffer file, creating it if it doesn't exist, and starts
+// replaying the messages buffered before.
+func New(consumer consumers.Consumer, cfg Config, logger logger.Logger) (Buffer, error) {
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = defRetryInterval
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defMaxAttempts
+	}
+
+	file, err := os.OpenFile(cfg.Path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, errors.Wrap(errOpen, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, errors.Wrap(errOpen, err)
+	}
+
+	b := &buffer{
+		cfg:      cfg,
+		consumer: consumer,
+		logger:   logger,
+		file:     file,
+		size:     info.Size(),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	b.offset = b.loadOffset()
+	if err := b.seek(); err != nil {
+		file.Close()
+		return nil, errors.Wrap(errOpen, err)
+	}
+	if b.pending > 0 {
+		logger.Info(fmt.Sprintf("Spill buffer contains %d pending writes", b.pending))
+	}
+
+	go b.run()
+
+	return b, nil
+}
+
+func (b *buffer) Consume(msgs interface{}) error {
+	rec, ok := toRecord(msgs)
+	if !ok {
+		return b.consumer.Consume(msgs)
+	}
+
+	b.wmu.Lock()
+	defer b.wmu.Unlock()
+
+	if b.Pending() == 0 {
+		err := b.consumer.Consume(msgs)
+		if err == nil {
+			return nil
+		}
+		if aerr := b.append(rec); aerr != nil {
+			return errors.Wrap(aerr, err)
+		}
+		return nil
+	}
+
+	if err := b.append(rec); err != nil {
+		// Rather write the messages out of order than drop them.
+		if cerr := b.consumer.Consume(msgs); cerr != nil {
+			return errors.Wrap(err, cerr)
+		}
+	}
+	return nil
+}
+
+func (b *buffer) Size() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.size
+}
+
+func (b *buffer) Pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.pending
+}
+
+func (b *buffer) Close() error {
+	close(b.done)
+	<-b.stopped
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.file.Close()
+}
+
+// append writes the record to the end of the buffer file.
+func (b *buffer) append(rec record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.size+int64(len(data)) > b.cfg.MaxSize {
+		return ErrFull
+	}
+	n, err := b.file.WriteAt(data, b.size)
+	b.size += int64(n)
+	if err != nil {
+		return err
+	}
+	b.pending++
+
+	return nil
+}
+
+func (b *buffer) run() {
+	defer close(b.stopped)
+
+	ticker := time.NewTicker(b.cfg.RetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-ticker.C:
+			b.replay()
+		}
+	}
+}
+
+// replay writes the buffered records in order, until the buffer is drained
+// or the write fails. The record that failed to be written too many times
+// is moved to the dead-letter file.
+func (b *buffer) replay() {
+	for {
+		select {
+		case <-b.done:
+			return
+		default:
+		}
+
+		if !b.replayNext() {
+			return
+		}
+	}
+}
+
+// replayNext writes the record at the replay offset, and returns true if
+// the replay may continue with the following record.
+func (b *buffer) replayNext() bool {
+	b.wmu.Lock()
+	defer b.wmu.Unlock()
+
+	line, msgs, next, ok := b.next()
+	if !ok {
+		return false
+	}
+	if msgs != nil {
+		if err := b.consumer.Consume(msgs); err != nil {
+			b.attempts++
+			if b.attempts < b.cfg.MaxAttempts {
+				b.logger.Warn(fmt.Sprintf("Failed to write buffered messages: %s", err))
+				return false
+			}
+			b.logger.Error(fmt.Sprintf("Moving buffered messages to dead-letter file after %d failed attempts: %s", b.attempts, err))
+			if err := b.bury(line); err != nil {
+				b.logger.Error(err.Error())
+				return false
+			}
+		}
+	}
+	if err := b.commit(next); err != nil {
+		b.logger.Error(err.Error())
+		return false
+	}
+
+	return true
+}
+
+// next reads the record at the replay offset. It returns the record line,
+// the record messages, which are nil if the record is corrupted, and the
+// offset of the following record. The reader is positioned back at the
+// record, so that the record is read again unless it's committed.
+func (b *buffer) next() ([]byte, interface{}, int64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.pending == 0 {
+		return nil, nil, 0, false
+	}
+
+	line, err := b.reader.ReadBytes('\n')
+	next := b.offset + int64(len(line))
+	b.reader.Reset(io.NewSectionReader(b.file, b.offset, maxOffset))
+	if err != nil {
+		b.logger.Warn(fmt.Sprintf("Dropping unreadable buffered records: %s", err))
+		return nil, nil, b.size, true
+	}
+
+	var rec record
+	if err := json.Unmarshal(line, &rec); err != nil {
+		b.logger.Warn(fmt.Sprintf("Dropping corrupted buffered record: %s", err))
+		return nil, nil, next, true
+	}
+
+	return line, rec.messages(), next, true
+}
+
+// bury appends the record line to the dead-letter file.
+func (b *buffer) bury(line []byte) error {
+	file, err := os.OpenFile(b.cfg.Path+deadSuffix, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrap(errUpdate, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(line); err != nil {
+		return errors.Wrap(errUpdate, err)
+	}
+	return nil
+}
+
+// commit moves the replay offset past the written record, and truncates
+// the buffer file once it's drained.
+func (b *buffer) commit(offset int64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pending--
+	b.attempts = 0
+	b.offset = offset
+	if b.offset >= b.size {
+		b.pending = 0
+		b.offset = 0
+		b.size = 0
+		if err := b.file.Truncate(0); err != nil {
+			return errors.Wrap(errUpdate, err)
+		}
+	}
+	b.reader.Reset(io.NewSectionReader(b.file, b.offset, maxOffset))
+
+	data := []byte(strconv.FormatInt(b.offset, 10))
+	if err := ioutil.WriteFile(b.cfg.Path+offsetSuffix, data, 0600); err != nil {
+		return errors.Wrap(errUpdate, err)
+	}
+
+	return nil
+}
+
+// loadOffset returns the saved replay offset, or zero if it's missing or
+// invalid.
+func (b *buffer) loadOffset() int64 {
+	data, err := ioutil.ReadFile(b.cfg.Path + offsetSuffix)
+	if err != nil {
+		return 0
+	}
+	offset, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil || offset < 0 || offset > b.size {
+		return 0
+	}
+	return offset
+}
+
+// seek positions the reader at the replay offset, and counts the pending
+// records. The incomplete record at the end of the file, left if the service
+// stopped while appending it, is truncated.
+func (b *buffer) seek() error {
+	reader := bufio.NewReader(io.NewSectionReader(b.file, b.offset, b.size-b.offset))
+	end := b.offset
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		end += int64(len(line))
+		b.pending++
+	}
+	if end < b.size {
+		b.logger.Warn(fmt.Sprintf("Dropping incomplete buffered record of %d bytes", b.size-end))
+		if err := b.file.Truncate(end); err != nil {
+			return err
+		}
+		b.size = end
+	}
+
+	b.reader = bufio.NewReader(io.NewSectionReader(b.file, b.offset, maxOffset))
+	return nil
+}
+
+func toRecord(msgs interface{}) (record, bool) {
+	switch m := msgs.(type) {
+	case []senml.Message:
+		return record{SenML: m}, true
+	case mfjson.Messages:
+		return record{JSON: &jsonMessages{Format: m.Format, Data: m.Data}}, true
+	default:
+		return record{}, false
+	}
+}
+
+func (rec record) messages() interface{} {
+	if rec.JSON != nil {
+		return mfjson.Messages{Format: rec.JSON.Format, Data: rec.JSON.Data}
+	}
+	return rec.SenML
+}
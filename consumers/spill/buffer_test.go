@@ -0,0 +1,239 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package spill_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/consumers/spill"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+	mfjson "github.com/mainflux/mainflux/pkg/transformers/json"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errDown = errors.New("database is down")
+
+// consumer records the written messages, and fails while it's down.
+type consumer struct {
+	mu     sync.Mutex
+	down   bool
+	writes []interface{}
+}
+
+func (c *consumer) Consume(msgs interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.down {
+		return errDown
+	}
+	c.writes = append(c.writes, msgs)
+	return nil
+}
+
+func (c *consumer) setDown(down bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.down = down
+}
+
+func (c *consumer) written() []interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]interface{}{}, c.writes...)
+}
+
+func newConfig(t *testing.T) spill.Config {
+	dir, err := ioutil.TempDir("", "spill")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	return spill.Config{
+		Path:          filepath.Join(dir, "buffer"),
+		MaxSize:       1024 * 1024,
+		RetryInterval: 10 * time.Millisecond,
+	}
+}
+
+func waitDrained(t *testing.T, b spill.Buffer) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if b.Pending() == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.FailNow(t, "buffer not drained in time")
+}
+
+func messages(n int) []interface{} {
+	var ret []interface{}
+	for i := 0; i < n; i++ {
+		v := float64(i)
+		ret = append(ret, []senml.Message{{Channel: "channel", Name: "temperature", Time: float64(i), Value: &v}})
+	}
+	return ret
+}
+
+func TestConsume(t *testing.T) {
+	c := &consumer{}
+	b, err := spill.New(c, newConfig(t), logger.NewMock())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer b.Close()
+
+	msgs := messages(5)
+	err = b.Consume(msgs[0])
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, 0, b.Pending(), fmt.Sprintf("expected no pending writes got %d", b.Pending()))
+
+	c.setDown(true)
+	for _, m := range msgs[1:3] {
+		err = b.Consume(m)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	assert.Equal(t, 2, b.Pending(), fmt.Sprintf("expected 2 pending writes got %d", b.Pending()))
+	assert.True(t, b.Size() > 0, "expected non-empty buffer")
+
+	// New messages are buffered after the pending ones to keep the order.
+	c.setDown(false)
+	for _, m := range msgs[3:] {
+		err = b.Consume(m)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	waitDrained(t, b)
+	assert.Equal(t, msgs, c.written(), fmt.Sprintf("expected writes %v got %v", msgs, c.written()))
+	assert.Equal(t, int64(0), b.Size(), fmt.Sprintf("expected empty buffer got %d bytes", b.Size()))
+}
+
+func TestConsumeJSON(t *testing.T) {
+	c := &consumer{down: true}
+	b, err := spill.New(c, newConfig(t), logger.NewMock())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer b.Close()
+
+	msgs := mfjson.Messages{
+		Format: "format",
+		Data: []mfjson.Message{
+			{
+				Channel:   "channel",
+				Created:   time.Now().UnixNano(),
+				Publisher: "publisher",
+				Protocol:  "http",
+				Payload:   map[string]interface{}{"temperature": 21.5},
+			},
+		},
+	}
+	err = b.Consume(msgs)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	c.setDown(false)
+	waitDrained(t, b)
+	expected := []interface{}{msgs}
+	assert.Equal(t, expected, c.written(), fmt.Sprintf("expected writes %v got %v", expected, c.written()))
+}
+
+func TestConsumeFull(t *testing.T) {
+	cfg := newConfig(t)
+	cfg.MaxSize = 100
+	c := &consumer{down: true}
+	b, err := spill.New(c, cfg, logger.NewMock())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer b.Close()
+
+	msgs := messages(3)
+	err = b.Consume(msgs[0])
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = b.Consume(msgs[1])
+	assert.True(t, errors.Contains(err, spill.ErrFull), fmt.Sprintf("expected error %s got %s", spill.ErrFull, err))
+	assert.True(t, errors.Contains(err, errDown), fmt.Sprintf("expected error %s got %s", errDown, err))
+	assert.Equal(t, 1, b.Pending(), fmt.Sprintf("expected 1 pending write got %d", b.Pending()))
+
+	// Messages that don't fit are written directly once the database recovers.
+	c.setDown(false)
+	err = b.Consume(msgs[2])
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	waitDrained(t, b)
+	expected := []interface{}{msgs[0], msgs[2]}
+	assert.ElementsMatch(t, expected, c.written(), fmt.Sprintf("expected writes %v got %v", expected, c.written()))
+}
+
+func TestReopen(t *testing.T) {
+	cfg := newConfig(t)
+	c := &consumer{down: true}
+	b, err := spill.New(c, cfg, logger.NewMock())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	msgs := messages(3)
+	for _, m := range msgs {
+		err = b.Consume(m)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	err = b.Close()
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// Simulate the crash while appending the record.
+	f, err := os.OpenFile(cfg.Path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = f.WriteString(`{"senml":[{"channel":`)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	f.Close()
+
+	c.setDown(false)
+	b, err = spill.New(c, cfg, logger.NewMock())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer b.Close()
+
+	waitDrained(t, b)
+	assert.Equal(t, msgs, c.written(), fmt.Sprintf("expected writes %v got %v", msgs, c.written()))
+}
+
+// poisonConsumer fails to write the messages published at the poison time.
+type poisonConsumer struct {
+	consumer
+	poison float64
+}
+
+func (pc *poisonConsumer) Consume(msgs interface{}) error {
+	if m, ok := msgs.([]senml.Message); ok && len(m) > 0 && m[0].Time == pc.poison {
+		return errDown
+	}
+	return pc.consumer.Consume(msgs)
+}
+
+func TestDeadLetter(t *testing.T) {
+	cfg := newConfig(t)
+	cfg.MaxAttempts = 3
+	c := &poisonConsumer{consumer: consumer{down: true}, poison: 1}
+	b, err := spill.New(c, cfg, logger.NewMock())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer b.Close()
+
+	msgs := messages(3)
+	for _, m := range msgs {
+		err = b.Consume(m)
+		assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	// The poison record is moved to the dead-letter file, and doesn't block
+	// the records buffered after it.
+	c.setDown(false)
+	waitDrained(t, b)
+	expected := []interface{}{msgs[0], msgs[2]}
+	assert.Equal(t, expected, c.written(), fmt.Sprintf("expected writes %v got %v", expected, c.written()))
+
+	data, err := ioutil.ReadFile(cfg.Path + ".dead")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Contains(t, string(data), `"time":1`, "expected poison record in dead-letter file")
+}
@@ -0,0 +1,7 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package spill provides the consumer decorator that buffers the messages
+// which failed to be written on the local disk, and writes them again once
+// the database recovers.
+package spill
@@ -10,6 +10,7 @@ import (
 
 	"github.com/go-kit/kit/metrics"
 	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/consumers/batch"
 )
 
 var _ consumers.Consumer = (*metricsMiddleware)(nil)
@@ -37,3 +38,29 @@ func (mm *metricsMiddleware) Consume(msgs interface{}) error {
 	}(time.Now())
 	return mm.consumer.Consume(msgs)
 }
+
+var _ consumers.Consumer = (*batchMetricsMiddleware)(nil)
+
+type batchMetricsMiddleware struct {
+	size     metrics.Histogram
+	latency  metrics.Histogram
+	consumer consumers.Consumer
+}
+
+// BatchMetricsMiddleware returns new consumer with Consume method wrapped to
+// expose the size and the flush latency of the written batches.
+func BatchMetricsMiddleware(consumer consumers.Consumer, size, latency metrics.Histogram) consumers.Consumer {
+	return &batchMetricsMiddleware{
+		size:     size,
+		latency:  latency,
+		consumer: consumer,
+	}
+}
+
+func (bm *batchMetricsMiddleware) Consume(msgs interface{}) error {
+	defer func(begin time.Time) {
+		bm.size.With("method", "flush").Observe(float64(batch.Size(msgs)))
+		bm.latency.With("method", "flush").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	return bm.consumer.Consume(msgs)
+}
@@ -14,6 +14,10 @@ import (
 	"github.com/mainflux/mainflux/pkg/transformers/senml"
 )
 
+// maxBatchQueries is the maximal number of inserts sent in the single batch,
+// which keeps the batch below the Cassandra batch size limit.
+const maxBatchQueries = 100
+
 var (
 	errSaveMessage = errors.New("failed to save message to cassandra database")
 	errNoTable     = errors.New("table does not exist")
@@ -47,15 +51,20 @@ func (cr *cassandraRepository) saveSenml(messages interface{}) error {
             name, unit, value, string_value, bool_value, data_value, sum,
             time, update_time)
             VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	id := gocql.TimeUUID()
 
-	for _, msg := range msgs {
-		err := cr.session.Query(cql, id, msg.Channel, msg.Subtopic, msg.Publisher,
+	batch := cr.session.NewBatch(gocql.UnloggedBatch)
+	for i, msg := range msgs {
+		batch.Query(cql, gocql.TimeUUID(), msg.Channel, msg.Subtopic, msg.Publisher,
 			msg.Protocol, msg.Name, msg.Unit, msg.Value, msg.StringValue,
-			msg.BoolValue, msg.DataValue, msg.Sum, msg.Time, msg.UpdateTime).Exec()
-		if err != nil {
+			msg.BoolValue, msg.DataValue, msg.Sum, msg.Time, msg.UpdateTime)
+		if batch.Size() < maxBatchQueries && i < len(msgs)-1 {
+			continue
+		}
+
+		if err := cr.session.ExecuteBatch(batch); err != nil {
 			return errors.Wrap(errSaveMessage, err)
 		}
+		batch = cr.session.NewBatch(gocql.UnloggedBatch)
 	}
 
 	return nil
@@ -75,22 +84,27 @@ func (cr *cassandraRepository) saveJSON(msgs mfjson.Messages) error {
 }
 
 func (cr *cassandraRepository) insertJSON(msgs mfjson.Messages) error {
-	for _, msg := range msgs.Data {
+	cql := `INSERT INTO %s (id, channel, created, subtopic, publisher, protocol, payload) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	cql = fmt.Sprintf(cql, msgs.Format)
+
+	batch := cr.session.NewBatch(gocql.UnloggedBatch)
+	for i, msg := range msgs.Data {
 		pld, err := json.Marshal(msg.Payload)
 		if err != nil {
 			return err
 		}
-		cql := `INSERT INTO %s (id, channel, created, subtopic, publisher, protocol, payload) VALUES (?, ?, ?, ?, ?, ?, ?)`
-		cql = fmt.Sprintf(cql, msgs.Format)
-		id := gocql.TimeUUID()
+		batch.Query(cql, gocql.TimeUUID(), msg.Channel, msg.Created, msg.Subtopic, msg.Publisher, msg.Protocol, string(pld))
+		if batch.Size() < maxBatchQueries && i < len(msgs.Data)-1 {
+			continue
+		}
 
-		err = cr.session.Query(cql, id, msg.Channel, msg.Created, msg.Subtopic, msg.Publisher, msg.Protocol, string(pld)).Exec()
-		if err != nil {
+		if err := cr.session.ExecuteBatch(batch); err != nil {
 			if err.Error() == fmt.Sprintf("unconfigured table %s", msgs.Format) {
 				return errNoTable
 			}
 			return errors.Wrap(errSaveMessage, err)
 		}
+		batch = cr.session.NewBatch(gocql.UnloggedBatch)
 	}
 	return nil
 }
@@ -0,0 +1,385 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package influxdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	influxdata "github.com/influxdata/influxdb/client/v2"
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// policyPoints is the measurement of the retention policies. Every policy
+// is a single point at the Unix epoch, so that saving the policy again
+// overwrites it.
+const policyPoints = "retention_policies"
+
+var (
+	errSetup  = errors.New("failed to set up continuous queries")
+	errDelete = errors.New("failed to delete expired messages")
+	errRemove = errors.New("failed to remove continuous queries")
+)
+
+var _ retention.PolicyRepository = (*policyRepository)(nil)
+
+type policyRepository struct {
+	client   influxdata.Client
+	database string
+}
+
+// NewPolicyRepository returns new InfluxDB retention policy repository.
+func NewPolicyRepository(client influxdata.Client, database string) retention.PolicyRepository {
+	return &policyRepository{
+		client:   client,
+		database: database,
+	}
+}
+
+func (pr *policyRepository) Save(_ context.Context, p retention.Policy) error {
+	tiers, err := json.Marshal(p.Tiers)
+	if err != nil {
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	var applied int64
+	if !p.Applied.IsZero() {
+		applied = p.Applied.UnixNano()
+	}
+	flds := fields{
+		"owner":   p.Owner,
+		"tiers":   string(tiers),
+		"applied": applied,
+		"updated": p.Updated.UnixNano(),
+	}
+	if err := pr.write(p.Channel, flds); err != nil {
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (pr *policyRepository) RetrieveByChannel(_ context.Context, chanID string) (retention.Policy, error) {
+	policies, err := pr.retrieve(fmt.Sprintf(`SELECT * FROM %s WHERE channel='%s'`, policyPoints, chanID))
+	if err != nil {
+		return retention.Policy{}, err
+	}
+	if len(policies) == 0 {
+		return retention.Policy{}, errors.ErrNotFound
+	}
+
+	return policies[0], nil
+}
+
+func (pr *policyRepository) RetrieveAll(_ context.Context) ([]retention.Policy, error) {
+	return pr.retrieve(fmt.Sprintf(`SELECT * FROM %s`, policyPoints))
+}
+
+func (pr *policyRepository) UpdateApplied(ctx context.Context, p retention.Policy) error {
+	cur, err := pr.RetrieveByChannel(ctx, p.Channel)
+	if err != nil {
+		if errors.Contains(err, errors.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !cur.Updated.Equal(p.Updated) {
+		return nil
+	}
+
+	if err := pr.write(p.Channel, fields{"applied": p.Applied.UnixNano()}); err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	return nil
+}
+
+func (pr *policyRepository) Remove(_ context.Context, chanID string) error {
+	if err := query(pr.client, pr.database, fmt.Sprintf(`DELETE FROM %s WHERE channel='%s'`, policyPoints, chanID)); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
+func (pr *policyRepository) write(chanID string, flds fields) error {
+	pts, err := influxdata.NewBatchPoints(influxdata.BatchPointsConfig{Database: pr.database})
+	if err != nil {
+		return err
+	}
+	pt, err := influxdata.NewPoint(policyPoints, tags{"channel": chanID}, flds, time.Unix(0, 0))
+	if err != nil {
+		return err
+	}
+	pts.AddPoint(pt)
+
+	return pr.client.Write(pts)
+}
+
+func (pr *policyRepository) retrieve(cmd string) ([]retention.Policy, error) {
+	resp, err := pr.client.Query(influxdata.Query{Command: cmd, Database: pr.database})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	if resp.Error() != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, resp.Error())
+	}
+	if len(resp.Results) == 0 || len(resp.Results[0].Series) == 0 {
+		return nil, nil
+	}
+
+	var policies []retention.Policy
+	series := resp.Results[0].Series[0]
+	for _, v := range series.Values {
+		p, err := parsePolicy(series.Columns, v)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		policies = append(policies, p)
+	}
+
+	return policies, nil
+}
+
+func parsePolicy(names []string, values []interface{}) (retention.Policy, error) {
+	var p retention.Policy
+	for i, name := range names {
+		switch name {
+		case "channel":
+			p.Channel, _ = values[i].(string)
+		case "owner":
+			p.Owner, _ = values[i].(string)
+		case "tiers":
+			s, _ := values[i].(string)
+			if err := json.Unmarshal([]byte(s), &p.Tiers); err != nil {
+				return retention.Policy{}, err
+			}
+		case "applied", "updated":
+			n, err := parseInt(values[i])
+			if err != nil {
+				return retention.Policy{}, err
+			}
+			t := time.Unix(0, n)
+			if name == "updated" {
+				p.Updated = t
+				continue
+			}
+			if n != 0 {
+				p.Applied = t
+			}
+		}
+	}
+
+	return p, nil
+}
+
+func parseInt(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case json.Number:
+		return n.Int64()
+	case string:
+		return strconv.ParseInt(n, 10, 64)
+	default:
+		return 0, nil
+	}
+}
+
+var _ retention.Store = (*retentionStore)(nil)
+
+type retentionStore struct {
+	client   influxdata.Client
+	database string
+}
+
+// NewRetentionStore returns new InfluxDB store the retention policies are
+// applied with. Messages of every channel are downsampled by the continuous
+// queries into the measurements named after their resolution, e.g.
+// "messages_60", kept in the retention policies named after the channel
+// and the resolution, e.g. "messages_60_<channel_id_hex>". Raw messages of
+// all the channels share the default retention policy, so the raw tier is
+// enforced by removing the expired raw messages of the channel every time
+// the policy is applied.
+func NewRetentionStore(client influxdata.Client, database string) retention.Store {
+	return &retentionStore{
+		client:   client,
+		database: database,
+	}
+}
+
+func (rs *retentionStore) Apply(_ context.Context, p retention.Policy, now time.Time) error {
+	if p.Applied.IsZero() {
+		if err := rs.setup(p); err != nil {
+			return errors.Wrap(errSetup, err)
+		}
+	}
+
+	raw, ok := p.Raw()
+	if !ok {
+		return nil
+	}
+	cmd := fmt.Sprintf(`DELETE FROM %s WHERE channel='%s' AND time < %d`, senmlPoints, p.Channel, now.Add(-raw.Retention).UnixNano())
+	if err := query(rs.client, rs.database, cmd); err != nil {
+		return errors.Wrap(errDelete, err)
+	}
+
+	return nil
+}
+
+func (rs *retentionStore) Remove(_ context.Context, p retention.Policy) error {
+	if err := rs.dropQueries(p.Channel); err != nil {
+		return errors.Wrap(errRemove, err)
+	}
+
+	rps, err := rs.retentionPolicies(p.Channel)
+	if err != nil {
+		return errors.Wrap(errRemove, err)
+	}
+	for rp := range rps {
+		if err := query(rs.client, rs.database, fmt.Sprintf(`DROP RETENTION POLICY "%s" ON "%s"`, rp, rs.database)); err != nil {
+			return errors.Wrap(errRemove, err)
+		}
+	}
+
+	return nil
+}
+
+// setup creates or alters the retention policies of the policy resolutions,
+// drops the retention policies of the resolutions removed from the policy,
+// and recreates the continuous queries of the channel. Messages published
+// before the continuous queries are created are downsampled once.
+func (rs *retentionStore) setup(p retention.Policy) error {
+	if err := rs.dropQueries(p.Channel); err != nil {
+		return err
+	}
+
+	existing, err := rs.retentionPolicies(p.Channel)
+	if err != nil {
+		return err
+	}
+	rps := make(map[string]retention.Tier)
+	for _, t := range p.Tiers {
+		if t.Resolution != retention.Raw {
+			rps[retention.ChannelTable(p.Channel, t.Resolution)] = t
+		}
+	}
+	for rp := range existing {
+		if _, ok := rps[rp]; ok {
+			continue
+		}
+		if err := query(rs.client, rs.database, fmt.Sprintf(`DROP RETENTION POLICY "%s" ON "%s"`, rp, rs.database)); err != nil {
+			return err
+		}
+	}
+
+	for rp, t := range rps {
+		cmd := `CREATE RETENTION POLICY "%s" ON "%s" DURATION %ds REPLICATION 1`
+		if existing[rp] {
+			cmd = `ALTER RETENTION POLICY "%s" ON "%s" DURATION %ds`
+		}
+		if err := query(rs.client, rs.database, fmt.Sprintf(cmd, rp, rs.database, rpDuration(t.Retention)/time.Second)); err != nil {
+			return err
+		}
+
+		// The last bucket is resampled on the next run, to include the
+		// messages that arrived late.
+		sec := int64(t.Resolution / time.Second)
+		sel := fmt.Sprintf(`SELECT MEAN(value) AS value, LAST(stringValue) AS stringValue, LAST(boolValue) AS boolValue,
+            LAST(dataValue) AS dataValue, LAST(protocol) AS protocol, LAST(unit) AS unit
+            INTO "%s"."%s"."%s" FROM "%s".."%s" WHERE channel='%s'`,
+			rs.database, rp, retention.Table(t.Resolution), rs.database, senmlPoints, p.Channel)
+		cmd = fmt.Sprintf(`CREATE CONTINUOUS QUERY "%s" ON "%s" RESAMPLE FOR %ds BEGIN %s GROUP BY time(%ds), * END`,
+			rp, rs.database, 2*sec, sel, sec)
+		if err := query(rs.client, rs.database, cmd); err != nil {
+			return err
+		}
+
+		cmd = fmt.Sprintf(`%s AND time >= now() - %ds GROUP BY time(%ds), * fill(none)`, sel, rpDuration(t.Retention)/time.Second, sec)
+		if err := query(rs.client, rs.database, cmd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// dropQueries drops the continuous queries of the channel.
+func (rs *retentionStore) dropQueries(chanID string) error {
+	resp, err := rs.client.Query(influxdata.Query{Command: "SHOW CONTINUOUS QUERIES", Database: rs.database})
+	if err != nil {
+		return err
+	}
+	if resp.Error() != nil {
+		return resp.Error()
+	}
+
+	suffix := retention.ChannelSuffix(chanID)
+	for _, res := range resp.Results {
+		for _, series := range res.Series {
+			if series.Name != rs.database {
+				continue
+			}
+			for _, v := range series.Values {
+				name, _ := v[0].(string)
+				if !strings.HasSuffix(name, suffix) {
+					continue
+				}
+				if err := query(rs.client, rs.database, fmt.Sprintf(`DROP CONTINUOUS QUERY "%s" ON "%s"`, name, rs.database)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// retentionPolicies returns the set of the retention policies of the channel.
+func (rs *retentionStore) retentionPolicies(chanID string) (map[string]bool, error) {
+	resp, err := rs.client.Query(influxdata.Query{Command: fmt.Sprintf(`SHOW RETENTION POLICIES ON "%s"`, rs.database)})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error() != nil {
+		return nil, resp.Error()
+	}
+
+	suffix := retention.ChannelSuffix(chanID)
+	rps := make(map[string]bool)
+	for _, res := range resp.Results {
+		for _, series := range res.Series {
+			for _, v := range series.Values {
+				name, _ := v[0].(string)
+				if strings.HasSuffix(name, suffix) {
+					rps[name] = true
+				}
+			}
+		}
+	}
+
+	return rps, nil
+}
+
+// rpDuration returns the duration of the retention policy keeping the
+// messages for the given period. InfluxDB retention policies keep the
+// messages for at least an hour.
+func rpDuration(d time.Duration) time.Duration {
+	if d < time.Hour {
+		return time.Hour
+	}
+	return d
+}
+
+func query(client influxdata.Client, database, cmd string) error {
+	resp, err := client.Query(influxdata.Query{Command: cmd, Database: database})
+	if err != nil {
+		return err
+	}
+
+	return resp.Error()
+}
@@ -0,0 +1,111 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package influxdb_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	influxdata "github.com/influxdata/influxdb/client/v2"
+	writer "github.com/mainflux/mainflux/consumers/writers/influxdb"
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const day = 24 * time.Hour
+
+func TestPolicyRepository(t *testing.T) {
+	repo := writer.NewPolicyRepository(client, testDB)
+
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	p := retention.Policy{
+		Channel: chid.String(),
+		Owner:   "owner",
+		Tiers:   []retention.Tier{{Resolution: retention.Raw, Retention: time.Hour}, {Resolution: time.Minute, Retention: day}},
+		Updated: time.Now().UTC().Truncate(time.Millisecond),
+	}
+	err = repo.Save(context.Background(), p)
+	assert.Nil(t, err, fmt.Sprintf("save policy: unexpected error: %s", err))
+
+	saved, err := repo.RetrieveByChannel(context.Background(), p.Channel)
+	assert.Nil(t, err, fmt.Sprintf("retrieve policy: unexpected error: %s", err))
+	assert.Equal(t, p.Tiers, saved.Tiers, fmt.Sprintf("retrieve policy: expected tiers %v got %v\n", p.Tiers, saved.Tiers))
+	assert.True(t, saved.Applied.IsZero(), fmt.Sprintf("retrieve policy: expected zero applied time got %s\n", saved.Applied))
+
+	p.Applied = time.Now().UTC().Truncate(time.Millisecond)
+	err = repo.UpdateApplied(context.Background(), p)
+	assert.Nil(t, err, fmt.Sprintf("update applied time: unexpected error: %s", err))
+
+	saved, err = repo.RetrieveByChannel(context.Background(), p.Channel)
+	assert.Nil(t, err, fmt.Sprintf("retrieve applied policy: unexpected error: %s", err))
+	assert.True(t, p.Applied.Equal(saved.Applied), fmt.Sprintf("retrieve applied policy: expected applied time %s got %s\n", p.Applied, saved.Applied))
+
+	err = repo.Remove(context.Background(), p.Channel)
+	assert.Nil(t, err, fmt.Sprintf("remove policy: unexpected error: %s", err))
+
+	_, err = repo.RetrieveByChannel(context.Background(), p.Channel)
+	assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("retrieve removed policy: expected %s got %s\n", errors.ErrNotFound, err))
+}
+
+func TestRetentionStore(t *testing.T) {
+	repo := writer.New(client, testDB)
+	store := writer.NewRetentionStore(client, testDB)
+
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	// Messages are downsampled once when the continuous queries are
+	// created, if they're still kept by the retention policy.
+	bucket := time.Now().Add(-time.Hour).Truncate(time.Minute)
+	low, high := 2.0, 4.0
+	msgs := []senml.Message{
+		{Channel: chid.String(), Publisher: "pub", Name: "temp", Time: float64(bucket.Unix() + 10), Value: &low},
+		{Channel: chid.String(), Publisher: "pub", Name: "temp", Time: float64(bucket.Unix() + 20), Value: &high},
+		{Channel: chid.String(), Publisher: "pub", Name: "temp", Time: float64(bucket.Unix() + 30), StringValue: &stringV},
+	}
+	err = repo.Consume(msgs)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	p := retention.Policy{
+		Channel: chid.String(),
+		Tiers:   []retention.Tier{{Resolution: time.Minute, Retention: day}},
+	}
+	err = store.Apply(context.Background(), p, time.Now())
+	assert.Nil(t, err, fmt.Sprintf("apply policy: unexpected error: %s", err))
+
+	rp := retention.ChannelTable(chid.String(), time.Minute)
+	rows, err := queryDB(fmt.Sprintf(`SELECT value, stringValue FROM "%s"."%s"."messages_60" WHERE time = %d`, testDB, rp, bucket.UnixNano()))
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	require.Len(t, rows, 1, "apply policy: expected single downsampled message")
+	assert.Equal(t, "3", fmt.Sprint(rows[0][1]), fmt.Sprintf("apply policy: expected average value 3 got %v\n", rows[0][1]))
+	assert.Equal(t, stringV, rows[0][2], fmt.Sprintf("apply policy: expected string value %s got %v\n", stringV, rows[0][2]))
+
+	cqs, err := client.Query(influxdata.Query{Command: "SHOW CONTINUOUS QUERIES"})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Contains(t, fmt.Sprint(cqs.Results), rp, "apply policy: expected continuous query created")
+
+	// Raw tier is added to the policy saved again.
+	p.Tiers = append(p.Tiers, retention.Tier{Resolution: retention.Raw, Retention: time.Hour})
+	err = store.Apply(context.Background(), p, bucket.Add(2*time.Hour))
+	assert.Nil(t, err, fmt.Sprintf("apply policy with raw tier: unexpected error: %s", err))
+
+	rows, err = queryDB(fmt.Sprintf(`SELECT * FROM messages WHERE channel='%s'`, chid.String()))
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Empty(t, rows, "apply policy with raw tier: expected expired raw messages removed")
+
+	err = store.Remove(context.Background(), p)
+	assert.Nil(t, err, fmt.Sprintf("remove policy: unexpected error: %s", err))
+
+	rps, err := queryDB(fmt.Sprintf(`SHOW RETENTION POLICIES ON "%s"`, testDB))
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.NotContains(t, fmt.Sprint(rps), rp, "remove policy: expected retention policy dropped")
+}
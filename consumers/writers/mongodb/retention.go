@@ -0,0 +1,226 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const policiesCollection = "retention_policies"
+
+var (
+	errDownsample = errors.New("failed to downsample messages")
+	errDelete     = errors.New("failed to delete expired messages")
+)
+
+var _ retention.PolicyRepository = (*policyRepository)(nil)
+
+type policyRepository struct {
+	db *mongo.Database
+}
+
+// NewPolicyRepository returns new MongoDB retention policy repository.
+func NewPolicyRepository(db *mongo.Database) retention.PolicyRepository {
+	return &policyRepository{db}
+}
+
+func (pr *policyRepository) Save(ctx context.Context, p retention.Policy) error {
+	coll := pr.db.Collection(policiesCollection)
+	opts := options.Replace().SetUpsert(true)
+	if _, err := coll.ReplaceOne(ctx, bson.M{"_id": p.Channel}, toDBPolicy(p), opts); err != nil {
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (pr *policyRepository) RetrieveByChannel(ctx context.Context, chanID string) (retention.Policy, error) {
+	var dbp dbPolicy
+	if err := pr.db.Collection(policiesCollection).FindOne(ctx, bson.M{"_id": chanID}).Decode(&dbp); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return retention.Policy{}, errors.ErrNotFound
+		}
+		return retention.Policy{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	return toPolicy(dbp), nil
+}
+
+func (pr *policyRepository) RetrieveAll(ctx context.Context) ([]retention.Policy, error) {
+	opts := options.Find().SetSort(bson.M{"_id": 1})
+	cursor, err := pr.db.Collection(policiesCollection).Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer cursor.Close(ctx)
+
+	var policies []retention.Policy
+	for cursor.Next(ctx) {
+		var dbp dbPolicy
+		if err := cursor.Decode(&dbp); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		policies = append(policies, toPolicy(dbp))
+	}
+
+	return policies, nil
+}
+
+func (pr *policyRepository) UpdateApplied(ctx context.Context, p retention.Policy) error {
+	filter := bson.M{"_id": p.Channel, "updated": p.Updated}
+	update := bson.M{"$set": bson.M{"applied": p.Applied}}
+	if _, err := pr.db.Collection(policiesCollection).UpdateOne(ctx, filter, update); err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	return nil
+}
+
+func (pr *policyRepository) Remove(ctx context.Context, chanID string) error {
+	if _, err := pr.db.Collection(policiesCollection).DeleteOne(ctx, bson.M{"_id": chanID}); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
+type dbTier struct {
+	Resolution time.Duration `bson:"resolution"`
+	Retention  time.Duration `bson:"retention"`
+}
+
+type dbPolicy struct {
+	Channel string     `bson:"_id"`
+	Owner   string     `bson:"owner"`
+	Tiers   []dbTier   `bson:"tiers"`
+	Applied *time.Time `bson:"applied,omitempty"`
+	Updated time.Time  `bson:"updated"`
+}
+
+func toDBPolicy(p retention.Policy) dbPolicy {
+	dbp := dbPolicy{
+		Channel: p.Channel,
+		Owner:   p.Owner,
+		Updated: p.Updated,
+	}
+	if !p.Applied.IsZero() {
+		dbp.Applied = &p.Applied
+	}
+	for _, t := range p.Tiers {
+		dbp.Tiers = append(dbp.Tiers, dbTier(t))
+	}
+
+	return dbp
+}
+
+func toPolicy(dbp dbPolicy) retention.Policy {
+	p := retention.Policy{
+		Channel: dbp.Channel,
+		Owner:   dbp.Owner,
+		Updated: dbp.Updated,
+	}
+	if dbp.Applied != nil {
+		p.Applied = *dbp.Applied
+	}
+	for _, t := range dbp.Tiers {
+		p.Tiers = append(p.Tiers, retention.Tier(t))
+	}
+
+	return p
+}
+
+var _ retention.Compactor = (*compactor)(nil)
+
+type compactor struct {
+	db *mongo.Database
+}
+
+// NewRetentionStore returns new MongoDB store the retention policies are
+// applied with. Messages are compacted by the writer into the collections
+// named after their resolution, e.g. "messages_60", using the aggregation
+// pipeline merging the time buckets into the collection. It requires
+// MongoDB 4.2 or newer.
+func NewRetentionStore(db *mongo.Database) retention.Store {
+	return retention.NewCompactionStore(&compactor{db})
+}
+
+func (c *compactor) Downsample(ctx context.Context, chanID string, res time.Duration, from, to time.Time) error {
+	sec := float64(res / time.Second)
+	// Bucket start is the message time rounded down to the resolution.
+	bucket := bson.M{"$subtract": bson.A{"$time", bson.M{"$mod": bson.A{"$time", sec}}}}
+	// Downsampled message ID is the bucket key, so that downsampling the
+	// same period again replaces the bucket.
+	key := bson.M{
+		"channel":   "$channel",
+		"subtopic":  "$subtopic",
+		"publisher": "$publisher",
+		"protocol":  "$protocol",
+		"name":      "$name",
+		"unit":      "$unit",
+		"time":      bucket,
+	}
+	// Messages are sorted by time, so that the non-numeric values of the
+	// bucket are the last ones published.
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"channel": chanID,
+			"time":    bson.M{"$gte": float64(from.Unix()), "$lt": float64(to.Unix())},
+		}}},
+		{{Key: "$sort", Value: bson.M{"time": 1}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          key,
+			"value":        bson.M{"$avg": "$value"},
+			"string_value": bson.M{"$push": "$string_value"},
+			"bool_value":   bson.M{"$push": "$bool_value"},
+			"data_value":   bson.M{"$push": "$data_value"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"channel":      "$_id.channel",
+			"subtopic":     "$_id.subtopic",
+			"publisher":    "$_id.publisher",
+			"protocol":     "$_id.protocol",
+			"name":         "$_id.name",
+			"unit":         "$_id.unit",
+			"time":         "$_id.time",
+			"value":        1,
+			"string_value": last("$string_value"),
+			"bool_value":   last("$bool_value"),
+			"data_value":   last("$data_value"),
+		}}},
+		{{Key: "$merge", Value: bson.M{"into": retention.Table(res), "whenMatched": "replace"}}},
+	}
+
+	cursor, err := c.db.Collection(senmlCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return errors.Wrap(errDownsample, err)
+	}
+
+	return cursor.Close(ctx)
+}
+
+func (c *compactor) Delete(ctx context.Context, chanID string, res time.Duration, before time.Time) error {
+	filter := bson.M{
+		"channel": chanID,
+		"time":    bson.M{"$lt": float64(before.UnixNano()) / 1e9},
+	}
+	if _, err := c.db.Collection(retention.Table(res)).DeleteMany(ctx, filter); err != nil {
+		return errors.Wrap(errDelete, err)
+	}
+
+	return nil
+}
+
+// last returns the last non-null element of the array. The field is left
+// out if there's no such element.
+func last(array string) bson.M {
+	values := bson.M{"$filter": bson.M{"input": array, "cond": bson.M{"$ne": bson.A{"$$this", nil}}}}
+	return bson.M{"$arrayElemAt": bson.A{values, -1}}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/consumers/writers/mongodb"
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const day = 24 * time.Hour
+
+// bucket is the start of the minute bucket the retention tests publish to.
+var bucket = time.Unix(1599998400, 0)
+
+func connectDB(t *testing.T) *mongo.Database {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(addr))
+	require.Nil(t, err, fmt.Sprintf("Creating new MongoDB client expected to succeed: %s.\n", err))
+	return client.Database(testDB)
+}
+
+func TestPolicyRepository(t *testing.T) {
+	repo := mongodb.NewPolicyRepository(connectDB(t))
+
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	p := retention.Policy{
+		Channel: chid.String(),
+		Owner:   "owner",
+		Tiers:   []retention.Tier{{Resolution: retention.Raw, Retention: time.Hour}, {Resolution: time.Minute, Retention: day}},
+		Updated: time.Now().UTC().Truncate(time.Millisecond),
+	}
+	err = repo.Save(context.Background(), p)
+	assert.Nil(t, err, fmt.Sprintf("save policy: unexpected error: %s", err))
+
+	saved, err := repo.RetrieveByChannel(context.Background(), p.Channel)
+	assert.Nil(t, err, fmt.Sprintf("retrieve policy: unexpected error: %s", err))
+	assert.Equal(t, p.Tiers, saved.Tiers, fmt.Sprintf("retrieve policy: expected tiers %v got %v\n", p.Tiers, saved.Tiers))
+	assert.True(t, saved.Applied.IsZero(), fmt.Sprintf("retrieve policy: expected zero applied time got %s\n", saved.Applied))
+
+	p.Applied = time.Now().UTC().Truncate(time.Millisecond)
+	err = repo.UpdateApplied(context.Background(), p)
+	assert.Nil(t, err, fmt.Sprintf("update applied time: unexpected error: %s", err))
+
+	saved, err = repo.RetrieveByChannel(context.Background(), p.Channel)
+	assert.Nil(t, err, fmt.Sprintf("retrieve applied policy: unexpected error: %s", err))
+	assert.True(t, p.Applied.Equal(saved.Applied), fmt.Sprintf("retrieve applied policy: expected applied time %s got %s\n", p.Applied, saved.Applied))
+
+	err = repo.Remove(context.Background(), p.Channel)
+	assert.Nil(t, err, fmt.Sprintf("remove policy: unexpected error: %s", err))
+
+	_, err = repo.RetrieveByChannel(context.Background(), p.Channel)
+	assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("retrieve removed policy: expected %s got %s\n", errors.ErrNotFound, err))
+}
+
+func TestRetentionStore(t *testing.T) {
+	db := connectDB(t)
+	repo := mongodb.New(db)
+	store := mongodb.NewRetentionStore(db)
+
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	low, high := 2.0, 4.0
+	msgs := []senml.Message{
+		{Channel: chid.String(), Publisher: "pub", Name: "temp", Time: float64(bucket.Unix() + 10), Value: &low},
+		{Channel: chid.String(), Publisher: "pub", Name: "temp", Time: float64(bucket.Unix() + 20), Value: &high},
+		{Channel: chid.String(), Publisher: "pub", Name: "temp", Time: float64(bucket.Unix() + 30), StringValue: &stringV},
+	}
+	err = repo.Consume(msgs)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	p := retention.Policy{
+		Channel: chid.String(),
+		Tiers:   []retention.Tier{{Resolution: retention.Raw, Retention: time.Hour}, {Resolution: time.Minute, Retention: 1000 * day}},
+	}
+	err = store.Apply(context.Background(), p, bucket.Add(2*time.Hour))
+	assert.Nil(t, err, fmt.Sprintf("apply policy: unexpected error: %s", err))
+
+	var rows []senml.Message
+	filter := bson.M{"channel": chid.String(), "time": float64(bucket.Unix())}
+	cursor, err := db.Collection("messages_60").Find(context.Background(), filter)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = cursor.All(context.Background(), &rows)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	require.Len(t, rows, 1, "apply policy: expected single downsampled message")
+	require.NotNil(t, rows[0].Value, "apply policy: expected average value")
+	assert.Equal(t, 3.0, *rows[0].Value, fmt.Sprintf("apply policy: expected average value 3 got %f\n", *rows[0].Value))
+	require.NotNil(t, rows[0].StringValue, "apply policy: expected string value")
+	assert.Equal(t, stringV, *rows[0].StringValue, fmt.Sprintf("apply policy: expected string value %s got %s\n", stringV, *rows[0].StringValue))
+	assert.Nil(t, rows[0].BoolValue, "apply policy: unexpected bool value")
+
+	raw, err := db.Collection(collection).CountDocuments(context.Background(), bson.M{"channel": chid.String()})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, int64(0), raw, fmt.Sprintf("apply policy: expected expired raw messages removed got %d\n", raw))
+
+	err = store.Remove(context.Background(), p)
+	assert.Nil(t, err, fmt.Sprintf("remove policy: unexpected error: %s", err))
+
+	downsampled, err := db.Collection("messages_60").CountDocuments(context.Background(), bson.M{"channel": chid.String()})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, int64(0), downsampled, fmt.Sprintf("remove policy: expected downsampled messages removed got %d\n", downsampled))
+}
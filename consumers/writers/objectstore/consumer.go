@@ -0,0 +1,190 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/pkg/errors"
+	mfjson "github.com/mainflux/mainflux/pkg/transformers/json"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+)
+
+const (
+	// Parquet is the format of the Parquet files.
+	Parquet = "parquet"
+
+	// NDJSON is the format of the gzipped newline delimited JSON files.
+	NDJSON = "ndjson"
+
+	senmlFormat = "messages"
+	dateLayout  = "2006-01-02"
+)
+
+var (
+	// ErrInvalidFormat indicates unsupported file format.
+	ErrInvalidFormat = errors.New("invalid file format")
+
+	errSaveMessage = errors.New("failed to save message to object storage")
+)
+
+// Bucket specifies the API of the object storage bucket the files are
+// uploaded to.
+type Bucket interface {
+	// Put uploads the object under the given key.
+	Put(ctx context.Context, key, contentType string, body []byte) error
+}
+
+// Config defines the object storage writer options.
+type Config struct {
+	// Format is the format of the uploaded files, Parquet or NDJSON.
+	Format string
+
+	// Prefix is prepended to the keys of the uploaded files.
+	Prefix string
+}
+
+type encoding struct {
+	ext         string
+	contentType string
+	senml       func([]senml.Message) ([]byte, error)
+	json        func([]mfjson.Message) ([]byte, error)
+}
+
+var encodings = map[string]encoding{
+	Parquet: {
+		ext:         ".parquet",
+		contentType: "application/vnd.apache.parquet",
+		senml:       senmlParquet,
+		json:        jsonParquet,
+	},
+	NDJSON: {
+		ext:         ".ndjson.gz",
+		contentType: "application/gzip",
+		senml:       senmlNDJSON,
+		json:        jsonNDJSON,
+	},
+}
+
+var _ consumers.Consumer = (*objectStore)(nil)
+
+type objectStore struct {
+	bucket Bucket
+	prefix string
+	enc    encoding
+}
+
+// New returns new object storage writer. Every consumed batch of messages is
+// uploaded as one file per channel and day, under the key
+// <prefix>/<format>/channel=<channel>/date=<yyyy-mm-dd>/<id><ext>, where the
+// format is "messages" for SenML messages, and the message format for JSON.
+func New(bucket Bucket, cfg Config) (consumers.Consumer, error) {
+	enc, ok := encodings[cfg.Format]
+	if !ok {
+		return nil, ErrInvalidFormat
+	}
+
+	return &objectStore{
+		bucket: bucket,
+		prefix: cfg.Prefix,
+		enc:    enc,
+	}, nil
+}
+
+func (repo *objectStore) Consume(message interface{}) error {
+	switch m := message.(type) {
+	case mfjson.Messages:
+		return repo.saveJSON(m)
+	default:
+		return repo.saveSenml(m)
+	}
+}
+
+func (repo *objectStore) saveSenml(messages interface{}) error {
+	msgs, ok := messages.([]senml.Message)
+	if !ok {
+		return errSaveMessage
+	}
+
+	var order []partition
+	parts := make(map[partition][]senml.Message)
+	for _, msg := range msgs {
+		sec, dec := math.Modf(msg.Time)
+		p := newPartition(msg.Channel, time.Unix(int64(sec), int64(dec*1e9)))
+		if _, ok := parts[p]; !ok {
+			order = append(order, p)
+		}
+		parts[p] = append(parts[p], msg)
+	}
+
+	for _, p := range order {
+		body, err := repo.enc.senml(parts[p])
+		if err != nil {
+			return errors.Wrap(errSaveMessage, err)
+		}
+		if err := repo.put(senmlFormat, p, body); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repo *objectStore) saveJSON(msgs mfjson.Messages) error {
+	var order []partition
+	parts := make(map[partition][]mfjson.Message)
+	for _, msg := range msgs.Data {
+		p := newPartition(msg.Channel, time.Unix(0, msg.Created))
+		if _, ok := parts[p]; !ok {
+			order = append(order, p)
+		}
+		parts[p] = append(parts[p], msg)
+	}
+
+	for _, p := range order {
+		body, err := repo.enc.json(parts[p])
+		if err != nil {
+			return errors.Wrap(errSaveMessage, err)
+		}
+		if err := repo.put(msgs.Format, p, body); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repo *objectStore) put(format string, p partition, body []byte) error {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return errors.Wrap(errSaveMessage, err)
+	}
+
+	name := fmt.Sprintf("%d-%s%s", time.Now().UnixNano(), id, repo.enc.ext)
+	key := path.Join(repo.prefix, format, "channel="+p.channel, "date="+p.date, name)
+	if err := repo.bucket.Put(context.Background(), key, repo.enc.contentType, body); err != nil {
+		return errors.Wrap(errSaveMessage, err)
+	}
+
+	return nil
+}
+
+// partition identifies the file the message is uploaded to.
+type partition struct {
+	channel string
+	date    string
+}
+
+func newPartition(channel string, t time.Time) partition {
+	return partition{
+		channel: channel,
+		date:    t.UTC().Format(dateLayout),
+	}
+}
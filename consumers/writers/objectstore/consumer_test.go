@@ -0,0 +1,348 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package objectstore_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mainflux/mainflux/consumers/writers/objectstore"
+	"github.com/mainflux/mainflux/pkg/errors"
+	mfjson "github.com/mainflux/mainflux/pkg/transformers/json"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+const (
+	chanID   = "chan"
+	otherID  = "other"
+	prefix   = "mainflux"
+	day      = 24 * 60 * 60
+	start    = 1600000000
+	startDay = "2020-09-13"
+	nextDay  = "2020-09-14"
+	lastDay  = "2020-09-15"
+)
+
+var errDown = errors.New("bucket is down")
+
+type object struct {
+	contentType string
+	body        []byte
+}
+
+// bucket records the uploaded objects, and fails while it's down.
+type bucket struct {
+	mu      sync.Mutex
+	down    bool
+	objects map[string]object
+}
+
+func newBucket() *bucket {
+	return &bucket{objects: make(map[string]object)}
+}
+
+func (b *bucket) Put(_ context.Context, key, contentType string, body []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.down {
+		return errDown
+	}
+	b.objects[key] = object{contentType: contentType, body: body}
+	return nil
+}
+
+// partitions returns the uploaded objects grouped by the key without the
+// file name.
+func (b *bucket) partitions() map[string][]object {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ret := make(map[string][]object)
+	for key, obj := range b.objects {
+		dir := key[:strings.LastIndex(key, "/")]
+		ret[dir] = append(ret[dir], obj)
+	}
+	return ret
+}
+
+func senmlMessages() []senml.Message {
+	v := 5.0
+	var msgs []senml.Message
+	for i, ch := range []string{chanID, otherID, chanID} {
+		msgs = append(msgs, senml.Message{
+			Channel:   ch,
+			Publisher: "publisher",
+			Protocol:  "mqtt",
+			Name:      "temperature",
+			Unit:      "Cel",
+			Time:      float64(start + i*day),
+			Value:     &v,
+		})
+	}
+	return msgs
+}
+
+func jsonMessages() mfjson.Messages {
+	var data []mfjson.Message
+	for i, ch := range []string{chanID, chanID, otherID} {
+		data = append(data, mfjson.Message{
+			Channel:   ch,
+			Created:   int64(start+i*day) * 1e9,
+			Publisher: "publisher",
+			Protocol:  "http",
+			Payload:   map[string]interface{}{"temperature": 5.0},
+		})
+	}
+	return mfjson.Messages{Format: "readings", Data: data}
+}
+
+func readNDJSON(t *testing.T, body []byte) []map[string]interface{} {
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	require.Nil(t, err, fmt.Sprintf("unexpected error reading gzip: %s", err))
+
+	var ret []map[string]interface{}
+	sc := bufio.NewScanner(zr)
+	for sc.Scan() {
+		var m map[string]interface{}
+		err := json.Unmarshal(sc.Bytes(), &m)
+		require.Nil(t, err, fmt.Sprintf("unexpected error decoding line: %s", err))
+		ret = append(ret, m)
+	}
+	return ret
+}
+
+func TestNew(t *testing.T) {
+	_, err := objectstore.New(newBucket(), objectstore.Config{Format: "csv"})
+	assert.True(t, errors.Contains(err, objectstore.ErrInvalidFormat), fmt.Sprintf("expected %s got %s", objectstore.ErrInvalidFormat, err))
+}
+
+func TestConsumeNDJSON(t *testing.T) {
+	b := newBucket()
+	repo, err := objectstore.New(b, objectstore.Config{Format: objectstore.NDJSON, Prefix: prefix})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = repo.Consume(senmlMessages())
+	assert.Nil(t, err, fmt.Sprintf("consume SenML messages: unexpected error: %s", err))
+	err = repo.Consume(jsonMessages())
+	assert.Nil(t, err, fmt.Sprintf("consume JSON messages: unexpected error: %s", err))
+
+	cases := map[string]int{
+		fmt.Sprintf("%s/messages/channel=%s/date=%s", prefix, chanID, startDay): 1,
+		fmt.Sprintf("%s/messages/channel=%s/date=%s", prefix, chanID, lastDay):  1,
+		fmt.Sprintf("%s/messages/channel=%s/date=%s", prefix, otherID, nextDay): 1,
+		fmt.Sprintf("%s/readings/channel=%s/date=%s", prefix, chanID, startDay): 1,
+		fmt.Sprintf("%s/readings/channel=%s/date=%s", prefix, chanID, nextDay):  1,
+		fmt.Sprintf("%s/readings/channel=%s/date=%s", prefix, otherID, lastDay): 1,
+	}
+
+	parts := b.partitions()
+	assert.Equal(t, len(cases), len(parts), fmt.Sprintf("expected %d partitions got %d", len(cases), len(parts)))
+	for dir, count := range cases {
+		objs := parts[dir]
+		require.Len(t, objs, 1, fmt.Sprintf("%s: expected one object got %d", dir, len(objs)))
+		assert.Equal(t, "application/gzip", objs[0].contentType, fmt.Sprintf("%s: unexpected content type", dir))
+
+		lines := readNDJSON(t, objs[0].body)
+		assert.Len(t, lines, count, fmt.Sprintf("%s: expected %d messages got %d", dir, count, len(lines)))
+		for _, line := range lines {
+			assert.Contains(t, dir, fmt.Sprintf("channel=%s/", line["channel"]), fmt.Sprintf("%s: message of another channel", dir))
+		}
+	}
+}
+
+// parquetFile is the in-memory Parquet file read by the Parquet reader.
+type parquetFile struct {
+	*bytes.Reader
+	data []byte
+}
+
+func newParquetFile(data []byte) *parquetFile {
+	return &parquetFile{Reader: bytes.NewReader(data), data: data}
+}
+
+func (f *parquetFile) Open(string) (source.ParquetFile, error) {
+	return newParquetFile(f.data), nil
+}
+
+func (f *parquetFile) Create(string) (source.ParquetFile, error) {
+	return nil, errors.New("read only file")
+}
+
+func (f *parquetFile) Write([]byte) (int, error) {
+	return 0, errors.New("read only file")
+}
+
+func (f *parquetFile) Close() error {
+	return nil
+}
+
+func TestConsumeParquet(t *testing.T) {
+	b := newBucket()
+	repo, err := objectstore.New(b, objectstore.Config{Format: objectstore.Parquet})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	str, data, flag, sum := "on", "base64", true, 10.0
+	msgs := senmlMessages()
+	for i := range msgs {
+		msgs[i].Channel = chanID
+		msgs[i].Subtopic = fmt.Sprintf("subtopic%d", i)
+		msgs[i].Time = start + float64(i)
+		msgs[i].UpdateTime = float64(i)
+	}
+	msgs[1].Value, msgs[1].StringValue, msgs[1].DataValue = nil, &str, &data
+	msgs[2].Value, msgs[2].BoolValue, msgs[2].Sum = nil, &flag, &sum
+	err = repo.Consume(msgs)
+	assert.Nil(t, err, fmt.Sprintf("consume SenML messages: unexpected error: %s", err))
+
+	dir := fmt.Sprintf("messages/channel=%s/date=%s", chanID, startDay)
+	objs := b.partitions()[dir]
+	require.Len(t, objs, 1, fmt.Sprintf("%s: expected one object got %d", dir, len(objs)))
+	assert.Equal(t, "application/vnd.apache.parquet", objs[0].contentType, "unexpected content type")
+
+	pr, err := reader.NewParquetColumnReader(newParquetFile(objs[0].body), 1)
+	require.Nil(t, err, fmt.Sprintf("unexpected error reading Parquet file: %s", err))
+	defer pr.ReadStop()
+
+	footer := pr.Footer
+	assert.Equal(t, int64(len(msgs)), footer.GetNumRows(), "unexpected number of rows")
+	require.Len(t, footer.GetRowGroups(), 1, "expected single row group")
+	assert.Equal(t, "mainflux objectstore writer", footer.GetCreatedBy(), "unexpected file creator")
+
+	v := *msgs[0].Value
+	cases := []struct {
+		name      string
+		typ       parquet.Type
+		converted *parquet.ConvertedType
+		optional  bool
+		values    []interface{}
+	}{
+		{
+			name:      "channel",
+			typ:       parquet.Type_BYTE_ARRAY,
+			converted: parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8),
+			values:    []interface{}{chanID, chanID, chanID},
+		},
+		{
+			name:      "subtopic",
+			typ:       parquet.Type_BYTE_ARRAY,
+			converted: parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8),
+			values:    []interface{}{"subtopic0", "subtopic1", "subtopic2"},
+		},
+		{
+			name:      "publisher",
+			typ:       parquet.Type_BYTE_ARRAY,
+			converted: parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8),
+			values:    []interface{}{"publisher", "publisher", "publisher"},
+		},
+		{
+			name:      "protocol",
+			typ:       parquet.Type_BYTE_ARRAY,
+			converted: parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8),
+			values:    []interface{}{"mqtt", "mqtt", "mqtt"},
+		},
+		{
+			name:      "name",
+			typ:       parquet.Type_BYTE_ARRAY,
+			converted: parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8),
+			values:    []interface{}{"temperature", "temperature", "temperature"},
+		},
+		{
+			name:      "unit",
+			typ:       parquet.Type_BYTE_ARRAY,
+			converted: parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8),
+			values:    []interface{}{"Cel", "Cel", "Cel"},
+		},
+		{
+			name:   "time",
+			typ:    parquet.Type_DOUBLE,
+			values: []interface{}{float64(start), float64(start + 1), float64(start + 2)},
+		},
+		{
+			name:   "update_time",
+			typ:    parquet.Type_DOUBLE,
+			values: []interface{}{0.0, 1.0, 2.0},
+		},
+		{
+			name:     "value",
+			typ:      parquet.Type_DOUBLE,
+			optional: true,
+			values:   []interface{}{v, nil, nil},
+		},
+		{
+			name:      "string_value",
+			typ:       parquet.Type_BYTE_ARRAY,
+			converted: parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8),
+			optional:  true,
+			values:    []interface{}{nil, str, nil},
+		},
+		{
+			name:      "data_value",
+			typ:       parquet.Type_BYTE_ARRAY,
+			converted: parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8),
+			optional:  true,
+			values:    []interface{}{nil, data, nil},
+		},
+		{
+			name:     "bool_value",
+			typ:      parquet.Type_BOOLEAN,
+			optional: true,
+			values:   []interface{}{nil, nil, flag},
+		},
+		{
+			name:     "sum",
+			typ:      parquet.Type_DOUBLE,
+			optional: true,
+			values:   []interface{}{nil, nil, sum},
+		},
+	}
+
+	schema := footer.GetSchema()
+	require.Len(t, schema, len(cases)+1, fmt.Sprintf("expected %d schema elements got %d", len(cases)+1, len(schema)))
+	for i, tc := range cases {
+		elem := schema[i+1]
+		repetition := parquet.FieldRepetitionType_REQUIRED
+		if tc.optional {
+			repetition = parquet.FieldRepetitionType_OPTIONAL
+		}
+		// The reader renames the schema elements, keeping the names in the file
+		// as the external names.
+		assert.Equal(t, tc.name, pr.SchemaHandler.GetExName(i+1), fmt.Sprintf("%s: unexpected column name", tc.name))
+		assert.Equal(t, tc.typ, elem.GetType(), fmt.Sprintf("%s: unexpected column type", tc.name))
+		assert.Equal(t, tc.converted, elem.ConvertedType, fmt.Sprintf("%s: unexpected converted type", tc.name))
+		assert.Equal(t, repetition, elem.GetRepetitionType(), fmt.Sprintf("%s: unexpected repetition", tc.name))
+
+		col := footer.GetRowGroups()[0].GetColumns()[i].GetMetaData()
+		assert.Equal(t, parquet.CompressionCodec_GZIP, col.GetCodec(), fmt.Sprintf("%s: unexpected compression codec", tc.name))
+
+		values, _, _, err := pr.ReadColumnByIndex(int64(i), int64(len(msgs)))
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error reading column: %s", tc.name, err))
+		assert.Equal(t, tc.values, values, fmt.Sprintf("%s: unexpected column values", tc.name))
+	}
+}
+
+func TestConsumeFailure(t *testing.T) {
+	b := newBucket()
+	b.down = true
+	repo, err := objectstore.New(b, objectstore.Config{Format: objectstore.NDJSON})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = repo.Consume(senmlMessages())
+	assert.True(t, errors.Contains(err, errDown), fmt.Sprintf("expected %s got %s", errDown, err))
+
+	err = repo.Consume("invalid")
+	assert.NotNil(t, err, "consume invalid messages: expected error got nil")
+}
@@ -0,0 +1,7 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package objectstore contains the writer that uploads the messages to the
+// S3-compatible object storage, as Parquet or gzipped NDJSON files
+// partitioned by channel and date.
+package objectstore
@@ -0,0 +1,59 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package objectstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+
+	mfjson "github.com/mainflux/mainflux/pkg/transformers/json"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+)
+
+// ndjsonWriter encodes the messages as the gzipped newline delimited JSON,
+// one message per line.
+type ndjsonWriter struct {
+	buf bytes.Buffer
+	zw  *gzip.Writer
+	enc *json.Encoder
+}
+
+func newNDJSONWriter() *ndjsonWriter {
+	w := &ndjsonWriter{}
+	w.zw = gzip.NewWriter(&w.buf)
+	w.enc = json.NewEncoder(w.zw)
+	return w
+}
+
+func (w *ndjsonWriter) write(msg interface{}) error {
+	return w.enc.Encode(msg)
+}
+
+func (w *ndjsonWriter) close() ([]byte, error) {
+	if err := w.zw.Close(); err != nil {
+		return nil, err
+	}
+	return w.buf.Bytes(), nil
+}
+
+func senmlNDJSON(msgs []senml.Message) ([]byte, error) {
+	w := newNDJSONWriter()
+	for _, msg := range msgs {
+		if err := w.write(msg); err != nil {
+			return nil, err
+		}
+	}
+	return w.close()
+}
+
+func jsonNDJSON(msgs []mfjson.Message) ([]byte, error) {
+	w := newNDJSONWriter()
+	for _, msg := range msgs {
+		if err := w.write(msg); err != nil {
+			return nil, err
+		}
+	}
+	return w.close()
+}
@@ -0,0 +1,353 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package objectstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+
+	mfjson "github.com/mainflux/mainflux/pkg/transformers/json"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+)
+
+const parquetMagic = "PAR1"
+
+// Parquet physical types.
+const (
+	typeBoolean   = 0
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+)
+
+// Parquet converted types, used as the logical types of the byte arrays.
+const (
+	convertedUTF8 = 0
+	convertedJSON = 19
+)
+
+// Parquet enums of the repetition types, encodings, compression codecs and
+// page types.
+const (
+	repetitionRequired = 0
+	repetitionOptional = 1
+	encodingPlain      = 0
+	encodingRLE        = 3
+	codecGzip          = 2
+	pageData           = 0
+)
+
+// column is the single column of the flat Parquet schema. Each column is
+// written as the single gzipped data page, using the plain encoding.
+type column struct {
+	name      string
+	typ       int32
+	converted int32
+	optional  bool
+
+	rows   int
+	defs   []bool
+	bools  []bool
+	values bytes.Buffer
+}
+
+func newColumn(name string, typ int32, optional bool) *column {
+	return &column{name: name, typ: typ, converted: -1, optional: optional}
+}
+
+func newStringColumn(name string, optional bool) *column {
+	c := newColumn(name, typeByteArray, optional)
+	c.converted = convertedUTF8
+	return c
+}
+
+// add records the row of the column, and reports whether it has a value.
+func (c *column) add(present bool) bool {
+	c.rows++
+	if c.optional {
+		c.defs = append(c.defs, present)
+	}
+	return present
+}
+
+func (c *column) addString(v string) {
+	c.add(true)
+	c.putBytes(v)
+}
+
+func (c *column) addStringPtr(v *string) {
+	if c.add(v != nil) {
+		c.putBytes(*v)
+	}
+}
+
+func (c *column) addInt64(v int64) {
+	c.add(true)
+	binary.Write(&c.values, binary.LittleEndian, v)
+}
+
+func (c *column) addDouble(v float64) {
+	c.add(true)
+	binary.Write(&c.values, binary.LittleEndian, math.Float64bits(v))
+}
+
+func (c *column) addDoublePtr(v *float64) {
+	if c.add(v != nil) {
+		binary.Write(&c.values, binary.LittleEndian, math.Float64bits(*v))
+	}
+}
+
+func (c *column) addBoolPtr(v *bool) {
+	if c.add(v != nil) {
+		c.bools = append(c.bools, *v)
+	}
+}
+
+func (c *column) putBytes(v string) {
+	binary.Write(&c.values, binary.LittleEndian, uint32(len(v)))
+	c.values.WriteString(v)
+}
+
+// page returns the uncompressed content of the data page: the definition
+// levels of the optional column followed by the plain encoded values.
+func (c *column) page() []byte {
+	var page bytes.Buffer
+	if c.optional {
+		levels := encodeLevels(c.defs)
+		binary.Write(&page, binary.LittleEndian, uint32(len(levels)))
+		page.Write(levels)
+	}
+
+	if c.typ == typeBoolean {
+		packed := make([]byte, (len(c.bools)+7)/8)
+		for i, v := range c.bools {
+			if v {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		page.Write(packed)
+		return page.Bytes()
+	}
+
+	page.Write(c.values.Bytes())
+	return page.Bytes()
+}
+
+// encodeLevels encodes the definition levels of the bit width 1 using the
+// RLE runs of the RLE/bit-packing hybrid encoding.
+func encodeLevels(defs []bool) []byte {
+	var buf bytes.Buffer
+	var b [binary.MaxVarintLen64]byte
+	for i := 0; i < len(defs); {
+		j := i
+		for j < len(defs) && defs[j] == defs[i] {
+			j++
+		}
+		n := binary.PutUvarint(b[:], uint64(j-i)<<1)
+		buf.Write(b[:n])
+		if defs[i] {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+		i = j
+	}
+	return buf.Bytes()
+}
+
+// chunk holds the location of the column chunk within the file.
+type chunk struct {
+	offset       int64
+	uncompressed int64
+	compressed   int64
+}
+
+// encodeParquet encodes the columns of the same number of rows as the Parquet
+// file with the single row group.
+func encodeParquet(cols []*column) ([]byte, error) {
+	rows := 0
+	if len(cols) > 0 {
+		rows = cols[0].rows
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(parquetMagic)
+
+	chunks := make([]chunk, len(cols))
+	for i, c := range cols {
+		page := c.page()
+
+		var compressed bytes.Buffer
+		zw := gzip.NewWriter(&compressed)
+		if _, err := zw.Write(page); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+
+		header := pageHeader(len(page), compressed.Len(), rows)
+		chunks[i] = chunk{
+			offset:       int64(buf.Len()),
+			uncompressed: int64(len(header) + len(page)),
+			compressed:   int64(len(header) + compressed.Len()),
+		}
+		buf.Write(header)
+		buf.Write(compressed.Bytes())
+	}
+
+	footer := fileMetadata(cols, chunks, rows)
+	buf.Write(footer)
+	binary.Write(&buf, binary.LittleEndian, uint32(len(footer)))
+	buf.WriteString(parquetMagic)
+
+	return buf.Bytes(), nil
+}
+
+func pageHeader(uncompressed, compressed, rows int) []byte {
+	w := newCompactWriter()
+	w.i32(1, pageData)
+	w.i32(2, int32(uncompressed))
+	w.i32(3, int32(compressed))
+	w.beginStruct(5)
+	w.i32(1, int32(rows))
+	w.i32(2, encodingPlain)
+	w.i32(3, encodingRLE)
+	w.i32(4, encodingRLE)
+	w.end()
+	w.end()
+	return w.bytes()
+}
+
+func fileMetadata(cols []*column, chunks []chunk, rows int) []byte {
+	w := newCompactWriter()
+	w.i32(1, 1)
+
+	w.list(2, ctStruct, len(cols)+1)
+	w.beginElem()
+	w.binary(4, "schema")
+	w.i32(5, int32(len(cols)))
+	w.end()
+	for _, c := range cols {
+		w.beginElem()
+		w.i32(1, c.typ)
+		repetition := int32(repetitionRequired)
+		if c.optional {
+			repetition = repetitionOptional
+		}
+		w.i32(3, repetition)
+		w.binary(4, c.name)
+		if c.converted >= 0 {
+			w.i32(6, c.converted)
+		}
+		w.end()
+	}
+
+	w.i64(3, int64(rows))
+
+	var total int64
+	for _, ch := range chunks {
+		total += ch.uncompressed
+	}
+	w.list(4, ctStruct, 1)
+	w.beginElem()
+	w.list(1, ctStruct, len(cols))
+	for i, c := range cols {
+		ch := chunks[i]
+		w.beginElem()
+		w.i64(2, ch.offset)
+		w.beginStruct(3)
+		w.i32(1, c.typ)
+		w.list(2, ctI32, 2)
+		w.elemI32(encodingPlain)
+		w.elemI32(encodingRLE)
+		w.list(3, ctBinary, 1)
+		w.elemBinary(c.name)
+		w.i32(4, codecGzip)
+		w.i64(5, int64(rows))
+		w.i64(6, ch.uncompressed)
+		w.i64(7, ch.compressed)
+		w.i64(9, ch.offset)
+		w.end()
+		w.end()
+	}
+	w.i64(2, total)
+	w.i64(3, int64(rows))
+	w.end()
+
+	w.binary(6, "mainflux objectstore writer")
+	w.end()
+	return w.bytes()
+}
+
+func senmlParquet(msgs []senml.Message) ([]byte, error) {
+	var (
+		channel     = newStringColumn("channel", false)
+		subtopic    = newStringColumn("subtopic", false)
+		publisher   = newStringColumn("publisher", false)
+		protocol    = newStringColumn("protocol", false)
+		name        = newStringColumn("name", false)
+		unit        = newStringColumn("unit", false)
+		tm          = newColumn("time", typeDouble, false)
+		updateTime  = newColumn("update_time", typeDouble, false)
+		value       = newColumn("value", typeDouble, true)
+		stringValue = newStringColumn("string_value", true)
+		dataValue   = newStringColumn("data_value", true)
+		boolValue   = newColumn("bool_value", typeBoolean, true)
+		sum         = newColumn("sum", typeDouble, true)
+	)
+
+	for _, msg := range msgs {
+		channel.addString(msg.Channel)
+		subtopic.addString(msg.Subtopic)
+		publisher.addString(msg.Publisher)
+		protocol.addString(msg.Protocol)
+		name.addString(msg.Name)
+		unit.addString(msg.Unit)
+		tm.addDouble(msg.Time)
+		updateTime.addDouble(msg.UpdateTime)
+		value.addDoublePtr(msg.Value)
+		stringValue.addStringPtr(msg.StringValue)
+		dataValue.addStringPtr(msg.DataValue)
+		boolValue.addBoolPtr(msg.BoolValue)
+		sum.addDoublePtr(msg.Sum)
+	}
+
+	return encodeParquet([]*column{
+		channel, subtopic, publisher, protocol, name, unit, tm, updateTime,
+		value, stringValue, dataValue, boolValue, sum,
+	})
+}
+
+// jsonParquet encodes the JSON messages. Since the payloads don't share the
+// schema, each payload is stored as the JSON string.
+func jsonParquet(msgs []mfjson.Message) ([]byte, error) {
+	var (
+		channel   = newStringColumn("channel", false)
+		created   = newColumn("created", typeInt64, false)
+		subtopic  = newStringColumn("subtopic", false)
+		publisher = newStringColumn("publisher", false)
+		protocol  = newStringColumn("protocol", false)
+		payload   = newColumn("payload", typeByteArray, false)
+	)
+	payload.converted = convertedJSON
+
+	for _, msg := range msgs {
+		data, err := json.Marshal(msg.Payload)
+		if err != nil {
+			return nil, err
+		}
+		channel.addString(msg.Channel)
+		created.addInt64(msg.Created)
+		subtopic.addString(msg.Subtopic)
+		publisher.addString(msg.Publisher)
+		protocol.addString(msg.Protocol)
+		payload.addString(string(data))
+	}
+
+	return encodeParquet([]*column{channel, created, subtopic, publisher, protocol, payload})
+}
@@ -0,0 +1,192 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+const (
+	signAlgorithm  = "AWS4-HMAC-SHA256"
+	signService    = "s3"
+	amzDateLayout  = "20060102T150405Z"
+	scopeLayout    = "20060102"
+	maxErrBodySize = 1024
+)
+
+var (
+	// ErrInvalidEndpoint indicates malformed S3 endpoint URL.
+	ErrInvalidEndpoint = errors.New("invalid S3 endpoint")
+
+	errPutObject = errors.New("failed to upload object")
+)
+
+// S3Config defines the options of the S3-compatible bucket.
+type S3Config struct {
+	// Endpoint is the URL of the S3 service, e.g. https://s3.amazonaws.com
+	// or http://localhost:9000 for MinIO.
+	Endpoint string
+
+	// Region is the region the bucket is located in.
+	Region string
+
+	// Bucket is the name of the bucket.
+	Bucket string
+
+	// AccessKey and SecretKey are the credentials the requests are signed
+	// with, using the AWS Signature Version 4.
+	AccessKey string
+	SecretKey string
+
+	// PathStyle enables the path-style requests, which address the bucket
+	// using the URL path instead of the host name. MinIO requires it.
+	PathStyle bool
+
+	// Timeout is the upload request timeout.
+	Timeout time.Duration
+}
+
+var _ Bucket = (*s3Bucket)(nil)
+
+type s3Bucket struct {
+	cfg    S3Config
+	base   *url.URL
+	client *http.Client
+}
+
+// NewS3Bucket returns the bucket of the S3-compatible object storage.
+func NewS3Bucket(cfg S3Config) (Bucket, error) {
+	base, err := url.Parse(cfg.Endpoint)
+	if err != nil || base.Host == "" || (base.Scheme != "http" && base.Scheme != "https") {
+		return nil, ErrInvalidEndpoint
+	}
+	if !cfg.PathStyle {
+		base.Host = fmt.Sprintf("%s.%s", cfg.Bucket, base.Host)
+	}
+	base.Path = strings.TrimSuffix(base.Path, "/")
+	if cfg.PathStyle {
+		base.Path = fmt.Sprintf("%s/%s", base.Path, cfg.Bucket)
+	}
+
+	return &s3Bucket{
+		cfg:    cfg,
+		base:   base,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+func (b *s3Bucket) Put(ctx context.Context, key, contentType string, body []byte) error {
+	u := *b.base
+	u.Path = fmt.Sprintf("%s/%s", u.Path, key)
+	u.RawPath = escapePath(u.Path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(errPutObject, err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	b.sign(req, body, time.Now())
+
+	res, err := b.client.Do(req)
+	if err != nil {
+		return errors.Wrap(errPutObject, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxErrBodySize))
+		return errors.Wrap(errPutObject, fmt.Errorf("unexpected status %d: %s", res.StatusCode, msg))
+	}
+
+	return nil
+}
+
+// sign signs the request using the AWS Signature Version 4.
+func (b *s3Bucket) sign(req *http.Request, body []byte, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format(amzDateLayout)
+	payloadHash := hashHex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{now.Format(scopeLayout), b.cfg.Region, signService, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{signAlgorithm, amzDate, scope, hashHex([]byte(canonicalRequest))}, "\n")
+
+	key := []byte("AWS4" + b.cfg.SecretKey)
+	for _, part := range strings.Split(scope, "/") {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signAlgorithm, b.cfg.AccessKey, scope, signedHeaders, signature))
+}
+
+// escapePath escapes the URL path as required by the AWS Signature Version 4,
+// leaving only the unreserved characters and slashes unescaped.
+func escapePath(p string) string {
+	var sb strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			sb.WriteByte(c)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,123 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package objectstore_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/consumers/writers/objectstore"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	bucketName = "mainflux"
+	accessKey  = "access"
+	secretKey  = "secret"
+	region     = "us-east-1"
+	objectKey  = "messages/channel=chan/date=2020-09-13/file.parquet"
+)
+
+type upload struct {
+	method      string
+	path        string
+	contentType string
+	auth        string
+	body        string
+}
+
+func newS3Server(status int, uploads chan<- upload) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		uploads <- upload{
+			method:      r.Method,
+			path:        r.URL.EscapedPath(),
+			contentType: r.Header.Get("Content-Type"),
+			auth:        r.Header.Get("Authorization"),
+			body:        string(body),
+		}
+		w.WriteHeader(status)
+	}))
+}
+
+func TestNewS3Bucket(t *testing.T) {
+	cases := []struct {
+		desc     string
+		endpoint string
+		err      error
+	}{
+		{
+			desc:     "create bucket",
+			endpoint: "http://localhost:9000",
+		},
+		{
+			desc:     "create bucket with endpoint without scheme",
+			endpoint: "localhost:9000",
+			err:      objectstore.ErrInvalidEndpoint,
+		},
+		{
+			desc:     "create bucket with unsupported scheme",
+			endpoint: "ftp://localhost",
+			err:      objectstore.ErrInvalidEndpoint,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := objectstore.NewS3Bucket(objectstore.S3Config{Endpoint: tc.endpoint, Bucket: bucketName})
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestPut(t *testing.T) {
+	cases := []struct {
+		desc   string
+		status int
+		err    bool
+	}{
+		{
+			desc:   "upload object",
+			status: http.StatusOK,
+		},
+		{
+			desc:   "upload object with invalid credentials",
+			status: http.StatusForbidden,
+			err:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		uploads := make(chan upload, 1)
+		ts := newS3Server(tc.status, uploads)
+
+		b, err := objectstore.NewS3Bucket(objectstore.S3Config{
+			Endpoint:  ts.URL,
+			Region:    region,
+			Bucket:    bucketName,
+			AccessKey: accessKey,
+			SecretKey: secretKey,
+			PathStyle: true,
+		})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		err = b.Put(context.Background(), objectKey, "application/vnd.apache.parquet", []byte("data"))
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: expected error %t got %s", tc.desc, tc.err, err))
+
+		up := <-uploads
+		assert.Equal(t, http.MethodPut, up.method, fmt.Sprintf("%s: unexpected method", tc.desc))
+		assert.Equal(t, "/mainflux/messages/channel%3Dchan/date%3D2020-09-13/file.parquet", up.path, fmt.Sprintf("%s: unexpected path", tc.desc))
+		assert.Equal(t, "application/vnd.apache.parquet", up.contentType, fmt.Sprintf("%s: unexpected content type", tc.desc))
+		assert.Equal(t, "data", up.body, fmt.Sprintf("%s: unexpected body", tc.desc))
+		assert.True(t, strings.HasPrefix(up.auth, fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/", accessKey)), fmt.Sprintf("%s: unexpected authorization %s", tc.desc, up.auth))
+		assert.Contains(t, up.auth, fmt.Sprintf("/%s/s3/aws4_request", region), fmt.Sprintf("%s: unexpected credential scope", tc.desc))
+
+		ts.Close()
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package objectstore
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol types used by the Parquet metadata.
+const (
+	ctI32    = 5
+	ctI64    = 6
+	ctBinary = 8
+	ctList   = 9
+	ctStruct = 12
+)
+
+// compactWriter encodes the Thrift structures using the compact protocol,
+// which is how the Parquet page headers and file metadata are serialized.
+// Fields of every struct have to be written in the ascending ID order.
+type compactWriter struct {
+	buf bytes.Buffer
+	// last holds the ID of the last field written to each of the nested
+	// structs, since the field IDs are encoded as deltas.
+	last []int16
+}
+
+func newCompactWriter() *compactWriter {
+	return &compactWriter{last: []int16{0}}
+}
+
+func (w *compactWriter) i32(id int16, v int32) {
+	w.field(id, ctI32)
+	w.varint(int64(v))
+}
+
+func (w *compactWriter) i64(id int16, v int64) {
+	w.field(id, ctI64)
+	w.varint(v)
+}
+
+func (w *compactWriter) binary(id int16, v string) {
+	w.field(id, ctBinary)
+	w.str(v)
+}
+
+// beginStruct starts the struct field. The struct has to be ended by end.
+func (w *compactWriter) beginStruct(id int16) {
+	w.field(id, ctStruct)
+	w.last = append(w.last, 0)
+}
+
+// beginElem starts the struct element of the list. The element has to be
+// ended by end.
+func (w *compactWriter) beginElem() {
+	w.last = append(w.last, 0)
+}
+
+// end writes the stop field of the current struct.
+func (w *compactWriter) end() {
+	w.buf.WriteByte(0)
+	w.last = w.last[:len(w.last)-1]
+}
+
+// list starts the list field of the given size. Elements are written
+// right after it, using elemI32, elemBinary or beginElem.
+func (w *compactWriter) list(id int16, elemType byte, size int) {
+	w.field(id, ctList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+	w.buf.WriteByte(0xf0 | elemType)
+	w.uvarint(uint64(size))
+}
+
+func (w *compactWriter) elemI32(v int32) {
+	w.varint(int64(v))
+}
+
+func (w *compactWriter) elemBinary(v string) {
+	w.str(v)
+}
+
+func (w *compactWriter) bytes() []byte {
+	return w.buf.Bytes()
+}
+
+func (w *compactWriter) field(id int16, fieldType byte) {
+	top := len(w.last) - 1
+	if delta := id - w.last[top]; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		w.buf.WriteByte(fieldType)
+		w.varint(int64(id))
+	}
+	w.last[top] = id
+}
+
+func (w *compactWriter) str(v string) {
+	w.uvarint(uint64(len(v)))
+	w.buf.WriteString(v)
+}
+
+// varint writes the zigzag encoded integer.
+func (w *compactWriter) varint(v int64) {
+	w.uvarint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+func (w *compactWriter) uvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	w.buf.Write(b[:n])
+}
@@ -20,6 +20,10 @@ import (
 const (
 	errInvalid        = "invalid_text_representation"
 	errUndefinedTable = "undefined_table"
+
+	// maxBatchRows is the maximal number of rows inserted by the single
+	// statement, which keeps the statement below the parameters limit.
+	maxBatchRows = 1000
 )
 
 var (
@@ -78,13 +82,21 @@ func (pr postgresRepo) saveSenml(messages interface{}) (err error) {
 		}
 	}()
 
+	dbMsgs := make([]senmlMessage, 0, len(msgs))
 	for _, msg := range msgs {
 		id, err := uuid.NewV4()
 		if err != nil {
 			return err
 		}
-		m := senmlMessage{Message: msg, ID: id.String()}
-		if _, err := tx.NamedExec(q, m); err != nil {
+		dbMsgs = append(dbMsgs, senmlMessage{Message: msg, ID: id.String()})
+	}
+
+	for start := 0; start < len(dbMsgs); start += maxBatchRows {
+		end := start + maxBatchRows
+		if end > len(dbMsgs) {
+			end = len(dbMsgs)
+		}
+		if _, err := tx.NamedExec(q, dbMsgs[start:end]); err != nil {
 			pqErr, ok := err.(*pq.Error)
 			if ok {
 				switch pqErr.Code.Name() {
@@ -134,13 +146,22 @@ func (pr postgresRepo) insertJSON(msgs mfjson.Messages) error {
           VALUES (:id, :channel, :created, :subtopic, :publisher, :protocol, :payload);`
 	q = fmt.Sprintf(q, msgs.Format)
 
+	dbMsgs := make([]jsonMessage, 0, len(msgs.Data))
 	for _, m := range msgs.Data {
 		var dbmsg jsonMessage
 		dbmsg, err = toJSONMessage(m)
 		if err != nil {
 			return errors.Wrap(errSaveMessage, err)
 		}
-		if _, err = tx.NamedExec(q, dbmsg); err != nil {
+		dbMsgs = append(dbMsgs, dbmsg)
+	}
+
+	for start := 0; start < len(dbMsgs); start += maxBatchRows {
+		end := start + maxBatchRows
+		if end > len(dbMsgs) {
+			end = len(dbMsgs)
+		}
+		if _, err = tx.NamedExec(q, dbMsgs[start:end]); err != nil {
 			pqErr, ok := err.(*pq.Error)
 			if ok {
 				switch pqErr.Code.Name() {
@@ -70,6 +70,22 @@ func migrateDB(db *sqlx.DB) error {
 					"DROP TABLE messages",
 				},
 			},
+			{
+				Id: "messages_2",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS retention_policies (
+                        channel  UUID,
+                        owner    VARCHAR(254),
+                        tiers    JSONB NOT NULL,
+                        applied  TIMESTAMPTZ,
+                        updated  TIMESTAMPTZ NOT NULL,
+                        PRIMARY KEY (channel)
+                    )`,
+				},
+				Down: []string{
+					"DROP TABLE retention_policies",
+				},
+			},
 		},
 	}
 
@@ -0,0 +1,225 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var (
+	errDownsample = errors.New("failed to downsample messages")
+	errDelete     = errors.New("failed to delete expired messages")
+)
+
+var _ retention.PolicyRepository = (*policyRepository)(nil)
+
+type policyRepository struct {
+	db *sqlx.DB
+}
+
+// NewPolicyRepository returns new PostgreSQL retention policy repository.
+func NewPolicyRepository(db *sqlx.DB) retention.PolicyRepository {
+	return &policyRepository{db: db}
+}
+
+func (pr policyRepository) Save(ctx context.Context, p retention.Policy) error {
+	q := `INSERT INTO retention_policies (channel, owner, tiers, applied, updated)
+          VALUES (:channel, :owner, :tiers, :applied, :updated)
+          ON CONFLICT (channel) DO UPDATE
+          SET owner = :owner, tiers = :tiers, applied = :applied, updated = :updated;`
+
+	dbp, err := toDBPolicy(p)
+	if err != nil {
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+	if _, err := pr.db.NamedExecContext(ctx, q, dbp); err != nil {
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (pr policyRepository) RetrieveByChannel(ctx context.Context, chanID string) (retention.Policy, error) {
+	q := `SELECT channel, owner, tiers, applied, updated FROM retention_policies WHERE channel = $1;`
+
+	var dbp dbPolicy
+	if err := pr.db.QueryRowxContext(ctx, q, chanID).StructScan(&dbp); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return retention.Policy{}, errors.ErrNotFound
+		}
+		if err == sql.ErrNoRows {
+			return retention.Policy{}, errors.ErrNotFound
+		}
+		return retention.Policy{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	return toPolicy(dbp)
+}
+
+func (pr policyRepository) RetrieveAll(ctx context.Context) ([]retention.Policy, error) {
+	q := `SELECT channel, owner, tiers, applied, updated FROM retention_policies ORDER BY channel;`
+
+	rows, err := pr.db.QueryxContext(ctx, q)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	var policies []retention.Policy
+	for rows.Next() {
+		var dbp dbPolicy
+		if err := rows.StructScan(&dbp); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		p, err := toPolicy(dbp)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+
+	return policies, nil
+}
+
+func (pr policyRepository) UpdateApplied(ctx context.Context, p retention.Policy) error {
+	q := `UPDATE retention_policies SET applied = $1 WHERE channel = $2 AND updated = $3;`
+
+	if _, err := pr.db.ExecContext(ctx, q, p.Applied, p.Channel, p.Updated); err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	return nil
+}
+
+func (pr policyRepository) Remove(ctx context.Context, chanID string) error {
+	q := `DELETE FROM retention_policies WHERE channel = $1;`
+
+	if _, err := pr.db.ExecContext(ctx, q, chanID); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
+type dbPolicy struct {
+	Channel string       `db:"channel"`
+	Owner   string       `db:"owner"`
+	Tiers   []byte       `db:"tiers"`
+	Applied sql.NullTime `db:"applied"`
+	Updated time.Time    `db:"updated"`
+}
+
+func toDBPolicy(p retention.Policy) (dbPolicy, error) {
+	tiers, err := json.Marshal(p.Tiers)
+	if err != nil {
+		return dbPolicy{}, err
+	}
+
+	return dbPolicy{
+		Channel: p.Channel,
+		Owner:   p.Owner,
+		Tiers:   tiers,
+		Applied: sql.NullTime{Time: p.Applied, Valid: !p.Applied.IsZero()},
+		Updated: p.Updated,
+	}, nil
+}
+
+func toPolicy(dbp dbPolicy) (retention.Policy, error) {
+	var tiers []retention.Tier
+	if err := json.Unmarshal(dbp.Tiers, &tiers); err != nil {
+		return retention.Policy{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	p := retention.Policy{
+		Channel: dbp.Channel,
+		Owner:   dbp.Owner,
+		Tiers:   tiers,
+		Updated: dbp.Updated,
+	}
+	if dbp.Applied.Valid {
+		p.Applied = dbp.Applied.Time
+	}
+
+	return p, nil
+}
+
+var _ retention.Compactor = (*compactor)(nil)
+
+type compactor struct {
+	db *sqlx.DB
+}
+
+// NewRetentionStore returns new PostgreSQL store the retention policies
+// are applied with. Messages are compacted by the writer into the tables
+// named after their resolution, e.g. "messages_60", sharing the raw SenML
+// messages columns.
+func NewRetentionStore(db *sqlx.DB) retention.Store {
+	return retention.NewCompactionStore(compactor{db: db})
+}
+
+func (c compactor) Downsample(ctx context.Context, chanID string, res time.Duration, from, to time.Time) error {
+	table := retention.Table(res)
+	q := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+        channel       UUID,
+        subtopic      VARCHAR(254),
+        publisher     UUID,
+        protocol      TEXT,
+        name          TEXT,
+        unit          TEXT,
+        value         FLOAT,
+        string_value  TEXT,
+        bool_value    BOOL,
+        data_value    BYTEA,
+        time          FLOAT,
+        PRIMARY KEY (channel, time, publisher, subtopic, protocol, name, unit)
+    );`, table)
+	if _, err := c.db.ExecContext(ctx, q); err != nil {
+		return errors.Wrap(errDownsample, err)
+	}
+
+	// Non-numeric values of the bucket are the last ones published.
+	q = fmt.Sprintf(`INSERT INTO %s (channel, subtopic, publisher, protocol, name, unit, value,
+            string_value, bool_value, data_value, time)
+        SELECT channel, COALESCE(subtopic, ''), publisher, COALESCE(protocol, ''), COALESCE(name, ''),
+            COALESCE(unit, ''), AVG(value),
+            (ARRAY_AGG(string_value ORDER BY time DESC) FILTER (WHERE string_value IS NOT NULL))[1],
+            (ARRAY_AGG(bool_value ORDER BY time DESC) FILTER (WHERE bool_value IS NOT NULL))[1],
+            (ARRAY_AGG(data_value ORDER BY time DESC) FILTER (WHERE data_value IS NOT NULL))[1],
+            FLOOR(time / $2) * $2 AS bucket
+        FROM messages
+        WHERE channel = $1 AND time >= $3 AND time < $4
+        GROUP BY 1, 2, 3, 4, 5, 6, bucket
+        ON CONFLICT (channel, time, publisher, subtopic, protocol, name, unit)
+        DO UPDATE SET value = EXCLUDED.value, string_value = EXCLUDED.string_value,
+            bool_value = EXCLUDED.bool_value, data_value = EXCLUDED.data_value;`, table)
+
+	sec := res.Seconds()
+	if _, err := c.db.ExecContext(ctx, q, chanID, sec, from.Unix(), to.Unix()); err != nil {
+		return errors.Wrap(errDownsample, err)
+	}
+
+	return nil
+}
+
+func (c compactor) Delete(ctx context.Context, chanID string, res time.Duration, before time.Time) error {
+	q := fmt.Sprintf(`DELETE FROM %s WHERE channel = $1 AND time < $2;`, retention.Table(res))
+
+	if _, err := c.db.ExecContext(ctx, q, chanID, float64(before.UnixNano())/1e9); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errUndefinedTable {
+			return nil
+		}
+		return errors.Wrap(errDelete, err)
+	}
+
+	return nil
+}
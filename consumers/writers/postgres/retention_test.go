@@ -0,0 +1,109 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/consumers/writers/postgres"
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const day = 24 * time.Hour
+
+// bucket is the start of the minute bucket the retention tests publish to.
+var bucket = time.Unix(1599998400, 0)
+
+func TestPolicyRepository(t *testing.T) {
+	repo := postgres.NewPolicyRepository(db)
+
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	p := retention.Policy{
+		Channel: chid.String(),
+		Owner:   "owner",
+		Tiers:   []retention.Tier{{Resolution: retention.Raw, Retention: time.Hour}, {Resolution: time.Minute, Retention: day}},
+		Updated: time.Now().UTC().Truncate(time.Millisecond),
+	}
+	err = repo.Save(context.Background(), p)
+	assert.Nil(t, err, fmt.Sprintf("save policy: unexpected error: %s", err))
+
+	saved, err := repo.RetrieveByChannel(context.Background(), p.Channel)
+	assert.Nil(t, err, fmt.Sprintf("retrieve policy: unexpected error: %s", err))
+	assert.Equal(t, p.Tiers, saved.Tiers, fmt.Sprintf("retrieve policy: expected tiers %v got %v\n", p.Tiers, saved.Tiers))
+	assert.True(t, saved.Applied.IsZero(), fmt.Sprintf("retrieve policy: expected zero applied time got %s\n", saved.Applied))
+
+	p.Applied = time.Now().UTC().Truncate(time.Millisecond)
+	err = repo.UpdateApplied(context.Background(), p)
+	assert.Nil(t, err, fmt.Sprintf("update applied time: unexpected error: %s", err))
+
+	saved, err = repo.RetrieveByChannel(context.Background(), p.Channel)
+	assert.Nil(t, err, fmt.Sprintf("retrieve applied policy: unexpected error: %s", err))
+	assert.True(t, p.Applied.Equal(saved.Applied), fmt.Sprintf("retrieve applied policy: expected applied time %s got %s\n", p.Applied, saved.Applied))
+
+	err = repo.Remove(context.Background(), p.Channel)
+	assert.Nil(t, err, fmt.Sprintf("remove policy: unexpected error: %s", err))
+
+	_, err = repo.RetrieveByChannel(context.Background(), p.Channel)
+	assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("retrieve removed policy: expected %s got %s\n", errors.ErrNotFound, err))
+}
+
+func TestRetentionStore(t *testing.T) {
+	repo := postgres.New(db)
+	store := postgres.NewRetentionStore(db)
+
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	low, high := 2.0, 4.0
+	msgs := []senml.Message{
+		{Channel: chid.String(), Publisher: pubid.String(), Name: "temp", Time: float64(bucket.Unix() + 10), Value: &low},
+		{Channel: chid.String(), Publisher: pubid.String(), Name: "temp", Time: float64(bucket.Unix() + 20), Value: &high},
+		{Channel: chid.String(), Publisher: pubid.String(), Name: "temp", Time: float64(bucket.Unix() + 30), StringValue: &stringV},
+	}
+	err = repo.Consume(msgs)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	p := retention.Policy{
+		Channel: chid.String(),
+		Tiers:   []retention.Tier{{Resolution: retention.Raw, Retention: time.Hour}, {Resolution: time.Minute, Retention: 1000 * day}},
+	}
+	err = store.Apply(context.Background(), p, bucket.Add(2*time.Hour))
+	assert.Nil(t, err, fmt.Sprintf("apply policy: unexpected error: %s", err))
+
+	var rows []struct {
+		Value       float64 `db:"value"`
+		StringValue string  `db:"string_value"`
+	}
+	q := `SELECT value, string_value FROM messages_60 WHERE channel = $1 AND time = $2;`
+	err = db.Select(&rows, q, chid.String(), bucket.Unix())
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	require.Len(t, rows, 1, "apply policy: expected single downsampled message")
+	assert.Equal(t, 3.0, rows[0].Value, fmt.Sprintf("apply policy: expected average value 3 got %f\n", rows[0].Value))
+	assert.Equal(t, stringV, rows[0].StringValue, fmt.Sprintf("apply policy: expected string value %s got %s\n", stringV, rows[0].StringValue))
+
+	var raw int
+	err = db.Get(&raw, `SELECT COUNT(*) FROM messages WHERE channel = $1;`, chid.String())
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, 0, raw, fmt.Sprintf("apply policy: expected expired raw messages removed got %d\n", raw))
+
+	err = store.Remove(context.Background(), p)
+	assert.Nil(t, err, fmt.Sprintf("remove policy: unexpected error: %s", err))
+
+	var downsampled int
+	err = db.Get(&downsampled, `SELECT COUNT(*) FROM messages_60 WHERE channel = $1;`, chid.String())
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, 0, downsampled, fmt.Sprintf("remove policy: expected downsampled messages removed got %d\n", downsampled))
+}
@@ -0,0 +1,61 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+)
+
+func savePolicyEndpoint(svc retention.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(savePolicyReq)
+		if err := req.validate(); err != nil {
+			return savePolicyRes{}, err
+		}
+
+		p, err := toPolicy(req)
+		if err != nil {
+			return savePolicyRes{}, err
+		}
+		if err := svc.SavePolicy(ctx, req.token, p); err != nil {
+			return savePolicyRes{}, err
+		}
+
+		return savePolicyRes{}, nil
+	}
+}
+
+func viewPolicyEndpoint(svc retention.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewPolicyReq)
+		if err := req.validate(); err != nil {
+			return viewPolicyRes{}, err
+		}
+
+		p, err := svc.ViewPolicy(ctx, req.token, req.chanID)
+		if err != nil {
+			return viewPolicyRes{}, err
+		}
+
+		return toViewPolicyRes(p), nil
+	}
+}
+
+func removePolicyEndpoint(svc retention.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewPolicyReq)
+		if err := req.validate(); err != nil {
+			return removePolicyRes{}, err
+		}
+
+		if err := svc.RemovePolicy(ctx, req.token, req.chanID); err != nil {
+			return removePolicyRes{}, err
+		}
+
+		return removePolicyRes{}, nil
+	}
+}
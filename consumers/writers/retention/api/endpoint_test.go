@@ -0,0 +1,322 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+	httpapi "github.com/mainflux/mainflux/consumers/writers/retention/api"
+	"github.com/mainflux/mainflux/consumers/writers/retention/mocks"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	contentType = "application/json"
+	email       = "user@example.com"
+	otherEmail  = "other@example.com"
+	token       = "token"
+	otherToken  = "other"
+	wrongValue  = "wrong_value"
+	chanID      = "chan"
+	otherChan   = "other"
+)
+
+type testRequest struct {
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	token       string
+	body        io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+	if tr.token != "" {
+		req.Header.Set("Authorization", httputil.BearerPrefix+tr.token)
+	}
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+	return tr.client.Do(req)
+}
+
+func newServer() *httptest.Server {
+	auth := mocks.NewAuth(map[string]string{token: email, otherToken: otherEmail})
+	things := mocks.NewThingsService(map[string]string{chanID: email, otherChan: otherEmail})
+	svc := retention.New(auth, things, mocks.NewPolicyRepository(), mocks.NewStore(nil))
+	mux := httpapi.MakeHandler(svc, mocktracer.New(), "writer")
+	return httptest.NewServer(mux)
+}
+
+func toJSON(data interface{}) string {
+	jsonData, _ := json.Marshal(data)
+	return string(jsonData)
+}
+
+type tier struct {
+	Resolution string `json:"resolution,omitempty"`
+	Retention  string `json:"retention"`
+}
+
+type policyReq struct {
+	Tiers []tier `json:"tiers"`
+}
+
+type policyRes struct {
+	Channel string `json:"channel"`
+	Owner   string `json:"owner"`
+	Tiers   []tier `json:"tiers"`
+}
+
+var validReq = policyReq{
+	Tiers: []tier{
+		{Resolution: "raw", Retention: "7d"},
+		{Resolution: "1m", Retention: "90d"},
+		{Resolution: "1h", Retention: "730d"},
+	},
+}
+
+func savePolicy(t *testing.T, ts *httptest.Server, chanID string) {
+	req := testRequest{
+		client:      ts.Client(),
+		method:      http.MethodPut,
+		url:         fmt.Sprintf("%s/channels/%s/retention", ts.URL, chanID),
+		contentType: contentType,
+		token:       token,
+		body:        strings.NewReader(toJSON(validReq)),
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error saving policy: %s", err))
+	require.Equal(t, http.StatusOK, res.StatusCode, fmt.Sprintf("unexpected status code saving policy: %d", res.StatusCode))
+}
+
+func TestSave(t *testing.T) {
+	ts := newServer()
+	defer ts.Close()
+
+	invalidDuration := policyReq{Tiers: []tier{{Resolution: "1 minute", Retention: "90d"}}}
+	invalidPolicy := policyReq{Tiers: []tier{{Resolution: "1d", Retention: "1h"}}}
+
+	cases := []struct {
+		desc        string
+		chanID      string
+		req         string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "save policy",
+			chanID:      chanID,
+			req:         toJSON(validReq),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "save policy with invalid duration",
+			chanID:      chanID,
+			req:         toJSON(invalidDuration),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "save invalid policy",
+			chanID:      chanID,
+			req:         toJSON(invalidPolicy),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "save policy without tiers",
+			chanID:      chanID,
+			req:         toJSON(policyReq{}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "save policy for channel owned by other user",
+			chanID:      otherChan,
+			req:         toJSON(validReq),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "save policy with invalid token",
+			chanID:      chanID,
+			req:         toJSON(validReq),
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "save policy with invalid request format",
+			chanID:      chanID,
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "save policy without content type",
+			chanID:      chanID,
+			req:         toJSON(validReq),
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/channels/%s/retention", ts.URL, tc.chanID),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestView(t *testing.T) {
+	ts := newServer()
+	defer ts.Close()
+
+	savePolicy(t, ts, chanID)
+
+	cases := []struct {
+		desc   string
+		chanID string
+		auth   string
+		status int
+		res    policyRes
+	}{
+		{
+			desc:   "view policy",
+			chanID: chanID,
+			auth:   token,
+			status: http.StatusOK,
+			res: policyRes{
+				Channel: chanID,
+				Owner:   email,
+				Tiers: []tier{
+					{Resolution: "raw", Retention: "7d"},
+					{Resolution: "1m", Retention: "90d"},
+					{Resolution: "1h", Retention: "730d"},
+				},
+			},
+		},
+		{
+			desc:   "view non-existing policy",
+			chanID: otherChan,
+			auth:   otherToken,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "view policy of channel owned by other user",
+			chanID: chanID,
+			auth:   otherToken,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "view policy with invalid token",
+			chanID: chanID,
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/%s/retention", ts.URL, tc.chanID),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var body policyRes
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, body))
+	}
+}
+
+func TestRemove(t *testing.T) {
+	ts := newServer()
+	defer ts.Close()
+
+	savePolicy(t, ts, chanID)
+
+	cases := []struct {
+		desc   string
+		chanID string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "remove policy with invalid token",
+			chanID: chanID,
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "remove policy of channel owned by other user",
+			chanID: chanID,
+			auth:   otherToken,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "remove policy",
+			chanID: chanID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove removed policy",
+			chanID: chanID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/channels/%s/retention", ts.URL, tc.chanID),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+var _ retention.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    retention.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc retention.Service, logger log.Logger) retention.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) SavePolicy(ctx context.Context, token string, p retention.Policy) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method save_policy for channel %s took %s to complete", p.Channel, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SavePolicy(ctx, token, p)
+}
+
+func (lm *loggingMiddleware) ViewPolicy(ctx context.Context, token, chanID string) (p retention.Policy, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_policy for channel %s took %s to complete", chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewPolicy(ctx, token, chanID)
+}
+
+func (lm *loggingMiddleware) RemovePolicy(ctx context.Context, token, chanID string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_policy for channel %s took %s to complete", chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemovePolicy(ctx, token, chanID)
+}
+
+func (lm *loggingMiddleware) Apply(ctx context.Context, now time.Time) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method apply_policies took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Apply(ctx, now)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+)
+
+var _ retention.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     retention.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and latency.
+func MetricsMiddleware(svc retention.Service, counter metrics.Counter, latency metrics.Histogram) retention.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) SavePolicy(ctx context.Context, token string, p retention.Policy) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "save_policy").Add(1)
+		ms.latency.With("method", "save_policy").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SavePolicy(ctx, token, p)
+}
+
+func (ms *metricsMiddleware) ViewPolicy(ctx context.Context, token, chanID string) (retention.Policy, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_policy").Add(1)
+		ms.latency.With("method", "view_policy").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewPolicy(ctx, token, chanID)
+}
+
+func (ms *metricsMiddleware) RemovePolicy(ctx context.Context, token, chanID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_policy").Add(1)
+		ms.latency.With("method", "remove_policy").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemovePolicy(ctx, token, chanID)
+}
+
+func (ms *metricsMiddleware) Apply(ctx context.Context, now time.Time) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "apply_policies").Add(1)
+		ms.latency.With("method", "apply_policies").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Apply(ctx, now)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+const (
+	rawResolution = "raw"
+	day           = 24 * time.Hour
+)
+
+var errInvalidDuration = errors.New("invalid duration")
+
+type tierReq struct {
+	Resolution string `json:"resolution,omitempty"`
+	Retention  string `json:"retention"`
+}
+
+type savePolicyReq struct {
+	token  string
+	chanID string
+	Tiers  []tierReq `json:"tiers"`
+}
+
+func (req savePolicyReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+	if req.chanID == "" {
+		return errors.ErrNotFound
+	}
+	if len(req.Tiers) == 0 {
+		return retention.ErrInvalidPolicy
+	}
+	return nil
+}
+
+type viewPolicyReq struct {
+	token  string
+	chanID string
+}
+
+func (req viewPolicyReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+	if req.chanID == "" {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+func toPolicy(req savePolicyReq) (retention.Policy, error) {
+	p := retention.Policy{Channel: req.chanID}
+	for _, t := range req.Tiers {
+		res := retention.Raw
+		if t.Resolution != "" && t.Resolution != rawResolution {
+			d, err := parseDuration(t.Resolution)
+			if err != nil {
+				return retention.Policy{}, err
+			}
+			res = d
+		}
+		ret, err := parseDuration(t.Retention)
+		if err != nil {
+			return retention.Policy{}, err
+		}
+		p.Tiers = append(p.Tiers, retention.Tier{Resolution: res, Retention: ret})
+	}
+
+	return p, nil
+}
+
+// parseDuration parses the Go duration, or the whole number of days
+// with the "d" suffix, e.g. "90d".
+func parseDuration(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		n, err := strconv.ParseUint(strings.TrimSuffix(s, "d"), 10, 32)
+		if err != nil {
+			return 0, errors.Wrap(errInvalidDuration, err)
+		}
+		return time.Duration(n) * day, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, errors.Wrap(errInvalidDuration, err)
+	}
+	return d, nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+)
+
+var (
+	_ mainflux.Response = (*savePolicyRes)(nil)
+	_ mainflux.Response = (*viewPolicyRes)(nil)
+	_ mainflux.Response = (*removePolicyRes)(nil)
+)
+
+type savePolicyRes struct{}
+
+func (res savePolicyRes) Code() int {
+	return http.StatusOK
+}
+
+func (res savePolicyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res savePolicyRes) Empty() bool {
+	return true
+}
+
+type tierRes struct {
+	Resolution string `json:"resolution"`
+	Retention  string `json:"retention"`
+}
+
+type viewPolicyRes struct {
+	Channel string     `json:"channel"`
+	Owner   string     `json:"owner"`
+	Tiers   []tierRes  `json:"tiers"`
+	Applied *time.Time `json:"applied,omitempty"`
+	Updated time.Time  `json:"updated"`
+}
+
+func (res viewPolicyRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewPolicyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewPolicyRes) Empty() bool {
+	return false
+}
+
+type removePolicyRes struct{}
+
+func (res removePolicyRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removePolicyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removePolicyRes) Empty() bool {
+	return true
+}
+
+func toViewPolicyRes(p retention.Policy) viewPolicyRes {
+	res := viewPolicyRes{
+		Channel: p.Channel,
+		Owner:   p.Owner,
+		Tiers:   []tierRes{},
+		Updated: p.Updated,
+	}
+	if !p.Applied.IsZero() {
+		res.Applied = &p.Applied
+	}
+	for _, t := range p.Tiers {
+		tr := tierRes{
+			Resolution: rawResolution,
+			Retention:  formatDuration(t.Retention),
+		}
+		if t.Resolution != retention.Raw {
+			tr.Resolution = formatDuration(t.Resolution)
+		}
+		res.Tiers = append(res.Tiers, tr)
+	}
+
+	return res
+}
+
+// formatDuration formats the duration in the largest whole unit, up to
+// the days.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d%day == 0:
+		return fmt.Sprintf("%dd", d/day)
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return d.String()
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package retention
+
+import (
+	"context"
+	"time"
+)
+
+var _ Store = (*compactionStore)(nil)
+
+type compactionStore struct {
+	compactor Compactor
+}
+
+// NewCompactionStore returns the store applying the policies by compacting
+// the messages with the given compactor every time the policies are applied.
+func NewCompactionStore(c Compactor) Store {
+	return &compactionStore{compactor: c}
+}
+
+// Apply downsamples the complete time buckets since the policy was last
+// applied, and removes the expired messages. The last bucket downsampled
+// by the previous run is downsampled again, to include the messages that
+// arrived late.
+func (cs *compactionStore) Apply(ctx context.Context, p Policy, now time.Time) error {
+	tiers := p.sorted()
+	for _, t := range tiers {
+		if t.Resolution == Raw {
+			continue
+		}
+
+		from := time.Unix(0, 0)
+		if !p.Applied.IsZero() {
+			from = truncate(p.Applied, t.Resolution).Add(-t.Resolution)
+		}
+		to := truncate(now, t.Resolution)
+		if !to.After(from) {
+			continue
+		}
+		if err := cs.compactor.Downsample(ctx, p.Channel, t.Resolution, from, to); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range tiers {
+		if err := cs.compactor.Delete(ctx, p.Channel, t.Resolution, now.Add(-t.Retention)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Remove removes all the downsampled messages of the channel, including
+// the bucket being filled at the moment.
+func (cs *compactionStore) Remove(ctx context.Context, p Policy) error {
+	now := time.Now()
+	for _, t := range p.sorted() {
+		if t.Resolution == Raw {
+			continue
+		}
+		if err := cs.compactor.Delete(ctx, p.Channel, t.Resolution, now.Add(t.Resolution)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// truncate rounds the time down to the multiple of the resolution since
+// the Unix epoch, the time buckets of the downsampled messages start at.
+func truncate(t time.Time, res time.Duration) time.Time {
+	sec := int64(res / time.Second)
+	unix := t.Unix()
+	return time.Unix(unix-unix%sec, 0)
+}
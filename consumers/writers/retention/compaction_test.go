@@ -0,0 +1,64 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package retention_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+	"github.com/mainflux/mainflux/consumers/writers/retention/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompactionApply(t *testing.T) {
+	compactor := mocks.NewCompactor(nil)
+	store := retention.NewCompactionStore(compactor)
+
+	now := time.Unix(1600000000, 0)
+	p := retention.Policy{Channel: chanID, Tiers: tiers}
+	err := store.Apply(context.Background(), p, now)
+	assert.Nil(t, err, fmt.Sprintf("apply policy: unexpected error: %s", err))
+
+	expDownsampling := []mocks.Downsampling{
+		{Channel: chanID, Resolution: time.Minute, From: time.Unix(0, 0), To: time.Unix(1599999960, 0)},
+		{Channel: chanID, Resolution: time.Hour, From: time.Unix(0, 0), To: time.Unix(1599998400, 0)},
+	}
+	assert.Equal(t, expDownsampling, compactor.Downsampling(), "apply policy: unexpected downsampling")
+
+	expDeletions := []mocks.Deletion{
+		{Channel: chanID, Resolution: retention.Raw, Before: now.Add(-7 * day)},
+		{Channel: chanID, Resolution: time.Minute, Before: now.Add(-90 * day)},
+		{Channel: chanID, Resolution: time.Hour, Before: now.Add(-730 * day)},
+	}
+	assert.Equal(t, expDeletions, compactor.Deletions(), "apply policy: unexpected deletions")
+
+	// Next run downsamples the last bucket of the previous run again.
+	p.Applied = now
+	err = store.Apply(context.Background(), p, now.Add(2*time.Minute))
+	assert.Nil(t, err, fmt.Sprintf("apply policy again: unexpected error: %s", err))
+
+	expDownsampling = []mocks.Downsampling{
+		{Channel: chanID, Resolution: time.Minute, From: time.Unix(1599999900, 0), To: time.Unix(1600000080, 0)},
+		{Channel: chanID, Resolution: time.Hour, From: time.Unix(1599994800, 0), To: time.Unix(1599998400, 0)},
+	}
+	assert.Equal(t, expDownsampling, compactor.Downsampling(), "apply policy again: unexpected downsampling")
+}
+
+func TestCompactionRemove(t *testing.T) {
+	compactor := mocks.NewCompactor(nil)
+	store := retention.NewCompactionStore(compactor)
+
+	err := store.Remove(context.Background(), retention.Policy{Channel: chanID, Tiers: tiers})
+	assert.Nil(t, err, fmt.Sprintf("remove policy: unexpected error: %s", err))
+
+	deletions := compactor.Deletions()
+	assert.Len(t, deletions, 2, "remove policy: expected downsampled messages of both resolutions removed")
+	for _, d := range deletions {
+		assert.NotEqual(t, retention.Raw, d.Resolution, "remove policy: unexpected removal of raw messages")
+		assert.True(t, d.Before.After(time.Now()), fmt.Sprintf("remove policy: expected removal of all messages got removal before %s\n", d.Before))
+	}
+}
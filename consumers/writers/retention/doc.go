@@ -0,0 +1,10 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package retention contains the per-channel retention policies of the
+// time-series writers. Policy defines how long the raw SenML messages are
+// kept, and the resolutions they are downsampled to along with the period
+// the downsampled messages are kept for. Policies are applied periodically
+// by the writer, using the database specific Store, which relies on the
+// native downsampling of the database, or compacts the messages itself.
+package retention
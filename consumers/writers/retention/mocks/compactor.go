@@ -0,0 +1,87 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+)
+
+var _ retention.Compactor = (*Compactor)(nil)
+
+// Downsampling represents the single downsampling of the channel messages.
+type Downsampling struct {
+	Channel    string
+	Resolution time.Duration
+	From       time.Time
+	To         time.Time
+}
+
+// Deletion represents the single removal of the expired channel messages.
+type Deletion struct {
+	Channel    string
+	Resolution time.Duration
+	Before     time.Time
+}
+
+// Compactor is the compactor mock recording the operations performed.
+type Compactor struct {
+	mu           sync.Mutex
+	err          error
+	downsampling []Downsampling
+	deletions    []Deletion
+}
+
+// NewCompactor returns the compactor mock. Operations fail with the given
+// error, if it's not nil.
+func NewCompactor(err error) *Compactor {
+	return &Compactor{err: err}
+}
+
+// Downsample records the downsampling.
+func (c *Compactor) Downsample(_ context.Context, chanID string, res time.Duration, from, to time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+	c.downsampling = append(c.downsampling, Downsampling{Channel: chanID, Resolution: res, From: from, To: to})
+	return nil
+}
+
+// Delete records the removal of the expired messages.
+func (c *Compactor) Delete(_ context.Context, chanID string, res time.Duration, before time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+	c.deletions = append(c.deletions, Deletion{Channel: chanID, Resolution: res, Before: before})
+	return nil
+}
+
+// Downsampling returns and clears the recorded downsampling.
+func (c *Compactor) Downsampling() []Downsampling {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ret := c.downsampling
+	c.downsampling = nil
+	return ret
+}
+
+// Deletions returns and clears the recorded removals.
+func (c *Compactor) Deletions() []Deletion {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ret := c.deletions
+	c.deletions = nil
+	return ret
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var _ retention.PolicyRepository = (*policyRepositoryMock)(nil)
+
+type policyRepositoryMock struct {
+	mu       sync.Mutex
+	policies map[string]retention.Policy
+}
+
+// NewPolicyRepository creates in-memory retention policy repository.
+func NewPolicyRepository() retention.PolicyRepository {
+	return &policyRepositoryMock{
+		policies: make(map[string]retention.Policy),
+	}
+}
+
+func (prm *policyRepositoryMock) Save(_ context.Context, p retention.Policy) error {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	prm.policies[p.Channel] = p
+	return nil
+}
+
+func (prm *policyRepositoryMock) RetrieveByChannel(_ context.Context, chanID string) (retention.Policy, error) {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	p, ok := prm.policies[chanID]
+	if !ok {
+		return retention.Policy{}, errors.ErrNotFound
+	}
+	return p, nil
+}
+
+func (prm *policyRepositoryMock) RetrieveAll(_ context.Context) ([]retention.Policy, error) {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	var policies []retention.Policy
+	for _, p := range prm.policies {
+		policies = append(policies, p)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Channel < policies[j].Channel
+	})
+
+	return policies, nil
+}
+
+func (prm *policyRepositoryMock) UpdateApplied(_ context.Context, p retention.Policy) error {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	cur, ok := prm.policies[p.Channel]
+	if !ok || !cur.Updated.Equal(p.Updated) {
+		return nil
+	}
+	cur.Applied = p.Applied
+	prm.policies[p.Channel] = cur
+
+	return nil
+}
+
+func (prm *policyRepositoryMock) Remove(_ context.Context, chanID string) error {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	delete(prm.policies, chanID)
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+)
+
+var _ retention.Store = (*Store)(nil)
+
+// Store is the retention store mock recording the policies applied and
+// removed.
+type Store struct {
+	mu      sync.Mutex
+	err     error
+	applied []retention.Policy
+	removed []retention.Policy
+}
+
+// NewStore returns the retention store mock. Operations fail with the
+// given error, if it's not nil.
+func NewStore(err error) *Store {
+	return &Store{err: err}
+}
+
+// Apply records the applied policy.
+func (s *Store) Apply(_ context.Context, p retention.Policy, _ time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	s.applied = append(s.applied, p)
+	return nil
+}
+
+// Remove records the removed policy.
+func (s *Store) Remove(_ context.Context, p retention.Policy) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	s.removed = append(s.removed, p)
+	return nil
+}
+
+// Applied returns and clears the recorded applied policies.
+func (s *Store) Applied() []retention.Policy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ret := s.applied
+	s.applied = nil
+	return ret
+}
+
+// Removed returns and clears the recorded removed policies.
+func (s *Store) Removed() []retention.Policy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ret := s.removed
+	s.removed = nil
+	return ret
+}
@@ -0,0 +1,207 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package retention
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+const (
+	// Raw is the resolution of the raw messages.
+	Raw time.Duration = 0
+
+	// RawTable is the name of the table, measurement or collection storing
+	// the raw SenML messages.
+	RawTable = "messages"
+
+	maxTiers = 10
+
+	// refreshBuckets is the number of the time buckets the raw messages
+	// must be kept for, so that the buckets are downsampled before the raw
+	// messages expire.
+	refreshBuckets = 4
+)
+
+// ErrInvalidPolicy indicates malformed retention policy.
+var ErrInvalidPolicy = errors.New("invalid retention policy")
+
+// Tier represents the single resolution of the channel messages.
+type Tier struct {
+	// Resolution is the length of the time buckets the numeric values of
+	// the raw messages are averaged over. Raw resolution stands for the
+	// raw messages.
+	Resolution time.Duration
+
+	// Retention is the period the messages of the resolution are kept for.
+	Retention time.Duration
+}
+
+// Policy represents the retention policy of the channel messages. Raw
+// messages are kept forever unless the policy contains the raw tier.
+type Policy struct {
+	Channel string
+	Owner   string
+	Tiers   []Tier
+
+	// Applied is the time the policy was last applied at. Zero value means
+	// that the policy is not applied yet.
+	Applied time.Time
+
+	// Updated is the time the policy was last saved at.
+	Updated time.Time
+}
+
+// Validate returns an error if the policy tiers are malformed. Resolutions
+// must be whole numbers of seconds, unique within the policy, and shorter
+// than the retention period. If the policy contains the raw tier, raw
+// messages must be kept for at least four buckets of every resolution,
+// and for a shorter period than the downsampled messages.
+func (p Policy) Validate() error {
+	if len(p.Tiers) == 0 || len(p.Tiers) > maxTiers {
+		return ErrInvalidPolicy
+	}
+
+	resolutions := make(map[time.Duration]bool)
+	for _, t := range p.Tiers {
+		if t.Resolution < 0 || t.Resolution%time.Second != 0 || resolutions[t.Resolution] {
+			return ErrInvalidPolicy
+		}
+		if t.Retention < time.Second || t.Retention <= t.Resolution {
+			return ErrInvalidPolicy
+		}
+		resolutions[t.Resolution] = true
+	}
+
+	raw, ok := p.Raw()
+	if !ok {
+		return nil
+	}
+	for _, t := range p.Tiers {
+		if t.Resolution == Raw {
+			continue
+		}
+		if raw.Retention < refreshBuckets*t.Resolution || raw.Retention > t.Retention {
+			return ErrInvalidPolicy
+		}
+	}
+
+	return nil
+}
+
+// Raw returns the raw tier of the policy, if the policy contains it.
+func (p Policy) Raw() (Tier, bool) {
+	for _, t := range p.Tiers {
+		if t.Resolution == Raw {
+			return t, true
+		}
+	}
+	return Tier{}, false
+}
+
+// Resolution returns the finest resolution of the policy that still keeps
+// the messages published since the given time. If none of them does, the
+// resolution kept for the longest period is returned.
+func (p Policy) Resolution(since, now time.Time) time.Duration {
+	tiers := p.sorted()
+	if len(tiers) == 0 || tiers[0].Resolution != Raw {
+		return Raw
+	}
+
+	age := now.Sub(since)
+	longest := tiers[0]
+	for _, t := range tiers {
+		if age <= t.Retention {
+			return t.Resolution
+		}
+		if t.Retention > longest.Retention {
+			longest = t
+		}
+	}
+
+	return longest.Resolution
+}
+
+// sorted returns the policy tiers sorted from the finest resolution.
+func (p Policy) sorted() []Tier {
+	tiers := append([]Tier{}, p.Tiers...)
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].Resolution < tiers[j].Resolution
+	})
+	return tiers
+}
+
+// Table returns the name of the table, measurement or collection storing
+// the messages of the given resolution.
+func Table(res time.Duration) string {
+	if res == Raw {
+		return RawTable
+	}
+	return fmt.Sprintf("%s_%d", RawTable, res/time.Second)
+}
+
+// ChannelTable returns the name of the table, measurement or retention
+// policy storing the downsampled messages of the given resolution of the
+// single channel, e.g. "messages_60_<channel_id_hex>". It's used by the
+// databases downsampling every channel separately.
+func ChannelTable(chanID string, res time.Duration) string {
+	return fmt.Sprintf("%s_%s", Table(res), ChannelSuffix(chanID))
+}
+
+// ChannelSuffix returns the suffix of the names of the channel tables.
+func ChannelSuffix(chanID string) string {
+	return strings.ReplaceAll(chanID, "-", "")
+}
+
+// PolicyRepository specifies a retention policy persistence API.
+type PolicyRepository interface {
+	// Save persists the policy, replacing the existing policy of the channel.
+	Save(ctx context.Context, p Policy) error
+
+	// RetrieveByChannel retrieves the policy of the channel identified by
+	// the given ID.
+	RetrieveByChannel(ctx context.Context, chanID string) (Policy, error)
+
+	// RetrieveAll retrieves all the policies.
+	RetrieveAll(ctx context.Context) ([]Policy, error)
+
+	// UpdateApplied updates the time the policy was applied at, unless the
+	// policy was saved again after it was retrieved.
+	UpdateApplied(ctx context.Context, p Policy) error
+
+	// Remove removes the policy of the channel identified by the given ID.
+	Remove(ctx context.Context, chanID string) error
+}
+
+// Store specifies the database operations the policies are applied with.
+type Store interface {
+	// Apply makes the database downsample the channel messages and remove
+	// the expired ones, according to the policy. Applied time of the policy
+	// is zero if the policy was saved after it was last applied.
+	Apply(ctx context.Context, p Policy, now time.Time) error
+
+	// Remove stops downsampling the channel messages, and removes the
+	// messages downsampled according to the policy.
+	Remove(ctx context.Context, p Policy) error
+}
+
+// Compactor specifies the database operations of the scheduled compaction
+// of the messages, for the databases lacking the native downsampling.
+type Compactor interface {
+	// Downsample averages the numeric values of the raw SenML messages of
+	// the channel, published in the [from, to) period, over the time
+	// buckets of the given resolution, and keeps the last string, boolean
+	// and data values of every bucket. Downsampling the same period again
+	// replaces the previously downsampled messages.
+	Downsample(ctx context.Context, chanID string, res time.Duration, from, to time.Time) error
+
+	// Delete removes the channel messages of the given resolution
+	// published before the given time.
+	Delete(ctx context.Context, chanID string, res time.Duration, before time.Time) error
+}
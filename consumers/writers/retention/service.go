@@ -0,0 +1,164 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package retention
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// ErrApply indicates failure to apply the retention policies.
+var ErrApply = errors.New("failed to apply retention policies")
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// SavePolicy saves the retention policy of the channel owned by the
+	// user identified by the provided key.
+	SavePolicy(ctx context.Context, token string, p Policy) error
+
+	// ViewPolicy retrieves the retention policy of the channel owned by
+	// the user identified by the provided key.
+	ViewPolicy(ctx context.Context, token, chanID string) (Policy, error)
+
+	// RemovePolicy removes the retention policy of the channel owned by
+	// the user identified by the provided key, along with the downsampled
+	// messages of the channel. Raw messages of the channel are kept forever
+	// afterwards.
+	RemovePolicy(ctx context.Context, token, chanID string) error
+
+	// Apply makes the store downsample and expire the messages of all the
+	// channels with the retention policy.
+	Apply(ctx context.Context, now time.Time) error
+}
+
+var _ Service = (*retentionService)(nil)
+
+type retentionService struct {
+	auth     mainflux.AuthServiceClient
+	things   mainflux.ThingsServiceClient
+	policies PolicyRepository
+	store    Store
+}
+
+// New instantiates the retention service implementation.
+func New(auth mainflux.AuthServiceClient, things mainflux.ThingsServiceClient, policies PolicyRepository, store Store) Service {
+	return &retentionService{
+		auth:     auth,
+		things:   things,
+		policies: policies,
+		store:    store,
+	}
+}
+
+func (rs *retentionService) SavePolicy(ctx context.Context, token string, p Policy) error {
+	owner, err := rs.authorize(ctx, token, p.Channel)
+	if err != nil {
+		return err
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	// Saved policy is applied from scratch, so that the store sets up the
+	// new resolutions, and downsamples all the available raw messages.
+	p.Owner = owner
+	p.Applied = time.Time{}
+	p.Updated = time.Now().UTC().Truncate(time.Millisecond)
+
+	return rs.policies.Save(ctx, p)
+}
+
+func (rs *retentionService) ViewPolicy(ctx context.Context, token, chanID string) (Policy, error) {
+	if _, err := rs.authorize(ctx, token, chanID); err != nil {
+		return Policy{}, err
+	}
+
+	return rs.policies.RetrieveByChannel(ctx, chanID)
+}
+
+func (rs *retentionService) RemovePolicy(ctx context.Context, token, chanID string) error {
+	if _, err := rs.authorize(ctx, token, chanID); err != nil {
+		return err
+	}
+
+	p, err := rs.policies.RetrieveByChannel(ctx, chanID)
+	if err != nil {
+		if errors.Contains(err, errors.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if err := rs.store.Remove(ctx, p); err != nil {
+		return err
+	}
+
+	return rs.policies.Remove(ctx, chanID)
+}
+
+func (rs *retentionService) Apply(ctx context.Context, now time.Time) error {
+	policies, err := rs.policies.RetrieveAll(ctx)
+	if err != nil {
+		return errors.Wrap(ErrApply, err)
+	}
+
+	var errs []string
+	for _, p := range policies {
+		if err := rs.apply(ctx, p, now); err != nil {
+			errs = append(errs, fmt.Sprintf("channel %s: %s", p.Channel, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Wrap(ErrApply, errors.New(strings.Join(errs, "; ")))
+	}
+	return nil
+}
+
+func (rs *retentionService) apply(ctx context.Context, p Policy, now time.Time) error {
+	if err := rs.store.Apply(ctx, p, now); err != nil {
+		return err
+	}
+
+	p.Applied = now
+	return rs.policies.UpdateApplied(ctx, p)
+}
+
+// authorize returns the ID of the user identified by the provided key, if
+// the user owns the channel identified by the given ID.
+func (rs *retentionService) authorize(ctx context.Context, token, chanID string) (string, error) {
+	res, err := rs.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", errors.Wrap(errors.ErrAuthentication, err)
+	}
+
+	req := &mainflux.ChannelOwnerReq{Owner: res.GetEmail(), ChanID: chanID}
+	if _, err := rs.things.IsChannelOwner(ctx, req); err != nil {
+		return "", errors.Wrap(errors.ErrAuthorization, err)
+	}
+
+	return res.GetId(), nil
+}
+
+// Run applies the policies periodically, until the context is canceled.
+// Failures are logged by the logging middleware, and the policies are
+// applied again on the next tick.
+func Run(ctx context.Context, svc Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			svc.Apply(ctx, now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
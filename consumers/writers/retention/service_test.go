@@ -0,0 +1,307 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package retention_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+	"github.com/mainflux/mainflux/consumers/writers/retention/mocks"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token      = "token"
+	otherToken = "other"
+	wrongToken = "wrong"
+	email      = "user@example.com"
+	otherEmail = "other@example.com"
+	chanID     = "chan"
+	otherChan  = "other"
+	day        = 24 * time.Hour
+)
+
+var tiers = []retention.Tier{
+	{Resolution: time.Hour, Retention: 730 * day},
+	{Resolution: retention.Raw, Retention: 7 * day},
+	{Resolution: time.Minute, Retention: 90 * day},
+}
+
+func newService(store retention.Store) retention.Service {
+	auth := mocks.NewAuth(map[string]string{token: email, otherToken: otherEmail})
+	things := mocks.NewThingsService(map[string]string{chanID: email, otherChan: otherEmail})
+	return retention.New(auth, things, mocks.NewPolicyRepository(), store)
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		desc  string
+		tiers []retention.Tier
+		err   error
+	}{
+		{
+			desc:  "validate policy",
+			tiers: tiers,
+		},
+		{
+			desc:  "validate policy without raw tier",
+			tiers: []retention.Tier{{Resolution: time.Minute, Retention: day}},
+		},
+		{
+			desc: "validate policy without tiers",
+			err:  retention.ErrInvalidPolicy,
+		},
+		{
+			desc:  "validate policy with duplicate resolutions",
+			tiers: []retention.Tier{{Resolution: time.Minute, Retention: day}, {Resolution: time.Minute, Retention: 2 * day}},
+			err:   retention.ErrInvalidPolicy,
+		},
+		{
+			desc:  "validate policy with fractional resolution",
+			tiers: []retention.Tier{{Resolution: 1500 * time.Millisecond, Retention: day}},
+			err:   retention.ErrInvalidPolicy,
+		},
+		{
+			desc:  "validate policy with negative resolution",
+			tiers: []retention.Tier{{Resolution: -time.Minute, Retention: day}},
+			err:   retention.ErrInvalidPolicy,
+		},
+		{
+			desc:  "validate policy with retention shorter than resolution",
+			tiers: []retention.Tier{{Resolution: day, Retention: time.Hour}},
+			err:   retention.ErrInvalidPolicy,
+		},
+		{
+			desc:  "validate policy keeping raw messages for less than four buckets",
+			tiers: []retention.Tier{{Resolution: retention.Raw, Retention: 3 * time.Hour}, {Resolution: time.Hour, Retention: day}},
+			err:   retention.ErrInvalidPolicy,
+		},
+		{
+			desc:  "validate policy keeping raw messages longer than downsampled",
+			tiers: []retention.Tier{{Resolution: retention.Raw, Retention: 7 * day}, {Resolution: time.Minute, Retention: day}},
+			err:   retention.ErrInvalidPolicy,
+		},
+		{
+			desc:  "validate policy without retention",
+			tiers: []retention.Tier{{Resolution: retention.Raw}},
+			err:   retention.ErrInvalidPolicy,
+		},
+	}
+
+	for _, tc := range cases {
+		err := retention.Policy{Channel: chanID, Tiers: tc.tiers}.Validate()
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestResolution(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		desc  string
+		tiers []retention.Tier
+		since time.Time
+		res   time.Duration
+	}{
+		{
+			desc:  "select raw resolution",
+			tiers: tiers,
+			since: now.Add(-day),
+			res:   retention.Raw,
+		},
+		{
+			desc:  "select minute resolution",
+			tiers: tiers,
+			since: now.Add(-30 * day),
+			res:   time.Minute,
+		},
+		{
+			desc:  "select hour resolution",
+			tiers: tiers,
+			since: now.Add(-365 * day),
+			res:   time.Hour,
+		},
+		{
+			desc:  "select resolution kept for the longest period",
+			tiers: tiers,
+			since: now.Add(-1000 * day),
+			res:   time.Hour,
+		},
+		{
+			desc:  "select raw resolution without raw tier",
+			tiers: []retention.Tier{{Resolution: time.Minute, Retention: day}},
+			since: now.Add(-30 * day),
+			res:   retention.Raw,
+		},
+	}
+
+	for _, tc := range cases {
+		res := retention.Policy{Tiers: tc.tiers}.Resolution(tc.since, now)
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.res, res))
+	}
+}
+
+func TestSavePolicy(t *testing.T) {
+	svc := newService(mocks.NewStore(nil))
+
+	cases := []struct {
+		desc   string
+		token  string
+		policy retention.Policy
+		err    error
+	}{
+		{
+			desc:   "save policy",
+			token:  token,
+			policy: retention.Policy{Channel: chanID, Tiers: tiers},
+		},
+		{
+			desc:   "save policy with invalid token",
+			token:  wrongToken,
+			policy: retention.Policy{Channel: chanID, Tiers: tiers},
+			err:    errors.ErrAuthentication,
+		},
+		{
+			desc:   "save policy for channel owned by other user",
+			token:  token,
+			policy: retention.Policy{Channel: otherChan, Tiers: tiers},
+			err:    errors.ErrAuthorization,
+		},
+		{
+			desc:   "save invalid policy",
+			token:  token,
+			policy: retention.Policy{Channel: chanID},
+			err:    retention.ErrInvalidPolicy,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.SavePolicy(context.Background(), tc.token, tc.policy)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestViewPolicy(t *testing.T) {
+	svc := newService(mocks.NewStore(nil))
+	err := svc.SavePolicy(context.Background(), token, retention.Policy{Channel: chanID, Tiers: tiers})
+	require.Nil(t, err, fmt.Sprintf("unexpected error saving policy: %s", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		chanID string
+		err    error
+	}{
+		{
+			desc:   "view policy",
+			token:  token,
+			chanID: chanID,
+		},
+		{
+			desc:   "view policy with invalid token",
+			token:  wrongToken,
+			chanID: chanID,
+			err:    errors.ErrAuthentication,
+		},
+		{
+			desc:   "view policy of channel owned by other user",
+			token:  otherToken,
+			chanID: chanID,
+			err:    errors.ErrAuthorization,
+		},
+		{
+			desc:   "view non-existing policy",
+			token:  otherToken,
+			chanID: otherChan,
+			err:    errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		p, err := svc.ViewPolicy(context.Background(), tc.token, tc.chanID)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, email, p.Owner, fmt.Sprintf("%s: expected owner %s got %s\n", tc.desc, email, p.Owner))
+			assert.ElementsMatch(t, tiers, p.Tiers, fmt.Sprintf("%s: expected tiers %v got %v\n", tc.desc, tiers, p.Tiers))
+		}
+	}
+}
+
+func TestRemovePolicy(t *testing.T) {
+	store := mocks.NewStore(nil)
+	svc := newService(store)
+	err := svc.SavePolicy(context.Background(), token, retention.Policy{Channel: chanID, Tiers: tiers})
+	require.Nil(t, err, fmt.Sprintf("unexpected error saving policy: %s", err))
+
+	err = svc.RemovePolicy(context.Background(), otherToken, chanID)
+	assert.True(t, errors.Contains(err, errors.ErrAuthorization), fmt.Sprintf("remove policy of channel owned by other user: expected %s got %s\n", errors.ErrAuthorization, err))
+
+	err = svc.RemovePolicy(context.Background(), token, chanID)
+	assert.Nil(t, err, fmt.Sprintf("remove policy: unexpected error: %s", err))
+
+	removed := store.Removed()
+	require.Len(t, removed, 1, "remove policy: expected one policy removed from store")
+	assert.Equal(t, chanID, removed[0].Channel, fmt.Sprintf("remove policy: expected channel %s got %s\n", chanID, removed[0].Channel))
+
+	err = svc.RemovePolicy(context.Background(), token, chanID)
+	assert.Nil(t, err, fmt.Sprintf("remove removed policy: unexpected error: %s", err))
+
+	_, err = svc.ViewPolicy(context.Background(), token, chanID)
+	assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("view removed policy: expected %s got %s\n", errors.ErrNotFound, err))
+}
+
+func TestApply(t *testing.T) {
+	store := mocks.NewStore(nil)
+	svc := newService(store)
+	err := svc.SavePolicy(context.Background(), token, retention.Policy{Channel: chanID, Tiers: tiers})
+	require.Nil(t, err, fmt.Sprintf("unexpected error saving policy: %s", err))
+
+	now := time.Unix(1600000000, 0)
+	err = svc.Apply(context.Background(), now)
+	assert.Nil(t, err, fmt.Sprintf("apply policies: unexpected error: %s", err))
+
+	applied := store.Applied()
+	require.Len(t, applied, 1, "apply policies: expected one policy applied")
+	assert.True(t, applied[0].Applied.IsZero(), fmt.Sprintf("apply saved policy: expected zero applied time got %s\n", applied[0].Applied))
+
+	err = svc.Apply(context.Background(), now.Add(time.Minute))
+	assert.Nil(t, err, fmt.Sprintf("apply policies again: unexpected error: %s", err))
+
+	applied = store.Applied()
+	require.Len(t, applied, 1, "apply policies again: expected one policy applied")
+	assert.True(t, applied[0].Applied.Equal(now), fmt.Sprintf("apply policies again: expected applied time %s got %s\n", now, applied[0].Applied))
+}
+
+func TestApplyFailure(t *testing.T) {
+	svc := newService(mocks.NewStore(errors.New("store failure")))
+	err := svc.SavePolicy(context.Background(), token, retention.Policy{Channel: chanID, Tiers: tiers})
+	require.Nil(t, err, fmt.Sprintf("unexpected error saving policy: %s", err))
+
+	err = svc.Apply(context.Background(), time.Now())
+	assert.True(t, errors.Contains(err, retention.ErrApply), fmt.Sprintf("apply policies: expected %s got %s\n", retention.ErrApply, err))
+}
+
+func TestRun(t *testing.T) {
+	store := mocks.NewStore(nil)
+	svc := newService(store)
+	err := svc.SavePolicy(context.Background(), token, retention.Policy{Channel: chanID, Tiers: tiers})
+	require.Nil(t, err, fmt.Sprintf("unexpected error saving policy: %s", err))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		retention.Run(ctx, svc, 10*time.Millisecond)
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+
+	assert.NotEmpty(t, store.Applied(), "run: expected policies applied")
+}
@@ -70,6 +70,34 @@ func migrateDB(db *sqlx.DB) error {
 					"DROP TABLE messages",
 				},
 			},
+			{
+				Id: "messages_2",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS retention_policies (
+                        channel  UUID,
+                        owner    VARCHAR(254),
+                        tiers    JSONB NOT NULL,
+                        applied  TIMESTAMPTZ,
+                        updated  TIMESTAMPTZ NOT NULL,
+                        PRIMARY KEY (channel)
+                    )`,
+				},
+				Down: []string{
+					"DROP TABLE retention_policies",
+				},
+			},
+			{
+				Id: "messages_3",
+				Up: []string{
+					`CREATE OR REPLACE FUNCTION unix_now() RETURNS BIGINT LANGUAGE SQL STABLE AS $$
+                        SELECT EXTRACT(EPOCH FROM NOW())::BIGINT
+                    $$;
+                    SELECT set_integer_now_func('messages', 'unix_now', replace_if_exists => TRUE);`,
+				},
+				Down: []string{
+					"DROP FUNCTION unix_now",
+				},
+			},
 		},
 	}
 
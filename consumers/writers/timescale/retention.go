@@ -0,0 +1,293 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package timescale
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/consumers/writers/retention"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var (
+	errSetup  = errors.New("failed to set up continuous aggregates")
+	errDelete = errors.New("failed to delete expired messages")
+	errRemove = errors.New("failed to remove continuous aggregates")
+)
+
+var _ retention.PolicyRepository = (*policyRepository)(nil)
+
+type policyRepository struct {
+	db *sqlx.DB
+}
+
+// NewPolicyRepository returns new TimescaleDB retention policy repository.
+func NewPolicyRepository(db *sqlx.DB) retention.PolicyRepository {
+	return &policyRepository{db: db}
+}
+
+func (pr policyRepository) Save(ctx context.Context, p retention.Policy) error {
+	q := `INSERT INTO retention_policies (channel, owner, tiers, applied, updated)
+          VALUES (:channel, :owner, :tiers, :applied, :updated)
+          ON CONFLICT (channel) DO UPDATE
+          SET owner = :owner, tiers = :tiers, applied = :applied, updated = :updated;`
+
+	dbp, err := toDBPolicy(p)
+	if err != nil {
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+	if _, err := pr.db.NamedExecContext(ctx, q, dbp); err != nil {
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (pr policyRepository) RetrieveByChannel(ctx context.Context, chanID string) (retention.Policy, error) {
+	q := `SELECT channel, owner, tiers, applied, updated FROM retention_policies WHERE channel = $1;`
+
+	var dbp dbPolicy
+	if err := pr.db.QueryRowxContext(ctx, q, chanID).StructScan(&dbp); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return retention.Policy{}, errors.ErrNotFound
+		}
+		if err == sql.ErrNoRows {
+			return retention.Policy{}, errors.ErrNotFound
+		}
+		return retention.Policy{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	return toPolicy(dbp)
+}
+
+func (pr policyRepository) RetrieveAll(ctx context.Context) ([]retention.Policy, error) {
+	q := `SELECT channel, owner, tiers, applied, updated FROM retention_policies ORDER BY channel;`
+
+	rows, err := pr.db.QueryxContext(ctx, q)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	var policies []retention.Policy
+	for rows.Next() {
+		var dbp dbPolicy
+		if err := rows.StructScan(&dbp); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		p, err := toPolicy(dbp)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+
+	return policies, nil
+}
+
+func (pr policyRepository) UpdateApplied(ctx context.Context, p retention.Policy) error {
+	q := `UPDATE retention_policies SET applied = $1 WHERE channel = $2 AND updated = $3;`
+
+	if _, err := pr.db.ExecContext(ctx, q, p.Applied, p.Channel, p.Updated); err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	return nil
+}
+
+func (pr policyRepository) Remove(ctx context.Context, chanID string) error {
+	q := `DELETE FROM retention_policies WHERE channel = $1;`
+
+	if _, err := pr.db.ExecContext(ctx, q, chanID); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
+type dbPolicy struct {
+	Channel string       `db:"channel"`
+	Owner   string       `db:"owner"`
+	Tiers   []byte       `db:"tiers"`
+	Applied sql.NullTime `db:"applied"`
+	Updated time.Time    `db:"updated"`
+}
+
+func toDBPolicy(p retention.Policy) (dbPolicy, error) {
+	tiers, err := json.Marshal(p.Tiers)
+	if err != nil {
+		return dbPolicy{}, err
+	}
+
+	return dbPolicy{
+		Channel: p.Channel,
+		Owner:   p.Owner,
+		Tiers:   tiers,
+		Applied: sql.NullTime{Time: p.Applied, Valid: !p.Applied.IsZero()},
+		Updated: p.Updated,
+	}, nil
+}
+
+func toPolicy(dbp dbPolicy) (retention.Policy, error) {
+	var tiers []retention.Tier
+	if err := json.Unmarshal(dbp.Tiers, &tiers); err != nil {
+		return retention.Policy{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	p := retention.Policy{
+		Channel: dbp.Channel,
+		Owner:   dbp.Owner,
+		Tiers:   tiers,
+		Updated: dbp.Updated,
+	}
+	if dbp.Applied.Valid {
+		p.Applied = dbp.Applied.Time
+	}
+
+	return p, nil
+}
+
+var _ retention.Store = (*retentionStore)(nil)
+
+type retentionStore struct {
+	db *sqlx.DB
+}
+
+// NewRetentionStore returns new TimescaleDB store the retention policies
+// are applied with. Messages of every channel are downsampled by the
+// continuous aggregates named after the channel and their resolution,
+// e.g. "messages_60_<channel_id_hex>", which are refreshed and expired by
+// the TimescaleDB jobs. Raw messages of all the channels share the
+// hypertable, so the raw tier is enforced by removing the expired raw
+// messages of the channel every time the policy is applied.
+func NewRetentionStore(db *sqlx.DB) retention.Store {
+	return &retentionStore{db: db}
+}
+
+func (rs retentionStore) Apply(ctx context.Context, p retention.Policy, now time.Time) error {
+	if p.Applied.IsZero() {
+		if err := rs.setup(ctx, p); err != nil {
+			return errors.Wrap(errSetup, err)
+		}
+	}
+
+	raw, ok := p.Raw()
+	if !ok {
+		return nil
+	}
+	q := `DELETE FROM messages WHERE channel = $1 AND time < $2;`
+	if _, err := rs.db.ExecContext(ctx, q, p.Channel, now.Add(-raw.Retention).Unix()); err != nil {
+		return errors.Wrap(errDelete, err)
+	}
+
+	return nil
+}
+
+func (rs retentionStore) Remove(ctx context.Context, p retention.Policy) error {
+	views, err := rs.views(ctx, p.Channel)
+	if err != nil {
+		return errors.Wrap(errRemove, err)
+	}
+	for _, v := range views {
+		if err := rs.drop(ctx, v); err != nil {
+			return errors.Wrap(errRemove, err)
+		}
+	}
+
+	return nil
+}
+
+// setup creates the continuous aggregates of the policy resolutions,
+// (re)schedules their refresh and expiry, and drops the continuous
+// aggregates of the resolutions removed from the policy.
+func (rs retentionStore) setup(ctx context.Context, p retention.Policy) error {
+	views := make(map[string]retention.Tier)
+	for _, t := range p.Tiers {
+		if t.Resolution != retention.Raw {
+			views[retention.ChannelTable(p.Channel, t.Resolution)] = t
+		}
+	}
+
+	existing, err := rs.views(ctx, p.Channel)
+	if err != nil {
+		return err
+	}
+	for _, v := range existing {
+		if _, ok := views[v]; ok {
+			continue
+		}
+		if err := rs.drop(ctx, v); err != nil {
+			return err
+		}
+	}
+
+	// Buckets are refreshed until the raw messages expire, so that the
+	// expiry of the raw messages doesn't remove the downsampled ones.
+	raw, hasRaw := p.Raw()
+	for v, t := range views {
+		sec := int64(t.Resolution / time.Second)
+		q := fmt.Sprintf(`CREATE MATERIALIZED VIEW IF NOT EXISTS %s WITH (timescaledb.continuous) AS
+            SELECT time_bucket(%d, time) AS time, channel, subtopic, publisher, protocol, name, unit,
+                AVG(value) AS value, last(string_value, time) AS string_value,
+                last(bool_value, time) AS bool_value, last(data_value, time) AS data_value
+            FROM messages
+            WHERE channel = %s
+            GROUP BY time_bucket(%d, time), channel, subtopic, publisher, protocol, name, unit
+            WITH NO DATA;`, pq.QuoteIdentifier(v), sec, pq.QuoteLiteral(p.Channel), sec)
+		if _, err := rs.db.ExecContext(ctx, q); err != nil {
+			return err
+		}
+
+		start := sql.NullInt64{}
+		if hasRaw {
+			start = sql.NullInt64{Int64: int64((raw.Retention - t.Resolution) / time.Second), Valid: true}
+		}
+		q = `SELECT remove_continuous_aggregate_policy($1::REGCLASS, if_exists => TRUE);`
+		if _, err := rs.db.ExecContext(ctx, q, v); err != nil {
+			return err
+		}
+		q = `SELECT add_continuous_aggregate_policy($1::REGCLASS, start_offset => $2::BIGINT,
+            end_offset => $3::BIGINT, schedule_interval => $4::INTERVAL);`
+		if _, err := rs.db.ExecContext(ctx, q, v, start, sec, fmt.Sprintf("%d seconds", sec)); err != nil {
+			return err
+		}
+
+		q = `SELECT remove_retention_policy($1::REGCLASS, if_exists => TRUE);`
+		if _, err := rs.db.ExecContext(ctx, q, v); err != nil {
+			return err
+		}
+		q = `SELECT add_retention_policy($1::REGCLASS, drop_after => $2::BIGINT);`
+		if _, err := rs.db.ExecContext(ctx, q, v, int64(t.Retention/time.Second)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// views returns the names of the continuous aggregates of the channel.
+func (rs retentionStore) views(ctx context.Context, chanID string) ([]string, error) {
+	q := `SELECT view_name FROM timescaledb_information.continuous_aggregates WHERE view_name LIKE $1;`
+
+	// Underscores are escaped, since they match any character.
+	pattern := fmt.Sprintf(`%s\_%%\_%s`, retention.RawTable, retention.ChannelSuffix(chanID))
+	var views []string
+	if err := rs.db.SelectContext(ctx, &views, q, pattern); err != nil {
+		return nil, err
+	}
+
+	return views, nil
+}
+
+func (rs retentionStore) drop(ctx context.Context, view string) error {
+	q := fmt.Sprintf(`DROP MATERIALIZED VIEW IF EXISTS %s;`, pq.QuoteIdentifier(view))
+	_, err := rs.db.ExecContext(ctx, q)
+	return err
+}
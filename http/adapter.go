@@ -7,29 +7,92 @@ package http
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/presence"
+	"github.com/mainflux/mainflux/pkg/quota"
+	"github.com/mainflux/mainflux/pkg/retained"
+	"github.com/mainflux/mainflux/pkg/schema"
 )
 
+const (
+	chansPrefix = "channels"
+	// HistorySize is the number of the latest messages kept per subscribed
+	// channel (and subtopic) to be replayed to the reconnecting clients.
+	// Clients must be able to buffer that many messages without blocking.
+	HistorySize = 100
+	// keepAlive is the period the subscription and its history are kept
+	// after the last client leaves, so that the client can reconnect.
+	keepAlive = time.Minute
+)
+
+// ErrFailedSubscription indicates that client couldn't subscribe to specified channel.
+var ErrFailedSubscription = errors.New("failed to subscribe to a channel")
+
 // Service specifies coap service API.
 type Service interface {
 	// Publish Messssage
 	Publish(ctx context.Context, token string, msg messaging.Message) error
+
+	// Subscribe subscribes the client to the channel with specified id and subtopic.
+	// If lastID is not zero, buffered events following the event with the given
+	// ID are forwarded to the client before the new ones.
+	Subscribe(ctx context.Context, token, chanID, subtopic string, lastID int64, c Client) error
+
+	// Unsubscribe removes the client with the given ID from the channel
+	// with specified id and subtopic.
+	Unsubscribe(ctx context.Context, chanID, subtopic, clientID string) error
+
+	// Latest returns the last message published to every subtopic of the
+	// channel with specified id. If the subtopic is not empty, only the
+	// last message of the given subtopic is returned.
+	Latest(ctx context.Context, token, chanID, subtopic string) ([]messaging.Message, error)
 }
 
 var _ Service = (*adapterService)(nil)
 
+// stream represents a broker subscription shared by the clients
+// observing the same subject.
+type stream struct {
+	clients map[string]Client
+	history []Event
+	seq     int64
+	expiry  *time.Timer
+}
+
 type adapterService struct {
-	publisher messaging.Publisher
-	things    mainflux.ThingsServiceClient
+	pubsub  messaging.PubSub
+	things  mainflux.ThingsServiceClient
+	schemas schema.Validator
+	store   retained.Store
+	limiter quota.Limiter
+	tracker presence.Tracker
+	logger  logger.Logger
+	streams map[string]*stream
+	mu      sync.Mutex
 }
 
-// New instantiates the HTTP adapter implementation.
-func New(publisher messaging.Publisher, things mainflux.ThingsServiceClient) Service {
+// New instantiates the HTTP adapter implementation. The published payloads
+// are validated against the channel schemas using the given validator, and
+// the latest messages are read from the given last-value store. The quotas
+// of the publishing things are enforced using the given limiter, and their
+// activity is recorded using the given presence tracker.
+func New(pubsub messaging.PubSub, things mainflux.ThingsServiceClient, schemas schema.Validator, store retained.Store, limiter quota.Limiter, tracker presence.Tracker, logger logger.Logger) Service {
 	return &adapterService{
-		publisher: publisher,
-		things:    things,
+		pubsub:  pubsub,
+		things:  things,
+		schemas: schemas,
+		store:   store,
+		limiter: limiter,
+		tracker: tracker,
+		logger:  logger,
+		streams: make(map[string]*stream),
 	}
 }
 
@@ -44,5 +107,155 @@ func (as *adapterService) Publish(ctx context.Context, token string, msg messagi
 	}
 	msg.Publisher = thid.GetValue()
 
-	return as.publisher.Publish(msg.Channel, msg)
+	if err := as.limiter.Allow(ctx, msg.Publisher, len(msg.Payload)); err != nil {
+		return err
+	}
+
+	if err := as.schemas.Validate(ctx, msg.Channel, msg.ContentType, msg.Payload); err != nil {
+		return err
+	}
+
+	if err := as.pubsub.Publish(msg.Channel, msg); err != nil {
+		return err
+	}
+
+	// The presence is tracked on the best-effort basis, so that the
+	// tracking failure doesn't fail the already published message.
+	as.tracker.Seen(ctx, msg.Publisher, msg.Protocol)
+	return nil
+}
+
+func (as *adapterService) Subscribe(ctx context.Context, token, chanID, subtopic string, lastID int64, c Client) error {
+	ar := &mainflux.AccessByKeyReq{
+		Token:  token,
+		ChanID: chanID,
+	}
+	if _, err := as.things.CanAccessByKey(ctx, ar); err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("%s.%s", chansPrefix, chanID)
+	if subtopic != "" {
+		subject = fmt.Sprintf("%s.%s", subject, subtopic)
+	}
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	s, ok := as.streams[subject]
+	if !ok {
+		// Sequence starts at the creation time, so that the IDs keep
+		// growing when the expired subscription is created again.
+		s = &stream{clients: make(map[string]Client), seq: time.Now().UnixNano()}
+		if err := as.pubsub.Subscribe(subject, as.broadcast(subject)); err != nil {
+			return errors.Wrap(ErrFailedSubscription, err)
+		}
+		as.streams[subject] = s
+	}
+	if s.expiry != nil {
+		s.expiry.Stop()
+		s.expiry = nil
+	}
+
+	// Replay missed messages while holding the lock, so
+	// that no new message can be forwarded before them.
+	if lastID != 0 {
+		for _, e := range s.history {
+			if e.ID > lastID {
+				c.Handle(e)
+			}
+		}
+	}
+	s.clients[c.ID()] = c
+
+	return nil
+}
+
+func (as *adapterService) Unsubscribe(ctx context.Context, chanID, subtopic, clientID string) error {
+	subject := fmt.Sprintf("%s.%s", chansPrefix, chanID)
+	if subtopic != "" {
+		subject = fmt.Sprintf("%s.%s", subject, subtopic)
+	}
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	s, ok := as.streams[subject]
+	if !ok {
+		return nil
+	}
+	delete(s.clients, clientID)
+	if len(s.clients) == 0 && s.expiry == nil {
+		s.expiry = time.AfterFunc(keepAlive, func() { as.expire(subject, s) })
+	}
+
+	return nil
+}
+
+func (as *adapterService) Latest(ctx context.Context, token, chanID, subtopic string) ([]messaging.Message, error) {
+	ar := &mainflux.AccessByKeyReq{
+		Token:  token,
+		ChanID: chanID,
+	}
+	if _, err := as.things.CanAccessByKey(ctx, ar); err != nil {
+		return nil, err
+	}
+
+	if subtopic == "" {
+		return as.store.RetrieveAll(ctx, chanID)
+	}
+
+	msg, err := as.store.Retrieve(ctx, chanID, subtopic)
+	if err != nil {
+		return nil, err
+	}
+	return []messaging.Message{msg}, nil
+}
+
+// expire removes the broker subscription if no client subscribed in the meantime.
+func (as *adapterService) expire(subject string, s *stream) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	if as.streams[subject] != s || len(s.clients) > 0 {
+		return
+	}
+	delete(as.streams, subject)
+	// There is no one to report the error to, the broker
+	// subscription is left to be cleaned up on shutdown.
+	as.pubsub.Unsubscribe(subject)
+}
+
+func (as *adapterService) broadcast(subject string) messaging.MessageHandler {
+	return func(msg messaging.Message) error {
+		as.mu.Lock()
+		s, ok := as.streams[subject]
+		if !ok {
+			as.mu.Unlock()
+			return nil
+		}
+		s.seq++
+		e := Event{ID: s.seq, Message: msg}
+		s.history = append(s.history, e)
+		if len(s.history) > HistorySize {
+			s.history = s.history[len(s.history)-HistorySize:]
+		}
+		// Send outside of the lock, clients subscribing in the meantime
+		// find the event in the history.
+		clients := make([]Client, 0, len(s.clients))
+		for _, c := range s.clients {
+			clients = append(clients, c)
+		}
+		as.mu.Unlock()
+
+		// The failure of a single client must not cause the redelivery
+		// of the message to all the others.
+		for _, c := range clients {
+			if err := c.Handle(e); err != nil {
+				as.logger.Warn(fmt.Sprintf("Failed to forward message to client %s: %s", c.ID(), err))
+			}
+		}
+
+		return nil
+	}
 }
@@ -0,0 +1,50 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"sync"
+
+	adapter "github.com/mainflux/mainflux/http"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// bufferSize is the number of messages buffered for a client before the
+// stream is considered too slow and closed. It fits the replayed history
+// along with the messages published while the history is being sent.
+const bufferSize = adapter.HistorySize + 64
+
+var errSlowClient = errors.New("client is too slow to receive messages")
+
+var _ adapter.Client = (*client)(nil)
+
+// client represents a server-sent events subscriber.
+type client struct {
+	id       string
+	events   chan adapter.Event
+	overflow chan struct{}
+	once     sync.Once
+}
+
+func newClient(id string) *client {
+	return &client{
+		id:       id,
+		events:   make(chan adapter.Event, bufferSize),
+		overflow: make(chan struct{}),
+	}
+}
+
+func (c *client) ID() string {
+	return c.id
+}
+
+func (c *client) Handle(e adapter.Event) error {
+	select {
+	case c.events <- e:
+		return nil
+	default:
+		c.once.Do(func() { close(c.overflow) })
+		return errSlowClient
+	}
+}
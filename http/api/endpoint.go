@@ -22,3 +22,24 @@ func sendMessageEndpoint(svc http.Service) endpoint.Endpoint {
 		return nil, err
 	}
 }
+
+func latestEndpoint(svc http.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(latestReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		msgs, err := svc.Latest(ctx, req.token, req.chanID, req.subtopic)
+		if err != nil {
+			return nil, err
+		}
+
+		res := latestRes{Messages: []retainedMessage{}}
+		for _, msg := range msgs {
+			res.Messages = append(res.Messages, newRetainedMessage(msg))
+		}
+		return res, nil
+	}
+}
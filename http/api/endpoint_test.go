@@ -4,6 +4,9 @@
 package api_test
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -18,16 +21,35 @@ import (
 	"github.com/mainflux/mainflux/http/api"
 	"github.com/mainflux/mainflux/http/mocks"
 	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	pmocks "github.com/mainflux/mainflux/pkg/presence/mocks"
+	"github.com/mainflux/mainflux/pkg/quota"
+	qmocks "github.com/mainflux/mainflux/pkg/quota/mocks"
+	rmocks "github.com/mainflux/mainflux/pkg/retained/mocks"
+	"github.com/mainflux/mainflux/pkg/schema"
+	smocks "github.com/mainflux/mainflux/pkg/schema/mocks"
+	"github.com/mainflux/mainflux/pkg/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func newService(cc mainflux.ThingsServiceClient) adapter.Service {
-	pub := mocks.NewPublisher()
-	return adapter.New(pub, cc)
+	return newServiceWithSchemas(cc, nil)
+}
+
+func newServiceWithSchemas(cc mainflux.ThingsServiceClient, schemas map[string]*schema.Schema) adapter.Service {
+	pub := mocks.NewPubSub()
+	return adapter.New(pub, cc, smocks.NewValidator(schemas), rmocks.NewStore(), qmocks.NewLimiter(nil), pmocks.NewTracker(), logger.NewMock())
+}
+
+func newServiceWithLimits(cc mainflux.ThingsServiceClient, limits map[string]quota.Limits) adapter.Service {
+	pub := mocks.NewPubSub()
+	return adapter.New(pub, cc, smocks.NewValidator(nil), rmocks.NewStore(), qmocks.NewLimiter(limits), pmocks.NewTracker(), logger.NewMock())
 }
 
 func newHTTPServer(svc adapter.Service) *httptest.Server {
-	mux := api.MakeHandler(svc, mocktracer.New())
+	mux := api.MakeHandler(svc, mocktracer.New(), uuid.NewMock(), logger.NewMock())
 	return httptest.NewServer(mux)
 }
 
@@ -64,9 +86,17 @@ func TestPublish(t *testing.T) {
 	contentType := "application/senml+json"
 	thingKey := "thing_key"
 	invalidKey := "invalid_key"
+	schemaChanID := "2"
+	schemaKey := "schema_key"
 	msg := `[{"n":"current","t":-1,"v":1.6}]`
-	thingsClient := mocks.NewThingsClient(map[string]string{thingKey: chanID})
-	svc := newService(thingsClient)
+	thingsClient := mocks.NewThingsClient(map[string]string{thingKey: chanID, schemaKey: schemaChanID})
+	sch, err := schema.Parse(map[string]interface{}{
+		schema.MetadataKey: map[string]interface{}{
+			"senml": []interface{}{map[string]interface{}{"name": "current", "unit": "A"}},
+		},
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error parsing schema: %s", err))
+	svc := newServiceWithSchemas(thingsClient, map[string]*schema.Schema{schemaChanID: sch})
 	ts := newHTTPServer(svc)
 	defer ts.Close()
 
@@ -115,6 +145,27 @@ func TestPublish(t *testing.T) {
 			basicAuth:   true,
 			status:      http.StatusUnauthorized,
 		},
+		"publish SenML CBOR message": {
+			chanID:      chanID,
+			msg:         "\x81\xa2\x00\x67current\x02\xfb\x3f\xf9\x99\x99\x99\x99\x99\x9a",
+			contentType: "application/senml+cbor",
+			key:         thingKey,
+			status:      http.StatusAccepted,
+		},
+		"publish SenML XML message": {
+			chanID:      chanID,
+			msg:         `<sensml xmlns="urn:ietf:params:xml:ns:senml"><senml n="current" v="1.6"></senml></sensml>`,
+			contentType: "application/senml+xml; charset=utf-8",
+			key:         thingKey,
+			status:      http.StatusAccepted,
+		},
+		"publish message with unsupported content type": {
+			chanID:      chanID,
+			msg:         msg,
+			contentType: "application/json",
+			key:         thingKey,
+			status:      http.StatusUnsupportedMediaType,
+		},
 		"publish message without content type": {
 			chanID:      chanID,
 			msg:         msg,
@@ -129,6 +180,27 @@ func TestPublish(t *testing.T) {
 			key:         thingKey,
 			status:      http.StatusBadRequest,
 		},
+		"publish conforming message to channel with schema": {
+			chanID:      schemaChanID,
+			msg:         `[{"n":"current","u":"A","t":-1,"v":1.6}]`,
+			contentType: contentType,
+			key:         schemaKey,
+			status:      http.StatusAccepted,
+		},
+		"publish message with invalid unit to channel with schema": {
+			chanID:      schemaChanID,
+			msg:         msg,
+			contentType: contentType,
+			key:         schemaKey,
+			status:      http.StatusBadRequest,
+		},
+		"publish message with unknown record to channel with schema": {
+			chanID:      schemaChanID,
+			msg:         `[{"n":"voltage","u":"V","t":-1,"v":230}]`,
+			contentType: contentType,
+			key:         schemaKey,
+			status:      http.StatusBadRequest,
+		},
 		"publish message unable to authorize": {
 			chanID:      chanID,
 			msg:         msg,
@@ -153,3 +225,348 @@ func TestPublish(t *testing.T) {
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
 	}
 }
+
+func TestPublishQuota(t *testing.T) {
+	chanID := "1"
+	sizeChanID := "2"
+	contentType := "application/senml+json"
+	thingKey := "thing_key"
+	sizeKey := "size_key"
+	msg := `[{"n":"current","t":-1,"v":1.6}]`
+	thingsClient := mocks.NewThingsClient(map[string]string{thingKey: chanID, sizeKey: sizeChanID})
+	// Things client mock identifies the thing by the ID of the channel.
+	svc := newServiceWithLimits(thingsClient, map[string]quota.Limits{
+		chanID:     {ThingMessages: 1},
+		sizeChanID: {ThingPayloadSize: uint64(len(msg))},
+	})
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	cases := []struct {
+		desc   string
+		chanID string
+		msg    string
+		key    string
+		status int
+	}{
+		{
+			desc:   "publish message within rate quota",
+			chanID: chanID,
+			msg:    msg,
+			key:    thingKey,
+			status: http.StatusAccepted,
+		},
+		{
+			desc:   "publish message exceeding rate quota",
+			chanID: chanID,
+			msg:    msg,
+			key:    thingKey,
+			status: http.StatusTooManyRequests,
+		},
+		{
+			desc:   "publish message within payload size quota",
+			chanID: sizeChanID,
+			msg:    msg,
+			key:    sizeKey,
+			status: http.StatusAccepted,
+		},
+		{
+			desc:   "publish message exceeding payload size quota",
+			chanID: sizeChanID,
+			msg:    `[{"n":"current","t":-1,"v":1.65}]`,
+			key:    sizeKey,
+			status: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels/%s/messages", ts.URL, tc.chanID),
+			contentType: contentType,
+			token:       tc.key,
+			body:        strings.NewReader(tc.msg),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	chanID := "1"
+	thingKey := "thing_key"
+	invalidKey := "invalid_key"
+	thingsClient := mocks.NewThingsClient(map[string]string{thingKey: chanID})
+	svc := newService(thingsClient)
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	cases := map[string]struct {
+		url         string
+		key         string
+		lastEventID string
+		basicAuth   bool
+		status      int
+	}{
+		"subscribe to channel": {
+			url:    fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID),
+			key:    thingKey,
+			status: http.StatusOK,
+		},
+		"subscribe to channel with subtopic": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/engine/temp", ts.URL, chanID),
+			key:    thingKey,
+			status: http.StatusOK,
+		},
+		"subscribe to channel with basic auth": {
+			url:       fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID),
+			key:       thingKey,
+			basicAuth: true,
+			status:    http.StatusOK,
+		},
+		"subscribe to channel with key in query": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?authorization=%s", ts.URL, chanID, thingKey),
+			status: http.StatusOK,
+		},
+		"subscribe to channel with last event ID": {
+			url:         fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID),
+			key:         thingKey,
+			lastEventID: "1",
+			status:      http.StatusOK,
+		},
+		"subscribe to channel with invalid last event ID": {
+			url:         fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID),
+			key:         thingKey,
+			lastEventID: "invalid",
+			status:      http.StatusBadRequest,
+		},
+		"subscribe to channel with malformed subtopic": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/a*b", ts.URL, chanID),
+			key:    thingKey,
+			status: http.StatusBadRequest,
+		},
+		"subscribe to channel with empty key": {
+			url:    fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID),
+			key:    "",
+			status: http.StatusUnauthorized,
+		},
+		"subscribe to channel with invalid key": {
+			url:    fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID),
+			key:    invalidKey,
+			status: http.StatusUnauthorized,
+		},
+		"subscribe to channel unable to authorize": {
+			url:    fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID),
+			key:    mocks.ServiceErrToken,
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for desc, tc := range cases {
+		ctx, cancel := context.WithCancel(context.Background())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, tc.url, nil)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		if tc.key != "" {
+			req.Header.Set("Authorization", httputil.BearerPrefix+tc.key)
+		}
+		if tc.basicAuth {
+			req.SetBasicAuth("", tc.key)
+		}
+		if tc.lastEventID != "" {
+			req.Header.Set("Last-Event-ID", tc.lastEventID)
+		}
+		res, err := ts.Client().Do(req)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+		if tc.status == http.StatusOK {
+			assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"), fmt.Sprintf("%s: unexpected content type", desc))
+		}
+		cancel()
+		res.Body.Close()
+	}
+}
+
+func TestStream(t *testing.T) {
+	chanID := "1"
+	thingKey := "thing_key"
+	thingsClient := mocks.NewThingsClient(map[string]string{thingKey: chanID})
+	svc := newService(thingsClient)
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	subscribe := func(lastEventID string) (*bufio.Reader, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID), nil)
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+		req.Header.Set("Authorization", httputil.BearerPrefix+thingKey)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		res, err := ts.Client().Do(req)
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+		r := bufio.NewReader(res.Body)
+		// Skip the reconnection time advertisement.
+		readEvent(t, r)
+		return r, cancel
+	}
+
+	r, cancel := subscribe("")
+	defer cancel()
+
+	for i := 1; i <= 3; i++ {
+		msg := messaging.Message{
+			Channel: chanID,
+			Payload: []byte(fmt.Sprintf("line %d\nnext", i)),
+			Created: int64(i),
+		}
+		err := svc.Publish(context.Background(), thingKey, msg)
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	}
+
+	// Event IDs are the consecutive sequence numbers of the subscription.
+	var ids []int64
+	for i := 1; i <= 3; i++ {
+		event := readEvent(t, r)
+		require.Len(t, event, 3, "unexpected event received")
+		var id int64
+		_, err := fmt.Sscanf(event[0], "id: %d", &id)
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+		if len(ids) > 0 {
+			assert.Equal(t, ids[len(ids)-1]+1, id, "unexpected event ID")
+		}
+		ids = append(ids, id)
+		expected := []string{fmt.Sprintf("data: line %d", i), "data: next"}
+		assert.Equal(t, expected, event[1:], "unexpected event received")
+	}
+
+	// Reconnecting client receives messages published after the last received one.
+	rr, rcancel := subscribe(fmt.Sprint(ids[1]))
+	defer rcancel()
+	expected := []string{fmt.Sprintf("id: %d", ids[2]), "data: line 3", "data: next"}
+	assert.Equal(t, expected, readEvent(t, rr), "unexpected event replayed")
+
+	// Whole history is replayed to the reconnecting client without closing
+	// the stream.
+	for i := 0; i < adapter.HistorySize; i++ {
+		msg := messaging.Message{Channel: chanID, Payload: []byte(fmt.Sprintf("history %d", i))}
+		err := svc.Publish(context.Background(), thingKey, msg)
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	}
+	hr, hcancel := subscribe(fmt.Sprint(ids[0]))
+	defer hcancel()
+	for i := 0; i < adapter.HistorySize; i++ {
+		expected := []string{fmt.Sprintf("id: %d", ids[2]+int64(i)+1), fmt.Sprintf("data: history %d", i)}
+		assert.Equal(t, expected, readEvent(t, hr), "unexpected history event replayed")
+	}
+}
+
+func TestLatest(t *testing.T) {
+	chanID := "1"
+	thingKey := "thing_key"
+	invalidKey := "invalid_key"
+	thingsClient := mocks.NewThingsClient(map[string]string{thingKey: chanID})
+	store := rmocks.NewStore()
+	svc := adapter.New(mocks.NewPubSub(), thingsClient, smocks.NewValidator(nil), store, qmocks.NewLimiter(nil), pmocks.NewTracker(), logger.NewMock())
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	msgs := []messaging.Message{
+		{
+			Channel:     chanID,
+			Subtopic:    "engine.temp",
+			Publisher:   "publisher",
+			Protocol:    "mqtt",
+			Payload:     []byte(`[{"n":"temp","v":90}]`),
+			ContentType: "application/senml+json",
+			Created:     1,
+		},
+		{
+			Channel:   chanID,
+			Subtopic:  "raw",
+			Publisher: "publisher",
+			Protocol:  "coap",
+			Payload:   []byte{0xFF},
+			Created:   2,
+		},
+	}
+	for _, msg := range msgs {
+		err := store.Save(context.Background(), msg)
+		require.Nil(t, err, fmt.Sprintf("unexpected error saving message: %s", err))
+	}
+
+	temp := `{"subtopic":"engine.temp","publisher":"publisher","protocol":"mqtt","content_type":"application/senml+json","created":1,"payload":[{"n":"temp","v":90}]}`
+	raw := `{"subtopic":"raw","publisher":"publisher","protocol":"coap","created":2,"data":"/w=="}`
+
+	cases := map[string]struct {
+		url    string
+		key    string
+		status int
+		res    string
+	}{
+		"retrieve latest messages of channel": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/latest", ts.URL, chanID),
+			key:    thingKey,
+			status: http.StatusOK,
+			res:    fmt.Sprintf(`{"messages":[%s,%s]}`, temp, raw),
+		},
+		"retrieve latest message of subtopic": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/latest?subtopic=engine/temp", ts.URL, chanID),
+			key:    thingKey,
+			status: http.StatusOK,
+			res:    fmt.Sprintf(`{"messages":[%s]}`, temp),
+		},
+		"retrieve latest message of subtopic without messages": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/latest?subtopic=engine", ts.URL, chanID),
+			key:    thingKey,
+			status: http.StatusNotFound,
+		},
+		"retrieve latest message of wildcard subtopic": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/latest?subtopic=engine.*", ts.URL, chanID),
+			key:    thingKey,
+			status: http.StatusBadRequest,
+		},
+		"retrieve latest messages with empty key": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/latest", ts.URL, chanID),
+			status: http.StatusUnauthorized,
+		},
+		"retrieve latest messages with invalid key": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/latest", ts.URL, chanID),
+			key:    invalidKey,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for desc, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.key,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+		if tc.res == "" {
+			continue
+		}
+		var body json.RawMessage
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.JSONEq(t, tc.res, string(body), fmt.Sprintf("%s: unexpected response body", desc))
+	}
+}
+
+func readEvent(t *testing.T, r *bufio.Reader) []string {
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
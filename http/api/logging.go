@@ -43,3 +43,54 @@ func (lm *loggingMiddleware) Publish(ctx context.Context, token string, msg mess
 
 	return lm.svc.Publish(ctx, token, msg)
 }
+
+func (lm *loggingMiddleware) Subscribe(ctx context.Context, token, chanID, subtopic string, lastID int64, c http.Client) (err error) {
+	defer func(begin time.Time) {
+		destChannel := chanID
+		if subtopic != "" {
+			destChannel = fmt.Sprintf("%s.%s", destChannel, subtopic)
+		}
+		message := fmt.Sprintf("Method subscribe to channel %s for client %s took %s to complete", destChannel, c.ID(), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Subscribe(ctx, token, chanID, subtopic, lastID, c)
+}
+
+func (lm *loggingMiddleware) Unsubscribe(ctx context.Context, chanID, subtopic, clientID string) (err error) {
+	defer func(begin time.Time) {
+		destChannel := chanID
+		if subtopic != "" {
+			destChannel = fmt.Sprintf("%s.%s", destChannel, subtopic)
+		}
+		message := fmt.Sprintf("Method unsubscribe from channel %s for client %s took %s to complete", destChannel, clientID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Unsubscribe(ctx, chanID, subtopic, clientID)
+}
+
+func (lm *loggingMiddleware) Latest(ctx context.Context, token, chanID, subtopic string) (msgs []messaging.Message, err error) {
+	defer func(begin time.Time) {
+		destChannel := chanID
+		if subtopic != "" {
+			destChannel = fmt.Sprintf("%s.%s", destChannel, subtopic)
+		}
+		message := fmt.Sprintf("Method latest for channel %s took %s to complete", destChannel, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Latest(ctx, token, chanID, subtopic)
+}
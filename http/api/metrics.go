@@ -39,3 +39,30 @@ func (mm *metricsMiddleware) Publish(ctx context.Context, token string, msg mess
 
 	return mm.svc.Publish(ctx, token, msg)
 }
+
+func (mm *metricsMiddleware) Subscribe(ctx context.Context, token, chanID, subtopic string, lastID int64, c http.Client) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "subscribe").Add(1)
+		mm.latency.With("method", "subscribe").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Subscribe(ctx, token, chanID, subtopic, lastID, c)
+}
+
+func (mm *metricsMiddleware) Unsubscribe(ctx context.Context, chanID, subtopic, clientID string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "unsubscribe").Add(1)
+		mm.latency.With("method", "unsubscribe").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Unsubscribe(ctx, chanID, subtopic, clientID)
+}
+
+func (mm *metricsMiddleware) Latest(ctx context.Context, token, chanID, subtopic string) ([]messaging.Message, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "latest").Add(1)
+		mm.latency.With("method", "latest").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Latest(ctx, token, chanID, subtopic)
+}
@@ -20,3 +20,40 @@ func (req publishReq) validate() error {
 
 	return nil
 }
+
+type subscribeReq struct {
+	token    string
+	chanID   string
+	subtopic string
+	lastID   int64
+}
+
+func (req subscribeReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+
+	if req.chanID == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type latestReq struct {
+	token    string
+	chanID   string
+	subtopic string
+}
+
+func (req latestReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+
+	if req.chanID == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+)
+
+type retainedMessage struct {
+	Subtopic    string          `json:"subtopic,omitempty"`
+	Publisher   string          `json:"publisher"`
+	Protocol    string          `json:"protocol"`
+	ContentType string          `json:"content_type,omitempty"`
+	Created     int64           `json:"created"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	Data        []byte          `json:"data,omitempty"`
+}
+
+// newRetainedMessage embeds the JSON payloads into the response, converting
+// SenML CBOR to SenML JSON. The other payloads are sent base64 encoded as
+// the data field.
+func newRetainedMessage(msg messaging.Message) retainedMessage {
+	rm := retainedMessage{
+		Subtopic:    msg.Subtopic,
+		Publisher:   msg.Publisher,
+		Protocol:    msg.Protocol,
+		ContentType: msg.ContentType,
+		Created:     msg.Created,
+	}
+
+	payload := msg.Payload
+	if msg.ContentType == senml.CBOR {
+		if p, err := senml.Convert(payload, senml.CBOR, senml.JSON); err == nil {
+			payload = p
+			rm.ContentType = senml.JSON
+		}
+	}
+	if json.Valid(payload) {
+		rm.Payload = payload
+		return rm
+	}
+	rm.Data = msg.Payload
+	return rm
+}
+
+type latestRes struct {
+	Messages []retainedMessage `json:"messages"`
+}
@@ -4,12 +4,16 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"mime"
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,8 +23,14 @@ import (
 	"github.com/mainflux/mainflux"
 	adapter "github.com/mainflux/mainflux/http"
 	"github.com/mainflux/mainflux/internal/httputil"
+	log "github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/presence"
+	"github.com/mainflux/mainflux/pkg/quota"
+	"github.com/mainflux/mainflux/pkg/retained"
+	"github.com/mainflux/mainflux/pkg/schema"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc/codes"
@@ -28,20 +38,31 @@ import (
 )
 
 const (
-	protocol    = "http"
-	contentType = "application/senml+json"
+	protocol        = "http"
+	contentType     = "application/senml+json"
+	eventStreamType = "text/event-stream"
+	lastEventHeader = "Last-Event-ID"
+	realIPHeader    = "X-Real-IP"
+	authQuery       = "authorization"
+	subtopicQuery   = "subtopic"
+	// retryInterval is the reconnection time advertised to the SSE clients.
+	retryInterval = 3 * time.Second
+	// heartbeat is the period of comments sent to keep idle streams open.
+	heartbeat = 30 * time.Second
 )
 
 var (
-	errMalformedSubtopic = errors.New("malformed subtopic")
+	errMalformedSubtopic    = errors.New("malformed subtopic")
+	errStreamingUnsupported = errors.New("streaming unsupported")
 )
 
 var channelPartRegExp = regexp.MustCompile(`^/channels/([\w\-]+)/messages(/[^?]*)?(\?.*)?$`)
 
 // MakeHandler returns a HTTP handler for API endpoints.
-func MakeHandler(svc adapter.Service, tracer opentracing.Tracer) http.Handler {
+func MakeHandler(svc adapter.Service, tracer opentracing.Tracer, idp mainflux.IDProvider, logger log.Logger) http.Handler {
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(populateClientIP),
 	}
 
 	r := bone.New()
@@ -59,12 +80,34 @@ func MakeHandler(svc adapter.Service, tracer opentracing.Tracer) http.Handler {
 		opts...,
 	))
 
+	// The latest messages route must precede the streaming route
+	// matching all the subtopics.
+	r.Get("/channels/:id/messages/latest", kithttp.NewServer(
+		kitot.TraceServer(tracer, "latest")(latestEndpoint(svc)),
+		decodeLatest,
+		encodeLatestResponse,
+		opts...,
+	))
+
+	r.GetFunc("/channels/:id/messages", subscribe(svc, idp, logger))
+	r.GetFunc("/channels/:id/messages/*", subscribe(svc, idp, logger))
+
 	r.GetFunc("/health", mainflux.Health("http"))
 	r.Handle("/metrics", promhttp.Handler())
 
 	return r
 }
 
+// populateClientIP stores the IP of the client in the request context, so
+// that the presence of the publishing thing can be tracked. The address set
+// by the reverse proxy takes precedence over the address of the connection.
+func populateClientIP(ctx context.Context, r *http.Request) context.Context {
+	if ip := r.Header.Get(realIPHeader); ip != "" {
+		return presence.WithClientIP(ctx, ip)
+	}
+	return presence.WithClientIP(ctx, r.RemoteAddr)
+}
+
 func parseSubtopic(subtopic string) (string, error) {
 	if subtopic == "" {
 		return subtopic, nil
@@ -95,7 +138,11 @@ func parseSubtopic(subtopic string) (string, error) {
 }
 
 func decodeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
-	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+	ct, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	if _, ok := senml.Format(ct); !ok {
 		return nil, errors.ErrUnsupportedContentType
 	}
 
@@ -129,11 +176,12 @@ func decodeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
 
 	req := publishReq{
 		msg: messaging.Message{
-			Protocol: protocol,
-			Channel:  bone.GetValue(r, "id"),
-			Subtopic: subtopic,
-			Payload:  payload,
-			Created:  time.Now().UnixNano(),
+			Protocol:    protocol,
+			Channel:     bone.GetValue(r, "id"),
+			Subtopic:    subtopic,
+			Payload:     payload,
+			Created:     time.Now().UnixNano(),
+			ContentType: ct,
 		},
 		token: token,
 	}
@@ -141,12 +189,206 @@ func decodeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
+func decodeLatest(_ context.Context, r *http.Request) (interface{}, error) {
+	subtopic, err := httputil.ReadStringQuery(r, subtopicQuery, "")
+	if err != nil {
+		return nil, err
+	}
+	if subtopic, err = parseSubtopic(subtopic); err != nil {
+		return nil, err
+	}
+	if strings.ContainsAny(subtopic, "*>") {
+		return nil, errMalformedSubtopic
+	}
+
+	var token string
+	_, pass, ok := r.BasicAuth()
+	switch {
+	case ok:
+		token = pass
+	case !ok:
+		token, err = httputil.ExtractAuthToken(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req := latestReq{
+		token:    token,
+		chanID:   bone.GetValue(r, "id"),
+		subtopic: subtopic,
+	}
+
+	return req, nil
+}
+
+// subscribe streams messages published to the channel as server-sent events.
+func subscribe(svc adapter.Service, idp mainflux.IDProvider, logger log.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		req, err := decodeSubscribe(r)
+		if err != nil {
+			encodeError(ctx, err, w)
+			return
+		}
+		if err := req.validate(); err != nil {
+			encodeError(ctx, err, w)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			encodeError(ctx, errStreamingUnsupported, w)
+			return
+		}
+
+		id, err := idp.ID()
+		if err != nil {
+			encodeError(ctx, err, w)
+			return
+		}
+		c := newClient(id)
+		if err := svc.Subscribe(ctx, req.token, req.chanID, req.subtopic, req.lastID, c); err != nil {
+			encodeError(ctx, err, w)
+			return
+		}
+		defer func() {
+			if err := svc.Unsubscribe(context.Background(), req.chanID, req.subtopic, id); err != nil {
+				logger.Warn(fmt.Sprintf("Failed to unsubscribe client %s: %s", id, err))
+			}
+		}()
+
+		w.Header().Set("Content-Type", eventStreamType)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		// Disable response buffering of the reverse proxy.
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "retry: %d\n\n", retryInterval.Milliseconds())
+		flusher.Flush()
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.overflow:
+				// Closing the stream makes the client reconnect and
+				// receive the missed messages using the Last-Event-ID.
+				logger.Warn(fmt.Sprintf("Closing stream of slow client %s", id))
+				return
+			case e := <-c.events:
+				if _, err := w.Write(encodeEvent(e)); err != nil {
+					return
+				}
+				flusher.Flush()
+			case <-ticker.C:
+				if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+func decodeSubscribe(r *http.Request) (subscribeReq, error) {
+	channelParts := channelPartRegExp.FindStringSubmatch(r.URL.Path)
+	if len(channelParts) < 2 {
+		return subscribeReq{}, errors.ErrMalformedEntity
+	}
+
+	subtopic, err := parseSubtopic(channelParts[2])
+	if err != nil {
+		return subscribeReq{}, err
+	}
+
+	// Browser EventSource can't set headers, so the
+	// key can be sent as a query parameter as well.
+	token, err := httputil.ReadStringQuery(r, authQuery, "")
+	if err != nil {
+		return subscribeReq{}, err
+	}
+	if token == "" {
+		_, pass, ok := r.BasicAuth()
+		switch {
+		case ok:
+			token = pass
+		case !ok:
+			token, err = httputil.ExtractAuthToken(r)
+			if err != nil {
+				return subscribeReq{}, err
+			}
+		}
+	}
+
+	var lastID int64
+	if h := r.Header.Get(lastEventHeader); h != "" {
+		lastID, err = strconv.ParseInt(h, 10, 64)
+		if err != nil {
+			return subscribeReq{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+
+	req := subscribeReq{
+		token:    token,
+		chanID:   bone.GetValue(r, "id"),
+		subtopic: subtopic,
+		lastID:   lastID,
+	}
+
+	return req, nil
+}
+
+// encodeEvent formats the message as an event, using the sequence number
+// within the subscription as the event ID. Every payload line is sent as a
+// separate data field. Since the events are text, SenML CBOR payloads are
+// sent as SenML JSON.
+func encodeEvent(e adapter.Event) []byte {
+	msg := e.Message
+	payload := msg.Payload
+	if msg.ContentType == senml.CBOR {
+		if p, err := senml.Convert(payload, senml.CBOR, senml.JSON); err == nil {
+			payload = p
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "id: %d\n", e.ID)
+	for _, line := range bytes.Split(payload, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(bytes.TrimSuffix(line, []byte("\r")))
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\n")
+
+	return buf.Bytes()
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.WriteHeader(http.StatusAccepted)
 	return nil
 }
 
+func encodeLatestResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(response)
+}
+
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	if errors.Contains(err, schema.ErrInvalidPayload) {
+		// Report why the payload doesn't conform to the schema.
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(httputil.ErrorRes{Err: err.Error()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
 	switch {
 	case errors.Contains(err, errors.ErrAuthentication):
 		w.WriteHeader(http.StatusUnauthorized)
@@ -154,8 +396,15 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		w.WriteHeader(http.StatusForbidden)
 	case errors.Contains(err, errors.ErrUnsupportedContentType):
 		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, retained.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Contains(err, quota.ErrRateLimited):
+		w.WriteHeader(http.StatusTooManyRequests)
+	case errors.Contains(err, quota.ErrPayloadTooLarge):
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
 	case errors.Contains(err, errMalformedSubtopic),
-		errors.Contains(err, errors.ErrMalformedEntity):
+		errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrInvalidQueryParams):
 		w.WriteHeader(http.StatusBadRequest)
 
 	default:
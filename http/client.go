@@ -0,0 +1,23 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import "github.com/mainflux/mainflux/pkg/messaging"
+
+// Event represents the message published to the subscribed channel, along
+// with its sequence number within the subscription, which the reconnecting
+// client resumes the subscription from.
+type Event struct {
+	ID      int64
+	Message messaging.Message
+}
+
+// Client represents a subscriber receiving the messages published to the channel.
+type Client interface {
+	// ID returns the unique identifier of the client.
+	ID() string
+
+	// Handle forwards the event to the client. Handle must not block.
+	Handle(e Event) error
+}
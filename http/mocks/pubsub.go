@@ -0,0 +1,69 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+const chansPrefix = "channels"
+
+var _ messaging.PubSub = (*mockPubSub)(nil)
+
+type mockPubSub struct {
+	mu            sync.Mutex
+	subscriptions map[string]messaging.MessageHandler
+}
+
+// NewPubSub returns mock message publisher/subscriber which
+// delivers published messages directly to the subscribed handlers.
+func NewPubSub() messaging.PubSub {
+	return &mockPubSub{
+		subscriptions: make(map[string]messaging.MessageHandler),
+	}
+}
+
+func (ps *mockPubSub) Publish(topic string, msg messaging.Message) error {
+	subject := fmt.Sprintf("%s.%s", chansPrefix, topic)
+	if msg.Subtopic != "" {
+		subject = fmt.Sprintf("%s.%s", subject, msg.Subtopic)
+	}
+
+	ps.mu.Lock()
+	h, ok := ps.subscriptions[subject]
+	ps.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	return h(msg)
+}
+
+func (ps *mockPubSub) Subscribe(topic string, handler messaging.MessageHandler) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if _, ok := ps.subscriptions[topic]; ok {
+		return errors.New("already subscribed to topic")
+	}
+	ps.subscriptions[topic] = handler
+
+	return nil
+}
+
+func (ps *mockPubSub) Unsubscribe(topic string) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if _, ok := ps.subscriptions[topic]; !ok {
+		return errors.New("not subscribed")
+	}
+	delete(ps.subscriptions, topic)
+
+	return nil
+}
@@ -58,6 +58,22 @@ func (tc thingsClient) IsChannelOwner(context.Context, *mainflux.ChannelOwnerReq
 	panic("not implemented")
 }
 
+func (tc thingsClient) IsChannelsOwner(context.Context, *mainflux.ChannelsOwnerReq, ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
 func (tc thingsClient) Identify(ctx context.Context, req *mainflux.Token, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
 	panic("not implemented")
 }
+
+func (tc thingsClient) ViewThing(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.Thing, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) ViewChannel(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Channel, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) ViewQuota(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.Quota, error) {
+	panic("not implemented")
+}
@@ -0,0 +1,81 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package httputil
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// ErrForbiddenAddress indicates that the requested URL resolves to the
+// loopback, link-local, private or otherwise non-public address.
+var ErrForbiddenAddress = errors.New("address is not allowed")
+
+// NewPublicClient returns the HTTP client that refuses to connect to the
+// non-public addresses, unless they belong to one of the allowed networks.
+// The address is checked once it's resolved, right before the connection
+// is made, so neither DNS records nor redirects can point the client to
+// the internal services. It's used to call the user provided URLs.
+func NewPublicClient(timeout time.Duration, allowed []*net.IPNet) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !allowedIP(ip, allowed) {
+				return errors.Wrap(ErrForbiddenAddress, errors.New(address))
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+	}
+}
+
+func allowedIP(ip net.IP, allowed []*net.IPNet) bool {
+	for _, n := range allowed {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified())
+}
+
+// ParseNetworks parses the comma separated list of networks in the CIDR
+// notation.
+func ParseNetworks(s string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	if s == "" {
+		return nets, nil
+	}
+	for _, cidr := range strings.Split(s, ",") {
+		_, n, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
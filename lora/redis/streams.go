@@ -23,13 +23,15 @@ const (
 	thingCreate     = thingPrefix + "create"
 	thingUpdate     = thingPrefix + "update"
 	thingRemove     = thingPrefix + "remove"
+	thingRestore    = thingPrefix + "restore"
 	thingConnect    = thingPrefix + "connect"
 	thingDisconnect = thingPrefix + "disconnect"
 
-	channelPrefix = "channel."
-	channelCreate = channelPrefix + "create"
-	channelUpdate = channelPrefix + "update"
-	channelRemove = channelPrefix + "remove"
+	channelPrefix  = "channel."
+	channelCreate  = channelPrefix + "create"
+	channelUpdate  = channelPrefix + "update"
+	channelRemove  = channelPrefix + "remove"
+	channelRestore = channelPrefix + "restore"
 
 	exists = "BUSYGROUP Consumer Group name already exists"
 )
@@ -89,7 +91,7 @@ func (es eventStore) Subscribe(ctx context.Context, subject string) error {
 
 			var err error
 			switch event["operation"] {
-			case thingCreate:
+			case thingCreate, thingRestore:
 				cte, derr := decodeCreateThing(event)
 				if derr != nil {
 					err = derr
@@ -104,7 +106,7 @@ func (es eventStore) Subscribe(ctx context.Context, subject string) error {
 				}
 				err = es.svc.CreateThing(ctx, ute.id, ute.loraDevEUI)
 
-			case channelCreate:
+			case channelCreate, channelRestore:
 				cce, derr := decodeCreateChannel(event)
 				if derr != nil {
 					err = derr
@@ -5,6 +5,8 @@ package mqtt
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"net/url"
 	"regexp"
 	"strings"
@@ -15,60 +17,94 @@ import (
 	"github.com/mainflux/mainflux/pkg/auth"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/quota"
+	"github.com/mainflux/mainflux/pkg/retained"
+	"github.com/mainflux/mainflux/pkg/schema"
+	"github.com/mainflux/mainflux/pkg/transformers/sparkplug"
 	"github.com/mainflux/mproxy/pkg/session"
 )
 
-var _ session.Handler = (*handler)(nil)
+var (
+	_ session.Handler   = (*handler)(nil)
+	_ PropertiesHandler = (*handler)(nil)
+	_ RetainedHandler   = (*handler)(nil)
+)
 
-const protocol = "mqtt"
+const (
+	protocol = "mqtt"
+	ctPrefix = "/ct/"
+)
 
 var (
-	channelRegExp        = regexp.MustCompile(`^\/?channels\/([\w\-]+)\/messages(\/[^?]*)?(\?.*)?$`)
-	errMalformedTopic    = errors.New("malformed topic")
-	errMalformedData     = errors.New("malformed request data")
-	errMalformedSubtopic = errors.New("malformed subtopic")
-	errNilClient         = errors.New("using nil client")
-	errInvalidConnect    = errors.New("CONNECT request with invalid username or client ID")
-	errNilTopicPub       = errors.New("PUBLISH to nil topic")
-	errNilTopicSub       = errors.New("SUB to nil topic")
+	channelRegExp         = regexp.MustCompile(`^\/?channels\/([\w\-]+)\/messages(\/[^?]*)?(\?.*)?$`)
+	sparkplugRegExp       = regexp.MustCompile(`^spBv1\.0\/([\w\-]+)\/([ND](?:BIRTH|DEATH|DATA|CMD))\/([^\/+#.*>]+)(?:\/([^\/+#.*>]+))?$`)
+	sparkplugFilterRegExp = regexp.MustCompile(`^spBv1\.0\/([\w\-]+)(\/.*)?$`)
+	errMalformedTopic     = errors.New("malformed topic")
+	errMalformedSubtopic  = errors.New("malformed subtopic")
+	errNilClient          = errors.New("using nil client")
+	errInvalidConnect     = errors.New("CONNECT request with invalid username or client ID")
+	errNilTopicPub        = errors.New("PUBLISH to nil topic")
+	errNilTopicSub        = errors.New("SUB to nil topic")
 )
 
 // Event implements events.Event interface
 type handler struct {
 	publishers []messaging.Publisher
 	auth       auth.Client
+	schemas    schema.Validator
+	store      retained.Store
+	limiter    quota.Limiter
 	logger     logger.Logger
 	es         redis.EventStore
 }
 
-// NewHandler creates new Handler entity
+// NewHandler creates new Handler entity. The retained messages sent to the
+// subscribing clients are read from the given last-value store, and the
+// quotas of the publishing things are enforced using the given limiter.
 func NewHandler(publishers []messaging.Publisher, es redis.EventStore,
-	logger logger.Logger, auth auth.Client) session.Handler {
+	logger logger.Logger, auth auth.Client, schemas schema.Validator, store retained.Store, limiter quota.Limiter) session.Handler {
 	return &handler{
 		es:         es,
 		logger:     logger,
 		publishers: publishers,
 		auth:       auth,
+		schemas:    schemas,
+		store:      store,
+		limiter:    limiter,
 	}
 }
 
 // AuthConnect is called on device connection,
 // prior forwarding to the MQTT broker
 func (h *handler) AuthConnect(c *session.Client) error {
+	return h.authConnect(c, "")
+}
+
+// AuthConnectAddress is called on device connection instead of AuthConnect
+// if the remote address of the device is known.
+func (h *handler) AuthConnectAddress(c *session.Client, addr net.Addr) error {
+	ip := addr.String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return h.authConnect(c, ip)
+}
+
+func (h *handler) authConnect(c *session.Client, clientIP string) error {
 	if c == nil {
 		return errInvalidConnect
 	}
 
 	thid, err := h.auth.Identify(context.Background(), string(c.Password))
 	if err != nil {
-		return err
+		return errors.Wrap(errors.ErrAuthentication, err)
 	}
 
 	if thid != c.Username {
 		return errors.ErrAuthentication
 	}
 
-	if err := h.es.Connect(c.Username); err != nil {
+	if err := h.es.Connect(c.Username, clientIP); err != nil {
 		h.logger.Warn("Failed to publish connect event: " + err.Error())
 	}
 
@@ -78,6 +114,12 @@ func (h *handler) AuthConnect(c *session.Client) error {
 // AuthPublish is called on device publish,
 // prior forwarding to the MQTT broker
 func (h *handler) AuthPublish(c *session.Client, topic *string, payload *[]byte) error {
+	return h.AuthPublishProperties(c, topic, payload, nil)
+}
+
+// AuthPublishProperties is called on device publish with the MQTT 5
+// properties, prior forwarding to the MQTT broker
+func (h *handler) AuthPublishProperties(c *session.Client, topic *string, payload *[]byte, props *Properties) error {
 	if c == nil {
 		return errNilClient
 	}
@@ -85,7 +127,32 @@ func (h *handler) AuthPublish(c *session.Client, topic *string, payload *[]byte)
 		return errNilTopicPub
 	}
 
-	return h.authAccess(c.Username, *topic)
+	chanID, _, ct, err := parseTopic(*topic)
+	if err != nil {
+		h.logger.Info("Malformed topic: " + *topic)
+		return err
+	}
+	if err := h.auth.Authorize(context.Background(), chanID, c.Username); err != nil {
+		return errors.Wrap(errors.ErrAuthorization, err)
+	}
+	ct = contentType(ct, props)
+
+	// Throttled and non-conforming payloads are rejected with the reason
+	// code for MQTT 5 clients, and disconnect MQTT 3.1.1 clients.
+	var data []byte
+	if payload != nil {
+		data = *payload
+	}
+	if err := h.limiter.Allow(context.Background(), c.Username, len(data)); err != nil {
+		h.logger.Info("Rejected publish - client ID " + c.ID + " to the topic " + *topic + ": " + err.Error())
+		return err
+	}
+	if err := h.schemas.Validate(context.Background(), chanID, ct, data); err != nil {
+		h.logger.Info("Rejected publish - client ID " + c.ID + " to the topic " + *topic + ": " + err.Error())
+		return err
+	}
+
+	return nil
 }
 
 // AuthSubscribe is called on device publish,
@@ -119,36 +186,37 @@ func (h *handler) Connect(c *session.Client) {
 
 // Publish - after client successfully published
 func (h *handler) Publish(c *session.Client, topic *string, payload *[]byte) {
+	h.PublishProperties(c, topic, payload, nil)
+}
+
+// PublishProperties - after client successfully published with the MQTT 5 properties
+func (h *handler) PublishProperties(c *session.Client, topic *string, payload *[]byte, props *Properties) {
 	if c == nil {
 		h.logger.Error("Nil client publish")
 		return
 	}
 	h.logger.Info("Publish - client ID " + c.ID + " to the topic: " + *topic)
-	// Topics are in the format:
-	// channels/<channel_id>/messages/<subtopic>/.../ct/<content_type>
 
-	channelParts := channelRegExp.FindStringSubmatch(*topic)
-	if len(channelParts) < 1 {
-		h.logger.Info("Error in mqtt publish %s" + errMalformedData.Error())
-		return
-	}
-
-	chanID := channelParts[1]
-	subtopic := channelParts[2]
-
-	subtopic, err := parseSubtopic(subtopic)
+	chanID, subtopic, ct, err := parseTopic(*topic)
 	if err != nil {
-		h.logger.Info("Error parsing subtopic: " + err.Error())
+		h.logger.Info("Error in mqtt publish: " + err.Error())
 		return
 	}
 
 	msg := messaging.Message{
-		Protocol:  protocol,
-		Channel:   chanID,
-		Subtopic:  subtopic,
-		Publisher: c.Username,
-		Payload:   *payload,
-		Created:   time.Now().UnixNano(),
+		Protocol:    protocol,
+		Channel:     chanID,
+		Subtopic:    subtopic,
+		Publisher:   c.Username,
+		Payload:     *payload,
+		Created:     time.Now().UnixNano(),
+		ContentType: contentType(ct, props),
+	}
+	if props != nil {
+		msg.UserProperties = props.UserProperties
+		msg.ResponseTopic = props.ResponseTopic
+		msg.CorrelationData = props.CorrelationData
+		msg.Expiry = props.MessageExpiry
 	}
 
 	for _, pub := range h.publishers {
@@ -167,6 +235,51 @@ func (h *handler) Subscribe(c *session.Client, topics *[]string) {
 	h.logger.Info("Subscribe - client ID: " + c.ID + ", to topics: " + strings.Join(*topics, ","))
 }
 
+// Retained returns the retained messages of the channels matching the
+// granted topic filters. Every message is returned once, even if it
+// matches multiple filters.
+func (h *handler) Retained(c *session.Client, filters []string) []RetainedMessage {
+	now := time.Now()
+	chans := make(map[string][]messaging.Message)
+	sent := make(map[string]bool)
+
+	var msgs []RetainedMessage
+	for _, f := range filters {
+		chanID := filterChannel(f)
+		if chanID == "" {
+			continue
+		}
+		if _, ok := chans[chanID]; !ok {
+			ms, err := h.store.RetrieveAll(context.Background(), chanID)
+			if err != nil {
+				h.logger.Warn("Failed to retrieve retained messages of channel " + chanID + ": " + err.Error())
+			}
+			chans[chanID] = ms
+		}
+
+		for _, msg := range chans[chanID] {
+			topic := messageTopic(msg)
+			if sent[topic] || !matchTopic(f, topic) {
+				continue
+			}
+			sent[topic] = true
+			msgs = append(msgs, RetainedMessage{
+				Topic:   topic,
+				Payload: msg.Payload,
+				Props: Properties{
+					ContentType:     msg.ContentType,
+					ResponseTopic:   msg.ResponseTopic,
+					CorrelationData: msg.CorrelationData,
+					MessageExpiry:   remainingExpiry(msg, now),
+					UserProperties:  msg.UserProperties,
+				},
+			})
+		}
+	}
+
+	return msgs
+}
+
 // Unsubscribe - after client unsubscribed
 func (h *handler) Unsubscribe(c *session.Client, topics *[]string) {
 	if c == nil {
@@ -191,18 +304,136 @@ func (h *handler) Disconnect(c *session.Client) {
 func (h *handler) authAccess(username string, topic string) error {
 	// Topics are in the format:
 	// channels/<channel_id>/messages/<subtopic>/.../ct/<content_type>
-	if !channelRegExp.Match([]byte(topic)) {
+	// or spBv1.0/<channel_id>/... for Sparkplug B topic filters.
+	channelParts := channelRegExp.FindStringSubmatch(topic)
+	if channelParts == nil {
+		channelParts = sparkplugFilterRegExp.FindStringSubmatch(topic)
+	}
+	if len(channelParts) < 2 {
 		h.logger.Info("Malformed topic: " + topic)
 		return errMalformedTopic
 	}
 
+	chanID := channelParts[1]
+	if err := h.auth.Authorize(context.Background(), chanID, username); err != nil {
+		return errors.Wrap(errors.ErrAuthorization, err)
+	}
+	return nil
+}
+
+// parseTopic returns the channel ID, subtopic and content type of the
+// published message. Sparkplug B topics are in the format:
+// spBv1.0/<channel_id>/<message_type>/<edge_node_id>[/<device_id>]
+// and are mapped onto the <message_type>.<edge_node_id>[.<device_id>]
+// subtopic of the channel.
+func parseTopic(topic string) (string, string, string, error) {
+	if parts := sparkplugRegExp.FindStringSubmatch(topic); parts != nil {
+		subtopic := strings.Join(parts[2:4], ".")
+		if parts[4] != "" {
+			subtopic = fmt.Sprintf("%s.%s", subtopic, parts[4])
+		}
+		return parts[1], subtopic, sparkplug.ContentType, nil
+	}
+
 	channelParts := channelRegExp.FindStringSubmatch(topic)
-	if len(channelParts) < 1 {
-		return errMalformedData
+	if channelParts == nil {
+		return "", "", "", errMalformedTopic
 	}
 
-	chanID := channelParts[1]
-	return h.auth.Authorize(context.Background(), chanID, username)
+	subtopic, ct, err := parseContentType(channelParts[2])
+	if err != nil {
+		return "", "", "", err
+	}
+	subtopic, err = parseSubtopic(subtopic)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	return channelParts[1], subtopic, ct, nil
+}
+
+// filterChannel returns the ID of the channel the topic filter refers to.
+func filterChannel(filter string) string {
+	parts := channelRegExp.FindStringSubmatch(filter)
+	if parts == nil {
+		parts = sparkplugFilterRegExp.FindStringSubmatch(filter)
+	}
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+// messageTopic returns the MQTT topic of the message, mapping the Sparkplug B
+// messages back onto the Sparkplug B namespace.
+func messageTopic(msg messaging.Message) string {
+	subtopic := strings.ReplaceAll(msg.Subtopic, ".", "/")
+	if msg.ContentType == sparkplug.ContentType {
+		return fmt.Sprintf("%s/%s/%s", sparkplug.Namespace, msg.Channel, subtopic)
+	}
+
+	topic := fmt.Sprintf("channels/%s/messages", msg.Channel)
+	if subtopic != "" {
+		topic = fmt.Sprintf("%s/%s", topic, subtopic)
+	}
+	return topic
+}
+
+// matchTopic returns true if the topic name matches the topic filter,
+// containing the "+" and "#" wildcards.
+func matchTopic(filter, topic string) bool {
+	fs, ts := strings.Split(filter, "/"), strings.Split(topic, "/")
+	for i, f := range fs {
+		if f == "#" {
+			return true
+		}
+		if i >= len(ts) || (f != "+" && f != ts[i]) {
+			return false
+		}
+	}
+	return len(fs) == len(ts)
+}
+
+// remainingExpiry returns the remaining message expiry interval in seconds,
+// rounded up.
+func remainingExpiry(msg messaging.Message, now time.Time) uint32 {
+	if msg.Expiry == 0 {
+		return 0
+	}
+	left := time.Duration(msg.Created + int64(msg.Expiry)*int64(time.Second) - now.UnixNano())
+	if left <= 0 {
+		return 1
+	}
+	return uint32((left + time.Second - 1) / time.Second)
+}
+
+// contentType returns the content type set by the MQTT 5 property, unless
+// the topic sets the Sparkplug B one.
+func contentType(ct string, props *Properties) string {
+	if props == nil || props.ContentType == "" || ct == sparkplug.ContentType {
+		return ct
+	}
+	return props.ContentType
+}
+
+// parseContentType splits the content type suffix off the subtopic. Since the
+// MQTT topic names can't contain "+", the content type is URL-encoded, e.g.
+// ct/senml%2Bcbor, and the "application/" type is assumed if omitted.
+func parseContentType(subtopic string) (string, string, error) {
+	i := strings.LastIndex(subtopic, ctPrefix)
+	if i < 0 {
+		return subtopic, "", nil
+	}
+
+	ct, err := url.PathUnescape(subtopic[i+len(ctPrefix):])
+	if err != nil || ct == "" {
+		return "", "", errMalformedSubtopic
+	}
+	if !strings.Contains(ct, "/") {
+		ct = "application/" + ct
+	}
+
+	return subtopic[:i], ct, nil
 }
 
 func parseSubtopic(subtopic string) (string, error) {
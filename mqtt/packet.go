@@ -0,0 +1,524 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sort"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// MQTT control packet types.
+const (
+	connectType     byte = 1
+	connackType     byte = 2
+	publishType     byte = 3
+	pubackType      byte = 4
+	pubrecType      byte = 5
+	pubrelType      byte = 6
+	pubcompType     byte = 7
+	subscribeType   byte = 8
+	subackType      byte = 9
+	unsubscribeType byte = 10
+	pingrespType    byte = 13
+	disconnectType  byte = 14
+)
+
+// retainFlag is the PUBLISH fixed header flag of the retained messages.
+const retainFlag byte = 0x01
+
+// MQTT protocol levels.
+const (
+	v311 byte = 4
+	v5   byte = 5
+)
+
+// MQTT 5 property identifiers.
+const (
+	propPayloadFormat      byte = 0x01
+	propMessageExpiry      byte = 0x02
+	propContentType        byte = 0x03
+	propResponseTopic      byte = 0x08
+	propCorrelationData    byte = 0x09
+	propSubscriptionID     byte = 0x0B
+	propSessionExpiry      byte = 0x11
+	propAssignedClientID   byte = 0x12
+	propServerKeepAlive    byte = 0x13
+	propAuthMethod         byte = 0x15
+	propAuthData           byte = 0x16
+	propRequestProblemInfo byte = 0x17
+	propWillDelay          byte = 0x18
+	propRequestRespInfo    byte = 0x19
+	propResponseInfo       byte = 0x1A
+	propServerReference    byte = 0x1C
+	propReasonString       byte = 0x1F
+	propReceiveMaximum     byte = 0x21
+	propTopicAliasMaximum  byte = 0x22
+	propTopicAlias         byte = 0x23
+	propMaximumQoS         byte = 0x24
+	propRetainAvailable    byte = 0x25
+	propUserProperty       byte = 0x26
+	propMaximumPacketSize  byte = 0x27
+	propWildcardSubAvail   byte = 0x28
+	propSubIDAvailable     byte = 0x29
+	propSharedSubAvailable byte = 0x2A
+)
+
+var (
+	errMalformedPacket = errors.New("malformed MQTT packet")
+
+	errPacketTooLarge = errors.New("MQTT packet exceeds the maximum packet size")
+)
+
+// Properties represents the MQTT 5 properties of the published message.
+type Properties struct {
+	ContentType     string
+	ResponseTopic   string
+	CorrelationData []byte
+	// MessageExpiry is the message expiry interval in seconds, 0 if the
+	// message doesn't expire.
+	MessageExpiry uint32
+	// UserProperties contains the user properties. If the property is
+	// repeated, the last value is kept.
+	UserProperties map[string]string
+
+	topicAlias uint16
+}
+
+// packet represents the MQTT control packet, with the body containing the
+// variable header and the payload.
+type packet struct {
+	header byte
+	body   []byte
+}
+
+func (p packet) kind() byte {
+	return p.header >> 4
+}
+
+// readPacket reads the packet, rejecting the packets larger than max bytes
+// before their body is read. Zero max means that the packet size is not
+// limited.
+func readPacket(r io.Reader, max int) (packet, error) {
+	var hdr [1]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return packet{}, err
+	}
+
+	n, mul := 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return packet{}, errMalformedPacket
+		}
+		var b [1]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return packet{}, err
+		}
+		n += int(b[0]&0x7F) * mul
+		if b[0]&0x80 == 0 {
+			break
+		}
+		mul *= 128
+	}
+
+	// Fixed header consists of the packet type byte and the remaining
+	// length, encoded in 1 to 4 bytes.
+	if size := 1 + varintLen(n) + n; max > 0 && size > max {
+		return packet{}, errPacketTooLarge
+	}
+
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return packet{}, err
+	}
+
+	return packet{header: hdr[0], body: body}, nil
+}
+
+// write writes the packet at once, so that the concurrent writers don't
+// interleave.
+func (p packet) write(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteByte(p.header)
+	writeVarint(&buf, len(p.body))
+	buf.Write(p.body)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func writeVarint(buf *bytes.Buffer, n int) {
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		buf.WriteByte(b)
+		if n == 0 {
+			return
+		}
+	}
+}
+
+func varintLen(n int) int {
+	l := 1
+	for n >= 128 {
+		n /= 128
+		l++
+	}
+	return l
+}
+
+func writeString(buf *bytes.Buffer, s []byte) {
+	var l [2]byte
+	binary.BigEndian.PutUint16(l[:], uint16(len(s)))
+	buf.Write(l[:])
+	buf.Write(s)
+}
+
+// reader decodes the packet body. The first decoding error is kept, and the
+// subsequent reads return zero values.
+type reader struct {
+	buf []byte
+	err error
+}
+
+func (r *reader) next(n int) []byte {
+	if r.err != nil || n < 0 || n > len(r.buf) {
+		r.err = errMalformedPacket
+		return nil
+	}
+	ret := r.buf[:n]
+	r.buf = r.buf[n:]
+	return ret
+}
+
+func (r *reader) byte() byte {
+	b := r.next(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (r *reader) uint16() uint16 {
+	b := r.next(2)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint16(b)
+}
+
+func (r *reader) uint32() uint32 {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (r *reader) varint() int {
+	n, mul := 0, 1
+	for i := 0; i < 4; i++ {
+		b := r.byte()
+		n += int(b&0x7F) * mul
+		if b&0x80 == 0 {
+			return n
+		}
+		mul *= 128
+	}
+	r.err = errMalformedPacket
+	return 0
+}
+
+func (r *reader) bytes() []byte {
+	return r.next(int(r.uint16()))
+}
+
+func (r *reader) string() string {
+	return string(r.bytes())
+}
+
+func (r *reader) empty() bool {
+	return len(r.buf) == 0
+}
+
+// properties returns the raw property block.
+func (r *reader) properties() []byte {
+	return r.next(r.varint())
+}
+
+// parseProperties decodes the publish properties of the raw property block.
+func parseProperties(raw []byte) (Properties, error) {
+	var props Properties
+	r := reader{buf: raw}
+	for !r.empty() && r.err == nil {
+		switch id := r.byte(); id {
+		case propPayloadFormat, propRequestProblemInfo, propRequestRespInfo,
+			propMaximumQoS, propRetainAvailable, propWildcardSubAvail,
+			propSubIDAvailable, propSharedSubAvailable:
+			r.byte()
+		case propServerKeepAlive, propReceiveMaximum, propTopicAliasMaximum:
+			r.uint16()
+		case propTopicAlias:
+			props.topicAlias = r.uint16()
+		case propMessageExpiry:
+			props.MessageExpiry = r.uint32()
+		case propSessionExpiry, propWillDelay, propMaximumPacketSize:
+			r.uint32()
+		case propSubscriptionID:
+			r.varint()
+		case propContentType:
+			props.ContentType = r.string()
+		case propResponseTopic:
+			props.ResponseTopic = r.string()
+		case propCorrelationData:
+			props.CorrelationData = r.bytes()
+		case propAssignedClientID, propAuthMethod, propResponseInfo,
+			propServerReference, propReasonString:
+			r.string()
+		case propAuthData:
+			r.bytes()
+		case propUserProperty:
+			k, v := r.string(), r.string()
+			if props.UserProperties == nil {
+				props.UserProperties = make(map[string]string)
+			}
+			props.UserProperties[k] = v
+		default:
+			return Properties{}, errMalformedPacket
+		}
+	}
+	if r.err != nil {
+		return Properties{}, r.err
+	}
+
+	return props, nil
+}
+
+// encodeProperties returns the raw property block of the publish
+// properties. User properties are sorted by the key.
+func encodeProperties(props Properties) []byte {
+	var buf bytes.Buffer
+	if props.MessageExpiry != 0 {
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], props.MessageExpiry)
+		buf.WriteByte(propMessageExpiry)
+		buf.Write(b[:])
+	}
+	if props.ContentType != "" {
+		buf.WriteByte(propContentType)
+		writeString(&buf, []byte(props.ContentType))
+	}
+	if props.ResponseTopic != "" {
+		buf.WriteByte(propResponseTopic)
+		writeString(&buf, []byte(props.ResponseTopic))
+	}
+	if props.CorrelationData != nil {
+		buf.WriteByte(propCorrelationData)
+		writeString(&buf, props.CorrelationData)
+	}
+
+	keys := make([]string, 0, len(props.UserProperties))
+	for k := range props.UserProperties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		buf.WriteByte(propUserProperty)
+		writeString(&buf, []byte(k))
+		writeString(&buf, []byte(props.UserProperties[k]))
+	}
+
+	return buf.Bytes()
+}
+
+type connectPacket struct {
+	level    byte
+	clientID string
+	username string
+	password []byte
+}
+
+func parseConnect(body []byte) (connectPacket, error) {
+	r := reader{buf: body}
+	var p connectPacket
+
+	if name := r.string(); name != "MQTT" && name != "MQIsdp" {
+		return p, errMalformedPacket
+	}
+	p.level = r.byte()
+	flags := r.byte()
+	r.uint16()
+	if p.level == v5 {
+		r.properties()
+	}
+
+	p.clientID = r.string()
+	if flags&0x04 != 0 {
+		if p.level == v5 {
+			r.properties()
+		}
+		r.string()
+		r.bytes()
+	}
+	if flags&0x80 != 0 {
+		p.username = r.string()
+	}
+	if flags&0x40 != 0 {
+		p.password = r.bytes()
+	}
+
+	return p, r.err
+}
+
+type publishPacket struct {
+	header   byte
+	topic    string
+	id       uint16
+	props    Properties
+	rawProps []byte
+	payload  []byte
+}
+
+func (p publishPacket) qos() byte {
+	return (p.header >> 1) & 0x03
+}
+
+func parsePublish(pkt packet, level byte) (publishPacket, error) {
+	r := reader{buf: pkt.body}
+	p := publishPacket{header: pkt.header}
+
+	p.topic = r.string()
+	if p.qos() > 0 {
+		p.id = r.uint16()
+	}
+	if level == v5 {
+		p.rawProps = r.properties()
+	}
+	if r.err != nil {
+		return p, r.err
+	}
+	if p.qos() > 2 {
+		return p, errMalformedPacket
+	}
+
+	var err error
+	if p.props, err = parseProperties(p.rawProps); err != nil {
+		return p, err
+	}
+	p.payload = r.buf
+
+	return p, nil
+}
+
+func (p publishPacket) encode(level byte) packet {
+	var buf bytes.Buffer
+	writeString(&buf, []byte(p.topic))
+	if p.qos() > 0 {
+		var id [2]byte
+		binary.BigEndian.PutUint16(id[:], p.id)
+		buf.Write(id[:])
+	}
+	if level == v5 {
+		writeVarint(&buf, len(p.rawProps))
+		buf.Write(p.rawProps)
+	}
+	buf.Write(p.payload)
+
+	return packet{header: p.header, body: buf.Bytes()}
+}
+
+// parseSubscribe returns the packet identifier and the topic filters of the
+// SUBSCRIBE or UNSUBSCRIBE packet.
+func parseSubscribe(pkt packet, level byte) (uint16, []string, error) {
+	r := reader{buf: pkt.body}
+
+	id := r.uint16()
+	if level == v5 {
+		r.properties()
+	}
+
+	var topics []string
+	for !r.empty() && r.err == nil {
+		topics = append(topics, r.string())
+		if pkt.kind() == subscribeType {
+			r.byte()
+		}
+	}
+	if r.err == nil && len(topics) == 0 {
+		return 0, nil, errMalformedPacket
+	}
+
+	return id, topics, r.err
+}
+
+// parseSuback returns the packet identifier and the reason codes of the
+// SUBACK packet.
+func parseSuback(pkt packet, level byte) (uint16, []byte, error) {
+	r := reader{buf: pkt.body}
+
+	id := r.uint16()
+	if level == v5 {
+		r.properties()
+	}
+	if r.err != nil {
+		return 0, nil, r.err
+	}
+
+	return id, r.buf, nil
+}
+
+// parsePubrel returns the packet identifier of the PUBREL packet.
+func parsePubrel(pkt packet) (uint16, error) {
+	r := reader{buf: pkt.body}
+	id := r.uint16()
+	return id, r.err
+}
+
+func connack(level, code byte) packet {
+	if level == v5 {
+		return packet{header: connackType << 4, body: []byte{0, code, 0}}
+	}
+	return packet{header: connackType << 4, body: []byte{0, code}}
+}
+
+// puback returns the PUBACK or PUBREC packet, depending on the QoS.
+func puback(qos byte, id uint16, code byte) packet {
+	kind := pubackType
+	if qos == 2 {
+		kind = pubrecType
+	}
+	body := make([]byte, 3)
+	binary.BigEndian.PutUint16(body, id)
+	body[2] = code
+	return packet{header: kind << 4, body: body}
+}
+
+// pubcomp returns the PUBCOMP packet completing the QoS 2 flow of the
+// packet identifier.
+func pubcomp(id uint16) packet {
+	body := make([]byte, 2)
+	binary.BigEndian.PutUint16(body, id)
+	return packet{header: pubcompType << 4, body: body}
+}
+
+func suback(level byte, id uint16, codes []byte) packet {
+	var buf bytes.Buffer
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], id)
+	buf.Write(b[:])
+	if level == v5 {
+		buf.WriteByte(0)
+	}
+	buf.Write(codes)
+	return packet{header: subackType << 4, body: buf.Bytes()}
+}
+
+func disconnect(code byte) packet {
+	return packet{header: disconnectType << 4, body: []byte{code, 0}}
+}
@@ -0,0 +1,521 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/quota"
+	"github.com/mainflux/mainflux/pkg/schema"
+	"github.com/mainflux/mproxy/pkg/session"
+	mptls "github.com/mainflux/mproxy/pkg/tls"
+)
+
+// MQTT 5 reason codes.
+const (
+	codeUnspecified        byte = 0x80
+	codeMalformedPacket    byte = 0x81
+	codeBadCredentials     byte = 0x86
+	codeNotAuthorized      byte = 0x87
+	codeTopicFilterInvalid byte = 0x8F
+	codeTopicNameInvalid   byte = 0x90
+	codeTopicAliasInvalid  byte = 0x94
+	codePacketTooLarge     byte = 0x95
+	codeQuotaExceeded      byte = 0x97
+	codePayloadInvalid     byte = 0x99
+)
+
+// MQTT 3.1.1 CONNACK return codes.
+const (
+	connBadProtocol    byte = 0x01
+	connBadCredentials byte = 0x04
+	connNotAuthorized  byte = 0x05
+)
+
+var (
+	errRejected         = errors.New("client rejected")
+	errInvalidAlias     = errors.New("invalid topic alias")
+	errConnectExpected  = errors.New("first packet must be CONNECT")
+	errProtocolMismatch = errors.New("unsupported protocol level")
+)
+
+// PropertiesHandler is implemented by the session handlers supporting the
+// MQTT 5 publish properties. The proxy calls it instead of the AuthPublish
+// and Publish methods of the session handler.
+type PropertiesHandler interface {
+	// AuthPublishProperties authorizes the client PUBLISH with the
+	// given properties.
+	AuthPublishProperties(c *session.Client, topic *string, payload *[]byte, props *Properties) error
+
+	// PublishProperties is called after the client successfully published
+	// the message with the given properties.
+	PublishProperties(c *session.Client, topic *string, payload *[]byte, props *Properties)
+}
+
+// AddressHandler is implemented by the session handlers tracking the network
+// address of the clients. The proxy calls it instead of the AuthConnect
+// method of the session handler.
+type AddressHandler interface {
+	// AuthConnectAddress authorizes the client CONNECT received from the
+	// given remote address.
+	AuthConnectAddress(c *session.Client, addr net.Addr) error
+}
+
+// RetainedMessage represents the message sent to the client right after it
+// subscribed to the message topic.
+type RetainedMessage struct {
+	Topic   string
+	Payload []byte
+	Props   Properties
+}
+
+// RetainedHandler is implemented by the session handlers providing the
+// retained messages. The proxy sends them to the client after the broker
+// acknowledges the subscription.
+type RetainedHandler interface {
+	// Retained returns the retained messages matching the topic filters.
+	Retained(c *session.Client, filters []string) []RetainedMessage
+}
+
+// Proxy forwards the MQTT 3.1.1 and MQTT 5 traffic between the clients and
+// the broker. Unlike mProxy, the rejected client packets are answered with
+// the reason codes, and the connection is closed only if the protocol
+// requires it.
+type Proxy struct {
+	address       string
+	target        string
+	maxPacketSize int
+	handler       session.Handler
+	logger        logger.Logger
+	dialer        net.Dialer
+}
+
+// NewProxy returns the MQTT proxy listening on the address and forwarding
+// the traffic to the target broker. Client packets larger than the maximum
+// packet size are rejected before they are read, zero means no limit.
+func NewProxy(address, target string, maxPacketSize int, handler session.Handler, logger logger.Logger) *Proxy {
+	return &Proxy{
+		address:       address,
+		target:        target,
+		maxPacketSize: maxPacketSize,
+		handler:       handler,
+		logger:        logger,
+	}
+}
+
+// Listen accepts the client connections, and blocks until the listener
+// fails.
+func (p *Proxy) Listen() error {
+	l, err := net.Listen("tcp", p.address)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			p.logger.Warn("Accept error " + err.Error())
+			continue
+		}
+		go p.handle(conn)
+	}
+}
+
+func (p *Proxy) handle(inbound net.Conn) {
+	defer inbound.Close()
+	outbound, err := p.dialer.Dial("tcp", p.target)
+	if err != nil {
+		p.logger.Error("Cannot connect to remote broker " + p.target + " due to: " + err.Error())
+		return
+	}
+	defer outbound.Close()
+
+	cert, err := mptls.ClientCert(inbound)
+	if err != nil {
+		p.logger.Error("Failed to get client certificate: " + err.Error())
+		return
+	}
+
+	serve(inbound, outbound, cert, p.maxPacketSize, p.handler, p.logger)
+}
+
+// serve proxies the client connection until either side fails, and is
+// shared by the TCP and WebSocket proxies.
+func serve(inbound, outbound net.Conn, cert x509.Certificate, maxPacketSize int, h session.Handler, logger logger.Logger) {
+	s := newProxySession(inbound, outbound, h, logger)
+	s.client.Cert = cert
+	s.maxPacketSize = maxPacketSize
+	if err := s.stream(); !errors.Contains(err, io.EOF) {
+		logger.Warn(fmt.Sprintf("Broken connection for client: %s with error: %s", s.client.ID, err))
+	}
+}
+
+type proxySession struct {
+	inbound  net.Conn
+	outbound net.Conn
+	handler  session.Handler
+	props    PropertiesHandler
+	retained RetainedHandler
+	address  AddressHandler
+	logger   logger.Logger
+	client   session.Client
+	level    byte
+	aliases  map[uint16]string
+	// maxPacketSize is the maximum size of the client packets, 0 if
+	// the size is not limited.
+	maxPacketSize int
+	// Both directions write to the client.
+	mu sync.Mutex
+	// pending contains the topic filters of the forwarded SUBSCRIBE
+	// packets waiting for the SUBACK, by the packet identifier.
+	pending map[uint16][]string
+	// rejected contains the identifiers of the rejected QoS 2 PUBLISH
+	// packets, whose PUBREL is answered by the proxy since the broker
+	// never received them. It's used only by the client direction.
+	rejected map[uint16]struct{}
+}
+
+func newProxySession(inbound, outbound net.Conn, h session.Handler, logger logger.Logger) *proxySession {
+	props, _ := h.(PropertiesHandler)
+	retained, _ := h.(RetainedHandler)
+	address, _ := h.(AddressHandler)
+	return &proxySession{
+		inbound:  inbound,
+		outbound: outbound,
+		handler:  h,
+		props:    props,
+		retained: retained,
+		address:  address,
+		logger:   logger,
+		aliases:  make(map[uint16]string),
+		pending:  make(map[uint16][]string),
+		rejected: make(map[uint16]struct{}),
+	}
+}
+
+// stream proxies the traffic until either side fails or the client is
+// rejected.
+func (s *proxySession) stream() error {
+	errs := make(chan error, 2)
+	go func() {
+		errs <- s.up()
+	}()
+	go func() {
+		errs <- s.down()
+	}()
+
+	err := <-errs
+	s.handler.Disconnect(&s.client)
+	return err
+}
+
+func (s *proxySession) down() error {
+	for {
+		pkt, err := readPacket(s.outbound, 0)
+		if err != nil {
+			return err
+		}
+		if err := s.reply(pkt); err != nil {
+			return err
+		}
+		if pkt.kind() == subackType {
+			if err := s.sendRetained(pkt); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendRetained sends the retained messages of the topic filters granted by
+// the SUBACK. The messages are sent with QoS 0, so that the proxy doesn't
+// have to allocate the packet identifiers.
+func (s *proxySession) sendRetained(pkt packet) error {
+	id, codes, err := parseSuback(pkt, s.level)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	filters := s.pending[id]
+	delete(s.pending, id)
+	s.mu.Unlock()
+	if s.retained == nil || len(filters) != len(codes) {
+		return nil
+	}
+
+	var granted []string
+	for i, f := range filters {
+		if codes[i] < codeUnspecified {
+			granted = append(granted, f)
+		}
+	}
+	if len(granted) == 0 {
+		return nil
+	}
+
+	for _, msg := range s.retained.Retained(&s.client, granted) {
+		p := publishPacket{
+			header:  publishType<<4 | retainFlag,
+			topic:   msg.Topic,
+			payload: msg.Payload,
+		}
+		if s.level == v5 {
+			p.rawProps = encodeProperties(msg.Props)
+		}
+		if err := s.reply(p.encode(s.level)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *proxySession) reply(pkt packet) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return pkt.write(s.inbound)
+}
+
+func (s *proxySession) up() error {
+	for {
+		pkt, err := readPacket(s.inbound, s.maxPacketSize)
+		if errors.Contains(err, errPacketTooLarge) {
+			return s.disconnect(codePacketTooLarge, err)
+		}
+		if err != nil {
+			return err
+		}
+		if s.level == 0 && pkt.kind() != connectType {
+			return errConnectExpected
+		}
+
+		switch pkt.kind() {
+		case connectType:
+			err = s.connect(pkt)
+		case publishType:
+			err = s.publish(pkt)
+		case pubrelType:
+			err = s.pubrel(pkt)
+		case subscribeType:
+			err = s.subscribe(pkt)
+		case unsubscribeType:
+			err = s.unsubscribe(pkt)
+		default:
+			err = pkt.write(s.outbound)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *proxySession) connect(pkt packet) error {
+	p, err := parseConnect(pkt.body)
+	if err != nil {
+		return err
+	}
+	if p.level < 3 || p.level > v5 {
+		s.reply(connack(v311, connBadProtocol))
+		return errProtocolMismatch
+	}
+	s.level = p.level
+
+	s.client.ID = p.clientID
+	s.client.Username = p.username
+	s.client.Password = p.password
+	if err := s.authConnect(); err != nil {
+		s.reply(connack(s.level, s.connectCode(err)))
+		return errors.Wrap(errRejected, err)
+	}
+
+	if err := pkt.write(s.outbound); err != nil {
+		return err
+	}
+	s.handler.Connect(&s.client)
+	return nil
+}
+
+func (s *proxySession) authConnect() error {
+	if s.address != nil {
+		return s.address.AuthConnectAddress(&s.client, s.inbound.RemoteAddr())
+	}
+	return s.handler.AuthConnect(&s.client)
+}
+
+func (s *proxySession) publish(pkt packet) error {
+	p, err := parsePublish(pkt, s.level)
+	if err != nil {
+		return s.disconnect(codeMalformedPacket, err)
+	}
+
+	topic := p.topic
+	if p.props.topicAlias != 0 {
+		switch {
+		case topic != "":
+			s.aliases[p.props.topicAlias] = topic
+		case s.aliases[p.props.topicAlias] != "":
+			topic = s.aliases[p.props.topicAlias]
+		default:
+			return s.disconnect(codeTopicAliasInvalid, errInvalidAlias)
+		}
+	}
+	payload := p.payload
+
+	if s.props != nil {
+		err = s.props.AuthPublishProperties(&s.client, &topic, &payload, &p.props)
+	} else {
+		err = s.handler.AuthPublish(&s.client, &topic, &payload)
+	}
+	if err != nil {
+		code := publishCode(err)
+		switch {
+		case s.level != v5:
+			// MQTT 3.1.1 can't report the reason of the rejected publish.
+			return errors.Wrap(errRejected, err)
+		case p.qos() == 0, code == codePacketTooLarge:
+			// Packet Too Large is not a valid PUBACK reason code.
+			return s.disconnect(code, err)
+		default:
+			s.logger.Info(fmt.Sprintf("Rejected publish - client ID %s to the topic %s: %s", s.client.ID, topic, err))
+			if p.qos() == 2 {
+				s.rejected[p.id] = struct{}{}
+			}
+			return s.reply(puback(p.qos(), p.id, code))
+		}
+	}
+	// The identifier of the rejected packet may be reused once its flow
+	// is complete.
+	delete(s.rejected, p.id)
+
+	// Re-encode the packet if the handler modified it.
+	if (p.topic != "" && topic != p.topic) || !bytes.Equal(payload, p.payload) {
+		p.topic, p.payload = topic, payload
+		pkt = p.encode(s.level)
+	}
+	if err := pkt.write(s.outbound); err != nil {
+		return err
+	}
+
+	if s.props != nil {
+		s.props.PublishProperties(&s.client, &topic, &payload, &p.props)
+		return nil
+	}
+	s.handler.Publish(&s.client, &topic, &payload)
+	return nil
+}
+
+// pubrel completes the QoS 2 flow of the rejected PUBLISH packets, and
+// forwards the other PUBREL packets to the broker. Otherwise, the broker
+// would receive the PUBREL of the packet it has never seen.
+func (s *proxySession) pubrel(pkt packet) error {
+	id, err := parsePubrel(pkt)
+	if err != nil {
+		return s.disconnect(codeMalformedPacket, err)
+	}
+	if _, ok := s.rejected[id]; !ok {
+		return pkt.write(s.outbound)
+	}
+	delete(s.rejected, id)
+	return s.reply(pubcomp(id))
+}
+
+func (s *proxySession) subscribe(pkt packet) error {
+	id, topics, err := parseSubscribe(pkt, s.level)
+	if err != nil {
+		return s.disconnect(codeMalformedPacket, err)
+	}
+
+	if err := s.handler.AuthSubscribe(&s.client, &topics); err != nil {
+		s.logger.Info(fmt.Sprintf("Rejected subscribe - client ID %s to the topics %v: %s", s.client.ID, topics, err))
+		codes := make([]byte, len(topics))
+		for i := range codes {
+			codes[i] = s.subscribeCode(err)
+		}
+		return s.reply(suback(s.level, id, codes))
+	}
+
+	if s.retained != nil {
+		s.mu.Lock()
+		s.pending[id] = topics
+		s.mu.Unlock()
+	}
+	if err := pkt.write(s.outbound); err != nil {
+		return err
+	}
+	s.handler.Subscribe(&s.client, &topics)
+	return nil
+}
+
+func (s *proxySession) unsubscribe(pkt packet) error {
+	_, topics, err := parseSubscribe(pkt, s.level)
+	if err != nil {
+		return s.disconnect(codeMalformedPacket, err)
+	}
+
+	if err := pkt.write(s.outbound); err != nil {
+		return err
+	}
+	s.handler.Unsubscribe(&s.client, &topics)
+	return nil
+}
+
+// disconnect sends the MQTT 5 DISCONNECT with the reason code to the
+// client, and returns the error closing the connection.
+func (s *proxySession) disconnect(code byte, err error) error {
+	if s.level == v5 {
+		s.reply(disconnect(code))
+	}
+	return errors.Wrap(errRejected, err)
+}
+
+func (s *proxySession) connectCode(err error) byte {
+	auth := errors.Contains(err, errors.ErrAuthentication)
+	switch {
+	case s.level == v5 && auth:
+		return codeBadCredentials
+	case s.level == v5:
+		return codeNotAuthorized
+	case auth:
+		return connBadCredentials
+	default:
+		return connNotAuthorized
+	}
+}
+
+func (s *proxySession) subscribeCode(err error) byte {
+	switch {
+	case s.level != v5:
+		return codeUnspecified
+	case errors.Contains(err, errMalformedTopic):
+		return codeTopicFilterInvalid
+	default:
+		return codeNotAuthorized
+	}
+}
+
+func publishCode(err error) byte {
+	switch {
+	case errors.Contains(err, errMalformedTopic), errors.Contains(err, errMalformedSubtopic):
+		return codeTopicNameInvalid
+	case errors.Contains(err, schema.ErrInvalidPayload):
+		return codePayloadInvalid
+	case errors.Contains(err, schema.ErrRetrieveSchema):
+		return codeUnspecified
+	case errors.Contains(err, quota.ErrRateLimited):
+		return codeQuotaExceeded
+	case errors.Contains(err, quota.ErrPayloadTooLarge):
+		return codePacketTooLarge
+	case errors.Contains(err, quota.ErrRetrieveQuota):
+		return codeUnspecified
+	default:
+		return codeNotAuthorized
+	}
+}
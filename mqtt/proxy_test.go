@@ -0,0 +1,570 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/quota"
+	"github.com/mainflux/mainflux/pkg/schema"
+	"github.com/mainflux/mproxy/pkg/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validKey     = "valid"
+	allowedTopic = "channels/allowed/messages"
+	deniedTopic  = "channels/denied/messages"
+	invalidTopic = "channels/invalid/messages"
+	limitedTopic = "channels/limited/messages"
+	timeout      = time.Second
+)
+
+// fakeHandler accepts the valid key, and authorizes the allowed topic.
+type fakeHandler struct {
+	mu        sync.Mutex
+	published []Properties
+	topics    []string
+	addr      string
+}
+
+func (h *fakeHandler) AuthConnect(c *session.Client) error {
+	if string(c.Password) != validKey {
+		return errors.ErrAuthentication
+	}
+	return nil
+}
+
+func (h *fakeHandler) AuthConnectAddress(c *session.Client, addr net.Addr) error {
+	h.mu.Lock()
+	h.addr = addr.String()
+	h.mu.Unlock()
+	return h.AuthConnect(c)
+}
+
+func (h *fakeHandler) AuthPublish(c *session.Client, topic *string, payload *[]byte) error {
+	return h.AuthPublishProperties(c, topic, payload, nil)
+}
+
+func (h *fakeHandler) AuthPublishProperties(c *session.Client, topic *string, payload *[]byte, props *Properties) error {
+	switch *topic {
+	case allowedTopic:
+		return nil
+	case invalidTopic:
+		return errors.Wrap(schema.ErrInvalidPayload, errors.New("invalid"))
+	case limitedTopic:
+		return quota.ErrRateLimited
+	default:
+		return errors.ErrAuthorization
+	}
+}
+
+func (h *fakeHandler) AuthSubscribe(c *session.Client, topics *[]string) error {
+	for _, t := range *topics {
+		if t != allowedTopic {
+			return errors.ErrAuthorization
+		}
+	}
+	return nil
+}
+
+func (h *fakeHandler) Connect(c *session.Client) {}
+
+func (h *fakeHandler) Publish(c *session.Client, topic *string, payload *[]byte) {
+	h.PublishProperties(c, topic, payload, &Properties{})
+}
+
+func (h *fakeHandler) PublishProperties(c *session.Client, topic *string, payload *[]byte, props *Properties) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.published = append(h.published, *props)
+	h.topics = append(h.topics, *topic)
+}
+
+func (h *fakeHandler) Retained(c *session.Client, filters []string) []RetainedMessage {
+	return []RetainedMessage{
+		{
+			Topic:   allowedTopic,
+			Payload: []byte("retained"),
+			Props:   Properties{ContentType: "application/senml+json"},
+		},
+	}
+}
+
+func (h *fakeHandler) Subscribe(c *session.Client, topics *[]string)   {}
+func (h *fakeHandler) Unsubscribe(c *session.Client, topics *[]string) {}
+func (h *fakeHandler) Disconnect(c *session.Client)                    {}
+
+type testConn struct {
+	client net.Conn
+	broker net.Conn
+	done   chan error
+}
+
+func newTestConn(h session.Handler, maxPacketSize int) testConn {
+	client, inbound := net.Pipe()
+	outbound, broker := net.Pipe()
+	s := newProxySession(inbound, outbound, h, logger.NewMock())
+	s.maxPacketSize = maxPacketSize
+
+	tc := testConn{client: client, broker: broker, done: make(chan error, 1)}
+	go func() {
+		err := s.stream()
+		inbound.Close()
+		outbound.Close()
+		tc.done <- err
+	}()
+	return tc
+}
+
+func (tc testConn) close() {
+	tc.client.Close()
+	tc.broker.Close()
+}
+
+func (tc testConn) send(t *testing.T, pkt packet) {
+	tc.client.SetWriteDeadline(time.Now().Add(timeout))
+	err := pkt.write(tc.client)
+	require.Nil(t, err, fmt.Sprintf("unexpected error writing packet: %s", err))
+}
+
+func read(t *testing.T, conn net.Conn) packet {
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	pkt, err := readPacket(conn, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error reading packet: %s", err))
+	return pkt
+}
+
+func connectPkt(level byte, key string) packet {
+	var buf bytes.Buffer
+	writeString(&buf, []byte("MQTT"))
+	buf.WriteByte(level)
+	buf.WriteByte(0xC2) // Username, password and clean session.
+	buf.Write([]byte{0, 60})
+	if level == v5 {
+		buf.WriteByte(0)
+	}
+	writeString(&buf, []byte("client"))
+	writeString(&buf, []byte("thing"))
+	writeString(&buf, []byte(key))
+	return packet{header: connectType << 4, body: buf.Bytes()}
+}
+
+func publishPkt(qos byte, topic string, id uint16, props []byte, payload string) packet {
+	var buf bytes.Buffer
+	writeString(&buf, []byte(topic))
+	if qos > 0 {
+		binary.Write(&buf, binary.BigEndian, id)
+	}
+	if props != nil {
+		writeVarint(&buf, len(props))
+		buf.Write(props)
+	}
+	buf.WriteString(payload)
+	return packet{header: publishType<<4 | qos<<1, body: buf.Bytes()}
+}
+
+func subscribePkt(level byte, id uint16, topics ...string) packet {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, id)
+	if level == v5 {
+		buf.WriteByte(0)
+	}
+	for _, t := range topics {
+		writeString(&buf, []byte(t))
+		buf.WriteByte(1)
+	}
+	return packet{header: subscribeType<<4 | 0x02, body: buf.Bytes()}
+}
+
+func pubrelPkt(id uint16) packet {
+	body := make([]byte, 2)
+	binary.BigEndian.PutUint16(body, id)
+	return packet{header: pubrelType<<4 | 0x02, body: body}
+}
+
+func TestConnect(t *testing.T) {
+	cases := []struct {
+		desc   string
+		level  byte
+		key    string
+		code   byte
+		accept bool
+	}{
+		{
+			desc:   "connect MQTT 5 client",
+			level:  v5,
+			key:    validKey,
+			accept: true,
+		},
+		{
+			desc:  "connect MQTT 5 client with invalid key",
+			level: v5,
+			key:   "invalid",
+			code:  codeBadCredentials,
+		},
+		{
+			desc:  "connect MQTT 3.1.1 client with invalid key",
+			level: v311,
+			key:   "invalid",
+			code:  connBadCredentials,
+		},
+		{
+			desc:  "connect client with unsupported protocol level",
+			level: 6,
+			key:   validKey,
+			code:  connBadProtocol,
+		},
+	}
+
+	for _, tc := range cases {
+		h := &fakeHandler{}
+		conn := newTestConn(h, 0)
+		conn.send(t, connectPkt(tc.level, tc.key))
+
+		if tc.accept {
+			pkt := read(t, conn.broker)
+			assert.Equal(t, connectPkt(tc.level, tc.key), pkt, fmt.Sprintf("%s: expected CONNECT to be forwarded", tc.desc))
+			h.mu.Lock()
+			assert.Equal(t, "pipe", h.addr, fmt.Sprintf("%s: expected client address to be passed to handler", tc.desc))
+			h.mu.Unlock()
+			conn.close()
+			continue
+		}
+
+		pkt := read(t, conn.client)
+		assert.Equal(t, connackType, pkt.kind(), fmt.Sprintf("%s: expected CONNACK got %d", tc.desc, pkt.kind()))
+		assert.Equal(t, tc.code, pkt.body[1], fmt.Sprintf("%s: expected code %#x got %#x", tc.desc, tc.code, pkt.body[1]))
+		err := <-conn.done
+		assert.True(t, errors.Contains(err, errRejected) || errors.Contains(err, errProtocolMismatch), fmt.Sprintf("%s: expected connection to be closed, got %s", tc.desc, err))
+		conn.close()
+	}
+}
+
+func TestPublish(t *testing.T) {
+	var props bytes.Buffer
+	props.WriteByte(propContentType)
+	writeString(&props, []byte("application/senml+cbor"))
+	props.WriteByte(propMessageExpiry)
+	binary.Write(&props, binary.BigEndian, uint32(60))
+	props.WriteByte(propResponseTopic)
+	writeString(&props, []byte("channels/allowed/messages/res"))
+	props.WriteByte(propCorrelationData)
+	writeString(&props, []byte{1, 2})
+	props.WriteByte(propUserProperty)
+	writeString(&props, []byte("key"))
+	writeString(&props, []byte("value"))
+	props.WriteByte(propTopicAlias)
+	binary.Write(&props, binary.BigEndian, uint16(1))
+
+	var alias bytes.Buffer
+	alias.WriteByte(propTopicAlias)
+	binary.Write(&alias, binary.BigEndian, uint16(1))
+
+	h := &fakeHandler{}
+	conn := newTestConn(h, 0)
+	defer conn.close()
+
+	conn.send(t, connectPkt(v5, validKey))
+	read(t, conn.broker)
+
+	allowed := publishPkt(1, allowedTopic, 1, props.Bytes(), "payload")
+	conn.send(t, allowed)
+	assert.Equal(t, allowed, read(t, conn.broker), "expected authorized PUBLISH to be forwarded")
+
+	aliased := publishPkt(0, "", 0, alias.Bytes(), "payload")
+	conn.send(t, aliased)
+	assert.Equal(t, aliased, read(t, conn.broker), "expected aliased PUBLISH to be forwarded")
+
+	cases := []struct {
+		desc string
+		pkt  packet
+		ack  packet
+	}{
+		{
+			desc: "publish QoS 1 message to unauthorized topic",
+			pkt:  publishPkt(1, deniedTopic, 2, []byte{}, "payload"),
+			ack:  packet{header: pubackType << 4, body: []byte{0, 2, codeNotAuthorized}},
+		},
+		{
+			desc: "publish QoS 2 message with invalid payload",
+			pkt:  publishPkt(2, invalidTopic, 3, []byte{}, "payload"),
+			ack:  packet{header: pubrecType << 4, body: []byte{0, 3, codePayloadInvalid}},
+		},
+		{
+			desc: "publish QoS 1 message exceeding the quota",
+			pkt:  publishPkt(1, limitedTopic, 4, []byte{}, "payload"),
+			ack:  packet{header: pubackType << 4, body: []byte{0, 4, codeQuotaExceeded}},
+		},
+	}
+	for _, tc := range cases {
+		conn.send(t, tc.pkt)
+		assert.Equal(t, tc.ack, read(t, conn.client), fmt.Sprintf("%s: unexpected acknowledgement", tc.desc))
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	expected := Properties{
+		ContentType:     "application/senml+cbor",
+		ResponseTopic:   "channels/allowed/messages/res",
+		CorrelationData: []byte{1, 2},
+		MessageExpiry:   60,
+		UserProperties:  map[string]string{"key": "value"},
+		topicAlias:      1,
+	}
+	require.Len(t, h.published, 2, fmt.Sprintf("expected 2 published messages got %d", len(h.published)))
+	assert.Equal(t, expected, h.published[0], "unexpected publish properties")
+	assert.Equal(t, []string{allowedTopic, allowedTopic}, h.topics, "expected aliased topic to be resolved")
+}
+
+func TestPubrel(t *testing.T) {
+	conn := newTestConn(&fakeHandler{}, 0)
+	defer conn.close()
+
+	conn.send(t, connectPkt(v5, validKey))
+	read(t, conn.broker)
+
+	allowed := publishPkt(2, allowedTopic, 1, []byte{}, "payload")
+	conn.send(t, allowed)
+	read(t, conn.broker)
+
+	conn.send(t, publishPkt(2, deniedTopic, 2, []byte{}, "payload"))
+	read(t, conn.client)
+
+	cases := []struct {
+		desc   string
+		pkt    packet
+		conn   net.Conn
+		expect packet
+	}{
+		{
+			desc:   "release authorized QoS 2 message",
+			pkt:    pubrelPkt(1),
+			conn:   conn.broker,
+			expect: pubrelPkt(1),
+		},
+		{
+			desc:   "release rejected QoS 2 message",
+			pkt:    pubrelPkt(2),
+			conn:   conn.client,
+			expect: packet{header: pubcompType << 4, body: []byte{0, 2}},
+		},
+		{
+			desc:   "release rejected QoS 2 message twice",
+			pkt:    pubrelPkt(2),
+			conn:   conn.broker,
+			expect: pubrelPkt(2),
+		},
+	}
+	for _, tc := range cases {
+		conn.send(t, tc.pkt)
+		assert.Equal(t, tc.expect, read(t, tc.conn), fmt.Sprintf("%s: unexpected packet", tc.desc))
+	}
+}
+
+func TestPublishQoS0(t *testing.T) {
+	cases := []struct {
+		desc  string
+		level byte
+		props []byte
+		pkt   *packet
+	}{
+		{
+			desc:  "publish MQTT 5 message to unauthorized topic",
+			level: v5,
+			props: []byte{},
+			pkt:   &packet{header: disconnectType << 4, body: []byte{codeNotAuthorized, 0}},
+		},
+		{
+			desc:  "publish MQTT 3.1.1 message to unauthorized topic",
+			level: v311,
+		},
+	}
+
+	for _, tc := range cases {
+		conn := newTestConn(&fakeHandler{}, 0)
+		conn.send(t, connectPkt(tc.level, validKey))
+		read(t, conn.broker)
+
+		conn.send(t, publishPkt(0, deniedTopic, 0, tc.props, "payload"))
+		if tc.pkt != nil {
+			assert.Equal(t, *tc.pkt, read(t, conn.client), fmt.Sprintf("%s: expected DISCONNECT", tc.desc))
+		}
+		err := <-conn.done
+		assert.True(t, errors.Contains(err, errRejected), fmt.Sprintf("%s: expected connection to be closed, got %s", tc.desc, err))
+		conn.close()
+	}
+}
+
+func TestPacketTooLarge(t *testing.T) {
+	maxSize := 64
+	tooLarge := packet{header: disconnectType << 4, body: []byte{codePacketTooLarge, 0}}
+
+	cases := []struct {
+		desc  string
+		level byte
+		pkt   *packet
+	}{
+		{
+			desc:  "publish MQTT 5 packet exceeding the maximum packet size",
+			level: v5,
+			pkt:   &tooLarge,
+		},
+		{
+			desc:  "publish MQTT 3.1.1 packet exceeding the maximum packet size",
+			level: v311,
+		},
+	}
+
+	for _, tc := range cases {
+		conn := newTestConn(&fakeHandler{}, maxSize)
+		conn.send(t, connectPkt(tc.level, validKey))
+		read(t, conn.broker)
+
+		small := publishPkt(0, allowedTopic, 0, propsFor(tc.level), "payload")
+		conn.send(t, small)
+		assert.Equal(t, small, read(t, conn.broker), fmt.Sprintf("%s: expected PUBLISH within the limit to be forwarded", tc.desc))
+
+		// The proxy rejects the packet without reading its body, so the
+		// write completes only once the connection is closed.
+		large := publishPkt(0, allowedTopic, 0, propsFor(tc.level), string(make([]byte, maxSize)))
+		go large.write(conn.client)
+		if tc.pkt != nil {
+			assert.Equal(t, *tc.pkt, read(t, conn.client), fmt.Sprintf("%s: expected DISCONNECT", tc.desc))
+		}
+		err := <-conn.done
+		assert.True(t, errors.Contains(err, errPacketTooLarge), fmt.Sprintf("%s: expected %s got %s", tc.desc, errPacketTooLarge, err))
+		conn.close()
+	}
+}
+
+// propsFor returns the empty properties of the MQTT 5 packets.
+func propsFor(level byte) []byte {
+	if level == v5 {
+		return []byte{}
+	}
+	return nil
+}
+
+func TestSubscribe(t *testing.T) {
+	cases := []struct {
+		desc  string
+		level byte
+		code  byte
+	}{
+		{
+			desc:  "subscribe MQTT 5 client to unauthorized topic",
+			level: v5,
+			code:  codeNotAuthorized,
+		},
+		{
+			desc:  "subscribe MQTT 3.1.1 client to unauthorized topic",
+			level: v311,
+			code:  codeUnspecified,
+		},
+	}
+
+	for _, tc := range cases {
+		conn := newTestConn(&fakeHandler{}, 0)
+		conn.send(t, connectPkt(tc.level, validKey))
+		read(t, conn.broker)
+
+		sub := subscribePkt(tc.level, 7, allowedTopic)
+		conn.send(t, sub)
+		assert.Equal(t, sub, read(t, conn.broker), fmt.Sprintf("%s: expected authorized SUBSCRIBE to be forwarded", tc.desc))
+
+		conn.send(t, subscribePkt(tc.level, 8, allowedTopic, deniedTopic))
+		expected := suback(tc.level, 8, []byte{tc.code, tc.code})
+		assert.Equal(t, expected, read(t, conn.client), fmt.Sprintf("%s: unexpected SUBACK", tc.desc))
+		conn.close()
+	}
+}
+
+func TestRetained(t *testing.T) {
+	var props bytes.Buffer
+	props.WriteByte(propContentType)
+	writeString(&props, []byte("application/senml+json"))
+
+	cases := []struct {
+		desc  string
+		level byte
+		codes []byte
+		pkt   *packet
+	}{
+		{
+			desc:  "receive retained message after MQTT 5 subscription",
+			level: v5,
+			codes: []byte{1},
+			pkt:   &packet{header: publishType<<4 | retainFlag, body: publishPkt(0, allowedTopic, 0, props.Bytes(), "retained").body},
+		},
+		{
+			desc:  "receive retained message after MQTT 3.1.1 subscription",
+			level: v311,
+			codes: []byte{0},
+			pkt:   &packet{header: publishType<<4 | retainFlag, body: publishPkt(0, allowedTopic, 0, nil, "retained").body},
+		},
+		{
+			desc:  "receive no retained message after rejected subscription",
+			level: v5,
+			codes: []byte{codeUnspecified},
+		},
+	}
+
+	for _, tc := range cases {
+		conn := newTestConn(&fakeHandler{}, 0)
+		conn.send(t, connectPkt(tc.level, validKey))
+		read(t, conn.broker)
+
+		conn.send(t, subscribePkt(tc.level, 7, allowedTopic))
+		read(t, conn.broker)
+
+		ack := suback(tc.level, 7, tc.codes)
+		conn.broker.SetWriteDeadline(time.Now().Add(timeout))
+		err := ack.write(conn.broker)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error writing SUBACK: %s", tc.desc, err))
+		assert.Equal(t, ack, read(t, conn.client), fmt.Sprintf("%s: expected SUBACK to be forwarded", tc.desc))
+
+		if tc.pkt != nil {
+			assert.Equal(t, *tc.pkt, read(t, conn.client), fmt.Sprintf("%s: unexpected retained message", tc.desc))
+		}
+		// The next packet forwarded to the client must not be a retained message.
+		pong := packet{header: pingrespType << 4, body: []byte{}}
+		conn.broker.SetWriteDeadline(time.Now().Add(timeout))
+		err = pong.write(conn.broker)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error writing PINGRESP: %s", tc.desc, err))
+		assert.Equal(t, pong, read(t, conn.client), fmt.Sprintf("%s: expected PINGRESP", tc.desc))
+		conn.close()
+	}
+}
+
+func TestMatchTopic(t *testing.T) {
+	cases := []struct {
+		filter string
+		topic  string
+		match  bool
+	}{
+		{"channels/1/messages", "channels/1/messages", true},
+		{"channels/1/messages", "channels/1/messages/temp", false},
+		{"channels/1/messages/#", "channels/1/messages", true},
+		{"channels/1/messages/#", "channels/1/messages/engine/temp", true},
+		{"channels/1/messages/+", "channels/1/messages/temp", true},
+		{"channels/1/messages/+", "channels/1/messages/engine/temp", false},
+		{"channels/1/messages/+/temp", "channels/1/messages/engine/temp", true},
+		{"spBv1.0/1/+/edge/#", "spBv1.0/1/DDATA/edge/device", true},
+	}
+
+	for _, tc := range cases {
+		match := matchTopic(tc.filter, tc.topic)
+		assert.Equal(t, tc.match, match, fmt.Sprintf("%s matching %s: expected %t got %t", tc.filter, tc.topic, tc.match, match))
+	}
+}
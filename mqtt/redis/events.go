@@ -13,16 +13,23 @@ var (
 
 type mqttEvent struct {
 	clientID  string
+	clientIP  string
 	timestamp string
 	eventType string
 	instance  string
 }
 
 func (me mqttEvent) Encode() map[string]interface{} {
-	return map[string]interface{}{
+	val := map[string]interface{}{
 		"thing_id":   me.clientID,
 		"timestamp":  me.timestamp,
 		"event_type": me.eventType,
 		"instance":   me.instance,
 	}
+
+	if me.clientIP != "" {
+		val["client_ip"] = me.clientIP
+	}
+
+	return val
 }
@@ -31,11 +31,12 @@ func NewEventStore(client *redis.Client, instance string) EventStore {
 	}
 }
 
-func (es EventStore) storeEvent(clientID, eventType string) error {
+func (es EventStore) storeEvent(clientID, clientIP, eventType string) error {
 	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
 
 	event := mqttEvent{
 		clientID:  clientID,
+		clientIP:  clientIP,
 		timestamp: timestamp,
 		eventType: eventType,
 		instance:  es.instance,
@@ -54,12 +55,12 @@ func (es EventStore) storeEvent(clientID, eventType string) error {
 	return nil
 }
 
-// Connect issues event on MQTT CONNECT
-func (es EventStore) Connect(clientID string) error {
-	return es.storeEvent(clientID, "connect")
+// Connect issues event on MQTT CONNECT from the given client IP
+func (es EventStore) Connect(clientID, clientIP string) error {
+	return es.storeEvent(clientID, clientIP, "connect")
 }
 
 // Disconnect issues event on MQTT CONNECT
 func (es EventStore) Disconnect(clientID string) error {
-	return es.storeEvent(clientID, "disconnect")
+	return es.storeEvent(clientID, "", "disconnect")
 }
@@ -0,0 +1,139 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mproxy/pkg/session"
+	mptls "github.com/mainflux/mproxy/pkg/tls"
+)
+
+var upgrader = websocket.Upgrader{
+	HandshakeTimeout: 10 * time.Second,
+	// Paho JS client expects the MQTT subprotocol in the upgrade response.
+	Subprotocols: []string{"mqttv3.1", "mqtt"},
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// WSProxy forwards the MQTT over WebSocket traffic between the clients and
+// the broker, handling the client packets the same way as the Proxy.
+type WSProxy struct {
+	target        url.URL
+	maxPacketSize int
+	handler       session.Handler
+	logger        logger.Logger
+	dialer        websocket.Dialer
+}
+
+// NewWSProxy returns the MQTT over WebSocket proxy forwarding the traffic
+// to the broker WebSocket endpoint on the target address and path. Client
+// packets larger than the maximum packet size are rejected before they are
+// read, zero means no limit.
+func NewWSProxy(target, path string, maxPacketSize int, handler session.Handler, logger logger.Logger) *WSProxy {
+	return &WSProxy{
+		target:        url.URL{Scheme: "ws", Host: target, Path: path},
+		maxPacketSize: maxPacketSize,
+		handler:       handler,
+		logger:        logger,
+		dialer:        websocket.Dialer{Subprotocols: []string{"mqtt"}},
+	}
+}
+
+// Handler returns the HTTP handler upgrading the client connections.
+func (p *WSProxy) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			p.logger.Warn("Failed to upgrade connection: " + err.Error())
+			return
+		}
+		go p.handle(conn)
+	})
+}
+
+func (p *WSProxy) handle(conn *websocket.Conn) {
+	inbound := newWSConn(conn)
+	defer inbound.Close()
+
+	srv, _, err := p.dialer.Dial(p.target.String(), nil)
+	if err != nil {
+		p.logger.Error("Cannot connect to remote broker " + p.target.String() + " due to: " + err.Error())
+		return
+	}
+	outbound := newWSConn(srv)
+	defer outbound.Close()
+
+	cert, err := mptls.ClientCert(conn.UnderlyingConn())
+	if err != nil {
+		p.logger.Error("Failed to get client certificate: " + err.Error())
+		return
+	}
+
+	serve(inbound, outbound, cert, p.maxPacketSize, p.handler, p.logger)
+}
+
+// wsConn adapts the WebSocket connection to the net.Conn, reading the
+// binary messages as a stream of bytes.
+type wsConn struct {
+	*websocket.Conn
+	r io.Reader
+	// Both directions write to the client.
+	mu sync.Mutex
+}
+
+func newWSConn(conn *websocket.Conn) net.Conn {
+	return &wsConn{Conn: conn}
+}
+
+func (c *wsConn) Read(b []byte) (int, error) {
+	for {
+		if c.r == nil {
+			_, r, err := c.NextReader()
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return 0, io.EOF
+			}
+			if err != nil {
+				return 0, err
+			}
+			c.r = r
+		}
+		n, err := c.r.Read(b)
+		if err == io.EOF {
+			c.r = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+// Write sends the bytes as a single binary message. The packets are written
+// at once, so each message contains the whole packet.
+func (c *wsConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.WriteMessage(websocket.BinaryMessage, b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (c *wsConn) SetDeadline(t time.Time) error {
+	if err := c.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWSProxy(t *testing.T) {
+	received := make(chan packet, 1)
+	broker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		c := newWSConn(conn)
+		defer c.Close()
+		for {
+			pkt, err := readPacket(c, 0)
+			if err != nil {
+				return
+			}
+			received <- pkt
+		}
+	}))
+	defer broker.Close()
+
+	target := strings.TrimPrefix(broker.URL, "http://")
+	proxy := httptest.NewServer(NewWSProxy(target, "/mqtt", 0, &fakeHandler{}, logger.NewMock()).Handler())
+	defer proxy.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(proxy.URL, "http"), nil)
+	require.Nil(t, err, fmt.Sprintf("unexpected error connecting to proxy: %s", err))
+	client := newWSConn(ws)
+	defer client.Close()
+
+	connect := connectPkt(v5, validKey)
+	require.Nil(t, connect.write(client), "unexpected error writing CONNECT")
+	assert.Equal(t, connect, <-received, "expected authorized CONNECT to be forwarded")
+
+	cases := []struct {
+		desc string
+		pkt  packet
+		ack  packet
+	}{
+		{
+			desc: "publish QoS 1 message to unauthorized topic",
+			pkt:  publishPkt(1, deniedTopic, 1, []byte{}, "payload"),
+			ack:  packet{header: pubackType << 4, body: []byte{0, 1, codeNotAuthorized}},
+		},
+		{
+			desc: "publish QoS 1 message exceeding the quota",
+			pkt:  publishPkt(1, limitedTopic, 2, []byte{}, "payload"),
+			ack:  packet{header: pubackType << 4, body: []byte{0, 2, codeQuotaExceeded}},
+		},
+	}
+	for _, tc := range cases {
+		err := tc.pkt.write(client)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error writing packet: %s", tc.desc, err))
+		assert.Equal(t, tc.ack, read(t, client), fmt.Sprintf("%s: unexpected acknowledgement", tc.desc))
+	}
+}
@@ -26,13 +26,15 @@ const (
 	thingCreate     = thingPrefix + "create"
 	thingUpdate     = thingPrefix + "update"
 	thingRemove     = thingPrefix + "remove"
+	thingRestore    = thingPrefix + "restore"
 	thingConnect    = thingPrefix + "connect"
 	thingDisconnect = thingPrefix + "disconnect"
 
-	channelPrefix = "channel."
-	channelCreate = channelPrefix + "create"
-	channelUpdate = channelPrefix + "update"
-	channelRemove = channelPrefix + "remove"
+	channelPrefix  = "channel."
+	channelCreate  = channelPrefix + "create"
+	channelUpdate  = channelPrefix + "update"
+	channelRemove  = channelPrefix + "remove"
+	channelRestore = channelPrefix + "restore"
 
 	exists = "BUSYGROUP Consumer Group name already exists"
 )
@@ -88,7 +90,7 @@ func (es eventStore) Subscribe(ctx context.Context, subject string) error {
 
 			var err error
 			switch event["operation"] {
-			case thingCreate:
+			case thingCreate, thingRestore:
 				cte, e := decodeCreateThing(event)
 				if e != nil {
 					err = e
@@ -105,7 +107,7 @@ func (es eventStore) Subscribe(ctx context.Context, subject string) error {
 			case thingRemove:
 				rte := decodeRemoveThing(event)
 				err = es.svc.RemoveThing(ctx, rte.id)
-			case channelCreate:
+			case channelCreate, channelRestore:
 				cce, e := decodeCreateChannel(event)
 				if e != nil {
 					err = e
@@ -0,0 +1,77 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package brokers creates the Publisher and PubSub of the message
+// broker the service is configured to use, so that the services don't
+// depend on the specific broker implementation.
+package brokers
+
+import (
+	"errors"
+
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/messaging/kafka"
+	"github.com/mainflux/mainflux/pkg/messaging/nats"
+)
+
+const (
+	// NATS is the default message broker.
+	NATS = "nats"
+	// Kafka is the Apache Kafka message broker.
+	Kafka = "kafka"
+
+	// SubjectAllChannels represents subject to subscribe for all the channels.
+	SubjectAllChannels = "channels.>"
+)
+
+// ErrUnsupportedBroker indicates that the configured message broker is not supported.
+var ErrUnsupportedBroker = errors.New("unsupported message broker")
+
+// Config contains the message broker type and the brokers URLs.
+type Config struct {
+	Type     string
+	NatsURL  string
+	KafkaURL string
+}
+
+// Publisher wraps messaging Publisher exposing
+// Close() method for the broker connection.
+type Publisher interface {
+	messaging.Publisher
+	Close()
+}
+
+// PubSub wraps messaging PubSub exposing
+// Close() method for the broker connection.
+type PubSub interface {
+	messaging.PubSub
+	Close()
+}
+
+// NewPublisher returns the Publisher of the configured message broker.
+func NewPublisher(cfg Config) (Publisher, error) {
+	switch cfg.Type {
+	case NATS:
+		return nats.NewPublisher(cfg.NatsURL)
+	case Kafka:
+		return kafka.NewPublisher(cfg.KafkaURL)
+	default:
+		return nil, ErrUnsupportedBroker
+	}
+}
+
+// NewPubSub returns the PubSub of the configured message broker.
+// Parameter queue has the same meaning for all the brokers: if it's
+// not empty, the messages are load-balanced between the subscribers
+// using the same queue, otherwise all of them receive every message.
+func NewPubSub(cfg Config, queue string, logger log.Logger) (PubSub, error) {
+	switch cfg.Type {
+	case NATS:
+		return nats.NewPubSub(cfg.NatsURL, queue, logger)
+	case Kafka:
+		return kafka.NewPubSub(cfg.KafkaURL, queue, logger)
+	default:
+		return nil, ErrUnsupportedBroker
+	}
+}
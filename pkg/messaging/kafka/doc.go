@@ -0,0 +1,12 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package kafka holds the implementation of the Publisher and PubSub
+// interfaces for the Apache Kafka messaging system. Messages published
+// to the channel are stored in the Kafka topic named after the channel
+// subject (channels.<channel_id>), using the subtopic as the record key,
+// so the messages of the same subtopic keep their order. Subscriptions
+// accept NATS subjects, including the wildcards, and are mapped to
+// the matching Kafka topics, while the subtopic is matched against
+// the record key.
+package kafka
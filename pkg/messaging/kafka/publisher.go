@@ -0,0 +1,86 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const chansPrefix = "channels"
+
+var _ messaging.Publisher = (*publisher)(nil)
+
+// client represents the subset of the Kafka client API used by
+// the publisher and the subscriptions.
+type client interface {
+	ProduceSync(ctx context.Context, rs ...*kgo.Record) kgo.ProduceResults
+	PollFetches(ctx context.Context) kgo.Fetches
+	Close()
+}
+
+type publisher struct {
+	client client
+}
+
+// Publisher wraps messaging Publisher exposing
+// Close() method for Kafka connection.
+type Publisher interface {
+	messaging.Publisher
+	Close()
+}
+
+// NewPublisher returns Kafka message Publisher. Parameter url is
+// a comma separated list of the Kafka brokers addresses.
+func NewPublisher(url string) (Publisher, error) {
+	cl, err := connect(url, kgo.AllowAutoTopicCreation())
+	if err != nil {
+		return nil, err
+	}
+	ret := &publisher{
+		client: cl,
+	}
+	return ret, nil
+}
+
+func (pub *publisher) Publish(topic string, msg messaging.Message) error {
+	data, err := proto.Marshal(&msg)
+	if err != nil {
+		return err
+	}
+
+	record := &kgo.Record{
+		Topic: fmt.Sprintf("%s.%s", chansPrefix, topic),
+		Key:   []byte(msg.Subtopic),
+		Value: data,
+	}
+	if err := pub.client.ProduceSync(context.Background(), record).FirstErr(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (pub *publisher) Close() {
+	pub.client.Close()
+}
+
+// connect creates the Kafka client and checks that the brokers are reachable.
+func connect(url string, opts ...kgo.Opt) (*kgo.Client, error) {
+	opts = append(opts, kgo.SeedBrokers(strings.Split(url, ",")...))
+	cl, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := cl.Ping(context.Background()); err != nil {
+		cl.Close()
+		return nil, err
+	}
+	return cl, nil
+}
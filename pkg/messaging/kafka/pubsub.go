@@ -0,0 +1,228 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// SubjectAllChannels represents subject to subscribe for all the channels.
+const SubjectAllChannels = "channels.>"
+
+// metadataMaxAge is the period after which the Kafka topics created
+// in the meantime are picked up by the wildcard subscriptions.
+const metadataMaxAge = 10 * time.Second
+
+var (
+	errAlreadySubscribed = errors.New("already subscribed to topic")
+	errNotSubscribed     = errors.New("not subscribed")
+	errEmptyTopic        = errors.New("empty topic")
+	errInvalidTopic      = errors.New("topic is not a channel subject")
+)
+
+var _ messaging.PubSub = (*pubsub)(nil)
+
+// PubSub wraps messaging Publisher exposing
+// Close() method for Kafka connection.
+type PubSub interface {
+	messaging.PubSub
+	Close()
+}
+
+type subscription struct {
+	client client
+	cancel context.CancelFunc
+}
+
+type pubsub struct {
+	publisher
+	url           string
+	queue         string
+	logger        log.Logger
+	mu            sync.Mutex
+	subscriptions map[string]subscription
+	// newConsumer creates the client consuming Kafka topics matching
+	// the subscription topic, as a member of the group if it's not empty.
+	newConsumer func(topic, group string) (client, error)
+}
+
+// NewPubSub returns Kafka message publisher/subscriber. Parameter url is
+// a comma separated list of the Kafka brokers addresses. Parameter queue
+// is the equivalent of the NATS queue: if it's not empty, subscribers
+// using the same queue join the same consumer group and the messages are
+// load-balanced between them. Otherwise, every subscriber receives all
+// the messages published after it subscribed.
+func NewPubSub(url, queue string, logger log.Logger) (PubSub, error) {
+	cl, err := connect(url, kgo.AllowAutoTopicCreation())
+	if err != nil {
+		return nil, err
+	}
+	ret := &pubsub{
+		publisher:     publisher{client: cl},
+		url:           url,
+		queue:         queue,
+		logger:        logger,
+		subscriptions: make(map[string]subscription),
+	}
+	ret.newConsumer = ret.connectConsumer
+	return ret, nil
+}
+
+func (ps *pubsub) Subscribe(topic string, handler messaging.MessageHandler) error {
+	if topic == "" {
+		return errEmptyTopic
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if _, ok := ps.subscriptions[topic]; ok {
+		return errAlreadySubscribed
+	}
+
+	group := ""
+	if ps.queue != "" {
+		group = fmt.Sprintf("%s-%s", ps.queue, topic)
+	}
+	cl, err := ps.newConsumer(topic, group)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go ps.consume(ctx, topic, cl, handler)
+	ps.subscriptions[topic] = subscription{
+		client: cl,
+		cancel: cancel,
+	}
+
+	return nil
+}
+
+func (ps *pubsub) Unsubscribe(topic string) error {
+	if topic == "" {
+		return errEmptyTopic
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	sub, ok := ps.subscriptions[topic]
+	if !ok {
+		return errNotSubscribed
+	}
+	sub.cancel()
+	sub.client.Close()
+
+	delete(ps.subscriptions, topic)
+	return nil
+}
+
+func (ps *pubsub) Close() {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	for topic, sub := range ps.subscriptions {
+		sub.cancel()
+		sub.client.Close()
+		delete(ps.subscriptions, topic)
+	}
+	ps.client.Close()
+}
+
+func (ps *pubsub) connectConsumer(topic, group string) (client, error) {
+	topics, regex, err := kafkaTopics(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []kgo.Opt{
+		kgo.ConsumeTopics(topics...),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtEnd()),
+		kgo.MetadataMaxAge(metadataMaxAge),
+	}
+	if regex {
+		opts = append(opts, kgo.ConsumeRegex())
+	}
+	if group != "" {
+		opts = append(opts, kgo.ConsumerGroup(group))
+	}
+
+	return connect(ps.url, opts...)
+}
+
+func (ps *pubsub) consume(ctx context.Context, topic string, cl client, h messaging.MessageHandler) {
+	for {
+		fetches := cl.PollFetches(ctx)
+		if fetches.IsClientClosed() || ctx.Err() != nil {
+			return
+		}
+		fetches.EachError(func(t string, p int32, err error) {
+			ps.logger.Warn(fmt.Sprintf("Failed to fetch messages from topic %s partition %d: %s", t, p, err))
+		})
+		fetches.EachRecord(func(r *kgo.Record) {
+			// Kafka topic may contain the messages of the subtopics
+			// not matching the subscription, so they're filtered here.
+			if !matches(topic, subject(r)) {
+				return
+			}
+			var msg messaging.Message
+			if err := proto.Unmarshal(r.Value, &msg); err != nil {
+				ps.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+				return
+			}
+			if err := h(msg); err != nil {
+				ps.logger.Warn(fmt.Sprintf("Failed to handle Mainflux message: %s", err))
+			}
+		})
+	}
+}
+
+// kafkaTopics returns the Kafka topics to consume in order to receive
+// the messages published to the subjects matching the given one, and
+// whether the topics are regular expressions.
+func kafkaTopics(topic string) ([]string, bool, error) {
+	tokens := strings.SplitN(topic, ".", 3)
+	if len(tokens) < 2 || tokens[0] != chansPrefix || tokens[1] == "" {
+		return nil, false, errInvalidTopic
+	}
+
+	switch tokens[1] {
+	case "*", ">":
+		return []string{fmt.Sprintf(`^%s\..+$`, chansPrefix)}, true, nil
+	default:
+		return []string{fmt.Sprintf("%s.%s", chansPrefix, tokens[1])}, false, nil
+	}
+}
+
+// subject returns the NATS equivalent of the record subject.
+func subject(r *kgo.Record) string {
+	if len(r.Key) == 0 {
+		return r.Topic
+	}
+	return fmt.Sprintf("%s.%s", r.Topic, r.Key)
+}
+
+// matches reports whether the subject matches the subscription topic,
+// following the NATS wildcards semantics.
+func matches(topic, subject string) bool {
+	tt := strings.Split(topic, ".")
+	st := strings.Split(subject, ".")
+	for i, t := range tt {
+		if t == ">" {
+			return len(st) > i
+		}
+		if i >= len(st) || (t != "*" && t != st[i]) {
+			return false
+		}
+	}
+	return len(tt) == len(st)
+}
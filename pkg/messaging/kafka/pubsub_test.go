@@ -0,0 +1,265 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	topic    = "topic"
+	channel  = "9b7b1b3f-b1b0-46a8-a717-b8213f9eda3b"
+	subtopic = "engine"
+	timeout  = time.Second
+)
+
+var data = []byte("payload")
+
+func TestPubsub(t *testing.T) {
+	pubsub := newBroker().newPubSub("")
+	msgChan := make(chan messaging.Message, 1)
+	handler := func(msg messaging.Message) error {
+		msgChan <- msg
+		return nil
+	}
+
+	err := pubsub.Subscribe(fmt.Sprintf("%s.%s", chansPrefix, topic), handler)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = pubsub.Subscribe(fmt.Sprintf("%s.%s.%s", chansPrefix, topic, subtopic), handler)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		channel  string
+		subtopic string
+		payload  []byte
+	}{
+		{
+			desc:    "publish message with nil payload",
+			payload: nil,
+		},
+		{
+			desc:    "publish message with string payload",
+			payload: data,
+		},
+		{
+			desc:    "publish message with channel",
+			payload: data,
+			channel: channel,
+		},
+		{
+			desc:     "publish message with subtopic",
+			payload:  data,
+			subtopic: subtopic,
+		},
+		{
+			desc:     "publish message with channel and subtopic",
+			payload:  data,
+			channel:  channel,
+			subtopic: subtopic,
+		},
+	}
+
+	for _, tc := range cases {
+		expectedMsg := messaging.Message{
+			Channel:  tc.channel,
+			Subtopic: tc.subtopic,
+			Payload:  tc.payload,
+		}
+		err = pubsub.Publish(topic, expectedMsg)
+		require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+
+		receivedMsg := receive(t, msgChan)
+		assert.Equal(t, expectedMsg, receivedMsg, fmt.Sprintf("%s: expected %+v got %+v\n", tc.desc, expectedMsg, receivedMsg))
+		assertEmpty(t, msgChan, tc.desc)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	cases := []struct {
+		desc     string
+		topic    string
+		subtopic string
+		received bool
+		err      error
+	}{
+		{
+			desc:     "subscribe to all channels",
+			topic:    SubjectAllChannels,
+			subtopic: subtopic,
+			received: true,
+		},
+		{
+			desc:     "subscribe to any channel without subtopic",
+			topic:    fmt.Sprintf("%s.*", chansPrefix),
+			subtopic: subtopic,
+			received: false,
+		},
+		{
+			desc:     "subscribe to subtopic of any channel",
+			topic:    fmt.Sprintf("%s.*.%s", chansPrefix, subtopic),
+			subtopic: subtopic,
+			received: true,
+		},
+		{
+			desc:     "subscribe to all subtopics of the channel",
+			topic:    fmt.Sprintf("%s.%s.>", chansPrefix, channel),
+			subtopic: fmt.Sprintf("%s.temperature", subtopic),
+			received: true,
+		},
+		{
+			desc:     "subscribe to single token subtopics of the channel",
+			topic:    fmt.Sprintf("%s.%s.*", chansPrefix, channel),
+			subtopic: fmt.Sprintf("%s.temperature", subtopic),
+			received: false,
+		},
+		{
+			desc:     "subscribe to another channel",
+			topic:    fmt.Sprintf("%s.%s", chansPrefix, topic),
+			received: false,
+		},
+		{
+			desc:  "subscribe to empty topic",
+			topic: "",
+			err:   errEmptyTopic,
+		},
+		{
+			desc:  "subscribe to non-channel topic",
+			topic: "things.>",
+			err:   errInvalidTopic,
+		},
+	}
+
+	for _, tc := range cases {
+		pubsub := newBroker().newPubSub("")
+		msgChan := make(chan messaging.Message, 1)
+		err := pubsub.Subscribe(tc.topic, func(msg messaging.Message) error {
+			msgChan <- msg
+			return nil
+		})
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		expectedMsg := messaging.Message{
+			Channel:  channel,
+			Subtopic: tc.subtopic,
+			Payload:  data,
+		}
+		err = pubsub.Publish(channel, expectedMsg)
+		require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+
+		if !tc.received {
+			assertEmpty(t, msgChan, tc.desc)
+			continue
+		}
+		receivedMsg := receive(t, msgChan)
+		assert.Equal(t, expectedMsg, receivedMsg, fmt.Sprintf("%s: expected %+v got %+v\n", tc.desc, expectedMsg, receivedMsg))
+	}
+}
+
+func TestQueue(t *testing.T) {
+	cases := []struct {
+		desc     string
+		queue    string
+		expected int
+	}{
+		{
+			desc:     "publish to subscribers without queue",
+			queue:    "",
+			expected: 2,
+		},
+		{
+			desc:     "publish to subscribers in the same queue",
+			queue:    "writers",
+			expected: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		broker := newBroker()
+		msgChan := make(chan messaging.Message, 2)
+		for i := 0; i < 2; i++ {
+			err := broker.newPubSub(tc.queue).Subscribe(SubjectAllChannels, func(msg messaging.Message) error {
+				msgChan <- msg
+				return nil
+			})
+			require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+		}
+
+		err := broker.newPubSub("").Publish(channel, messaging.Message{Channel: channel, Payload: data})
+		require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+
+		for i := 0; i < tc.expected; i++ {
+			receive(t, msgChan)
+		}
+		assertEmpty(t, msgChan, tc.desc)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	pubsub := newBroker().newPubSub("")
+	msgChan := make(chan messaging.Message, 1)
+	err := pubsub.Subscribe(SubjectAllChannels, func(msg messaging.Message) error {
+		msgChan <- msg
+		return nil
+	})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		topic string
+		err   error
+	}{
+		{
+			desc:  "unsubscribe from empty topic",
+			topic: "",
+			err:   errEmptyTopic,
+		},
+		{
+			desc:  "unsubscribe from subscribed topic",
+			topic: SubjectAllChannels,
+			err:   nil,
+		},
+		{
+			desc:  "unsubscribe from topic without subscription",
+			topic: SubjectAllChannels,
+			err:   errNotSubscribed,
+		},
+	}
+
+	for _, tc := range cases {
+		err := pubsub.Unsubscribe(tc.topic)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	err = pubsub.Publish(channel, messaging.Message{Channel: channel, Payload: data})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assertEmpty(t, msgChan, "publish after unsubscribe")
+}
+
+func receive(t *testing.T, ch chan messaging.Message) messaging.Message {
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(timeout):
+		require.Fail(t, "message not received")
+		return messaging.Message{}
+	}
+}
+
+func assertEmpty(t *testing.T, ch chan messaging.Message, desc string) {
+	select {
+	case msg := <-ch:
+		assert.Fail(t, fmt.Sprintf("%s: unexpected message received: %+v", desc, msg))
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package kafka
+
+import (
+	"context"
+	"regexp"
+	"sync"
+
+	"github.com/mainflux/mainflux/logger"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// broker is an in-process stand-in for the Kafka cluster. It keeps
+// the records per topic and the consumer groups offsets in memory.
+type broker struct {
+	mu      sync.Mutex
+	topics  map[string][]*kgo.Record
+	groups  map[string]map[string]int
+	updated chan struct{}
+}
+
+func newBroker() *broker {
+	return &broker{
+		topics:  make(map[string][]*kgo.Record),
+		groups:  make(map[string]map[string]int),
+		updated: make(chan struct{}),
+	}
+}
+
+// newPubSub returns PubSub connected to the broker stand-in.
+func (b *broker) newPubSub(queue string) PubSub {
+	ps := &pubsub{
+		publisher:     publisher{client: b.client("", "")},
+		queue:         queue,
+		logger:        logger.NewMock(),
+		subscriptions: make(map[string]subscription),
+	}
+	ps.newConsumer = func(topic, group string) (client, error) {
+		if _, _, err := kafkaTopics(topic); err != nil {
+			return nil, err
+		}
+		return b.client(topic, group), nil
+	}
+	return ps
+}
+
+func (b *broker) client(topic, group string) *fakeClient {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := &fakeClient{
+		broker:  b,
+		topic:   topic,
+		group:   group,
+		offsets: make(map[string]int),
+		closed:  make(chan struct{}),
+	}
+	if group != "" {
+		offsets, ok := b.groups[group]
+		if ok {
+			c.offsets = offsets
+			return c
+		}
+		b.groups[group] = c.offsets
+	}
+	// Consumption starts at the end of the existing topics.
+	for name, records := range b.topics {
+		c.offsets[name] = len(records)
+	}
+	return c
+}
+
+type fakeClient struct {
+	broker  *broker
+	topic   string
+	group   string
+	offsets map[string]int
+	closed  chan struct{}
+	once    sync.Once
+}
+
+func (c *fakeClient) ProduceSync(ctx context.Context, rs ...*kgo.Record) kgo.ProduceResults {
+	c.broker.mu.Lock()
+	defer c.broker.mu.Unlock()
+
+	var res kgo.ProduceResults
+	for _, r := range rs {
+		r.Offset = int64(len(c.broker.topics[r.Topic]))
+		c.broker.topics[r.Topic] = append(c.broker.topics[r.Topic], r)
+		res = append(res, kgo.ProduceResult{Record: r})
+	}
+	close(c.broker.updated)
+	c.broker.updated = make(chan struct{})
+
+	return res
+}
+
+func (c *fakeClient) PollFetches(ctx context.Context) kgo.Fetches {
+	for {
+		c.broker.mu.Lock()
+		var topics []kgo.FetchTopic
+		for name, records := range c.broker.topics {
+			if !c.consumes(name) || c.offsets[name] == len(records) {
+				continue
+			}
+			topics = append(topics, kgo.FetchTopic{
+				Topic:      name,
+				Partitions: []kgo.FetchPartition{{Records: records[c.offsets[name]:]}},
+			})
+			c.offsets[name] = len(records)
+		}
+		updated := c.broker.updated
+		c.broker.mu.Unlock()
+
+		if len(topics) > 0 {
+			return kgo.Fetches{{Topics: topics}}
+		}
+
+		select {
+		case <-updated:
+		case <-ctx.Done():
+			return fetchErr(ctx.Err())
+		case <-c.closed:
+			return fetchErr(kgo.ErrClientClosed)
+		}
+	}
+}
+
+func (c *fakeClient) Close() {
+	c.once.Do(func() { close(c.closed) })
+}
+
+func (c *fakeClient) consumes(name string) bool {
+	topics, regex, err := kafkaTopics(c.topic)
+	if err != nil {
+		return false
+	}
+	if regex {
+		return regexp.MustCompile(topics[0]).MatchString(name)
+	}
+	return topics[0] == name
+}
+
+func fetchErr(err error) kgo.Fetches {
+	return kgo.Fetches{{Topics: []kgo.FetchTopic{{Partitions: []kgo.FetchPartition{{Err: err}}}}}}
+}
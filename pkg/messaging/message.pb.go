@@ -24,15 +24,21 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 // Message represents a message emitted by the Mainflux adapters layer.
 type Message struct {
-	Channel              string   `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
-	Subtopic             string   `protobuf:"bytes,2,opt,name=subtopic,proto3" json:"subtopic,omitempty"`
-	Publisher            string   `protobuf:"bytes,3,opt,name=publisher,proto3" json:"publisher,omitempty"`
-	Protocol             string   `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
-	Payload              []byte   `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
-	Created              int64    `protobuf:"varint,6,opt,name=created,proto3" json:"created,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Channel     string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Subtopic    string `protobuf:"bytes,2,opt,name=subtopic,proto3" json:"subtopic,omitempty"`
+	Publisher   string `protobuf:"bytes,3,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	Protocol    string `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Payload     []byte `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
+	Created     int64  `protobuf:"varint,6,opt,name=created,proto3" json:"created,omitempty"`
+	ContentType string `protobuf:"bytes,7,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// MQTT 5 publish properties.
+	UserProperties       map[string]string `protobuf:"bytes,8,rep,name=user_properties,json=userProperties,proto3" json:"user_properties,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ResponseTopic        string            `protobuf:"bytes,9,opt,name=response_topic,json=responseTopic,proto3" json:"response_topic,omitempty"`
+	CorrelationData      []byte            `protobuf:"bytes,10,opt,name=correlation_data,json=correlationData,proto3" json:"correlation_data,omitempty"`
+	Expiry               uint32            `protobuf:"varint,11,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *Message) Reset()         { *m = Message{} }
@@ -110,26 +116,72 @@ func (m *Message) GetCreated() int64 {
 	return 0
 }
 
+func (m *Message) GetContentType() string {
+	if m != nil {
+		return m.ContentType
+	}
+	return ""
+}
+
+func (m *Message) GetUserProperties() map[string]string {
+	if m != nil {
+		return m.UserProperties
+	}
+	return nil
+}
+
+func (m *Message) GetResponseTopic() string {
+	if m != nil {
+		return m.ResponseTopic
+	}
+	return ""
+}
+
+func (m *Message) GetCorrelationData() []byte {
+	if m != nil {
+		return m.CorrelationData
+	}
+	return nil
+}
+
+func (m *Message) GetExpiry() uint32 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Message)(nil), "messaging.Message")
+	proto.RegisterMapType((map[string]string)(nil), "messaging.Message.UserPropertiesEntry")
 }
 
 func init() { proto.RegisterFile("pkg/messaging/message.proto", fileDescriptor_e5e29d24c44e4762) }
 
 var fileDescriptor_e5e29d24c44e4762 = []byte{
-	// 187 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0x2e, 0xc8, 0x4e, 0xd7,
-	0xcf, 0x4d, 0x2d, 0x2e, 0x4e, 0x4c, 0xcf, 0xcc, 0x83, 0xb1, 0x52, 0xf5, 0x0a, 0x8a, 0xf2, 0x4b,
-	0xf2, 0x85, 0x38, 0xe1, 0x12, 0x4a, 0x6b, 0x19, 0xb9, 0xd8, 0x7d, 0x21, 0x92, 0x42, 0x12, 0x5c,
-	0xec, 0xc9, 0x19, 0x89, 0x79, 0x79, 0xa9, 0x39, 0x12, 0x8c, 0x0a, 0x8c, 0x1a, 0x9c, 0x41, 0x30,
-	0xae, 0x90, 0x14, 0x17, 0x47, 0x71, 0x69, 0x52, 0x49, 0x7e, 0x41, 0x66, 0xb2, 0x04, 0x13, 0x58,
-	0x0a, 0xce, 0x17, 0x92, 0xe1, 0xe2, 0x2c, 0x28, 0x4d, 0xca, 0xc9, 0x2c, 0xce, 0x48, 0x2d, 0x92,
-	0x60, 0x06, 0x4b, 0x22, 0x04, 0x40, 0x3a, 0xc1, 0x76, 0x26, 0xe7, 0xe7, 0x48, 0xb0, 0x40, 0x74,
-	0xc2, 0xf8, 0x20, 0xfb, 0x0a, 0x12, 0x2b, 0x73, 0xf2, 0x13, 0x53, 0x24, 0x58, 0x15, 0x18, 0x35,
-	0x78, 0x82, 0x60, 0x5c, 0xb0, 0x4b, 0x8a, 0x52, 0x13, 0x4b, 0x52, 0x53, 0x24, 0xd8, 0x14, 0x18,
-	0x35, 0x98, 0x83, 0x60, 0x5c, 0x27, 0x81, 0x13, 0x8f, 0xe4, 0x18, 0x2f, 0x3c, 0x92, 0x63, 0x7c,
-	0xf0, 0x48, 0x8e, 0x71, 0xc6, 0x63, 0x39, 0x86, 0x24, 0x36, 0xb0, 0x79, 0xc6, 0x80, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0xa9, 0x1e, 0x4a, 0xea, 0xf2, 0x00, 0x00, 0x00,
+	// 349 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xcb, 0x4a, 0xec, 0x30,
+	0x18, 0xc7, 0x4f, 0xa6, 0x67, 0x2e, 0xcd, 0x5c, 0xc9, 0x39, 0x1c, 0xc2, 0x1c, 0x29, 0x55, 0x50,
+	0xea, 0xa6, 0x82, 0x6e, 0xc4, 0x9d, 0xa2, 0x4b, 0x51, 0xca, 0xb8, 0x2e, 0x99, 0xce, 0xc7, 0x4c,
+	0x99, 0x9a, 0x84, 0x24, 0x15, 0xfb, 0x26, 0x3e, 0x92, 0x2b, 0xf1, 0x11, 0x64, 0x7c, 0x11, 0x99,
+	0xf4, 0xa2, 0x82, 0xbb, 0xef, 0xf7, 0xff, 0xe7, 0xcb, 0x77, 0xc3, 0xff, 0xe5, 0x7a, 0x79, 0x74,
+	0x0f, 0x5a, 0xb3, 0x65, 0xca, 0xeb, 0x08, 0x42, 0xa9, 0x84, 0x11, 0xc4, 0x6d, 0x8c, 0xbd, 0x17,
+	0x07, 0x77, 0xaf, 0x4b, 0x93, 0x50, 0xdc, 0x4d, 0x56, 0x8c, 0x73, 0xc8, 0x28, 0xf2, 0x51, 0xe0,
+	0x46, 0x35, 0x92, 0x29, 0xee, 0xe9, 0x7c, 0x6e, 0x84, 0x4c, 0x13, 0xda, 0xb2, 0x56, 0xc3, 0x64,
+	0x07, 0xbb, 0x32, 0x9f, 0x67, 0xa9, 0x5e, 0x81, 0xa2, 0x8e, 0x35, 0x3f, 0x85, 0x6d, 0xa6, 0xad,
+	0x99, 0x88, 0x8c, 0xfe, 0x2e, 0x33, 0x6b, 0xde, 0xd6, 0x93, 0xac, 0xc8, 0x04, 0x5b, 0xd0, 0xb6,
+	0x8f, 0x82, 0x41, 0x54, 0xa3, 0xed, 0x44, 0x01, 0x33, 0xb0, 0xa0, 0x1d, 0x1f, 0x05, 0x4e, 0x54,
+	0x23, 0xd9, 0xc5, 0x83, 0x44, 0x70, 0x03, 0xdc, 0xc4, 0xa6, 0x90, 0x40, 0xbb, 0xf6, 0xcf, 0x7e,
+	0xa5, 0xcd, 0x0a, 0x09, 0xe4, 0x06, 0x8f, 0x73, 0x0d, 0x2a, 0x96, 0x4a, 0x48, 0x50, 0x26, 0x05,
+	0x4d, 0x7b, 0xbe, 0x13, 0xf4, 0x8f, 0x0f, 0xc2, 0x66, 0xee, 0xb0, 0x9a, 0x39, 0xbc, 0xd3, 0xa0,
+	0x6e, 0x9b, 0x87, 0x57, 0xdc, 0xa8, 0x22, 0x1a, 0xe5, 0xdf, 0x44, 0xb2, 0x8f, 0x47, 0x0a, 0xb4,
+	0x14, 0x5c, 0x43, 0x5c, 0xee, 0xc0, 0xb5, 0x55, 0x87, 0xb5, 0x3a, 0xb3, 0x8b, 0x38, 0xc4, 0x93,
+	0x44, 0x28, 0x05, 0x19, 0x33, 0xa9, 0xe0, 0xf1, 0x82, 0x19, 0x46, 0xb1, 0x9d, 0x6b, 0xfc, 0x45,
+	0xbf, 0x64, 0x86, 0x91, 0x7f, 0xb8, 0x03, 0x8f, 0x32, 0x55, 0x05, 0xed, 0xfb, 0x28, 0x18, 0x46,
+	0x15, 0x4d, 0xcf, 0xf1, 0x9f, 0x1f, 0x1a, 0x22, 0x13, 0xec, 0xac, 0xa1, 0xa8, 0x8e, 0xb2, 0x0d,
+	0xc9, 0x5f, 0xdc, 0x7e, 0x60, 0x59, 0x0e, 0xd5, 0x35, 0x4a, 0x38, 0x6b, 0x9d, 0xa2, 0x8b, 0xc9,
+	0xf3, 0xc6, 0x43, 0xaf, 0x1b, 0x0f, 0xbd, 0x6d, 0x3c, 0xf4, 0xf4, 0xee, 0xfd, 0x9a, 0x77, 0xec,
+	0xc2, 0x4f, 0x3e, 0x06, 0x00, 0x7a, 0x76, 0x62, 0x4f, 0x13, 0x02, 0x00, 0x00,
 }
 
 func (m *Message) Marshal() (dAtA []byte, err error) {
@@ -156,6 +208,51 @@ func (m *Message) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Expiry != 0 {
+		i = encodeVarintMessage(dAtA, i, uint64(m.Expiry))
+		i--
+		dAtA[i] = 0x58
+	}
+	if len(m.CorrelationData) > 0 {
+		i -= len(m.CorrelationData)
+		copy(dAtA[i:], m.CorrelationData)
+		i = encodeVarintMessage(dAtA, i, uint64(len(m.CorrelationData)))
+		i--
+		dAtA[i] = 0x52
+	}
+	if len(m.ResponseTopic) > 0 {
+		i -= len(m.ResponseTopic)
+		copy(dAtA[i:], m.ResponseTopic)
+		i = encodeVarintMessage(dAtA, i, uint64(len(m.ResponseTopic)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.UserProperties) > 0 {
+		for k := range m.UserProperties {
+			v := m.UserProperties[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintMessage(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintMessage(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintMessage(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if len(m.ContentType) > 0 {
+		i -= len(m.ContentType)
+		copy(dAtA[i:], m.ContentType)
+		i = encodeVarintMessage(dAtA, i, uint64(len(m.ContentType)))
+		i--
+		dAtA[i] = 0x3a
+	}
 	if m.Created != 0 {
 		i = encodeVarintMessage(dAtA, i, uint64(m.Created))
 		i--
@@ -239,6 +336,29 @@ func (m *Message) Size() (n int) {
 	if m.Created != 0 {
 		n += 1 + sovMessage(uint64(m.Created))
 	}
+	l = len(m.ContentType)
+	if l > 0 {
+		n += 1 + l + sovMessage(uint64(l))
+	}
+	if len(m.UserProperties) > 0 {
+		for k, v := range m.UserProperties {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovMessage(uint64(len(k))) + 1 + len(v) + sovMessage(uint64(len(v)))
+			n += mapEntrySize + 1 + sovMessage(uint64(mapEntrySize))
+		}
+	}
+	l = len(m.ResponseTopic)
+	if l > 0 {
+		n += 1 + l + sovMessage(uint64(l))
+	}
+	l = len(m.CorrelationData)
+	if l > 0 {
+		n += 1 + l + sovMessage(uint64(l))
+	}
+	if m.Expiry != 0 {
+		n += 1 + sovMessage(uint64(m.Expiry))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -461,16 +581,257 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContentType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ContentType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UserProperties", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.UserProperties == nil {
+				m.UserProperties = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMessage
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMessage
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthMessage
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthMessage
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMessage
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthMessage
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthMessage
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipMessage(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthMessage
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.UserProperties[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResponseTopic", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ResponseTopic = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CorrelationData", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CorrelationData = append(m.CorrelationData[:0], dAtA[iNdEx:postIndex]...)
+			if m.CorrelationData == nil {
+				m.CorrelationData = []byte{}
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Expiry", wireType)
+			}
+			m.Expiry = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Expiry |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMessage(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthMessage
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthMessage
 			}
 			if (iNdEx + skippy) > l {
@@ -0,0 +1,358 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	broker "github.com/nats-io/nats.go"
+)
+
+const (
+	// streamName is the name of the JetStream stream storing channel messages.
+	streamName = "mainflux"
+	// deadLetterStreamName is the name of the stream storing messages that could not be handled.
+	deadLetterStreamName = "mainflux-dead-letter"
+	// deadLetterMaxAge is the period the dead-lettered messages are kept for inspection.
+	deadLetterMaxAge = 7 * 24 * time.Hour
+	// ackWait is the time the server waits for the ack before it redelivers the message.
+	ackWait = 30 * time.Second
+	// maxBackoff is the longest redelivery delay if the number of delivery
+	// attempts is unlimited. It's kept below the ack wait, otherwise the
+	// server redelivers the message before the backoff expires.
+	maxBackoff = ackWait / 2
+	// defDeadLetter is the dead-letter subject prefix used if none is configured.
+	defDeadLetter = "deadletter"
+
+	// ErrorHeader is the dead-lettered message header containing the last handling error.
+	ErrorHeader = "Mainflux-Error"
+	// DeliveriesHeader is the dead-lettered message header containing the number of delivery attempts.
+	DeliveriesHeader = "Mainflux-Deliveries"
+)
+
+var errEmptyQueue = errors.New("durable consumer requires queue name")
+
+// JetStreamConfig contains redelivery parameters of the JetStream consumers.
+type JetStreamConfig struct {
+	// MaxDeliver is the number of delivery attempts after which
+	// the message is published to the dead-letter subject.
+	MaxDeliver int
+
+	// Backoff is the redelivery delay after the first handling failure.
+	// The delay doubles after every subsequent failure. If MaxDeliver is
+	// not set, the delay is capped, so that it stays below the ack wait.
+	Backoff time.Duration
+
+	// DeadLetter is the prefix of the subject the messages are published to
+	// after they fail to be handled. The rest of the subject is the original
+	// message subject, e.g. "deadletter.channels.<chan_id>.<subtopic>".
+	DeadLetter string
+
+	// MaxInFlight is the number of the messages handled concurrently by
+	// each subscription. The messages are handled one at a time if it's
+	// not set, otherwise the delivery waits while MaxInFlight messages
+	// are being handled.
+	MaxInFlight int
+}
+
+var _ messaging.PubSub = (*jsPubSub)(nil)
+
+type jsPubSub struct {
+	conn          *broker.Conn
+	js            broker.JetStreamContext
+	logger        log.Logger
+	mu            sync.Mutex
+	queue         string
+	cfg           JetStreamConfig
+	subscriptions map[string]*broker.Subscription
+}
+
+// NewJetStreamPubSub returns NATS JetStream message publisher/subscriber.
+// Unlike the PubSub returned by NewPubSub, messages are persisted in the stream
+// and delivered at least once: the message is acknowledged only after the
+// MessageHandler returns nil, otherwise it is redelivered with the exponential
+// backoff. Once the message has been delivered cfg.MaxDeliver times, it is
+// published to the dead-letter subject and is not redelivered anymore.
+// Parameter queue is mandatory and is used to name the durable consumers, so
+// the delivery continues where it stopped after the subscriber restarts. All
+// the subscribers using the same queue share the consumer and messages are
+// load-balanced between them.
+func NewJetStreamPubSub(url, queue string, cfg JetStreamConfig, logger log.Logger) (PubSub, error) {
+	if queue == "" {
+		return nil, errEmptyQueue
+	}
+	if cfg.DeadLetter == "" {
+		cfg.DeadLetter = defDeadLetter
+	}
+
+	conn, err := broker.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	streams := []*broker.StreamConfig{
+		{
+			Name:     streamName,
+			Subjects: []string{SubjectAllChannels},
+			// Messages are removed once all the consumers acknowledge them.
+			Retention: broker.InterestPolicy,
+			Storage:   broker.FileStorage,
+		},
+		{
+			Name:      deadLetterStreamName,
+			Subjects:  []string{fmt.Sprintf("%s.>", cfg.DeadLetter)},
+			Retention: broker.LimitsPolicy,
+			MaxAge:    deadLetterMaxAge,
+			Storage:   broker.FileStorage,
+		},
+	}
+	for _, sc := range streams {
+		if err := createStream(js, sc); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	ret := &jsPubSub{
+		conn:          conn,
+		js:            js,
+		queue:         queue,
+		cfg:           cfg,
+		logger:        logger,
+		subscriptions: make(map[string]*broker.Subscription),
+	}
+	return ret, nil
+}
+
+func (ps *jsPubSub) Publish(topic string, msg messaging.Message) error {
+	data, err := proto.Marshal(&msg)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("%s.%s", chansPrefix, topic)
+	if msg.Subtopic != "" {
+		subject = fmt.Sprintf("%s.%s", subject, msg.Subtopic)
+	}
+	if _, err := ps.js.Publish(subject, data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (ps *jsPubSub) Subscribe(topic string, handler messaging.MessageHandler) error {
+	if topic == "" {
+		return errEmptyTopic
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if _, ok := ps.subscriptions[topic]; ok {
+		return errAlreadySubscribed
+	}
+
+	// The consumer is created explicitly and then bound to, because
+	// the consumer created by the subscription is deleted together
+	// with the subscription, which would make it non-durable.
+	durable := durableName(ps.queue, topic)
+	if _, err := ps.js.ConsumerInfo(streamName, durable); err != nil {
+		if !errors.Is(err, broker.ErrConsumerNotFound) {
+			return err
+		}
+		cc := &broker.ConsumerConfig{
+			Durable:        durable,
+			DeliverSubject: broker.NewInbox(),
+			DeliverGroup:   ps.queue,
+			DeliverPolicy:  broker.DeliverAllPolicy,
+			AckPolicy:      broker.AckExplicitPolicy,
+			AckWait:        ps.ackWait(),
+			FilterSubject:  topic,
+		}
+		if _, err := ps.js.AddConsumer(streamName, cc); err != nil {
+			return err
+		}
+	}
+
+	sub, err := ps.js.QueueSubscribe(topic, ps.queue, ps.jsHandler(handler), broker.Bind(streamName, durable), broker.ManualAck())
+	if err != nil {
+		return err
+	}
+	ps.subscriptions[topic] = sub
+
+	return nil
+}
+
+func (ps *jsPubSub) Unsubscribe(topic string) error {
+	if topic == "" {
+		return errEmptyTopic
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	sub, ok := ps.subscriptions[topic]
+	if !ok {
+		return errNotSubscribed
+	}
+
+	// The durable consumer outlives the subscription, so
+	// the delivery continues on the next subscription.
+	if err := sub.Unsubscribe(); err != nil {
+		return err
+	}
+
+	delete(ps.subscriptions, topic)
+	return nil
+}
+
+func (ps *jsPubSub) Close() {
+	ps.conn.Close()
+}
+
+func (ps *jsPubSub) jsHandler(h messaging.MessageHandler) broker.MsgHandler {
+	handle := ps.handle(h)
+	if ps.cfg.MaxInFlight <= 1 {
+		return handle
+	}
+
+	inFlight := make(chan struct{}, ps.cfg.MaxInFlight)
+	return func(m *broker.Msg) {
+		inFlight <- struct{}{}
+		go func() {
+			defer func() { <-inFlight }()
+			handle(m)
+		}()
+	}
+}
+
+// handle acknowledges the message once it's handled, or schedules its redelivery.
+func (ps *jsPubSub) handle(h messaging.MessageHandler) broker.MsgHandler {
+	return func(m *broker.Msg) {
+		var msg messaging.Message
+		if err := proto.Unmarshal(m.Data, &msg); err != nil {
+			ps.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+			// Malformed message can't be handled no matter how many times it's redelivered.
+			ps.deadLetter(m, err)
+			return
+		}
+
+		if err := h(msg); err != nil {
+			ps.logger.Warn(fmt.Sprintf("Failed to handle Mainflux message: %s", err))
+			ps.retry(m, err)
+			return
+		}
+
+		if err := m.Ack(); err != nil {
+			ps.logger.Warn(fmt.Sprintf("Failed to acknowledge message: %s", err))
+		}
+	}
+}
+
+// retry schedules the message redelivery, or moves it to the dead-letter
+// subject if the maximum number of delivery attempts is reached.
+func (ps *jsPubSub) retry(m *broker.Msg, err error) {
+	md, mdErr := m.Metadata()
+	if mdErr != nil {
+		ps.logger.Warn(fmt.Sprintf("Failed to read message metadata: %s", mdErr))
+		return
+	}
+
+	if ps.cfg.MaxDeliver > 0 && md.NumDelivered >= uint64(ps.cfg.MaxDeliver) {
+		ps.deadLetter(m, err)
+		return
+	}
+
+	// Message is negatively acknowledged after the backoff, so that the server
+	// redelivers it immediately. Until then, the message counts as in progress.
+	time.AfterFunc(ps.backoff(md.NumDelivered), func() {
+		if err := m.Nak(); err != nil {
+			ps.logger.Warn(fmt.Sprintf("Failed to negatively acknowledge message: %s", err))
+		}
+	})
+}
+
+// deadLetter publishes the message to the dead-letter subject and terminates its delivery.
+func (ps *jsPubSub) deadLetter(m *broker.Msg, err error) {
+	dl := broker.NewMsg(fmt.Sprintf("%s.%s", ps.cfg.DeadLetter, m.Subject))
+	dl.Data = m.Data
+	dl.Header.Set(ErrorHeader, err.Error())
+	if md, err := m.Metadata(); err == nil {
+		dl.Header.Set(DeliveriesHeader, strconv.FormatUint(md.NumDelivered, 10))
+	}
+
+	if _, err := ps.js.PublishMsg(dl); err != nil {
+		// Message is left unacknowledged, so it's redelivered after the ack wait.
+		ps.logger.Error(fmt.Sprintf("Failed to publish message to dead-letter subject: %s", err))
+		return
+	}
+
+	if err := m.Term(); err != nil {
+		ps.logger.Warn(fmt.Sprintf("Failed to terminate message delivery: %s", err))
+	}
+}
+
+// ackWait returns the ack wait long enough not to
+// redeliver the message while it's being backed off.
+func (ps *jsPubSub) ackWait() time.Duration {
+	if ps.cfg.MaxDeliver <= 0 {
+		return ackWait
+	}
+	return ackWait + backoff(ps.cfg.Backoff, uint64(ps.cfg.MaxDeliver))
+}
+
+// backoff returns the redelivery delay after the given number of
+// deliveries, capped to fit in the ack wait if the deliveries are unlimited.
+func (ps *jsPubSub) backoff(delivered uint64) time.Duration {
+	d := backoff(ps.cfg.Backoff, delivered)
+	if ps.cfg.MaxDeliver <= 0 && d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
+
+// backoff returns the redelivery delay after the given number of deliveries.
+func backoff(base time.Duration, delivered uint64) time.Duration {
+	if delivered == 0 {
+		return base
+	}
+	// Limit the exponent to prevent the duration overflow.
+	exp := delivered - 1
+	if exp > 16 {
+		exp = 16
+	}
+	return base * time.Duration(1<<exp)
+}
+
+func createStream(js broker.JetStreamContext, cfg *broker.StreamConfig) error {
+	_, err := js.StreamInfo(cfg.Name)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, broker.ErrStreamNotFound):
+		_, err = js.AddStream(cfg)
+		return err
+	default:
+		return err
+	}
+}
+
+// durableName creates the consumer name from queue and topic, since
+// consumer names must not contain subject tokens separators and wildcards.
+func durableName(queue, topic string) string {
+	r := strings.NewReplacer(".", "_", "*", "any", ">", "all")
+	return fmt.Sprintf("%s-%s", r.Replace(queue), r.Replace(topic))
+}
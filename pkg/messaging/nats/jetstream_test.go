@@ -0,0 +1,204 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package nats_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	jsTopic    = "jetstream"
+	receiveTTL = 5 * time.Second
+)
+
+var errHandle = errors.New("failed to handle message")
+
+func TestJetStreamPubSub(t *testing.T) {
+	received := make(chan messaging.Message, 1)
+	topic := fmt.Sprintf("%s.%s.%s", chansPrefix, jsTopic, subtopic)
+	err := jsPubsub.Subscribe(topic, func(msg messaging.Message) error {
+		received <- msg
+		return nil
+	})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		subtopic string
+		payload  []byte
+	}{
+		{
+			desc:     "publish message with string payload",
+			subtopic: subtopic,
+			payload:  data,
+		},
+		{
+			desc:     "publish message with nil payload",
+			subtopic: subtopic,
+			payload:  nil,
+		},
+	}
+
+	for _, tc := range cases {
+		expectedMsg := messaging.Message{
+			Channel:  jsTopic,
+			Subtopic: tc.subtopic,
+			Payload:  tc.payload,
+		}
+		err = jsPubsub.Publish(jsTopic, expectedMsg)
+		require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+
+		receivedMsg := receive(t, received)
+		assert.Equal(t, expectedMsg, receivedMsg, fmt.Sprintf("%s: expected %+v got %+v\n", tc.desc, expectedMsg, receivedMsg))
+	}
+
+	// Messages published while unsubscribed are delivered on the next subscription.
+	err = jsPubsub.Unsubscribe(topic)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	expectedMsg := messaging.Message{
+		Channel:  jsTopic,
+		Subtopic: subtopic,
+		Payload:  data,
+	}
+	err = jsPubsub.Publish(jsTopic, expectedMsg)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = jsPubsub.Subscribe(topic, func(msg messaging.Message) error {
+		received <- msg
+		return nil
+	})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	defer jsPubsub.Unsubscribe(topic)
+
+	receivedMsg := receive(t, received)
+	assert.Equal(t, expectedMsg, receivedMsg, fmt.Sprintf("expected message published while unsubscribed %+v got %+v\n", expectedMsg, receivedMsg))
+}
+
+func TestJetStreamRedelivery(t *testing.T) {
+	var mu sync.Mutex
+	deliveries := 0
+	received := make(chan messaging.Message, jsConfig.MaxDeliver)
+	topic := fmt.Sprintf("%s.%s.redelivery", chansPrefix, jsTopic)
+	err := jsPubsub.Subscribe(topic, func(msg messaging.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		deliveries++
+		received <- msg
+		// Fail all but the last delivery attempt.
+		if deliveries < jsConfig.MaxDeliver {
+			return errHandle
+		}
+		return nil
+	})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	defer jsPubsub.Unsubscribe(topic)
+
+	expectedMsg := messaging.Message{
+		Channel:  jsTopic,
+		Subtopic: "redelivery",
+		Payload:  data,
+	}
+	err = jsPubsub.Publish(jsTopic, expectedMsg)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	for i := 0; i < jsConfig.MaxDeliver; i++ {
+		receivedMsg := receive(t, received)
+		assert.Equal(t, expectedMsg, receivedMsg, fmt.Sprintf("delivery %d: expected %+v got %+v\n", i+1, expectedMsg, receivedMsg))
+	}
+
+	select {
+	case msg := <-received:
+		assert.Fail(t, fmt.Sprintf("acknowledged message redelivered: %+v", msg))
+	case <-time.After(time.Second):
+	}
+}
+
+func TestJetStreamMaxInFlight(t *testing.T) {
+	started := make(chan messaging.Message, 2)
+	release := make(chan struct{})
+	topic := fmt.Sprintf("%s.%s.concurrent", chansPrefix, jsTopic)
+	err := jsConcurrent.Subscribe(topic, func(msg messaging.Message) error {
+		started <- msg
+		<-release
+		return nil
+	})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	defer jsConcurrent.Unsubscribe(topic)
+	defer close(release)
+
+	expectedMsg := messaging.Message{
+		Channel:  jsTopic,
+		Subtopic: "concurrent",
+		Payload:  data,
+	}
+	for i := 0; i < 2; i++ {
+		err = jsConcurrent.Publish(jsTopic, expectedMsg)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	// Both messages are handled while none of them is acknowledged.
+	for i := 0; i < 2; i++ {
+		receivedMsg := receive(t, started)
+		assert.Equal(t, expectedMsg, receivedMsg, fmt.Sprintf("message %d: expected %+v got %+v\n", i+1, expectedMsg, receivedMsg))
+	}
+}
+
+func TestJetStreamDeadLetter(t *testing.T) {
+	deadLetters := make(chan messaging.Message, 1)
+	dlTopic := fmt.Sprintf("%s.%s.%s.deadletter", jsConfig.DeadLetter, chansPrefix, jsTopic)
+	err := pubsub.Subscribe(dlTopic, func(msg messaging.Message) error {
+		deadLetters <- msg
+		return nil
+	})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	defer pubsub.Unsubscribe(dlTopic)
+
+	var mu sync.Mutex
+	deliveries := 0
+	topic := fmt.Sprintf("%s.%s.deadletter", chansPrefix, jsTopic)
+	err = jsPubsub.Subscribe(topic, func(msg messaging.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		deliveries++
+		return errHandle
+	})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	defer jsPubsub.Unsubscribe(topic)
+
+	expectedMsg := messaging.Message{
+		Channel:  jsTopic,
+		Subtopic: "deadletter",
+		Payload:  data,
+	}
+	err = jsPubsub.Publish(jsTopic, expectedMsg)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	receivedMsg := receive(t, deadLetters)
+	assert.Equal(t, expectedMsg, receivedMsg, fmt.Sprintf("expected dead-lettered %+v got %+v\n", expectedMsg, receivedMsg))
+
+	// Dead-lettered message is not redelivered.
+	time.Sleep(time.Second)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, jsConfig.MaxDeliver, deliveries, fmt.Sprintf("expected %d deliveries got %d", jsConfig.MaxDeliver, deliveries))
+}
+
+func receive(t *testing.T, ch chan messaging.Message) messaging.Message {
+	select {
+	case msg := <-ch:
+		return msg
+	case <-time.After(receiveTTL):
+		require.Fail(t, "message not received")
+		return messaging.Message{}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"os/signal"
 	"syscall"
 	"testing"
+	"time"
 
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/pkg/messaging"
@@ -20,15 +21,28 @@ import (
 var (
 	publisher messaging.Publisher
 	pubsub    messaging.PubSub
+	jsPubsub  messaging.PubSub
+	// jsConcurrent handles jsConfig.MaxInFlight messages concurrently.
+	jsConcurrent messaging.PubSub
 )
 
+var jsConfig = nats.JetStreamConfig{
+	MaxDeliver: 3,
+	Backoff:    10 * time.Millisecond,
+	DeadLetter: "deadletter",
+}
+
 func TestMain(m *testing.M) {
 	pool, err := dockertest.NewPool("")
 	if err != nil {
 		log.Fatalf("Could not connect to docker: %s", err)
 	}
 
-	container, err := pool.Run("nats", "1.3.0", []string{})
+	container, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "nats",
+		Tag:        "2.2.4-alpine",
+		Cmd:        []string{"-js"},
+	})
 	if err != nil {
 		log.Fatalf("Could not start container: %s", err)
 	}
@@ -52,6 +66,21 @@ func TestMain(m *testing.M) {
 	}); err != nil {
 		log.Fatalf("Could not connect to docker: %s", err)
 	}
+	if err := pool.Retry(func() error {
+		jsPubsub, err = nats.NewJetStreamPubSub(address, "test", jsConfig, logger)
+		return err
+	}); err != nil {
+		log.Fatalf("Could not connect to docker: %s", err)
+	}
+
+	concurrentCfg := jsConfig
+	concurrentCfg.MaxInFlight = 2
+	if err := pool.Retry(func() error {
+		jsConcurrent, err = nats.NewJetStreamPubSub(address, "concurrent", concurrentCfg, logger)
+		return err
+	}); err != nil {
+		log.Fatalf("Could not connect to docker: %s", err)
+	}
 
 	code := m.Run()
 	if err := pool.Purge(container); err != nil {
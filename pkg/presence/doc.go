@@ -0,0 +1,7 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package presence contains the tracking of the things publishing over the
+// connectionless protocols, used by the things service to maintain the
+// thing presence.
+package presence
@@ -0,0 +1,24 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/pkg/presence"
+)
+
+var _ presence.Tracker = (*trackerMock)(nil)
+
+type trackerMock struct{}
+
+// NewTracker returns mock implementation of the tracker discarding the
+// recorded activity.
+func NewTracker() presence.Tracker {
+	return trackerMock{}
+}
+
+func (trackerMock) Seen(context.Context, string, string) error {
+	return nil
+}
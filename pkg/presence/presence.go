@@ -0,0 +1,34 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package presence
+
+import (
+	"context"
+	"net"
+)
+
+type clientIPKey struct{}
+
+// Tracker records the publish activity of the things.
+type Tracker interface {
+	// Seen records that the thing published the message over the given
+	// protocol. The client IP is read from the context, if present.
+	Seen(ctx context.Context, thingID, protocol string) error
+}
+
+// WithClientIP returns the context carrying the IP of the client the
+// request is received from. The port of the address, if any, is stripped.
+func WithClientIP(ctx context.Context, addr string) context.Context {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return context.WithValue(ctx, clientIPKey{}, addr)
+}
+
+// ClientIP returns the client IP carried by the context, or an empty string
+// if the context doesn't carry one.
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
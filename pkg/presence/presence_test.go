@@ -0,0 +1,47 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package presence_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/pkg/presence"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	cases := []struct {
+		desc string
+		ctx  context.Context
+		ip   string
+	}{
+		{
+			desc: "client IP of address with port",
+			ctx:  presence.WithClientIP(context.Background(), "192.168.0.1:5683"),
+			ip:   "192.168.0.1",
+		},
+		{
+			desc: "client IP of IPv6 address with port",
+			ctx:  presence.WithClientIP(context.Background(), "[::1]:8080"),
+			ip:   "::1",
+		},
+		{
+			desc: "client IP of address without port",
+			ctx:  presence.WithClientIP(context.Background(), "10.0.0.1"),
+			ip:   "10.0.0.1",
+		},
+		{
+			desc: "client IP of context without address",
+			ctx:  context.Background(),
+			ip:   "",
+		},
+	}
+
+	for _, tc := range cases {
+		ip := presence.ClientIP(tc.ctx)
+		assert.Equal(t, tc.ip, ip, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.ip, ip))
+	}
+}
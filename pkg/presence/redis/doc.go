@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redis contains the presence tracker implementation publishing
+// the thing activity to the Redis stream.
+package redis
@@ -0,0 +1,88 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mainflux/mainflux/pkg/presence"
+)
+
+const (
+	streamID  = "mainflux.presence"
+	streamLen = 1000
+	publish   = "publish"
+)
+
+var _ presence.Tracker = (*tracker)(nil)
+
+type tracker struct {
+	client   *redis.Client
+	interval time.Duration
+	mu       sync.Mutex
+	// seen contains the things that published in the current interval.
+	seen  map[string]bool
+	reset time.Time
+}
+
+// NewTracker returns the presence tracker publishing the thing activity to
+// the Redis stream. The activity of the thing is published at most once per
+// the given interval.
+func NewTracker(client *redis.Client, interval time.Duration) presence.Tracker {
+	return &tracker{
+		client:   client,
+		interval: interval,
+		seen:     make(map[string]bool),
+		reset:    time.Now(),
+	}
+}
+
+func (t *tracker) Seen(ctx context.Context, thingID, protocol string) error {
+	now := time.Now()
+	if !t.first(thingID, now) {
+		return nil
+	}
+
+	record := &redis.XAddArgs{
+		Stream:       streamID,
+		MaxLenApprox: streamLen,
+		Values: map[string]interface{}{
+			"thing_id":   thingID,
+			"protocol":   protocol,
+			"client_ip":  presence.ClientIP(ctx),
+			"timestamp":  strconv.FormatInt(now.Unix(), 10),
+			"event_type": publish,
+		},
+	}
+	if err := t.client.XAdd(ctx, record).Err(); err != nil {
+		t.mu.Lock()
+		delete(t.seen, thingID)
+		t.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// first reports whether the thing is seen for the first time in the current
+// interval. The seen things are forgotten when the interval elapses, so that
+// the memory doesn't grow with the number of the things seen over time.
+func (t *tracker) first(thingID string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Sub(t.reset) >= t.interval {
+		t.seen = make(map[string]bool)
+		t.reset = now
+	}
+	if t.seen[thingID] {
+		return false
+	}
+	t.seen[thingID] = true
+	return true
+}
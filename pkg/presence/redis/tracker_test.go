@@ -0,0 +1,67 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/presence"
+	"github.com/mainflux/mainflux/pkg/presence/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	streamID = "mainflux.presence"
+	thingID  = "5384fb1c-d0ae-4cbe-be52-c54223150fe0"
+	interval = 100 * time.Millisecond
+)
+
+func TestSeen(t *testing.T) {
+	err := redisClient.FlushAll(context.Background()).Err()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	tracker := redis.NewTracker(redisClient, interval)
+	ctx := presence.WithClientIP(context.Background(), "10.0.0.1:1234")
+
+	cases := []struct {
+		desc   string
+		sleep  time.Duration
+		events int64
+	}{
+		{
+			desc:   "record first activity of the thing",
+			events: 1,
+		},
+		{
+			desc:   "record activity of the thing within interval",
+			events: 1,
+		},
+		{
+			desc:   "record activity of the thing after interval",
+			sleep:  interval,
+			events: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		time.Sleep(tc.sleep)
+		err := tracker.Seen(ctx, thingID, "http")
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		n, err := redisClient.XLen(context.Background(), streamID).Result()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.events, n, fmt.Sprintf("%s: expected %d events got %d", tc.desc, tc.events, n))
+	}
+
+	msgs, err := redisClient.XRange(context.Background(), streamID, "-", "+").Result()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	event := msgs[0].Values
+	assert.Equal(t, thingID, event["thing_id"], "unexpected thing ID")
+	assert.Equal(t, "http", event["protocol"], "unexpected protocol")
+	assert.Equal(t, "10.0.0.1", event["client_ip"], "unexpected client IP")
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package quota contains the enforcement of the per-thing and per-owner
+// message-rate and payload-size quotas used by the protocol adapters.
+package quota
@@ -0,0 +1,50 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/pkg/quota"
+)
+
+var _ quota.Limiter = (*limiterMock)(nil)
+
+type limiterMock struct {
+	mu     sync.Mutex
+	limits map[string]quota.Limits
+	things map[string]uint64
+	owners map[string]uint64
+}
+
+// NewLimiter returns mock implementation of the limiter enforcing the given
+// limits of the things. Messages are counted without time windows.
+func NewLimiter(limits map[string]quota.Limits) quota.Limiter {
+	return &limiterMock{
+		limits: limits,
+		things: make(map[string]uint64),
+		owners: make(map[string]uint64),
+	}
+}
+
+func (lm *limiterMock) Allow(_ context.Context, thingID string, size int) error {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	l := lm.limits[thingID]
+	if max := l.PayloadSize(); max > 0 && uint64(size) > max {
+		return quota.ErrPayloadTooLarge
+	}
+	if l.ThingMessages > 0 && lm.things[thingID] >= l.ThingMessages {
+		return quota.ErrRateLimited
+	}
+	if l.OwnerMessages > 0 && lm.owners[l.Owner] >= l.OwnerMessages {
+		return quota.ErrRateLimited
+	}
+
+	lm.things[thingID]++
+	lm.owners[l.Owner]++
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package quota
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var (
+	// ErrRateLimited indicates that the thing or its owner exceeded the
+	// message-rate quota.
+	ErrRateLimited = errors.New("message rate quota exceeded")
+
+	// ErrPayloadTooLarge indicates that the message payload exceeds the
+	// payload-size quota.
+	ErrPayloadTooLarge = errors.New("payload size quota exceeded")
+
+	// ErrRetrieveQuota indicates failure to retrieve the thing quota.
+	ErrRetrieveQuota = errors.New("failed to retrieve quota")
+)
+
+// Limiter enforces the quotas of the things publishing messages.
+type Limiter interface {
+	// Allow accounts the message of the given payload size published by the
+	// thing and returns an error if the message exceeds any of the thing or
+	// owner quotas.
+	Allow(ctx context.Context, thingID string, size int) error
+}
+
+// Limits contains the quotas that apply to the thing: its own quota and the
+// quota shared by all the things of its owner. Zero value means no limit.
+type Limits struct {
+	Owner            string
+	ThingMessages    uint64
+	ThingPayloadSize uint64
+	OwnerMessages    uint64
+	OwnerPayloadSize uint64
+}
+
+// PayloadSize returns the effective payload-size limit, which is the lower of
+// the thing and owner limits that are set.
+func (l Limits) PayloadSize() uint64 {
+	switch {
+	case l.ThingPayloadSize == 0:
+		return l.OwnerPayloadSize
+	case l.OwnerPayloadSize == 0 || l.ThingPayloadSize < l.OwnerPayloadSize:
+		return l.ThingPayloadSize
+	default:
+		return l.OwnerPayloadSize
+	}
+}
+
+var _ Limiter = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	limiter   Limiter
+	throttled metrics.Counter
+}
+
+// MetricsMiddleware counts the messages rejected by the limiter, labeled by
+// the reason of the rejection.
+func MetricsMiddleware(limiter Limiter, throttled metrics.Counter) Limiter {
+	return &metricsMiddleware{
+		limiter:   limiter,
+		throttled: throttled,
+	}
+}
+
+func (mm *metricsMiddleware) Allow(ctx context.Context, thingID string, size int) error {
+	err := mm.limiter.Allow(ctx, thingID, size)
+	switch {
+	case errors.Contains(err, ErrRateLimited):
+		mm.throttled.With("reason", "rate").Add(1)
+	case errors.Contains(err, ErrPayloadTooLarge):
+		mm.throttled.With("reason", "payload_size").Add(1)
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package quota_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/pkg/quota"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPayloadSize(t *testing.T) {
+	cases := []struct {
+		desc   string
+		limits quota.Limits
+		size   uint64
+	}{
+		{
+			desc:   "no limits",
+			limits: quota.Limits{},
+			size:   0,
+		},
+		{
+			desc:   "thing limit only",
+			limits: quota.Limits{ThingPayloadSize: 10},
+			size:   10,
+		},
+		{
+			desc:   "owner limit only",
+			limits: quota.Limits{OwnerPayloadSize: 20},
+			size:   20,
+		},
+		{
+			desc:   "lower thing limit",
+			limits: quota.Limits{ThingPayloadSize: 10, OwnerPayloadSize: 20},
+			size:   10,
+		},
+		{
+			desc:   "lower owner limit",
+			limits: quota.Limits{ThingPayloadSize: 30, OwnerPayloadSize: 20},
+			size:   20,
+		},
+	}
+
+	for _, tc := range cases {
+		size := tc.limits.PayloadSize()
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, size))
+	}
+}
@@ -0,0 +1,7 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redis contains the quota limiter implementation keeping the cached
+// quotas and the message counters in Redis, so that the quotas are shared by
+// all the adapter instances.
+package redis
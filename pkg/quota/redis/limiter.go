@@ -0,0 +1,158 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/quota"
+)
+
+const (
+	limitsPrefix   = "quota:limits"
+	thingPrefix    = "quota:thing"
+	ownerPrefix    = "quota:owner"
+	window         = time.Minute
+	ownerField     = "owner"
+	thingMsgField  = "thing_messages"
+	thingSizeField = "thing_payload_size"
+	ownerMsgField  = "owner_messages"
+	ownerSizeField = "owner_payload_size"
+)
+
+// count checks the thing (KEYS[1]) and owner (KEYS[2]) counters of the
+// current window against their limits (ARGV[1] and ARGV[2]) and increments
+// them only if none of the limits is reached, so that the rejected messages
+// are not counted.
+var count = redis.NewScript(`
+local tl = tonumber(ARGV[1])
+local ol = tonumber(ARGV[2])
+if tl > 0 and tonumber(redis.call('GET', KEYS[1]) or '0') >= tl then
+	return 1
+end
+if ol > 0 and tonumber(redis.call('GET', KEYS[2]) or '0') >= ol then
+	return 1
+end
+if tl > 0 then
+	redis.call('INCR', KEYS[1])
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+if ol > 0 then
+	redis.call('INCR', KEYS[2])
+	redis.call('PEXPIRE', KEYS[2], ARGV[3])
+end
+return 0
+`)
+
+var _ quota.Limiter = (*limiter)(nil)
+
+type limiter struct {
+	client *redis.Client
+	things mainflux.ThingsServiceClient
+	ttl    time.Duration
+}
+
+// NewLimiter returns the limiter counting messages in fixed one-minute
+// windows. The quotas retrieved from the things service are cached for the
+// given period.
+func NewLimiter(client *redis.Client, things mainflux.ThingsServiceClient, ttl time.Duration) quota.Limiter {
+	return limiter{
+		client: client,
+		things: things,
+		ttl:    ttl,
+	}
+}
+
+func (l limiter) Allow(ctx context.Context, thingID string, size int) error {
+	lm, err := l.limits(ctx, thingID)
+	if err != nil {
+		return err
+	}
+
+	if max := lm.PayloadSize(); max > 0 && uint64(size) > max {
+		return quota.ErrPayloadTooLarge
+	}
+
+	if lm.ThingMessages == 0 && lm.OwnerMessages == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	w := now.Truncate(window).Unix()
+	keys := []string{
+		fmt.Sprintf("%s:%s:%d", thingPrefix, thingID, w),
+		fmt.Sprintf("%s:%s:%d", ownerPrefix, lm.Owner, w),
+	}
+	res, err := count.Run(ctx, l.client, keys, lm.ThingMessages, lm.OwnerMessages, window.Milliseconds()).Int()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+	if res != 0 {
+		return quota.ErrRateLimited
+	}
+
+	return nil
+}
+
+func (l limiter) limits(ctx context.Context, thingID string) (quota.Limits, error) {
+	key := fmt.Sprintf("%s:%s", limitsPrefix, thingID)
+
+	vals, err := l.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return quota.Limits{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	if len(vals) > 0 {
+		return toLimits(vals), nil
+	}
+
+	q, err := l.things.ViewQuota(ctx, &mainflux.ThingID{Value: thingID})
+	if err != nil {
+		return quota.Limits{}, errors.Wrap(quota.ErrRetrieveQuota, err)
+	}
+
+	lm := quota.Limits{
+		Owner:            q.GetOwner(),
+		ThingMessages:    q.GetThingMessages(),
+		ThingPayloadSize: q.GetThingPayloadSize(),
+		OwnerMessages:    q.GetOwnerMessages(),
+		OwnerPayloadSize: q.GetOwnerPayloadSize(),
+	}
+	_, err = l.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			ownerField, lm.Owner,
+			thingMsgField, lm.ThingMessages,
+			thingSizeField, lm.ThingPayloadSize,
+			ownerMsgField, lm.OwnerMessages,
+			ownerSizeField, lm.OwnerPayloadSize,
+		)
+		pipe.Expire(ctx, key, l.ttl)
+		return nil
+	})
+	if err != nil {
+		return quota.Limits{}, errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	return lm, nil
+}
+
+func toLimits(vals map[string]string) quota.Limits {
+	parse := func(field string) uint64 {
+		v, _ := strconv.ParseUint(vals[field], 10, 64)
+		return v
+	}
+
+	return quota.Limits{
+		Owner:            vals[ownerField],
+		ThingMessages:    parse(thingMsgField),
+		ThingPayloadSize: parse(thingSizeField),
+		OwnerMessages:    parse(ownerMsgField),
+		OwnerPayloadSize: parse(ownerSizeField),
+	}
+}
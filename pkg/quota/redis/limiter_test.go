@@ -0,0 +1,146 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/quota"
+	"github.com/mainflux/mainflux/pkg/quota/redis"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+const owner = "user@example.com"
+
+var _ mainflux.ThingsServiceClient = (*thingsClient)(nil)
+
+type thingsClient struct {
+	quotas map[string]*mainflux.Quota
+}
+
+func (tc thingsClient) CanAccessByKey(context.Context, *mainflux.AccessByKeyReq, ...grpc.CallOption) (*mainflux.ThingID, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) CanAccessByID(context.Context, *mainflux.AccessByIDReq, ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) IsChannelOwner(context.Context, *mainflux.ChannelOwnerReq, ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) IsChannelsOwner(context.Context, *mainflux.ChannelsOwnerReq, ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) Identify(context.Context, *mainflux.Token, ...grpc.CallOption) (*mainflux.ThingID, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) ViewThing(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.Thing, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) ViewChannel(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Channel, error) {
+	panic("not implemented")
+}
+
+func (tc thingsClient) ViewQuota(_ context.Context, req *mainflux.ThingID, _ ...grpc.CallOption) (*mainflux.Quota, error) {
+	q, ok := tc.quotas[req.GetValue()]
+	if !ok {
+		return nil, errors.ErrNotFound
+	}
+	return q, nil
+}
+
+func TestAllow(t *testing.T) {
+	things := thingsClient{quotas: map[string]*mainflux.Quota{
+		"limited":   {Owner: owner, ThingMessages: 2, ThingPayloadSize: 10},
+		"unlimited": {Owner: owner, OwnerPayloadSize: 20},
+		"shared-1":  {Owner: "shared@example.com", OwnerMessages: 2},
+		"shared-2":  {Owner: "shared@example.com", OwnerMessages: 2},
+	}}
+	limiter := redis.NewLimiter(redisClient, things, time.Minute)
+
+	cases := []struct {
+		desc    string
+		thingID string
+		size    int
+		err     error
+	}{
+		{
+			desc:    "allow message within quota",
+			thingID: "limited",
+			size:    10,
+			err:     nil,
+		},
+		{
+			desc:    "reject message exceeding payload size",
+			thingID: "limited",
+			size:    11,
+			err:     quota.ErrPayloadTooLarge,
+		},
+		{
+			desc:    "allow last message within rate",
+			thingID: "limited",
+			size:    1,
+			err:     nil,
+		},
+		{
+			desc:    "reject message exceeding rate",
+			thingID: "limited",
+			size:    1,
+			err:     quota.ErrRateLimited,
+		},
+		{
+			desc:    "allow message without rate quota",
+			thingID: "unlimited",
+			size:    20,
+			err:     nil,
+		},
+		{
+			desc:    "reject message exceeding owner payload size",
+			thingID: "unlimited",
+			size:    21,
+			err:     quota.ErrPayloadTooLarge,
+		},
+		{
+			desc:    "allow message within owner rate",
+			thingID: "shared-1",
+			size:    1,
+			err:     nil,
+		},
+		{
+			desc:    "allow message of another thing within owner rate",
+			thingID: "shared-2",
+			size:    1,
+			err:     nil,
+		},
+		{
+			desc:    "reject message exceeding owner rate",
+			thingID: "shared-1",
+			size:    1,
+			err:     quota.ErrRateLimited,
+		},
+		{
+			desc:    "reject message of non-existing thing",
+			thingID: "non-existing",
+			size:    1,
+			err:     quota.ErrRetrieveQuota,
+		},
+	}
+
+	for _, tc := range cases {
+		err := limiter.Allow(context.Background(), tc.thingID, tc.size)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
@@ -0,0 +1,7 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package retained contains the store of the last message published to
+// every channel subtopic, used to serve the latest values to the clients
+// subscribing after the message was published.
+package retained
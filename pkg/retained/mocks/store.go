@@ -0,0 +1,68 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/retained"
+)
+
+var _ retained.Store = (*storeMock)(nil)
+
+type storeMock struct {
+	mu   sync.Mutex
+	msgs map[string]map[string]messaging.Message
+}
+
+// NewStore returns the in-memory last-value store.
+func NewStore() retained.Store {
+	return &storeMock{msgs: make(map[string]map[string]messaging.Message)}
+}
+
+func (sm *storeMock) Save(_ context.Context, msg messaging.Message) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if len(msg.Payload) == 0 {
+		delete(sm.msgs[msg.Channel], msg.Subtopic)
+		return nil
+	}
+	if _, ok := sm.msgs[msg.Channel]; !ok {
+		sm.msgs[msg.Channel] = make(map[string]messaging.Message)
+	}
+	sm.msgs[msg.Channel][msg.Subtopic] = msg
+	return nil
+}
+
+func (sm *storeMock) Retrieve(_ context.Context, chanID, subtopic string) (messaging.Message, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	msg, ok := sm.msgs[chanID][subtopic]
+	if !ok || retained.Expired(msg, time.Now()) {
+		return messaging.Message{}, retained.ErrNotFound
+	}
+	return msg, nil
+}
+
+func (sm *storeMock) RetrieveAll(_ context.Context, chanID string) ([]messaging.Message, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	msgs := []messaging.Message{}
+	for _, msg := range sm.msgs[chanID] {
+		if !retained.Expired(msg, time.Now()) {
+			msgs = append(msgs, msg)
+		}
+	}
+	sort.Slice(msgs, func(i, j int) bool {
+		return msgs[i].Subtopic < msgs[j].Subtopic
+	})
+	return msgs, nil
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redis contains the last-value store implementation using Redis as
+// the underlying database.
+package redis
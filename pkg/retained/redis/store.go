@@ -0,0 +1,105 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/retained"
+)
+
+const keyPrefix = "retained"
+
+var _ retained.Store = (*store)(nil)
+
+type store struct {
+	client *redis.Client
+}
+
+// NewStore returns the Redis last-value store. The messages of the channel
+// are kept in a hash, with the subtopic as the field.
+func NewStore(client *redis.Client) retained.Store {
+	return store{client: client}
+}
+
+func (s store) Save(ctx context.Context, msg messaging.Message) error {
+	if len(msg.Payload) == 0 {
+		if err := s.client.HDel(ctx, key(msg.Channel), msg.Subtopic).Err(); err != nil {
+			return errors.Wrap(errors.ErrRemoveEntity, err)
+		}
+		return nil
+	}
+
+	data, err := proto.Marshal(&msg)
+	if err != nil {
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+	if err := s.client.HSet(ctx, key(msg.Channel), msg.Subtopic, data).Err(); err != nil {
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+	return nil
+}
+
+func (s store) Retrieve(ctx context.Context, chanID, subtopic string) (messaging.Message, error) {
+	data, err := s.client.HGet(ctx, key(chanID), subtopic).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return messaging.Message{}, retained.ErrNotFound
+		}
+		return messaging.Message{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	var msg messaging.Message
+	if err := proto.Unmarshal([]byte(data), &msg); err != nil {
+		return messaging.Message{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	if retained.Expired(msg, time.Now()) {
+		s.client.HDel(ctx, key(chanID), subtopic)
+		return messaging.Message{}, retained.ErrNotFound
+	}
+
+	return msg, nil
+}
+
+func (s store) RetrieveAll(ctx context.Context, chanID string) ([]messaging.Message, error) {
+	fields, err := s.client.HGetAll(ctx, key(chanID)).Result()
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	now := time.Now()
+	msgs := []messaging.Message{}
+	var expired []string
+	for subtopic, data := range fields {
+		var msg messaging.Message
+		if err := proto.Unmarshal([]byte(data), &msg); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		if retained.Expired(msg, now) {
+			expired = append(expired, subtopic)
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	if len(expired) > 0 {
+		s.client.HDel(ctx, key(chanID), expired...)
+	}
+
+	sort.Slice(msgs, func(i, j int) bool {
+		return msgs[i].Subtopic < msgs[j].Subtopic
+	})
+
+	return msgs, nil
+}
+
+func key(chanID string) string {
+	return fmt.Sprintf("%s:%s", keyPrefix, chanID)
+}
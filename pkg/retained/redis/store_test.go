@@ -0,0 +1,116 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/retained"
+	"github.com/mainflux/mainflux/pkg/retained/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const chanID = "chan"
+
+func message(subtopic, payload string) messaging.Message {
+	return messaging.Message{
+		Channel:   chanID,
+		Subtopic:  subtopic,
+		Publisher: "publisher",
+		Protocol:  "mqtt",
+		Payload:   []byte(payload),
+		Created:   time.Now().UnixNano(),
+	}
+}
+
+func TestSave(t *testing.T) {
+	store := redis.NewStore(redisClient)
+
+	first := message("temperature", "first")
+	second := message("temperature", "second")
+	cleared := message("humidity", "")
+
+	err := store.Save(context.Background(), message("humidity", "retained"))
+	require.Nil(t, err, fmt.Sprintf("unexpected error saving message: %s", err))
+
+	cases := []struct {
+		desc     string
+		msg      messaging.Message
+		subtopic string
+		expected messaging.Message
+		err      error
+	}{
+		{
+			desc:     "save message",
+			msg:      first,
+			subtopic: "temperature",
+			expected: first,
+		},
+		{
+			desc:     "save message replacing retained message",
+			msg:      second,
+			subtopic: "temperature",
+			expected: second,
+		},
+		{
+			desc:     "save message with empty payload",
+			msg:      cleared,
+			subtopic: "humidity",
+			err:      retained.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := store.Save(context.Background(), tc.msg)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		msg, err := store.Retrieve(context.Background(), chanID, tc.subtopic)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.expected, msg, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.expected, msg))
+	}
+}
+
+func TestRetrieveAll(t *testing.T) {
+	store := redis.NewStore(redisClient)
+
+	root := message("", "root")
+	sub := message("sub", "sub")
+	expired := message("expired", "expired")
+	expired.Created = time.Now().Add(-time.Minute).UnixNano()
+	expired.Expiry = 1
+	for _, msg := range []messaging.Message{sub, root, expired} {
+		msg.Channel = "all"
+		err := store.Save(context.Background(), msg)
+		require.Nil(t, err, fmt.Sprintf("unexpected error saving message: %s", err))
+	}
+	root.Channel, sub.Channel = "all", "all"
+
+	cases := []struct {
+		desc     string
+		chanID   string
+		expected []messaging.Message
+	}{
+		{
+			desc:     "retrieve retained messages of the channel",
+			chanID:   "all",
+			expected: []messaging.Message{root, sub},
+		},
+		{
+			desc:     "retrieve retained messages of the channel without messages",
+			chanID:   "empty",
+			expected: []messaging.Message{},
+		},
+	}
+
+	for _, tc := range cases {
+		msgs, err := store.RetrieveAll(context.Background(), tc.chanID)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.expected, msgs, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.expected, msgs))
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package retained
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+// ErrNotFound indicates that there is no retained message for the channel
+// subtopic.
+var ErrNotFound = errors.New("retained message not found")
+
+// Store specifies the last-value store API.
+type Store interface {
+	// Save replaces the retained message of the message channel and
+	// subtopic. The message with an empty payload clears it.
+	Save(ctx context.Context, msg messaging.Message) error
+
+	// Retrieve returns the retained message of the channel subtopic. The
+	// empty subtopic refers to the messages published to the channel itself.
+	Retrieve(ctx context.Context, chanID, subtopic string) (messaging.Message, error)
+
+	// RetrieveAll returns the retained messages of all the channel
+	// subtopics, sorted by the subtopic.
+	RetrieveAll(ctx context.Context, chanID string) ([]messaging.Message, error)
+}
+
+// Handler returns the message handler saving the messages received from
+// the broker to the store.
+func Handler(store Store) messaging.MessageHandler {
+	return func(msg messaging.Message) error {
+		return store.Save(context.Background(), msg)
+	}
+}
+
+// Expired returns true if the message expiry interval elapsed at the given
+// time. The messages without the expiry interval never expire.
+func Expired(msg messaging.Message, now time.Time) bool {
+	if msg.Expiry == 0 {
+		return false
+	}
+	return msg.Created+int64(msg.Expiry)*int64(time.Second) <= now.UnixNano()
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schema contains the validation of the message payloads against
+// the optional schema stored in the channel metadata.
+package schema
@@ -0,0 +1,26 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/pkg/schema"
+)
+
+var _ schema.Validator = (*validatorMock)(nil)
+
+type validatorMock struct {
+	schemas map[string]*schema.Schema
+}
+
+// NewValidator returns mock implementation of the validator of the given
+// channel schemas.
+func NewValidator(schemas map[string]*schema.Schema) schema.Validator {
+	return validatorMock{schemas: schemas}
+}
+
+func (vm validatorMock) Validate(_ context.Context, chanID, contentType string, payload []byte) error {
+	return vm.schemas[chanID].Validate(contentType, payload)
+}
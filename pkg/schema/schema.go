@@ -0,0 +1,180 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	mfsenml "github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/mainflux/senml"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// MetadataKey is the channel metadata key the payload schema is stored under.
+const MetadataKey = "schema"
+
+var (
+	// ErrInvalidSchema indicates malformed payload schema.
+	ErrInvalidSchema = errors.New("invalid payload schema")
+
+	// ErrInvalidPayload indicates the payload that doesn't conform to the schema.
+	ErrInvalidPayload = errors.New("payload doesn't conform to the channel schema")
+)
+
+// Record constrains the SenML records with the given name. If the unit is
+// set, the records must have the same unit.
+type Record struct {
+	Name string `json:"name"`
+	Unit string `json:"unit,omitempty"`
+}
+
+type definition struct {
+	JSON  json.RawMessage `json:"json,omitempty"`
+	SenML []Record        `json:"senml,omitempty"`
+}
+
+// Schema is the payload schema of the channel. It consists of the optional
+// JSON Schema, which the JSON representation of the payload is validated
+// against, and the optional list of the allowed SenML records.
+type Schema struct {
+	json    *gojsonschema.Schema
+	records map[string]Record
+}
+
+// Parse returns the payload schema stored in the channel metadata, or nil
+// if the channel doesn't have one. The schema is stored as:
+//
+//	"schema": {
+//	  "json": { <JSON Schema> },
+//	  "senml": [{"name": "temperature", "unit": "Cel"}]
+//	}
+func Parse(metadata map[string]interface{}) (*Schema, error) {
+	val, ok := metadata[MetadataKey]
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(val)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidSchema, err)
+	}
+	var def definition
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, errors.Wrap(ErrInvalidSchema, err)
+	}
+
+	s := &Schema{}
+	if len(def.JSON) > 0 && string(def.JSON) != "null" {
+		var doc interface{}
+		if err := json.Unmarshal(def.JSON, &doc); err != nil {
+			return nil, errors.Wrap(ErrInvalidSchema, err)
+		}
+		// Remote references would make the service fetch
+		// arbitrary URLs, so only the local ones are allowed.
+		if err := checkRefs(doc); err != nil {
+			return nil, err
+		}
+		s.json, err = gojsonschema.NewSchema(gojsonschema.NewBytesLoader(def.JSON))
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidSchema, err)
+		}
+	}
+
+	if def.SenML != nil {
+		s.records = make(map[string]Record, len(def.SenML))
+		for _, r := range def.SenML {
+			if r.Name == "" {
+				return nil, errors.Wrap(ErrInvalidSchema, errors.New("missing SenML record name"))
+			}
+			s.records[r.Name] = r
+		}
+	}
+
+	return s, nil
+}
+
+// Validate validates the payload of the given content type against the
+// schema. SenML CBOR and XML payloads are converted to SenML JSON before
+// they are validated against the JSON Schema, and the other payloads are
+// expected to be JSON.
+func (s *Schema) Validate(contentType string, payload []byte) error {
+	if s == nil {
+		return nil
+	}
+
+	format, ok := mfsenml.Format(contentType)
+	if !ok {
+		format = senml.JSON
+	}
+
+	if s.json != nil {
+		doc := payload
+		if format != senml.JSON {
+			p, err := senml.Decode(payload, format)
+			if err != nil {
+				return errors.Wrap(ErrInvalidPayload, err)
+			}
+			if doc, err = senml.Encode(p, senml.JSON); err != nil {
+				return errors.Wrap(ErrInvalidPayload, err)
+			}
+		}
+		res, err := s.json.Validate(gojsonschema.NewBytesLoader(doc))
+		if err != nil {
+			return errors.Wrap(ErrInvalidPayload, err)
+		}
+		if !res.Valid() {
+			var msgs []string
+			for _, e := range res.Errors() {
+				msgs = append(msgs, e.String())
+			}
+			return errors.Wrap(ErrInvalidPayload, errors.New(strings.Join(msgs, "; ")))
+		}
+	}
+
+	if s.records != nil {
+		p, err := senml.Decode(payload, format)
+		if err != nil {
+			return errors.Wrap(ErrInvalidPayload, err)
+		}
+		p, err = senml.Normalize(p)
+		if err != nil {
+			return errors.Wrap(ErrInvalidPayload, err)
+		}
+		for _, rec := range p.Records {
+			r, ok := s.records[rec.Name]
+			if !ok {
+				return errors.Wrap(ErrInvalidPayload, fmt.Errorf("record %q is not allowed", rec.Name))
+			}
+			if r.Unit != "" && rec.Unit != r.Unit {
+				return errors.Wrap(ErrInvalidPayload, fmt.Errorf("record %q must have unit %q", rec.Name, r.Unit))
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkRefs(doc interface{}) error {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		for key, val := range v {
+			if ref, ok := val.(string); ok && key == "$ref" && !strings.HasPrefix(ref, "#") {
+				return errors.Wrap(ErrInvalidSchema, fmt.Errorf("remote reference %q is not allowed", ref))
+			}
+			if err := checkRefs(val); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, val := range v {
+			if err := checkRefs(val); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
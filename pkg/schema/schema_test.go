@@ -0,0 +1,179 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package schema_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/schema"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	mfsenml "github.com/mainflux/senml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func metadata(def map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{schema.MetadataKey: def}
+}
+
+var (
+	jsonSchema = map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"temperature"},
+		"properties": map[string]interface{}{
+			"temperature": map[string]interface{}{"type": "number", "minimum": -50},
+		},
+	}
+	senmlSchema = []interface{}{
+		map[string]interface{}{"name": "temperature", "unit": "Cel"},
+		map[string]interface{}{"name": "status"},
+	}
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		desc     string
+		metadata map[string]interface{}
+		empty    bool
+		err      error
+	}{
+		{
+			desc:     "parse metadata without schema",
+			metadata: map[string]interface{}{"location": "hall"},
+			empty:    true,
+		},
+		{
+			desc:     "parse JSON schema",
+			metadata: metadata(map[string]interface{}{"json": jsonSchema}),
+		},
+		{
+			desc:     "parse SenML schema",
+			metadata: metadata(map[string]interface{}{"senml": senmlSchema}),
+		},
+		{
+			desc:     "parse schema of invalid type",
+			metadata: map[string]interface{}{schema.MetadataKey: "schema"},
+			err:      schema.ErrInvalidSchema,
+		},
+		{
+			desc:     "parse invalid JSON schema",
+			metadata: metadata(map[string]interface{}{"json": map[string]interface{}{"type": 5}}),
+			err:      schema.ErrInvalidSchema,
+		},
+		{
+			desc:     "parse JSON schema with remote reference",
+			metadata: metadata(map[string]interface{}{"json": map[string]interface{}{"$ref": "http://example.com/schema.json"}}),
+			err:      schema.ErrInvalidSchema,
+		},
+		{
+			desc:     "parse SenML schema without record name",
+			metadata: metadata(map[string]interface{}{"senml": []interface{}{map[string]interface{}{"unit": "Cel"}}}),
+			err:      schema.ErrInvalidSchema,
+		},
+	}
+
+	for _, tc := range cases {
+		s, err := schema.Parse(tc.metadata)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, tc.empty, s == nil, fmt.Sprintf("%s: expected empty schema %t got %v", tc.desc, tc.empty, s))
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	js, err := schema.Parse(metadata(map[string]interface{}{"json": jsonSchema}))
+	require.Nil(t, err, fmt.Sprintf("unexpected error parsing JSON schema: %s", err))
+	ss, err := schema.Parse(metadata(map[string]interface{}{"senml": senmlSchema}))
+	require.Nil(t, err, fmt.Sprintf("unexpected error parsing SenML schema: %s", err))
+	arr, err := schema.Parse(metadata(map[string]interface{}{"json": map[string]interface{}{"type": "array", "maxItems": 1}}))
+	require.Nil(t, err, fmt.Sprintf("unexpected error parsing JSON schema: %s", err))
+
+	v := 20.0
+	cbor, err := mfsenml.Encode(mfsenml.Pack{Records: []mfsenml.Record{{Name: "temperature", Unit: "Cel", Value: &v}, {Name: "status", Value: &v}}}, mfsenml.CBOR)
+	require.Nil(t, err, fmt.Sprintf("unexpected error encoding CBOR: %s", err))
+
+	cases := []struct {
+		desc        string
+		schema      *schema.Schema
+		contentType string
+		payload     string
+		err         error
+	}{
+		{
+			desc:    "validate payload without schema",
+			payload: "invalid",
+		},
+		{
+			desc:    "validate conforming JSON payload",
+			schema:  js,
+			payload: `{"temperature": 20}`,
+		},
+		{
+			desc:    "validate JSON payload without required field",
+			schema:  js,
+			payload: `{"humidity": 20}`,
+			err:     schema.ErrInvalidPayload,
+		},
+		{
+			desc:    "validate JSON payload with value out of range",
+			schema:  js,
+			payload: `{"temperature": -60}`,
+			err:     schema.ErrInvalidPayload,
+		},
+		{
+			desc:    "validate malformed JSON payload",
+			schema:  js,
+			payload: `{"temperature"`,
+			err:     schema.ErrInvalidPayload,
+		},
+		{
+			desc:        "validate conforming SenML JSON payload",
+			schema:      ss,
+			contentType: senml.JSON,
+			payload:     `[{"bn":"temp","n":"erature","u":"Cel","v":20},{"n":"erature","u":"Cel","v":21}]`,
+		},
+		{
+			desc:        "validate SenML JSON payload with unknown record",
+			schema:      ss,
+			contentType: senml.JSON,
+			payload:     `[{"n":"humidity","u":"%RH","v":40}]`,
+			err:         schema.ErrInvalidPayload,
+		},
+		{
+			desc:        "validate SenML JSON payload with invalid unit",
+			schema:      ss,
+			contentType: senml.JSON,
+			payload:     `[{"n":"temperature","u":"K","v":290}]`,
+			err:         schema.ErrInvalidPayload,
+		},
+		{
+			desc:        "validate malformed SenML JSON payload",
+			schema:      ss,
+			contentType: senml.JSON,
+			payload:     `{"n":"temperature"}`,
+			err:         schema.ErrInvalidPayload,
+		},
+		{
+			desc:        "validate conforming SenML CBOR payload",
+			schema:      ss,
+			contentType: senml.CBOR,
+			payload:     string(cbor),
+		},
+		{
+			desc:        "validate SenML CBOR payload against JSON schema",
+			schema:      arr,
+			contentType: senml.CBOR,
+			payload:     string(cbor),
+			err:         schema.ErrInvalidPayload,
+		},
+	}
+
+	for _, tc := range cases {
+		err := tc.schema.Validate(tc.contentType, []byte(tc.payload))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// ErrRetrieveSchema indicates failure to retrieve the channel schema.
+var ErrRetrieveSchema = errors.New("failed to retrieve channel schema")
+
+// Validator specifies the validation of the published payloads.
+type Validator interface {
+	// Validate validates the payload of the given content type published to
+	// the channel with the given ID against the channel schema.
+	Validate(ctx context.Context, chanID, contentType string, payload []byte) error
+}
+
+type entry struct {
+	schema  *Schema
+	expires time.Time
+}
+
+type thingsValidator struct {
+	things mainflux.ThingsServiceClient
+	ttl    time.Duration
+	mu     sync.Mutex
+	cache  map[string]entry
+}
+
+// NewValidator returns the Validator of the channel schemas retrieved from
+// the things service. Schemas are cached for the given period, so that the
+// things service is not called for every published message.
+func NewValidator(things mainflux.ThingsServiceClient, ttl time.Duration) Validator {
+	return &thingsValidator{
+		things: things,
+		ttl:    ttl,
+		cache:  make(map[string]entry),
+	}
+}
+
+func (tv *thingsValidator) Validate(ctx context.Context, chanID, contentType string, payload []byte) error {
+	s, err := tv.schema(ctx, chanID)
+	if err != nil {
+		return err
+	}
+
+	return s.Validate(contentType, payload)
+}
+
+func (tv *thingsValidator) schema(ctx context.Context, chanID string) (*Schema, error) {
+	now := time.Now()
+
+	tv.mu.Lock()
+	e, ok := tv.cache[chanID]
+	tv.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.schema, nil
+	}
+
+	ch, err := tv.things.ViewChannel(ctx, &mainflux.ChannelID{Value: chanID})
+	if err != nil {
+		return nil, errors.Wrap(ErrRetrieveSchema, err)
+	}
+
+	md := map[string]interface{}{}
+	if len(ch.GetMetadata()) > 0 {
+		if err := json.Unmarshal(ch.GetMetadata(), &md); err != nil {
+			return nil, errors.Wrap(ErrRetrieveSchema, err)
+		}
+	}
+	s, err := Parse(md)
+	if err != nil {
+		return nil, errors.Wrap(ErrRetrieveSchema, err)
+	}
+
+	tv.mu.Lock()
+	tv.cache[chanID] = entry{schema: s, expires: now.Add(tv.ttl)}
+	tv.mu.Unlock()
+
+	return s, nil
+}
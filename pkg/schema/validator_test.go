@@ -0,0 +1,112 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package schema_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/schema"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+const (
+	chanID  = "chan"
+	otherID = "other"
+)
+
+// thingsClient serves the channel metadata and counts the retrievals.
+type thingsClient struct {
+	channels map[string][]byte
+	calls    int
+}
+
+func (tc *thingsClient) CanAccessByKey(context.Context, *mainflux.AccessByKeyReq, ...grpc.CallOption) (*mainflux.ThingID, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) CanAccessByID(context.Context, *mainflux.AccessByIDReq, ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) IsChannelOwner(context.Context, *mainflux.ChannelOwnerReq, ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) IsChannelsOwner(context.Context, *mainflux.ChannelsOwnerReq, ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) Identify(context.Context, *mainflux.Token, ...grpc.CallOption) (*mainflux.ThingID, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) ViewThing(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.Thing, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) ViewChannel(_ context.Context, req *mainflux.ChannelID, _ ...grpc.CallOption) (*mainflux.Channel, error) {
+	tc.calls++
+	md, ok := tc.channels[req.GetValue()]
+	if !ok {
+		return nil, errors.ErrNotFound
+	}
+	return &mainflux.Channel{Id: req.GetValue(), Metadata: md}, nil
+}
+
+func (tc *thingsClient) ViewQuota(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.Quota, error) {
+	panic("not implemented")
+}
+
+func TestValidator(t *testing.T) {
+	md, err := json.Marshal(metadata(map[string]interface{}{"senml": senmlSchema}))
+	require.Nil(t, err, fmt.Sprintf("unexpected error encoding metadata: %s", err))
+	things := &thingsClient{channels: map[string][]byte{chanID: md, otherID: []byte(`{}`)}}
+	validator := schema.NewValidator(things, time.Minute)
+
+	cases := []struct {
+		desc    string
+		chanID  string
+		payload string
+		err     error
+	}{
+		{
+			desc:    "validate conforming payload",
+			chanID:  chanID,
+			payload: `[{"n":"temperature","u":"Cel","v":20}]`,
+		},
+		{
+			desc:    "validate non-conforming payload",
+			chanID:  chanID,
+			payload: `[{"n":"humidity","u":"%RH","v":40}]`,
+			err:     schema.ErrInvalidPayload,
+		},
+		{
+			desc:    "validate payload published to channel without schema",
+			chanID:  otherID,
+			payload: `[{"n":"humidity","u":"%RH","v":40}]`,
+		},
+		{
+			desc:    "validate payload published to non-existing channel",
+			chanID:  "unknown",
+			payload: `[{"n":"temperature","u":"Cel","v":20}]`,
+			err:     schema.ErrRetrieveSchema,
+		},
+	}
+
+	for _, tc := range cases {
+		err := validator.Validate(context.Background(), tc.chanID, senml.JSON, []byte(tc.payload))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+	assert.Equal(t, 3, things.calls, fmt.Sprintf("expected schemas to be retrieved %d times got %d", 3, things.calls))
+}
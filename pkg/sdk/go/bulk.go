@@ -0,0 +1,85 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+func (sdk mfSDK) ImportThings(data []byte, ct ContentType, dryRun bool, token string) (ImportReport, error) {
+	return sdk.importEntities(thingsEndpoint, data, ct, dryRun, token)
+}
+
+func (sdk mfSDK) ImportChannels(data []byte, ct ContentType, dryRun bool, token string) (ImportReport, error) {
+	return sdk.importEntities(channelsEndpoint, data, ct, dryRun, token)
+}
+
+func (sdk mfSDK) ExportThings(output, token string) ([]byte, error) {
+	return sdk.exportEntities(thingsEndpoint, output, token)
+}
+
+func (sdk mfSDK) ExportChannels(output, token string) ([]byte, error) {
+	return sdk.exportEntities(channelsEndpoint, output, token)
+}
+
+func (sdk mfSDK) importEntities(endpoint string, data []byte, ct ContentType, dryRun bool, token string) (ImportReport, error) {
+	url := fmt.Sprintf("%s/%s/import?dry_run=%t", sdk.thingsURL, endpoint, dryRun)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(ct))
+	if err != nil {
+		return ImportReport{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return ImportReport{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return ImportReport{}, errors.Wrap(ErrFailedCreation, errors.New(resp.Status))
+	}
+
+	var ir ImportReport
+	if err := json.Unmarshal(body, &ir); err != nil {
+		return ImportReport{}, err
+	}
+
+	return ir, nil
+}
+
+func (sdk mfSDK) exportEntities(endpoint, output, token string) ([]byte, error) {
+	url := fmt.Sprintf("%s/%s/export?output=%s", sdk.thingsURL, endpoint, output)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Wrap(ErrFailedFetch, errors.New(resp.Status))
+	}
+
+	return body, nil
+}
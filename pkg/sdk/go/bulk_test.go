@@ -0,0 +1,148 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	sdk "github.com/mainflux/mainflux/pkg/sdk/go"
+	"github.com/mainflux/mainflux/pkg/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportThings(t *testing.T) {
+	svc := newThingsService(map[string]string{token: email})
+	ts := newThingsServer(svc)
+	defer ts.Close()
+
+	sdkConf := sdk.Config{
+		ThingsURL:       ts.URL,
+		MsgContentType:  contentType,
+		TLSVerification: false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+	id := fmt.Sprintf("%s%012d", uuid.Prefix, 1)
+
+	cases := []struct {
+		desc   string
+		data   string
+		ct     sdk.ContentType
+		dryRun bool
+		token  string
+		report sdk.ImportReport
+		err    error
+	}{
+		{
+			desc:   "import things with dry run",
+			data:   "name,metadata\ntest1,\"{\"\"meta\"\":\"\"data\"\"}\"\n",
+			ct:     sdk.CTCSV,
+			dryRun: true,
+			token:  token,
+			report: sdk.ImportReport{DryRun: true, Created: 1, Rows: []sdk.ImportRow{{Row: 1, Action: "create"}}},
+			err:    nil,
+		},
+		{
+			desc:   "import things",
+			data:   "name,metadata\ntest1,\"{\"\"meta\"\":\"\"data\"\"}\"\n",
+			ct:     sdk.CTCSV,
+			token:  token,
+			report: sdk.ImportReport{Created: 1, Rows: []sdk.ImportRow{{Row: 1, ID: id, Action: "create"}}},
+			err:    nil,
+		},
+		{
+			desc:   "import existing things",
+			data:   "{\"name\":\"test1\",\"metadata\":{\"meta\":\"data2\"}}\n",
+			ct:     sdk.CTNDJSON,
+			token:  token,
+			report: sdk.ImportReport{Updated: 1, Rows: []sdk.ImportRow{{Row: 1, ID: id, Action: "update"}}},
+			err:    nil,
+		},
+		{
+			desc:   "import things with invalid data",
+			data:   "invalid\n",
+			ct:     sdk.CTCSV,
+			token:  token,
+			report: sdk.ImportReport{},
+			err:    createError(sdk.ErrFailedCreation, http.StatusBadRequest),
+		},
+		{
+			desc:   "import things with invalid token",
+			data:   "name\ntest1\n",
+			ct:     sdk.CTCSV,
+			token:  wrongValue,
+			report: sdk.ImportReport{},
+			err:    createError(sdk.ErrFailedCreation, http.StatusUnauthorized),
+		},
+	}
+
+	for _, tc := range cases {
+		report, err := mainfluxSDK.ImportThings([]byte(tc.data), tc.ct, tc.dryRun, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.report, report, fmt.Sprintf("%s: expected report %v got %v", tc.desc, tc.report, report))
+	}
+}
+
+func TestExportThings(t *testing.T) {
+	svc := newThingsService(map[string]string{token: email})
+	ts := newThingsServer(svc)
+	defer ts.Close()
+
+	sdkConf := sdk.Config{
+		ThingsURL:       ts.URL,
+		MsgContentType:  contentType,
+		TLSVerification: false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+	ths, err := mainfluxSDK.CreateThings([]sdk.Thing{{Name: "test1"}}, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	th := ths[0]
+
+	cases := []struct {
+		desc   string
+		output string
+		token  string
+		data   string
+		err    error
+	}{
+		{
+			desc:   "export things as CSV",
+			output: "csv",
+			token:  token,
+			data:   fmt.Sprintf("id,name,key,metadata,connections\n%s,%s,%s,,\n", th.ID, th.Name, th.Key),
+			err:    nil,
+		},
+		{
+			desc:   "export things as NDJSON",
+			output: "ndjson",
+			token:  token,
+			data:   fmt.Sprintf("{\"id\":\"%s\",\"name\":\"%s\",\"key\":\"%s\"}\n", th.ID, th.Name, th.Key),
+			err:    nil,
+		},
+		{
+			desc:   "export things with invalid output",
+			output: wrongValue,
+			token:  token,
+			data:   "",
+			err:    createError(sdk.ErrFailedFetch, http.StatusBadRequest),
+		},
+		{
+			desc:   "export things with invalid token",
+			output: "csv",
+			token:  wrongValue,
+			data:   "",
+			err:    createError(sdk.ErrFailedFetch, http.StatusUnauthorized),
+		},
+	}
+
+	for _, tc := range cases {
+		data, err := mainfluxSDK.ExportThings(tc.output, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.data, string(data), fmt.Sprintf("%s: expected data %s got %s", tc.desc, tc.data, data))
+	}
+}
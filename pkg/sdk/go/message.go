@@ -77,7 +77,9 @@ func (sdk mfSDK) ReadMessages(chanName, token string) (MessagesPage, error) {
 }
 
 func (sdk mfSDK) SetContentType(ct ContentType) error {
-	if ct != CTJSON && ct != CTJSONSenML && ct != CTBinary {
+	switch ct {
+	case CTJSON, CTJSONSenML, CTCBORSenML, CTXMLSenML, CTBinary:
+	default:
 		return ErrInvalidContentType
 	}
 
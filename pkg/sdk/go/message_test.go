@@ -13,18 +13,24 @@ import (
 	adapter "github.com/mainflux/mainflux/http"
 	"github.com/mainflux/mainflux/http/api"
 	"github.com/mainflux/mainflux/http/mocks"
+	"github.com/mainflux/mainflux/logger"
+	pmocks "github.com/mainflux/mainflux/pkg/presence/mocks"
+	qmocks "github.com/mainflux/mainflux/pkg/quota/mocks"
+	rmocks "github.com/mainflux/mainflux/pkg/retained/mocks"
+	smocks "github.com/mainflux/mainflux/pkg/schema/mocks"
 	sdk "github.com/mainflux/mainflux/pkg/sdk/go"
+	"github.com/mainflux/mainflux/pkg/uuid"
 	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/stretchr/testify/assert"
 )
 
 func newMessageService(cc mainflux.ThingsServiceClient) adapter.Service {
-	pub := mocks.NewPublisher()
-	return adapter.New(pub, cc)
+	pub := mocks.NewPubSub()
+	return adapter.New(pub, cc, smocks.NewValidator(nil), rmocks.NewStore(), qmocks.NewLimiter(nil), pmocks.NewTracker(), logger.NewMock())
 }
 
 func newMessageServer(svc adapter.Service) *httptest.Server {
-	mux := api.MakeHandler(svc, mocktracer.New())
+	mux := api.MakeHandler(svc, mocktracer.New(), uuid.NewMock(), logger.NewMock())
 	return httptest.NewServer(mux)
 }
 
@@ -120,6 +126,11 @@ func TestSetContentType(t *testing.T) {
 			cType: "application/senml+json",
 			err:   nil,
 		},
+		{
+			desc:  "set senml+cbor content type",
+			cType: "application/senml+cbor",
+			err:   nil,
+		},
 		{
 			desc:  "set invalid content type",
 			cType: "invalid",
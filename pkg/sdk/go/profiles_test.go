@@ -0,0 +1,306 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	sdk "github.com/mainflux/mainflux/pkg/sdk/go"
+	"github.com/mainflux/mainflux/pkg/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var profile = sdk.Profile{
+	Name:     "thermometer",
+	Metadata: metadata,
+	Channels: []sdk.ProfileChannel{
+		{
+			Name:      "telemetry",
+			Subtopics: []string{"temperature"},
+			Schema:    map[string]interface{}{"json": map[string]interface{}{"type": "object"}},
+		},
+	},
+}
+
+func TestCreateProfile(t *testing.T) {
+	svc := newThingsService(map[string]string{token: email})
+	ts := newThingsServer(svc)
+	defer ts.Close()
+
+	sdkConf := sdk.Config{
+		ThingsURL:       ts.URL,
+		MsgContentType:  contentType,
+		TLSVerification: false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+
+	invalidSchema := profile
+	invalidSchema.Channels = []sdk.ProfileChannel{{Name: "telemetry", Schema: map[string]interface{}{"json": "invalid"}}}
+
+	cases := []struct {
+		desc     string
+		profile  sdk.Profile
+		token    string
+		err      error
+		location string
+	}{
+		{
+			desc:     "create new profile",
+			profile:  profile,
+			token:    token,
+			err:      nil,
+			location: fmt.Sprintf("%s%012d", uuid.Prefix, 1),
+		},
+		{
+			desc:     "create new profile with invalid channel schema",
+			profile:  invalidSchema,
+			token:    token,
+			err:      createError(sdk.ErrFailedCreation, http.StatusBadRequest),
+			location: "",
+		},
+		{
+			desc:     "create new profile with unknown connection",
+			profile:  sdk.Profile{Name: "unknown", Connections: []string{th2.ID}},
+			token:    token,
+			err:      createError(sdk.ErrFailedCreation, http.StatusBadRequest),
+			location: "",
+		},
+		{
+			desc:     "create new profile with invalid token",
+			profile:  profile,
+			token:    wrongValue,
+			err:      createError(sdk.ErrFailedCreation, http.StatusUnauthorized),
+			location: "",
+		},
+	}
+	for _, tc := range cases {
+		loc, err := mainfluxSDK.CreateProfile(tc.profile, tc.token)
+
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.location, loc, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, loc))
+	}
+}
+
+func TestProfile(t *testing.T) {
+	svc := newThingsService(map[string]string{token: email, otherToken: otherEmail})
+	ts := newThingsServer(svc)
+	defer ts.Close()
+
+	sdkConf := sdk.Config{
+		ThingsURL:       ts.URL,
+		MsgContentType:  contentType,
+		TLSVerification: false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+	id, err := mainfluxSDK.CreateProfile(profile, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	pr := profile
+	pr.ID = id
+
+	cases := []struct {
+		desc     string
+		id       string
+		token    string
+		err      error
+		response sdk.Profile
+	}{
+		{
+			desc:     "get existing profile",
+			id:       id,
+			token:    token,
+			err:      nil,
+			response: pr,
+		},
+		{
+			desc:     "get profile of other user",
+			id:       id,
+			token:    otherToken,
+			err:      createError(sdk.ErrFailedFetch, http.StatusNotFound),
+			response: sdk.Profile{},
+		},
+		{
+			desc:     "get profile with invalid token",
+			id:       id,
+			token:    wrongValue,
+			err:      createError(sdk.ErrFailedFetch, http.StatusUnauthorized),
+			response: sdk.Profile{},
+		},
+	}
+
+	for _, tc := range cases {
+		resp, err := mainfluxSDK.Profile(tc.id, tc.token)
+
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.response, resp, fmt.Sprintf("%s: expected response profile %v, got %v", tc.desc, tc.response, resp))
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	svc := newThingsService(map[string]string{token: email})
+	ts := newThingsServer(svc)
+	defer ts.Close()
+
+	sdkConf := sdk.Config{
+		ThingsURL:       ts.URL,
+		MsgContentType:  contentType,
+		TLSVerification: false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+	var profiles []sdk.Profile
+	for i := 0; i < 20; i++ {
+		pr := sdk.Profile{Name: fmt.Sprintf("profile-%d", i)}
+		id, err := mainfluxSDK.CreateProfile(pr, token)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		pr.ID = id
+		profiles = append(profiles, pr)
+	}
+
+	cases := []struct {
+		desc     string
+		token    string
+		offset   uint64
+		limit    uint64
+		err      error
+		response []sdk.Profile
+	}{
+		{
+			desc:     "get a list of profiles",
+			token:    token,
+			offset:   0,
+			limit:    5,
+			err:      nil,
+			response: profiles[0:5],
+		},
+		{
+			desc:     "get a list of profiles with offset",
+			token:    token,
+			offset:   15,
+			limit:    10,
+			err:      nil,
+			response: profiles[15:20],
+		},
+		{
+			desc:     "get a list of profiles with limit greater than max",
+			token:    token,
+			offset:   0,
+			limit:    110,
+			err:      createError(sdk.ErrFailedFetch, http.StatusBadRequest),
+			response: nil,
+		},
+		{
+			desc:     "get a list of profiles with invalid token",
+			token:    wrongValue,
+			offset:   0,
+			limit:    5,
+			err:      createError(sdk.ErrFailedFetch, http.StatusUnauthorized),
+			response: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := mainfluxSDK.Profiles(tc.token, tc.offset, tc.limit, "")
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.response, page.Profiles, fmt.Sprintf("%s: expected response profiles %v, got %v", tc.desc, tc.response, page.Profiles))
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	svc := newThingsService(map[string]string{token: email})
+	ts := newThingsServer(svc)
+	defer ts.Close()
+
+	sdkConf := sdk.Config{
+		ThingsURL:       ts.URL,
+		MsgContentType:  contentType,
+		TLSVerification: false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+	id, err := mainfluxSDK.CreateProfile(profile, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		profile sdk.Profile
+		token   string
+		err     error
+	}{
+		{
+			desc:    "update existing profile",
+			profile: sdk.Profile{ID: id, Name: "updated", Metadata: metadata2},
+			token:   token,
+			err:     nil,
+		},
+		{
+			desc:    "update non-existing profile",
+			profile: sdk.Profile{ID: th2.ID, Name: "updated"},
+			token:   token,
+			err:     createError(sdk.ErrFailedUpdate, http.StatusNotFound),
+		},
+		{
+			desc:    "update profile with invalid token",
+			profile: sdk.Profile{ID: id, Name: "updated"},
+			token:   wrongValue,
+			err:     createError(sdk.ErrFailedUpdate, http.StatusUnauthorized),
+		},
+	}
+
+	for _, tc := range cases {
+		err := mainfluxSDK.UpdateProfile(tc.profile, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestDeleteProfile(t *testing.T) {
+	svc := newThingsService(map[string]string{token: email})
+	ts := newThingsServer(svc)
+	defer ts.Close()
+
+	sdkConf := sdk.Config{
+		ThingsURL:       ts.URL,
+		MsgContentType:  contentType,
+		TLSVerification: false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+	id, err := mainfluxSDK.CreateProfile(profile, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "delete profile with invalid token",
+			id:    id,
+			token: wrongValue,
+			err:   createError(sdk.ErrFailedRemoval, http.StatusUnauthorized),
+		},
+		{
+			desc:  "delete existing profile",
+			id:    id,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "delete deleted profile",
+			id:    id,
+			token: token,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := mainfluxSDK.DeleteProfile(tc.id, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+	}
+}
@@ -40,6 +40,29 @@ type ChannelsPage struct {
 	pageRes
 }
 
+// ProfilesPage contains list of device profiles in a page with proper metadata.
+type ProfilesPage struct {
+	Profiles []Profile `json:"profiles"`
+	pageRes
+}
+
+// ImportReport contains the outcome of the things or channels import.
+type ImportReport struct {
+	DryRun  bool        `json:"dry_run"`
+	Created int         `json:"created"`
+	Updated int         `json:"updated"`
+	Failed  int         `json:"failed"`
+	Rows    []ImportRow `json:"rows"`
+}
+
+// ImportRow contains the outcome of importing a single row.
+type ImportRow struct {
+	Row    int    `json:"row"`
+	ID     string `json:"id,omitempty"`
+	Action string `json:"action,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
 // MessagesPage contains list of messages in a page with proper metadata.
 type MessagesPage struct {
 	Messages []senml.Message `json:"messages,omitempty"`
@@ -20,8 +20,21 @@ const (
 	// CTJSONSenML represents JSON SenML content type.
 	CTJSONSenML ContentType = "application/senml+json"
 
+	// CTCBORSenML represents CBOR SenML content type.
+	CTCBORSenML ContentType = "application/senml+cbor"
+
+	// CTXMLSenML represents XML SenML content type.
+	CTXMLSenML ContentType = "application/senml+xml"
+
 	// CTBinary represents binary content type.
 	CTBinary ContentType = "application/octet-stream"
+
+	// CTCSV represents CSV content type of the things and channels import.
+	CTCSV ContentType = "text/csv"
+
+	// CTNDJSON represents newline delimited JSON content type of the things
+	// and channels import.
+	CTNDJSON ContentType = "application/x-ndjson"
 )
 
 var (
@@ -97,10 +110,11 @@ type Group struct {
 
 // Thing represents mainflux thing.
 type Thing struct {
-	ID       string                 `json:"id,omitempty"`
-	Name     string                 `json:"name,omitempty"`
-	Key      string                 `json:"key,omitempty"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	ID        string                 `json:"id,omitempty"`
+	Name      string                 `json:"name,omitempty"`
+	Key       string                 `json:"key,omitempty"`
+	ProfileID string                 `json:"profile_id,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
 }
 
 // Channel represents mainflux channel.
@@ -110,6 +124,25 @@ type Channel struct {
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// Profile represents mainflux device profile, the template the things are
+// created from.
+type Profile struct {
+	ID          string                 `json:"id,omitempty"`
+	Name        string                 `json:"name,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Channels    []ProfileChannel       `json:"channels,omitempty"`
+	Connections []string               `json:"connections,omitempty"`
+}
+
+// ProfileChannel represents the channel created for every thing created from
+// the profile.
+type ProfileChannel struct {
+	Name      string                 `json:"name"`
+	Subtopics []string               `json:"subtopics,omitempty"`
+	Schema    map[string]interface{} `json:"schema,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+}
+
 // Member represents group member.
 type Member struct {
 	ID   string
@@ -225,6 +258,38 @@ type SDK interface {
 	// DeleteChannel removes existing channel.
 	DeleteChannel(id, token string) error
 
+	// CreateProfile creates new device profile and returns its id.
+	CreateProfile(profile Profile, token string) (string, error)
+
+	// Profiles returns page of device profiles.
+	Profiles(token string, offset, limit uint64, name string) (ProfilesPage, error)
+
+	// Profile returns device profile data by id.
+	Profile(id, token string) (Profile, error)
+
+	// UpdateProfile updates existing device profile.
+	UpdateProfile(profile Profile, token string) error
+
+	// DeleteProfile removes existing device profile.
+	DeleteProfile(id, token string) error
+
+	// ImportThings creates or updates things from the CSV or NDJSON data and
+	// returns the per-row report. If dryRun is set, the data is only
+	// validated.
+	ImportThings(data []byte, ct ContentType, dryRun bool, token string) (ImportReport, error)
+
+	// ImportChannels creates or updates channels from the CSV or NDJSON data
+	// and returns the per-row report.
+	ImportChannels(data []byte, ct ContentType, dryRun bool, token string) (ImportReport, error)
+
+	// ExportThings returns all the things, in the "csv" or "ndjson" output
+	// format accepted by ImportThings.
+	ExportThings(output, token string) ([]byte, error)
+
+	// ExportChannels returns all the channels, in the "csv" or "ndjson"
+	// output format accepted by ImportChannels.
+	ExportChannels(output, token string) ([]byte, error)
+
 	// SendMessage send message to specified channel.
 	SendMessage(chanID, msg, token string) error
 
@@ -47,11 +47,14 @@ func newThingsService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	profilesRepo := mocks.NewProfileRepository()
+	quotasRepo := mocks.NewQuotaRepository()
+	statusRepo := mocks.NewStatusRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idProvider := uuid.NewMock()
 
-	return things.New(auth, thingsRepo, channelsRepo, chanCache, thingCache, idProvider)
+	return things.New(auth, thingsRepo, channelsRepo, profilesRepo, quotasRepo, statusRepo, chanCache, thingCache, idProvider)
 }
 
 func newThingsServer(svc things.Service) *httptest.Server {
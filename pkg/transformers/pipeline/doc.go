@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pipeline contains the transformer that applies user-defined
+// rules to the output of SenML and JSON transformers.
+package pipeline
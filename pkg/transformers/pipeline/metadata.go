@@ -0,0 +1,75 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// ErrMetadata indicates failure to retrieve the thing metadata.
+var ErrMetadata = errors.New("failed to retrieve thing metadata")
+
+// Metadata specifies the source of the thing metadata used by the enrich rules.
+type Metadata interface {
+	// Metadata returns the metadata of the thing with the given ID.
+	Metadata(ctx context.Context, thingID string) (map[string]interface{}, error)
+}
+
+type entry struct {
+	metadata map[string]interface{}
+	expires  time.Time
+}
+
+type thingsMetadata struct {
+	things mainflux.ThingsServiceClient
+	ttl    time.Duration
+	mu     sync.Mutex
+	cache  map[string]entry
+}
+
+// NewThingsMetadata returns the Metadata retrieved from the things service.
+// Metadata is cached for the given period, so that the things service
+// is not called for every consumed message.
+func NewThingsMetadata(things mainflux.ThingsServiceClient, ttl time.Duration) Metadata {
+	return &thingsMetadata{
+		things: things,
+		ttl:    ttl,
+		cache:  make(map[string]entry),
+	}
+}
+
+func (tm *thingsMetadata) Metadata(ctx context.Context, thingID string) (map[string]interface{}, error) {
+	now := time.Now()
+
+	tm.mu.Lock()
+	e, ok := tm.cache[thingID]
+	tm.mu.Unlock()
+	if ok && now.Before(e.expires) {
+		return e.metadata, nil
+	}
+
+	th, err := tm.things.ViewThing(ctx, &mainflux.ThingID{Value: thingID})
+	if err != nil {
+		return nil, errors.Wrap(ErrMetadata, err)
+	}
+
+	md := map[string]interface{}{}
+	if len(th.GetMetadata()) > 0 {
+		if err := json.Unmarshal(th.GetMetadata(), &md); err != nil {
+			return nil, errors.Wrap(ErrMetadata, err)
+		}
+	}
+
+	tm.mu.Lock()
+	tm.cache[thingID] = entry{metadata: md, expires: now.Add(tm.ttl)}
+	tm.mu.Unlock()
+
+	return md, nil
+}
@@ -0,0 +1,367 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/transformers"
+	mfjson "github.com/mainflux/mainflux/pkg/transformers/json"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+)
+
+const pathSep = "."
+
+// ErrMissingMetadata indicates that enrich rule is used without the metadata source.
+var ErrMissingMetadata = errors.New("enrich rule requires thing metadata source")
+
+var _ transformers.Transformer = (*pipeline)(nil)
+
+type pipeline struct {
+	transformer transformers.Transformer
+	stages      []stage
+	metadata    Metadata
+}
+
+// New returns the transformer applying the rules, in the given order, to
+// the output of the given SenML or JSON transformer. The output of any other
+// transformer is returned unchanged. Metadata is needed only by the enrich
+// rules and may be nil if there are none.
+func New(t transformers.Transformer, rules []Rule, md Metadata) (transformers.Transformer, error) {
+	p := &pipeline{
+		transformer: t,
+		metadata:    md,
+	}
+	for _, r := range rules {
+		s, err := compile(r)
+		if err != nil {
+			return nil, err
+		}
+		if s.Type == Enrich && md == nil {
+			return nil, ErrMissingMetadata
+		}
+		p.stages = append(p.stages, s)
+	}
+
+	return p, nil
+}
+
+func (p *pipeline) Transform(msg messaging.Message) (interface{}, error) {
+	m, err := p.transformer.Transform(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var stages []stage
+	for _, s := range p.stages {
+		if s.applies(msg.Channel, msg.Subtopic) {
+			stages = append(stages, s)
+		}
+	}
+	if len(stages) == 0 {
+		return m, nil
+	}
+
+	// Metadata is retrieved at most once per message, and only if needed.
+	var md map[string]interface{}
+	metadata := func() (map[string]interface{}, error) {
+		if md != nil {
+			return md, nil
+		}
+		md, err = p.metadata.Metadata(context.Background(), msg.Publisher)
+		return md, err
+	}
+
+	switch msgs := m.(type) {
+	case []senml.Message:
+		return transformSenML(msgs, stages, metadata)
+	case mfjson.Messages:
+		return transformJSON(msgs, stages, metadata)
+	default:
+		return m, nil
+	}
+}
+
+func transformSenML(msgs []senml.Message, stages []stage, metadata func() (map[string]interface{}, error)) ([]senml.Message, error) {
+	for _, s := range stages {
+		switch s.Type {
+		case Filter, Drop:
+			var ret []senml.Message
+			for _, msg := range msgs {
+				if s.satisfied(senmlLookup(msg)) == (s.Type == Filter) {
+					ret = append(ret, msg)
+				}
+			}
+			msgs = ret
+		case Rename:
+			for i := range msgs {
+				if msgs[i].Name == s.Field && s.satisfied(senmlLookup(msgs[i])) {
+					msgs[i].Name = s.To
+				}
+			}
+		case Convert:
+			for i := range msgs {
+				msg := &msgs[i]
+				if msg.Value == nil || (s.Field != "" && msg.Name != s.Field) || (s.From != "" && msg.Unit != s.From) {
+					continue
+				}
+				if !s.satisfied(senmlLookup(*msg)) {
+					continue
+				}
+				v := s.convert(*msg.Value)
+				msg.Value = &v
+				if s.To != "" {
+					msg.Unit = s.To
+				}
+			}
+		case Enrich:
+			// Metadata records are added once per message, based on the first
+			// record satisfying the predicates.
+			for _, msg := range msgs {
+				if !s.satisfied(senmlLookup(msg)) {
+					continue
+				}
+				md, err := metadata()
+				if err != nil {
+					return nil, err
+				}
+				msgs = append(msgs, metadataRecords(msg, md, s)...)
+				break
+			}
+		}
+	}
+
+	return msgs, nil
+}
+
+func transformJSON(msgs mfjson.Messages, stages []stage, metadata func() (map[string]interface{}, error)) (mfjson.Messages, error) {
+	for _, s := range stages {
+		switch s.Type {
+		case Filter, Drop:
+			var ret []mfjson.Message
+			for _, msg := range msgs.Data {
+				if s.satisfied(jsonLookup(msg)) == (s.Type == Filter) {
+					ret = append(ret, msg)
+				}
+			}
+			msgs.Data = ret
+		case Rename:
+			for _, msg := range msgs.Data {
+				v, ok := get(msg.Payload, s.Field)
+				if !ok || !s.satisfied(jsonLookup(msg)) {
+					continue
+				}
+				del(msg.Payload, s.Field)
+				set(msg.Payload, s.To, v)
+			}
+		case Convert:
+			for _, msg := range msgs.Data {
+				v, ok := get(msg.Payload, s.Field)
+				if !ok {
+					continue
+				}
+				f, ok := toFloat(v)
+				if !ok || !s.satisfied(jsonLookup(msg)) {
+					continue
+				}
+				set(msg.Payload, s.Field, s.convert(f))
+			}
+		case Enrich:
+			for _, msg := range msgs.Data {
+				if !s.satisfied(jsonLookup(msg)) {
+					continue
+				}
+				md, err := metadata()
+				if err != nil {
+					return msgs, err
+				}
+				for _, k := range metadataKeys(md, s.Keys) {
+					msg.Payload[s.Prefix+k] = md[k]
+				}
+			}
+		}
+	}
+
+	return msgs, nil
+}
+
+// metadataRecords creates a SenML record for each of the selected metadata
+// keys, sharing the time and the message fields with the given record.
+func metadataRecords(tmpl senml.Message, md map[string]interface{}, s stage) []senml.Message {
+	var ret []senml.Message
+	for _, k := range metadataKeys(md, s.Keys) {
+		rec := senml.Message{
+			Channel:    tmpl.Channel,
+			Subtopic:   tmpl.Subtopic,
+			Publisher:  tmpl.Publisher,
+			Protocol:   tmpl.Protocol,
+			Name:       s.Prefix + k,
+			Time:       tmpl.Time,
+			UpdateTime: tmpl.UpdateTime,
+		}
+		switch v := md[k].(type) {
+		case float64:
+			rec.Value = &v
+		case string:
+			rec.StringValue = &v
+		case bool:
+			rec.BoolValue = &v
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			d := string(data)
+			rec.DataValue = &d
+		}
+		ret = append(ret, rec)
+	}
+
+	return ret
+}
+
+// metadataKeys returns the selected keys present in the metadata,
+// or all the metadata keys in alphabetical order if none is selected.
+func metadataKeys(md map[string]interface{}, keys []string) []string {
+	var ret []string
+	if len(keys) == 0 {
+		for k := range md {
+			ret = append(ret, k)
+		}
+		sort.Strings(ret)
+		return ret
+	}
+	for _, k := range keys {
+		if _, ok := md[k]; ok {
+			ret = append(ret, k)
+		}
+	}
+
+	return ret
+}
+
+func senmlLookup(msg senml.Message) func(string) (interface{}, bool) {
+	return func(field string) (interface{}, bool) {
+		switch field {
+		case "channel":
+			return msg.Channel, true
+		case "subtopic":
+			return msg.Subtopic, true
+		case "publisher":
+			return msg.Publisher, true
+		case "protocol":
+			return msg.Protocol, true
+		case "name":
+			return msg.Name, true
+		case "unit":
+			return msg.Unit, true
+		case "time":
+			return msg.Time, true
+		case "update_time":
+			return msg.UpdateTime, true
+		case "value":
+			if msg.Value != nil {
+				return *msg.Value, true
+			}
+		case "string_value":
+			if msg.StringValue != nil {
+				return *msg.StringValue, true
+			}
+		case "data_value":
+			if msg.DataValue != nil {
+				return *msg.DataValue, true
+			}
+		case "bool_value":
+			if msg.BoolValue != nil {
+				return *msg.BoolValue, true
+			}
+		case "sum":
+			if msg.Sum != nil {
+				return *msg.Sum, true
+			}
+		}
+		return nil, false
+	}
+}
+
+func jsonLookup(msg mfjson.Message) func(string) (interface{}, bool) {
+	return func(field string) (interface{}, bool) {
+		switch field {
+		case "channel":
+			return msg.Channel, true
+		case "subtopic":
+			return msg.Subtopic, true
+		case "publisher":
+			return msg.Publisher, true
+		case "protocol":
+			return msg.Protocol, true
+		case "created":
+			return msg.Created, true
+		default:
+			return get(msg.Payload, field)
+		}
+	}
+}
+
+// get returns the value of the payload field with the given dot-separated path.
+func get(payload map[string]interface{}, path string) (interface{}, bool) {
+	keys := strings.Split(path, pathSep)
+	var cur interface{} = payload
+	for _, k := range keys {
+		m, ok := asMap(cur)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[k]; !ok {
+			return nil, false
+		}
+	}
+
+	return cur, true
+}
+
+// set sets the value of the payload field with the given dot-separated
+// path, creating the intermediate objects if needed.
+func set(payload map[string]interface{}, path string, val interface{}) {
+	keys := strings.Split(path, pathSep)
+	m := payload
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := asMap(m[k])
+		if !ok {
+			next = map[string]interface{}{}
+			m[k] = next
+		}
+		m = next
+	}
+	m[keys[len(keys)-1]] = val
+}
+
+func del(payload map[string]interface{}, path string) {
+	keys := strings.Split(path, pathSep)
+	m := payload
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := asMap(m[k])
+		if !ok {
+			return
+		}
+		m = next
+	}
+	delete(m, keys[len(keys)-1])
+}
+
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case mfjson.Payload:
+		return m, true
+	default:
+		return nil, false
+	}
+}
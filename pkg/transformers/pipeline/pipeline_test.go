@@ -0,0 +1,370 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/transformers/json"
+	"github.com/mainflux/mainflux/pkg/transformers/pipeline"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chanID    = "chan"
+	publisher = "publisher"
+	protocol  = "http"
+)
+
+var errMetadata = errors.New("metadata not found")
+
+type metadataMock map[string]map[string]interface{}
+
+func (mm metadataMock) Metadata(_ context.Context, thingID string) (map[string]interface{}, error) {
+	md, ok := mm[thingID]
+	if !ok {
+		return nil, errMetadata
+	}
+	return md, nil
+}
+
+var metadata = metadataMock{
+	publisher: {
+		"location": "hall",
+		"floor":    2.0,
+		"indoor":   true,
+	},
+}
+
+func senmlRecord(name, unit string, val float64) senml.Message {
+	return senml.Message{
+		Channel:   chanID,
+		Subtopic:  "temp",
+		Publisher: publisher,
+		Protocol:  protocol,
+		Name:      name,
+		Unit:      unit,
+		Time:      100,
+		Value:     &val,
+	}
+}
+
+func TestTransformSenML(t *testing.T) {
+	payload := []byte(`[{"n":"t1","u":"Cel","t":100,"v":20},{"n":"t2","u":"Cel","t":100,"v":-60},{"n":"h","u":"%RH","t":100,"v":40}]`)
+	msg := messaging.Message{
+		Channel:   chanID,
+		Subtopic:  "temp",
+		Publisher: publisher,
+		Protocol:  protocol,
+		Payload:   payload,
+	}
+	location := "hall"
+	indoor := true
+	floor := 2.0
+
+	cases := []struct {
+		desc  string
+		rules []pipeline.Rule
+		msg   messaging.Message
+		res   interface{}
+		err   error
+	}{
+		{
+			desc: "transform without rules",
+			msg:  msg,
+			res: []senml.Message{
+				senmlRecord("t1", "Cel", 20),
+				senmlRecord("t2", "Cel", -60),
+				senmlRecord("h", "%RH", 40),
+			},
+		},
+		{
+			desc:  "drop records satisfying predicates",
+			rules: []pipeline.Rule{{Type: pipeline.Drop, Where: []string{"value < -50", `unit == "Cel"`}}},
+			msg:   msg,
+			res: []senml.Message{
+				senmlRecord("t1", "Cel", 20),
+				senmlRecord("h", "%RH", 40),
+			},
+		},
+		{
+			desc:  "filter records satisfying predicates",
+			rules: []pipeline.Rule{{Type: pipeline.Filter, Where: []string{"name != 'h'"}}},
+			msg:   msg,
+			res: []senml.Message{
+				senmlRecord("t1", "Cel", 20),
+				senmlRecord("t2", "Cel", -60),
+			},
+		},
+		{
+			desc:  "rename record",
+			rules: []pipeline.Rule{{Type: pipeline.Rename, Field: "h", To: "humidity"}},
+			msg:   msg,
+			res: []senml.Message{
+				senmlRecord("t1", "Cel", 20),
+				senmlRecord("t2", "Cel", -60),
+				senmlRecord("humidity", "%RH", 40),
+			},
+		},
+		{
+			desc:  "convert known units",
+			rules: []pipeline.Rule{{Type: pipeline.Convert, From: "Cel", To: "K"}},
+			msg:   msg,
+			res: []senml.Message{
+				senmlRecord("t1", "K", 293.15),
+				senmlRecord("t2", "K", 213.15),
+				senmlRecord("h", "%RH", 40),
+			},
+		},
+		{
+			desc:  "convert with scale and offset",
+			rules: []pipeline.Rule{{Type: pipeline.Convert, Field: "h", To: "/", Scale: 0.01}},
+			msg:   msg,
+			res: []senml.Message{
+				senmlRecord("t1", "Cel", 20),
+				senmlRecord("t2", "Cel", -60),
+				senmlRecord("h", "/", 0.4),
+			},
+		},
+		{
+			desc: "chain rules",
+			rules: []pipeline.Rule{
+				{Type: pipeline.Drop, Where: []string{"value < -50"}},
+				{Type: pipeline.Rename, Field: "t1", To: "temperature"},
+				{Type: pipeline.Filter, Where: []string{"name == 'temperature'"}},
+			},
+			msg: msg,
+			res: []senml.Message{
+				senmlRecord("temperature", "Cel", 20),
+			},
+		},
+		{
+			desc:  "skip rule of other channel",
+			rules: []pipeline.Rule{{Type: pipeline.Drop, Channel: "other"}},
+			msg:   msg,
+			res: []senml.Message{
+				senmlRecord("t1", "Cel", 20),
+				senmlRecord("t2", "Cel", -60),
+				senmlRecord("h", "%RH", 40),
+			},
+		},
+		{
+			desc:  "skip rule of other subtopic",
+			rules: []pipeline.Rule{{Type: pipeline.Drop, Channel: chanID, Subtopic: "temp.*"}},
+			msg:   msg,
+			res: []senml.Message{
+				senmlRecord("t1", "Cel", 20),
+				senmlRecord("t2", "Cel", -60),
+				senmlRecord("h", "%RH", 40),
+			},
+		},
+		{
+			desc:  "apply rule of subtopic matching wildcard",
+			rules: []pipeline.Rule{{Type: pipeline.Drop, Subtopic: ">"}},
+			msg:   msg,
+			res:   []senml.Message(nil),
+		},
+		{
+			desc:  "enrich with selected metadata keys",
+			rules: []pipeline.Rule{{Type: pipeline.Enrich, Keys: []string{"location", "missing"}, Prefix: "md_"}},
+			msg:   msg,
+			res: []senml.Message{
+				senmlRecord("t1", "Cel", 20),
+				senmlRecord("t2", "Cel", -60),
+				senmlRecord("h", "%RH", 40),
+				{Channel: chanID, Subtopic: "temp", Publisher: publisher, Protocol: protocol, Name: "md_location", Time: 100, StringValue: &location},
+			},
+		},
+		{
+			desc:  "enrich with all metadata keys",
+			rules: []pipeline.Rule{{Type: pipeline.Enrich, Where: []string{"name == 'h'"}}},
+			msg:   msg,
+			res: []senml.Message{
+				senmlRecord("t1", "Cel", 20),
+				senmlRecord("t2", "Cel", -60),
+				senmlRecord("h", "%RH", 40),
+				{Channel: chanID, Subtopic: "temp", Publisher: publisher, Protocol: protocol, Name: "floor", Time: 100, Value: &floor},
+				{Channel: chanID, Subtopic: "temp", Publisher: publisher, Protocol: protocol, Name: "indoor", Time: 100, BoolValue: &indoor},
+				{Channel: chanID, Subtopic: "temp", Publisher: publisher, Protocol: protocol, Name: "location", Time: 100, StringValue: &location},
+			},
+		},
+		{
+			desc:  "enrich message of unknown publisher",
+			rules: []pipeline.Rule{{Type: pipeline.Enrich}},
+			msg: messaging.Message{
+				Channel:   chanID,
+				Subtopic:  "temp",
+				Publisher: "unknown",
+				Payload:   payload,
+			},
+			res: nil,
+			err: errMetadata,
+		},
+	}
+
+	for _, tc := range cases {
+		p, err := pipeline.New(senml.New(senml.JSON), tc.rules, metadata)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		res, err := p.Transform(tc.msg)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+		assertSenML(t, tc.desc, tc.res.([]senml.Message), res.([]senml.Message))
+	}
+}
+
+func assertSenML(t *testing.T, desc string, expected, actual []senml.Message) {
+	require.Equal(t, len(expected), len(actual), fmt.Sprintf("%s: expected %d records got %d", desc, len(expected), len(actual)))
+	for i := range expected {
+		if expected[i].Value != nil && actual[i].Value != nil {
+			assert.InDelta(t, *expected[i].Value, *actual[i].Value, 1e-9, fmt.Sprintf("%s: expected value %f got %f", desc, *expected[i].Value, *actual[i].Value))
+			e, a := expected[i], actual[i]
+			e.Value, a.Value = nil, nil
+			assert.Equal(t, e, a, fmt.Sprintf("%s: expected %+v got %+v", desc, e, a))
+			continue
+		}
+		assert.Equal(t, expected[i], actual[i], fmt.Sprintf("%s: expected %+v got %+v", desc, expected[i], actual[i]))
+	}
+}
+
+func TestTransformJSON(t *testing.T) {
+	msg := messaging.Message{
+		Channel:   chanID,
+		Subtopic:  "sensors.json",
+		Publisher: publisher,
+		Protocol:  protocol,
+		Payload:   []byte(`[{"temp":{"value":68,"unit":"degF"},"status":"ok"},{"temp":{"value":-80,"unit":"degF"},"status":"fault"}]`),
+	}
+
+	cases := []struct {
+		desc  string
+		rules []pipeline.Rule
+		res   []json.Payload
+	}{
+		{
+			desc: "transform without rules",
+			res: []json.Payload{
+				{"temp": map[string]interface{}{"value": 68.0, "unit": "degF"}, "status": "ok"},
+				{"temp": map[string]interface{}{"value": -80.0, "unit": "degF"}, "status": "fault"},
+			},
+		},
+		{
+			desc:  "drop messages satisfying predicates",
+			rules: []pipeline.Rule{{Type: pipeline.Drop, Where: []string{"status == 'fault'"}}},
+			res: []json.Payload{
+				{"temp": map[string]interface{}{"value": 68.0, "unit": "degF"}, "status": "ok"},
+			},
+		},
+		{
+			desc:  "filter messages by nested field",
+			rules: []pipeline.Rule{{Type: pipeline.Filter, Where: []string{"temp.value <= -80"}}},
+			res: []json.Payload{
+				{"temp": map[string]interface{}{"value": -80.0, "unit": "degF"}, "status": "fault"},
+			},
+		},
+		{
+			desc:  "rename nested field",
+			rules: []pipeline.Rule{{Type: pipeline.Rename, Field: "temp.value", To: "temperature"}},
+			res: []json.Payload{
+				{"temp": map[string]interface{}{"unit": "degF"}, "temperature": 68.0, "status": "ok"},
+				{"temp": map[string]interface{}{"unit": "degF"}, "temperature": -80.0, "status": "fault"},
+			},
+		},
+		{
+			desc:  "convert field satisfying predicates",
+			rules: []pipeline.Rule{{Type: pipeline.Convert, Field: "temp.value", From: "degF", To: "Cel", Where: []string{"status == 'ok'"}}},
+			res: []json.Payload{
+				{"temp": map[string]interface{}{"value": 20.0, "unit": "degF"}, "status": "ok"},
+				{"temp": map[string]interface{}{"value": -80.0, "unit": "degF"}, "status": "fault"},
+			},
+		},
+		{
+			desc:  "enrich with metadata",
+			rules: []pipeline.Rule{{Type: pipeline.Enrich, Keys: []string{"location"}, Prefix: "thing_"}},
+			res: []json.Payload{
+				{"temp": map[string]interface{}{"value": 68.0, "unit": "degF"}, "status": "ok", "thing_location": "hall"},
+				{"temp": map[string]interface{}{"value": -80.0, "unit": "degF"}, "status": "fault", "thing_location": "hall"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		p, err := pipeline.New(json.New(nil), tc.rules, metadata)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		res, err := p.Transform(msg)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		msgs := res.(json.Messages)
+		require.Equal(t, len(tc.res), len(msgs.Data), fmt.Sprintf("%s: expected %d messages got %d", tc.desc, len(tc.res), len(msgs.Data)))
+		for i, pld := range tc.res {
+			if v, ok := pld["temp"].(map[string]interface{})["value"].(float64); ok {
+				actual := msgs.Data[i].Payload["temp"].(map[string]interface{})["value"].(float64)
+				assert.InDelta(t, v, actual, 1e-9, fmt.Sprintf("%s: expected %f got %f", tc.desc, v, actual))
+				pld["temp"].(map[string]interface{})["value"] = actual
+			}
+			assert.Equal(t, pld, msgs.Data[i].Payload, fmt.Sprintf("%s: expected %v got %v", tc.desc, pld, msgs.Data[i].Payload))
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	cases := []struct {
+		desc     string
+		rule     pipeline.Rule
+		metadata pipeline.Metadata
+		err      error
+	}{
+		{
+			desc: "create pipeline with valid rule",
+			rule: pipeline.Rule{Type: pipeline.Drop, Where: []string{"value >= 10", "name == 'temp'", "bool_value != false"}},
+			err:  nil,
+		},
+		{
+			desc: "create pipeline with unknown rule type",
+			rule: pipeline.Rule{Type: "unknown"},
+			err:  pipeline.ErrInvalidRule,
+		},
+		{
+			desc: "create pipeline with malformed predicate",
+			rule: pipeline.Rule{Type: pipeline.Drop, Where: []string{"value ~ 10"}},
+			err:  pipeline.ErrInvalidRule,
+		},
+		{
+			desc: "create pipeline with invalid predicate literal",
+			rule: pipeline.Rule{Type: pipeline.Drop, Where: []string{"name == temp"}},
+			err:  pipeline.ErrInvalidRule,
+		},
+		{
+			desc: "create pipeline with invalid subtopic pattern",
+			rule: pipeline.Rule{Type: pipeline.Drop, Subtopic: "a.>.b"},
+			err:  pipeline.ErrInvalidRule,
+		},
+		{
+			desc: "create pipeline with rename rule without target",
+			rule: pipeline.Rule{Type: pipeline.Rename, Field: "temp"},
+			err:  pipeline.ErrInvalidRule,
+		},
+		{
+			desc: "create pipeline with incompatible units",
+			rule: pipeline.Rule{Type: pipeline.Convert, From: "Cel", To: "m"},
+			err:  pipeline.ErrInvalidRule,
+		},
+		{
+			desc: "create pipeline with enrich rule without metadata",
+			rule: pipeline.Rule{Type: pipeline.Enrich},
+			err:  pipeline.ErrMissingMetadata,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := pipeline.New(senml.New(senml.JSON), []pipeline.Rule{tc.rule}, tc.metadata)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s", tc.desc, tc.err, err))
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var (
+	errInvalidPredicate = errors.New("invalid predicate")
+	errInvalidLiteral   = errors.New("invalid predicate literal")
+
+	predicateRegexp = regexp.MustCompile(`^\s*([\w\-.]+)\s*(==|!=|<=|>=|<|>)\s*(.+?)\s*$`)
+)
+
+// predicate compares the value of the record field to the literal.
+type predicate struct {
+	field string
+	op    string
+	value interface{}
+}
+
+func parsePredicate(expr string) (predicate, error) {
+	m := predicateRegexp.FindStringSubmatch(expr)
+	if m == nil {
+		return predicate{}, errInvalidPredicate
+	}
+
+	val, err := parseLiteral(m[3])
+	if err != nil {
+		return predicate{}, err
+	}
+
+	return predicate{field: m[1], op: m[2], value: val}, nil
+}
+
+func parseLiteral(lit string) (interface{}, error) {
+	if len(lit) >= 2 && (lit[0] == '"' || lit[0] == '\'') && lit[len(lit)-1] == lit[0] {
+		return lit[1 : len(lit)-1], nil
+	}
+	if b, err := strconv.ParseBool(lit); err == nil {
+		return b, nil
+	}
+	if f, err := strconv.ParseFloat(lit, 64); err == nil {
+		return f, nil
+	}
+	return nil, errInvalidLiteral
+}
+
+// eval evaluates the predicate against the record. Predicates referring
+// to a missing field or comparing values of different types are not satisfied.
+func (p predicate) eval(lookup func(string) (interface{}, bool)) bool {
+	v, ok := lookup(p.field)
+	if !ok {
+		return false
+	}
+
+	switch lit := p.value.(type) {
+	case float64:
+		f, ok := toFloat(v)
+		if !ok {
+			return false
+		}
+		return compare(p.op, cmpFloat(f, lit))
+	case string:
+		s, ok := v.(string)
+		if !ok {
+			return false
+		}
+		return compare(p.op, strings.Compare(s, lit))
+	case bool:
+		b, ok := v.(bool)
+		if !ok {
+			return false
+		}
+		switch p.op {
+		case "==":
+			return b == lit
+		case "!=":
+			return b != lit
+		}
+	}
+
+	return false
+}
+
+func compare(op string, res int) bool {
+	switch op {
+	case "==":
+		return res == 0
+	case "!=":
+		return res != 0
+	case "<":
+		return res < 0
+	case "<=":
+		return res <= 0
+	case ">":
+		return res > 0
+	case ">=":
+		return res >= 0
+	default:
+		return false
+	}
+}
+
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"strings"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// Rule types.
+const (
+	// Filter keeps only the records matching the rule predicates.
+	Filter = "filter"
+	// Drop removes the records matching the rule predicates.
+	Drop = "drop"
+	// Rename renames the record (SenML) or payload field (JSON).
+	Rename = "rename"
+	// Convert converts the record value (SenML) or payload field (JSON) to another unit.
+	Convert = "convert"
+	// Enrich adds the metadata of the publishing thing to the message.
+	Enrich = "enrich"
+)
+
+var (
+	// ErrInvalidRule indicates malformed rule definition.
+	ErrInvalidRule = errors.New("invalid pipeline rule")
+
+	errUnknownType    = errors.New("unknown rule type")
+	errMissingField   = errors.New("missing rule field")
+	errMissingTarget  = errors.New("missing rule target")
+	errUnknownUnits   = errors.New("unknown or incompatible units and no scale given")
+	errInvalidPattern = errors.New("invalid subtopic pattern")
+)
+
+// Rule represents a single pipeline stage. Rule is applied only to the
+// messages published to the given channel and subtopic and only to the
+// records satisfying all the Where predicates.
+type Rule struct {
+	// Type is one of filter, drop, rename, convert or enrich.
+	Type string `toml:"type"`
+
+	// Channel limits the rule to the messages of the channel with given ID.
+	// Rule applies to all the channels if empty.
+	Channel string `toml:"channel"`
+
+	// Subtopic limits the rule to the messages of the given subtopic. Subtopic
+	// tokens are separated by dots and support "*" and ">" wildcards.
+	// Rule applies to all the subtopics if empty.
+	Subtopic string `toml:"subtopic"`
+
+	// Where contains predicates in the form "<field> <op> <literal>", where
+	// op is one of ==, !=, <, <=, > and >=. Literal is a number, a quoted
+	// string, or a boolean. All the predicates must be satisfied.
+	Where []string `toml:"where"`
+
+	// Field is the SenML record name or the JSON payload field the rule
+	// renames or converts. JSON payload fields are addressed by the path
+	// of dot-separated keys.
+	Field string `toml:"field"`
+
+	// To is the new name of the renamed field, or the unit
+	// the converted value is expressed in.
+	To string `toml:"to"`
+
+	// From is the unit of the converted value. SenML records
+	// are converted only if their unit is equal to From.
+	From string `toml:"from"`
+
+	// Scale and Offset are used to convert between the units unknown to the
+	// pipeline, so that converted = value * scale + offset. Scale is ignored
+	// if both units are known.
+	Scale  float64 `toml:"scale"`
+	Offset float64 `toml:"offset"`
+
+	// Keys are the thing metadata keys added to the message. All the
+	// top-level metadata keys are added if empty.
+	Keys []string `toml:"keys"`
+
+	// Prefix is prepended to the names of the added metadata fields.
+	Prefix string `toml:"prefix"`
+}
+
+// stage is a validated and compiled rule.
+type stage struct {
+	Rule
+	where   []predicate
+	convert func(float64) float64
+}
+
+func compile(r Rule) (stage, error) {
+	s := stage{Rule: r}
+	s.Type = strings.ToLower(r.Type)
+
+	if !validPattern(r.Subtopic) {
+		return s, errors.Wrap(ErrInvalidRule, errInvalidPattern)
+	}
+
+	for _, w := range r.Where {
+		p, err := parsePredicate(w)
+		if err != nil {
+			return s, errors.Wrap(ErrInvalidRule, err)
+		}
+		s.where = append(s.where, p)
+	}
+
+	switch s.Type {
+	case Filter, Drop, Enrich:
+	case Rename:
+		if r.Field == "" {
+			return s, errors.Wrap(ErrInvalidRule, errMissingField)
+		}
+		if r.To == "" {
+			return s, errors.Wrap(ErrInvalidRule, errMissingTarget)
+		}
+	case Convert:
+		if r.Field == "" && r.From == "" {
+			return s, errors.Wrap(ErrInvalidRule, errMissingField)
+		}
+		conv, ok := conversion(r.From, r.To)
+		if !ok {
+			if r.Scale == 0 {
+				return s, errors.Wrap(ErrInvalidRule, errUnknownUnits)
+			}
+			scale, offset := r.Scale, r.Offset
+			conv = func(v float64) float64 { return v*scale + offset }
+		}
+		s.convert = conv
+	default:
+		return s, errors.Wrap(ErrInvalidRule, errUnknownType)
+	}
+
+	return s, nil
+}
+
+// applies checks if the rule applies to the messages of the given channel and subtopic.
+func (s stage) applies(channel, subtopic string) bool {
+	if s.Channel != "" && s.Channel != channel {
+		return false
+	}
+	return s.Subtopic == "" || matchSubtopic(s.Subtopic, subtopic)
+}
+
+// satisfied checks if the record satisfies all the rule predicates.
+func (s stage) satisfied(lookup func(string) (interface{}, bool)) bool {
+	for _, p := range s.where {
+		if !p.eval(lookup) {
+			return false
+		}
+	}
+	return true
+}
+
+func validPattern(pattern string) bool {
+	if pattern == "" {
+		return true
+	}
+	tokens := strings.Split(pattern, ".")
+	for i, t := range tokens {
+		if t == "" || (t == ">" && i != len(tokens)-1) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchSubtopic matches the dot-separated subtopic against the pattern
+// which may contain "*" (single token) and ">" (one or more tokens) wildcards.
+func matchSubtopic(pattern, subtopic string) bool {
+	pt := strings.Split(pattern, ".")
+	if subtopic == "" {
+		return false
+	}
+	st := strings.Split(subtopic, ".")
+	for i, p := range pt {
+		if p == ">" {
+			return len(st) > i
+		}
+		if i >= len(st) || (p != "*" && p != st[i]) {
+			return false
+		}
+	}
+	return len(pt) == len(st)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+// unit describes the linear conversion of the unit to the base unit
+// of its dimension, so that base = value * scale + offset.
+type unit struct {
+	dimension string
+	scale     float64
+	offset    float64
+}
+
+// units contains the SenML units (RFC 8428 and RFC 8798) supported by the
+// convert rule, along with a few widely used non-SenML ones.
+var units = map[string]unit{
+	"K":    {"temperature", 1, 0},
+	"Cel":  {"temperature", 1, 273.15},
+	"degF": {"temperature", 5.0 / 9, 459.67 * 5 / 9},
+
+	"m":  {"length", 1, 0},
+	"km": {"length", 1e3, 0},
+	"cm": {"length", 1e-2, 0},
+	"mm": {"length", 1e-3, 0},
+
+	"kg": {"mass", 1, 0},
+	"g":  {"mass", 1e-3, 0},
+
+	"s":   {"time", 1, 0},
+	"ms":  {"time", 1e-3, 0},
+	"min": {"time", 60, 0},
+	"h":   {"time", 3600, 0},
+
+	"Pa":  {"pressure", 1, 0},
+	"hPa": {"pressure", 1e2, 0},
+	"kPa": {"pressure", 1e3, 0},
+	"bar": {"pressure", 1e5, 0},
+
+	"J":   {"energy", 1, 0},
+	"Wh":  {"energy", 3600, 0},
+	"kWh": {"energy", 3.6e6, 0},
+
+	"W":  {"power", 1, 0},
+	"kW": {"power", 1e3, 0},
+
+	"m/s":  {"velocity", 1, 0},
+	"km/h": {"velocity", 1 / 3.6, 0},
+
+	"/": {"ratio", 1, 0},
+	"%": {"ratio", 1e-2, 0},
+}
+
+// conversion returns the function converting the values from one unit
+// to another, if both units are known and of the same dimension.
+func conversion(from, to string) (func(float64) float64, bool) {
+	f, ok := units[from]
+	if !ok {
+		return nil, false
+	}
+	t, ok := units[to]
+	if !ok || f.dimension != t.dimension {
+		return nil, false
+	}
+
+	return func(v float64) float64 {
+		base := v*f.scale + f.offset
+		return (base - t.offset) / t.scale
+	}, true
+}
@@ -15,23 +15,65 @@ const (
 	JSON = "application/senml+json"
 	// CBOR represents SenML in CBOR format content type.
 	CBOR = "application/senml+cbor"
+	// XML represents SenML in XML format content type.
+	XML = "application/senml+xml"
 )
 
 var (
+	// ErrUnsupportedFormat indicates the content type that isn't SenML.
+	ErrUnsupportedFormat = errors.New("unsupported senml content type")
+
 	errDecode    = errors.New("failed to decode senml")
+	errEncode    = errors.New("failed to encode senml")
 	errNormalize = errors.New("failed to normalize senml")
 )
 
 var formats = map[string]senml.Format{
 	JSON: senml.JSON,
 	CBOR: senml.CBOR,
+	XML:  senml.XML,
+}
+
+// Format returns the SenML format of the given content type, and whether
+// the content type is SenML at all.
+func Format(contentType string) (senml.Format, bool) {
+	format, ok := formats[contentType]
+	return format, ok
+}
+
+// Convert converts the SenML payload between the given content types.
+func Convert(payload []byte, from, to string) ([]byte, error) {
+	src, ok := formats[from]
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+	dst, ok := formats[to]
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+	if src == dst {
+		return payload, nil
+	}
+
+	p, err := senml.Decode(payload, src)
+	if err != nil {
+		return nil, errors.Wrap(errDecode, err)
+	}
+	ret, err := senml.Encode(p, dst)
+	if err != nil {
+		return nil, errors.Wrap(errEncode, err)
+	}
+
+	return ret, nil
 }
 
 type transformer struct {
 	format senml.Format
 }
 
-// New returns transformer service implementation for SenML messages.
+// New returns transformer service implementation for SenML messages. The
+// messages are decoded using their content type if it's SenML, and using
+// the given content format otherwise.
 func New(contentFormat string) transformers.Transformer {
 	format, ok := formats[contentFormat]
 	if !ok {
@@ -44,7 +86,12 @@ func New(contentFormat string) transformers.Transformer {
 }
 
 func (t transformer) Transform(msg messaging.Message) (interface{}, error) {
-	raw, err := senml.Decode(msg.Payload, t.format)
+	format, ok := formats[msg.ContentType]
+	if !ok {
+		format = t.format
+	}
+
+	raw, err := senml.Decode(msg.Payload, format)
 	if err != nil {
 		return nil, errors.Wrap(errDecode, err)
 	}
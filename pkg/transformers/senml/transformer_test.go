@@ -151,3 +151,119 @@ func TestTransformCBOR(t *testing.T) {
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s expected %s, got %s", tc.desc, tc.err, err))
 	}
 }
+
+func TestTransformXML(t *testing.T) {
+	xmlBytes := []byte(`<sensml xmlns="urn:ietf:params:xml:ns:senml"><senml bn="base-name" bt="100" bu="base-unit" bver="10" bv="10" bs="100" n="name" u="unit" t="300" ut="150" v="42" s="10"></senml></sensml>`)
+
+	msg := messaging.Message{
+		Channel:   "channel",
+		Subtopic:  "subtopic",
+		Publisher: "publisher",
+		Protocol:  "protocol",
+		Payload:   xmlBytes,
+	}
+
+	typedMsg := msg
+	typedMsg.ContentType = senml.XML
+
+	val := 52.0
+	sum := 110.0
+	msgs := []senml.Message{
+		{
+			Channel:    "channel",
+			Subtopic:   "subtopic",
+			Publisher:  "publisher",
+			Protocol:   "protocol",
+			Name:       "base-namename",
+			Unit:       "unit",
+			Time:       400,
+			UpdateTime: 150,
+			Value:      &val,
+			Sum:        &sum,
+		},
+	}
+
+	cases := []struct {
+		desc string
+		msg  messaging.Message
+		ct   string
+		msgs interface{}
+		err  bool
+	}{
+		{
+			desc: "test normalize XML",
+			msg:  msg,
+			ct:   senml.XML,
+			msgs: msgs,
+		},
+		{
+			desc: "test normalize XML message using its content type",
+			msg:  typedMsg,
+			ct:   senml.JSON,
+			msgs: msgs,
+		},
+		{
+			desc: "test normalize XML message as JSON",
+			msg:  msg,
+			ct:   senml.JSON,
+			msgs: nil,
+			err:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		msgs, err := senml.New(tc.ct).Transform(tc.msg)
+		assert.Equal(t, tc.msgs, msgs, fmt.Sprintf("%s expected %v, got %v", tc.desc, tc.msgs, msgs))
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s expected error %t, got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestConvert(t *testing.T) {
+	payload := []byte(`[{"bn":"base-name","n":"name","u":"unit","t":300,"v":42}]`)
+
+	cases := []struct {
+		desc string
+		from string
+		to   string
+		err  error
+	}{
+		{
+			desc: "convert JSON to CBOR",
+			from: senml.JSON,
+			to:   senml.CBOR,
+		},
+		{
+			desc: "convert JSON to XML",
+			from: senml.JSON,
+			to:   senml.XML,
+		},
+		{
+			desc: "convert JSON to JSON",
+			from: senml.JSON,
+			to:   senml.JSON,
+		},
+		{
+			desc: "convert JSON to unsupported content type",
+			from: senml.JSON,
+			to:   "application/json",
+			err:  senml.ErrUnsupportedFormat,
+		},
+		{
+			desc: "convert from unsupported content type",
+			from: "text/plain",
+			to:   senml.CBOR,
+			err:  senml.ErrUnsupportedFormat,
+		},
+	}
+
+	for _, tc := range cases {
+		converted, err := senml.Convert(payload, tc.from, tc.to)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		back, err := senml.Convert(converted, tc.to, tc.from)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error converting back: %s", tc.desc, err))
+		assert.JSONEq(t, string(payload), string(back), fmt.Sprintf("%s: expected %s got %s", tc.desc, payload, back))
+	}
+}
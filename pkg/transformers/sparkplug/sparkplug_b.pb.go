@@ -0,0 +1,1497 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: pkg/transformers/sparkplug/sparkplug_b.proto
+
+package sparkplug
+
+import (
+	encoding_binary "encoding/binary"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	io "io"
+	math "math"
+	math_bits "math/bits"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// DataType represents the Sparkplug B metric data type.
+type DataType int32
+
+const (
+	DataType_Unknown  DataType = 0
+	DataType_Int8     DataType = 1
+	DataType_Int16    DataType = 2
+	DataType_Int32    DataType = 3
+	DataType_Int64    DataType = 4
+	DataType_UInt8    DataType = 5
+	DataType_UInt16   DataType = 6
+	DataType_UInt32   DataType = 7
+	DataType_UInt64   DataType = 8
+	DataType_Float    DataType = 9
+	DataType_Double   DataType = 10
+	DataType_Boolean  DataType = 11
+	DataType_String   DataType = 12
+	DataType_DateTime DataType = 13
+	DataType_Text     DataType = 14
+	DataType_UUID     DataType = 15
+	DataType_DataSet  DataType = 16
+	DataType_Bytes    DataType = 17
+	DataType_File     DataType = 18
+	DataType_Template DataType = 19
+)
+
+var DataType_name = map[int32]string{
+	0:  "Unknown",
+	1:  "Int8",
+	2:  "Int16",
+	3:  "Int32",
+	4:  "Int64",
+	5:  "UInt8",
+	6:  "UInt16",
+	7:  "UInt32",
+	8:  "UInt64",
+	9:  "Float",
+	10: "Double",
+	11: "Boolean",
+	12: "String",
+	13: "DateTime",
+	14: "Text",
+	15: "UUID",
+	16: "DataSet",
+	17: "Bytes",
+	18: "File",
+	19: "Template",
+}
+
+var DataType_value = map[string]int32{
+	"Unknown":  0,
+	"Int8":     1,
+	"Int16":    2,
+	"Int32":    3,
+	"Int64":    4,
+	"UInt8":    5,
+	"UInt16":   6,
+	"UInt32":   7,
+	"UInt64":   8,
+	"Float":    9,
+	"Double":   10,
+	"Boolean":  11,
+	"String":   12,
+	"DateTime": 13,
+	"Text":     14,
+	"UUID":     15,
+	"DataSet":  16,
+	"Bytes":    17,
+	"File":     18,
+	"Template": 19,
+}
+
+func (x DataType) Enum() *DataType {
+	p := new(DataType)
+	*p = x
+	return p
+}
+
+func (x DataType) String() string {
+	return proto.EnumName(DataType_name, int32(x))
+}
+
+func (x *DataType) UnmarshalJSON(data []byte) error {
+	value, err := proto.UnmarshalJSONEnum(DataType_value, data, "DataType")
+	if err != nil {
+		return err
+	}
+	*x = DataType(value)
+	return nil
+}
+
+func (DataType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_a9f959b818ce267c, []int{0}
+}
+
+// Payload represents the Sparkplug B message payload.
+type Payload struct {
+	Timestamp            *uint64           `protobuf:"varint,1,opt,name=timestamp" json:"timestamp,omitempty"`
+	Metrics              []*Payload_Metric `protobuf:"bytes,2,rep,name=metrics" json:"metrics,omitempty"`
+	Seq                  *uint64           `protobuf:"varint,3,opt,name=seq" json:"seq,omitempty"`
+	Uuid                 *string           `protobuf:"bytes,4,opt,name=uuid" json:"uuid,omitempty"`
+	Body                 []byte            `protobuf:"bytes,5,opt,name=body" json:"body,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *Payload) Reset()         { *m = Payload{} }
+func (m *Payload) String() string { return proto.CompactTextString(m) }
+func (*Payload) ProtoMessage()    {}
+func (*Payload) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a9f959b818ce267c, []int{0}
+}
+func (m *Payload) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Payload) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Payload.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Payload) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Payload.Merge(m, src)
+}
+func (m *Payload) XXX_Size() int {
+	return m.Size()
+}
+func (m *Payload) XXX_DiscardUnknown() {
+	xxx_messageInfo_Payload.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Payload proto.InternalMessageInfo
+
+func (m *Payload) GetTimestamp() uint64 {
+	if m != nil && m.Timestamp != nil {
+		return *m.Timestamp
+	}
+	return 0
+}
+
+func (m *Payload) GetMetrics() []*Payload_Metric {
+	if m != nil {
+		return m.Metrics
+	}
+	return nil
+}
+
+func (m *Payload) GetSeq() uint64 {
+	if m != nil && m.Seq != nil {
+		return *m.Seq
+	}
+	return 0
+}
+
+func (m *Payload) GetUuid() string {
+	if m != nil && m.Uuid != nil {
+		return *m.Uuid
+	}
+	return ""
+}
+
+func (m *Payload) GetBody() []byte {
+	if m != nil {
+		return m.Body
+	}
+	return nil
+}
+
+type Payload_Metric struct {
+	Name         *string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Alias        *uint64 `protobuf:"varint,2,opt,name=alias" json:"alias,omitempty"`
+	Timestamp    *uint64 `protobuf:"varint,3,opt,name=timestamp" json:"timestamp,omitempty"`
+	Datatype     *uint32 `protobuf:"varint,4,opt,name=datatype" json:"datatype,omitempty"`
+	IsHistorical *bool   `protobuf:"varint,5,opt,name=is_historical,json=isHistorical" json:"is_historical,omitempty"`
+	IsTransient  *bool   `protobuf:"varint,6,opt,name=is_transient,json=isTransient" json:"is_transient,omitempty"`
+	IsNull       *bool   `protobuf:"varint,7,opt,name=is_null,json=isNull" json:"is_null,omitempty"`
+	// Types that are valid to be assigned to Value:
+	//	*Payload_Metric_IntValue
+	//	*Payload_Metric_LongValue
+	//	*Payload_Metric_FloatValue
+	//	*Payload_Metric_DoubleValue
+	//	*Payload_Metric_BooleanValue
+	//	*Payload_Metric_StringValue
+	//	*Payload_Metric_BytesValue
+	Value                isPayload_Metric_Value `protobuf_oneof:"value"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *Payload_Metric) Reset()         { *m = Payload_Metric{} }
+func (m *Payload_Metric) String() string { return proto.CompactTextString(m) }
+func (*Payload_Metric) ProtoMessage()    {}
+func (*Payload_Metric) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a9f959b818ce267c, []int{0, 0}
+}
+func (m *Payload_Metric) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Payload_Metric) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Payload_Metric.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Payload_Metric) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Payload_Metric.Merge(m, src)
+}
+func (m *Payload_Metric) XXX_Size() int {
+	return m.Size()
+}
+func (m *Payload_Metric) XXX_DiscardUnknown() {
+	xxx_messageInfo_Payload_Metric.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Payload_Metric proto.InternalMessageInfo
+
+type isPayload_Metric_Value interface {
+	isPayload_Metric_Value()
+	MarshalTo([]byte) (int, error)
+	Size() int
+}
+
+type Payload_Metric_IntValue struct {
+	IntValue uint32 `protobuf:"varint,10,opt,name=int_value,json=intValue,oneof" json:"int_value,omitempty"`
+}
+type Payload_Metric_LongValue struct {
+	LongValue uint64 `protobuf:"varint,11,opt,name=long_value,json=longValue,oneof" json:"long_value,omitempty"`
+}
+type Payload_Metric_FloatValue struct {
+	FloatValue float32 `protobuf:"fixed32,12,opt,name=float_value,json=floatValue,oneof" json:"float_value,omitempty"`
+}
+type Payload_Metric_DoubleValue struct {
+	DoubleValue float64 `protobuf:"fixed64,13,opt,name=double_value,json=doubleValue,oneof" json:"double_value,omitempty"`
+}
+type Payload_Metric_BooleanValue struct {
+	BooleanValue bool `protobuf:"varint,14,opt,name=boolean_value,json=booleanValue,oneof" json:"boolean_value,omitempty"`
+}
+type Payload_Metric_StringValue struct {
+	StringValue string `protobuf:"bytes,15,opt,name=string_value,json=stringValue,oneof" json:"string_value,omitempty"`
+}
+type Payload_Metric_BytesValue struct {
+	BytesValue []byte `protobuf:"bytes,16,opt,name=bytes_value,json=bytesValue,oneof" json:"bytes_value,omitempty"`
+}
+
+func (*Payload_Metric_IntValue) isPayload_Metric_Value()     {}
+func (*Payload_Metric_LongValue) isPayload_Metric_Value()    {}
+func (*Payload_Metric_FloatValue) isPayload_Metric_Value()   {}
+func (*Payload_Metric_DoubleValue) isPayload_Metric_Value()  {}
+func (*Payload_Metric_BooleanValue) isPayload_Metric_Value() {}
+func (*Payload_Metric_StringValue) isPayload_Metric_Value()  {}
+func (*Payload_Metric_BytesValue) isPayload_Metric_Value()   {}
+
+func (m *Payload_Metric) GetValue() isPayload_Metric_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *Payload_Metric) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *Payload_Metric) GetAlias() uint64 {
+	if m != nil && m.Alias != nil {
+		return *m.Alias
+	}
+	return 0
+}
+
+func (m *Payload_Metric) GetTimestamp() uint64 {
+	if m != nil && m.Timestamp != nil {
+		return *m.Timestamp
+	}
+	return 0
+}
+
+func (m *Payload_Metric) GetDatatype() uint32 {
+	if m != nil && m.Datatype != nil {
+		return *m.Datatype
+	}
+	return 0
+}
+
+func (m *Payload_Metric) GetIsHistorical() bool {
+	if m != nil && m.IsHistorical != nil {
+		return *m.IsHistorical
+	}
+	return false
+}
+
+func (m *Payload_Metric) GetIsTransient() bool {
+	if m != nil && m.IsTransient != nil {
+		return *m.IsTransient
+	}
+	return false
+}
+
+func (m *Payload_Metric) GetIsNull() bool {
+	if m != nil && m.IsNull != nil {
+		return *m.IsNull
+	}
+	return false
+}
+
+func (m *Payload_Metric) GetIntValue() uint32 {
+	if x, ok := m.GetValue().(*Payload_Metric_IntValue); ok {
+		return x.IntValue
+	}
+	return 0
+}
+
+func (m *Payload_Metric) GetLongValue() uint64 {
+	if x, ok := m.GetValue().(*Payload_Metric_LongValue); ok {
+		return x.LongValue
+	}
+	return 0
+}
+
+func (m *Payload_Metric) GetFloatValue() float32 {
+	if x, ok := m.GetValue().(*Payload_Metric_FloatValue); ok {
+		return x.FloatValue
+	}
+	return 0
+}
+
+func (m *Payload_Metric) GetDoubleValue() float64 {
+	if x, ok := m.GetValue().(*Payload_Metric_DoubleValue); ok {
+		return x.DoubleValue
+	}
+	return 0
+}
+
+func (m *Payload_Metric) GetBooleanValue() bool {
+	if x, ok := m.GetValue().(*Payload_Metric_BooleanValue); ok {
+		return x.BooleanValue
+	}
+	return false
+}
+
+func (m *Payload_Metric) GetStringValue() string {
+	if x, ok := m.GetValue().(*Payload_Metric_StringValue); ok {
+		return x.StringValue
+	}
+	return ""
+}
+
+func (m *Payload_Metric) GetBytesValue() []byte {
+	if x, ok := m.GetValue().(*Payload_Metric_BytesValue); ok {
+		return x.BytesValue
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Payload_Metric) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Payload_Metric_IntValue)(nil),
+		(*Payload_Metric_LongValue)(nil),
+		(*Payload_Metric_FloatValue)(nil),
+		(*Payload_Metric_DoubleValue)(nil),
+		(*Payload_Metric_BooleanValue)(nil),
+		(*Payload_Metric_StringValue)(nil),
+		(*Payload_Metric_BytesValue)(nil),
+	}
+}
+
+func init() {
+	proto.RegisterEnum("sparkplug.DataType", DataType_name, DataType_value)
+	proto.RegisterType((*Payload)(nil), "sparkplug.Payload")
+	proto.RegisterType((*Payload_Metric)(nil), "sparkplug.Payload.Metric")
+}
+
+func init() {
+	proto.RegisterFile("pkg/transformers/sparkplug/sparkplug_b.proto", fileDescriptor_a9f959b818ce267c)
+}
+
+var fileDescriptor_a9f959b818ce267c = []byte{
+	// 578 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x91, 0xdf, 0x6e, 0xd3, 0x30,
+	0x14, 0xc6, 0x93, 0xf5, 0x4f, 0x9a, 0x93, 0x74, 0x33, 0x06, 0x89, 0x30, 0x41, 0xe9, 0x98, 0x90,
+	0x2a, 0x84, 0x3a, 0xb1, 0x4d, 0x13, 0xd7, 0xd5, 0x34, 0x75, 0x17, 0x20, 0x94, 0xb5, 0xdc, 0x56,
+	0xee, 0xea, 0x0d, 0x6b, 0x8e, 0x1d, 0x62, 0x07, 0xe8, 0x9b, 0xf0, 0x48, 0xdc, 0x20, 0xf1, 0x08,
+	0x68, 0x48, 0xbc, 0x00, 0x2f, 0x80, 0x8e, 0x93, 0x66, 0x82, 0xbb, 0x2f, 0xdf, 0xf9, 0xf5, 0xf3,
+	0x77, 0x4e, 0xe1, 0x65, 0x7e, 0x73, 0x7d, 0x60, 0x0b, 0xa6, 0xcc, 0x95, 0x2e, 0x32, 0x5e, 0x98,
+	0x03, 0x93, 0xb3, 0xe2, 0x26, 0x97, 0xe5, 0xf5, 0x9d, 0x5a, 0x2c, 0xc7, 0x79, 0xa1, 0xad, 0xa6,
+	0x61, 0x63, 0x3d, 0xfb, 0xdd, 0x86, 0xe0, 0x1d, 0x5b, 0x4b, 0xcd, 0x56, 0xf4, 0x31, 0x84, 0x56,
+	0x64, 0xdc, 0x58, 0x96, 0xe5, 0x89, 0x3f, 0xf4, 0x47, 0xed, 0xf4, 0xce, 0xa0, 0x47, 0x10, 0x64,
+	0xdc, 0x16, 0xe2, 0xd2, 0x24, 0x5b, 0xc3, 0xd6, 0x28, 0x3a, 0x7c, 0x34, 0x6e, 0x62, 0xc6, 0x75,
+	0xc4, 0xf8, 0x8d, 0x23, 0xd2, 0x0d, 0x49, 0x09, 0xb4, 0x0c, 0xff, 0x98, 0xb4, 0x5c, 0x18, 0x4a,
+	0x4a, 0xa1, 0x5d, 0x96, 0x62, 0x95, 0xb4, 0x87, 0xfe, 0x28, 0x4c, 0x9d, 0x46, 0x6f, 0xa9, 0x57,
+	0xeb, 0xa4, 0x33, 0xf4, 0x47, 0x71, 0xea, 0xf4, 0xee, 0xf7, 0x16, 0x74, 0xab, 0x34, 0x1c, 0x2b,
+	0x96, 0x71, 0x57, 0x29, 0x4c, 0x9d, 0xa6, 0x0f, 0xa0, 0xc3, 0xa4, 0x60, 0xd8, 0x05, 0xa3, 0xab,
+	0x8f, 0x7f, 0x37, 0x68, 0xfd, 0xbf, 0xc1, 0x2e, 0xf4, 0x56, 0xcc, 0x32, 0xbb, 0xce, 0xb9, 0x7b,
+	0xbe, 0x9f, 0x36, 0xdf, 0x74, 0x1f, 0xfa, 0xc2, 0x2c, 0x3e, 0x08, 0x63, 0x75, 0x21, 0x2e, 0x99,
+	0x74, 0x5d, 0x7a, 0x69, 0x2c, 0xcc, 0xb4, 0xf1, 0xe8, 0x1e, 0xc4, 0xc2, 0x2c, 0xdc, 0x99, 0x05,
+	0x57, 0x36, 0xe9, 0x3a, 0x26, 0x12, 0x66, 0xb6, 0xb1, 0xe8, 0x43, 0x08, 0x84, 0x59, 0xa8, 0x52,
+	0xca, 0x24, 0x70, 0xd3, 0xae, 0x30, 0x6f, 0x4b, 0x29, 0xe9, 0x13, 0x08, 0x85, 0xb2, 0x8b, 0x4f,
+	0x4c, 0x96, 0x3c, 0x01, 0x7c, 0x7d, 0xea, 0xa5, 0x3d, 0xa1, 0xec, 0x7b, 0x74, 0xe8, 0x53, 0x00,
+	0xa9, 0xd5, 0x75, 0x3d, 0x8f, 0xb0, 0xfa, 0xd4, 0x4b, 0x43, 0xf4, 0x2a, 0x60, 0x0f, 0xa2, 0x2b,
+	0xa9, 0xd9, 0x26, 0x21, 0x1e, 0xfa, 0xa3, 0xad, 0xa9, 0x97, 0x82, 0x33, 0x2b, 0x64, 0x1f, 0xe2,
+	0x95, 0x2e, 0x97, 0x92, 0xd7, 0x4c, 0x7f, 0xe8, 0x8f, 0xfc, 0xa9, 0x97, 0x46, 0x95, 0x5b, 0x41,
+	0xcf, 0xa1, 0xbf, 0xd4, 0x5a, 0x72, 0xa6, 0x6a, 0x6a, 0x1b, 0x6b, 0x4e, 0xbd, 0x34, 0xae, 0xed,
+	0x26, 0xcb, 0xd8, 0x42, 0x34, 0x8d, 0x76, 0xf0, 0xf6, 0x98, 0x55, 0xb9, 0x4d, 0xa7, 0xe5, 0xda,
+	0x72, 0x53, 0x33, 0x04, 0xff, 0x3e, 0xec, 0xe4, 0x4c, 0x87, 0x4c, 0x02, 0xe8, 0xb8, 0xe1, 0x8b,
+	0x3f, 0x3e, 0xf4, 0x4e, 0x99, 0x65, 0x33, 0xbc, 0x76, 0x04, 0xc1, 0x5c, 0xdd, 0x28, 0xfd, 0x59,
+	0x11, 0x8f, 0xf6, 0xa0, 0x7d, 0xae, 0xec, 0x6b, 0xe2, 0xd3, 0x10, 0x3a, 0xe7, 0xca, 0xbe, 0x3a,
+	0x21, 0x5b, 0xb5, 0x3c, 0x3a, 0x24, 0xad, 0x5a, 0x9e, 0x1c, 0x93, 0x36, 0xca, 0xb9, 0x63, 0x3b,
+	0x14, 0xa0, 0x3b, 0xaf, 0xe0, 0xee, 0x46, 0x1f, 0x1d, 0x92, 0x60, 0xa3, 0x4f, 0x8e, 0x49, 0x0f,
+	0xf1, 0x33, 0x3c, 0x0f, 0x09, 0xd1, 0x3e, 0x75, 0x57, 0x20, 0x80, 0xaf, 0x4f, 0xaa, 0x5d, 0x49,
+	0x84, 0x83, 0x0b, 0xb7, 0x12, 0x89, 0x69, 0xec, 0x2a, 0xf2, 0x99, 0xc8, 0x38, 0xe9, 0x63, 0xaf,
+	0x19, 0xff, 0x62, 0xc9, 0x36, 0xaa, 0xf9, 0xfc, 0xfc, 0x94, 0xec, 0xe0, 0x4f, 0x71, 0x89, 0x0b,
+	0x6e, 0x09, 0xc1, 0xf8, 0x09, 0x6e, 0x4a, 0xee, 0x21, 0x71, 0x26, 0x24, 0x27, 0x14, 0x33, 0x66,
+	0x3c, 0xcb, 0x25, 0xb3, 0x9c, 0xdc, 0x9f, 0x90, 0x6f, 0xb7, 0x03, 0xff, 0xc7, 0xed, 0xc0, 0xff,
+	0x79, 0x3b, 0xf0, 0xbf, 0xfe, 0x1a, 0x78, 0x7f, 0x07, 0x00, 0x2b, 0x54, 0xd9, 0x0d, 0xaa, 0x03,
+	0x00, 0x00,
+}
+
+func (m *Payload) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Payload) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Payload) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Body != nil {
+		i -= len(m.Body)
+		copy(dAtA[i:], m.Body)
+		i = encodeVarintSparkplugB(dAtA, i, uint64(len(m.Body)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Uuid != nil {
+		i -= len(*m.Uuid)
+		copy(dAtA[i:], *m.Uuid)
+		i = encodeVarintSparkplugB(dAtA, i, uint64(len(*m.Uuid)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Seq != nil {
+		i = encodeVarintSparkplugB(dAtA, i, uint64(*m.Seq))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Metrics) > 0 {
+		for iNdEx := len(m.Metrics) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Metrics[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintSparkplugB(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Timestamp != nil {
+		i = encodeVarintSparkplugB(dAtA, i, uint64(*m.Timestamp))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Payload_Metric) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Payload_Metric) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Payload_Metric) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Value != nil {
+		{
+			size := m.Value.Size()
+			i -= size
+			if _, err := m.Value.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if m.IsNull != nil {
+		i--
+		if *m.IsNull {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.IsTransient != nil {
+		i--
+		if *m.IsTransient {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.IsHistorical != nil {
+		i--
+		if *m.IsHistorical {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Datatype != nil {
+		i = encodeVarintSparkplugB(dAtA, i, uint64(*m.Datatype))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Timestamp != nil {
+		i = encodeVarintSparkplugB(dAtA, i, uint64(*m.Timestamp))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Alias != nil {
+		i = encodeVarintSparkplugB(dAtA, i, uint64(*m.Alias))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Name != nil {
+		i -= len(*m.Name)
+		copy(dAtA[i:], *m.Name)
+		i = encodeVarintSparkplugB(dAtA, i, uint64(len(*m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Payload_Metric_IntValue) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Payload_Metric_IntValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i = encodeVarintSparkplugB(dAtA, i, uint64(m.IntValue))
+	i--
+	dAtA[i] = 0x50
+	return len(dAtA) - i, nil
+}
+func (m *Payload_Metric_LongValue) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Payload_Metric_LongValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i = encodeVarintSparkplugB(dAtA, i, uint64(m.LongValue))
+	i--
+	dAtA[i] = 0x58
+	return len(dAtA) - i, nil
+}
+func (m *Payload_Metric_FloatValue) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Payload_Metric_FloatValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i -= 4
+	encoding_binary.LittleEndian.PutUint32(dAtA[i:], uint32(math.Float32bits(float32(m.FloatValue))))
+	i--
+	dAtA[i] = 0x65
+	return len(dAtA) - i, nil
+}
+func (m *Payload_Metric_DoubleValue) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Payload_Metric_DoubleValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i -= 8
+	encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.DoubleValue))))
+	i--
+	dAtA[i] = 0x69
+	return len(dAtA) - i, nil
+}
+func (m *Payload_Metric_BooleanValue) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Payload_Metric_BooleanValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i--
+	if m.BooleanValue {
+		dAtA[i] = 1
+	} else {
+		dAtA[i] = 0
+	}
+	i--
+	dAtA[i] = 0x70
+	return len(dAtA) - i, nil
+}
+func (m *Payload_Metric_StringValue) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Payload_Metric_StringValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	i -= len(m.StringValue)
+	copy(dAtA[i:], m.StringValue)
+	i = encodeVarintSparkplugB(dAtA, i, uint64(len(m.StringValue)))
+	i--
+	dAtA[i] = 0x7a
+	return len(dAtA) - i, nil
+}
+func (m *Payload_Metric_BytesValue) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Payload_Metric_BytesValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.BytesValue != nil {
+		i -= len(m.BytesValue)
+		copy(dAtA[i:], m.BytesValue)
+		i = encodeVarintSparkplugB(dAtA, i, uint64(len(m.BytesValue)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x82
+	}
+	return len(dAtA) - i, nil
+}
+func encodeVarintSparkplugB(dAtA []byte, offset int, v uint64) int {
+	offset -= sovSparkplugB(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *Payload) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Timestamp != nil {
+		n += 1 + sovSparkplugB(uint64(*m.Timestamp))
+	}
+	if len(m.Metrics) > 0 {
+		for _, e := range m.Metrics {
+			l = e.Size()
+			n += 1 + l + sovSparkplugB(uint64(l))
+		}
+	}
+	if m.Seq != nil {
+		n += 1 + sovSparkplugB(uint64(*m.Seq))
+	}
+	if m.Uuid != nil {
+		l = len(*m.Uuid)
+		n += 1 + l + sovSparkplugB(uint64(l))
+	}
+	if m.Body != nil {
+		l = len(m.Body)
+		n += 1 + l + sovSparkplugB(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Payload_Metric) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Name != nil {
+		l = len(*m.Name)
+		n += 1 + l + sovSparkplugB(uint64(l))
+	}
+	if m.Alias != nil {
+		n += 1 + sovSparkplugB(uint64(*m.Alias))
+	}
+	if m.Timestamp != nil {
+		n += 1 + sovSparkplugB(uint64(*m.Timestamp))
+	}
+	if m.Datatype != nil {
+		n += 1 + sovSparkplugB(uint64(*m.Datatype))
+	}
+	if m.IsHistorical != nil {
+		n += 2
+	}
+	if m.IsTransient != nil {
+		n += 2
+	}
+	if m.IsNull != nil {
+		n += 2
+	}
+	if m.Value != nil {
+		n += m.Value.Size()
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Payload_Metric_IntValue) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovSparkplugB(uint64(m.IntValue))
+	return n
+}
+func (m *Payload_Metric_LongValue) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 1 + sovSparkplugB(uint64(m.LongValue))
+	return n
+}
+func (m *Payload_Metric_FloatValue) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 5
+	return n
+}
+func (m *Payload_Metric_DoubleValue) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 9
+	return n
+}
+func (m *Payload_Metric_BooleanValue) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	n += 2
+	return n
+}
+func (m *Payload_Metric_StringValue) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.StringValue)
+	n += 1 + l + sovSparkplugB(uint64(l))
+	return n
+}
+func (m *Payload_Metric_BytesValue) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.BytesValue != nil {
+		l = len(m.BytesValue)
+		n += 2 + l + sovSparkplugB(uint64(l))
+	}
+	return n
+}
+
+func sovSparkplugB(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozSparkplugB(x uint64) (n int) {
+	return sovSparkplugB(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Payload) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSparkplugB
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Payload: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Payload: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			var v uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Timestamp = &v
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metrics", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthSparkplugB
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthSparkplugB
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Metrics = append(m.Metrics, &Payload_Metric{})
+			if err := m.Metrics[len(m.Metrics)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seq", wireType)
+			}
+			var v uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Seq = &v
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Uuid", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSparkplugB
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSparkplugB
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			s := string(dAtA[iNdEx:postIndex])
+			m.Uuid = &s
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Body", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthSparkplugB
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSparkplugB
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Body = append(m.Body[:0], dAtA[iNdEx:postIndex]...)
+			if m.Body == nil {
+				m.Body = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSparkplugB(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthSparkplugB
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Payload_Metric) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowSparkplugB
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Metric: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Metric: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSparkplugB
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSparkplugB
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			s := string(dAtA[iNdEx:postIndex])
+			m.Name = &s
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Alias", wireType)
+			}
+			var v uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Alias = &v
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			var v uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Timestamp = &v
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Datatype", wireType)
+			}
+			var v uint32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Datatype = &v
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IsHistorical", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			b := bool(v != 0)
+			m.IsHistorical = &b
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IsTransient", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			b := bool(v != 0)
+			m.IsTransient = &b
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IsNull", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			b := bool(v != 0)
+			m.IsNull = &b
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IntValue", wireType)
+			}
+			var v uint32
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Value = &Payload_Metric_IntValue{v}
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LongValue", wireType)
+			}
+			var v uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Value = &Payload_Metric_LongValue{v}
+		case 12:
+			if wireType != 5 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FloatValue", wireType)
+			}
+			var v uint32
+			if (iNdEx + 4) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint32(encoding_binary.LittleEndian.Uint32(dAtA[iNdEx:]))
+			iNdEx += 4
+			m.Value = &Payload_Metric_FloatValue{float32(math.Float32frombits(v))}
+		case 13:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DoubleValue", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.Value = &Payload_Metric_DoubleValue{float64(math.Float64frombits(v))}
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BooleanValue", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			b := bool(v != 0)
+			m.Value = &Payload_Metric_BooleanValue{b}
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StringValue", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthSparkplugB
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSparkplugB
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = &Payload_Metric_StringValue{string(dAtA[iNdEx:postIndex])}
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BytesValue", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthSparkplugB
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthSparkplugB
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := make([]byte, postIndex-iNdEx)
+			copy(v, dAtA[iNdEx:postIndex])
+			m.Value = &Payload_Metric_BytesValue{v}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipSparkplugB(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthSparkplugB
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func skipSparkplugB(dAtA []byte) (n int, err error) {
+	l := len(dAtA)
+	iNdEx := 0
+	depth := 0
+	for iNdEx < l {
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return 0, ErrIntOverflowSparkplugB
+			}
+			if iNdEx >= l {
+				return 0, io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		wireType := int(wire & 0x7)
+		switch wireType {
+		case 0:
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				iNdEx++
+				if dAtA[iNdEx-1] < 0x80 {
+					break
+				}
+			}
+		case 1:
+			iNdEx += 8
+		case 2:
+			var length int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return 0, ErrIntOverflowSparkplugB
+				}
+				if iNdEx >= l {
+					return 0, io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				length |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if length < 0 {
+				return 0, ErrInvalidLengthSparkplugB
+			}
+			iNdEx += length
+		case 3:
+			depth++
+		case 4:
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupSparkplugB
+			}
+			depth--
+		case 5:
+			iNdEx += 4
+		default:
+			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
+		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthSparkplugB
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
+	}
+	return 0, io.ErrUnexpectedEOF
+}
+
+var (
+	ErrInvalidLengthSparkplugB        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowSparkplugB          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupSparkplugB = fmt.Errorf("proto: unexpected end of group")
+)
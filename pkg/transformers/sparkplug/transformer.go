@@ -0,0 +1,192 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sparkplug
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/transformers"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+)
+
+const (
+	// Namespace is the Sparkplug B MQTT topic namespace.
+	Namespace = "spBv1.0"
+
+	// ContentType is the content type of Sparkplug B messages.
+	ContentType = "application/x-sparkplug-b"
+
+	nodeBirth = "NBIRTH"
+	aliasFmt  = "alias:%d"
+)
+
+var (
+	// ErrUnsupportedMessage indicates that the message isn't Sparkplug B and
+	// there is no transformer to fall back to.
+	ErrUnsupportedMessage = errors.New("message is not Sparkplug B")
+
+	errDecode = errors.New("failed to decode Sparkplug B payload")
+)
+
+var _ transformers.Transformer = (*transformer)(nil)
+
+type transformer struct {
+	next    transformers.Transformer
+	mu      sync.Mutex
+	aliases map[string]map[uint64]string
+}
+
+// New returns the transformer of Sparkplug B messages into SenML messages.
+// Sparkplug B messages are recognized by the content type, and the other
+// messages are transformed using the given transformer, which may be nil.
+//
+// The subtopic of Sparkplug B messages is expected to be
+// <message_type>.<edge_node_id>[.<device_id>], as set by the MQTT adapter.
+// Metric aliases are resolved using the names reported by the birth
+// messages of the edge node.
+func New(next transformers.Transformer) transformers.Transformer {
+	return &transformer{
+		next:    next,
+		aliases: make(map[string]map[uint64]string),
+	}
+}
+
+func (t *transformer) Transform(msg messaging.Message) (interface{}, error) {
+	if msg.ContentType != ContentType {
+		if t.next == nil {
+			return nil, ErrUnsupportedMessage
+		}
+		return t.next.Transform(msg)
+	}
+
+	var p Payload
+	if err := proto.Unmarshal(msg.Payload, &p); err != nil {
+		return nil, errors.Wrap(errDecode, err)
+	}
+
+	names := t.names(msg, p.GetMetrics())
+
+	msgs := []senml.Message{}
+	for _, m := range p.GetMetrics() {
+		name := m.GetName()
+		if name == "" {
+			name = names[m.GetAlias()]
+		}
+		if name == "" {
+			name = fmt.Sprintf(aliasFmt, m.GetAlias())
+		}
+
+		sm := senml.Message{
+			Channel:   msg.Channel,
+			Subtopic:  msg.Subtopic,
+			Publisher: msg.Publisher,
+			Protocol:  msg.Protocol,
+			Name:      name,
+			Time:      timestamp(m, &p, msg.Created),
+		}
+		if !value(m, &sm) {
+			continue
+		}
+		msgs = append(msgs, sm)
+	}
+
+	return msgs, nil
+}
+
+// names records the aliases of the named metrics, and returns the aliases
+// known for the edge node. Birth message of the edge node resets them.
+func (t *transformer) names(msg messaging.Message, metrics []*Payload_Metric) map[uint64]string {
+	parts := strings.SplitN(msg.Subtopic, ".", 3)
+	if len(parts) < 2 {
+		return nil
+	}
+	node := fmt.Sprintf("%s.%s", msg.Channel, parts[1])
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	names, ok := t.aliases[node]
+	if !ok || parts[0] == nodeBirth {
+		names = make(map[uint64]string)
+		t.aliases[node] = names
+	}
+	for _, m := range metrics {
+		if m.Name != nil && m.Alias != nil {
+			names[m.GetAlias()] = m.GetName()
+		}
+	}
+
+	ret := make(map[uint64]string, len(names))
+	for alias, name := range names {
+		ret[alias] = name
+	}
+	return ret
+}
+
+// timestamp returns the metric time in seconds, falling back to the payload
+// time and the reception time.
+func timestamp(m *Payload_Metric, p *Payload, created int64) float64 {
+	if m.Timestamp != nil {
+		return float64(m.GetTimestamp()) / 1e3
+	}
+	if p.Timestamp != nil {
+		return float64(p.GetTimestamp()) / 1e3
+	}
+	return float64(created) / 1e9
+}
+
+// value sets the SenML value field matching the metric data type. Null
+// metrics and the unsupported data types are skipped.
+func value(m *Payload_Metric, sm *senml.Message) bool {
+	if m.GetIsNull() || m.GetValue() == nil {
+		return false
+	}
+
+	var v float64
+	switch DataType(m.GetDatatype()) {
+	case DataType_Int8:
+		v = float64(int8(m.GetIntValue()))
+	case DataType_Int16:
+		v = float64(int16(m.GetIntValue()))
+	case DataType_Int32:
+		v = float64(int32(m.GetIntValue()))
+	case DataType_UInt8, DataType_UInt16, DataType_UInt32:
+		v = float64(m.GetIntValue())
+	case DataType_Int64:
+		v = float64(int64(m.GetLongValue()))
+	case DataType_UInt64, DataType_DateTime:
+		v = float64(m.GetLongValue())
+	case DataType_Float:
+		v = float64(m.GetFloatValue())
+	case DataType_Double:
+		v = m.GetDoubleValue()
+	case DataType_Boolean:
+		b := m.GetBooleanValue()
+		sm.BoolValue = &b
+		return true
+	case DataType_String, DataType_Text, DataType_UUID:
+		s := m.GetStringValue()
+		sm.StringValue = &s
+		return true
+	case DataType_Bytes, DataType_File:
+		d := base64.StdEncoding.EncodeToString(m.GetBytesValue())
+		sm.DataValue = &d
+		return true
+	default:
+		return false
+	}
+
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return false
+	}
+	sm.Value = &v
+	return true
+}
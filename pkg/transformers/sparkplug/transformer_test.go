@@ -0,0 +1,201 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sparkplug_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/mainflux/mainflux/pkg/transformers/sparkplug"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chanID    = "channel"
+	publisher = "publisher"
+	protocol  = "mqtt"
+	created   = int64(1600000000000000000)
+)
+
+func metric(name string, alias uint64, dt sparkplug.DataType) *sparkplug.Payload_Metric {
+	m := &sparkplug.Payload_Metric{
+		Alias:    proto.Uint64(alias),
+		Datatype: proto.Uint32(uint32(dt)),
+	}
+	if name != "" {
+		m.Name = proto.String(name)
+	}
+	return m
+}
+
+func message(t *testing.T, subtopic string, p *sparkplug.Payload) messaging.Message {
+	data, err := proto.Marshal(p)
+	require.Nil(t, err, fmt.Sprintf("unexpected error marshaling payload: %s", err))
+
+	return messaging.Message{
+		Channel:     chanID,
+		Subtopic:    subtopic,
+		Publisher:   publisher,
+		Protocol:    protocol,
+		Payload:     data,
+		Created:     created,
+		ContentType: sparkplug.ContentType,
+	}
+}
+
+func record(subtopic, name string, tm float64) senml.Message {
+	return senml.Message{
+		Channel:   chanID,
+		Subtopic:  subtopic,
+		Publisher: publisher,
+		Protocol:  protocol,
+		Name:      name,
+		Time:      tm,
+	}
+}
+
+func TestTransform(t *testing.T) {
+	tr := sparkplug.New(nil)
+
+	temp := metric("temperature", 1, sparkplug.DataType_Int16)
+	temp.Value = &sparkplug.Payload_Metric_IntValue{IntValue: uint32(0xFFF6)}
+	temp.Timestamp = proto.Uint64(1600000001500)
+	on := metric("on", 2, sparkplug.DataType_Boolean)
+	on.Value = &sparkplug.Payload_Metric_BooleanValue{BooleanValue: true}
+	state := metric("state", 3, sparkplug.DataType_String)
+	state.Value = &sparkplug.Payload_Metric_StringValue{StringValue: "running"}
+	null := metric("null", 4, sparkplug.DataType_Double)
+	null.IsNull = proto.Bool(true)
+	birth := &sparkplug.Payload{
+		Timestamp: proto.Uint64(1600000001000),
+		Metrics:   []*sparkplug.Payload_Metric{temp, on, state, null},
+	}
+
+	aliased := metric("", 1, sparkplug.DataType_Int16)
+	aliased.Value = &sparkplug.Payload_Metric_IntValue{IntValue: 21}
+	unknown := metric("", 9, sparkplug.DataType_Double)
+	unknown.Value = &sparkplug.Payload_Metric_DoubleValue{DoubleValue: 1.5}
+	raw := metric("raw", 5, sparkplug.DataType_Bytes)
+	raw.Value = &sparkplug.Payload_Metric_BytesValue{BytesValue: []byte("raw")}
+	data := &sparkplug.Payload{
+		Metrics: []*sparkplug.Payload_Metric{aliased, unknown, raw},
+	}
+
+	tempVal, onVal, stateVal := -10.0, true, "running"
+	birthMsgs := []senml.Message{
+		record("DBIRTH.edge.device", "temperature", 1600000001.5),
+		record("DBIRTH.edge.device", "on", 1600000001),
+		record("DBIRTH.edge.device", "state", 1600000001),
+	}
+	birthMsgs[0].Value = &tempVal
+	birthMsgs[1].BoolValue = &onVal
+	birthMsgs[2].StringValue = &stateVal
+
+	aliasedVal, unknownVal, rawVal := 21.0, 1.5, "cmF3"
+	dataMsgs := []senml.Message{
+		record("DDATA.edge.device", "temperature", 1600000000),
+		record("DDATA.edge.device", "alias:9", 1600000000),
+		record("DDATA.edge.device", "raw", 1600000000),
+	}
+	dataMsgs[0].Value = &aliasedVal
+	dataMsgs[1].Value = &unknownVal
+	dataMsgs[2].DataValue = &rawVal
+
+	rebirthMsgs := []senml.Message{
+		record("NDATA.edge", "alias:1", 1600000000),
+		record("NDATA.edge", "alias:9", 1600000000),
+		record("NDATA.edge", "raw", 1600000000),
+	}
+	rebirthMsgs[0].Value = &aliasedVal
+	rebirthMsgs[1].Value = &unknownVal
+	rebirthMsgs[2].DataValue = &rawVal
+
+	invalid := message(t, "DDATA.edge.device", data)
+	invalid.Payload = []byte{0xFF, 0xFF}
+	notSparkplug := message(t, "DDATA.edge.device", data)
+	notSparkplug.ContentType = senml.JSON
+
+	cases := []struct {
+		desc string
+		msg  messaging.Message
+		msgs interface{}
+		err  error
+	}{
+		{
+			desc: "transform device birth message",
+			msg:  message(t, "DBIRTH.edge.device", birth),
+			msgs: birthMsgs,
+		},
+		{
+			desc: "transform device data message with aliases",
+			msg:  message(t, "DDATA.edge.device", data),
+			msgs: dataMsgs,
+		},
+		{
+			desc: "transform data message after node rebirth",
+			msg:  message(t, "NBIRTH.edge", &sparkplug.Payload{}),
+			msgs: []senml.Message{},
+		},
+		{
+			desc: "transform node data message with unknown aliases",
+			msg:  message(t, "NDATA.edge", data),
+			msgs: rebirthMsgs,
+		},
+		{
+			desc: "transform invalid payload",
+			msg:  invalid,
+			err:  errors.New("failed to decode Sparkplug B payload"),
+		},
+		{
+			desc: "transform message without Sparkplug B content type",
+			msg:  notSparkplug,
+			err:  sparkplug.ErrUnsupportedMessage,
+		},
+	}
+
+	for _, tc := range cases {
+		msgs, err := tr.Transform(tc.msg)
+		if tc.err != nil {
+			assert.NotNil(t, err, fmt.Sprintf("%s: expected error got nil", tc.desc))
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+			continue
+		}
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.msgs, msgs, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.msgs, msgs))
+	}
+}
+
+func TestTransformFallback(t *testing.T) {
+	tr := sparkplug.New(senml.New(senml.JSON))
+
+	msg := messaging.Message{
+		Channel:     chanID,
+		Publisher:   publisher,
+		Protocol:    protocol,
+		Payload:     []byte(`[{"n":"temperature","u":"Cel","t":1600000000,"v":21}]`),
+		ContentType: senml.JSON,
+	}
+
+	val := 21.0
+	expected := []senml.Message{
+		{
+			Channel:   chanID,
+			Publisher: publisher,
+			Protocol:  protocol,
+			Name:      "temperature",
+			Unit:      "Cel",
+			Time:      1600000000,
+			Value:     &val,
+		},
+	}
+
+	msgs, err := tr.Transform(msg)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Equal(t, expected, msgs, fmt.Sprintf("expected %v got %v", expected, msgs))
+}
@@ -19,14 +19,82 @@ func listMessagesEndpoint(svc readers.MessageRepository, tc mainflux.ThingsServi
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
-		if err := authorize(ctx, req, tc, ac); err != nil {
+		if err := authorize(ctx, req.token, req.chanID, tc, ac); err != nil {
 			return nil, errors.Wrap(errors.ErrAuthorization, err)
 		}
+		if req.pageMeta.Aggregation != "" {
+			page, err := svc.Aggregate(req.chanID, req.pageMeta)
+			if err != nil {
+				return nil, err
+			}
+
+			return aggregatesPageRes{
+				PageMetadata: page.PageMetadata,
+				Total:        page.Total,
+				Aggregates:   page.Aggregates,
+			}, nil
+		}
+
 		page, err := svc.ReadAll(req.chanID, req.pageMeta)
 		if err != nil {
 			return nil, err
 		}
 
+		return pageRes{
+			PageMetadata: page.PageMetadata,
+			Total:        page.Total,
+			Messages:     page.Messages,
+			NextCursor:   page.NextCursor,
+		}, nil
+	}
+}
+
+func exportMessagesEndpoint(svc readers.MessageRepository, tc mainflux.ThingsServiceClient, ac mainflux.AuthServiceClient) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportMessagesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := authorize(ctx, req.token, req.chanID, tc, ac); err != nil {
+			return nil, errors.Wrap(errors.ErrAuthorization, err)
+		}
+
+		// Messages are read while the response is being encoded.
+		return exportRes{
+			output: req.output,
+			export: func(fn func(readers.Message) error) error {
+				return svc.Export(req.chanID, req.pageMeta, fn)
+			},
+		}, nil
+	}
+}
+
+func listChannelsMessagesEndpoint(svc readers.MessageRepository, tc mainflux.ThingsServiceClient, ac mainflux.AuthServiceClient) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listChannelsMessagesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		chanIDs := req.chanIDs
+		if req.groupID != "" {
+			ids, err := groupChannels(ctx, req.token, req.groupID)
+			if err != nil {
+				return nil, errors.Wrap(errors.ErrAuthorization, err)
+			}
+			chanIDs = ids
+		}
+		if err := authorizeChannels(ctx, req.token, chanIDs, tc, ac); err != nil {
+			return nil, errors.Wrap(errors.ErrAuthorization, err)
+		}
+
+		page, err := svc.ReadAllChannels(chanIDs, req.pageMeta)
+		if err != nil {
+			return nil, err
+		}
+
 		return pageRes{
 			PageMetadata: page.PageMetadata,
 			Total:        page.Total,
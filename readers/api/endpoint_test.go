@@ -4,14 +4,18 @@
 package api_test
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/internal/httputil"
 	"github.com/mainflux/mainflux/pkg/transformers/senml"
@@ -58,6 +62,7 @@ type testRequest struct {
 	method string
 	url    string
 	token  string
+	accept string
 	body   io.Reader
 }
 
@@ -69,6 +74,9 @@ func (tr testRequest) make() (*http.Response, error) {
 	if tr.token != "" {
 		req.Header.Set("Authorization", httputil.BearerPrefix+tr.token)
 	}
+	if tr.accept != "" {
+		req.Header.Set("Accept", tr.accept)
+	}
 
 	return tr.client.Do(req)
 }
@@ -126,7 +134,7 @@ func TestReadAll(t *testing.T) {
 		messages = append(messages, msg)
 	}
 
-	thSvc := mocks.NewThingsService(map[string]string{email: chanID})
+	thSvc := mocks.NewThingsService(map[string][]string{email: {chanID}})
 	mockAuthzDB := map[string][]authmocks.SubjectSet{}
 	mockAuthzDB[email] = append(mockAuthzDB[email], authmocks.SubjectSet{Object: "authorities", Relation: "member"})
 	usrSvc := authmocks.NewAuthService(map[string]string{userToken: email}, mockAuthzDB)
@@ -736,10 +744,704 @@ func TestReadAll(t *testing.T) {
 	}
 }
 
+func TestAggregate(t *testing.T) {
+	chanID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	// Bucket start aligned to the minute.
+	start := float64(time.Now().Unix() / 60 * 60)
+	vals := []struct {
+		time  float64
+		value float64
+	}{
+		{start - 50, 1},
+		{start - 10, 3},
+		{start, 5},
+		{start + 20, 7},
+		{start + 59, 9},
+	}
+
+	var messages []senml.Message
+	for _, val := range vals {
+		value := val.value
+		messages = append(messages, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      val.time,
+			Value:     &value,
+		})
+	}
+	// Messages without the numeric value are not aggregated.
+	messages = append(messages, senml.Message{
+		Channel:   chanID,
+		Publisher: pubID,
+		Protocol:  mqttProt,
+		Name:      msgName,
+		Time:      start + 30,
+		BoolValue: &vb,
+	})
+
+	thSvc := mocks.NewThingsService(map[string][]string{email: {chanID}})
+	mockAuthzDB := map[string][]authmocks.SubjectSet{}
+	mockAuthzDB[email] = append(mockAuthzDB[email], authmocks.SubjectSet{Object: "authorities", Relation: "member"})
+	usrSvc := authmocks.NewAuthService(map[string]string{userToken: email}, mockAuthzDB)
+
+	repo := mocks.NewMessageRepository(chanID, fromSenml(messages))
+	ts := newServer(repo, thSvc, usrSvc)
+	defer ts.Close()
+
+	cases := []struct {
+		desc   string
+		url    string
+		token  string
+		status int
+		res    aggregatesPageRes
+	}{
+		{
+			desc:   "aggregate average as thing",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=avg&interval=1m", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+			status: http.StatusOK,
+			res: aggregatesPageRes{
+				Total:      2,
+				Aggregates: []readers.Aggregate{{Time: start, Value: 7}, {Time: start - 60, Value: 2}},
+			},
+		},
+		{
+			desc:   "aggregate minimum as user",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=min&interval=1m", ts.URL, chanID),
+			token:  fmt.Sprintf("Bearer %s", userToken),
+			status: http.StatusOK,
+			res: aggregatesPageRes{
+				Total:      2,
+				Aggregates: []readers.Aggregate{{Time: start, Value: 5}, {Time: start - 60, Value: 1}},
+			},
+		},
+		{
+			desc:   "aggregate maximum",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=max&interval=1m", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+			status: http.StatusOK,
+			res: aggregatesPageRes{
+				Total:      2,
+				Aggregates: []readers.Aggregate{{Time: start, Value: 9}, {Time: start - 60, Value: 3}},
+			},
+		},
+		{
+			desc:   "aggregate sum",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=sum&interval=1m", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+			status: http.StatusOK,
+			res: aggregatesPageRes{
+				Total:      2,
+				Aggregates: []readers.Aggregate{{Time: start, Value: 21}, {Time: start - 60, Value: 4}},
+			},
+		},
+		{
+			desc:   "aggregate count",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=count&interval=1m", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+			status: http.StatusOK,
+			res: aggregatesPageRes{
+				Total:      2,
+				Aggregates: []readers.Aggregate{{Time: start, Value: 3}, {Time: start - 60, Value: 2}},
+			},
+		},
+		{
+			desc:   "aggregate count with offset and limit",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=count&interval=1m&offset=1&limit=1", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+			status: http.StatusOK,
+			res: aggregatesPageRes{
+				Total:      2,
+				Aggregates: []readers.Aggregate{{Time: start - 60, Value: 2}},
+			},
+		},
+		{
+			desc:   "aggregate sum in single bucket",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=sum&interval=1h", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+			status: http.StatusOK,
+			res: aggregatesPageRes{
+				Total:      1,
+				Aggregates: []readers.Aggregate{{Time: float64(int64(start) / 3600 * 3600), Value: 25}},
+			},
+		},
+		{
+			desc:   "aggregate with invalid token",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=avg&interval=1m", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", invalid),
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "aggregate with invalid aggregation",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=median&interval=1m", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "aggregate without interval",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=avg", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "aggregate with invalid interval",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=avg&interval=%s", ts.URL, chanID, invalid),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "aggregate with sub-second interval",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=avg&interval=500ms", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "aggregate JSON messages",
+			url:    fmt.Sprintf("%s/channels/%s/messages?aggregation=avg&interval=1m&format=json", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read page with interval and without aggregation",
+			url:    fmt.Sprintf("%s/channels/%s/messages?interval=1m", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		var page aggregatesPageRes
+		json.NewDecoder(res.Body).Decode(&page)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res.Total, page.Total, fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.res.Total, page.Total))
+		assert.Equal(t, tc.res.Aggregates, page.Aggregates, fmt.Sprintf("%s: expected aggregates %v got %v", tc.desc, tc.res.Aggregates, page.Aggregates))
+	}
+}
+
+func TestReadAllCursor(t *testing.T) {
+	chanID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now().Unix()
+	var messages []senml.Message
+	for i := 0; i < 25; i++ {
+		messages = append(messages, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      float64(now - int64(i)),
+			Value:     &v,
+		})
+	}
+
+	thSvc := mocks.NewThingsService(map[string][]string{email: {chanID}})
+	usrSvc := authmocks.NewAuthService(map[string]string{userToken: email}, map[string][]authmocks.SubjectSet{})
+
+	repo := mocks.NewMessageRepository(chanID, fromSenml(messages))
+	ts := newServer(repo, thSvc, usrSvc)
+	defer ts.Close()
+
+	var read []senml.Message
+	cursor := readers.FirstCursor
+	for pages := 0; cursor != ""; pages++ {
+		require.Less(t, pages, 5, "expected cursor pagination to end")
+
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/%s/messages?limit=10&cursor=%s", ts.URL, chanID, cursor),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+		}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+		require.Equal(t, http.StatusOK, res.StatusCode, fmt.Sprintf("expected %d got %d", http.StatusOK, res.StatusCode))
+
+		var page pageRes
+		err = json.NewDecoder(res.Body).Decode(&page)
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+		read = append(read, page.Messages...)
+		cursor = page.NextCursor
+	}
+	assert.Equal(t, messages, read, fmt.Sprintf("expected messages %v got %v", messages, read))
+
+	cases := []struct {
+		desc   string
+		url    string
+		status int
+	}{
+		{
+			desc:   "read page with invalid cursor",
+			url:    fmt.Sprintf("%s/channels/%s/messages?cursor=%s", ts.URL, chanID, invalid),
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read page with cursor and offset",
+			url:    fmt.Sprintf("%s/channels/%s/messages?cursor=%s&offset=10", ts.URL, chanID, readers.FirstCursor),
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read JSON messages page with cursor",
+			url:    fmt.Sprintf("%s/channels/%s/messages?cursor=%s&format=json", ts.URL, chanID, readers.FirstCursor),
+			status: http.StatusOK,
+		},
+		{
+			desc:   "read aggregates page with cursor",
+			url:    fmt.Sprintf("%s/channels/%s/messages?cursor=%s&aggregation=avg&interval=1m", ts.URL, chanID, readers.FirstCursor),
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  fmt.Sprintf("Thing %s", thingToken),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestReadAllCBOR(t *testing.T) {
+	chanID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now().Unix()
+	var messages []senml.Message
+	for i := 0; i < numOfMessages; i++ {
+		messages = append(messages, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Unit:      "Cel",
+			Time:      float64(now - int64(i)),
+			Value:     &v,
+		})
+	}
+
+	thSvc := mocks.NewThingsService(map[string][]string{email: {chanID}})
+	usrSvc := authmocks.NewAuthService(map[string]string{userToken: email}, map[string][]authmocks.SubjectSet{})
+
+	repo := mocks.NewMessageRepository(chanID, fromSenml(messages))
+	ts := newServer(repo, thSvc, usrSvc)
+	defer ts.Close()
+
+	cases := []struct {
+		desc   string
+		accept string
+		ct     string
+	}{
+		{
+			desc: "read page without accept header",
+			ct:   "application/json",
+		},
+		{
+			desc:   "read page accepting JSON",
+			accept: "application/json",
+			ct:     "application/json",
+		},
+		{
+			desc:   "read page accepting CBOR",
+			accept: "application/cbor",
+			ct:     "application/cbor",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/%s/messages?limit=10", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+			accept: tc.accept,
+		}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		require.Equal(t, http.StatusOK, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", tc.desc, http.StatusOK, res.StatusCode))
+		assert.Equal(t, tc.ct, res.Header.Get("Content-Type"), fmt.Sprintf("%s: unexpected content type", tc.desc))
+
+		var page pageRes
+		if tc.ct == "application/cbor" {
+			err = cbor.NewDecoder(res.Body).Decode(&page)
+		} else {
+			err = json.NewDecoder(res.Body).Decode(&page)
+		}
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, uint64(numOfMessages), page.Total, fmt.Sprintf("%s: expected %d total got %d", tc.desc, numOfMessages, page.Total))
+		assert.Equal(t, messages[:10], page.Messages, fmt.Sprintf("%s: expected messages %v got %v", tc.desc, messages[:10], page.Messages))
+	}
+}
+
+func TestExport(t *testing.T) {
+	chanID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now().Unix()
+	var messages []senml.Message
+	for i := 0; i < numOfMessages; i++ {
+		msg := senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      float64(now - int64(i)),
+		}
+		if i%2 == 0 {
+			msg.Value = &v
+		} else {
+			msg.StringValue = &vs
+		}
+		messages = append(messages, msg)
+	}
+	// Exported messages are sorted from the oldest to the newest.
+	var exported []senml.Message
+	for i := len(messages) - 1; i >= 0; i-- {
+		exported = append(exported, messages[i])
+	}
+
+	thSvc := mocks.NewThingsService(map[string][]string{email: {chanID}})
+	usrSvc := authmocks.NewAuthService(map[string]string{userToken: email}, map[string][]authmocks.SubjectSet{})
+
+	repo := mocks.NewMessageRepository(chanID, fromSenml(messages))
+	ts := newServer(repo, thSvc, usrSvc)
+	defer ts.Close()
+
+	cases := []struct {
+		desc        string
+		url         string
+		token       string
+		status      int
+		contentType string
+		res         []senml.Message
+	}{
+		{
+			desc:        "export NDJSON messages as thing",
+			url:         fmt.Sprintf("%s/channels/%s/messages/export", ts.URL, chanID),
+			token:       fmt.Sprintf("Thing %s", thingToken),
+			status:      http.StatusOK,
+			contentType: "application/x-ndjson",
+			res:         exported,
+		},
+		{
+			desc:        "export CSV messages as user",
+			url:         fmt.Sprintf("%s/channels/%s/messages/export?output=csv", ts.URL, chanID),
+			token:       fmt.Sprintf("Bearer %s", userToken),
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			res:         exported,
+		},
+		{
+			desc:        "export messages time range",
+			url:         fmt.Sprintf("%s/channels/%s/messages/export?from=%f&to=%f", ts.URL, chanID, messages[19].Time, messages[4].Time),
+			token:       fmt.Sprintf("Thing %s", thingToken),
+			status:      http.StatusOK,
+			contentType: "application/x-ndjson",
+			res:         exported[len(exported)-20 : len(exported)-5],
+		},
+		{
+			desc:        "export CSV messages without matching messages",
+			url:         fmt.Sprintf("%s/channels/%s/messages/export?output=csv&name=%s", ts.URL, chanID, invalid),
+			token:       fmt.Sprintf("Thing %s", thingToken),
+			status:      http.StatusOK,
+			contentType: "text/csv",
+		},
+		{
+			desc:   "export messages with invalid token",
+			url:    fmt.Sprintf("%s/channels/%s/messages/export", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", invalid),
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "export messages with invalid output",
+			url:    fmt.Sprintf("%s/channels/%s/messages/export?output=xml", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "export JSON messages as CSV",
+			url:    fmt.Sprintf("%s/channels/%s/messages/export?output=csv&format=json", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "export aggregated messages",
+			url:    fmt.Sprintf("%s/channels/%s/messages/export?aggregation=avg&interval=1m", ts.URL, chanID),
+			token:  fmt.Sprintf("Thing %s", thingToken),
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		ct := res.Header.Get("Content-Type")
+		assert.Equal(t, tc.contentType, ct, fmt.Sprintf("%s: expected content type %s got %s", tc.desc, tc.contentType, ct))
+
+		var msgs []senml.Message
+		switch ct {
+		case "text/csv":
+			records, err := csv.NewReader(res.Body).ReadAll()
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			require.NotEmpty(t, records, fmt.Sprintf("%s: expected CSV header", tc.desc))
+			msgs = fromCSV(t, records[1:])
+		default:
+			dec := json.NewDecoder(res.Body)
+			for dec.More() {
+				var msg senml.Message
+				err := dec.Decode(&msg)
+				assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+				msgs = append(msgs, msg)
+			}
+		}
+		assert.Equal(t, tc.res, msgs, fmt.Sprintf("%s: expected messages %v got %v", tc.desc, tc.res, msgs))
+	}
+}
+
+func fromCSV(t *testing.T, records [][]string) []senml.Message {
+	var msgs []senml.Message
+	for _, rec := range records {
+		tm, err := strconv.ParseFloat(rec[6], 64)
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+		msg := senml.Message{
+			Channel:   rec[0],
+			Subtopic:  rec[1],
+			Publisher: rec[2],
+			Protocol:  rec[3],
+			Name:      rec[4],
+			Unit:      rec[5],
+			Time:      tm,
+		}
+		if rec[8] != "" {
+			val, err := strconv.ParseFloat(rec[8], 64)
+			require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+			msg.Value = &val
+		}
+		if rec[9] != "" {
+			val := rec[9]
+			msg.StringValue = &val
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+func TestReadAllChannels(t *testing.T) {
+	var chanIDs []string
+	for i := 0; i < 3; i++ {
+		id, err := idProvider.ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		chanIDs = append(chanIDs, id)
+	}
+	pubID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	// Messages of the channels are interleaved in time, the newest first.
+	now := time.Now().Unix()
+	repo := map[string][]readers.Message{}
+	var messages []senml.Message
+	for i := 0; i < numOfMessages; i++ {
+		chanID := chanIDs[i%len(chanIDs)]
+		msg := senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      float64(now - int64(i)),
+			Value:     &v,
+		}
+		repo[chanID] = append(repo[chanID], msg)
+		if chanID != chanIDs[2] {
+			messages = append(messages, msg)
+		}
+	}
+
+	thSvc := mocks.NewThingsService(map[string][]string{email: chanIDs[:2]})
+	usrSvc := mocks.NewAuthService(map[string]string{userToken: email}, map[string][]string{
+		"group":   chanIDs[:2],
+		"foreign": chanIDs,
+	})
+
+	ts := newServer(mocks.NewChannelsMessageRepository(repo), thSvc, usrSvc)
+	defer ts.Close()
+
+	channels := strings.Join(chanIDs[:2], ",")
+	total := uint64(len(messages))
+
+	cases := []struct {
+		desc   string
+		url    string
+		token  string
+		status int
+		res    pageRes
+	}{
+		{
+			desc:   "read messages of channels",
+			url:    fmt.Sprintf("%s/messages?channels=%s&limit=10", ts.URL, channels),
+			token:  userToken,
+			status: http.StatusOK,
+			res: pageRes{
+				Total:    total,
+				Messages: messages[0:10],
+			},
+		},
+		{
+			desc:   "read messages of channels with offset",
+			url:    fmt.Sprintf("%s/messages?channels=%s&offset=5&limit=10", ts.URL, channels),
+			token:  userToken,
+			status: http.StatusOK,
+			res: pageRes{
+				Total:    total,
+				Messages: messages[5:15],
+			},
+		},
+		{
+			desc:   "read messages of repeated channels",
+			url:    fmt.Sprintf("%s/messages?channels=%s,%s&limit=10", ts.URL, channels, chanIDs[0]),
+			token:  userToken,
+			status: http.StatusOK,
+			res: pageRes{
+				Total:    total,
+				Messages: messages[0:10],
+			},
+		},
+		{
+			desc:   "read messages of channels group",
+			url:    fmt.Sprintf("%s/messages?group=group&limit=10", ts.URL),
+			token:  userToken,
+			status: http.StatusOK,
+			res: pageRes{
+				Total:    total,
+				Messages: messages[0:10],
+			},
+		},
+		{
+			desc:   "read messages of channels with offset beyond total",
+			url:    fmt.Sprintf("%s/messages?channels=%s&offset=%d", ts.URL, channels, numOfMessages),
+			token:  userToken,
+			status: http.StatusOK,
+			res: pageRes{
+				Total: total,
+			},
+		},
+		{
+			desc:   "read messages of channel not owned by user",
+			url:    fmt.Sprintf("%s/messages?channels=%s,%s", ts.URL, channels, chanIDs[2]),
+			token:  userToken,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "read messages of group with channel not owned by user",
+			url:    fmt.Sprintf("%s/messages?group=foreign", ts.URL),
+			token:  userToken,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "read messages of channels with invalid token",
+			url:    fmt.Sprintf("%s/messages?channels=%s", ts.URL, channels),
+			token:  invalid,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "read messages of channels with empty token",
+			url:    fmt.Sprintf("%s/messages?channels=%s", ts.URL, channels),
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "read messages without channels",
+			url:    fmt.Sprintf("%s/messages", ts.URL),
+			token:  userToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read messages of both channels and group",
+			url:    fmt.Sprintf("%s/messages?channels=%s&group=group", ts.URL, channels),
+			token:  userToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read messages of channels with cursor",
+			url:    fmt.Sprintf("%s/messages?channels=%s&cursor=%s", ts.URL, channels, readers.FirstCursor),
+			token:  userToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read messages of channels with aggregation",
+			url:    fmt.Sprintf("%s/messages?channels=%s&aggregation=avg&interval=1m", ts.URL, channels),
+			token:  userToken,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "read messages of channels with invalid limit",
+			url:    fmt.Sprintf("%s/messages?channels=%s&limit=0", ts.URL, channels),
+			token:  userToken,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var page pageRes
+		err = json.NewDecoder(res.Body).Decode(&page)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.res.Total, page.Total, fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.res.Total, page.Total))
+		assert.ElementsMatch(t, tc.res.Messages, page.Messages, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.res.Messages, page.Messages))
+		assert.Equal(t, tc.res.Messages, page.Messages, fmt.Sprintf("%s: expected ordered %v got %v", tc.desc, tc.res.Messages, page.Messages))
+	}
+}
+
 type pageRes struct {
 	readers.PageMetadata
-	Total    uint64          `json:"total"`
-	Messages []senml.Message `json:"messages,omitempty"`
+	Total      uint64          `json:"total"`
+	Messages   []senml.Message `json:"messages,omitempty"`
+	NextCursor string          `json:"next_cursor,omitempty"`
 }
 
 func fromSenml(in []senml.Message) []readers.Message {
@@ -749,3 +1451,9 @@ func fromSenml(in []senml.Message) []readers.Message {
 	}
 	return ret
 }
+
+type aggregatesPageRes struct {
+	readers.PageMetadata
+	Total      uint64              `json:"total"`
+	Aggregates []readers.Aggregate `json:"aggregates"`
+}
@@ -40,3 +40,42 @@ func (lm *loggingMiddleware) ReadAll(chanID string, rpm readers.PageMetadata) (p
 
 	return lm.svc.ReadAll(chanID, rpm)
 }
+
+func (lm *loggingMiddleware) ReadAllChannels(chanIDs []string, rpm readers.PageMetadata) (page readers.MessagesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method read_all_channels for channels %s with query %v took %s to complete", chanIDs, rpm, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ReadAllChannels(chanIDs, rpm)
+}
+
+func (lm *loggingMiddleware) Aggregate(chanID string, rpm readers.PageMetadata) (page readers.AggregatesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method aggregate for channel %s with query %v took %s to complete", chanID, rpm, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Aggregate(chanID, rpm)
+}
+
+func (lm *loggingMiddleware) Export(chanID string, rpm readers.PageMetadata, fn func(readers.Message) error) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method export for channel %s with query %v took %s to complete", chanID, rpm, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Export(chanID, rpm, fn)
+}
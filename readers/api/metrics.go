@@ -37,3 +37,30 @@ func (mm *metricsMiddleware) ReadAll(chanID string, rpm readers.PageMetadata) (r
 
 	return mm.svc.ReadAll(chanID, rpm)
 }
+
+func (mm *metricsMiddleware) ReadAllChannels(chanIDs []string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "read_all_channels").Add(1)
+		mm.latency.With("method", "read_all_channels").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ReadAllChannels(chanIDs, rpm)
+}
+
+func (mm *metricsMiddleware) Aggregate(chanID string, rpm readers.PageMetadata) (readers.AggregatesPage, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "aggregate").Add(1)
+		mm.latency.With("method", "aggregate").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Aggregate(chanID, rpm)
+}
+
+func (mm *metricsMiddleware) Export(chanID string, rpm readers.PageMetadata, fn func(readers.Message) error) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "export").Add(1)
+		mm.latency.With("method", "export").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Export(chanID, rpm, fn)
+}
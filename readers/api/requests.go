@@ -28,12 +28,120 @@ func (req listMessagesReq) validate() error {
 	if req.pageMeta.Limit < 1 || req.pageMeta.Offset < 0 {
 		return errors.ErrInvalidQueryParams
 	}
-	if req.pageMeta.Comparator != "" &&
-		req.pageMeta.Comparator != readers.EqualKey &&
-		req.pageMeta.Comparator != readers.LowerThanKey &&
-		req.pageMeta.Comparator != readers.LowerThanEqualKey &&
-		req.pageMeta.Comparator != readers.GreaterThanKey &&
-		req.pageMeta.Comparator != readers.GreaterThanEqualKey {
+	if !validComparator(req.pageMeta.Comparator) {
+		return errors.ErrInvalidQueryParams
+	}
+	// Cursor pagination is supported only for messages pages.
+	if req.pageMeta.Cursor != "" && (req.pageMeta.Offset != 0 || req.pageMeta.Aggregation != "") {
+		return errors.ErrInvalidQueryParams
+	}
+
+	return req.validateAggregation()
+}
+
+func (req listMessagesReq) validateAggregation() error {
+	if req.pageMeta.Aggregation == "" {
+		if req.pageMeta.Interval != "" {
+			return errors.ErrInvalidQueryParams
+		}
+		return nil
+	}
+
+	switch req.pageMeta.Aggregation {
+	case readers.MinAggregation,
+		readers.MaxAggregation,
+		readers.AvgAggregation,
+		readers.SumAggregation,
+		readers.CountAggregation:
+	default:
+		return errors.ErrInvalidQueryParams
+	}
+
+	// Only the numeric values of SenML messages can be aggregated.
+	if req.pageMeta.Format != defFormat {
+		return errors.ErrInvalidQueryParams
+	}
+	if _, err := readers.ParseInterval(req.pageMeta.Interval); err != nil {
+		return errors.Wrap(errors.ErrInvalidQueryParams, err)
+	}
+
+	return nil
+}
+
+type exportMessagesReq struct {
+	chanID   string
+	token    string
+	output   string
+	pageMeta readers.PageMetadata
+}
+
+func (req exportMessagesReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+	if req.chanID == "" {
+		return errors.ErrMalformedEntity
+	}
+	if !validComparator(req.pageMeta.Comparator) || req.pageMeta.Aggregation != "" || req.pageMeta.Interval != "" {
+		return errors.ErrInvalidQueryParams
+	}
+
+	switch req.output {
+	case ndjsonOutput:
+	case csvOutput:
+		// Only SenML messages have the fixed set of columns.
+		if req.pageMeta.Format != defFormat {
+			return errors.ErrInvalidQueryParams
+		}
+	default:
+		return errors.ErrInvalidQueryParams
+	}
+
+	return nil
+}
+
+func validComparator(comparator string) bool {
+	switch comparator {
+	case "",
+		readers.EqualKey,
+		readers.LowerThanKey,
+		readers.LowerThanEqualKey,
+		readers.GreaterThanKey,
+		readers.GreaterThanEqualKey:
+		return true
+	default:
+		return false
+	}
+}
+
+type listChannelsMessagesReq struct {
+	token    string
+	chanIDs  []string
+	groupID  string
+	pageMeta readers.PageMetadata
+}
+
+func (req listChannelsMessagesReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+	// Channels are set either explicitly, or as the group members.
+	if (len(req.chanIDs) == 0) == (req.groupID == "") {
+		return errors.ErrMalformedEntity
+	}
+	if len(req.chanIDs) > maxChannels {
+		return errors.ErrInvalidQueryParams
+	}
+	for _, id := range req.chanIDs {
+		if id == "" {
+			return errors.ErrMalformedEntity
+		}
+	}
+	if req.pageMeta.Limit < 1 || !validComparator(req.pageMeta.Comparator) {
+		return errors.ErrInvalidQueryParams
+	}
+	// Only the offset pagination of messages is supported.
+	if req.pageMeta.Cursor != "" || req.pageMeta.Aggregation != "" || req.pageMeta.Interval != "" {
 		return errors.ErrInvalidQueryParams
 	}
 
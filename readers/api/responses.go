@@ -10,12 +10,16 @@ import (
 	"github.com/mainflux/mainflux/readers"
 )
 
-var _ mainflux.Response = (*pageRes)(nil)
+var (
+	_ mainflux.Response = (*pageRes)(nil)
+	_ mainflux.Response = (*aggregatesPageRes)(nil)
+)
 
 type pageRes struct {
 	readers.PageMetadata
-	Total    uint64            `json:"total"`
-	Messages []readers.Message `json:"messages,omitempty"`
+	Total      uint64            `json:"total"`
+	Messages   []readers.Message `json:"messages,omitempty"`
+	NextCursor string            `json:"next_cursor,omitempty"`
 }
 
 func (res pageRes) Headers() map[string]string {
@@ -29,3 +33,26 @@ func (res pageRes) Code() int {
 func (res pageRes) Empty() bool {
 	return false
 }
+
+type aggregatesPageRes struct {
+	readers.PageMetadata
+	Total      uint64              `json:"total"`
+	Aggregates []readers.Aggregate `json:"aggregates"`
+}
+
+func (res aggregatesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res aggregatesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res aggregatesPageRes) Empty() bool {
+	return false
+}
+
+type exportRes struct {
+	output string
+	export func(fn func(readers.Message) error) error
+}
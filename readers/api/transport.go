@@ -5,15 +5,20 @@ package api
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/fxamacker/cbor/v2"
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/internal/httputil"
 	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
 	"github.com/mainflux/mainflux/readers"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc/codes"
@@ -21,26 +26,40 @@ import (
 )
 
 const (
-	contentType      = "application/json"
-	offsetKey        = "offset"
-	limitKey         = "limit"
-	formatKey        = "format"
-	subtopicKey      = "subtopic"
-	publisherKey     = "publisher"
-	protocolKey      = "protocol"
-	nameKey          = "name"
-	valueKey         = "v"
-	stringValueKey   = "vs"
-	dataValueKey     = "vd"
-	boolValueKey     = "vb"
-	comparatorKey    = "comparator"
-	fromKey          = "from"
-	toKey            = "to"
-	defLimit         = 10
-	defOffset        = 0
-	defFormat        = "messages"
-	thingTokenPrefix = "Thing "
-	userTokenPrefix  = "Bearer "
+	contentType       = "application/json"
+	cborContentType   = "application/cbor"
+	offsetKey         = "offset"
+	limitKey          = "limit"
+	formatKey         = "format"
+	subtopicKey       = "subtopic"
+	publisherKey      = "publisher"
+	protocolKey       = "protocol"
+	nameKey           = "name"
+	valueKey          = "v"
+	stringValueKey    = "vs"
+	dataValueKey      = "vd"
+	boolValueKey      = "vb"
+	comparatorKey     = "comparator"
+	fromKey           = "from"
+	toKey             = "to"
+	aggregationKey    = "aggregation"
+	intervalKey       = "interval"
+	cursorKey         = "cursor"
+	outputKey         = "output"
+	csvOutput         = "csv"
+	ndjsonOutput      = "ndjson"
+	csvContentType    = "text/csv"
+	ndjsonContentType = "application/x-ndjson"
+	flushSize         = 100
+	channelsKey       = "channels"
+	groupKey          = "group"
+	channelsGroupType = "channels"
+	maxChannels       = 500
+	defLimit          = 10
+	defOffset         = 0
+	defFormat         = "messages"
+	thingTokenPrefix  = "Thing "
+	userTokenPrefix   = "Bearer "
 )
 
 var (
@@ -56,6 +75,7 @@ func MakeHandler(svc readers.MessageRepository, tc mainflux.ThingsServiceClient,
 	usersAuth = ac
 
 	opts := []kithttp.ServerOption{
+		kithttp.ServerBefore(kithttp.PopulateRequestContext),
 		kithttp.ServerErrorEncoder(encodeError),
 	}
 
@@ -67,6 +87,20 @@ func MakeHandler(svc readers.MessageRepository, tc mainflux.ThingsServiceClient,
 		opts...,
 	))
 
+	mux.Get("/channels/:chanID/messages/export", kithttp.NewServer(
+		exportMessagesEndpoint(svc, tc, ac),
+		decodeExport,
+		encodeExport,
+		opts...,
+	))
+
+	mux.Get("/messages", kithttp.NewServer(
+		listChannelsMessagesEndpoint(svc, tc, ac),
+		decodeListChannels,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.GetFunc("/health", mainflux.Health(svcName))
 	mux.Handle("/metrics", promhttp.Handler())
 
@@ -74,109 +108,215 @@ func MakeHandler(svc readers.MessageRepository, tc mainflux.ThingsServiceClient,
 }
 
 func decodeList(ctx context.Context, r *http.Request) (interface{}, error) {
-	offset, err := httputil.ReadUintQuery(r, offsetKey, defOffset)
+	pm, err := decodePageMeta(r)
 	if err != nil {
 		return nil, err
 	}
 
-	limit, err := httputil.ReadUintQuery(r, limitKey, defLimit)
+	pm.Cursor, err = httputil.ReadStringQuery(r, cursorKey, "")
 	if err != nil {
 		return nil, err
 	}
 
-	format, err := httputil.ReadStringQuery(r, formatKey, defFormat)
+	t, err := httputil.ExtractAuthToken(r)
 	if err != nil {
 		return nil, err
 	}
 
-	subtopic, err := httputil.ReadStringQuery(r, subtopicKey, "")
+	req := listMessagesReq{
+		chanID:   bone.GetValue(r, "chanID"),
+		token:    t,
+		pageMeta: pm,
+	}
+
+	return req, nil
+}
+
+func decodeListChannels(ctx context.Context, r *http.Request) (interface{}, error) {
+	pm, err := decodePageMeta(r)
 	if err != nil {
 		return nil, err
 	}
 
-	publisher, err := httputil.ReadStringQuery(r, publisherKey, "")
+	pm.Cursor, err = httputil.ReadStringQuery(r, cursorKey, "")
 	if err != nil {
 		return nil, err
 	}
 
-	protocol, err := httputil.ReadStringQuery(r, protocolKey, "")
+	groupID, err := httputil.ReadStringQuery(r, groupKey, "")
 	if err != nil {
 		return nil, err
 	}
 
-	name, err := httputil.ReadStringQuery(r, nameKey, "")
+	// The key prefix is kept to tell the user tokens from the thing keys.
+	req := listChannelsMessagesReq{
+		token:    r.Header.Get("Authorization"),
+		groupID:  groupID,
+		pageMeta: pm,
+	}
+	// Channels are given either as the comma separated list, or repeated.
+	if channels := bone.GetQuery(r, channelsKey); len(channels) > 0 {
+		req.chanIDs = uniqueIDs(channels)
+	}
+
+	return req, nil
+}
+
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	var ret []string
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		ret = append(ret, id)
+	}
+	return ret
+}
+
+func decodeExport(ctx context.Context, r *http.Request) (interface{}, error) {
+	pm, err := decodePageMeta(r)
 	if err != nil {
 		return nil, err
 	}
 
-	v, err := httputil.ReadFloatQuery(r, valueKey, 0)
+	output, err := httputil.ReadStringQuery(r, outputKey, ndjsonOutput)
 	if err != nil {
 		return nil, err
 	}
 
-	comparator, err := httputil.ReadStringQuery(r, comparatorKey, "")
+	t, err := httputil.ExtractAuthToken(r)
 	if err != nil {
 		return nil, err
 	}
 
+	req := exportMessagesReq{
+		chanID:   bone.GetValue(r, "chanID"),
+		token:    t,
+		output:   output,
+		pageMeta: pm,
+	}
+
+	return req, nil
+}
+
+func decodePageMeta(r *http.Request) (readers.PageMetadata, error) {
+	offset, err := httputil.ReadUintQuery(r, offsetKey, defOffset)
+	if err != nil {
+		return readers.PageMetadata{}, err
+	}
+
+	limit, err := httputil.ReadUintQuery(r, limitKey, defLimit)
+	if err != nil {
+		return readers.PageMetadata{}, err
+	}
+
+	format, err := httputil.ReadStringQuery(r, formatKey, defFormat)
+	if err != nil {
+		return readers.PageMetadata{}, err
+	}
+
+	subtopic, err := httputil.ReadStringQuery(r, subtopicKey, "")
+	if err != nil {
+		return readers.PageMetadata{}, err
+	}
+
+	publisher, err := httputil.ReadStringQuery(r, publisherKey, "")
+	if err != nil {
+		return readers.PageMetadata{}, err
+	}
+
+	protocol, err := httputil.ReadStringQuery(r, protocolKey, "")
+	if err != nil {
+		return readers.PageMetadata{}, err
+	}
+
+	name, err := httputil.ReadStringQuery(r, nameKey, "")
+	if err != nil {
+		return readers.PageMetadata{}, err
+	}
+
+	v, err := httputil.ReadFloatQuery(r, valueKey, 0)
+	if err != nil {
+		return readers.PageMetadata{}, err
+	}
+
+	comparator, err := httputil.ReadStringQuery(r, comparatorKey, "")
+	if err != nil {
+		return readers.PageMetadata{}, err
+	}
+
 	vs, err := httputil.ReadStringQuery(r, stringValueKey, "")
 	if err != nil {
-		return nil, err
+		return readers.PageMetadata{}, err
 	}
 
 	vd, err := httputil.ReadStringQuery(r, dataValueKey, "")
 	if err != nil {
-		return nil, err
+		return readers.PageMetadata{}, err
 	}
 
 	from, err := httputil.ReadFloatQuery(r, fromKey, 0)
 	if err != nil {
-		return nil, err
+		return readers.PageMetadata{}, err
 	}
 
 	to, err := httputil.ReadFloatQuery(r, toKey, 0)
 	if err != nil {
-		return nil, err
+		return readers.PageMetadata{}, err
 	}
 
-	t, err := httputil.ExtractAuthToken(r)
+	aggregation, err := httputil.ReadStringQuery(r, aggregationKey, "")
 	if err != nil {
-		return nil, err
+		return readers.PageMetadata{}, err
 	}
 
-	req := listMessagesReq{
-		chanID: bone.GetValue(r, "chanID"),
-		token:  t,
-		pageMeta: readers.PageMetadata{
-			Offset:      offset,
-			Limit:       limit,
-			Format:      format,
-			Subtopic:    subtopic,
-			Publisher:   publisher,
-			Protocol:    protocol,
-			Name:        name,
-			Value:       v,
-			Comparator:  comparator,
-			StringValue: vs,
-			DataValue:   vd,
-			From:        from,
-			To:          to,
-		},
+	interval, err := httputil.ReadStringQuery(r, intervalKey, "")
+	if err != nil {
+		return readers.PageMetadata{}, err
+	}
+
+	pm := readers.PageMetadata{
+		Offset:      offset,
+		Limit:       limit,
+		Format:      format,
+		Subtopic:    subtopic,
+		Publisher:   publisher,
+		Protocol:    protocol,
+		Name:        name,
+		Value:       v,
+		Comparator:  comparator,
+		StringValue: vs,
+		DataValue:   vd,
+		From:        from,
+		To:          to,
+		Aggregation: aggregation,
+		Interval:    interval,
 	}
 
 	vb, err := httputil.ReadBoolQuery(r, boolValueKey, false)
 	if err != nil && err != errors.ErrNotFoundParam {
-		return nil, err
+		return readers.PageMetadata{}, err
 	}
 	if err == nil {
-		req.pageMeta.BoolValue = vb
+		pm.BoolValue = vb
 	}
 
-	return req, nil
+	return pm, nil
 }
 
-func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+// encodeResponse encodes the response as JSON, or as CBOR if the client
+// accepts it.
+func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	accept, _ := ctx.Value(kithttp.ContextKeyRequestAccept).(string)
+	useCBOR := strings.Contains(accept, cborContentType)
+
 	w.Header().Set("Content-Type", contentType)
+	if useCBOR {
+		w.Header().Set("Content-Type", cborContentType)
+	}
 
 	if ar, ok := response.(mainflux.Response); ok {
 		for k, v := range ar.Headers() {
@@ -190,9 +330,123 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 		}
 	}
 
+	if useCBOR {
+		return cbor.NewEncoder(w).Encode(response)
+	}
 	return json.NewEncoder(w).Encode(response)
 }
 
+// encodeExport streams the exported messages. Since the status is sent along
+// with the first message, the export errors are encoded only if no message
+// has been written yet.
+func encodeExport(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	res := response.(exportRes)
+
+	ct, encode := ndjsonContentType, ndjsonEncoder(w)
+	if res.output == csvOutput {
+		ct, encode = csvContentType, csvEncoder(w)
+	}
+
+	flusher, _ := w.(http.Flusher)
+	count := 0
+	writeHeader := func() {
+		w.Header().Set("Content-Type", ct)
+		w.WriteHeader(http.StatusOK)
+	}
+	err := res.export(func(msg readers.Message) error {
+		if count == 0 {
+			writeHeader()
+		}
+		count++
+		if err := encode(msg); err != nil {
+			return err
+		}
+		if count%flushSize == 0 && flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if count == 0 {
+		if err != nil {
+			encodeError(ctx, err, w)
+			return nil
+		}
+		writeHeader()
+	}
+	if err != nil {
+		return err
+	}
+
+	return encode(nil)
+}
+
+func ndjsonEncoder(w io.Writer) func(readers.Message) error {
+	enc := json.NewEncoder(w)
+	return func(msg readers.Message) error {
+		if msg == nil {
+			return nil
+		}
+		return enc.Encode(msg)
+	}
+}
+
+// csvEncoder writes the header row before the first message, and flushes the
+// buffered rows on the nil message.
+func csvEncoder(w io.Writer) func(readers.Message) error {
+	cw := csv.NewWriter(w)
+	header := false
+	return func(msg readers.Message) error {
+		if !header {
+			header = true
+			if err := cw.Write(csvHeader); err != nil {
+				return err
+			}
+		}
+		if msg == nil {
+			cw.Flush()
+			return cw.Error()
+		}
+
+		m, ok := msg.(senml.Message)
+		if !ok {
+			return errors.ErrMalformedEntity
+		}
+		if err := cw.Write(csvRecord(m)); err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+}
+
+var csvHeader = []string{"channel", "subtopic", "publisher", "protocol", "name", "unit",
+	"time", "update_time", "value", "string_value", "bool_value", "data_value", "sum"}
+
+func csvRecord(msg senml.Message) []string {
+	rec := []string{msg.Channel, msg.Subtopic, msg.Publisher, msg.Protocol, msg.Name, msg.Unit,
+		formatFloat(msg.Time), formatFloat(msg.UpdateTime), "", "", "", "", ""}
+	if msg.Value != nil {
+		rec[8] = formatFloat(*msg.Value)
+	}
+	if msg.StringValue != nil {
+		rec[9] = *msg.StringValue
+	}
+	if msg.BoolValue != nil {
+		rec[10] = strconv.FormatBool(*msg.BoolValue)
+	}
+	if msg.DataValue != nil {
+		rec[11] = *msg.DataValue
+	}
+	if msg.Sum != nil {
+		rec[12] = formatFloat(*msg.Sum)
+	}
+	return rec
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	switch {
 	case errors.Contains(err, nil):
@@ -201,6 +455,8 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		w.WriteHeader(http.StatusBadRequest)
 	case errors.Contains(err, errors.ErrAuthentication):
 		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrAuthorization):
+		w.WriteHeader(http.StatusForbidden)
 
 	case errors.Contains(err, readers.ErrReadMessages):
 		w.WriteHeader(http.StatusInternalServerError)
@@ -217,10 +473,71 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	}
 }
 
-func authorize(ctx context.Context, req listMessagesReq, tc mainflux.ThingsServiceClient, ac mainflux.AuthServiceClient) (err error) {
+// groupChannels returns the IDs of the channels that are members of the group.
+func groupChannels(ctx context.Context, key, groupID string) ([]string, error) {
+	if !strings.HasPrefix(key, userTokenPrefix) {
+		return nil, errUserAccess
+	}
+	token := strings.TrimPrefix(key, userTokenPrefix)
+
+	var ids []string
+	for {
+		res, err := usersAuth.Members(ctx, &mainflux.MembersReq{
+			Token:   token,
+			GroupID: groupID,
+			Offset:  uint64(len(ids)),
+			Limit:   maxChannels,
+			Type:    channelsGroupType,
+		})
+		if err != nil {
+			return nil, errors.Wrap(errUserAccess, err)
+		}
+		if res.GetTotal() > maxChannels {
+			return nil, errors.ErrInvalidQueryParams
+		}
+
+		ids = append(ids, res.GetMembers()...)
+		if len(res.GetMembers()) == 0 || uint64(len(ids)) >= res.GetTotal() {
+			return ids, nil
+		}
+	}
+}
+
+// authorizeChannels checks the access to all the channels, verifying the
+// ownership of the user's channels in a single call.
+func authorizeChannels(ctx context.Context, key string, chanIDs []string, tc mainflux.ThingsServiceClient, ac mainflux.AuthServiceClient) error {
+	if !strings.HasPrefix(key, userTokenPrefix) {
+		for _, chanID := range chanIDs {
+			if err := authorize(ctx, key, chanID, tc, ac); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	token := strings.TrimPrefix(key, userTokenPrefix)
+	user, err := usersAuth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		e, ok := status.FromError(err)
+		if ok && e.Code() == codes.PermissionDenied {
+			return errors.Wrap(errUserAccess, err)
+		}
+		return err
+	}
+	if _, err = thingsAuth.IsChannelsOwner(ctx, &mainflux.ChannelsOwnerReq{Owner: user.Email, ChanIDs: chanIDs}); err != nil {
+		e, ok := status.FromError(err)
+		if ok && e.Code() == codes.PermissionDenied {
+			return errors.Wrap(errUserAccess, err)
+		}
+		return err
+	}
+	return nil
+}
+
+func authorize(ctx context.Context, key, chanID string, tc mainflux.ThingsServiceClient, ac mainflux.AuthServiceClient) (err error) {
 	switch {
-	case strings.HasPrefix(req.token, userTokenPrefix):
-		token := strings.TrimPrefix(req.token, userTokenPrefix)
+	case strings.HasPrefix(key, userTokenPrefix):
+		token := strings.TrimPrefix(key, userTokenPrefix)
 		user, err := usersAuth.Identify(ctx, &mainflux.Token{Value: token})
 		if err != nil {
 			e, ok := status.FromError(err)
@@ -229,7 +546,7 @@ func authorize(ctx context.Context, req listMessagesReq, tc mainflux.ThingsServi
 			}
 			return err
 		}
-		if _, err = thingsAuth.IsChannelOwner(ctx, &mainflux.ChannelOwnerReq{Owner: user.Email, ChanID: req.chanID}); err != nil {
+		if _, err = thingsAuth.IsChannelOwner(ctx, &mainflux.ChannelOwnerReq{Owner: user.Email, ChanID: chanID}); err != nil {
 			e, ok := status.FromError(err)
 			if ok && e.Code() == codes.PermissionDenied {
 				return errors.Wrap(errUserAccess, err)
@@ -238,8 +555,8 @@ func authorize(ctx context.Context, req listMessagesReq, tc mainflux.ThingsServi
 		}
 		return nil
 	default:
-		token := strings.TrimPrefix(req.token, thingTokenPrefix)
-		if _, err := thingsAuth.CanAccessByKey(ctx, &mainflux.AccessByKeyReq{Token: token, ChanID: req.chanID}); err != nil {
+		token := strings.TrimPrefix(key, thingTokenPrefix)
+		if _, err := thingsAuth.CanAccessByKey(ctx, &mainflux.AccessByKeyReq{Token: token, ChanID: chanID}); err != nil {
 			return errors.Wrap(errThingAccess, err)
 		}
 		return nil
@@ -6,6 +6,8 @@ package cassandra
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
 
 	"github.com/gocql/gocql"
 	"github.com/mainflux/mainflux/pkg/errors"
@@ -20,6 +22,8 @@ const (
 
 	// Error code for Undefined table error.
 	undefinedTableCode = 8704
+	// Number of rows fetched at once while exporting messages.
+	exportPageSize = 1000
 )
 
 var _ readers.MessageRepository = (*cassandraRepository)(nil)
@@ -41,6 +45,13 @@ func (cr cassandraRepository) ReadAll(chanID string, rpm readers.PageMetadata) (
 		format = rpm.Format
 	}
 
+	if rpm.Cursor != "" {
+		if format != defTable {
+			return readers.MessagesPage{}, errors.ErrInvalidQueryParams
+		}
+		return cr.readPage(chanID, rpm)
+	}
+
 	q, vals := buildQuery(chanID, rpm)
 
 	selectCQL := fmt.Sprintf(`SELECT channel, subtopic, publisher, protocol, name, unit,
@@ -120,6 +131,246 @@ func (cr cassandraRepository) ReadAll(chanID string, rpm readers.PageMetadata) (
 	return page, nil
 }
 
+// ReadAllChannels merges the pages of the channels, since the messages of
+// different channels are stored in separate partitions.
+func (cr cassandraRepository) ReadAllChannels(chanIDs []string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	if rpm.Cursor != "" {
+		return readers.MessagesPage{}, errors.ErrInvalidQueryParams
+	}
+
+	return readers.MergeChannels(cr, chanIDs, rpm)
+}
+
+// readPage reads a single page of SenML messages, using the Cassandra
+// paging state as the pagination cursor. Since the filtering is applied to
+// the fetched rows, the page may contain less than the limit of messages
+// even if it's not the last one.
+func (cr cassandraRepository) readPage(chanID string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	var pos position
+	if rpm.Cursor != readers.FirstCursor {
+		if err := readers.DecodeCursor(rpm.Cursor, &pos); err != nil {
+			return readers.MessagesPage{}, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+
+	q, vals := buildQuery(chanID, rpm)
+	selectCQL := fmt.Sprintf(`SELECT channel, subtopic, publisher, protocol, name, unit,
+		value, string_value, bool_value, data_value, sum, time,
+		update_time FROM messages WHERE channel = ? %s ALLOW FILTERING`, q)
+
+	// Setting the paging state disables the automatic paging.
+	iter := cr.session.Query(selectCQL, vals[:len(vals)-1]...).PageSize(int(rpm.Limit)).PageState(pos.State).Iter()
+	scanner := iter.Scanner()
+
+	page := readers.MessagesPage{
+		PageMetadata: rpm,
+		Messages:     []readers.Message{},
+	}
+	for scanner.Next() {
+		msg, err := scanSenml(scanner)
+		if err != nil {
+			iter.Close()
+			return readers.MessagesPage{}, err
+		}
+		page.Messages = append(page.Messages, msg)
+	}
+
+	state := iter.PageState()
+	if err := iter.Close(); err != nil {
+		if e, ok := err.(gocql.RequestError); ok {
+			if e.Code() == undefinedTableCode {
+				return readers.MessagesPage{}, nil
+			}
+		}
+		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	if len(state) > 0 {
+		next, err := readers.EncodeCursor(position{State: state})
+		if err != nil {
+			return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+		page.NextCursor = next
+	}
+
+	return page, nil
+}
+
+func (cr cassandraRepository) Export(chanID string, rpm readers.PageMetadata, fn func(readers.Message) error) error {
+	format := defTable
+	if rpm.Format != "" {
+		format = rpm.Format
+	}
+
+	q, vals := buildQuery(chanID, rpm)
+	selectCQL := fmt.Sprintf(`SELECT channel, subtopic, publisher, protocol, name, unit,
+		value, string_value, bool_value, data_value, sum, time,
+		update_time FROM messages WHERE channel = ? %s ALLOW FILTERING`, q)
+	if format != defTable {
+		selectCQL = fmt.Sprintf(`SELECT channel, subtopic, publisher, protocol, created, payload FROM %s WHERE channel = ? %s
+			ALLOW FILTERING`, format, q)
+	}
+
+	// Rows are fetched page by page, in the clustering order.
+	iter := cr.session.Query(selectCQL, vals[:len(vals)-1]...).PageSize(exportPageSize).Iter()
+	scanner := iter.Scanner()
+	for scanner.Next() {
+		var msg readers.Message
+		var err error
+		switch format {
+		case defTable:
+			msg, err = scanSenml(scanner)
+		default:
+			msg, err = scanJSON(scanner)
+		}
+		if err == nil {
+			err = fn(msg)
+		}
+		if err != nil {
+			iter.Close()
+			return err
+		}
+	}
+
+	if err := iter.Close(); err != nil {
+		if e, ok := err.(gocql.RequestError); ok {
+			if e.Code() == undefinedTableCode {
+				return nil
+			}
+		}
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+	return nil
+}
+
+func scanSenml(scanner gocql.Scanner) (senml.Message, error) {
+	var msg senml.Message
+	err := scanner.Scan(&msg.Channel, &msg.Subtopic, &msg.Publisher, &msg.Protocol,
+		&msg.Name, &msg.Unit, &msg.Value, &msg.StringValue, &msg.BoolValue,
+		&msg.DataValue, &msg.Sum, &msg.Time, &msg.UpdateTime)
+	if err != nil {
+		return senml.Message{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	return msg, nil
+}
+
+func scanJSON(scanner gocql.Scanner) (map[string]interface{}, error) {
+	var msg jsonMessage
+	if err := scanner.Scan(&msg.Channel, &msg.Subtopic, &msg.Publisher, &msg.Protocol, &msg.Created, &msg.Payload); err != nil {
+		return nil, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	m, err := msg.toMap()
+	if err != nil {
+		return nil, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	return m, nil
+}
+
+// Aggregate groups and aggregates the values on the client side, since
+// Cassandra doesn't support grouping by the arbitrary expressions.
+func (cr cassandraRepository) Aggregate(chanID string, rpm readers.PageMetadata) (readers.AggregatesPage, error) {
+	switch rpm.Aggregation {
+	case readers.MinAggregation,
+		readers.MaxAggregation,
+		readers.AvgAggregation,
+		readers.SumAggregation,
+		readers.CountAggregation:
+	default:
+		return readers.AggregatesPage{}, errors.ErrInvalidQueryParams
+	}
+	interval, err := readers.ParseInterval(rpm.Interval)
+	if err != nil {
+		return readers.AggregatesPage{}, errors.Wrap(errors.ErrInvalidQueryParams, err)
+	}
+
+	q, vals := buildQuery(chanID, rpm)
+	selectCQL := fmt.Sprintf(`SELECT time, value FROM %s WHERE channel = ? %s ALLOW FILTERING`, defTable, q)
+
+	iter := cr.session.Query(selectCQL, vals[:len(vals)-1]...).Iter()
+	scanner := iter.Scanner()
+
+	size := float64(interval)
+	buckets := map[float64]*bucket{}
+	for scanner.Next() {
+		var t float64
+		var v *float64
+		if err := scanner.Scan(&t, &v); err != nil {
+			iter.Close()
+			return readers.AggregatesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+		if v == nil {
+			continue
+		}
+
+		start := math.Floor(t/size) * size
+		b, ok := buckets[start]
+		if !ok {
+			b = &bucket{min: *v, max: *v}
+			buckets[start] = b
+		}
+		b.add(*v)
+	}
+	if err := iter.Close(); err != nil {
+		if e, ok := err.(gocql.RequestError); ok {
+			if e.Code() == undefinedTableCode {
+				return readers.AggregatesPage{}, nil
+			}
+		}
+		return readers.AggregatesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	aggs := make([]readers.Aggregate, 0, len(buckets))
+	for start, b := range buckets {
+		aggs = append(aggs, readers.Aggregate{Time: start, Value: b.value(rpm.Aggregation)})
+	}
+	sort.Slice(aggs, func(i, j int) bool { return aggs[i].Time > aggs[j].Time })
+
+	page := readers.AggregatesPage{
+		PageMetadata: rpm,
+		Total:        uint64(len(aggs)),
+		Aggregates:   []readers.Aggregate{},
+	}
+	if rpm.Offset >= page.Total {
+		return page, nil
+	}
+	end := rpm.Offset + rpm.Limit
+	if end > page.Total {
+		end = page.Total
+	}
+	page.Aggregates = aggs[rpm.Offset:end]
+
+	return page, nil
+}
+
+type bucket struct {
+	min   float64
+	max   float64
+	sum   float64
+	count uint64
+}
+
+func (b *bucket) add(v float64) {
+	b.min = math.Min(b.min, v)
+	b.max = math.Max(b.max, v)
+	b.sum += v
+	b.count++
+}
+
+func (b *bucket) value(aggregation string) float64 {
+	switch aggregation {
+	case readers.MinAggregation:
+		return b.min
+	case readers.MaxAggregation:
+		return b.max
+	case readers.AvgAggregation:
+		return b.sum / float64(b.count)
+	case readers.SumAggregation:
+		return b.sum
+	default:
+		return float64(b.count)
+	}
+}
+
 func buildQuery(chanID string, rpm readers.PageMetadata) (string, []interface{}) {
 	var condCQL string
 	vals := []interface{}{chanID}
@@ -167,6 +418,11 @@ func buildQuery(chanID string, rpm readers.PageMetadata) (string, []interface{})
 	return condCQL, vals
 }
 
+// position is the paging state of the page.
+type position struct {
+	State []byte `json:"state"`
+}
+
 type jsonMessage struct {
 	ID        string
 	Channel   string
@@ -0,0 +1,92 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package readers
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+)
+
+// maxConcurrentReads is the maximal number of channels read at the same time.
+const maxConcurrentReads = 10
+
+// MergeChannels returns the page of messages of the given channels, read
+// channel by channel, merged and ordered from the newest to the oldest one.
+// It's intended for the repositories that can't read several channels in a
+// single query. Since every channel is read up to the end of the page, the
+// offset should be kept reasonably small. The total is the sum of the
+// channels totals.
+func MergeChannels(repo MessageRepository, chanIDs []string, pm PageMetadata) (MessagesPage, error) {
+	cpm := pm
+	cpm.Offset = 0
+	cpm.Limit = pm.Offset + pm.Limit
+
+	pages := make([]MessagesPage, len(chanIDs))
+	errs := make([]error, len(chanIDs))
+	sem := make(chan struct{}, maxConcurrentReads)
+	var wg sync.WaitGroup
+	for i, chanID := range chanIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, chanID string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			pages[i], errs[i] = repo.ReadAll(chanID, cpm)
+		}(i, chanID)
+	}
+	wg.Wait()
+
+	page := MessagesPage{
+		PageMetadata: pm,
+		Messages:     []Message{},
+	}
+	var msgs []Message
+	for i := range pages {
+		if errs[i] != nil {
+			return MessagesPage{}, errs[i]
+		}
+		page.Total += pages[i].Total
+		msgs = append(msgs, pages[i].Messages...)
+	}
+
+	sort.SliceStable(msgs, func(i, j int) bool {
+		return messageTime(msgs[i]) > messageTime(msgs[j])
+	})
+
+	n := uint64(len(msgs))
+	if pm.Offset >= n {
+		return page, nil
+	}
+	end := pm.Offset + pm.Limit
+	if end > n {
+		end = n
+	}
+	page.Messages = msgs[pm.Offset:end]
+
+	return page, nil
+}
+
+// messageTime returns the time of SenML, or the creation time of JSON message.
+func messageTime(msg Message) float64 {
+	switch m := msg.(type) {
+	case senml.Message:
+		return m.Time
+	case map[string]interface{}:
+		switch created := m["created"].(type) {
+		case int64:
+			return float64(created)
+		case float64:
+			return created
+		case json.Number:
+			f, _ := created.Float64()
+			return f
+		}
+	}
+	return 0
+}
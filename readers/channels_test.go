@@ -0,0 +1,163 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package readers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/mainflux/mainflux/readers"
+	"github.com/mainflux/mainflux/readers/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	chanID1 = "chan-1"
+	chanID2 = "chan-2"
+	chanID3 = "chan-3"
+)
+
+var errRead = errors.New("failed to read channel")
+
+// pagesRepository returns the given page of each channel.
+type pagesRepository struct {
+	readers.MessageRepository
+	pages map[string]readers.MessagesPage
+}
+
+func (repo pagesRepository) ReadAll(chanID string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	page, ok := repo.pages[chanID]
+	if !ok {
+		return readers.MessagesPage{}, errRead
+	}
+	return page, nil
+}
+
+func TestMergeChannels(t *testing.T) {
+	// Messages of the channels are ordered from the newest to the oldest
+	// one, the way the repositories return them.
+	msgs := map[string][]readers.Message{
+		chanID1: {senml.Message{Channel: chanID1, Time: 9}, senml.Message{Channel: chanID1, Time: 5}, senml.Message{Channel: chanID1, Time: 1}},
+		chanID2: {senml.Message{Channel: chanID2, Time: 8}, senml.Message{Channel: chanID2, Time: 7}, senml.Message{Channel: chanID2, Time: 2}},
+		chanID3: {},
+	}
+	repo := mocks.NewChannelsMessageRepository(msgs)
+
+	cases := []struct {
+		desc    string
+		chanIDs []string
+		pm      readers.PageMetadata
+		times   []float64
+		total   uint64
+		err     error
+	}{
+		{
+			desc:    "merge all messages of the channels",
+			chanIDs: []string{chanID1, chanID2},
+			pm:      readers.PageMetadata{Offset: 0, Limit: 10},
+			times:   []float64{9, 8, 7, 5, 2, 1},
+			total:   6,
+		},
+		{
+			desc:    "merge the first page of the channels",
+			chanIDs: []string{chanID1, chanID2},
+			pm:      readers.PageMetadata{Offset: 0, Limit: 3},
+			times:   []float64{9, 8, 7},
+			total:   6,
+		},
+		{
+			desc:    "merge the page spanning the channels",
+			chanIDs: []string{chanID1, chanID2},
+			pm:      readers.PageMetadata{Offset: 2, Limit: 3},
+			times:   []float64{7, 5, 2},
+			total:   6,
+		},
+		{
+			desc:    "merge the page with the empty channel",
+			chanIDs: []string{chanID3, chanID2},
+			pm:      readers.PageMetadata{Offset: 1, Limit: 5},
+			times:   []float64{7, 2},
+			total:   3,
+		},
+		{
+			desc:    "merge the page past the last message",
+			chanIDs: []string{chanID1, chanID2},
+			pm:      readers.PageMetadata{Offset: 6, Limit: 5},
+			times:   []float64{},
+			total:   6,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := readers.MergeChannels(repo, tc.chanIDs, tc.pm)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.total, page.Total))
+		assert.Equal(t, tc.pm, page.PageMetadata, fmt.Sprintf("%s: expected page metadata %v got %v\n", tc.desc, tc.pm, page.PageMetadata))
+
+		times := []float64{}
+		for _, msg := range page.Messages {
+			times = append(times, msg.(senml.Message).Time)
+		}
+		assert.Equal(t, tc.times, times, fmt.Sprintf("%s: expected times %v got %v\n", tc.desc, tc.times, times))
+	}
+}
+
+func TestMergeChannelsJSON(t *testing.T) {
+	repo := pagesRepository{
+		pages: map[string]readers.MessagesPage{
+			chanID1: {
+				Total: 2,
+				Messages: []readers.Message{
+					map[string]interface{}{"channel": chanID1, "created": int64(30)},
+					map[string]interface{}{"channel": chanID1, "created": int64(10)},
+				},
+			},
+			chanID2: {
+				Total: 1,
+				Messages: []readers.Message{
+					map[string]interface{}{"channel": chanID2, "created": json.Number("20")},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		desc    string
+		chanIDs []string
+		pm      readers.PageMetadata
+		created []interface{}
+		total   uint64
+		err     error
+	}{
+		{
+			desc:    "merge JSON messages by creation time",
+			chanIDs: []string{chanID1, chanID2},
+			pm:      readers.PageMetadata{Offset: 0, Limit: 10, Format: "json"},
+			created: []interface{}{int64(30), json.Number("20"), int64(10)},
+			total:   3,
+		},
+		{
+			desc:    "merge JSON messages with the failing channel",
+			chanIDs: []string{chanID1, chanID3},
+			pm:      readers.PageMetadata{Offset: 0, Limit: 10, Format: "json"},
+			created: []interface{}{},
+			err:     errRead,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := readers.MergeChannels(repo, tc.chanIDs, tc.pm)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.total, page.Total))
+
+		created := []interface{}{}
+		for _, msg := range page.Messages {
+			created = append(created, msg.(map[string]interface{})["created"])
+		}
+		assert.Equal(t, tc.created, created, fmt.Sprintf("%s: expected creation times %v got %v\n", tc.desc, tc.created, created))
+	}
+}
@@ -3,11 +3,14 @@ package influxdb
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/mainflux/mainflux/consumers/writers/retention"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/readers"
 
@@ -20,8 +23,20 @@ const (
 	countCol = "count_protocol"
 	// Measurement for SenML messages
 	defMeasurement = "messages"
+	// Number of points in the single chunk of the exported messages.
+	exportChunkSize = 1000
 )
 
+var errMissingTime = errors.New("missing message time")
+
+var aggregations = map[string]string{
+	readers.MinAggregation:   "MIN",
+	readers.MaxAggregation:   "MAX",
+	readers.AvgAggregation:   "MEAN",
+	readers.SumAggregation:   "SUM",
+	readers.CountAggregation: "COUNT",
+}
+
 var _ readers.MessageRepository = (*influxRepository)(nil)
 
 type influxRepository struct {
@@ -43,9 +58,77 @@ func (repo *influxRepository) ReadAll(chanID string, rpm readers.PageMetadata) (
 		format = rpm.Format
 	}
 
+	// Downsampled messages are SenML messages read from the measurement
+	// of their resolution, kept in the retention policy of the channel.
+	measurement := format
+	res, err := repo.resolution(chanID, rpm)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+	if res != retention.Raw {
+		measurement = fmt.Sprintf(`"%s"."%s"`, retention.ChannelTable(chanID, res), retention.Table(res))
+		rpm.Resolution = uint64(res / time.Second)
+	}
+
 	condition := fmtCondition(chanID, rpm)
+	offset := rpm.Offset
+
+	// InfluxDB points don't have IDs, so the cursor is the time of the last
+	// message of the page, along with the number of the returned messages
+	// with that time.
+	var prev position
+	if rpm.Cursor != "" {
+		if format != defMeasurement {
+			return readers.MessagesPage{}, errors.ErrInvalidQueryParams
+		}
+		offset = 0
+	}
+	if rpm.Cursor != "" && rpm.Cursor != readers.FirstCursor {
+		if err := readers.DecodeCursor(rpm.Cursor, &prev); err != nil {
+			return readers.MessagesPage{}, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+		condition = fmt.Sprintf(`%s AND time <= %d`, condition, prev.Time)
+		offset = prev.Skip
+	}
+
+	return repo.readPage(measurement, format, condition, offset, prev, rpm)
+}
+
+func (repo *influxRepository) ReadAllChannels(chanIDs []string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	if rpm.Cursor != "" {
+		return readers.MessagesPage{}, errors.ErrInvalidQueryParams
+	}
+
+	for _, chanID := range chanIDs {
+		res, err := repo.resolution(chanID, rpm)
+		if err != nil {
+			return readers.MessagesPage{}, err
+		}
+		if res != retention.Raw {
+			// Downsampled messages are read from the retention policies
+			// of the channels, so the channel pages are merged.
+			return readers.MergeChannels(repo, chanIDs, rpm)
+		}
+	}
 
-	cmd := fmt.Sprintf(`SELECT * FROM %s WHERE %s ORDER BY time DESC LIMIT %d OFFSET %d`, format, condition, rpm.Limit, rpm.Offset)
+	format := defMeasurement
+	if rpm.Format != "" {
+		format = rpm.Format
+	}
+
+	channels := make([]string, len(chanIDs))
+	for i, chanID := range chanIDs {
+		channels[i] = fmt.Sprintf(`channel='%s'`, chanID)
+	}
+	condition := filterCondition(fmt.Sprintf("(%s)", strings.Join(channels, " OR ")), rpm)
+
+	return repo.readPage(format, format, condition, rpm.Offset, position{}, rpm)
+}
+
+// readPage reads the page of messages matching the condition, and either
+// the cursor of the next page or the total number of matching messages.
+func (repo *influxRepository) readPage(measurement, format, condition string, offset uint64, prev position, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	cmd := fmt.Sprintf(`SELECT * FROM %s WHERE %s ORDER BY time DESC LIMIT %d OFFSET %d`, measurement, condition, rpm.Limit, offset)
 	q := influxdata.Query{
 		Command:  cmd,
 		Database: repo.database,
@@ -73,7 +156,24 @@ func (repo *influxRepository) ReadAll(chanID string, rpm readers.PageMetadata) (
 		messages = append(messages, msg)
 	}
 
-	total, err := repo.count(format, condition)
+	if rpm.Cursor != "" {
+		page := readers.MessagesPage{
+			PageMetadata: rpm,
+			Messages:     messages,
+		}
+		if uint64(len(messages)) == rpm.Limit {
+			next, err := nextPosition(prev, result.Columns, result.Values)
+			if err != nil {
+				return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+			}
+			if page.NextCursor, err = readers.EncodeCursor(next); err != nil {
+				return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+			}
+		}
+		return page, nil
+	}
+
+	total, err := repo.count(measurement, condition)
 	if err != nil {
 		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
 	}
@@ -87,6 +187,201 @@ func (repo *influxRepository) ReadAll(chanID string, rpm readers.PageMetadata) (
 	return page, nil
 }
 
+// resolution returns the resolution of the messages the page is read
+// from, selected by the channel retention policy.
+func (repo *influxRepository) resolution(chanID string, rpm readers.PageMetadata) (time.Duration, error) {
+	if !rpm.Downsamplable() {
+		return retention.Raw, nil
+	}
+
+	q := influxdata.Query{
+		Command:  fmt.Sprintf(`SELECT tiers FROM retention_policies WHERE channel='%s'`, chanID),
+		Database: repo.database,
+	}
+	resp, err := repo.client.Query(q)
+	if err != nil {
+		return 0, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	if resp.Error() != nil {
+		return 0, errors.Wrap(readers.ErrReadMessages, resp.Error())
+	}
+	if len(resp.Results) == 0 ||
+		len(resp.Results[0].Series) == 0 ||
+		len(resp.Results[0].Series[0].Values) == 0 {
+		return retention.Raw, nil
+	}
+
+	var p retention.Policy
+	series := resp.Results[0].Series[0]
+	for i, col := range series.Columns {
+		if col != "tiers" {
+			continue
+		}
+		tiers, _ := series.Values[0][i].(string)
+		if err := json.Unmarshal([]byte(tiers), &p.Tiers); err != nil {
+			return 0, errors.Wrap(readers.ErrReadMessages, err)
+		}
+	}
+	sec, dec := math.Modf(rpm.From)
+
+	return p.Resolution(time.Unix(int64(sec), int64(dec*1e9)), time.Now()), nil
+}
+
+func (repo *influxRepository) Export(chanID string, rpm readers.PageMetadata, fn func(readers.Message) error) error {
+	format := defMeasurement
+	if rpm.Format != "" {
+		format = rpm.Format
+	}
+
+	// Chunked response is decoded one chunk at the time.
+	resp, err := repo.client.QueryAsChunk(influxdata.Query{
+		Command:   fmt.Sprintf(`SELECT * FROM %s WHERE %s ORDER BY time ASC`, format, fmtCondition(chanID, rpm)),
+		Database:  repo.database,
+		Chunked:   true,
+		ChunkSize: exportChunkSize,
+	})
+	if err != nil {
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer resp.Close()
+
+	for {
+		r, err := resp.NextResponse()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(readers.ErrReadMessages, err)
+		}
+		if r.Error() != nil {
+			return errors.Wrap(readers.ErrReadMessages, r.Error())
+		}
+
+		for _, res := range r.Results {
+			for _, series := range res.Series {
+				for _, v := range series.Values {
+					msg, err := parseMessage(format, series.Columns, v)
+					if err != nil {
+						return err
+					}
+					if err := fn(msg); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+}
+
+func (repo *influxRepository) Aggregate(chanID string, rpm readers.PageMetadata) (readers.AggregatesPage, error) {
+	fn, ok := aggregations[rpm.Aggregation]
+	if !ok {
+		return readers.AggregatesPage{}, errors.ErrInvalidQueryParams
+	}
+	interval, err := readers.ParseInterval(rpm.Interval)
+	if err != nil {
+		return readers.AggregatesPage{}, errors.Wrap(errors.ErrInvalidQueryParams, err)
+	}
+
+	// Empty buckets are omitted using fill(none).
+	selectCmd := fmt.Sprintf(`SELECT %s(value) AS value FROM %s WHERE %s GROUP BY time(%ds) fill(none)`, fn, defMeasurement, fmtCondition(chanID, rpm), interval)
+
+	cmd := fmt.Sprintf(`%s ORDER BY time DESC LIMIT %d OFFSET %d`, selectCmd, rpm.Limit, rpm.Offset)
+	resp, err := repo.client.Query(influxdata.Query{
+		Command:  cmd,
+		Database: repo.database,
+	})
+	if err != nil {
+		return readers.AggregatesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	if resp.Error() != nil {
+		return readers.AggregatesPage{}, errors.Wrap(readers.ErrReadMessages, resp.Error())
+	}
+
+	page := readers.AggregatesPage{
+		PageMetadata: rpm,
+		Aggregates:   []readers.Aggregate{},
+	}
+	if len(resp.Results) == 0 || len(resp.Results[0].Series) == 0 {
+		return page, nil
+	}
+
+	for _, v := range resp.Results[0].Series[0].Values {
+		agg, err := parseAggregate(v)
+		if err != nil {
+			return readers.AggregatesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+		page.Aggregates = append(page.Aggregates, agg)
+	}
+
+	total, err := repo.countBuckets(selectCmd)
+	if err != nil {
+		return readers.AggregatesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	page.Total = total
+
+	return page, nil
+}
+
+func (repo *influxRepository) countBuckets(selectCmd string) (uint64, error) {
+	cmd := fmt.Sprintf(`SELECT COUNT(value) FROM (%s)`, selectCmd)
+	resp, err := repo.client.Query(influxdata.Query{
+		Command:  cmd,
+		Database: repo.database,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if resp.Error() != nil {
+		return 0, resp.Error()
+	}
+
+	if len(resp.Results) == 0 ||
+		len(resp.Results[0].Series) == 0 ||
+		len(resp.Results[0].Series[0].Values) == 0 {
+		return 0, nil
+	}
+
+	// The first column is the time, and the second one is the count.
+	result := resp.Results[0].Series[0].Values[0]
+	if len(result) < 2 {
+		return 0, nil
+	}
+
+	count, ok := result[1].(json.Number)
+	if !ok {
+		return 0, nil
+	}
+	return strconv.ParseUint(count.String(), 10, 64)
+}
+
+// parseAggregate converts the aggregation query result row, consisting of
+// the RFC3339 bucket time and the aggregated value, to the aggregate.
+func parseAggregate(fields []interface{}) (readers.Aggregate, error) {
+	var agg readers.Aggregate
+	if len(fields) < 2 {
+		return agg, nil
+	}
+
+	if ts, ok := fields[0].(string); ok {
+		t, err := time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			return agg, err
+		}
+		agg.Time = float64(t.UnixNano()) / 1e9
+	}
+
+	if val, ok := fields[1].(json.Number); ok {
+		v, err := val.Float64()
+		if err != nil {
+			return agg, err
+		}
+		agg.Value = v
+	}
+
+	return agg, nil
+}
+
 func (repo *influxRepository) count(measurement, condition string) (uint64, error) {
 	cmd := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s`, measurement, condition)
 	q := influxdata.Query{
@@ -129,7 +424,11 @@ func (repo *influxRepository) count(measurement, condition string) (uint64, erro
 }
 
 func fmtCondition(chanID string, rpm readers.PageMetadata) string {
-	condition := fmt.Sprintf(`channel='%s'`, chanID)
+	return filterCondition(fmt.Sprintf(`channel='%s'`, chanID), rpm)
+}
+
+// filterCondition appends the page metadata filters to the channel condition.
+func filterCondition(condition string, rpm readers.PageMetadata) string {
 
 	var query map[string]interface{}
 	meta, err := json.Marshal(rpm)
@@ -170,6 +469,55 @@ func fmtCondition(chanID string, rpm readers.PageMetadata) string {
 	return condition
 }
 
+// position identifies the last message of the page.
+type position struct {
+	// Time of the last message in nanoseconds.
+	Time int64 `json:"time"`
+	// Skip is the number of the already returned messages with that time.
+	Skip uint64 `json:"skip"`
+}
+
+// nextPosition returns the position of the last message of the page, given
+// the position of the last message of the previous one.
+func nextPosition(prev position, names []string, rows [][]interface{}) (position, error) {
+	idx := -1
+	for i, name := range names {
+		if name == "time" {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return position{}, errMissingTime
+	}
+
+	times := make([]int64, len(rows))
+	for i, row := range rows {
+		ts, ok := row[idx].(string)
+		if !ok {
+			return position{}, errMissingTime
+		}
+		t, err := time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			return position{}, err
+		}
+		times[i] = t.UnixNano()
+	}
+
+	next := position{Time: times[len(times)-1]}
+	for _, t := range times {
+		if t == next.Time {
+			next.Skip++
+		}
+	}
+	// The whole page has the same time as the previous position.
+	if times[0] == next.Time && prev.Time == next.Time {
+		next.Skip += prev.Skip
+	}
+
+	return next, nil
+}
+
 // ParseMessage and parseValues are util methods. Since InfluxDB client returns
 // results in form of rows and columns, this obscure message conversion is needed
 // to return actual []broker.Message from the query result.
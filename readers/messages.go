@@ -3,7 +3,12 @@
 
 package readers
 
-import "errors"
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
 
 const (
 	// EqualKey represents the equal comparison operator key.
@@ -18,14 +23,58 @@ const (
 	GreaterThanEqualKey = "ge"
 )
 
+const (
+	// MinAggregation represents the minimum value aggregation.
+	MinAggregation = "min"
+	// MaxAggregation represents the maximum value aggregation.
+	MaxAggregation = "max"
+	// AvgAggregation represents the average value aggregation.
+	AvgAggregation = "avg"
+	// SumAggregation represents the value sum aggregation.
+	SumAggregation = "sum"
+	// CountAggregation represents the value count aggregation.
+	CountAggregation = "count"
+)
+
+// defFormat is the format of the SenML messages.
+const defFormat = "messages"
+
+// FirstCursor is the cursor of the first page of the cursor pagination.
+const FirstCursor = "first"
+
 // ErrReadMessages indicates failure occurred while reading messages from database.
 var ErrReadMessages = errors.New("failed to read messages from database")
 
+// ErrInvalidInterval indicates the aggregation interval is not a positive
+// whole number of seconds.
+var ErrInvalidInterval = errors.New("invalid aggregation interval")
+
+// ErrInvalidCursor indicates malformed pagination cursor.
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
 // MessageRepository specifies message reader API.
 type MessageRepository interface {
 	// ReadAll skips given number of messages for given channel and returns next
-	// limited number of messages.
+	// limited number of messages. If the page metadata cursor is set, SenML
+	// messages older than the cursor are returned instead, without counting
+	// the total number of messages.
 	ReadAll(chanID string, pm PageMetadata) (MessagesPage, error)
+	// ReadAllChannels skips given number of messages of the given channels
+	// and returns next limited number of messages, ordered from the newest
+	// to the oldest one. The cursor pagination isn't supported.
+	ReadAllChannels(chanIDs []string, pm PageMetadata) (MessagesPage, error)
+
+	// Export streams the messages for given channel matching the page
+	// metadata filters to the given function, from the oldest to the newest
+	// one, ignoring the offset, limit and cursor. Streaming stops at the
+	// first error returned by the function.
+	Export(chanID string, pm PageMetadata, fn func(Message) error) error
+
+	// Aggregate applies the page metadata aggregation function to the
+	// numeric values of the SenML messages for given channel, grouped in
+	// time buckets of the page metadata interval. Buckets are returned
+	// from the newest to the oldest, and the empty ones are omitted.
+	Aggregate(chanID string, pm PageMetadata) (AggregatesPage, error)
 }
 
 // Message represents any message format.
@@ -35,8 +84,23 @@ type Message interface{}
 // belong to this page.
 type MessagesPage struct {
 	PageMetadata
-	Total    uint64
-	Messages []Message
+	Total      uint64
+	Messages   []Message
+	NextCursor string
+}
+
+// Aggregate represents the aggregated value of a single time bucket.
+type Aggregate struct {
+	Time  float64 `json:"time"`
+	Value float64 `json:"value"`
+}
+
+// AggregatesPage contains page related metadata as well as list of aggregates
+// that belong to this page.
+type AggregatesPage struct {
+	PageMetadata
+	Total      uint64
+	Aggregates []Aggregate
 }
 
 // PageMetadata represents the parameters used to create database queries
@@ -55,6 +119,21 @@ type PageMetadata struct {
 	From        float64 `json:"from,omitempty"`
 	To          float64 `json:"to,omitempty"`
 	Format      string  `json:"format,omitempty"`
+	Aggregation string  `json:"aggregation,omitempty"`
+	Interval    string  `json:"interval,omitempty"`
+	Cursor      string  `json:"cursor,omitempty"`
+
+	// Resolution is the length of the time buckets, in seconds, of the
+	// downsampled messages the page is read from. It's set by the
+	// repository, according to the channel retention policy.
+	Resolution uint64 `json:"resolution,omitempty"`
+}
+
+// Downsamplable returns true if the page can be read from the downsampled
+// messages, i.e. if it's the page of SenML messages published since the
+// given time, which is not read using the cursor.
+func (pm PageMetadata) Downsamplable() bool {
+	return pm.From > 0 && pm.Cursor == "" && (pm.Format == "" || pm.Format == defFormat)
 }
 
 // ParseValueComparator convert comparison operator keys into mathematic anotation
@@ -78,3 +157,39 @@ func ParseValueComparator(query map[string]interface{}) string {
 
 	return comparator
 }
+
+// ParseInterval returns the aggregation interval in seconds. The interval is
+// represented using the Go duration format, e.g. "15m" or "1h".
+func ParseInterval(interval string) (int64, error) {
+	d, err := time.ParseDuration(interval)
+	if err != nil || d < time.Second || d%time.Second != 0 {
+		return 0, ErrInvalidInterval
+	}
+
+	return int64(d / time.Second), nil
+}
+
+// EncodeCursor encodes the repository specific position of the last message
+// of the page to the opaque pagination cursor.
+func EncodeCursor(pos interface{}) (string, error) {
+	data, err := json.Marshal(pos)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes the opaque pagination cursor to the repository
+// specific position of the last message of the previous page.
+func DecodeCursor(cursor string, pos interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, pos); err != nil {
+		return ErrInvalidCursor
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+var _ mainflux.AuthServiceClient = (*authServiceMock)(nil)
+
+type authServiceMock struct {
+	users  map[string]string
+	groups map[string][]string
+}
+
+// NewAuthService returns mock implementation of auth service, given the maps
+// of tokens to user emails and of group IDs to their members.
+func NewAuthService(users map[string]string, groups map[string][]string) mainflux.AuthServiceClient {
+	return &authServiceMock{
+		users:  users,
+		groups: groups,
+	}
+}
+
+func (svc authServiceMock) Identify(ctx context.Context, in *mainflux.Token, opts ...grpc.CallOption) (*mainflux.UserIdentity, error) {
+	if email, ok := svc.users[in.GetValue()]; ok {
+		return &mainflux.UserIdentity{Id: email, Email: email}, nil
+	}
+	return nil, errors.ErrAuthentication
+}
+
+func (svc authServiceMock) Members(ctx context.Context, in *mainflux.MembersReq, opts ...grpc.CallOption) (*mainflux.MembersRes, error) {
+	if _, ok := svc.users[in.GetToken()]; !ok {
+		return nil, errors.ErrAuthentication
+	}
+
+	members := svc.groups[in.GetGroupID()]
+	total := uint64(len(members))
+	res := &mainflux.MembersRes{
+		Total:  total,
+		Offset: in.GetOffset(),
+		Limit:  in.GetLimit(),
+		Type:   in.GetType(),
+	}
+	if in.GetOffset() >= total {
+		return res, nil
+	}
+	end := in.GetOffset() + in.GetLimit()
+	if end > total {
+		end = total
+	}
+	res.Members = members[in.GetOffset():end]
+
+	return res, nil
+}
+
+func (svc authServiceMock) Issue(ctx context.Context, in *mainflux.IssueReq, opts ...grpc.CallOption) (*mainflux.Token, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceMock) Authorize(ctx context.Context, in *mainflux.AuthorizeReq, opts ...grpc.CallOption) (*mainflux.AuthorizeRes, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceMock) AddPolicy(ctx context.Context, in *mainflux.AddPolicyReq, opts ...grpc.CallOption) (*mainflux.AddPolicyRes, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceMock) DeletePolicy(ctx context.Context, in *mainflux.DeletePolicyReq, opts ...grpc.CallOption) (*mainflux.DeletePolicyRes, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceMock) ListPolicies(ctx context.Context, in *mainflux.ListPoliciesReq, opts ...grpc.CallOption) (*mainflux.ListPoliciesRes, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceMock) Assign(ctx context.Context, in *mainflux.Assignment, opts ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
@@ -5,8 +5,11 @@ package mocks
 
 import (
 	"encoding/json"
+	"math"
+	"sort"
 	"sync"
 
+	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/pkg/transformers/senml"
 	"github.com/mainflux/mainflux/readers"
 )
@@ -24,9 +27,15 @@ func NewMessageRepository(chanID string, messages []readers.Message) readers.Mes
 		chanID: messages,
 	}
 
+	return NewChannelsMessageRepository(repo)
+}
+
+// NewChannelsMessageRepository returns mock implementation of message
+// repository, given the map of channel IDs to their messages.
+func NewChannelsMessageRepository(messages map[string][]readers.Message) readers.MessageRepository {
 	return &messageRepositoryMock{
 		mutex:    sync.Mutex{},
-		messages: repo,
+		messages: messages,
 	}
 }
 
@@ -38,6 +47,154 @@ func (repo *messageRepositoryMock) ReadAll(chanID string, rpm readers.PageMetada
 		return readers.MessagesPage{}, nil
 	}
 
+	msgs := repo.filter(chanID, rpm)
+	if rpm.Cursor != "" {
+		return readPage(msgs, rpm)
+	}
+	numOfMessages := uint64(len(msgs))
+
+	if rpm.Offset >= numOfMessages {
+		return readers.MessagesPage{}, nil
+	}
+
+	if rpm.Limit < 1 {
+		return readers.MessagesPage{}, nil
+	}
+
+	end := rpm.Offset + rpm.Limit
+	if rpm.Offset+rpm.Limit > numOfMessages {
+		end = numOfMessages
+	}
+
+	return readers.MessagesPage{
+		PageMetadata: rpm,
+		Total:        uint64(len(msgs)),
+		Messages:     msgs[rpm.Offset:end],
+	}, nil
+}
+
+func (repo *messageRepositoryMock) ReadAllChannels(chanIDs []string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	return readers.MergeChannels(repo, chanIDs, rpm)
+}
+
+// readPage uses the index of the first message of the page as the cursor.
+func readPage(msgs []readers.Message, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	var start uint64
+	if rpm.Cursor != readers.FirstCursor {
+		if err := readers.DecodeCursor(rpm.Cursor, &start); err != nil {
+			return readers.MessagesPage{}, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+	}
+
+	page := readers.MessagesPage{
+		PageMetadata: rpm,
+		Messages:     []readers.Message{},
+	}
+	numOfMessages := uint64(len(msgs))
+	if start >= numOfMessages {
+		return page, nil
+	}
+
+	end := start + rpm.Limit
+	if end > numOfMessages {
+		end = numOfMessages
+	}
+	page.Messages = msgs[start:end]
+	if end-start == rpm.Limit {
+		page.NextCursor, _ = readers.EncodeCursor(end)
+	}
+
+	return page, nil
+}
+
+func (repo *messageRepositoryMock) Export(chanID string, rpm readers.PageMetadata, fn func(readers.Message) error) error {
+	repo.mutex.Lock()
+	msgs := repo.filter(chanID, rpm)
+	repo.mutex.Unlock()
+
+	sort.SliceStable(msgs, func(i, j int) bool {
+		return msgs[i].(senml.Message).Time < msgs[j].(senml.Message).Time
+	})
+	for _, msg := range msgs {
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repo *messageRepositoryMock) Aggregate(chanID string, rpm readers.PageMetadata) (readers.AggregatesPage, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	interval, err := readers.ParseInterval(rpm.Interval)
+	if err != nil {
+		return readers.AggregatesPage{}, err
+	}
+	size := float64(interval)
+
+	buckets := map[float64][]float64{}
+	for _, m := range repo.filter(chanID, rpm) {
+		msg := m.(senml.Message)
+		if msg.Value == nil {
+			continue
+		}
+		b := math.Floor(msg.Time/size) * size
+		buckets[b] = append(buckets[b], *msg.Value)
+	}
+
+	aggs := []readers.Aggregate{}
+	for b, vals := range buckets {
+		aggs = append(aggs, readers.Aggregate{Time: b, Value: aggregate(rpm.Aggregation, vals)})
+	}
+	sort.Slice(aggs, func(i, j int) bool { return aggs[i].Time > aggs[j].Time })
+
+	page := readers.AggregatesPage{
+		PageMetadata: rpm,
+		Total:        uint64(len(aggs)),
+		Aggregates:   []readers.Aggregate{},
+	}
+	if rpm.Offset >= page.Total {
+		return page, nil
+	}
+	end := rpm.Offset + rpm.Limit
+	if end > page.Total {
+		end = page.Total
+	}
+	page.Aggregates = aggs[rpm.Offset:end]
+
+	return page, nil
+}
+
+func aggregate(fn string, vals []float64) float64 {
+	ret := vals[0]
+	switch fn {
+	case readers.CountAggregation:
+		return float64(len(vals))
+	case readers.MinAggregation:
+		for _, v := range vals {
+			ret = math.Min(ret, v)
+		}
+		return ret
+	case readers.MaxAggregation:
+		for _, v := range vals {
+			ret = math.Max(ret, v)
+		}
+		return ret
+	}
+
+	sum := 0.0
+	for _, v := range vals {
+		sum += v
+	}
+	if fn == readers.AvgAggregation {
+		return sum / float64(len(vals))
+	}
+	return sum
+}
+
+func (repo *messageRepositoryMock) filter(chanID string, rpm readers.PageMetadata) []readers.Message {
 	var query map[string]interface{}
 	meta, _ := json.Marshal(rpm)
 	json.Unmarshal(meta, &query)
@@ -140,24 +297,5 @@ func (repo *messageRepositoryMock) ReadAll(chanID string, rpm readers.PageMetada
 		}
 	}
 
-	numOfMessages := uint64(len(msgs))
-
-	if rpm.Offset >= numOfMessages {
-		return readers.MessagesPage{}, nil
-	}
-
-	if rpm.Limit < 1 {
-		return readers.MessagesPage{}, nil
-	}
-
-	end := rpm.Offset + rpm.Limit
-	if rpm.Offset+rpm.Limit > numOfMessages {
-		end = numOfMessages
-	}
-
-	return readers.MessagesPage{
-		PageMetadata: rpm,
-		Total:        uint64(len(msgs)),
-		Messages:     msgs[rpm.Offset:end],
-	}, nil
+	return msgs
 }
@@ -16,11 +16,12 @@ import (
 var _ mainflux.ThingsServiceClient = (*thingsServiceMock)(nil)
 
 type thingsServiceMock struct {
-	channels map[string]string
+	channels map[string][]string
 }
 
-// NewThingsService returns mock implementation of things service
-func NewThingsService(channels map[string]string) mainflux.ThingsServiceClient {
+// NewThingsService returns mock implementation of things service, given the
+// map of owners to the channels they own.
+func NewThingsService(channels map[string][]string) mainflux.ThingsServiceClient {
 	return &thingsServiceMock{channels}
 }
 
@@ -46,7 +47,7 @@ func (svc thingsServiceMock) CanAccessByID(context.Context, *mainflux.AccessByID
 }
 
 func (svc thingsServiceMock) IsChannelOwner(ctx context.Context, in *mainflux.ChannelOwnerReq, opts ...grpc.CallOption) (*empty.Empty, error) {
-	if id, ok := svc.channels[in.GetOwner()]; ok {
+	for _, id := range svc.channels[in.GetOwner()] {
 		if id == in.ChanID {
 			return nil, nil
 		}
@@ -54,6 +55,31 @@ func (svc thingsServiceMock) IsChannelOwner(ctx context.Context, in *mainflux.Ch
 	return nil, errors.ErrAuthorization
 }
 
+func (svc thingsServiceMock) IsChannelsOwner(ctx context.Context, in *mainflux.ChannelsOwnerReq, opts ...grpc.CallOption) (*empty.Empty, error) {
+	owned := map[string]bool{}
+	for _, id := range svc.channels[in.GetOwner()] {
+		owned[id] = true
+	}
+	for _, id := range in.GetChanIDs() {
+		if !owned[id] {
+			return nil, errors.ErrAuthorization
+		}
+	}
+	return nil, nil
+}
+
 func (svc thingsServiceMock) Identify(context.Context, *mainflux.Token, ...grpc.CallOption) (*mainflux.ThingID, error) {
 	panic("not implemented")
 }
+
+func (svc thingsServiceMock) ViewThing(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.Thing, error) {
+	panic("not implemented")
+}
+
+func (svc thingsServiceMock) ViewChannel(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Channel, error) {
+	panic("not implemented")
+}
+
+func (svc thingsServiceMock) ViewQuota(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.Quota, error) {
+	panic("not implemented")
+}
@@ -6,11 +6,15 @@ package mongodb
 import (
 	"context"
 	"encoding/json"
+	"math"
+	"time"
 
+	"github.com/mainflux/mainflux/consumers/writers/retention"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/pkg/transformers/senml"
 	"github.com/mainflux/mainflux/readers"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -19,8 +23,18 @@ const (
 	format = "format"
 	// Collection for SenML messages
 	defCollection = "messages"
+	// Collection for retention policies
+	policiesCollection = "retention_policies"
 )
 
+var aggregations = map[string]interface{}{
+	readers.MinAggregation:   bson.M{"$min": "$value"},
+	readers.MaxAggregation:   bson.M{"$max": "$value"},
+	readers.AvgAggregation:   bson.M{"$avg": "$value"},
+	readers.SumAggregation:   bson.M{"$sum": "$value"},
+	readers.CountAggregation: bson.M{"$sum": 1},
+}
+
 var _ readers.MessageRepository = (*mongoRepository)(nil)
 
 type mongoRepository struct {
@@ -42,22 +56,90 @@ func (repo mongoRepository) ReadAll(chanID string, rpm readers.PageMetadata) (re
 		format = rpm.Format
 	}
 
+	res, err := repo.resolution(chanID, rpm)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+	if res != retention.Raw {
+		format = retention.Table(res)
+		rpm.Resolution = uint64(res / time.Second)
+	}
+
 	col := repo.db.Collection(format)
 
-	sortMap := map[string]interface{}{
-		order: -1,
+	// Message ID breaks the ties between the messages with the same time.
+	sort := bson.D{
+		{Key: order, Value: -1},
+		{Key: "_id", Value: -1},
 	}
 	// Remove format filter and format the rest properly.
 	filter := fmtCondition(chanID, rpm)
-	cursor, err := col.Find(context.Background(), filter, options.Find().SetSort(sortMap).SetLimit(int64(rpm.Limit)).SetSkip(int64(rpm.Offset)))
+	opts := options.Find().SetSort(sort).SetLimit(int64(rpm.Limit)).SetSkip(int64(rpm.Offset))
+	if rpm.Cursor != "" {
+		opts.SetSkip(0)
+	}
+	if rpm.Cursor != "" && rpm.Cursor != readers.FirstCursor {
+		cf, err := cursorFilter(rpm.Cursor, order)
+		if err != nil {
+			return readers.MessagesPage{}, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+		filter = append(filter, cf)
+	}
+
+	return repo.readPage(col, filter, opts, rpm)
+}
+
+func (repo mongoRepository) ReadAllChannels(chanIDs []string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	if rpm.Cursor != "" {
+		return readers.MessagesPage{}, errors.ErrInvalidQueryParams
+	}
+
+	for _, chanID := range chanIDs {
+		res, err := repo.resolution(chanID, rpm)
+		if err != nil {
+			return readers.MessagesPage{}, err
+		}
+		if res != retention.Raw {
+			// The channels downsampled messages may be read from the
+			// collections of different resolutions, so the channel pages
+			// are merged.
+			return readers.MergeChannels(repo, chanIDs, rpm)
+		}
+	}
+
+	format := defCollection
+	order := "time"
+	if rpm.Format != "" && rpm.Format != defCollection {
+		order = "created"
+		format = rpm.Format
+	}
+
+	sort := bson.D{
+		{Key: order, Value: -1},
+		{Key: "_id", Value: -1},
+	}
+	filter := fmtCondition("", rpm)
+	filter[0] = bson.E{Key: "channel", Value: bson.M{"$in": chanIDs}}
+	opts := options.Find().SetSort(sort).SetLimit(int64(rpm.Limit)).SetSkip(int64(rpm.Offset))
+
+	return repo.readPage(repo.db.Collection(format), filter, opts, rpm)
+}
+
+// readPage reads the page of messages matching the filter, and either the
+// cursor of the next page or the total number of matching messages.
+func (repo mongoRepository) readPage(col *mongo.Collection, filter bson.D, opts *options.FindOptions, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	format := col.Name()
+	cursor, err := col.Find(context.Background(), filter, opts)
 	if err != nil {
 		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
 	}
 	defer cursor.Close(context.Background())
 
 	var messages []readers.Message
-	switch format {
-	case defCollection:
+	var last position
+	switch {
+	case rpm.Resolution > 0:
+		// Downsampled messages are identified by their time bucket.
 		for cursor.Next(context.Background()) {
 			var m senml.Message
 			if err := cursor.Decode(&m); err != nil {
@@ -66,6 +148,16 @@ func (repo mongoRepository) ReadAll(chanID string, rpm readers.PageMetadata) (re
 
 			messages = append(messages, m)
 		}
+	case format == defCollection:
+		for cursor.Next(context.Background()) {
+			var m senmlMessage
+			if err := cursor.Decode(&m); err != nil {
+				return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+			}
+
+			messages = append(messages, m.Message)
+			last = position{Time: m.Time, ID: m.ID.Hex()}
+		}
 	default:
 		for cursor.Next(context.Background()) {
 			var m map[string]interface{}
@@ -74,21 +166,170 @@ func (repo mongoRepository) ReadAll(chanID string, rpm readers.PageMetadata) (re
 			}
 
 			messages = append(messages, m)
+			var pos struct {
+				ID      primitive.ObjectID `bson:"_id"`
+				Created int64              `bson:"created"`
+			}
+			if err := cursor.Decode(&pos); err != nil {
+				return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+			}
+			last = position{Created: pos.Created, ID: pos.ID.Hex()}
 		}
 	}
 
+	mp := readers.MessagesPage{
+		PageMetadata: rpm,
+		Messages:     messages,
+	}
+
+	if rpm.Cursor != "" {
+		if uint64(len(messages)) == rpm.Limit {
+			if mp.NextCursor, err = readers.EncodeCursor(last); err != nil {
+				return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+			}
+		}
+		return mp, nil
+	}
+
 	total, err := col.CountDocuments(context.Background(), filter)
 	if err != nil {
 		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
 	}
+	mp.Total = uint64(total)
 
-	mp := readers.MessagesPage{
+	return mp, nil
+}
+
+// resolution returns the resolution of the messages the page is read
+// from, selected by the channel retention policy.
+func (repo mongoRepository) resolution(chanID string, rpm readers.PageMetadata) (time.Duration, error) {
+	if !rpm.Downsamplable() {
+		return retention.Raw, nil
+	}
+
+	var p struct {
+		Tiers []retention.Tier `bson:"tiers"`
+	}
+	if err := repo.db.Collection(policiesCollection).FindOne(context.Background(), bson.M{"_id": chanID}).Decode(&p); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return retention.Raw, nil
+		}
+		return 0, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	sec, dec := math.Modf(rpm.From)
+
+	return retention.Policy{Tiers: p.Tiers}.Resolution(time.Unix(int64(sec), int64(dec*1e9)), time.Now()), nil
+}
+
+func (repo mongoRepository) Export(chanID string, rpm readers.PageMetadata, fn func(readers.Message) error) error {
+	format := defCollection
+	order := "time"
+	if rpm.Format != "" && rpm.Format != defCollection {
+		order = "created"
+		format = rpm.Format
+	}
+
+	// Sorting of the large result sets doesn't fit the memory limit.
+	// Message ID breaks the ties between the messages with the same time.
+	sort := bson.D{
+		{Key: order, Value: 1},
+		{Key: "_id", Value: 1},
+	}
+	opts := options.Find().SetSort(sort).SetAllowDiskUse(true)
+	cursor, err := repo.db.Collection(format).Find(context.Background(), fmtCondition(chanID, rpm), opts)
+	if err != nil {
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer cursor.Close(context.Background())
+
+	for cursor.Next(context.Background()) {
+		var msg readers.Message
+		switch format {
+		case defCollection:
+			var m senml.Message
+			if err := cursor.Decode(&m); err != nil {
+				return errors.Wrap(readers.ErrReadMessages, err)
+			}
+			msg = m
+		default:
+			var m map[string]interface{}
+			if err := cursor.Decode(&m); err != nil {
+				return errors.Wrap(readers.ErrReadMessages, err)
+			}
+			msg = m
+		}
+
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+	return nil
+}
+
+func (repo mongoRepository) Aggregate(chanID string, rpm readers.PageMetadata) (readers.AggregatesPage, error) {
+	acc, ok := aggregations[rpm.Aggregation]
+	if !ok {
+		return readers.AggregatesPage{}, errors.ErrInvalidQueryParams
+	}
+	interval, err := readers.ParseInterval(rpm.Interval)
+	if err != nil {
+		return readers.AggregatesPage{}, errors.Wrap(errors.ErrInvalidQueryParams, err)
+	}
+
+	// Bucket start is the message time rounded down to the interval.
+	bucket := bson.M{"$subtract": bson.A{"$time", bson.M{"$mod": bson.A{"$time", interval}}}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: fmtCondition(chanID, rpm)}},
+		{{Key: "$match", Value: bson.M{"value": bson.M{"$type": "number"}}}},
+		{{Key: "$group", Value: bson.M{"_id": bucket, "value": acc}}},
+		{{Key: "$facet", Value: bson.M{
+			"total": bson.A{bson.M{"$count": "total"}},
+			"aggregates": bson.A{
+				bson.M{"$sort": bson.M{"_id": -1}},
+				bson.M{"$skip": int64(rpm.Offset)},
+				bson.M{"$limit": int64(rpm.Limit)},
+			},
+		}}},
+	}
+
+	cursor, err := repo.db.Collection(defCollection).Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return readers.AggregatesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer cursor.Close(context.Background())
+
+	var res []struct {
+		Total []struct {
+			Total uint64 `bson:"total"`
+		} `bson:"total"`
+		Aggregates []struct {
+			Time  float64 `bson:"_id"`
+			Value float64 `bson:"value"`
+		} `bson:"aggregates"`
+	}
+	if err := cursor.All(context.Background(), &res); err != nil {
+		return readers.AggregatesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	page := readers.AggregatesPage{
 		PageMetadata: rpm,
-		Total:        uint64(total),
-		Messages:     messages,
+		Aggregates:   []readers.Aggregate{},
+	}
+	if len(res) == 0 {
+		return page, nil
+	}
+	if len(res[0].Total) > 0 {
+		page.Total = res[0].Total[0].Total
+	}
+	for _, agg := range res[0].Aggregates {
+		page.Aggregates = append(page.Aggregates, readers.Aggregate{Time: agg.Time, Value: agg.Value})
 	}
 
-	return mp, nil
+	return page, nil
 }
 
 func fmtCondition(chanID string, rpm readers.PageMetadata) bson.D {
@@ -148,3 +389,38 @@ func fmtCondition(chanID string, rpm readers.PageMetadata) bson.D {
 
 	return filter
 }
+
+// cursorFilter returns the filter of the messages older than the message
+// the pagination cursor points to, ordered by the given time field.
+func cursorFilter(cursor, order string) (bson.E, error) {
+	var pos position
+	if err := readers.DecodeCursor(cursor, &pos); err != nil {
+		return bson.E{}, err
+	}
+	id, err := primitive.ObjectIDFromHex(pos.ID)
+	if err != nil {
+		return bson.E{}, readers.ErrInvalidCursor
+	}
+
+	var t interface{} = pos.Time
+	if order == "created" {
+		t = pos.Created
+	}
+	return bson.E{Key: "$or", Value: bson.A{
+		bson.M{order: bson.M{"$lt": t}},
+		bson.M{order: t, "_id": bson.M{"$lt": id}},
+	}}, nil
+}
+
+// position identifies the last message of the page. SenML messages are
+// ordered by the time, and JSON messages by the creation time.
+type position struct {
+	Time    float64 `json:"time,omitempty"`
+	Created int64   `json:"created,omitempty"`
+	ID      string  `json:"id"`
+}
+
+type senmlMessage struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	senml.Message `bson:",inline"`
+}
@@ -4,11 +4,15 @@
 package postgres
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
+	"math"
+	"time"
 
 	"github.com/jmoiron/sqlx" // required for DB access
 	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/consumers/writers/retention"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/pkg/transformers/senml"
 	"github.com/mainflux/mainflux/readers"
@@ -25,6 +29,14 @@ const (
 	undefinedTableCode = "42P01"
 )
 
+var aggregations = map[string]string{
+	readers.MinAggregation:   "MIN",
+	readers.MaxAggregation:   "MAX",
+	readers.AvgAggregation:   "AVG",
+	readers.SumAggregation:   "SUM",
+	readers.CountAggregation: "COUNT",
+}
+
 var _ readers.MessageRepository = (*postgresRepository)(nil)
 
 type postgresRepository struct {
@@ -39,34 +51,86 @@ func New(db *sqlx.DB) readers.MessageRepository {
 }
 
 func (tr postgresRepository) ReadAll(chanID string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
-	order := "time"
+	order := "time DESC, id DESC"
 	format := defTable
 
 	if rpm.Format != "" && rpm.Format != defTable {
-		order = "created"
+		order = "created DESC, id DESC"
 		format = rpm.Format
 	}
 
-	q := fmt.Sprintf(`SELECT * FROM %s
-    WHERE %s ORDER BY %s DESC
-	LIMIT :limit OFFSET :offset;`, format, fmtCondition(chanID, rpm), order)
+	res, err := tr.resolution(chanID, rpm)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+	if res != retention.Raw {
+		order = "time DESC"
+		format = retention.Table(res)
+		rpm.Resolution = uint64(res / time.Second)
+	}
 
-	params := map[string]interface{}{
-		"channel":      chanID,
-		"limit":        rpm.Limit,
-		"offset":       rpm.Offset,
-		"subtopic":     rpm.Subtopic,
-		"publisher":    rpm.Publisher,
-		"name":         rpm.Name,
-		"protocol":     rpm.Protocol,
-		"value":        rpm.Value,
-		"bool_value":   rpm.BoolValue,
-		"string_value": rpm.StringValue,
-		"data_value":   rpm.DataValue,
-		"from":         rpm.From,
-		"to":           rpm.To,
+	condition := fmtCondition(chanID, rpm)
+	params := queryParams(chanID, rpm)
+	if rpm.Cursor != "" {
+		params["offset"] = 0
+	}
+	if rpm.Cursor != "" && rpm.Cursor != readers.FirstCursor {
+		var c position
+		if err := readers.DecodeCursor(rpm.Cursor, &c); err != nil {
+			return readers.MessagesPage{}, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		}
+		switch format {
+		case defTable:
+			condition = fmt.Sprintf(`%s AND (time, id) < (:cursor_time, :cursor_id)`, condition)
+			params["cursor_time"] = c.Time
+		default:
+			condition = fmt.Sprintf(`%s AND (created, id) < (:cursor_created, :cursor_id)`, condition)
+			params["cursor_created"] = c.Created
+		}
+		params["cursor_id"] = c.ID
+	}
+
+	return tr.readPage(format, condition, order, params, rpm)
+}
+
+func (tr postgresRepository) ReadAllChannels(chanIDs []string, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	if rpm.Cursor != "" {
+		return readers.MessagesPage{}, errors.ErrInvalidQueryParams
+	}
+
+	for _, chanID := range chanIDs {
+		res, err := tr.resolution(chanID, rpm)
+		if err != nil {
+			return readers.MessagesPage{}, err
+		}
+		if res != retention.Raw {
+			// Downsampled messages are read from the tables of the
+			// channel retention policy, so the channel pages are merged.
+			return readers.MergeChannels(tr, chanIDs, rpm)
+		}
+	}
+
+	order := "time DESC, id DESC"
+	format := defTable
+	if rpm.Format != "" && rpm.Format != defTable {
+		order = "created DESC, id DESC"
+		format = rpm.Format
 	}
 
+	condition := filterCondition(`channel = ANY(:channels)`, rpm)
+	params := queryParams("", rpm)
+	params["channels"] = pq.Array(chanIDs)
+
+	return tr.readPage(format, condition, order, params, rpm)
+}
+
+// readPage reads the page of messages matching the condition, and either
+// the cursor of the next page or the total number of matching messages.
+func (tr postgresRepository) readPage(format, condition, order string, params map[string]interface{}, rpm readers.PageMetadata) (readers.MessagesPage, error) {
+	q := fmt.Sprintf(`SELECT * FROM %s
+    WHERE %s ORDER BY %s
+	LIMIT :limit OFFSET :offset;`, format, condition, order)
+
 	rows, err := tr.db.NamedQuery(q, params)
 	if err != nil {
 		if e, ok := err.(*pq.Error); ok {
@@ -82,8 +146,9 @@ func (tr postgresRepository) ReadAll(chanID string, rpm readers.PageMetadata) (r
 		PageMetadata: rpm,
 		Messages:     []readers.Message{},
 	}
-	switch format {
-	case defTable:
+	var last position
+	switch {
+	case format == defTable, rpm.Resolution > 0:
 		for rows.Next() {
 			msg := senmlMessage{Message: senml.Message{}}
 			if err := rows.StructScan(&msg); err != nil {
@@ -91,6 +156,7 @@ func (tr postgresRepository) ReadAll(chanID string, rpm readers.PageMetadata) (r
 			}
 
 			page.Messages = append(page.Messages, msg.Message)
+			last = position{Time: msg.Time, ID: msg.ID}
 		}
 	default:
 		for rows.Next() {
@@ -102,12 +168,22 @@ func (tr postgresRepository) ReadAll(chanID string, rpm readers.PageMetadata) (r
 			if err != nil {
 				return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
 			}
+
 			page.Messages = append(page.Messages, m)
+			last = position{Created: msg.Created, ID: msg.ID}
 		}
+	}
 
+	if rpm.Cursor != "" {
+		if uint64(len(page.Messages)) == rpm.Limit {
+			if page.NextCursor, err = readers.EncodeCursor(last); err != nil {
+				return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+			}
+		}
+		return page, nil
 	}
 
-	q = fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s;`, format, fmtCondition(chanID, rpm))
+	q = fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s;`, format, condition)
 	rows, err = tr.db.NamedQuery(q, params)
 	if err != nil {
 		return readers.MessagesPage{}, errors.Wrap(readers.ErrReadMessages, err)
@@ -125,8 +201,163 @@ func (tr postgresRepository) ReadAll(chanID string, rpm readers.PageMetadata) (r
 	return page, nil
 }
 
+// resolution returns the resolution of the messages the page is read
+// from, selected by the channel retention policy.
+func (tr postgresRepository) resolution(chanID string, rpm readers.PageMetadata) (time.Duration, error) {
+	if !rpm.Downsamplable() {
+		return retention.Raw, nil
+	}
+
+	var tiers []byte
+	q := `SELECT tiers FROM retention_policies WHERE channel = $1;`
+	if err := tr.db.QueryRowx(q, chanID).Scan(&tiers); err != nil {
+		if err == sql.ErrNoRows {
+			return retention.Raw, nil
+		}
+		if e, ok := err.(*pq.Error); ok && (e.Code == undefinedTableCode || e.Code.Name() == errInvalid) {
+			return retention.Raw, nil
+		}
+		return 0, errors.Wrap(readers.ErrReadMessages, err)
+	}
+
+	var p retention.Policy
+	if err := json.Unmarshal(tiers, &p.Tiers); err != nil {
+		return 0, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	sec, dec := math.Modf(rpm.From)
+
+	return p.Resolution(time.Unix(int64(sec), int64(dec*1e9)), time.Now()), nil
+}
+
+func (tr postgresRepository) Export(chanID string, rpm readers.PageMetadata, fn func(readers.Message) error) error {
+	// Message ID breaks the ties between the messages with the same time,
+	// so that the export order is stable.
+	order := "time, id"
+	format := defTable
+
+	if rpm.Format != "" && rpm.Format != defTable {
+		order = "created, id"
+		format = rpm.Format
+	}
+
+	q := fmt.Sprintf(`SELECT * FROM %s WHERE %s ORDER BY %s;`, format, fmtCondition(chanID, rpm), order)
+	rows, err := tr.db.NamedQuery(q, queryParams(chanID, rpm))
+	if err != nil {
+		if e, ok := err.(*pq.Error); ok {
+			if e.Code == undefinedTableCode {
+				return nil
+			}
+		}
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var msg readers.Message
+		switch format {
+		case defTable:
+			m := senmlMessage{Message: senml.Message{}}
+			if err := rows.StructScan(&m); err != nil {
+				return errors.Wrap(readers.ErrReadMessages, err)
+			}
+			msg = m.Message
+		default:
+			if msg, err = scanJSON(rows); err != nil {
+				return err
+			}
+		}
+
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(readers.ErrReadMessages, err)
+	}
+	return nil
+}
+
+func (tr postgresRepository) Aggregate(chanID string, rpm readers.PageMetadata) (readers.AggregatesPage, error) {
+	fn, ok := aggregations[rpm.Aggregation]
+	if !ok {
+		return readers.AggregatesPage{}, errors.ErrInvalidQueryParams
+	}
+	interval, err := readers.ParseInterval(rpm.Interval)
+	if err != nil {
+		return readers.AggregatesPage{}, errors.Wrap(errors.ErrInvalidQueryParams, err)
+	}
+
+	condition := fmt.Sprintf(`%s AND value IS NOT NULL`, fmtCondition(chanID, rpm))
+	params := queryParams(chanID, rpm)
+	params["interval"] = interval
+
+	q := fmt.Sprintf(`SELECT FLOOR(time / :interval) * :interval AS bucket, %s(value) AS value FROM %s
+	WHERE %s GROUP BY bucket ORDER BY bucket DESC LIMIT :limit OFFSET :offset;`, fn, defTable, condition)
+
+	rows, err := tr.db.NamedQuery(q, params)
+	if err != nil {
+		if e, ok := err.(*pq.Error); ok {
+			if e.Code == undefinedTableCode {
+				return readers.AggregatesPage{}, nil
+			}
+		}
+		return readers.AggregatesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer rows.Close()
+
+	page := readers.AggregatesPage{
+		PageMetadata: rpm,
+		Aggregates:   []readers.Aggregate{},
+	}
+	for rows.Next() {
+		var agg readers.Aggregate
+		if err := rows.Scan(&agg.Time, &agg.Value); err != nil {
+			return readers.AggregatesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+		page.Aggregates = append(page.Aggregates, agg)
+	}
+
+	q = fmt.Sprintf(`SELECT COUNT(DISTINCT FLOOR(time / :interval) * :interval) FROM %s WHERE %s;`, defTable, condition)
+	rows, err = tr.db.NamedQuery(q, params)
+	if err != nil {
+		return readers.AggregatesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&page.Total); err != nil {
+			return readers.AggregatesPage{}, errors.Wrap(readers.ErrReadMessages, err)
+		}
+	}
+
+	return page, nil
+}
+
+func queryParams(chanID string, rpm readers.PageMetadata) map[string]interface{} {
+	return map[string]interface{}{
+		"channel":      chanID,
+		"limit":        rpm.Limit,
+		"offset":       rpm.Offset,
+		"subtopic":     rpm.Subtopic,
+		"publisher":    rpm.Publisher,
+		"name":         rpm.Name,
+		"protocol":     rpm.Protocol,
+		"value":        rpm.Value,
+		"bool_value":   rpm.BoolValue,
+		"string_value": rpm.StringValue,
+		"data_value":   rpm.DataValue,
+		"from":         rpm.From,
+		"to":           rpm.To,
+	}
+}
+
 func fmtCondition(chanID string, rpm readers.PageMetadata) string {
-	condition := `channel = :channel`
+	return filterCondition(`channel = :channel`, rpm)
+}
+
+// filterCondition appends the page metadata filters to the channel condition.
+func filterCondition(condition string, rpm readers.PageMetadata) string {
 
 	var query map[string]interface{}
 	meta, err := json.Marshal(rpm)
@@ -161,6 +392,26 @@ func fmtCondition(chanID string, rpm readers.PageMetadata) string {
 	return condition
 }
 
+func scanJSON(rows *sqlx.Rows) (map[string]interface{}, error) {
+	msg := jsonMessage{}
+	if err := rows.StructScan(&msg); err != nil {
+		return nil, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	m, err := msg.toMap()
+	if err != nil {
+		return nil, errors.Wrap(readers.ErrReadMessages, err)
+	}
+	return m, nil
+}
+
+// position identifies the last message of the page. SenML messages are
+// ordered by the time, and JSON messages by the creation time.
+type position struct {
+	Time    float64 `json:"time,omitempty"`
+	Created int64   `json:"created,omitempty"`
+	ID      string  `json:"id"`
+}
+
 type senmlMessage struct {
 	ID string `db:"id"`
 	senml.Message
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	pwriter "github.com/mainflux/mainflux/consumers/writers/postgres"
+	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/pkg/transformers/json"
 	"github.com/mainflux/mainflux/pkg/transformers/senml"
 	"github.com/mainflux/mainflux/pkg/uuid"
@@ -376,6 +377,299 @@ func TestReadSenml(t *testing.T) {
 	}
 }
 
+func TestAggregate(t *testing.T) {
+	writer := pwriter.New(db)
+
+	chanID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	// Bucket start aligned to the minute.
+	start := float64(time.Now().Unix() / 60 * 60)
+	vals := []struct {
+		time  float64
+		value float64
+	}{
+		{start - 50, 1},
+		{start - 10, 3},
+		{start, 5},
+		{start + 20, 7},
+		{start + 59, 9},
+	}
+
+	messages := []senml.Message{}
+	for _, val := range vals {
+		value := val.value
+		messages = append(messages, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      val.time,
+			Value:     &value,
+		})
+	}
+	// Messages without the numeric value are not aggregated.
+	messages = append(messages, senml.Message{
+		Channel:   chanID,
+		Publisher: pubID,
+		Protocol:  mqttProt,
+		Name:      msgName,
+		Time:      start + 30,
+		BoolValue: &vb,
+	})
+
+	err = writer.Consume(messages)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := preader.New(db)
+
+	cases := map[string]struct {
+		pageMeta readers.PageMetadata
+		page     readers.AggregatesPage
+		err      error
+	}{
+		"aggregate average": {
+			pageMeta: readers.PageMetadata{Limit: limit, Aggregation: readers.AvgAggregation, Interval: "1m"},
+			page: readers.AggregatesPage{
+				Total:      2,
+				Aggregates: []readers.Aggregate{{Time: start, Value: 7}, {Time: start - 60, Value: 2}},
+			},
+		},
+		"aggregate minimum": {
+			pageMeta: readers.PageMetadata{Limit: limit, Aggregation: readers.MinAggregation, Interval: "1m"},
+			page: readers.AggregatesPage{
+				Total:      2,
+				Aggregates: []readers.Aggregate{{Time: start, Value: 5}, {Time: start - 60, Value: 1}},
+			},
+		},
+		"aggregate maximum": {
+			pageMeta: readers.PageMetadata{Limit: limit, Aggregation: readers.MaxAggregation, Interval: "1m"},
+			page: readers.AggregatesPage{
+				Total:      2,
+				Aggregates: []readers.Aggregate{{Time: start, Value: 9}, {Time: start - 60, Value: 3}},
+			},
+		},
+		"aggregate sum": {
+			pageMeta: readers.PageMetadata{Limit: limit, Aggregation: readers.SumAggregation, Interval: "1m"},
+			page: readers.AggregatesPage{
+				Total:      2,
+				Aggregates: []readers.Aggregate{{Time: start, Value: 21}, {Time: start - 60, Value: 4}},
+			},
+		},
+		"aggregate count with offset": {
+			pageMeta: readers.PageMetadata{Offset: 1, Limit: limit, Aggregation: readers.CountAggregation, Interval: "1m"},
+			page: readers.AggregatesPage{
+				Total:      2,
+				Aggregates: []readers.Aggregate{{Time: start - 60, Value: 2}},
+			},
+		},
+		"aggregate with value filter": {
+			pageMeta: readers.PageMetadata{Limit: limit, Aggregation: readers.CountAggregation, Interval: "1m", Value: 5, Comparator: readers.GreaterThanEqualKey},
+			page: readers.AggregatesPage{
+				Total:      1,
+				Aggregates: []readers.Aggregate{{Time: start, Value: 3}},
+			},
+		},
+		"aggregate with invalid aggregation": {
+			pageMeta: readers.PageMetadata{Limit: limit, Aggregation: "median", Interval: "1m"},
+			err:      errors.ErrInvalidQueryParams,
+		},
+		"aggregate with invalid interval": {
+			pageMeta: readers.PageMetadata{Limit: limit, Aggregation: readers.AvgAggregation, Interval: "1ms"},
+			err:      errors.ErrInvalidQueryParams,
+		},
+	}
+
+	for desc, tc := range cases {
+		result, err := reader.Aggregate(chanID, tc.pageMeta)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s", desc, tc.err, err))
+		assert.Equal(t, tc.page.Aggregates, result.Aggregates, fmt.Sprintf("%s: expected %v got %v", desc, tc.page.Aggregates, result.Aggregates))
+		assert.Equal(t, tc.page.Total, result.Total, fmt.Sprintf("%s: expected %v got %v", desc, tc.page.Total, result.Total))
+	}
+}
+
+func TestReadCursor(t *testing.T) {
+	writer := pwriter.New(db)
+
+	chanID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	// Pairs of messages share the same time to check the ties are broken.
+	now := float64(time.Now().Unix())
+	messages := []senml.Message{}
+	for i := 0; i < 25; i++ {
+		messages = append(messages, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      fmt.Sprintf("%s-%d", msgName, i%2),
+			Time:      now - float64(i/2),
+			Value:     &v,
+		})
+	}
+
+	err = writer.Consume(messages)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := preader.New(db)
+
+	read := []readers.Message{}
+	cursor := readers.FirstCursor
+	for pages := 0; cursor != ""; pages++ {
+		require.LessOrEqual(t, pages, len(messages), "expected cursor pagination to end")
+
+		page, err := reader.ReadAll(chanID, readers.PageMetadata{Limit: limit, Cursor: cursor})
+		require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+		assert.LessOrEqual(t, len(page.Messages), limit, fmt.Sprintf("expected at most %d messages got %d", limit, len(page.Messages)))
+		read = append(read, page.Messages...)
+		cursor = page.NextCursor
+	}
+	assert.ElementsMatch(t, fromSenml(messages), read, fmt.Sprintf("expected %v got %v", messages, read))
+
+	_, err = reader.ReadAll(chanID, readers.PageMetadata{Limit: limit, Cursor: wrongValue})
+	assert.True(t, errors.Contains(err, errors.ErrInvalidQueryParams), fmt.Sprintf("expected error %s got %s", errors.ErrInvalidQueryParams, err))
+}
+
+func TestReadAllChannels(t *testing.T) {
+	writer := pwriter.New(db)
+
+	chanID1, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chanID2, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	// Messages alternate between the channels, from the newest to the oldest.
+	now := float64(time.Now().Unix())
+	messages := []senml.Message{}
+	for i := 0; i < 20; i++ {
+		chanID := chanID1
+		if i%2 == 1 {
+			chanID = chanID2
+		}
+		messages = append(messages, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      now - float64(i),
+			Value:     &v,
+		})
+	}
+
+	err = writer.Consume(messages)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := preader.New(db)
+
+	cases := map[string]struct {
+		chanIDs  []string
+		pageMeta readers.PageMetadata
+		page     readers.MessagesPage
+		err      error
+	}{
+		"read messages of both channels": {
+			chanIDs:  []string{chanID1, chanID2},
+			pageMeta: readers.PageMetadata{Offset: 2, Limit: 5},
+			page: readers.MessagesPage{
+				Total:    uint64(len(messages)),
+				Messages: fromSenml(messages[2:7]),
+			},
+		},
+		"read messages of single channel": {
+			chanIDs:  []string{chanID2},
+			pageMeta: readers.PageMetadata{Offset: 0, Limit: 2},
+			page: readers.MessagesPage{
+				Total:    uint64(len(messages) / 2),
+				Messages: fromSenml([]senml.Message{messages[1], messages[3]}),
+			},
+		},
+		"read messages of both channels with cursor": {
+			chanIDs:  []string{chanID1, chanID2},
+			pageMeta: readers.PageMetadata{Limit: 5, Cursor: readers.FirstCursor},
+			err:      errors.ErrInvalidQueryParams,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := reader.ReadAllChannels(tc.chanIDs, tc.pageMeta)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s", desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		assert.Equal(t, tc.page.Total, page.Total, fmt.Sprintf("%s: expected total %d got %d", desc, tc.page.Total, page.Total))
+		assert.Equal(t, tc.page.Messages, page.Messages, fmt.Sprintf("%s: expected %v got %v", desc, tc.page.Messages, page.Messages))
+	}
+}
+
+func TestExport(t *testing.T) {
+	writer := pwriter.New(db)
+
+	chanID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := float64(time.Now().Unix())
+	messages := []senml.Message{}
+	for i := 0; i < msgsNum; i++ {
+		messages = append(messages, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  mqttProt,
+			Name:      msgName,
+			Time:      now - float64(msgsNum-i),
+			Value:     &v,
+		})
+	}
+
+	err = writer.Consume(messages)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := preader.New(db)
+
+	cases := map[string]struct {
+		pageMeta readers.PageMetadata
+		messages []readers.Message
+	}{
+		"export all messages": {
+			pageMeta: readers.PageMetadata{},
+			messages: fromSenml(messages),
+		},
+		"export messages time range": {
+			pageMeta: readers.PageMetadata{
+				From: messages[10].Time,
+				To:   messages[20].Time,
+			},
+			messages: fromSenml(messages[10:20]),
+		},
+		"export messages for non-existing name": {
+			pageMeta: readers.PageMetadata{Name: wrongValue},
+		},
+	}
+
+	for desc, tc := range cases {
+		var exported []readers.Message
+		err := reader.Export(chanID, tc.pageMeta, func(msg readers.Message) error {
+			exported = append(exported, msg)
+			return nil
+		})
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
+		assert.Equal(t, tc.messages, exported, fmt.Sprintf("%s: expected %v got %v", desc, tc.messages, exported))
+	}
+
+	errStop := errors.New("stop")
+	err = reader.Export(chanID, readers.PageMetadata{}, func(readers.Message) error {
+		return errStop
+	})
+	assert.True(t, errors.Contains(err, errStop), fmt.Sprintf("expected error %s got %s", errStop, err))
+}
+
 func TestReadJSON(t *testing.T) {
 	writer := pwriter.New(db)
 
@@ -517,6 +811,53 @@ func TestReadJSON(t *testing.T) {
 	}
 }
 
+func TestReadJSONCursor(t *testing.T) {
+	writer := pwriter.New(db)
+
+	chanID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	// Pairs of messages share the creation time to check the ties are broken.
+	now := time.Now().Unix()
+	messages := json.Messages{
+		Format: format1,
+	}
+	msgs := []map[string]interface{}{}
+	for i := 0; i < 25; i++ {
+		msg := json.Message{
+			Channel:   chanID,
+			Publisher: chanID,
+			Created:   now - int64(i/2),
+			Subtopic:  fmt.Sprintf("subtopic/cursor/%d", i),
+			Protocol:  mqttProt,
+			Payload:   map[string]interface{}{"field_1": "value"},
+		}
+		messages.Data = append(messages.Data, msg)
+		msgs = append(msgs, toMap(msg))
+	}
+	err = writer.Consume(messages)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	reader := preader.New(db)
+
+	read := []readers.Message{}
+	cursor := readers.FirstCursor
+	for pages := 0; cursor != ""; pages++ {
+		require.LessOrEqual(t, pages, len(msgs), "expected cursor pagination to end")
+
+		page, err := reader.ReadAll(chanID, readers.PageMetadata{Format: format1, Limit: limit, Cursor: cursor})
+		require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+		assert.LessOrEqual(t, len(page.Messages), limit, fmt.Sprintf("expected at most %d messages got %d", limit, len(page.Messages)))
+		for _, m := range page.Messages {
+			// Remove id as it is not sent by the client.
+			delete(m.(map[string]interface{}), "id")
+		}
+		read = append(read, page.Messages...)
+		cursor = page.NextCursor
+	}
+	assert.ElementsMatch(t, fromJSON(msgs), read, fmt.Sprintf("expected %v got %v", msgs, read))
+}
+
 func fromSenml(msg []senml.Message) []readers.Message {
 	var ret []readers.Message
 	for _, m := range msg {
@@ -0,0 +1,128 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/rules"
+)
+
+func createRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createRuleReq)
+		if err := req.validate(); err != nil {
+			return createRuleRes{}, err
+		}
+
+		r, err := toRule(req.ruleReq)
+		if err != nil {
+			return createRuleRes{}, err
+		}
+		saved, err := svc.CreateRule(ctx, req.token, r)
+		if err != nil {
+			return createRuleRes{}, err
+		}
+
+		return createRuleRes{ID: saved.ID}, nil
+	}
+}
+
+func viewRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewRuleReq)
+		if err := req.validate(); err != nil {
+			return viewRuleRes{}, err
+		}
+
+		r, err := svc.ViewRule(ctx, req.token, req.id)
+		if err != nil {
+			return viewRuleRes{}, err
+		}
+
+		return toViewRuleRes(r), nil
+	}
+}
+
+func updateRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateRuleReq)
+		if err := req.validate(); err != nil {
+			return updateRuleRes{}, err
+		}
+
+		r, err := toRule(req.ruleReq)
+		if err != nil {
+			return updateRuleRes{}, err
+		}
+		if err := svc.UpdateRule(ctx, req.token, r); err != nil {
+			return updateRuleRes{}, err
+		}
+
+		return updateRuleRes{}, nil
+	}
+}
+
+func listRulesEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listRulesReq)
+		if err := req.validate(); err != nil {
+			return listRulesRes{}, err
+		}
+
+		pm := rules.PageMetadata{
+			Offset:  req.offset,
+			Limit:   req.limit,
+			Channel: req.channel,
+		}
+		page, err := svc.ListRules(ctx, req.token, pm)
+		if err != nil {
+			return listRulesRes{}, err
+		}
+
+		res := listRulesRes{
+			Total:  page.Total,
+			Offset: page.Offset,
+			Limit:  page.Limit,
+			Rules:  []viewRuleRes{},
+		}
+		for _, r := range page.Rules {
+			res.Rules = append(res.Rules, toViewRuleRes(r))
+		}
+
+		return res, nil
+	}
+}
+
+func removeRuleEndpoint(svc rules.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewRuleReq)
+		if err := req.validate(); err != nil {
+			return removeRuleRes{}, err
+		}
+
+		if err := svc.RemoveRule(ctx, req.token, req.id); err != nil {
+			return removeRuleRes{}, err
+		}
+
+		return removeRuleRes{}, nil
+	}
+}
+
+func toRule(req ruleReq) (rules.Rule, error) {
+	cond, err := rules.ParseCondition(req.Condition)
+	if err != nil {
+		return rules.Rule{}, err
+	}
+
+	return rules.Rule{
+		ID:        req.id,
+		Name:      req.Name,
+		Channel:   req.Channel,
+		Subtopic:  req.Subtopic,
+		Condition: cond,
+		Actions:   req.Actions,
+	}, nil
+}
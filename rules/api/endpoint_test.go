@@ -0,0 +1,504 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/uuid"
+	"github.com/mainflux/mainflux/rules"
+	httpapi "github.com/mainflux/mainflux/rules/api"
+	"github.com/mainflux/mainflux/rules/mocks"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	contentType = "application/json"
+	email       = "user@example.com"
+	token       = "token"
+	wrongValue  = "wrong_value"
+	chanID      = "chan"
+	alarmsID    = "alarms"
+)
+
+var (
+	notFoundRes = toJSON(httputil.ErrorRes{Err: errors.ErrNotFound.Error()})
+	unauthRes   = toJSON(httputil.ErrorRes{Err: errors.ErrAuthentication.Error()})
+)
+
+type testRequest struct {
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	token       string
+	body        io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+	if tr.token != "" {
+		req.Header.Set("Authorization", httputil.BearerPrefix+tr.token)
+	}
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+	return tr.client.Do(req)
+}
+
+func newService() rules.Service {
+	auth := mocks.NewAuth(map[string]string{token: email})
+	things := mocks.NewThingsService(map[string]string{chanID: email, alarmsID: email})
+	cfg := rules.Config{WebhookTimeout: time.Second}
+	return rules.New(auth, things, mocks.NewRuleRepository(), uuid.NewMock(), mocks.NewPublisher(), mocks.NewNotifier(), cfg)
+}
+
+func newServer(svc rules.Service) *httptest.Server {
+	mux := httpapi.MakeHandler(svc, mocktracer.New())
+	return httptest.NewServer(mux)
+}
+
+func toJSON(data interface{}) string {
+	jsonData, _ := json.Marshal(data)
+	return string(jsonData)
+}
+
+type ruleReq struct {
+	Name      string         `json:"name,omitempty"`
+	Channel   string         `json:"channel,omitempty"`
+	Subtopic  string         `json:"subtopic,omitempty"`
+	Condition string         `json:"condition,omitempty"`
+	Actions   []rules.Action `json:"actions,omitempty"`
+}
+
+type ruleRes struct {
+	ID        string         `json:"id"`
+	Owner     string         `json:"owner"`
+	Name      string         `json:"name,omitempty"`
+	Channel   string         `json:"channel"`
+	Subtopic  string         `json:"subtopic,omitempty"`
+	Condition string         `json:"condition"`
+	Actions   []rules.Action `json:"actions"`
+}
+
+type rulesPageRes struct {
+	Total  uint64    `json:"total"`
+	Offset uint64    `json:"offset"`
+	Limit  uint64    `json:"limit"`
+	Rules  []ruleRes `json:"rules"`
+}
+
+var validReq = ruleReq{
+	Name:      "high temperature",
+	Channel:   chanID,
+	Condition: "temperature > 30 for 5m",
+	Actions:   []rules.Action{{Type: rules.Publish, Channel: alarmsID}},
+}
+
+func TestCreate(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	invalidCond := validReq
+	invalidCond.Condition = "temperature"
+
+	invalidAction := validReq
+	invalidAction.Actions = []rules.Action{{Type: rules.Webhook, URL: "ftp://example.com"}}
+
+	noActions := validReq
+	noActions.Actions = nil
+
+	loop := validReq
+	loop.Actions = []rules.Action{{Type: rules.Publish, Channel: chanID}}
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		location    string
+	}{
+		{
+			desc:        "create rule",
+			req:         toJSON(validReq),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			location:    fmt.Sprintf("/rules/%s%012d", uuid.Prefix, 1),
+		},
+		{
+			desc:        "create rule with invalid condition",
+			req:         toJSON(invalidCond),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create rule with invalid action",
+			req:         toJSON(invalidAction),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create rule without actions",
+			req:         toJSON(noActions),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create rule publishing to its own channel",
+			req:         toJSON(loop),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create rule with invalid token",
+			req:         toJSON(validReq),
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "create rule with invalid request format",
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create rule without content type",
+			req:         toJSON(validReq),
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/rules", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		location := res.Header.Get("Location")
+		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
+	}
+}
+
+func TestView(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	r := createRule(t, ts)
+	data := toJSON(r)
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+		res    string
+	}{
+		{
+			desc:   "view rule",
+			id:     r.ID,
+			auth:   token,
+			status: http.StatusOK,
+			res:    data,
+		},
+		{
+			desc:   "view non-existing rule",
+			id:     wrongValue,
+			auth:   token,
+			status: http.StatusNotFound,
+			res:    notFoundRes,
+		},
+		{
+			desc:   "view rule with invalid token",
+			id:     r.ID,
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+			res:    unauthRes,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/rules/%s", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		if tc.status == http.StatusOK {
+			var body ruleRes
+			err = json.NewDecoder(res.Body).Decode(&body)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			assert.Equal(t, tc.res, toJSON(body), fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, toJSON(body)))
+			continue
+		}
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.res, strings.Trim(string(body), "\n"), fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, string(body)))
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	r := createRule(t, ts)
+
+	updated := validReq
+	updated.Condition = "temperature > 40"
+
+	invalidCond := validReq
+	invalidCond.Condition = "temperature ~ 40"
+
+	cases := []struct {
+		desc        string
+		id          string
+		req         string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "update rule",
+			id:          r.ID,
+			req:         toJSON(updated),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "update rule with invalid condition",
+			id:          r.ID,
+			req:         toJSON(invalidCond),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update non-existing rule",
+			id:          wrongValue,
+			req:         toJSON(updated),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "update rule with invalid token",
+			id:          r.ID,
+			req:         toJSON(updated),
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "update rule without content type",
+			id:          r.ID,
+			req:         toJSON(updated),
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/rules/%s", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestList(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	n := 5
+	for i := 0; i < n; i++ {
+		createRule(t, ts)
+	}
+
+	cases := []struct {
+		desc   string
+		query  string
+		auth   string
+		status int
+		size   int
+	}{
+		{
+			desc:   "list rules",
+			query:  "",
+			auth:   token,
+			status: http.StatusOK,
+			size:   n,
+		},
+		{
+			desc:   "list rules with offset and limit",
+			query:  "?offset=3&limit=1",
+			auth:   token,
+			status: http.StatusOK,
+			size:   1,
+		},
+		{
+			desc:   "list rules of the channel",
+			query:  fmt.Sprintf("?channel=%s", alarmsID),
+			auth:   token,
+			status: http.StatusOK,
+			size:   0,
+		},
+		{
+			desc:   "list rules with invalid limit",
+			query:  "?limit=1000",
+			auth:   token,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "list rules with invalid offset",
+			query:  "?offset=invalid",
+			auth:   token,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "list rules with invalid token",
+			query:  "",
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/rules%s", ts.URL, tc.query),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+		var page rulesPageRes
+		err = json.NewDecoder(res.Body).Decode(&page)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.size, len(page.Rules), fmt.Sprintf("%s: expected %d rules got %d", tc.desc, tc.size, len(page.Rules)))
+	}
+}
+
+func TestRemove(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	r := createRule(t, ts)
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "remove rule with invalid token",
+			id:     r.ID,
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "remove rule",
+			id:     r.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove removed rule",
+			id:     r.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/rules/%s", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func createRule(t *testing.T, ts *httptest.Server) ruleRes {
+	req := testRequest{
+		client:      ts.Client(),
+		method:      http.MethodPost,
+		url:         fmt.Sprintf("%s/rules", ts.URL),
+		contentType: contentType,
+		token:       token,
+		body:        strings.NewReader(toJSON(validReq)),
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error creating rule: %s", err))
+	require.Equal(t, http.StatusCreated, res.StatusCode, "unexpected status creating rule")
+
+	cond, err := rules.ParseCondition(validReq.Condition)
+	require.Nil(t, err, fmt.Sprintf("unexpected error parsing condition: %s", err))
+
+	id := strings.TrimPrefix(res.Header.Get("Location"), "/rules/")
+	return ruleRes{
+		ID:        id,
+		Owner:     email,
+		Name:      validReq.Name,
+		Channel:   validReq.Channel,
+		Condition: cond.String(),
+		Actions:   validReq.Actions,
+	}
+}
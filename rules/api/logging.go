@@ -0,0 +1,105 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/rules"
+)
+
+var _ rules.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    rules.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc rules.Service, logger log.Logger) rules.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) CreateRule(ctx context.Context, token string, r rules.Rule) (saved rules.Rule, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_rule with the id %s for channel %s took %s to complete", saved.ID, r.Channel, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateRule(ctx, token, r)
+}
+
+func (lm *loggingMiddleware) ViewRule(ctx context.Context, token, id string) (r rules.Rule, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_rule with the id %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewRule(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) UpdateRule(ctx context.Context, token string, r rules.Rule) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_rule with the id %s took %s to complete", r.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateRule(ctx, token, r)
+}
+
+func (lm *loggingMiddleware) ListRules(ctx context.Context, token string, pm rules.PageMetadata) (page rules.Page, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_rules for channel %s took %s to complete", pm.Channel, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListRules(ctx, token, pm)
+}
+
+func (lm *loggingMiddleware) RemoveRule(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_rule with the id %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveRule(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Consume(msg interface{}) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method consume took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Consume(msg)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/rules"
+)
+
+var _ rules.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     rules.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and latency.
+func MetricsMiddleware(svc rules.Service, counter metrics.Counter, latency metrics.Histogram) rules.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) CreateRule(ctx context.Context, token string, r rules.Rule) (rules.Rule, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_rule").Add(1)
+		ms.latency.With("method", "create_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateRule(ctx, token, r)
+}
+
+func (ms *metricsMiddleware) ViewRule(ctx context.Context, token, id string) (rules.Rule, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_rule").Add(1)
+		ms.latency.With("method", "view_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewRule(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) UpdateRule(ctx context.Context, token string, r rules.Rule) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_rule").Add(1)
+		ms.latency.With("method", "update_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateRule(ctx, token, r)
+}
+
+func (ms *metricsMiddleware) ListRules(ctx context.Context, token string, pm rules.PageMetadata) (rules.Page, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_rules").Add(1)
+		ms.latency.With("method", "list_rules").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListRules(ctx, token, pm)
+}
+
+func (ms *metricsMiddleware) RemoveRule(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_rule").Add(1)
+		ms.latency.With("method", "remove_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveRule(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Consume(msg interface{}) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "consume").Add(1)
+		ms.latency.With("method", "consume").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Consume(msg)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/rules"
+)
+
+const maxLimitSize = 100
+
+var (
+	errInvalidChannel = errors.New("invalid Rule channel")
+	errMissingActions = errors.New("missing Rule actions")
+)
+
+type ruleReq struct {
+	token     string
+	id        string
+	Name      string         `json:"name,omitempty"`
+	Channel   string         `json:"channel,omitempty"`
+	Subtopic  string         `json:"subtopic,omitempty"`
+	Condition string         `json:"condition,omitempty"`
+	Actions   []rules.Action `json:"actions,omitempty"`
+}
+
+func (req ruleReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+	if req.Channel == "" {
+		return errInvalidChannel
+	}
+	if _, err := rules.ParseCondition(req.Condition); err != nil {
+		return err
+	}
+	if len(req.Actions) == 0 {
+		return errMissingActions
+	}
+	for _, a := range req.Actions {
+		if err := a.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type createRuleReq struct {
+	ruleReq
+}
+
+type updateRuleReq struct {
+	ruleReq
+}
+
+func (req updateRuleReq) validate() error {
+	if req.id == "" {
+		return errors.ErrNotFound
+	}
+	return req.ruleReq.validate()
+}
+
+type viewRuleReq struct {
+	token string
+	id    string
+}
+
+func (req viewRuleReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+	if req.id == "" {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+type listRulesReq struct {
+	token   string
+	channel string
+	offset  uint64
+	limit   uint64
+}
+
+func (req listRulesReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return errors.ErrInvalidQueryParams
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/rules"
+)
+
+var (
+	_ mainflux.Response = (*createRuleRes)(nil)
+	_ mainflux.Response = (*updateRuleRes)(nil)
+	_ mainflux.Response = (*viewRuleRes)(nil)
+	_ mainflux.Response = (*listRulesRes)(nil)
+	_ mainflux.Response = (*removeRuleRes)(nil)
+)
+
+type createRuleRes struct {
+	ID string
+}
+
+func (res createRuleRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res createRuleRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/rules/%s", res.ID),
+	}
+}
+
+func (res createRuleRes) Empty() bool {
+	return true
+}
+
+type updateRuleRes struct{}
+
+func (res updateRuleRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updateRuleRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res updateRuleRes) Empty() bool {
+	return true
+}
+
+type viewRuleRes struct {
+	ID        string         `json:"id"`
+	Owner     string         `json:"owner"`
+	Name      string         `json:"name,omitempty"`
+	Channel   string         `json:"channel"`
+	Subtopic  string         `json:"subtopic,omitempty"`
+	Condition string         `json:"condition"`
+	Actions   []rules.Action `json:"actions"`
+	Created   time.Time      `json:"created"`
+}
+
+func (res viewRuleRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewRuleRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewRuleRes) Empty() bool {
+	return false
+}
+
+type listRulesRes struct {
+	Total  uint64        `json:"total"`
+	Offset uint64        `json:"offset"`
+	Limit  uint64        `json:"limit"`
+	Rules  []viewRuleRes `json:"rules"`
+}
+
+func (res listRulesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listRulesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listRulesRes) Empty() bool {
+	return false
+}
+
+type removeRuleRes struct{}
+
+func (res removeRuleRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRuleRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRuleRes) Empty() bool {
+	return true
+}
+
+func toViewRuleRes(r rules.Rule) viewRuleRes {
+	return viewRuleRes{
+		ID:        r.ID,
+		Owner:     r.Owner,
+		Name:      r.Name,
+		Channel:   r.Channel,
+		Subtopic:  r.Subtopic,
+		Condition: r.Condition.String(),
+		Actions:   r.Actions,
+		Created:   r.Created,
+	}
+}
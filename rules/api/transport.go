@@ -0,0 +1,211 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/rules"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	contentType = "application/json"
+	defLimit    = 10
+)
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(svc rules.Service, tracer opentracing.Tracer) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	mux := bone.New()
+
+	mux.Post("/rules", kithttp.NewServer(
+		kitot.TraceServer(tracer, "create_rule")(createRuleEndpoint(svc)),
+		decodeCreate,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/rules/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_rule")(viewRuleEndpoint(svc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Put("/rules/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "update_rule")(updateRuleEndpoint(svc)),
+		decodeUpdate,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/rules", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_rules")(listRulesEndpoint(svc)),
+		decodeList,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/rules/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_rule")(removeRuleEndpoint(svc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.GetFunc("/health", mainflux.Health("rules"))
+	mux.Handle("/metrics", promhttp.Handler())
+
+	return mux
+}
+
+func decodeCreate(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	var req createRuleReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	t, err := httputil.ExtractAuthToken(r)
+	if err != nil {
+		return nil, err
+	}
+	req.token = t
+
+	return req, nil
+}
+
+func decodeUpdate(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	var req updateRuleReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	t, err := httputil.ExtractAuthToken(r)
+	if err != nil {
+		return nil, err
+	}
+	req.token = t
+	req.id = bone.GetValue(r, "id")
+
+	return req, nil
+}
+
+func decodeView(_ context.Context, r *http.Request) (interface{}, error) {
+	t, err := httputil.ExtractAuthToken(r)
+	if err != nil {
+		return nil, err
+	}
+	req := viewRuleReq{
+		token: t,
+		id:    bone.GetValue(r, "id"),
+	}
+
+	return req, nil
+}
+
+func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
+	t, err := httputil.ExtractAuthToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := httputil.ReadUintQuery(r, "offset", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := httputil.ReadUintQuery(r, "limit", defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := httputil.ReadStringQuery(r, "channel", "")
+	if err != nil {
+		return nil, err
+	}
+
+	req := listRulesReq{
+		token:   t,
+		offset:  o,
+		limit:   l,
+		channel: c,
+	}
+
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	switch {
+	case errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errInvalidChannel),
+		errors.Contains(err, errMissingActions),
+		errors.Contains(err, rules.ErrInvalidCondition),
+		errors.Contains(err, rules.ErrInvalidAction),
+		errors.Contains(err, rules.ErrLoop),
+		errors.Contains(err, errors.ErrInvalidQueryParams):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Contains(err, errors.ErrAuthentication):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrAuthorization):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Contains(err, errors.ErrConflict):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+
+	case errors.Contains(err, errors.ErrCreateEntity),
+		errors.Contains(err, errors.ErrUpdateEntity),
+		errors.Contains(err, errors.ErrViewEntity),
+		errors.Contains(err, errors.ErrRemoveEntity):
+		w.WriteHeader(http.StatusInternalServerError)
+
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	if errorVal, ok := err.(errors.Error); ok {
+		w.Header().Set("Content-Type", contentType)
+		if err := json.NewEncoder(w).Encode(httputil.ErrorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package rules contains the domain concept definitions needed to support
+// Mainflux rules engine service functionality. Rule is a user-defined
+// condition over the SenML records published to a channel, and a set of
+// actions triggered once the condition is satisfied.
+package rules
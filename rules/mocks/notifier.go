@@ -0,0 +1,41 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"sync"
+
+	notifiers "github.com/mainflux/mainflux/consumers/notifiers"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+var _ notifiers.Notifier = (*Notifier)(nil)
+
+// Notifier is the Notifier mock recording the notified contacts.
+type Notifier struct {
+	mu       sync.Mutex
+	contacts []string
+}
+
+// NewNotifier returns a new Notifier mock.
+func NewNotifier() *Notifier {
+	return &Notifier{}
+}
+
+// Notify records the notified contacts.
+func (n *Notifier) Notify(from string, to []string, msg messaging.Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.contacts = append(n.contacts, to...)
+	return nil
+}
+
+// Contacts returns and clears the notified contacts.
+func (n *Notifier) Contacts() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	ret := n.contacts
+	n.contacts = nil
+	return ret
+}
@@ -0,0 +1,40 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+var _ messaging.Publisher = (*Publisher)(nil)
+
+// Publisher is the Publisher mock recording the published messages.
+type Publisher struct {
+	mu       sync.Mutex
+	messages []messaging.Message
+}
+
+// NewPublisher returns a new Publisher mock.
+func NewPublisher() *Publisher {
+	return &Publisher{}
+}
+
+// Publish records the published message.
+func (pub *Publisher) Publish(topic string, msg messaging.Message) error {
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+	pub.messages = append(pub.messages, msg)
+	return nil
+}
+
+// Messages returns and clears the recorded messages.
+func (pub *Publisher) Messages() []messaging.Message {
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+	ret := pub.messages
+	pub.messages = nil
+	return ret
+}
@@ -0,0 +1,115 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/rules"
+)
+
+var _ rules.RuleRepository = (*ruleRepositoryMock)(nil)
+
+type ruleRepositoryMock struct {
+	mu    sync.Mutex
+	rules map[string]rules.Rule
+}
+
+// NewRuleRepository creates in-memory rule repository.
+func NewRuleRepository() rules.RuleRepository {
+	return &ruleRepositoryMock{
+		rules: make(map[string]rules.Rule),
+	}
+}
+
+func (rrm *ruleRepositoryMock) Save(_ context.Context, r rules.Rule) (rules.Rule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	if _, ok := rrm.rules[r.ID]; ok {
+		return rules.Rule{}, errors.ErrConflict
+	}
+	rrm.rules[r.ID] = r
+
+	return r, nil
+}
+
+func (rrm *ruleRepositoryMock) Update(_ context.Context, r rules.Rule) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	cur, ok := rrm.rules[r.ID]
+	if !ok || cur.Owner != r.Owner {
+		return errors.ErrNotFound
+	}
+	r.Created = cur.Created
+	rrm.rules[r.ID] = r
+
+	return nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveByID(_ context.Context, owner, id string) (rules.Rule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	r, ok := rrm.rules[id]
+	if !ok || r.Owner != owner {
+		return rules.Rule{}, errors.ErrNotFound
+	}
+
+	return r, nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveAll(_ context.Context, owner string, pm rules.PageMetadata) (rules.Page, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	var all []rules.Rule
+	for _, r := range rrm.rules {
+		if r.Owner == owner && (pm.Channel == "" || r.Channel == pm.Channel) {
+			all = append(all, r)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+
+	page := rules.Page{
+		PageMetadata: pm,
+		Rules:        []rules.Rule{},
+	}
+	page.Total = uint64(len(all))
+	for i := pm.Offset; i < uint64(len(all)) && i < pm.Offset+pm.Limit; i++ {
+		page.Rules = append(page.Rules, all[i])
+	}
+
+	return page, nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveByChannel(_ context.Context, chanID string) ([]rules.Rule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	var ret []rules.Rule
+	for _, r := range rrm.rules {
+		if r.Channel == chanID {
+			ret = append(ret, r)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ID < ret[j].ID })
+
+	return ret, nil
+}
+
+func (rrm *ruleRepositoryMock) Remove(_ context.Context, owner, id string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	if r, ok := rrm.rules[id]; ok && r.Owner == owner {
+		delete(rrm.rules, id)
+	}
+
+	return nil
+}
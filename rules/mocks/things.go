@@ -0,0 +1,65 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+var _ mainflux.ThingsServiceClient = (*thingsServiceMock)(nil)
+
+type thingsServiceMock struct {
+	channels map[string]string
+}
+
+// NewThingsService returns mock implementation of things service. Channels
+// map contains the owners of the channels, identified by the channel IDs.
+func NewThingsService(channels map[string]string) mainflux.ThingsServiceClient {
+	return &thingsServiceMock{channels}
+}
+
+func (svc thingsServiceMock) CanAccessByKey(context.Context, *mainflux.AccessByKeyReq, ...grpc.CallOption) (*mainflux.ThingID, error) {
+	panic("not implemented")
+}
+
+func (svc thingsServiceMock) CanAccessByID(context.Context, *mainflux.AccessByIDReq, ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
+func (svc thingsServiceMock) IsChannelOwner(ctx context.Context, in *mainflux.ChannelOwnerReq, opts ...grpc.CallOption) (*empty.Empty, error) {
+	if owner, ok := svc.channels[in.GetChanID()]; ok && owner == in.GetOwner() {
+		return &empty.Empty{}, nil
+	}
+	return nil, errors.ErrAuthorization
+}
+
+func (svc thingsServiceMock) IsChannelsOwner(ctx context.Context, in *mainflux.ChannelsOwnerReq, opts ...grpc.CallOption) (*empty.Empty, error) {
+	for _, chanID := range in.GetChanIDs() {
+		if owner, ok := svc.channels[chanID]; !ok || owner != in.GetOwner() {
+			return nil, errors.ErrAuthorization
+		}
+	}
+	return &empty.Empty{}, nil
+}
+
+func (svc thingsServiceMock) Identify(context.Context, *mainflux.Token, ...grpc.CallOption) (*mainflux.ThingID, error) {
+	panic("not implemented")
+}
+
+func (svc thingsServiceMock) ViewThing(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.Thing, error) {
+	panic("not implemented")
+}
+
+func (svc thingsServiceMock) ViewChannel(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Channel, error) {
+	panic("not implemented")
+}
+
+func (svc thingsServiceMock) ViewQuota(context.Context, *mainflux.ThingID, ...grpc.CallOption) (*mainflux.Quota, error) {
+	panic("not implemented")
+}
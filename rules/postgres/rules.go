@@ -0,0 +1,254 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/rules"
+)
+
+var _ rules.RuleRepository = (*rulesRepo)(nil)
+
+const errDuplicate = "unique_violation"
+
+type rulesRepo struct {
+	db Database
+}
+
+// New instantiates a PostgreSQL implementation of Rules repository.
+func New(db Database) rules.RuleRepository {
+	return &rulesRepo{
+		db: db,
+	}
+}
+
+func (repo rulesRepo) Save(ctx context.Context, r rules.Rule) (rules.Rule, error) {
+	q := `INSERT INTO rules (id, owner, name, channel, subtopic, condition, actions, created)
+		  VALUES (:id, :owner, :name, :channel, :subtopic, :condition, :actions, :created)`
+
+	dbr, err := toDBRule(r)
+	if err != nil {
+		return rules.Rule{}, errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	if _, err := repo.db.NamedExecContext(ctx, q, dbr); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errDuplicate {
+			return rules.Rule{}, errors.Wrap(errors.ErrConflict, err)
+		}
+		return rules.Rule{}, errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	return r, nil
+}
+
+func (repo rulesRepo) Update(ctx context.Context, r rules.Rule) error {
+	q := `UPDATE rules SET name = :name, channel = :channel, subtopic = :subtopic, condition = :condition, actions = :actions
+		  WHERE owner = :owner AND id = :id`
+
+	dbr, err := toDBRule(r)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	res, err := repo.db.NamedExecContext(ctx, q, dbr)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	if cnt == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (repo rulesRepo) RetrieveByID(ctx context.Context, owner, id string) (rules.Rule, error) {
+	q := `SELECT id, owner, name, channel, subtopic, condition, actions, created FROM rules WHERE owner = $1 AND id = $2`
+
+	dbr := dbRule{}
+	if err := repo.db.QueryRowxContext(ctx, q, owner, id).StructScan(&dbr); err != nil {
+		if err == sql.ErrNoRows {
+			return rules.Rule{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return rules.Rule{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	r, err := toRule(dbr)
+	if err != nil {
+		return rules.Rule{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	return r, nil
+}
+
+func (repo rulesRepo) RetrieveAll(ctx context.Context, owner string, pm rules.PageMetadata) (rules.Page, error) {
+	condition := "WHERE owner = :owner"
+	if pm.Channel != "" {
+		condition = fmt.Sprintf("%s AND channel = :channel", condition)
+	}
+
+	q := fmt.Sprintf(`SELECT id, owner, name, channel, subtopic, condition, actions, created FROM rules
+		  %s ORDER BY id LIMIT :limit OFFSET :offset`, condition)
+	params := map[string]interface{}{
+		"owner":   owner,
+		"channel": pm.Channel,
+		"limit":   pm.Limit,
+		"offset":  pm.Offset,
+	}
+
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return rules.Page{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	items := []rules.Rule{}
+	for rows.Next() {
+		dbr := dbRule{}
+		if err := rows.StructScan(&dbr); err != nil {
+			return rules.Page{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+
+		r, err := toRule(dbr)
+		if err != nil {
+			return rules.Page{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+
+		items = append(items, r)
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM rules %s`, condition)
+	total, err := total(ctx, repo.db, cq, params)
+	if err != nil {
+		return rules.Page{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	page := rules.Page{
+		PageMetadata: pm,
+		Rules:        items,
+	}
+	page.Total = total
+
+	return page, nil
+}
+
+func (repo rulesRepo) RetrieveByChannel(ctx context.Context, chanID string) ([]rules.Rule, error) {
+	q := `SELECT id, owner, name, channel, subtopic, condition, actions, created FROM rules WHERE channel = :channel ORDER BY id`
+
+	rows, err := repo.db.NamedQueryContext(ctx, q, map[string]interface{}{"channel": chanID})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	var items []rules.Rule
+	for rows.Next() {
+		dbr := dbRule{}
+		if err := rows.StructScan(&dbr); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+
+		r, err := toRule(dbr)
+		if err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+
+		items = append(items, r)
+	}
+
+	return items, nil
+}
+
+func (repo rulesRepo) Remove(ctx context.Context, owner, id string) error {
+	q := `DELETE FROM rules WHERE owner = :owner AND id = :id`
+
+	params := map[string]interface{}{
+		"owner": owner,
+		"id":    id,
+	}
+	if _, err := repo.db.NamedExecContext(ctx, q, params); err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
+func total(ctx context.Context, db Database, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	var total uint64
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+type dbRule struct {
+	ID        string    `db:"id"`
+	Owner     string    `db:"owner"`
+	Name      string    `db:"name"`
+	Channel   string    `db:"channel"`
+	Subtopic  string    `db:"subtopic"`
+	Condition string    `db:"condition"`
+	Actions   []byte    `db:"actions"`
+	Created   time.Time `db:"created"`
+}
+
+func toDBRule(r rules.Rule) (dbRule, error) {
+	actions, err := json.Marshal(r.Actions)
+	if err != nil {
+		return dbRule{}, err
+	}
+
+	return dbRule{
+		ID:        r.ID,
+		Owner:     r.Owner,
+		Name:      r.Name,
+		Channel:   r.Channel,
+		Subtopic:  r.Subtopic,
+		Condition: r.Condition.String(),
+		Actions:   actions,
+		Created:   r.Created,
+	}, nil
+}
+
+func toRule(dbr dbRule) (rules.Rule, error) {
+	cond, err := rules.ParseCondition(dbr.Condition)
+	if err != nil {
+		return rules.Rule{}, err
+	}
+
+	var actions []rules.Action
+	if err := json.Unmarshal(dbr.Actions, &actions); err != nil {
+		return rules.Rule{}, err
+	}
+
+	return rules.Rule{
+		ID:        dbr.ID,
+		Owner:     dbr.Owner,
+		Name:      dbr.Name,
+		Channel:   dbr.Channel,
+		Subtopic:  dbr.Subtopic,
+		Condition: cond,
+		Actions:   actions,
+		Created:   dbr.Created,
+	}, nil
+}
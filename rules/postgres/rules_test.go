@@ -0,0 +1,236 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/rules"
+	"github.com/mainflux/mainflux/rules/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	owner    = "owner@example.com"
+	numRules = 10
+)
+
+func newRule(t *testing.T, channel string) rules.Rule {
+	id, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cond, err := rules.ParseCondition("temperature > 30 for 5m")
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	return rules.Rule{
+		ID:        id,
+		Owner:     owner,
+		Name:      "rule",
+		Channel:   channel,
+		Condition: cond,
+		Actions: []rules.Action{
+			{Type: rules.Publish, Channel: "alarms"},
+		},
+		Created: time.Now().UTC().Round(time.Millisecond),
+	}
+}
+
+func TestSave(t *testing.T) {
+	repo := postgres.New(postgres.NewDatabase(db))
+
+	r := newRule(t, "save")
+
+	cases := []struct {
+		desc string
+		rule rules.Rule
+		err  error
+	}{
+		{
+			desc: "save successfully",
+			rule: r,
+			err:  nil,
+		},
+		{
+			desc: "save duplicate",
+			rule: r,
+			err:  errors.ErrConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := repo.Save(context.Background(), tc.rule)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	repo := postgres.New(postgres.NewDatabase(db))
+
+	r := newRule(t, "update")
+	_, err := repo.Save(context.Background(), r)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	updated := r
+	updated.Name = "updated"
+	updated.Condition.Value = 40.0
+
+	wrongOwner := updated
+	wrongOwner.Owner = "wrong"
+
+	cases := []struct {
+		desc string
+		rule rules.Rule
+		err  error
+	}{
+		{
+			desc: "update existing rule",
+			rule: updated,
+			err:  nil,
+		},
+		{
+			desc: "update rule with wrong owner",
+			rule: wrongOwner,
+			err:  errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Update(context.Background(), tc.rule)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	saved, err := repo.RetrieveByID(context.Background(), owner, r.ID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, updated.Name, saved.Name, "expected name to be updated")
+	assert.Equal(t, updated.Condition, saved.Condition, "expected condition to be updated")
+}
+
+func TestRetrieveByID(t *testing.T) {
+	repo := postgres.New(postgres.NewDatabase(db))
+
+	r := newRule(t, "view")
+	_, err := repo.Save(context.Background(), r)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		id    string
+		rule  rules.Rule
+		err   error
+	}{
+		{
+			desc:  "retrieve existing rule",
+			owner: owner,
+			id:    r.ID,
+			rule:  r,
+			err:   nil,
+		},
+		{
+			desc:  "retrieve rule with wrong owner",
+			owner: "wrong",
+			id:    r.ID,
+			rule:  rules.Rule{},
+			err:   errors.ErrNotFound,
+		},
+		{
+			desc:  "retrieve non-existing rule",
+			owner: owner,
+			id:    "non-existing",
+			rule:  rules.Rule{},
+			err:   errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		rule, err := repo.RetrieveByID(context.Background(), tc.owner, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			tc.rule.Created = rule.Created
+		}
+		assert.Equal(t, tc.rule, rule, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.rule, rule))
+	}
+}
+
+func TestRetrieveAll(t *testing.T) {
+	repo := postgres.New(postgres.NewDatabase(db))
+
+	for i := 0; i < numRules; i++ {
+		_, err := repo.Save(context.Background(), newRule(t, "list"))
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc  string
+		owner string
+		pm    rules.PageMetadata
+		size  int
+		total uint64
+	}{
+		{
+			desc:  "retrieve all rules of the channel",
+			owner: owner,
+			pm:    rules.PageMetadata{Offset: 0, Limit: numRules, Channel: "list"},
+			size:  numRules,
+			total: numRules,
+		},
+		{
+			desc:  "retrieve a page of rules of the channel",
+			owner: owner,
+			pm:    rules.PageMetadata{Offset: 5, Limit: 3, Channel: "list"},
+			size:  3,
+			total: numRules,
+		},
+		{
+			desc:  "retrieve rules with wrong owner",
+			owner: "wrong",
+			pm:    rules.PageMetadata{Offset: 0, Limit: numRules, Channel: "list"},
+			size:  0,
+			total: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := repo.RetrieveAll(context.Background(), tc.owner, tc.pm)
+		assert.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.size, len(page.Rules), fmt.Sprintf("%s: expected %d rules got %d\n", tc.desc, tc.size, len(page.Rules)))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.total, page.Total))
+	}
+}
+
+func TestRetrieveByChannel(t *testing.T) {
+	repo := postgres.New(postgres.NewDatabase(db))
+
+	for i := 0; i < numRules; i++ {
+		_, err := repo.Save(context.Background(), newRule(t, "channel"))
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	rs, err := repo.RetrieveByChannel(context.Background(), "channel")
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, numRules, len(rs), fmt.Sprintf("expected %d rules got %d\n", numRules, len(rs)))
+
+	rs, err = repo.RetrieveByChannel(context.Background(), "non-existing")
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, 0, len(rs), fmt.Sprintf("expected no rules got %d\n", len(rs)))
+}
+
+func TestRemove(t *testing.T) {
+	repo := postgres.New(postgres.NewDatabase(db))
+
+	r := newRule(t, "remove")
+	_, err := repo.Save(context.Background(), r)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = repo.Remove(context.Background(), owner, r.ID)
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	_, err = repo.RetrieveByID(context.Background(), owner, r.ID)
+	assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("expected %s got %s\n", errors.ErrNotFound, err))
+}
@@ -0,0 +1,207 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// Action types.
+const (
+	// Publish action publishes the triggering records to another channel.
+	Publish = "publish"
+	// Webhook action posts the triggering records to the given URL.
+	Webhook = "webhook"
+	// Notify action sends the triggering records to the given contacts.
+	Notify = "notify"
+)
+
+var (
+	// ErrInvalidCondition indicates malformed rule condition.
+	ErrInvalidCondition = errors.New("invalid rule condition")
+
+	// ErrInvalidAction indicates malformed rule action.
+	ErrInvalidAction = errors.New("invalid rule action")
+
+	conditionRegexp = regexp.MustCompile(`^\s*([^\s=!<>]+)\s*(==|!=|<=|>=|<|>)\s*("[^"]*"|'[^']*'|[^\s"']+)(?:\s+for\s+(\S+))?\s*$`)
+)
+
+// Rule represents a user-defined rule. Rule is evaluated for every SenML
+// record published to its channel and subtopic, and its actions are
+// triggered once the condition is satisfied.
+type Rule struct {
+	ID        string
+	Owner     string
+	Name      string
+	Channel   string
+	Subtopic  string
+	Condition Condition
+	Actions   []Action
+	Created   time.Time
+}
+
+// Condition is a comparison of the SenML record value to the given value.
+// Condition is satisfied once it holds for all the records of the given
+// name, published by the same thing, over the period of For.
+type Condition struct {
+	// Field is the name of the SenML record.
+	Field string
+
+	// Operator is one of ==, !=, <, <=, > and >=.
+	Operator string
+
+	// Value is a float64, string or a bool compared to the record value.
+	Value interface{}
+
+	// For is the period the comparison must hold before the condition
+	// is satisfied. Zero value means that the condition is satisfied
+	// by the first record.
+	For time.Duration
+}
+
+// ParseCondition parses the condition in the form
+// "<field> <op> <value> [for <duration>]", e.g. "temperature > 30 for 5m".
+// String values must be quoted.
+func ParseCondition(s string) (Condition, error) {
+	m := conditionRegexp.FindStringSubmatch(s)
+	if m == nil {
+		return Condition{}, ErrInvalidCondition
+	}
+
+	c := Condition{
+		Field:    m[1],
+		Operator: m[2],
+	}
+
+	lit := m[3]
+	switch {
+	case len(lit) >= 2 && (lit[0] == '"' || lit[0] == '\'') && lit[len(lit)-1] == lit[0]:
+		c.Value = lit[1 : len(lit)-1]
+	case lit == "true" || lit == "false":
+		c.Value = lit == "true"
+		if c.Operator != "==" && c.Operator != "!=" {
+			return Condition{}, ErrInvalidCondition
+		}
+	default:
+		f, err := strconv.ParseFloat(lit, 64)
+		if err != nil {
+			return Condition{}, errors.Wrap(ErrInvalidCondition, err)
+		}
+		c.Value = f
+	}
+
+	if m[4] != "" {
+		d, err := time.ParseDuration(m[4])
+		if err != nil || d < 0 {
+			return Condition{}, ErrInvalidCondition
+		}
+		c.For = d
+	}
+
+	return c, nil
+}
+
+// String returns the condition in the form accepted by ParseCondition.
+func (c Condition) String() string {
+	var val string
+	switch v := c.Value.(type) {
+	case string:
+		val = strconv.Quote(v)
+	case float64:
+		val = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		val = fmt.Sprint(v)
+	}
+
+	s := fmt.Sprintf("%s %s %s", c.Field, c.Operator, val)
+	if c.For > 0 {
+		s = fmt.Sprintf("%s for %s", s, c.For)
+	}
+	return s
+}
+
+// Action represents the action triggered by the rule.
+type Action struct {
+	// Type is one of publish, webhook and notify.
+	Type string `json:"type"`
+
+	// Channel and Subtopic are the destination of the publish action.
+	Channel  string `json:"channel,omitempty"`
+	Subtopic string `json:"subtopic,omitempty"`
+
+	// URL is the webhook action URL.
+	URL string `json:"url,omitempty"`
+
+	// Contacts are the notify action receivers.
+	Contacts []string `json:"contacts,omitempty"`
+}
+
+// Validate returns an error if the action is malformed.
+func (a Action) Validate() error {
+	switch a.Type {
+	case Publish:
+		if a.Channel == "" {
+			return ErrInvalidAction
+		}
+	case Webhook:
+		if !strings.HasPrefix(a.URL, "http://") && !strings.HasPrefix(a.URL, "https://") {
+			return ErrInvalidAction
+		}
+	case Notify:
+		if len(a.Contacts) == 0 {
+			return ErrInvalidAction
+		}
+	default:
+		return ErrInvalidAction
+	}
+
+	return nil
+}
+
+// PageMetadata contains page metadata that helps navigation.
+type PageMetadata struct {
+	Total   uint64
+	Offset  uint64
+	Limit   uint64
+	Channel string
+}
+
+// Page contains page related metadata as well as a list of rules that
+// belong to this page.
+type Page struct {
+	PageMetadata
+	Rules []Rule
+}
+
+// RuleRepository specifies a rule persistence API.
+type RuleRepository interface {
+	// Save persists the rule.
+	Save(ctx context.Context, r Rule) (Rule, error)
+
+	// Update performs an update of the existing rule. A non-nil error is
+	// returned to indicate operation failure.
+	Update(ctx context.Context, r Rule) error
+
+	// RetrieveByID retrieves the rule having the provided identifier, that is
+	// owned by the specified user.
+	RetrieveByID(ctx context.Context, owner, id string) (Rule, error)
+
+	// RetrieveAll retrieves the subset of rules owned by the specified user.
+	RetrieveAll(ctx context.Context, owner string, pm PageMetadata) (Page, error)
+
+	// RetrieveByChannel retrieves all the rules evaluated for the messages
+	// published to the channel with the given ID.
+	RetrieveByChannel(ctx context.Context, chanID string) ([]Rule, error)
+
+	// Remove removes the rule having the provided identifier, that is owned
+	// by the specified user.
+	Remove(ctx context.Context, owner, id string) error
+}
@@ -0,0 +1,496 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/consumers/notifiers"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	mfsenml "github.com/mainflux/senml"
+)
+
+// Protocol is the protocol of the messages published by the rules.
+const Protocol = "rules"
+
+var (
+	// ErrMessage indicates an error converting a message to SenML records.
+	ErrMessage = errors.New("failed to convert to SenML records")
+
+	// ErrAction indicates failure to perform the rule action.
+	ErrAction = errors.New("failed to perform rule action")
+
+	// ErrLoop indicates that the rule publishes to the subject it's evaluated
+	// for, either directly or through the other rules.
+	ErrLoop = errors.New("rule publishes to its own channel")
+
+	errWebhookStatus = errors.New("unexpected webhook response status")
+)
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// CreateRule creates the rule evaluated for the messages published to
+	// the channel owned by the user identified by the provided key.
+	CreateRule(ctx context.Context, token string, r Rule) (Rule, error)
+
+	// ViewRule retrieves data about the rule identified with the provided ID,
+	// that belongs to the user identified by the provided key.
+	ViewRule(ctx context.Context, token, id string) (Rule, error)
+
+	// UpdateRule updates the rule identified by the provided ID, that
+	// belongs to the user identified by the provided key.
+	UpdateRule(ctx context.Context, token string, r Rule) error
+
+	// ListRules retrieves data about the subset of rules that belong to the
+	// user identified by the provided key.
+	ListRules(ctx context.Context, token string, pm PageMetadata) (Page, error)
+
+	// RemoveRule removes the rule identified by the provided ID, that
+	// belongs to the user identified by the provided key.
+	RemoveRule(ctx context.Context, token, id string) error
+
+	consumers.Consumer
+}
+
+// Config contains the rule actions configuration.
+type Config struct {
+	// From is the sender of the notifications sent by the notify actions.
+	From string
+
+	// WebhookTimeout is the timeout of the webhook action requests.
+	WebhookTimeout time.Duration
+
+	// WebhookAllowedNets are the non-public networks the webhook actions
+	// are allowed to call. Loopback, link-local and private addresses
+	// are rejected otherwise.
+	WebhookAllowedNets []*net.IPNet
+}
+
+// episode tracks the period the rule condition holds for the records of a single thing.
+type episode struct {
+	since float64
+	fired bool
+}
+
+var _ Service = (*rulesService)(nil)
+
+type rulesService struct {
+	auth      mainflux.AuthServiceClient
+	things    mainflux.ThingsServiceClient
+	rules     RuleRepository
+	idp       mainflux.IDProvider
+	publisher messaging.Publisher
+	notifier  notifiers.Notifier
+	client    *http.Client
+	from      string
+	mu        sync.Mutex
+	episodes  map[string]map[string]*episode
+}
+
+// New instantiates the rules service implementation.
+func New(auth mainflux.AuthServiceClient, things mainflux.ThingsServiceClient, rules RuleRepository, idp mainflux.IDProvider, publisher messaging.Publisher, notifier notifiers.Notifier, cfg Config) Service {
+	return &rulesService{
+		auth:      auth,
+		things:    things,
+		rules:     rules,
+		idp:       idp,
+		publisher: publisher,
+		notifier:  notifier,
+		client:    httputil.NewPublicClient(cfg.WebhookTimeout, cfg.WebhookAllowedNets),
+		from:      cfg.From,
+		episodes:  make(map[string]map[string]*episode),
+	}
+}
+
+func (rs *rulesService) CreateRule(ctx context.Context, token string, r Rule) (Rule, error) {
+	res, err := rs.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Rule{}, errors.Wrap(errors.ErrAuthentication, err)
+	}
+	if err := rs.authorize(ctx, res.GetEmail(), r); err != nil {
+		return Rule{}, err
+	}
+
+	r.ID, err = rs.idp.ID()
+	if err != nil {
+		return Rule{}, err
+	}
+	r.Owner = res.GetId()
+	r.Created = time.Now()
+	if err := rs.checkLoop(ctx, r); err != nil {
+		return Rule{}, err
+	}
+
+	return rs.rules.Save(ctx, r)
+}
+
+func (rs *rulesService) ViewRule(ctx context.Context, token, id string) (Rule, error) {
+	res, err := rs.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Rule{}, errors.Wrap(errors.ErrAuthentication, err)
+	}
+
+	return rs.rules.RetrieveByID(ctx, res.GetId(), id)
+}
+
+func (rs *rulesService) UpdateRule(ctx context.Context, token string, r Rule) error {
+	res, err := rs.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return errors.Wrap(errors.ErrAuthentication, err)
+	}
+	if err := rs.authorize(ctx, res.GetEmail(), r); err != nil {
+		return err
+	}
+
+	r.Owner = res.GetId()
+	if err := rs.checkLoop(ctx, r); err != nil {
+		return err
+	}
+	if err := rs.rules.Update(ctx, r); err != nil {
+		return err
+	}
+	rs.reset(r.ID)
+
+	return nil
+}
+
+func (rs *rulesService) ListRules(ctx context.Context, token string, pm PageMetadata) (Page, error) {
+	res, err := rs.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Page{}, errors.Wrap(errors.ErrAuthentication, err)
+	}
+
+	return rs.rules.RetrieveAll(ctx, res.GetId(), pm)
+}
+
+func (rs *rulesService) RemoveRule(ctx context.Context, token, id string) error {
+	res, err := rs.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return errors.Wrap(errors.ErrAuthentication, err)
+	}
+
+	if err := rs.rules.Remove(ctx, res.GetId(), id); err != nil {
+		return err
+	}
+	rs.reset(id)
+
+	return nil
+}
+
+func (rs *rulesService) Consume(message interface{}) error {
+	msgs, ok := message.([]senml.Message)
+	if !ok {
+		return ErrMessage
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	// All the records of the message share the channel, subtopic and publisher.
+	head := msgs[0]
+	rules, err := rs.rules.RetrieveByChannel(context.Background(), head.Channel)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, r := range rules {
+		if !matchSubtopic(r.Subtopic, head.Subtopic) {
+			continue
+		}
+		triggers := rs.evaluate(r, msgs)
+		if len(triggers) == 0 {
+			continue
+		}
+		for _, a := range r.Actions {
+			if err := rs.perform(r, a, triggers); err != nil {
+				errs = append(errs, fmt.Sprintf("rule %s %s action: %s", r.ID, a.Type, err))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Wrap(ErrAction, errors.New(strings.Join(errs, "; ")))
+	}
+	return nil
+}
+
+// authorize checks if the user owns the channel the rule is evaluated
+// for, as well as the channels the rule publishes to.
+func (rs *rulesService) authorize(ctx context.Context, owner string, r Rule) error {
+	chans := []string{r.Channel}
+	for _, a := range r.Actions {
+		if a.Type != Publish {
+			continue
+		}
+		chans = append(chans, a.Channel)
+	}
+
+	for _, ch := range chans {
+		req := &mainflux.ChannelOwnerReq{Owner: owner, ChanID: ch}
+		if _, err := rs.things.IsChannelOwner(ctx, req); err != nil {
+			return errors.Wrap(errors.ErrAuthorization, err)
+		}
+	}
+
+	return nil
+}
+
+// checkLoop follows the publish actions of the rule through the rules
+// evaluated for the published messages, and returns ErrLoop if the chain
+// leads back to the rule. The rule replaces its stored version, if any.
+func (rs *rulesService) checkLoop(ctx context.Context, r Rule) error {
+	visited := map[string]bool{r.ID: true}
+	queue := []Rule{r}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, a := range cur.Actions {
+			if a.Type != Publish {
+				continue
+			}
+			if a.Channel == r.Channel && matchSubtopic(r.Subtopic, a.Subtopic) {
+				return ErrLoop
+			}
+			next, err := rs.rules.RetrieveByChannel(ctx, a.Channel)
+			if err != nil {
+				return err
+			}
+			for _, n := range next {
+				if visited[n.ID] || !matchSubtopic(n.Subtopic, a.Subtopic) {
+					continue
+				}
+				visited[n.ID] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+
+	return nil
+}
+
+// evaluate returns the records triggering the rule actions. Actions
+// are triggered only once, when the condition becomes satisfied, and
+// are triggered again only after the condition stops holding.
+func (rs *rulesService) evaluate(r Rule, msgs []senml.Message) []senml.Message {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	episodes, ok := rs.episodes[r.ID]
+	if !ok {
+		episodes = make(map[string]*episode)
+		rs.episodes[r.ID] = episodes
+	}
+
+	var triggers []senml.Message
+	for _, msg := range msgs {
+		if msg.Name != r.Condition.Field {
+			continue
+		}
+		if !r.Condition.holds(msg) {
+			delete(episodes, msg.Publisher)
+			continue
+		}
+
+		t := msg.Time
+		if t == 0 {
+			t = float64(time.Now().UnixNano()) / 1e9
+		}
+		e, ok := episodes[msg.Publisher]
+		if !ok {
+			e = &episode{since: t}
+			episodes[msg.Publisher] = e
+		}
+		if !e.fired && t-e.since >= r.Condition.For.Seconds() {
+			e.fired = true
+			triggers = append(triggers, msg)
+		}
+	}
+
+	return triggers
+}
+
+// reset drops the evaluation state of the rule.
+func (rs *rulesService) reset(id string) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	delete(rs.episodes, id)
+}
+
+func (rs *rulesService) perform(r Rule, a Action, records []senml.Message) error {
+	payload, err := encode(records)
+	if err != nil {
+		return err
+	}
+
+	switch a.Type {
+	case Publish:
+		msg := messaging.Message{
+			Channel:   a.Channel,
+			Subtopic:  a.Subtopic,
+			Publisher: records[0].Publisher,
+			Protocol:  Protocol,
+			Payload:   payload,
+			Created:   time.Now().UnixNano(),
+		}
+		return rs.publisher.Publish(a.Channel, msg)
+	case Webhook:
+		return rs.callWebhook(r, a.URL, records)
+	case Notify:
+		if rs.notifier == nil {
+			return ErrAction
+		}
+		msg := messaging.Message{
+			Channel:   records[0].Channel,
+			Subtopic:  records[0].Subtopic,
+			Publisher: records[0].Publisher,
+			Protocol:  Protocol,
+			Payload:   payload,
+		}
+		return rs.notifier.Notify(rs.from, a.Contacts, msg)
+	default:
+		return ErrInvalidAction
+	}
+}
+
+type webhookReq struct {
+	RuleID    string          `json:"rule_id"`
+	RuleName  string          `json:"rule_name,omitempty"`
+	Condition string          `json:"condition"`
+	Records   []senml.Message `json:"records"`
+}
+
+func (rs *rulesService) callWebhook(r Rule, url string, records []senml.Message) error {
+	body, err := json.Marshal(webhookReq{
+		RuleID:    r.ID,
+		RuleName:  r.Name,
+		Condition: r.Condition.String(),
+		Records:   records,
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := rs.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return errors.Wrap(errWebhookStatus, errors.New(res.Status))
+	}
+	return nil
+}
+
+// encode encodes the records as SenML JSON message.
+func encode(records []senml.Message) ([]byte, error) {
+	var p mfsenml.Pack
+	for _, r := range records {
+		p.Records = append(p.Records, mfsenml.Record{
+			Name:        r.Name,
+			Unit:        r.Unit,
+			Time:        r.Time,
+			UpdateTime:  r.UpdateTime,
+			Value:       r.Value,
+			StringValue: r.StringValue,
+			DataValue:   r.DataValue,
+			BoolValue:   r.BoolValue,
+			Sum:         r.Sum,
+		})
+	}
+	return mfsenml.Encode(p, mfsenml.JSON)
+}
+
+// holds checks if the record value satisfies the condition.
+func (c Condition) holds(msg senml.Message) bool {
+	switch v := c.Value.(type) {
+	case float64:
+		if msg.Value == nil {
+			return false
+		}
+		return compare(c.Operator, cmpFloat(*msg.Value, v))
+	case string:
+		if msg.StringValue == nil {
+			return false
+		}
+		return compare(c.Operator, strings.Compare(*msg.StringValue, v))
+	case bool:
+		if msg.BoolValue == nil {
+			return false
+		}
+		eq := *msg.BoolValue == v
+		return (c.Operator == "==" && eq) || (c.Operator == "!=" && !eq)
+	default:
+		return false
+	}
+}
+
+func compare(op string, res int) bool {
+	switch op {
+	case "==":
+		return res == 0
+	case "!=":
+		return res != 0
+	case "<":
+		return res < 0
+	case "<=":
+		return res <= 0
+	case ">":
+		return res > 0
+	case ">=":
+		return res >= 0
+	default:
+		return false
+	}
+}
+
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// matchSubtopic matches the subtopic against the rule subtopic, which may
+// contain "*" (single token) and ">" (one or more tokens) wildcards. Empty
+// rule subtopic matches all the subtopics.
+func matchSubtopic(pattern, subtopic string) bool {
+	if pattern == "" {
+		return true
+	}
+	if subtopic == "" {
+		return false
+	}
+
+	pt := strings.Split(pattern, ".")
+	st := strings.Split(subtopic, ".")
+	for i, p := range pt {
+		if p == ">" {
+			return len(st) > i
+		}
+		if i >= len(st) || (p != "*" && p != st[i]) {
+			return false
+		}
+	}
+	return len(pt) == len(st)
+}
@@ -0,0 +1,551 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rules_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/mainflux/mainflux/pkg/uuid"
+	"github.com/mainflux/mainflux/rules"
+	"github.com/mainflux/mainflux/rules/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token      = "token"
+	otherToken = "other"
+	email      = "user@example.com"
+	otherEmail = "other@example.com"
+	chanID     = "chan"
+	alarmsID   = "alarms"
+	otherChan  = "other"
+	archiveID  = "archive"
+	contact    = "contact@example.com"
+)
+
+type fixture struct {
+	svc       rules.Service
+	publisher *mocks.Publisher
+	notifier  *mocks.Notifier
+}
+
+func newService() fixture {
+	auth := mocks.NewAuth(map[string]string{token: email, otherToken: otherEmail})
+	things := mocks.NewThingsService(map[string]string{chanID: email, alarmsID: email, archiveID: email, otherChan: otherEmail})
+	pub := mocks.NewPublisher()
+	notif := mocks.NewNotifier()
+	_, loopback, _ := net.ParseCIDR("127.0.0.0/8")
+	cfg := rules.Config{From: "rules@example.com", WebhookTimeout: time.Second, WebhookAllowedNets: []*net.IPNet{loopback}}
+	svc := rules.New(auth, things, mocks.NewRuleRepository(), uuid.NewMock(), pub, notif, cfg)
+	return fixture{svc: svc, publisher: pub, notifier: notif}
+}
+
+func newRule(t *testing.T, cond string, actions ...rules.Action) rules.Rule {
+	c, err := rules.ParseCondition(cond)
+	require.Nil(t, err, fmt.Sprintf("unexpected error parsing condition: %s", err))
+	return rules.Rule{
+		Name:      "rule",
+		Channel:   chanID,
+		Condition: c,
+		Actions:   actions,
+	}
+}
+
+func channelRule(r rules.Rule, chanID string) rules.Rule {
+	r.Channel = chanID
+	return r
+}
+
+func record(name string, value float64, t float64) senml.Message {
+	return senml.Message{
+		Channel:   chanID,
+		Publisher: "thing",
+		Name:      name,
+		Value:     &value,
+		Time:      t,
+	}
+}
+
+func TestParseCondition(t *testing.T) {
+	cases := []struct {
+		desc string
+		in   string
+		cond rules.Condition
+		err  error
+	}{
+		{
+			desc: "parse numeric condition",
+			in:   "temperature > 30",
+			cond: rules.Condition{Field: "temperature", Operator: ">", Value: 30.0},
+		},
+		{
+			desc: "parse numeric condition with duration",
+			in:   "temperature >= 30.5 for 5m",
+			cond: rules.Condition{Field: "temperature", Operator: ">=", Value: 30.5, For: 5 * time.Minute},
+		},
+		{
+			desc: "parse string condition",
+			in:   `state == "open for business"`,
+			cond: rules.Condition{Field: "state", Operator: "==", Value: "open for business"},
+		},
+		{
+			desc: "parse bool condition",
+			in:   "door != true",
+			cond: rules.Condition{Field: "door", Operator: "!=", Value: true},
+		},
+		{
+			desc: "parse bool condition with invalid operator",
+			in:   "door > true",
+			err:  rules.ErrInvalidCondition,
+		},
+		{
+			desc: "parse condition with invalid value",
+			in:   "temperature > hot",
+			err:  rules.ErrInvalidCondition,
+		},
+		{
+			desc: "parse condition with invalid duration",
+			in:   "temperature > 30 for ever",
+			err:  rules.ErrInvalidCondition,
+		},
+		{
+			desc: "parse condition without operator",
+			in:   "temperature",
+			err:  rules.ErrInvalidCondition,
+		},
+	}
+
+	for _, tc := range cases {
+		cond, err := rules.ParseCondition(tc.in)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+		assert.Equal(t, tc.cond, cond, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.cond, cond))
+		parsed, err := rules.ParseCondition(cond.String())
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error parsing formatted condition: %s", tc.desc, err))
+		assert.Equal(t, cond, parsed, fmt.Sprintf("%s: expected formatted condition to round-trip", tc.desc))
+	}
+}
+
+func TestCreateRule(t *testing.T) {
+	f := newService()
+	publish := rules.Action{Type: rules.Publish, Channel: alarmsID}
+
+	cases := []struct {
+		desc  string
+		token string
+		rule  rules.Rule
+		err   error
+	}{
+		{
+			desc:  "create rule",
+			token: token,
+			rule:  newRule(t, "temperature > 30", publish),
+			err:   nil,
+		},
+		{
+			desc:  "create rule with invalid token",
+			token: "invalid",
+			rule:  newRule(t, "temperature > 30", publish),
+			err:   errors.ErrAuthentication,
+		},
+		{
+			desc:  "create rule for channel owned by another user",
+			token: otherToken,
+			rule:  newRule(t, "temperature > 30", publish),
+			err:   errors.ErrAuthorization,
+		},
+		{
+			desc:  "create rule publishing to channel owned by another user",
+			token: token,
+			rule:  newRule(t, "temperature > 30", rules.Action{Type: rules.Publish, Channel: otherChan}),
+			err:   errors.ErrAuthorization,
+		},
+		{
+			desc:  "create rule publishing to its own channel",
+			token: token,
+			rule:  newRule(t, "temperature > 30", rules.Action{Type: rules.Publish, Channel: chanID}),
+			err:   rules.ErrLoop,
+		},
+		{
+			desc:  "create rule publishing to its channel through another rule",
+			token: token,
+			rule:  channelRule(newRule(t, "temperature > 30", rules.Action{Type: rules.Publish, Channel: chanID}), alarmsID),
+			err:   rules.ErrLoop,
+		},
+		{
+			desc:  "create rule publishing to the channel of another rule",
+			token: token,
+			rule:  channelRule(newRule(t, "temperature > 30", rules.Action{Type: rules.Publish, Channel: archiveID}), alarmsID),
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		r, err := f.svc.CreateRule(context.Background(), tc.token, tc.rule)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.NotEmpty(t, r.ID, fmt.Sprintf("%s: expected non-empty rule ID", tc.desc))
+			assert.Equal(t, email, r.Owner, fmt.Sprintf("%s: expected owner %s got %s\n", tc.desc, email, r.Owner))
+		}
+	}
+}
+
+func TestViewRule(t *testing.T) {
+	f := newService()
+	saved, err := f.svc.CreateRule(context.Background(), token, newRule(t, "temperature > 30", rules.Action{Type: rules.Publish, Channel: alarmsID}))
+	require.Nil(t, err, fmt.Sprintf("unexpected error creating rule: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		rule  rules.Rule
+		err   error
+	}{
+		{
+			desc:  "view rule",
+			token: token,
+			id:    saved.ID,
+			rule:  saved,
+			err:   nil,
+		},
+		{
+			desc:  "view rule with invalid token",
+			token: "invalid",
+			id:    saved.ID,
+			rule:  rules.Rule{},
+			err:   errors.ErrAuthentication,
+		},
+		{
+			desc:  "view rule owned by another user",
+			token: otherToken,
+			id:    saved.ID,
+			rule:  rules.Rule{},
+			err:   errors.ErrNotFound,
+		},
+		{
+			desc:  "view non-existing rule",
+			token: token,
+			id:    "non-existing",
+			rule:  rules.Rule{},
+			err:   errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		r, err := f.svc.ViewRule(context.Background(), tc.token, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.rule, r, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.rule, r))
+	}
+}
+
+func TestUpdateRule(t *testing.T) {
+	f := newService()
+	saved, err := f.svc.CreateRule(context.Background(), token, newRule(t, "temperature > 30", rules.Action{Type: rules.Publish, Channel: alarmsID}))
+	require.Nil(t, err, fmt.Sprintf("unexpected error creating rule: %s", err))
+
+	updated := saved
+	updated.Condition.Value = 40.0
+
+	missing := updated
+	missing.ID = "non-existing"
+
+	next, err := f.svc.CreateRule(context.Background(), token, channelRule(newRule(t, "temperature > 30", rules.Action{Type: rules.Publish, Channel: archiveID}), alarmsID))
+	require.Nil(t, err, fmt.Sprintf("unexpected error creating rule: %s", err))
+	loop := next
+	loop.Actions = []rules.Action{{Type: rules.Publish, Channel: chanID}}
+
+	cases := []struct {
+		desc  string
+		token string
+		rule  rules.Rule
+		err   error
+	}{
+		{
+			desc:  "update rule",
+			token: token,
+			rule:  updated,
+			err:   nil,
+		},
+		{
+			desc:  "update rule with invalid token",
+			token: "invalid",
+			rule:  updated,
+			err:   errors.ErrAuthentication,
+		},
+		{
+			desc:  "update non-existing rule",
+			token: token,
+			rule:  missing,
+			err:   errors.ErrNotFound,
+		},
+		{
+			desc:  "update rule to publish to its channel through another rule",
+			token: token,
+			rule:  loop,
+			err:   rules.ErrLoop,
+		},
+	}
+
+	for _, tc := range cases {
+		err := f.svc.UpdateRule(context.Background(), tc.token, tc.rule)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	r, err := f.svc.ViewRule(context.Background(), token, saved.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error viewing rule: %s", err))
+	assert.Equal(t, updated.Condition, r.Condition, "expected condition to be updated")
+}
+
+func TestListRules(t *testing.T) {
+	f := newService()
+	n := 10
+	for i := 0; i < n; i++ {
+		_, err := f.svc.CreateRule(context.Background(), token, newRule(t, "temperature > 30", rules.Action{Type: rules.Publish, Channel: alarmsID}))
+		require.Nil(t, err, fmt.Sprintf("unexpected error creating rule: %s", err))
+	}
+
+	cases := []struct {
+		desc  string
+		token string
+		pm    rules.PageMetadata
+		size  int
+		err   error
+	}{
+		{
+			desc:  "list all rules",
+			token: token,
+			pm:    rules.PageMetadata{Offset: 0, Limit: uint64(n)},
+			size:  n,
+			err:   nil,
+		},
+		{
+			desc:  "list a page of rules",
+			token: token,
+			pm:    rules.PageMetadata{Offset: 8, Limit: 5},
+			size:  2,
+			err:   nil,
+		},
+		{
+			desc:  "list rules of the other channel",
+			token: token,
+			pm:    rules.PageMetadata{Offset: 0, Limit: uint64(n), Channel: alarmsID},
+			size:  0,
+			err:   nil,
+		},
+		{
+			desc:  "list rules of another user",
+			token: otherToken,
+			pm:    rules.PageMetadata{Offset: 0, Limit: uint64(n)},
+			size:  0,
+			err:   nil,
+		},
+		{
+			desc:  "list rules with invalid token",
+			token: "invalid",
+			pm:    rules.PageMetadata{Offset: 0, Limit: uint64(n)},
+			size:  0,
+			err:   errors.ErrAuthentication,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := f.svc.ListRules(context.Background(), tc.token, tc.pm)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(page.Rules), fmt.Sprintf("%s: expected %d rules got %d\n", tc.desc, tc.size, len(page.Rules)))
+	}
+}
+
+func TestRemoveRule(t *testing.T) {
+	f := newService()
+	saved, err := f.svc.CreateRule(context.Background(), token, newRule(t, "temperature > 30", rules.Action{Type: rules.Publish, Channel: alarmsID}))
+	require.Nil(t, err, fmt.Sprintf("unexpected error creating rule: %s", err))
+
+	err = f.svc.RemoveRule(context.Background(), "invalid", saved.ID)
+	assert.True(t, errors.Contains(err, errors.ErrAuthentication), fmt.Sprintf("expected %s got %s\n", errors.ErrAuthentication, err))
+
+	err = f.svc.RemoveRule(context.Background(), token, saved.ID)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error removing rule: %s", err))
+
+	_, err = f.svc.ViewRule(context.Background(), token, saved.ID)
+	assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("expected %s got %s\n", errors.ErrNotFound, err))
+}
+
+func TestConsume(t *testing.T) {
+	f := newService()
+	_, err := f.svc.CreateRule(context.Background(), token, newRule(t, "temperature > 30",
+		rules.Action{Type: rules.Publish, Channel: alarmsID, Subtopic: "high"},
+		rules.Action{Type: rules.Notify, Contacts: []string{contact}},
+	))
+	require.Nil(t, err, fmt.Sprintf("unexpected error creating rule: %s", err))
+
+	cases := []struct {
+		desc     string
+		msgs     []senml.Message
+		triggers int
+	}{
+		{
+			desc:     "consume records not satisfying the condition",
+			msgs:     []senml.Message{record("temperature", 20, 1)},
+			triggers: 0,
+		},
+		{
+			desc:     "consume records of another field",
+			msgs:     []senml.Message{record("humidity", 50, 2)},
+			triggers: 0,
+		},
+		{
+			desc:     "consume records satisfying the condition",
+			msgs:     []senml.Message{record("temperature", 35, 3)},
+			triggers: 1,
+		},
+		{
+			desc:     "consume records while the condition still holds",
+			msgs:     []senml.Message{record("temperature", 36, 4)},
+			triggers: 0,
+		},
+		{
+			desc:     "consume records re-arming the condition",
+			msgs:     []senml.Message{record("temperature", 25, 5), record("temperature", 31, 6)},
+			triggers: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		err := f.svc.Consume(tc.msgs)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		msgs := f.publisher.Messages()
+		assert.Equal(t, tc.triggers, len(msgs), fmt.Sprintf("%s: expected %d published messages got %d\n", tc.desc, tc.triggers, len(msgs)))
+		for _, msg := range msgs {
+			assert.Equal(t, alarmsID, msg.Channel, fmt.Sprintf("%s: expected channel %s got %s\n", tc.desc, alarmsID, msg.Channel))
+			assert.Equal(t, "high", msg.Subtopic, fmt.Sprintf("%s: expected subtopic high got %s\n", tc.desc, msg.Subtopic))
+			assert.Equal(t, rules.Protocol, msg.Protocol, fmt.Sprintf("%s: expected protocol %s got %s\n", tc.desc, rules.Protocol, msg.Protocol))
+		}
+
+		contacts := f.notifier.Contacts()
+		assert.Equal(t, tc.triggers, len(contacts), fmt.Sprintf("%s: expected %d notifications got %d\n", tc.desc, tc.triggers, len(contacts)))
+	}
+
+	err = f.svc.Consume("invalid")
+	assert.True(t, errors.Contains(err, rules.ErrMessage), fmt.Sprintf("expected %s got %s\n", rules.ErrMessage, err))
+}
+
+func TestConsumeFor(t *testing.T) {
+	f := newService()
+	_, err := f.svc.CreateRule(context.Background(), token, newRule(t, "temperature > 30 for 5m",
+		rules.Action{Type: rules.Publish, Channel: alarmsID},
+	))
+	require.Nil(t, err, fmt.Sprintf("unexpected error creating rule: %s", err))
+
+	cases := []struct {
+		desc     string
+		msgs     []senml.Message
+		triggers int
+	}{
+		{
+			desc:     "consume the first record satisfying the condition",
+			msgs:     []senml.Message{record("temperature", 35, 1000)},
+			triggers: 0,
+		},
+		{
+			desc:     "consume record before the period expires",
+			msgs:     []senml.Message{record("temperature", 35, 1240)},
+			triggers: 0,
+		},
+		{
+			desc:     "consume record after the period expires",
+			msgs:     []senml.Message{record("temperature", 35, 1300)},
+			triggers: 1,
+		},
+		{
+			desc:     "consume record interrupting the period",
+			msgs:     []senml.Message{record("temperature", 20, 1400), record("temperature", 35, 1500), record("temperature", 35, 1700)},
+			triggers: 0,
+		},
+		{
+			desc:     "consume record after the new period expires",
+			msgs:     []senml.Message{record("temperature", 35, 1800)},
+			triggers: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		err := f.svc.Consume(tc.msgs)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		msgs := f.publisher.Messages()
+		assert.Equal(t, tc.triggers, len(msgs), fmt.Sprintf("%s: expected %d published messages got %d\n", tc.desc, tc.triggers, len(msgs)))
+	}
+}
+
+func TestConsumeWebhook(t *testing.T) {
+	reqs := make(chan map[string]interface{}, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		reqs <- body
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	f := newService()
+	saved, err := f.svc.CreateRule(context.Background(), token, newRule(t, "temperature > 30",
+		rules.Action{Type: rules.Webhook, URL: ts.URL},
+	))
+	require.Nil(t, err, fmt.Sprintf("unexpected error creating rule: %s", err))
+
+	err = f.svc.Consume([]senml.Message{record("temperature", 35, 1)})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	body := <-reqs
+	assert.Equal(t, saved.ID, body["rule_id"], fmt.Sprintf("expected rule ID %s got %v\n", saved.ID, body["rule_id"]))
+	assert.Equal(t, "temperature > 30", body["condition"], fmt.Sprintf("expected condition got %v\n", body["condition"]))
+
+	saved.Actions = []rules.Action{{Type: rules.Webhook, URL: ts.URL + "/fail"}}
+	err = f.svc.UpdateRule(context.Background(), token, saved)
+	require.Nil(t, err, fmt.Sprintf("unexpected error updating rule: %s", err))
+
+	err = f.svc.Consume([]senml.Message{record("temperature", 35, 2)})
+	<-reqs
+	assert.True(t, errors.Contains(err, rules.ErrAction), fmt.Sprintf("expected %s got %s\n", rules.ErrAction, err))
+}
+
+func TestConsumeWebhookPrivateAddress(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	auth := mocks.NewAuth(map[string]string{token: email})
+	things := mocks.NewThingsService(map[string]string{chanID: email})
+	svc := rules.New(auth, things, mocks.NewRuleRepository(), uuid.NewMock(), mocks.NewPublisher(), mocks.NewNotifier(), rules.Config{WebhookTimeout: time.Second})
+
+	_, err := svc.CreateRule(context.Background(), token, newRule(t, "temperature > 30",
+		rules.Action{Type: rules.Webhook, URL: ts.URL},
+	))
+	require.Nil(t, err, fmt.Sprintf("unexpected error creating rule: %s", err))
+
+	err = svc.Consume([]senml.Message{record("temperature", 35, 1)})
+	assert.True(t, errors.Contains(err, rules.ErrAction), fmt.Sprintf("expected %s got %s\n", rules.ErrAction, err))
+	assert.Contains(t, fmt.Sprint(err), httputil.ErrForbiddenAddress.Error(), fmt.Sprintf("expected %s got %s\n", httputil.ErrForbiddenAddress, err))
+}
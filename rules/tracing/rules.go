@@ -0,0 +1,96 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tracing contains middlewares that will add spans
+// to existing traces.
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/rules"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveOp              = "save_op"
+	updateOp            = "update_op"
+	retrieveByIDOp      = "retrieve_by_id_op"
+	retrieveAllOp       = "retrieve_all_op"
+	retrieveByChannelOp = "retrieve_by_channel_op"
+	removeOp            = "remove_op"
+)
+
+var _ rules.RuleRepository = (*ruleRepositoryMiddleware)(nil)
+
+type ruleRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   rules.RuleRepository
+}
+
+// New instantiates a new Rules repository that
+// tracks request and their latency, and adds spans to context.
+func New(repo rules.RuleRepository, tracer opentracing.Tracer) rules.RuleRepository {
+	return ruleRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (rrm ruleRepositoryMiddleware) Save(ctx context.Context, r rules.Rule) (rules.Rule, error) {
+	span := createSpan(ctx, rrm.tracer, saveOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.Save(ctx, r)
+}
+
+func (rrm ruleRepositoryMiddleware) Update(ctx context.Context, r rules.Rule) error {
+	span := createSpan(ctx, rrm.tracer, updateOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.Update(ctx, r)
+}
+
+func (rrm ruleRepositoryMiddleware) RetrieveByID(ctx context.Context, owner, id string) (rules.Rule, error) {
+	span := createSpan(ctx, rrm.tracer, retrieveByIDOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.RetrieveByID(ctx, owner, id)
+}
+
+func (rrm ruleRepositoryMiddleware) RetrieveAll(ctx context.Context, owner string, pm rules.PageMetadata) (rules.Page, error) {
+	span := createSpan(ctx, rrm.tracer, retrieveAllOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.RetrieveAll(ctx, owner, pm)
+}
+
+func (rrm ruleRepositoryMiddleware) RetrieveByChannel(ctx context.Context, chanID string) ([]rules.Rule, error) {
+	span := createSpan(ctx, rrm.tracer, retrieveByChannelOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.RetrieveByChannel(ctx, chanID)
+}
+
+func (rrm ruleRepositoryMiddleware) Remove(ctx context.Context, owner, id string) error {
+	span := createSpan(ctx, rrm.tracer, removeOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.Remove(ctx, owner, id)
+}
+
+func createSpan(ctx context.Context, tracer opentracing.Tracer, opName string) opentracing.Span {
+	if parentSpan := opentracing.SpanFromContext(ctx); parentSpan != nil {
+		return tracer.StartSpan(
+			opName,
+			opentracing.ChildOf(parentSpan.Context()),
+		)
+	}
+	return tracer.StartSpan(opName)
+}
@@ -19,11 +19,15 @@ import (
 var _ mainflux.ThingsServiceClient = (*grpcClient)(nil)
 
 type grpcClient struct {
-	timeout        time.Duration
-	canAccessByKey endpoint.Endpoint
-	canAccessByID  endpoint.Endpoint
-	isChannelOwner endpoint.Endpoint
-	identify       endpoint.Endpoint
+	timeout         time.Duration
+	canAccessByKey  endpoint.Endpoint
+	canAccessByID   endpoint.Endpoint
+	isChannelOwner  endpoint.Endpoint
+	isChannelsOwner endpoint.Endpoint
+	identify        endpoint.Endpoint
+	viewThing       endpoint.Endpoint
+	viewChannel     endpoint.Endpoint
+	viewQuota       endpoint.Endpoint
 }
 
 // NewClient returns new gRPC client instance.
@@ -56,6 +60,14 @@ func NewClient(conn *grpc.ClientConn, tracer opentracing.Tracer, timeout time.Du
 			decodeEmptyResponse,
 			empty.Empty{},
 		).Endpoint()),
+		isChannelsOwner: kitot.TraceClient(tracer, "is_channels_owner")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"IsChannelsOwner",
+			encodeIsChannelsOwner,
+			decodeEmptyResponse,
+			empty.Empty{},
+		).Endpoint()),
 		identify: kitot.TraceClient(tracer, "identify")(kitgrpc.NewClient(
 			conn,
 			svcName,
@@ -64,6 +76,30 @@ func NewClient(conn *grpc.ClientConn, tracer opentracing.Tracer, timeout time.Du
 			decodeIdentityResponse,
 			mainflux.ThingID{},
 		).Endpoint()),
+		viewThing: kitot.TraceClient(tracer, "view_thing")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"ViewThing",
+			encodeViewThingRequest,
+			decodeThingResponse,
+			mainflux.Thing{},
+		).Endpoint()),
+		viewChannel: kitot.TraceClient(tracer, "view_channel")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"ViewChannel",
+			encodeViewChannelRequest,
+			decodeChannelResponse,
+			mainflux.Channel{},
+		).Endpoint()),
+		viewQuota: kitot.TraceClient(tracer, "view_quota")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"ViewQuota",
+			encodeViewThingRequest,
+			decodeQuotaResponse,
+			mainflux.Quota{},
+		).Endpoint()),
 	}
 }
 
@@ -106,6 +142,17 @@ func (client grpcClient) IsChannelOwner(ctx context.Context, req *mainflux.Chann
 	return &empty.Empty{}, er.err
 }
 
+func (client grpcClient) IsChannelsOwner(ctx context.Context, req *mainflux.ChannelsOwnerReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	ar := channelsOwnerReq{owner: req.GetOwner(), chanIDs: req.GetChanIDs()}
+	res, err := client.isChannelsOwner(ctx, ar)
+	if err != nil {
+		return nil, err
+	}
+
+	er := res.(emptyRes)
+	return &empty.Empty{}, er.err
+}
+
 func (client grpcClient) Identify(ctx context.Context, req *mainflux.Token, _ ...grpc.CallOption) (*mainflux.ThingID, error) {
 	ctx, cancel := context.WithTimeout(ctx, client.timeout)
 	defer cancel()
@@ -119,6 +166,51 @@ func (client grpcClient) Identify(ctx context.Context, req *mainflux.Token, _ ..
 	return &mainflux.ThingID{Value: ir.id}, nil
 }
 
+func (client grpcClient) ViewThing(ctx context.Context, req *mainflux.ThingID, _ ...grpc.CallOption) (*mainflux.Thing, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	res, err := client.viewThing(ctx, viewThingReq{id: req.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	tr := res.(thingRes)
+	return &mainflux.Thing{Id: tr.id, Owner: tr.owner, Name: tr.name, Metadata: tr.metadata}, nil
+}
+
+func (client grpcClient) ViewChannel(ctx context.Context, req *mainflux.ChannelID, _ ...grpc.CallOption) (*mainflux.Channel, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	res, err := client.viewChannel(ctx, viewChannelReq{id: req.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	cr := res.(channelRes)
+	return &mainflux.Channel{Id: cr.id, Owner: cr.owner, Name: cr.name, Metadata: cr.metadata}, nil
+}
+
+func (client grpcClient) ViewQuota(ctx context.Context, req *mainflux.ThingID, _ ...grpc.CallOption) (*mainflux.Quota, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	res, err := client.viewQuota(ctx, viewThingReq{id: req.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	qr := res.(quotaRes)
+	return &mainflux.Quota{
+		Owner:            qr.owner,
+		ThingMessages:    qr.thingMessages,
+		ThingPayloadSize: qr.thingPayloadSize,
+		OwnerMessages:    qr.ownerMessages,
+		OwnerPayloadSize: qr.ownerPayloadSize,
+	}, nil
+}
+
 func encodeCanAccessByKeyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(AccessByKeyReq)
 	return &mainflux.AccessByKeyReq{Token: req.thingKey, ChanID: req.chanID}, nil
@@ -134,11 +226,47 @@ func encodeIsChannelOwner(_ context.Context, grpcReq interface{}) (interface{},
 	return &mainflux.ChannelOwnerReq{Owner: req.owner, ChanID: req.chanID}, nil
 }
 
+func encodeIsChannelsOwner(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(channelsOwnerReq)
+	return &mainflux.ChannelsOwnerReq{Owner: req.owner, ChanIDs: req.chanIDs}, nil
+}
+
 func encodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(identifyReq)
 	return &mainflux.Token{Value: req.key}, nil
 }
 
+func encodeViewThingRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(viewThingReq)
+	return &mainflux.ThingID{Value: req.id}, nil
+}
+
+func encodeViewChannelRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(viewChannelReq)
+	return &mainflux.ChannelID{Value: req.id}, nil
+}
+
+func decodeThingResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.Thing)
+	return thingRes{id: res.GetId(), owner: res.GetOwner(), name: res.GetName(), metadata: res.GetMetadata()}, nil
+}
+
+func decodeChannelResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.Channel)
+	return channelRes{id: res.GetId(), owner: res.GetOwner(), name: res.GetName(), metadata: res.GetMetadata()}, nil
+}
+
+func decodeQuotaResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.Quota)
+	return quotaRes{
+		owner:            res.GetOwner(),
+		thingMessages:    res.GetThingMessages(),
+		thingPayloadSize: res.GetThingPayloadSize(),
+		ownerMessages:    res.GetOwnerMessages(),
+		ownerPayloadSize: res.GetOwnerPayloadSize(),
+	}, nil
+}
+
 func decodeIdentityResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(*mainflux.ThingID)
 	return identityRes{id: res.GetValue()}, nil
@@ -5,6 +5,7 @@ package grpc
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/mainflux/mainflux/things"
@@ -49,6 +50,18 @@ func isChannelOwnerEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func isChannelsOwnerEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(channelsOwnerReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		err := svc.IsChannelsOwner(ctx, req.owner, req.chanIDs)
+		return emptyRes{err: err}, err
+	}
+}
+
 func identifyEndpoint(svc things.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(identifyReq)
@@ -62,3 +75,78 @@ func identifyEndpoint(svc things.Service) endpoint.Endpoint {
 		return identityRes{id: id}, nil
 	}
 }
+
+func viewThingEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewThingReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		th, err := svc.ViewThingByID(ctx, req.id)
+		if err != nil {
+			return thingRes{}, err
+		}
+
+		metadata, err := json.Marshal(th.Metadata)
+		if err != nil {
+			return thingRes{}, err
+		}
+		res := thingRes{
+			id:       th.ID,
+			owner:    th.Owner,
+			name:     th.Name,
+			metadata: metadata,
+		}
+		return res, nil
+	}
+}
+
+func viewChannelEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewChannelReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		ch, err := svc.ViewChannelByID(ctx, req.id)
+		if err != nil {
+			return channelRes{}, err
+		}
+
+		metadata, err := json.Marshal(ch.Metadata)
+		if err != nil {
+			return channelRes{}, err
+		}
+		res := channelRes{
+			id:       ch.ID,
+			owner:    ch.Owner,
+			name:     ch.Name,
+			metadata: metadata,
+		}
+		return res, nil
+	}
+}
+
+func viewQuotaEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewThingReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		q, err := svc.ViewThingQuota(ctx, req.id)
+		if err != nil {
+			return quotaRes{}, err
+		}
+
+		res := quotaRes{
+			owner:            q.Owner,
+			thingMessages:    q.Thing.Messages,
+			thingPayloadSize: q.Thing.PayloadSize,
+			ownerMessages:    q.OwnerQuota.Messages,
+			ownerPayloadSize: q.OwnerQuota.PayloadSize,
+		}
+		return res, nil
+	}
+}
@@ -148,6 +148,58 @@ func TestCanAccessByID(t *testing.T) {
 	}
 }
 
+func TestIsChannelsOwner(t *testing.T) {
+	chs, err := svc.CreateChannels(context.Background(), token, channel, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch1, ch2 := chs[0], chs[1]
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, err := grpc.Dial(usersAddr, grpc.WithInsecure())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	cli := grpcapi.NewClient(conn, mocktracer.New(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		owner   string
+		chanIDs []string
+		code    codes.Code
+	}{
+		"check if user owns the channels": {
+			owner:   email,
+			chanIDs: []string{ch1.ID, ch2.ID},
+			code:    codes.OK,
+		},
+		"check if user owns the channels including non-existent one": {
+			owner:   email,
+			chanIDs: []string{ch1.ID, wrong},
+			code:    codes.NotFound,
+		},
+		"check if other user owns the channels": {
+			owner:   wrong,
+			chanIDs: []string{ch1.ID, ch2.ID},
+			code:    codes.NotFound,
+		},
+		"check if user owns the channels including channel with empty ID": {
+			owner:   email,
+			chanIDs: []string{ch1.ID, wrongID},
+			code:    codes.InvalidArgument,
+		},
+		"check if user owns empty list of channels": {
+			owner:   email,
+			chanIDs: []string{},
+			code:    codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		_, err := cli.IsChannelsOwner(ctx, &mainflux.ChannelsOwnerReq{Owner: tc.owner, ChanIDs: tc.chanIDs})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
+
 func TestIdentify(t *testing.T) {
 	ths, err := svc.CreateThings(context.Background(), token, thing)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
@@ -185,3 +237,140 @@ func TestIdentify(t *testing.T) {
 		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
 	}
 }
+
+func TestViewThing(t *testing.T) {
+	ths, err := svc.CreateThings(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sth := ths[0]
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, err := grpc.Dial(usersAddr, grpc.WithInsecure())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	cli := grpcapi.NewClient(conn, mocktracer.New(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		id       string
+		name     string
+		metadata string
+		code     codes.Code
+	}{
+		"view existing thing": {
+			id:       sth.ID,
+			name:     sth.Name,
+			metadata: `{"test":"test"}`,
+			code:     codes.OK,
+		},
+		"view non-existent thing": {
+			id:   "non-existent",
+			code: codes.NotFound,
+		},
+		"view thing with empty ID": {
+			id:   wrongID,
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		th, err := cli.ViewThing(ctx, &mainflux.ThingID{Value: tc.id})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.name, th.GetName(), fmt.Sprintf("%s: expected %s got %s", desc, tc.name, th.GetName()))
+		assert.Equal(t, tc.metadata, string(th.GetMetadata()), fmt.Sprintf("%s: expected %s got %s", desc, tc.metadata, th.GetMetadata()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
+
+func TestViewChannel(t *testing.T) {
+	chs, err := svc.CreateChannels(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sch := chs[0]
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, err := grpc.Dial(usersAddr, grpc.WithInsecure())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	cli := grpcapi.NewClient(conn, mocktracer.New(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		id       string
+		name     string
+		metadata string
+		code     codes.Code
+	}{
+		"view existing channel": {
+			id:       sch.ID,
+			name:     sch.Name,
+			metadata: `{"test":"test"}`,
+			code:     codes.OK,
+		},
+		"view non-existent channel": {
+			id:   "non-existent",
+			code: codes.NotFound,
+		},
+		"view channel with empty ID": {
+			id:   wrongID,
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		ch, err := cli.ViewChannel(ctx, &mainflux.ChannelID{Value: tc.id})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.name, ch.GetName(), fmt.Sprintf("%s: expected %s got %s", desc, tc.name, ch.GetName()))
+		assert.Equal(t, tc.metadata, string(ch.GetMetadata()), fmt.Sprintf("%s: expected %s got %s", desc, tc.metadata, ch.GetMetadata()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
+
+func TestViewQuota(t *testing.T) {
+	th := thing
+	th.Metadata = map[string]interface{}{"quota": map[string]interface{}{"messages_per_minute": float64(60), "max_payload_size": float64(1024)}}
+	ths, err := svc.CreateThings(context.Background(), token, th)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sth := ths[0]
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, err := grpc.Dial(usersAddr, grpc.WithInsecure())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	cli := grpcapi.NewClient(conn, mocktracer.New(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		id          string
+		owner       string
+		messages    uint64
+		payloadSize uint64
+		code        codes.Code
+	}{
+		"view quota of existing thing": {
+			id:          sth.ID,
+			owner:       email,
+			messages:    60,
+			payloadSize: 1024,
+			code:        codes.OK,
+		},
+		"view quota of non-existent thing": {
+			id:   "non-existent",
+			code: codes.NotFound,
+		},
+		"view quota with empty ID": {
+			id:   wrongID,
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		q, err := cli.ViewQuota(ctx, &mainflux.ThingID{Value: tc.id})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.owner, q.GetOwner(), fmt.Sprintf("%s: expected %s got %s", desc, tc.owner, q.GetOwner()))
+		assert.Equal(t, tc.messages, q.GetThingMessages(), fmt.Sprintf("%s: expected %d got %d", desc, tc.messages, q.GetThingMessages()))
+		assert.Equal(t, tc.payloadSize, q.GetThingPayloadSize(), fmt.Sprintf("%s: expected %d got %d", desc, tc.payloadSize, q.GetThingPayloadSize()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
@@ -44,6 +44,49 @@ func (req channelOwnerReq) validate() error {
 	return nil
 }
 
+type channelsOwnerReq struct {
+	owner   string
+	chanIDs []string
+}
+
+func (req channelsOwnerReq) validate() error {
+	if req.owner == "" || len(req.chanIDs) == 0 {
+		return errors.ErrMalformedEntity
+	}
+
+	for _, chanID := range req.chanIDs {
+		if chanID == "" {
+			return errors.ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+type viewThingReq struct {
+	id string
+}
+
+func (req viewThingReq) validate() error {
+	if req.id == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewChannelReq struct {
+	id string
+}
+
+func (req viewChannelReq) validate() error {
+	if req.id == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	return nil
+}
+
 type identifyReq struct {
 	key string
 }
@@ -7,6 +7,28 @@ type identityRes struct {
 	id string
 }
 
+type thingRes struct {
+	id       string
+	owner    string
+	name     string
+	metadata []byte
+}
+
+type channelRes struct {
+	id       string
+	owner    string
+	name     string
+	metadata []byte
+}
+
+type quotaRes struct {
+	owner            string
+	thingMessages    uint64
+	thingPayloadSize uint64
+	ownerMessages    uint64
+	ownerPayloadSize uint64
+}
+
 type emptyRes struct {
 	err error
 }
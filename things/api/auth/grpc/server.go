@@ -20,10 +20,14 @@ import (
 var _ mainflux.ThingsServiceServer = (*grpcServer)(nil)
 
 type grpcServer struct {
-	canAccessByKey kitgrpc.Handler
-	canAccessByID  kitgrpc.Handler
-	isChannelOwner kitgrpc.Handler
-	identify       kitgrpc.Handler
+	canAccessByKey  kitgrpc.Handler
+	canAccessByID   kitgrpc.Handler
+	isChannelOwner  kitgrpc.Handler
+	isChannelsOwner kitgrpc.Handler
+	identify        kitgrpc.Handler
+	viewThing       kitgrpc.Handler
+	viewChannel     kitgrpc.Handler
+	viewQuota       kitgrpc.Handler
 }
 
 // NewServer returns new ThingsServiceServer instance.
@@ -44,11 +48,31 @@ func NewServer(tracer opentracing.Tracer, svc things.Service) mainflux.ThingsSer
 			decodeIsChannelOwnerRequest,
 			encodeEmptyResponse,
 		),
+		isChannelsOwner: kitgrpc.NewServer(
+			isChannelsOwnerEndpoint(svc),
+			decodeIsChannelsOwnerRequest,
+			encodeEmptyResponse,
+		),
 		identify: kitgrpc.NewServer(
 			kitot.TraceServer(tracer, "identify")(identifyEndpoint(svc)),
 			decodeIdentifyRequest,
 			encodeIdentityResponse,
 		),
+		viewThing: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "view_thing")(viewThingEndpoint(svc)),
+			decodeViewThingRequest,
+			encodeThingResponse,
+		),
+		viewChannel: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "view_channel")(viewChannelEndpoint(svc)),
+			decodeViewChannelRequest,
+			encodeChannelResponse,
+		),
+		viewQuota: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "view_quota")(viewQuotaEndpoint(svc)),
+			decodeViewThingRequest,
+			encodeQuotaResponse,
+		),
 	}
 }
 
@@ -79,6 +103,15 @@ func (gs *grpcServer) IsChannelOwner(ctx context.Context, req *mainflux.ChannelO
 	return res.(*empty.Empty), nil
 }
 
+func (gs *grpcServer) IsChannelsOwner(ctx context.Context, req *mainflux.ChannelsOwnerReq) (*empty.Empty, error) {
+	_, res, err := gs.isChannelsOwner.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*empty.Empty), nil
+}
+
 func (gs *grpcServer) Identify(ctx context.Context, req *mainflux.Token) (*mainflux.ThingID, error) {
 	_, res, err := gs.identify.ServeGRPC(ctx, req)
 	if err != nil {
@@ -88,6 +121,33 @@ func (gs *grpcServer) Identify(ctx context.Context, req *mainflux.Token) (*mainf
 	return res.(*mainflux.ThingID), nil
 }
 
+func (gs *grpcServer) ViewThing(ctx context.Context, req *mainflux.ThingID) (*mainflux.Thing, error) {
+	_, res, err := gs.viewThing.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*mainflux.Thing), nil
+}
+
+func (gs *grpcServer) ViewChannel(ctx context.Context, req *mainflux.ChannelID) (*mainflux.Channel, error) {
+	_, res, err := gs.viewChannel.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*mainflux.Channel), nil
+}
+
+func (gs *grpcServer) ViewQuota(ctx context.Context, req *mainflux.ThingID) (*mainflux.Quota, error) {
+	_, res, err := gs.viewQuota.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*mainflux.Quota), nil
+}
+
 func decodeCanAccessByKeyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*mainflux.AccessByKeyReq)
 	return AccessByKeyReq{thingKey: req.GetToken(), chanID: req.GetChanID()}, nil
@@ -103,16 +163,52 @@ func decodeIsChannelOwnerRequest(_ context.Context, grpcReq interface{}) (interf
 	return channelOwnerReq{owner: req.GetOwner(), chanID: req.GetChanID()}, nil
 }
 
+func decodeIsChannelsOwnerRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.ChannelsOwnerReq)
+	return channelsOwnerReq{owner: req.GetOwner(), chanIDs: req.GetChanIDs()}, nil
+}
+
 func decodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*mainflux.Token)
 	return identifyReq{key: req.GetValue()}, nil
 }
 
+func decodeViewThingRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.ThingID)
+	return viewThingReq{id: req.GetValue()}, nil
+}
+
+func decodeViewChannelRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.ChannelID)
+	return viewChannelReq{id: req.GetValue()}, nil
+}
+
 func encodeIdentityResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(identityRes)
 	return &mainflux.ThingID{Value: res.id}, nil
 }
 
+func encodeThingResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(thingRes)
+	return &mainflux.Thing{Id: res.id, Owner: res.owner, Name: res.name, Metadata: res.metadata}, nil
+}
+
+func encodeChannelResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(channelRes)
+	return &mainflux.Channel{Id: res.id, Owner: res.owner, Name: res.name, Metadata: res.metadata}, nil
+}
+
+func encodeQuotaResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(quotaRes)
+	return &mainflux.Quota{
+		Owner:            res.owner,
+		ThingMessages:    res.thingMessages,
+		ThingPayloadSize: res.thingPayloadSize,
+		OwnerMessages:    res.ownerMessages,
+		OwnerPayloadSize: res.ownerPayloadSize,
+	}, nil
+}
+
 func encodeEmptyResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(emptyRes)
 	return &empty.Empty{}, encodeError(res.err)
@@ -78,6 +78,45 @@ func (lm *loggingMiddleware) UpdateKey(ctx context.Context, token, id, key strin
 	return lm.svc.UpdateKey(ctx, token, id, key)
 }
 
+func (lm *loggingMiddleware) RotateKey(ctx context.Context, token, id, key string, grace time.Duration) (_ string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method rotate_key for thing %s with grace period %s took %s to complete", id, grace, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RotateKey(ctx, token, id, key, grace)
+}
+
+func (lm *loggingMiddleware) AddKey(ctx context.Context, token, id string, key things.Key) (_ things.Key, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method add_key for thing %s and key label %s took %s to complete", id, key.Label, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AddKey(ctx, token, id, key)
+}
+
+func (lm *loggingMiddleware) RemoveKey(ctx context.Context, token, id, key string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_key for thing %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveKey(ctx, token, id, key)
+}
+
 func (lm *loggingMiddleware) ViewThing(ctx context.Context, token, id string) (thing things.Thing, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method view_thing for token %s and thing %s took %s to complete", token, id, time.Since(begin))
@@ -133,6 +172,32 @@ func (lm *loggingMiddleware) RemoveThing(ctx context.Context, token, id string)
 	return lm.svc.RemoveThing(ctx, token, id)
 }
 
+func (lm *loggingMiddleware) RestoreThing(ctx context.Context, token, id string) (th things.Thing, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method restore_thing for token %s and thing %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RestoreThing(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) PurgeThings(ctx context.Context, before time.Time) (ids []string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method purge_things for things removed before %s took %s to complete", before, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.PurgeThings(ctx, before)
+}
+
 func (lm *loggingMiddleware) CreateChannels(ctx context.Context, token string, channels ...things.Channel) (saved []things.Channel, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method create_channels for token %s and channels %s took %s to complete", token, saved, time.Since(begin))
@@ -214,6 +279,32 @@ func (lm *loggingMiddleware) RemoveChannel(ctx context.Context, token, id string
 	return lm.svc.RemoveChannel(ctx, token, id)
 }
 
+func (lm *loggingMiddleware) RestoreChannel(ctx context.Context, token, id string) (ch things.Channel, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method restore_channel for token %s and channel %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RestoreChannel(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) PurgeChannels(ctx context.Context, before time.Time) (ids []string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method purge_channels for channels removed before %s took %s to complete", before, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.PurgeChannels(ctx, before)
+}
+
 func (lm *loggingMiddleware) Connect(ctx context.Context, token string, chIDs, thIDs []string) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method connect for token %s, channels %s and things %s took %s to complete", token, chIDs, thIDs, time.Since(begin))
@@ -279,6 +370,19 @@ func (lm *loggingMiddleware) IsChannelOwner(ctx context.Context, owner, chanID s
 	return lm.svc.IsChannelOwner(ctx, owner, chanID)
 }
 
+func (lm *loggingMiddleware) IsChannelsOwner(ctx context.Context, owner string, chanIDs []string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method is_channels_owner for channels %s and user %s took %s to complete", chanIDs, owner, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.IsChannelsOwner(ctx, owner, chanIDs)
+}
+
 func (lm *loggingMiddleware) Identify(ctx context.Context, key string) (id string, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method identify for token %s and thing %s took %s to complete", key, id, time.Since(begin))
@@ -292,6 +396,32 @@ func (lm *loggingMiddleware) Identify(ctx context.Context, key string) (id strin
 	return lm.svc.Identify(ctx, key)
 }
 
+func (lm *loggingMiddleware) ViewThingByID(ctx context.Context, id string) (thing things.Thing, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_thing_by_id for thing %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewThingByID(ctx, id)
+}
+
+func (lm *loggingMiddleware) ViewChannelByID(ctx context.Context, id string) (channel things.Channel, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_channel_by_id for channel %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewChannelByID(ctx, id)
+}
+
 func (lm *loggingMiddleware) ListMembers(ctx context.Context, token, groupID string, pm things.PageMetadata) (tp things.Page, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method list_members for token %s and group id %s took %s to complete", token, groupID, time.Since(begin))
@@ -304,3 +434,185 @@ func (lm *loggingMiddleware) ListMembers(ctx context.Context, token, groupID str
 
 	return lm.svc.ListMembers(ctx, token, groupID, pm)
 }
+
+func (lm *loggingMiddleware) UpdateQuota(ctx context.Context, token, owner string, q things.Quota) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_quota for token %s and owner %s took %s to complete", token, owner, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateQuota(ctx, token, owner, q)
+}
+
+func (lm *loggingMiddleware) ViewQuota(ctx context.Context, token, owner string) (q things.Quota, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_quota for token %s and owner %s took %s to complete", token, owner, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewQuota(ctx, token, owner)
+}
+
+func (lm *loggingMiddleware) ViewThingQuota(ctx context.Context, id string) (tq things.ThingQuota, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_thing_quota for thing %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewThingQuota(ctx, id)
+}
+
+func (lm *loggingMiddleware) ViewStatus(ctx context.Context, token, id string) (st things.Status, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_status for thing %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewStatus(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) UpdateStatus(ctx context.Context, st things.Status) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_status for thing %s took %s to complete", st.ThingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateStatus(ctx, st)
+}
+
+func (lm *loggingMiddleware) CreateProfile(ctx context.Context, token string, p things.Profile) (saved things.Profile, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_profile for profile %s took %s to complete", saved.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateProfile(ctx, token, p)
+}
+
+func (lm *loggingMiddleware) UpdateProfile(ctx context.Context, token string, p things.Profile) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_profile for profile %s took %s to complete", p.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateProfile(ctx, token, p)
+}
+
+func (lm *loggingMiddleware) ViewProfile(ctx context.Context, token, id string) (p things.Profile, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_profile for profile %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewProfile(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListProfiles(ctx context.Context, token string, pm things.PageMetadata) (_ things.ProfilesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_profiles for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListProfiles(ctx, token, pm)
+}
+
+func (lm *loggingMiddleware) RemoveProfile(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_profile for profile %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveProfile(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ImportThings(ctx context.Context, token string, records []things.Record, dryRun bool) (_ []things.ImportResult, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method import_things of %d records with dry run %t took %s to complete", len(records), dryRun, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ImportThings(ctx, token, records, dryRun)
+}
+
+func (lm *loggingMiddleware) ImportChannels(ctx context.Context, token string, records []things.Record, dryRun bool) (_ []things.ImportResult, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method import_channels of %d records with dry run %t took %s to complete", len(records), dryRun, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ImportChannels(ctx, token, records, dryRun)
+}
+
+func (lm *loggingMiddleware) ExportThings(ctx context.Context, token string) (_ []things.Record, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method export_things for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ExportThings(ctx, token)
+}
+
+func (lm *loggingMiddleware) ExportChannels(ctx context.Context, token string) (_ []things.Record, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method export_channels for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ExportChannels(ctx, token)
+}
@@ -66,6 +66,33 @@ func (ms *metricsMiddleware) UpdateKey(ctx context.Context, token, id, key strin
 	return ms.svc.UpdateKey(ctx, token, id, key)
 }
 
+func (ms *metricsMiddleware) RotateKey(ctx context.Context, token, id, key string, grace time.Duration) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "rotate_key").Add(1)
+		ms.latency.With("method", "rotate_key").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RotateKey(ctx, token, id, key, grace)
+}
+
+func (ms *metricsMiddleware) AddKey(ctx context.Context, token, id string, key things.Key) (things.Key, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "add_key").Add(1)
+		ms.latency.With("method", "add_key").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AddKey(ctx, token, id, key)
+}
+
+func (ms *metricsMiddleware) RemoveKey(ctx context.Context, token, id, key string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_key").Add(1)
+		ms.latency.With("method", "remove_key").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveKey(ctx, token, id, key)
+}
+
 func (ms *metricsMiddleware) ViewThing(ctx context.Context, token, id string) (things.Thing, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "view_thing").Add(1)
@@ -102,6 +129,24 @@ func (ms *metricsMiddleware) RemoveThing(ctx context.Context, token, id string)
 	return ms.svc.RemoveThing(ctx, token, id)
 }
 
+func (ms *metricsMiddleware) RestoreThing(ctx context.Context, token, id string) (things.Thing, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "restore_thing").Add(1)
+		ms.latency.With("method", "restore_thing").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RestoreThing(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) PurgeThings(ctx context.Context, before time.Time) ([]string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "purge_things").Add(1)
+		ms.latency.With("method", "purge_things").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.PurgeThings(ctx, before)
+}
+
 func (ms *metricsMiddleware) CreateChannels(ctx context.Context, token string, channels ...things.Channel) (saved []things.Channel, err error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "create_channels").Add(1)
@@ -156,6 +201,24 @@ func (ms *metricsMiddleware) RemoveChannel(ctx context.Context, token, id string
 	return ms.svc.RemoveChannel(ctx, token, id)
 }
 
+func (ms *metricsMiddleware) RestoreChannel(ctx context.Context, token, id string) (things.Channel, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "restore_channel").Add(1)
+		ms.latency.With("method", "restore_channel").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RestoreChannel(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) PurgeChannels(ctx context.Context, before time.Time) ([]string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "purge_channels").Add(1)
+		ms.latency.With("method", "purge_channels").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.PurgeChannels(ctx, before)
+}
+
 func (ms *metricsMiddleware) Connect(ctx context.Context, token string, chIDs, thIDs []string) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "connect").Add(1)
@@ -201,6 +264,15 @@ func (ms *metricsMiddleware) IsChannelOwner(ctx context.Context, owner, chanID s
 	return ms.svc.IsChannelOwner(ctx, owner, chanID)
 }
 
+func (ms *metricsMiddleware) IsChannelsOwner(ctx context.Context, owner string, chanIDs []string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "is_channels_owner").Add(1)
+		ms.latency.With("method", "is_channels_owner").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.IsChannelsOwner(ctx, owner, chanIDs)
+}
+
 func (ms *metricsMiddleware) Identify(ctx context.Context, key string) (string, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "identify").Add(1)
@@ -210,6 +282,24 @@ func (ms *metricsMiddleware) Identify(ctx context.Context, key string) (string,
 	return ms.svc.Identify(ctx, key)
 }
 
+func (ms *metricsMiddleware) ViewThingByID(ctx context.Context, id string) (things.Thing, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_thing_by_id").Add(1)
+		ms.latency.With("method", "view_thing_by_id").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewThingByID(ctx, id)
+}
+
+func (ms *metricsMiddleware) ViewChannelByID(ctx context.Context, id string) (things.Channel, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_channel_by_id").Add(1)
+		ms.latency.With("method", "view_channel_by_id").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewChannelByID(ctx, id)
+}
+
 func (ms *metricsMiddleware) ListMembers(ctx context.Context, token, groupID string, pm things.PageMetadata) (tp things.Page, err error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "list_members").Add(1)
@@ -218,3 +308,129 @@ func (ms *metricsMiddleware) ListMembers(ctx context.Context, token, groupID str
 
 	return ms.svc.ListMembers(ctx, token, groupID, pm)
 }
+
+func (ms *metricsMiddleware) UpdateQuota(ctx context.Context, token, owner string, q things.Quota) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_quota").Add(1)
+		ms.latency.With("method", "update_quota").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateQuota(ctx, token, owner, q)
+}
+
+func (ms *metricsMiddleware) ViewQuota(ctx context.Context, token, owner string) (things.Quota, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_quota").Add(1)
+		ms.latency.With("method", "view_quota").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewQuota(ctx, token, owner)
+}
+
+func (ms *metricsMiddleware) ViewThingQuota(ctx context.Context, id string) (things.ThingQuota, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_thing_quota").Add(1)
+		ms.latency.With("method", "view_thing_quota").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewThingQuota(ctx, id)
+}
+
+func (ms *metricsMiddleware) ViewStatus(ctx context.Context, token, id string) (things.Status, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_status").Add(1)
+		ms.latency.With("method", "view_status").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewStatus(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) UpdateStatus(ctx context.Context, st things.Status) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_status").Add(1)
+		ms.latency.With("method", "update_status").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateStatus(ctx, st)
+}
+
+func (ms *metricsMiddleware) CreateProfile(ctx context.Context, token string, p things.Profile) (things.Profile, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_profile").Add(1)
+		ms.latency.With("method", "create_profile").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateProfile(ctx, token, p)
+}
+
+func (ms *metricsMiddleware) UpdateProfile(ctx context.Context, token string, p things.Profile) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_profile").Add(1)
+		ms.latency.With("method", "update_profile").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateProfile(ctx, token, p)
+}
+
+func (ms *metricsMiddleware) ViewProfile(ctx context.Context, token, id string) (things.Profile, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_profile").Add(1)
+		ms.latency.With("method", "view_profile").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewProfile(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListProfiles(ctx context.Context, token string, pm things.PageMetadata) (things.ProfilesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_profiles").Add(1)
+		ms.latency.With("method", "list_profiles").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListProfiles(ctx, token, pm)
+}
+
+func (ms *metricsMiddleware) RemoveProfile(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_profile").Add(1)
+		ms.latency.With("method", "remove_profile").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveProfile(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ImportThings(ctx context.Context, token string, records []things.Record, dryRun bool) ([]things.ImportResult, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "import_things").Add(1)
+		ms.latency.With("method", "import_things").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ImportThings(ctx, token, records, dryRun)
+}
+
+func (ms *metricsMiddleware) ImportChannels(ctx context.Context, token string, records []things.Record, dryRun bool) ([]things.ImportResult, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "import_channels").Add(1)
+		ms.latency.With("method", "import_channels").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ImportChannels(ctx, token, records, dryRun)
+}
+
+func (ms *metricsMiddleware) ExportThings(ctx context.Context, token string) ([]things.Record, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "export_things").Add(1)
+		ms.latency.With("method", "export_things").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ExportThings(ctx, token)
+}
+
+func (ms *metricsMiddleware) ExportChannels(ctx context.Context, token string) ([]things.Record, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "export_channels").Add(1)
+		ms.latency.With("method", "export_channels").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ExportChannels(ctx, token)
+}
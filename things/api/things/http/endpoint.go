@@ -5,6 +5,7 @@ package http
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/mainflux/mainflux/pkg/errors"
@@ -20,10 +21,11 @@ func createThingEndpoint(svc things.Service) endpoint.Endpoint {
 		}
 
 		th := things.Thing{
-			Key:      req.Key,
-			ID:       req.ID,
-			Name:     req.Name,
-			Metadata: req.Metadata,
+			Key:       req.Key,
+			ID:        req.ID,
+			Name:      req.Name,
+			ProfileID: req.ProfileID,
+			Metadata:  req.Metadata,
 		}
 		saved, err := svc.CreateThings(ctx, req.token, th)
 		if err != nil {
@@ -50,10 +52,11 @@ func createThingsEndpoint(svc things.Service) endpoint.Endpoint {
 		ths := []things.Thing{}
 		for _, tReq := range req.Things {
 			th := things.Thing{
-				Name:     tReq.Name,
-				Key:      tReq.Key,
-				ID:       tReq.ID,
-				Metadata: tReq.Metadata,
+				Name:      tReq.Name,
+				Key:       tReq.Key,
+				ID:        tReq.ID,
+				ProfileID: tReq.ProfileID,
+				Metadata:  tReq.Metadata,
 			}
 			ths = append(ths, th)
 		}
@@ -137,6 +140,68 @@ func updateKeyEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func rotateKeyEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(rotateKeyReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		grace := time.Duration(req.GracePeriod) * time.Second
+		key, err := svc.RotateKey(ctx, req.token, req.id, req.Key, grace)
+		if err != nil {
+			return nil, err
+		}
+
+		res := keyRes{Key: key}
+		return res, nil
+	}
+}
+
+func addKeyEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(addKeyReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		key := things.Key{
+			Value: req.Key,
+			Label: req.Label,
+		}
+		if req.ExpiresAt != nil {
+			key.ExpiresAt = *req.ExpiresAt
+		}
+
+		saved, err := svc.AddKey(ctx, req.token, req.id, key)
+		if err != nil {
+			return nil, err
+		}
+
+		res := toKeyRes(saved)
+		res.created = true
+		return res, nil
+	}
+}
+
+func removeKeyEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(removeKeyReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveKey(ctx, req.token, req.id, req.key); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
 func viewThingEndpoint(svc things.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(viewResourceReq)
@@ -151,11 +216,41 @@ func viewThingEndpoint(svc things.Service) endpoint.Endpoint {
 		}
 
 		res := viewThingRes{
-			ID:       thing.ID,
-			Owner:    thing.Owner,
-			Name:     thing.Name,
-			Key:      thing.Key,
-			Metadata: thing.Metadata,
+			ID:        thing.ID,
+			Owner:     thing.Owner,
+			Name:      thing.Name,
+			Key:       thing.Key,
+			ProfileID: thing.ProfileID,
+			Metadata:  thing.Metadata,
+		}
+		for _, k := range thing.Keys {
+			res.Keys = append(res.Keys, toKeyRes(k))
+		}
+		return res, nil
+	}
+}
+
+func viewStatusEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		st, err := svc.ViewStatus(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := viewStatusRes{
+			ID:       st.ThingID,
+			Online:   st.Online,
+			Protocol: st.Protocol,
+			ClientIP: st.ClientIP,
+		}
+		if !st.LastSeen.IsZero() {
+			res.LastSeen = &st.LastSeen
 		}
 		return res, nil
 	}
@@ -186,11 +281,15 @@ func listThingsEndpoint(svc things.Service) endpoint.Endpoint {
 		}
 		for _, thing := range page.Things {
 			view := viewThingRes{
-				ID:       thing.ID,
-				Owner:    thing.Owner,
-				Name:     thing.Name,
-				Key:      thing.Key,
-				Metadata: thing.Metadata,
+				ID:        thing.ID,
+				Owner:     thing.Owner,
+				Name:      thing.Name,
+				Key:       thing.Key,
+				ProfileID: thing.ProfileID,
+				Metadata:  thing.Metadata,
+			}
+			if !thing.DeletedAt.IsZero() {
+				view.DeletedAt = &thing.DeletedAt
 			}
 			res.Things = append(res.Things, view)
 		}
@@ -222,11 +321,12 @@ func listThingsByChannelEndpoint(svc things.Service) endpoint.Endpoint {
 		}
 		for _, thing := range page.Things {
 			view := viewThingRes{
-				ID:       thing.ID,
-				Owner:    thing.Owner,
-				Key:      thing.Key,
-				Name:     thing.Name,
-				Metadata: thing.Metadata,
+				ID:        thing.ID,
+				Owner:     thing.Owner,
+				Key:       thing.Key,
+				Name:      thing.Name,
+				ProfileID: thing.ProfileID,
+				Metadata:  thing.Metadata,
 			}
 			res.Things = append(res.Things, view)
 		}
@@ -256,6 +356,31 @@ func removeThingEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func restoreThingEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		thing, err := svc.RestoreThing(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := viewThingRes{
+			ID:        thing.ID,
+			Owner:     thing.Owner,
+			Name:      thing.Name,
+			Key:       thing.Key,
+			ProfileID: thing.ProfileID,
+			Metadata:  thing.Metadata,
+		}
+		return res, nil
+	}
+}
+
 func createChannelEndpoint(svc things.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(createChannelReq)
@@ -403,6 +528,9 @@ func listChannelsEndpoint(svc things.Service) endpoint.Endpoint {
 				Name:     channel.Name,
 				Metadata: channel.Metadata,
 			}
+			if !channel.DeletedAt.IsZero() {
+				view.DeletedAt = &channel.DeletedAt
+			}
 
 			res.Channels = append(res.Channels, view)
 		}
@@ -465,6 +593,159 @@ func removeChannelEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func restoreChannelEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		channel, err := svc.RestoreChannel(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := viewChannelRes{
+			ID:       channel.ID,
+			Owner:    channel.Owner,
+			Name:     channel.Name,
+			Metadata: channel.Metadata,
+		}
+
+		return res, nil
+	}
+}
+
+func createProfileEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(profileReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		p := things.Profile{
+			ID:          req.ID,
+			Name:        req.Name,
+			Metadata:    req.Metadata,
+			Channels:    req.Channels,
+			Connections: req.Connections,
+		}
+		saved, err := svc.CreateProfile(ctx, req.token, p)
+		if err != nil {
+			return nil, err
+		}
+
+		res := profileRes{
+			ID:      saved.ID,
+			created: true,
+		}
+		return res, nil
+	}
+}
+
+func updateProfileEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateProfileReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		p := things.Profile{
+			ID:          req.id,
+			Name:        req.Name,
+			Metadata:    req.Metadata,
+			Channels:    req.Channels,
+			Connections: req.Connections,
+		}
+		if err := svc.UpdateProfile(ctx, req.token, p); err != nil {
+			return nil, err
+		}
+
+		res := profileRes{
+			ID:      req.id,
+			created: false,
+		}
+		return res, nil
+	}
+}
+
+func viewProfileEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		p, err := svc.ViewProfile(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toProfileRes(p), nil
+	}
+}
+
+func listProfilesEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listResourcesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListProfiles(ctx, req.token, req.pageMetadata)
+		if err != nil {
+			return nil, err
+		}
+
+		res := profilesPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+				Order:  page.Order,
+				Dir:    page.Dir,
+			},
+			Profiles: []viewProfileRes{},
+		}
+		for _, p := range page.Profiles {
+			res.Profiles = append(res.Profiles, toProfileRes(p))
+		}
+
+		return res, nil
+	}
+}
+
+func removeProfileEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveProfile(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func toProfileRes(p things.Profile) viewProfileRes {
+	return viewProfileRes{
+		ID:          p.ID,
+		Name:        p.Name,
+		Metadata:    p.Metadata,
+		Channels:    p.Channels,
+		Connections: p.Connections,
+	}
+}
+
 func connectThingEndpoint(svc things.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		cr := request.(connectThingReq)
@@ -564,3 +845,169 @@ func buildThingsResponse(up things.Page) thingsPageRes {
 	}
 	return res
 }
+
+func updateQuotaEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateQuotaReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		q := things.Quota{
+			Messages:    req.Messages,
+			PayloadSize: req.PayloadSize,
+		}
+		if err := svc.UpdateQuota(ctx, req.token, req.owner, q); err != nil {
+			return nil, err
+		}
+
+		return updateQuotaRes{}, nil
+	}
+}
+
+func viewQuotaEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewQuotaReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		q, err := svc.ViewQuota(ctx, req.token, req.owner)
+		if err != nil {
+			return nil, err
+		}
+
+		res := viewQuotaRes{
+			Owner:       req.owner,
+			Messages:    q.Messages,
+			PayloadSize: q.PayloadSize,
+		}
+		return res, nil
+	}
+}
+
+func toKeyRes(k things.Key) keyRes {
+	res := keyRes{
+		Key:   k.Value,
+		Label: k.Label,
+	}
+	if !k.ExpiresAt.IsZero() {
+		res.ExpiresAt = &k.ExpiresAt
+	}
+	return res
+}
+
+func importThingsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(importReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		results, err := svc.ImportThings(ctx, req.token, req.records, req.dryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		return toImportRes(results, req.dryRun), nil
+	}
+}
+
+func importChannelsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(importReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		results, err := svc.ImportChannels(ctx, req.token, req.records, req.dryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		return toImportRes(results, req.dryRun), nil
+	}
+}
+
+func exportThingsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		records, err := svc.ExportThings(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := exportRes{
+			output:  req.output,
+			columns: thingColumns,
+			records: records,
+		}
+		return res, nil
+	}
+}
+
+func exportChannelsEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		records, err := svc.ExportChannels(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := exportRes{
+			output:  req.output,
+			columns: channelColumns,
+			records: records,
+		}
+		return res, nil
+	}
+}
+
+func toImportRes(results []things.ImportResult, dryRun bool) importRes {
+	res := importRes{
+		DryRun: dryRun,
+		Rows:   []importRowRes{},
+	}
+	for _, r := range results {
+		row := importRowRes{
+			Row:    r.Row,
+			ID:     r.Record.ID,
+			Action: r.Action,
+		}
+		switch {
+		case r.Err != nil:
+			row.Error = r.Err.Error()
+			res.Failed++
+		case r.Action == things.ImportCreate:
+			res.Created++
+		default:
+			res.Updated++
+		}
+		res.Rows = append(res.Rows, row)
+	}
+	return res
+}
+
+func toRecordRes(rec things.Record) recordRes {
+	return recordRes{
+		ID:          rec.ID,
+		Name:        rec.Name,
+		Key:         rec.Key,
+		Metadata:    rec.Metadata,
+		Connections: rec.Connections,
+	}
+}
@@ -28,17 +28,19 @@ import (
 )
 
 const (
-	contentType = "application/json"
-	email       = "user@example.com"
-	adminEmail  = "admin@example.com"
-	token       = "token"
-	wrongValue  = "wrong_value"
-	wrongID     = 0
-	maxNameSize = 1024
-	nameKey     = "name"
-	ascKey      = "asc"
-	descKey     = "desc"
-	prefix      = "fe6b4e92-cc98-425e-b0aa-"
+	contentType       = "application/json"
+	csvContentType    = "text/csv"
+	ndjsonContentType = "application/x-ndjson"
+	email             = "user@example.com"
+	adminEmail        = "admin@example.com"
+	token             = "token"
+	wrongValue        = "wrong_value"
+	wrongID           = 0
+	maxNameSize       = 1024
+	nameKey           = "name"
+	ascKey            = "asc"
+	descKey           = "desc"
+	prefix            = "fe6b4e92-cc98-425e-b0aa-"
 )
 
 var (
@@ -91,11 +93,14 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	profilesRepo := mocks.NewProfileRepository()
+	quotasRepo := mocks.NewQuotaRepository()
+	statusRepo := mocks.NewStatusRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idProvider := uuid.NewMock()
 
-	return things.New(auth, thingsRepo, channelsRepo, chanCache, thingCache, idProvider)
+	return things.New(auth, thingsRepo, channelsRepo, profilesRepo, quotasRepo, statusRepo, chanCache, thingCache, idProvider)
 }
 
 func newServer(svc things.Service) *httptest.Server {
@@ -200,6 +205,22 @@ func TestCreateThing(t *testing.T) {
 			status:      http.StatusBadRequest,
 			location:    "",
 		},
+		{
+			desc:        "add thing with invalid profile ID",
+			req:         fmt.Sprintf(`{"profile_id": "%s"}`, wrongValue),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+		{
+			desc:        "add thing with non-existent profile",
+			req:         fmt.Sprintf(`{"profile_id": "%s%012d"}`, uuid.Prefix, 100),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
 	}
 
 	for _, tc := range cases {
@@ -685,6 +706,286 @@ func TestUpdateKey(t *testing.T) {
 	}
 }
 
+func TestRotateKey(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	ths, err := svc.CreateThings(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	th := ths[0]
+
+	cases := []struct {
+		desc        string
+		req         string
+		id          string
+		contentType string
+		auth        string
+		status      int
+		res         string
+	}{
+		{
+			desc:        "rotate key of an existing thing",
+			req:         `{"key":"rotated-key","grace_period":3600}`,
+			id:          th.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			res:         toJSON(keyRes{Key: "rotated-key"}),
+		},
+		{
+			desc:        "rotate key to the conflicting key",
+			req:         `{"key":"rotated-key"}`,
+			id:          th.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusConflict,
+			res:         toJSON(httputil.ErrorRes{Err: errors.ErrConflict.Error()}),
+		},
+		{
+			desc:        "rotate key with too long grace period",
+			req:         `{"grace_period":18446744073709551615}`,
+			id:          th.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			res:         toJSON(httputil.ErrorRes{Err: errors.ErrMalformedEntity.Error()}),
+		},
+		{
+			desc:        "rotate key of non-existent thing",
+			req:         "{}",
+			id:          strconv.FormatUint(wrongID, 10),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusForbidden,
+			res:         unauthzRes,
+		},
+		{
+			desc:        "rotate key with invalid user token",
+			req:         "{}",
+			id:          th.ID,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusUnauthorized,
+			res:         unauthRes,
+		},
+		{
+			desc:        "rotate key with invalid data format",
+			req:         "{",
+			id:          th.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			res:         toJSON(httputil.ErrorRes{Err: errors.ErrMalformedEntity.Error()}),
+		},
+		{
+			desc:        "rotate key without content type",
+			req:         "{}",
+			id:          th.ID,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+			res:         toJSON(httputil.ErrorRes{Err: errors.ErrUnsupportedContentType.Error()}),
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things/%s/key/rotate", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+
+	_, err = svc.Identify(context.Background(), th.Key)
+	assert.Nil(t, err, fmt.Sprintf("identify by rotated key within grace period: unexpected error %s", err))
+}
+
+func TestAddKey(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	ths, err := svc.CreateThings(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	th := ths[0]
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	expired := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	cases := []struct {
+		desc        string
+		req         string
+		id          string
+		contentType string
+		auth        string
+		status      int
+		res         string
+	}{
+		{
+			desc:        "add labeled key to an existing thing",
+			req:         toJSON(keyRes{Key: "backup-key", Label: "backup"}),
+			id:          th.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			res:         toJSON(keyRes{Key: "backup-key", Label: "backup"}),
+		},
+		{
+			desc:        "add expiring key to an existing thing",
+			req:         toJSON(keyRes{Key: "temporary-key", ExpiresAt: &expiresAt}),
+			id:          th.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			res:         toJSON(keyRes{Key: "temporary-key", ExpiresAt: &expiresAt}),
+		},
+		{
+			desc:        "add expired key to an existing thing",
+			req:         toJSON(keyRes{Key: "expired-key", ExpiresAt: &expired}),
+			id:          th.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			res:         toJSON(httputil.ErrorRes{Err: errors.ErrMalformedEntity.Error()}),
+		},
+		{
+			desc:        "add key with invalid label",
+			req:         toJSON(keyRes{Key: "other-key", Label: invalidName}),
+			id:          th.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			res:         toJSON(httputil.ErrorRes{Err: errors.ErrMalformedEntity.Error()}),
+		},
+		{
+			desc:        "add conflicting key to an existing thing",
+			req:         toJSON(keyRes{Key: th.Key}),
+			id:          th.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusConflict,
+			res:         toJSON(httputil.ErrorRes{Err: errors.ErrConflict.Error()}),
+		},
+		{
+			desc:        "add key to non-existent thing",
+			req:         "{}",
+			id:          strconv.FormatUint(wrongID, 10),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusForbidden,
+			res:         unauthzRes,
+		},
+		{
+			desc:        "add key with invalid user token",
+			req:         "{}",
+			id:          th.ID,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusUnauthorized,
+			res:         unauthRes,
+		},
+		{
+			desc:        "add key without content type",
+			req:         "{}",
+			id:          th.ID,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+			res:         toJSON(httputil.ErrorRes{Err: errors.ErrUnsupportedContentType.Error()}),
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things/%s/keys", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+}
+
+func TestRemoveKey(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	ths, err := svc.CreateThings(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	th := ths[0]
+	key, err := svc.AddKey(context.Background(), token, th.ID, things.Key{Label: "backup"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		key    string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "remove key with invalid user token",
+			id:     th.ID,
+			key:    key.Value,
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "remove additional key of an existing thing",
+			id:     th.ID,
+			key:    key.Value,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove removed key of an existing thing",
+			id:     th.ID,
+			key:    key.Value,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "remove key of non-existent thing",
+			id:     strconv.FormatUint(wrongID, 10),
+			key:    key.Value,
+			auth:   token,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/things/%s/keys/%s", ts.URL, tc.id, tc.key),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
 func TestViewThing(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
@@ -826,6 +1127,13 @@ func TestListThings(t *testing.T) {
 			url:    fmt.Sprintf("%s?offset=%d&limit=%d&order=name&dir=wrong", thingURL, 0, 5),
 			res:    nil,
 		},
+		{
+			desc:   "get a list of things with invalid online filter",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s?offset=%d&limit=%d&online=wrong", thingURL, 0, 5),
+			res:    nil,
+		},
 		{
 			desc:   "get a list of things with invalid token",
 			auth:   wrongValue,
@@ -1403,26 +1711,102 @@ func TestRemoveThing(t *testing.T) {
 	}
 }
 
-func TestCreateChannel(t *testing.T) {
+func TestRestoreThing(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
 	defer ts.Close()
 
-	data := toJSON(channel)
+	ths, err := svc.CreateThings(context.Background(), token, things.Thing{Name: "restored"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	th := ths[0]
+	err = svc.RemoveThing(context.Background(), token, th.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 
-	th := channel
-	th.Name = invalidName
-	invalidData := toJSON(th)
+	req := testRequest{
+		client: ts.Client(),
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s/things?deleted=true", ts.URL),
+		token:  token,
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	var page thingsPageRes
+	err = json.NewDecoder(res.Body).Decode(&page)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	require.Len(t, page.Things, 1, "expected one removed thing")
+	assert.Equal(t, th.ID, page.Things[0].ID, fmt.Sprintf("list removed things: expected id %s got %s", th.ID, page.Things[0].ID))
 
 	cases := []struct {
-		desc        string
-		req         string
-		contentType string
-		auth        string
-		status      int
-		location    string
-	}{
-		{
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "restore thing with invalid token",
+			id:     th.ID,
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "restore thing with empty token",
+			id:     th.ID,
+			auth:   "",
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "restore removed thing",
+			id:     th.ID,
+			auth:   token,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "restore restored thing",
+			id:     th.ID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "restore non-existent thing",
+			id:     strconv.FormatUint(wrongID, 10),
+			auth:   token,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/things/%s/restore", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestCreateChannel(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	data := toJSON(channel)
+
+	th := channel
+	th.Name = invalidName
+	invalidData := toJSON(th)
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		location    string
+	}{
+		{
 			desc:        "create new channel",
 			req:         data,
 			contentType: contentType,
@@ -1513,6 +1897,8 @@ func TestCreateChannels(t *testing.T) {
 
 	data := `[{"name": "1"}, {"name": "2"}]`
 	invalidData := fmt.Sprintf(`[{"name": "%s"}]`, invalidName)
+	schemaData := `[{"name": "1", "metadata": {"schema": {"senml": [{"name": "temperature", "unit": "Cel"}]}}}]`
+	invalidSchemaData := `[{"name": "1", "metadata": {"schema": {"json": {"type": 5}}}}]`
 
 	cases := []struct {
 		desc        string
@@ -1585,6 +1971,22 @@ func TestCreateChannels(t *testing.T) {
 			status:      http.StatusBadRequest,
 			response:    "",
 		},
+		{
+			desc:        "create channel with payload schema",
+			data:        schemaData,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			response:    "",
+		},
+		{
+			desc:        "create channel with invalid payload schema",
+			data:        invalidSchemaData,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			response:    "",
+		},
 	}
 
 	for _, tc := range cases {
@@ -1621,6 +2023,10 @@ func TestUpdateChannel(t *testing.T) {
 	c.Name = invalidName
 	invalidData := toJSON(c)
 
+	c.Name = "updated_channel"
+	c.Metadata = map[string]interface{}{"schema": map[string]interface{}{"senml": "temperature"}}
+	invalidSchemaData := toJSON(c)
+
 	cases := []struct {
 		desc        string
 		req         string
@@ -1708,6 +2114,14 @@ func TestUpdateChannel(t *testing.T) {
 			auth:        token,
 			status:      http.StatusBadRequest,
 		},
+		{
+			desc:        "update channel with invalid payload schema",
+			req:         invalidSchemaData,
+			id:          ch.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
 	}
 
 	for _, tc := range cases {
@@ -2258,6 +2672,76 @@ func TestRemoveChannel(t *testing.T) {
 	}
 }
 
+func TestRestoreChannel(t *testing.T) {
+	svc := newService(map[string]string{token: adminEmail})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	chs, err := svc.CreateChannels(context.Background(), token, things.Channel{Name: "restored"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch := chs[0]
+	err = svc.RemoveChannel(context.Background(), token, ch.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	req := testRequest{
+		client: ts.Client(),
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s/channels?deleted=true", ts.URL),
+		token:  token,
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	var page channelsPageRes
+	err = json.NewDecoder(res.Body).Decode(&page)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	require.Len(t, page.Channels, 1, "expected one removed channel")
+	assert.Equal(t, ch.ID, page.Channels[0].ID, fmt.Sprintf("list removed channels: expected id %s got %s", ch.ID, page.Channels[0].ID))
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "restore channel with invalid token",
+			id:     ch.ID,
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "restore channel with empty token",
+			id:     ch.ID,
+			auth:   "",
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "restore removed channel",
+			id:     ch.ID,
+			auth:   token,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "restore restored channel",
+			id:     ch.ID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/channels/%s/restore", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
 func TestConnect(t *testing.T) {
 	otherToken := "other_token"
 	otherEmail := "other_user@example.com"
@@ -2837,29 +3321,1023 @@ func TestDisconnnect(t *testing.T) {
 	}
 }
 
-type thingRes struct {
-	ID       string                 `json:"id"`
-	Name     string                 `json:"name,omitempty"`
-	Key      string                 `json:"key"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
-}
+func TestUpdateQuota(t *testing.T) {
+	adminToken := "admin-token"
+	svc := newService(map[string]string{token: email, adminToken: adminEmail})
+	ts := newServer(svc)
+	defer ts.Close()
 
-type channelRes struct {
-	ID       string                 `json:"id"`
-	Name     string                 `json:"name,omitempty"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	data := toJSON(quotaRes{Messages: 60, PayloadSize: 1024})
+
+	cases := []struct {
+		desc        string
+		req         string
+		owner       string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "update quota as admin",
+			req:         data,
+			owner:       email,
+			contentType: contentType,
+			auth:        adminToken,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "update quota as non-admin",
+			req:         data,
+			owner:       email,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "update quota with invalid token",
+			req:         data,
+			owner:       email,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "update quota with invalid request format",
+			req:         "}",
+			owner:       email,
+			contentType: contentType,
+			auth:        adminToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update quota with negative limit",
+			req:         `{"messages_per_minute":-1}`,
+			owner:       email,
+			contentType: contentType,
+			auth:        adminToken,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update quota without content type",
+			req:         data,
+			owner:       email,
+			contentType: "",
+			auth:        adminToken,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/quotas/%s", ts.URL, tc.owner),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
 }
 
-type thingsPageRes struct {
-	Things []thingRes `json:"things"`
-	Total  uint64     `json:"total"`
-	Offset uint64     `json:"offset"`
-	Limit  uint64     `json:"limit"`
+func TestViewQuota(t *testing.T) {
+	adminToken := "admin-token"
+	otherToken := "other-token"
+	svc := newService(map[string]string{token: email, adminToken: adminEmail, otherToken: "other@example.com"})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	q := things.Quota{Messages: 60, PayloadSize: 1024}
+	err := svc.UpdateQuota(context.Background(), adminToken, email, q)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	data := toJSON(quotaRes{Owner: email, Messages: q.Messages, PayloadSize: q.PayloadSize})
+	emptyData := toJSON(quotaRes{Owner: adminEmail})
+
+	cases := []struct {
+		desc   string
+		owner  string
+		auth   string
+		status int
+		res    string
+	}{
+		{
+			desc:   "view own quota",
+			owner:  email,
+			auth:   token,
+			status: http.StatusOK,
+			res:    data,
+		},
+		{
+			desc:   "view quota as admin",
+			owner:  email,
+			auth:   adminToken,
+			status: http.StatusOK,
+			res:    data,
+		},
+		{
+			desc:   "view quota that is not set",
+			owner:  adminEmail,
+			auth:   adminToken,
+			status: http.StatusOK,
+			res:    emptyData,
+		},
+		{
+			desc:   "view quota of another owner",
+			owner:  email,
+			auth:   otherToken,
+			status: http.StatusForbidden,
+			res:    unauthzRes,
+		},
+		{
+			desc:   "view quota with invalid token",
+			owner:  email,
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+			res:    unauthRes,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/quotas/%s", ts.URL, tc.owner),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
 }
 
-type channelsPageRes struct {
+func TestViewStatus(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	ths, err := svc.CreateThings(context.Background(), token, thing, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	seen, unseen := ths[0], ths[1]
+
+	lastSeen := time.Unix(1640995200, 0).UTC()
+	st := things.Status{
+		ThingID:  seen.ID,
+		Online:   true,
+		LastSeen: lastSeen,
+		Protocol: "mqtt",
+		ClientIP: "10.0.0.1",
+	}
+	err = svc.UpdateStatus(context.Background(), st)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	data := toJSON(statusRes{ID: seen.ID, Online: true, LastSeen: &lastSeen, Protocol: "mqtt", ClientIP: "10.0.0.1"})
+	unseenData := toJSON(statusRes{ID: unseen.ID})
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+		res    string
+	}{
+		{
+			desc:   "view status of online thing",
+			id:     seen.ID,
+			auth:   token,
+			status: http.StatusOK,
+			res:    data,
+		},
+		{
+			desc:   "view status of never seen thing",
+			id:     unseen.ID,
+			auth:   token,
+			status: http.StatusOK,
+			res:    unseenData,
+		},
+		{
+			desc:   "view status with invalid token",
+			id:     seen.ID,
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+			res:    unauthRes,
+		},
+		{
+			desc:   "view status with empty token",
+			id:     seen.ID,
+			auth:   "",
+			status: http.StatusUnauthorized,
+			res:    unauthRes,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/things/%s/status", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+}
+
+func TestCreateProfile(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	chs, err := svc.CreateChannels(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	pc := things.ProfileChannel{
+		Name:      "telemetry",
+		Subtopics: []string{"temperature"},
+		Schema:    map[string]interface{}{"json": map[string]interface{}{"type": "object"}},
+	}
+	data := toJSON(profileRes{Name: "thermometer", Channels: []things.ProfileChannel{pc}, Connections: []string{chs[0].ID}})
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		location    string
+	}{
+		{
+			desc:        "add valid profile",
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			location:    fmt.Sprintf("/profiles/%s%012d", uuid.Prefix, 2),
+		},
+		{
+			desc:        "add profile with invalid name",
+			req:         toJSON(profileRes{Name: invalidName}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+		{
+			desc:        "add profile with unnamed channel",
+			req:         toJSON(profileRes{Channels: []things.ProfileChannel{{}}}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+		{
+			desc:        "add profile with duplicated channel names",
+			req:         toJSON(profileRes{Channels: []things.ProfileChannel{pc, pc}}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+		{
+			desc:        "add profile with invalid channel schema",
+			req:         toJSON(profileRes{Channels: []things.ProfileChannel{{Name: "a", Schema: map[string]interface{}{"json": "invalid"}}}}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+		{
+			desc:        "add profile with invalid connection",
+			req:         toJSON(profileRes{Connections: []string{wrongValue}}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+		{
+			desc:        "add profile with non-existing connection",
+			req:         toJSON(profileRes{Connections: []string{fmt.Sprintf("%s%012d", uuid.Prefix, 100)}}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+		{
+			desc:        "add profile with invalid auth token",
+			req:         data,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusUnauthorized,
+			location:    "",
+		},
+		{
+			desc:        "add profile with invalid request format",
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+			location:    "",
+		},
+		{
+			desc:        "add profile without content type",
+			req:         data,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+			location:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/profiles", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+
+		location := res.Header.Get("Location")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.location, location, fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	p, err := svc.CreateProfile(context.Background(), token, things.Profile{Name: "thermometer"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	data := toJSON(profileRes{Name: "updated", Metadata: map[string]interface{}{"model": "t2"}})
+
+	cases := []struct {
+		desc        string
+		req         string
+		id          string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "update existing profile",
+			req:         data,
+			id:          p.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "update non-existent profile",
+			req:         data,
+			id:          strconv.FormatUint(wrongID, 10),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "update profile with invalid name",
+			req:         toJSON(profileRes{Name: invalidName}),
+			id:          p.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update profile with invalid user token",
+			req:         data,
+			id:          p.ID,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "update profile without content type",
+			req:         data,
+			id:          p.ID,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/profiles/%s", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestViewProfile(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	p := things.Profile{
+		Name:     "thermometer",
+		Metadata: map[string]interface{}{"model": "t1"},
+		Channels: []things.ProfileChannel{{Name: "telemetry", Subtopics: []string{"temperature"}}},
+	}
+	p, err := svc.CreateProfile(context.Background(), token, p)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	data := toJSON(profileRes{ID: p.ID, Name: p.Name, Metadata: p.Metadata, Channels: p.Channels})
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+		res    string
+	}{
+		{
+			desc:   "view existing profile",
+			id:     p.ID,
+			auth:   token,
+			status: http.StatusOK,
+			res:    data,
+		},
+		{
+			desc:   "view non-existent profile",
+			id:     strconv.FormatUint(wrongID, 10),
+			auth:   token,
+			status: http.StatusNotFound,
+			res:    notFoundRes,
+		},
+		{
+			desc:   "view profile by passing invalid token",
+			id:     p.ID,
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+			res:    unauthRes,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/profiles/%s", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+}
+
+func TestListProfiles(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	data := []profileRes{}
+	for i := 0; i < 10; i++ {
+		p, err := svc.CreateProfile(context.Background(), token, things.Profile{Name: fmt.Sprintf("profile-%d", i)})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+		data = append(data, profileRes{ID: p.ID, Name: p.Name})
+	}
+
+	cases := []struct {
+		desc   string
+		auth   string
+		status int
+		url    string
+		res    []profileRes
+	}{
+		{
+			desc:   "get a list of profiles",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/profiles?offset=%d&limit=%d", ts.URL, 0, 5),
+			res:    data[0:5],
+		},
+		{
+			desc:   "get a list of profiles with offset",
+			auth:   token,
+			status: http.StatusOK,
+			url:    fmt.Sprintf("%s/profiles?offset=%d&limit=%d", ts.URL, 8, 5),
+			res:    data[8:10],
+		},
+		{
+			desc:   "get a list of profiles with limit greater than max",
+			auth:   token,
+			status: http.StatusBadRequest,
+			url:    fmt.Sprintf("%s/profiles?offset=%d&limit=%d", ts.URL, 0, 110),
+			res:    nil,
+		},
+		{
+			desc:   "get a list of profiles with invalid token",
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+			url:    fmt.Sprintf("%s/profiles?offset=%d&limit=%d", ts.URL, 0, 5),
+			res:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var data profilesPageRes
+		json.NewDecoder(res.Body).Decode(&data)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.ElementsMatch(t, tc.res, data.Profiles, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, data.Profiles))
+	}
+}
+
+func TestRemoveProfile(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	p, err := svc.CreateProfile(context.Background(), token, things.Profile{Name: "thermometer"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "delete existing profile",
+			id:     p.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "delete non-existent profile",
+			id:     p.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "delete profile with invalid token",
+			id:     p.ID,
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/profiles/%s", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestImportThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	_, err := svc.CreateChannels(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, err = svc.CreateThings(context.Background(), token, things.Thing{Name: "twin"}, things.Thing{Name: "twin"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	id := fmt.Sprintf("%s%012d", uuid.Prefix, 6)
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		query       string
+		auth        string
+		status      int
+		res         string
+	}{
+		{
+			desc:        "import things from CSV",
+			req:         "name,metadata,connections\na,\"{\"\"model\"\":\"\"t1\"\"}\",test\ntwin,,\n",
+			contentType: csvContentType,
+			auth:        token,
+			status:      http.StatusOK,
+			res: toJSON(importRes{
+				Created: 1,
+				Failed:  1,
+				Rows: []importRowRes{
+					{Row: 1, ID: id, Action: things.ImportCreate},
+					{Row: 2, Error: things.ErrAmbiguousMatch.Error()},
+				},
+			}),
+		},
+		{
+			desc:        "import things from NDJSON with dry run",
+			req:         "{\"name\":\"a\",\"key\":\"key\"}\n{\"name\":\"b\",\"connections\":[\"test\"]}\n",
+			contentType: ndjsonContentType,
+			query:       "?dry_run=true",
+			auth:        token,
+			status:      http.StatusOK,
+			res: toJSON(importRes{
+				DryRun:  true,
+				Created: 1,
+				Updated: 1,
+				Rows: []importRowRes{
+					{Row: 1, ID: id, Action: things.ImportUpdate},
+					{Row: 2, Action: things.ImportCreate},
+				},
+			}),
+		},
+		{
+			desc:        "import things from CSV with unknown column",
+			req:         "name,owner\na,b\n",
+			contentType: csvContentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import things from CSV with invalid metadata",
+			req:         "name,metadata\na,invalid\n",
+			contentType: csvContentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import things from CSV without records",
+			req:         "name,metadata\n",
+			contentType: csvContentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import things from invalid NDJSON",
+			req:         "{\"name\":\"a\"\n",
+			contentType: ndjsonContentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import things with invalid dry run",
+			req:         "name\na\n",
+			contentType: csvContentType,
+			query:       "?dry_run=invalid",
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import things with invalid content type",
+			req:         "name\na\n",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "import things with invalid token",
+			req:         "name\na\n",
+			contentType: csvContentType,
+			auth:        wrongValue,
+			status:      http.StatusUnauthorized,
+			res:         unauthRes,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things/import%s", ts.URL, tc.query),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.res == "" {
+			continue
+		}
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+}
+
+func TestImportChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	chs, err := svc.CreateChannels(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch := chs[0]
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		res         string
+	}{
+		{
+			desc:        "import channels from NDJSON",
+			req:         fmt.Sprintf("{\"id\":\"%s\",\"metadata\":{\"unit\":\"Cel\"}}\n{\"name\":\"new\"}\n", ch.ID),
+			contentType: ndjsonContentType,
+			auth:        token,
+			status:      http.StatusOK,
+			res: toJSON(importRes{
+				Created: 1,
+				Updated: 1,
+				Rows: []importRowRes{
+					{Row: 1, ID: ch.ID, Action: things.ImportUpdate},
+					{Row: 2, ID: fmt.Sprintf("%s%012d", uuid.Prefix, 2), Action: things.ImportCreate},
+				},
+			}),
+		},
+		{
+			desc:        "import channels from CSV with thing column",
+			req:         "name,connections\na,b\n",
+			contentType: csvContentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import channels from NDJSON with thing field",
+			req:         "{\"name\":\"a\",\"key\":\"key\"}\n",
+			contentType: ndjsonContentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "import channels with invalid token",
+			req:         "name\na\n",
+			contentType: csvContentType,
+			auth:        wrongValue,
+			status:      http.StatusUnauthorized,
+			res:         unauthRes,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels/import", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.res == "" {
+			continue
+		}
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+}
+
+func TestExportThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	chs, err := svc.CreateChannels(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch := chs[0]
+	ths, err := svc.CreateThings(context.Background(), token, thing)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	th := ths[0]
+	err = svc.Connect(context.Background(), token, []string{ch.ID}, []string{th.ID})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc        string
+		query       string
+		auth        string
+		status      int
+		contentType string
+		res         string
+	}{
+		{
+			desc:        "export things as NDJSON",
+			auth:        token,
+			status:      http.StatusOK,
+			contentType: ndjsonContentType,
+			res:         toJSON(recordRes{ID: th.ID, Name: th.Name, Key: th.Key, Metadata: th.Metadata, Connections: []string{ch.ID}}),
+		},
+		{
+			desc:        "export things as CSV",
+			query:       "?output=csv",
+			auth:        token,
+			status:      http.StatusOK,
+			contentType: csvContentType,
+			res:         fmt.Sprintf("id,name,key,metadata,connections\n%s,%s,%s,\"{\"\"test\"\":\"\"data\"\"}\",%s", th.ID, th.Name, th.Key, ch.ID),
+		},
+		{
+			desc:   "export things with invalid output",
+			query:  "?output=invalid",
+			auth:   token,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "export things with invalid token",
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+			res:    unauthRes,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/things/export%s", ts.URL, tc.query),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.contentType != "" {
+			assert.Equal(t, tc.contentType, res.Header.Get("Content-Type"), fmt.Sprintf("%s: expected content type %s got %s", tc.desc, tc.contentType, res.Header.Get("Content-Type")))
+		}
+		if tc.res == "" {
+			continue
+		}
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+}
+
+func TestExportChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	chs, err := svc.CreateChannels(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch := chs[0]
+
+	cases := []struct {
+		desc   string
+		query  string
+		auth   string
+		status int
+		res    string
+	}{
+		{
+			desc:   "export channels as NDJSON",
+			auth:   token,
+			status: http.StatusOK,
+			res:    toJSON(recordRes{ID: ch.ID, Name: ch.Name, Metadata: ch.Metadata}),
+		},
+		{
+			desc:   "export channels as CSV",
+			query:  "?output=csv",
+			auth:   token,
+			status: http.StatusOK,
+			res:    fmt.Sprintf("id,name,metadata\n%s,%s,\"{\"\"test\"\":\"\"data\"\"}\"", ch.ID, ch.Name),
+		},
+		{
+			desc:   "export channels with invalid token",
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+			res:    unauthRes,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/export%s", ts.URL, tc.query),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+}
+
+type thingRes struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name,omitempty"`
+	Key      string                 `json:"key"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type channelRes struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+type thingsPageRes struct {
+	Things []thingRes `json:"things"`
+	Total  uint64     `json:"total"`
+	Offset uint64     `json:"offset"`
+	Limit  uint64     `json:"limit"`
+}
+
+type channelsPageRes struct {
 	Channels []channelRes `json:"channels"`
 	Total    uint64       `json:"total"`
 	Offset   uint64       `json:"offset"`
 	Limit    uint64       `json:"limit"`
 }
+
+type quotaRes struct {
+	Owner       string `json:"owner,omitempty"`
+	Messages    uint64 `json:"messages_per_minute"`
+	PayloadSize uint64 `json:"max_payload_size"`
+}
+
+type keyRes struct {
+	Key       string     `json:"key"`
+	Label     string     `json:"label,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type statusRes struct {
+	ID       string     `json:"id"`
+	Online   bool       `json:"online"`
+	LastSeen *time.Time `json:"last_seen,omitempty"`
+	Protocol string     `json:"protocol,omitempty"`
+	ClientIP string     `json:"client_ip,omitempty"`
+}
+
+type profileRes struct {
+	ID          string                  `json:"id,omitempty"`
+	Name        string                  `json:"name,omitempty"`
+	Metadata    map[string]interface{}  `json:"metadata,omitempty"`
+	Channels    []things.ProfileChannel `json:"channels,omitempty"`
+	Connections []string                `json:"connections,omitempty"`
+}
+
+type profilesPageRes struct {
+	Profiles []profileRes `json:"profiles"`
+	Total    uint64       `json:"total"`
+	Offset   uint64       `json:"offset"`
+	Limit    uint64       `json:"limit"`
+}
+
+type importRowRes struct {
+	Row    int    `json:"row"`
+	ID     string `json:"id,omitempty"`
+	Action string `json:"action,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type importRes struct {
+	DryRun  bool           `json:"dry_run"`
+	Created int            `json:"created"`
+	Updated int            `json:"updated"`
+	Failed  int            `json:"failed"`
+	Rows    []importRowRes `json:"rows"`
+}
+
+type recordRes struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name,omitempty"`
+	Key         string                 `json:"key,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Connections []string               `json:"connections,omitempty"`
+}
@@ -4,8 +4,12 @@
 package http
 
 import (
+	"math"
+	"time"
+
 	"github.com/gofrs/uuid"
 	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/schema"
 	"github.com/mainflux/mainflux/things"
 )
 
@@ -22,11 +26,12 @@ const (
 )
 
 type createThingReq struct {
-	token    string
-	Name     string                 `json:"name,omitempty"`
-	Key      string                 `json:"key,omitempty"`
-	ID       string                 `json:"id,omitempty"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	token     string
+	Name      string                 `json:"name,omitempty"`
+	Key       string                 `json:"key,omitempty"`
+	ID        string                 `json:"id,omitempty"`
+	ProfileID string                 `json:"profile_id,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
 }
 
 func validateUUID(extID string) (err error) {
@@ -38,6 +43,15 @@ func validateUUID(extID string) (err error) {
 	return nil
 }
 
+// validateSchema validates the payload schema stored in the channel metadata.
+func validateSchema(metadata map[string]interface{}) error {
+	if _, err := schema.Parse(metadata); err != nil {
+		return errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return nil
+}
+
 func (req createThingReq) validate() error {
 	if req.token == "" {
 		return errors.ErrAuthentication
@@ -47,6 +61,10 @@ func (req createThingReq) validate() error {
 		return errors.ErrMalformedEntity
 	}
 
+	if req.ProfileID != "" && validateUUID(req.ProfileID) != nil {
+		return errors.ErrMalformedEntity
+	}
+
 	if len(req.Name) > maxNameSize {
 		return errors.ErrMalformedEntity
 	}
@@ -73,6 +91,10 @@ func (req createThingsReq) validate() error {
 			return errors.ErrMalformedEntity
 		}
 
+		if thing.ProfileID != "" && validateUUID(thing.ProfileID) != nil {
+			return errors.ErrMalformedEntity
+		}
+
 		if len(thing.Name) > maxNameSize {
 			return errors.ErrMalformedEntity
 		}
@@ -145,6 +167,72 @@ func (req updateKeyReq) validate() error {
 	return nil
 }
 
+type rotateKeyReq struct {
+	token string
+	id    string
+	Key   string `json:"key,omitempty"`
+	// GracePeriod is the number of seconds the replaced key remains active.
+	GracePeriod uint64 `json:"grace_period,omitempty"`
+}
+
+func (req rotateKeyReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+
+	if req.id == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	if req.GracePeriod > math.MaxInt64/uint64(time.Second) {
+		return errors.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type addKeyReq struct {
+	token     string
+	id        string
+	Key       string     `json:"key,omitempty"`
+	Label     string     `json:"label,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func (req addKeyReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+
+	if req.id == "" || len(req.Label) > maxNameSize {
+		return errors.ErrMalformedEntity
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return errors.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type removeKeyReq struct {
+	token string
+	id    string
+	key   string
+}
+
+func (req removeKeyReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+
+	if req.id == "" || req.key == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	return nil
+}
+
 type createChannelReq struct {
 	token    string
 	Name     string                 `json:"name,omitempty"`
@@ -165,7 +253,7 @@ func (req createChannelReq) validate() error {
 		return errors.ErrMalformedEntity
 	}
 
-	return nil
+	return validateSchema(req.Metadata)
 }
 
 type createChannelsReq struct {
@@ -190,6 +278,10 @@ func (req createChannelsReq) validate() error {
 		if len(channel.Name) > maxNameSize {
 			return errors.ErrMalformedEntity
 		}
+
+		if err := validateSchema(channel.Metadata); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -215,6 +307,74 @@ func (req updateChannelReq) validate() error {
 		return errors.ErrMalformedEntity
 	}
 
+	return validateSchema(req.Metadata)
+}
+
+type profileReq struct {
+	token       string
+	id          string
+	ID          string                  `json:"id,omitempty"`
+	Name        string                  `json:"name,omitempty"`
+	Metadata    map[string]interface{}  `json:"metadata,omitempty"`
+	Channels    []things.ProfileChannel `json:"channels,omitempty"`
+	Connections []string                `json:"connections,omitempty"`
+}
+
+func (req profileReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+
+	if req.ID != "" && validateUUID(req.ID) != nil {
+		return errors.ErrMalformedEntity
+	}
+
+	if len(req.Name) > maxNameSize {
+		return errors.ErrMalformedEntity
+	}
+
+	names := map[string]bool{}
+	for _, pc := range req.Channels {
+		if pc.Name == "" || len(pc.Name) > maxNameSize || names[pc.Name] {
+			return errors.ErrMalformedEntity
+		}
+		names[pc.Name] = true
+
+		for _, st := range pc.Subtopics {
+			if st == "" {
+				return errors.ErrMalformedEntity
+			}
+		}
+
+		if len(pc.Schema) > 0 {
+			if err := validateSchema(map[string]interface{}{schema.MetadataKey: pc.Schema}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, chID := range req.Connections {
+		if validateUUID(chID) != nil {
+			return errors.ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+type updateProfileReq struct {
+	profileReq
+}
+
+func (req updateProfileReq) validate() error {
+	if err := req.profileReq.validate(); err != nil {
+		return err
+	}
+
+	if req.id == "" {
+		return errors.ErrMalformedEntity
+	}
+
 	return nil
 }
 
@@ -385,3 +545,78 @@ func (req listThingsGroupReq) validate() error {
 	return nil
 
 }
+
+type updateQuotaReq struct {
+	token       string
+	owner       string
+	Messages    uint64 `json:"messages_per_minute"`
+	PayloadSize uint64 `json:"max_payload_size"`
+}
+
+func (req updateQuotaReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+
+	if req.owner == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	if req.Messages > math.MaxInt64 || req.PayloadSize > math.MaxInt64 {
+		return errors.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewQuotaReq struct {
+	token string
+	owner string
+}
+
+func (req viewQuotaReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+
+	if req.owner == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type importReq struct {
+	token   string
+	dryRun  bool
+	records []things.Record
+}
+
+func (req importReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+
+	if len(req.records) == 0 {
+		return errors.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type exportReq struct {
+	token  string
+	output string
+}
+
+func (req exportReq) validate() error {
+	if req.token == "" {
+		return errors.ErrAuthentication
+	}
+
+	if req.output != csvOutput && req.output != ndjsonOutput {
+		return errors.ErrInvalidQueryParams
+	}
+
+	return nil
+}
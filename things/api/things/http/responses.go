@@ -6,14 +6,18 @@ package http
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
 )
 
 var (
 	_ mainflux.Response = (*removeRes)(nil)
 	_ mainflux.Response = (*thingRes)(nil)
 	_ mainflux.Response = (*viewThingRes)(nil)
+	_ mainflux.Response = (*keyRes)(nil)
+	_ mainflux.Response = (*viewStatusRes)(nil)
 	_ mainflux.Response = (*thingsPageRes)(nil)
 	_ mainflux.Response = (*channelRes)(nil)
 	_ mainflux.Response = (*viewChannelRes)(nil)
@@ -23,6 +27,10 @@ var (
 	_ mainflux.Response = (*disconnectThingRes)(nil)
 	_ mainflux.Response = (*disconnectRes)(nil)
 	_ mainflux.Response = (*shareThingRes)(nil)
+	_ mainflux.Response = (*profileRes)(nil)
+	_ mainflux.Response = (*viewProfileRes)(nil)
+	_ mainflux.Response = (*profilesPageRes)(nil)
+	_ mainflux.Response = (*importRes)(nil)
 )
 
 type removeRes struct{}
@@ -106,11 +114,14 @@ func (res thingsRes) Empty() bool {
 }
 
 type viewThingRes struct {
-	ID       string                 `json:"id"`
-	Owner    string                 `json:"-"`
-	Name     string                 `json:"name,omitempty"`
-	Key      string                 `json:"key"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	ID        string                 `json:"id"`
+	Owner     string                 `json:"-"`
+	Name      string                 `json:"name,omitempty"`
+	Key       string                 `json:"key"`
+	Keys      []keyRes               `json:"keys,omitempty"`
+	ProfileID string                 `json:"profile_id,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	DeletedAt *time.Time             `json:"deleted_at,omitempty"`
 }
 
 func (res viewThingRes) Code() int {
@@ -125,6 +136,49 @@ func (res viewThingRes) Empty() bool {
 	return false
 }
 
+type keyRes struct {
+	Key       string     `json:"key"`
+	Label     string     `json:"label,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	created   bool
+}
+
+func (res keyRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res keyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res keyRes) Empty() bool {
+	return false
+}
+
+type viewStatusRes struct {
+	ID       string     `json:"id"`
+	Online   bool       `json:"online"`
+	LastSeen *time.Time `json:"last_seen,omitempty"`
+	Protocol string     `json:"protocol,omitempty"`
+	ClientIP string     `json:"client_ip,omitempty"`
+}
+
+func (res viewStatusRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewStatusRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewStatusRes) Empty() bool {
+	return false
+}
+
 type thingsPageRes struct {
 	pageRes
 	Things []viewThingRes `json:"things"`
@@ -194,11 +248,12 @@ func (res channelsRes) Empty() bool {
 }
 
 type viewChannelRes struct {
-	ID       string                 `json:"id"`
-	Owner    string                 `json:"-"`
-	Name     string                 `json:"name,omitempty"`
-	Things   []viewThingRes         `json:"connected,omitempty"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	ID        string                 `json:"id"`
+	Owner     string                 `json:"-"`
+	Name      string                 `json:"name,omitempty"`
+	Things    []viewThingRes         `json:"connected,omitempty"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	DeletedAt *time.Time             `json:"deleted_at,omitempty"`
 }
 
 func (res viewChannelRes) Code() int {
@@ -230,6 +285,70 @@ func (res channelsPageRes) Empty() bool {
 	return false
 }
 
+type profileRes struct {
+	ID      string `json:"id"`
+	created bool
+}
+
+func (res profileRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res profileRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/profiles/%s", res.ID),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res profileRes) Empty() bool {
+	return true
+}
+
+type viewProfileRes struct {
+	ID          string                  `json:"id"`
+	Name        string                  `json:"name,omitempty"`
+	Metadata    map[string]interface{}  `json:"metadata,omitempty"`
+	Channels    []things.ProfileChannel `json:"channels,omitempty"`
+	Connections []string                `json:"connections,omitempty"`
+}
+
+func (res viewProfileRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewProfileRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewProfileRes) Empty() bool {
+	return false
+}
+
+type profilesPageRes struct {
+	pageRes
+	Profiles []viewProfileRes `json:"profiles"`
+}
+
+func (res profilesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res profilesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res profilesPageRes) Empty() bool {
+	return false
+}
+
 type connectThingRes struct{}
 
 func (res connectThingRes) Code() int {
@@ -288,6 +407,38 @@ func (res disconnectThingRes) Empty() bool {
 	return true
 }
 
+type updateQuotaRes struct{}
+
+func (res updateQuotaRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updateQuotaRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res updateQuotaRes) Empty() bool {
+	return true
+}
+
+type viewQuotaRes struct {
+	Owner       string `json:"owner"`
+	Messages    uint64 `json:"messages_per_minute"`
+	PayloadSize uint64 `json:"max_payload_size"`
+}
+
+func (res viewQuotaRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewQuotaRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewQuotaRes) Empty() bool {
+	return false
+}
+
 type pageRes struct {
 	Total  uint64 `json:"total"`
 	Offset uint64 `json:"offset"`
@@ -295,3 +446,45 @@ type pageRes struct {
 	Order  string `json:"order"`
 	Dir    string `json:"direction"`
 }
+
+type importRowRes struct {
+	Row    int    `json:"row"`
+	ID     string `json:"id,omitempty"`
+	Action string `json:"action,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type importRes struct {
+	DryRun  bool           `json:"dry_run"`
+	Created int            `json:"created"`
+	Updated int            `json:"updated"`
+	Failed  int            `json:"failed"`
+	Rows    []importRowRes `json:"rows"`
+}
+
+func (res importRes) Code() int {
+	return http.StatusOK
+}
+
+func (res importRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res importRes) Empty() bool {
+	return false
+}
+
+// exportRes is encoded as the CSV or NDJSON records by encodeExport.
+type exportRes struct {
+	output  string
+	columns []string
+	records []things.Record
+}
+
+type recordRes struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name,omitempty"`
+	Key         string                 `json:"key,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Connections []string               `json:"connections,omitempty"`
+}
@@ -5,7 +5,9 @@ package http
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"io"
 	"net/http"
 	"strings"
 
@@ -31,8 +33,31 @@ const (
 	metadataKey = "metadata"
 	disconnKey  = "disconnected"
 	sharedKey   = "shared"
+	onlineKey   = "online"
+	deletedKey  = "deleted"
+	dryRunKey   = "dry_run"
+	outputKey   = "output"
 	defOffset   = 0
 	defLimit    = 10
+
+	csvOutput         = "csv"
+	ndjsonOutput      = "ndjson"
+	csvContentType    = "text/csv"
+	ndjsonContentType = "application/x-ndjson"
+
+	idColumn          = "id"
+	nameColumn        = "name"
+	keyColumn         = "key"
+	metadataColumn    = "metadata"
+	connectionsColumn = "connections"
+	connectionsSep    = ";"
+)
+
+var (
+	thingColumns   = []string{idColumn, nameColumn, keyColumn, metadataColumn, connectionsColumn}
+	channelColumns = []string{idColumn, nameColumn, metadataColumn}
+
+	errUnknownColumn = errors.New("unknown column")
 )
 
 // MakeHandler returns a HTTP handler for API endpoints.
@@ -57,6 +82,20 @@ func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Post("/things/import", kithttp.NewServer(
+		kitot.TraceServer(tracer, "import_things")(importThingsEndpoint(svc)),
+		decodeThingsImport,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/things/export", kithttp.NewServer(
+		kitot.TraceServer(tracer, "export_things")(exportThingsEndpoint(svc)),
+		decodeExport,
+		encodeExport,
+		opts...,
+	))
+
 	r.Post("/things/:id/share", kithttp.NewServer(
 		kitot.TraceServer(tracer, "share_thing")(shareThingEndpoint(svc)),
 		decodeShareThing,
@@ -71,6 +110,27 @@ func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Post("/things/:id/key/rotate", kithttp.NewServer(
+		kitot.TraceServer(tracer, "rotate_key")(rotateKeyEndpoint(svc)),
+		decodeKeyRotation,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/things/:id/keys", kithttp.NewServer(
+		kitot.TraceServer(tracer, "add_key")(addKeyEndpoint(svc)),
+		decodeKeyAddition,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/things/:id/keys/:key", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_key")(removeKeyEndpoint(svc)),
+		decodeKeyRemoval,
+		encodeResponse,
+		opts...,
+	))
+
 	r.Put("/things/:id", kithttp.NewServer(
 		kitot.TraceServer(tracer, "update_thing")(updateThingEndpoint(svc)),
 		decodeThingUpdate,
@@ -85,6 +145,13 @@ func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Post("/things/:id/restore", kithttp.NewServer(
+		kitot.TraceServer(tracer, "restore_thing")(restoreThingEndpoint(svc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
 	r.Get("/things/:id", kithttp.NewServer(
 		kitot.TraceServer(tracer, "view_thing")(viewThingEndpoint(svc)),
 		decodeView,
@@ -92,6 +159,13 @@ func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Get("/things/:id/status", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_status")(viewStatusEndpoint(svc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
 	r.Get("/things/:id/channels", kithttp.NewServer(
 		kitot.TraceServer(tracer, "list_channels_by_thing")(listChannelsByThingEndpoint(svc)),
 		decodeListByConnection,
@@ -127,6 +201,20 @@ func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Post("/channels/import", kithttp.NewServer(
+		kitot.TraceServer(tracer, "import_channels")(importChannelsEndpoint(svc)),
+		decodeChannelsImport,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels/export", kithttp.NewServer(
+		kitot.TraceServer(tracer, "export_channels")(exportChannelsEndpoint(svc)),
+		decodeExport,
+		encodeExport,
+		opts...,
+	))
+
 	r.Put("/channels/:id", kithttp.NewServer(
 		kitot.TraceServer(tracer, "update_channel")(updateChannelEndpoint(svc)),
 		decodeChannelUpdate,
@@ -141,6 +229,13 @@ func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Post("/channels/:id/restore", kithttp.NewServer(
+		kitot.TraceServer(tracer, "restore_channel")(restoreChannelEndpoint(svc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
 	r.Get("/channels/:id", kithttp.NewServer(
 		kitot.TraceServer(tracer, "view_channel")(viewChannelEndpoint(svc)),
 		decodeView,
@@ -197,6 +292,55 @@ func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Put("/quotas/:owner", kithttp.NewServer(
+		kitot.TraceServer(tracer, "update_quota")(updateQuotaEndpoint(svc)),
+		decodeQuotaUpdate,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/quotas/:owner", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_quota")(viewQuotaEndpoint(svc)),
+		decodeViewQuota,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/profiles", kithttp.NewServer(
+		kitot.TraceServer(tracer, "create_profile")(createProfileEndpoint(svc)),
+		decodeProfileCreation,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Put("/profiles/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "update_profile")(updateProfileEndpoint(svc)),
+		decodeProfileUpdate,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/profiles/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_profile")(removeProfileEndpoint(svc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/profiles/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_profile")(viewProfileEndpoint(svc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/profiles", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_profiles")(listProfilesEndpoint(svc)),
+		decodeList,
+		encodeResponse,
+		opts...,
+	))
+
 	r.GetFunc("/health", mainflux.Health("things"))
 	r.Handle("/metrics", promhttp.Handler())
 
@@ -361,6 +505,104 @@ func decodeChannelUpdate(_ context.Context, r *http.Request) (interface{}, error
 	return req, nil
 }
 
+func decodeKeyRotation(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	t, err := httputil.ExtractAuthToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := rotateKeyReq{
+		token: t,
+		id:    bone.GetValue(r, "id"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeKeyAddition(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	t, err := httputil.ExtractAuthToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := addKeyReq{
+		token: t,
+		id:    bone.GetValue(r, "id"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeKeyRemoval(_ context.Context, r *http.Request) (interface{}, error) {
+	t, err := httputil.ExtractAuthToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := removeKeyReq{
+		token: t,
+		id:    bone.GetValue(r, "id"),
+		key:   bone.GetValue(r, "key"),
+	}
+
+	return req, nil
+}
+
+func decodeProfileCreation(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	t, err := httputil.ExtractAuthToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := profileReq{token: t}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeProfileUpdate(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	t, err := httputil.ExtractAuthToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := updateProfileReq{
+		profileReq{
+			token: t,
+			id:    bone.GetValue(r, "id"),
+		},
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
 func decodeView(_ context.Context, r *http.Request) (interface{}, error) {
 	t, err := httputil.ExtractAuthToken(r)
 	if err != nil {
@@ -375,6 +617,41 @@ func decodeView(_ context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
+func decodeQuotaUpdate(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	t, err := httputil.ExtractAuthToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := updateQuotaReq{
+		token: t,
+		owner: bone.GetValue(r, "owner"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeViewQuota(_ context.Context, r *http.Request) (interface{}, error) {
+	t, err := httputil.ExtractAuthToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := viewQuotaReq{
+		token: t,
+		owner: bone.GetValue(r, "owner"),
+	}
+
+	return req, nil
+}
+
 func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 	o, err := httputil.ReadUintQuery(r, offsetKey, defOffset)
 	if err != nil {
@@ -410,6 +687,21 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 		return nil, err
 	}
 
+	// The online filter is applied only if it's set.
+	var online *bool
+	if _, ok := r.URL.Query()[onlineKey]; ok {
+		on, err := httputil.ReadBoolQuery(r, onlineKey, false)
+		if err != nil {
+			return nil, err
+		}
+		online = &on
+	}
+
+	deleted, err := httputil.ReadBoolQuery(r, deletedKey, false)
+	if err != nil {
+		return nil, err
+	}
+
 	t, err := httputil.ExtractAuthToken(r)
 	if err != nil {
 		return nil, err
@@ -425,6 +717,8 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 			Dir:               d,
 			Metadata:          m,
 			FetchSharedThings: shared,
+			Online:            online,
+			Deleted:           deleted,
 		},
 	}
 
@@ -557,6 +851,177 @@ func decodeListMembersRequest(_ context.Context, r *http.Request) (interface{},
 	return req, nil
 }
 
+func decodeThingsImport(_ context.Context, r *http.Request) (interface{}, error) {
+	return decodeImport(r, thingColumns)
+}
+
+func decodeChannelsImport(_ context.Context, r *http.Request) (interface{}, error) {
+	return decodeImport(r, channelColumns)
+}
+
+func decodeImport(r *http.Request, columns []string) (interface{}, error) {
+	var read func(io.Reader, []string) ([]things.Record, error)
+	switch ct := r.Header.Get("Content-Type"); {
+	case strings.Contains(ct, csvContentType):
+		read = readCSV
+	case strings.Contains(ct, ndjsonContentType):
+		read = readNDJSON
+	default:
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	t, err := httputil.ExtractAuthToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	dryRun, err := httputil.ReadBoolQuery(r, dryRunKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := read(r.Body, columns)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	req := importReq{
+		token:   t,
+		dryRun:  dryRun,
+		records: records,
+	}
+
+	return req, nil
+}
+
+// readCSV reads the records from the CSV with the header row naming the
+// columns. The metadata is the JSON object and the connections are separated
+// by semicolons.
+func readCSV(r io.Reader, columns []string) ([]things.Record, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, col := range header {
+		header[i] = strings.TrimSpace(col)
+		if !hasColumn(columns, header[i]) || hasColumn(header[:i], header[i]) {
+			return nil, errors.Wrap(errUnknownColumn, errors.New(col))
+		}
+	}
+
+	var records []things.Record
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var rec things.Record
+		for i, val := range row {
+			switch header[i] {
+			case idColumn:
+				rec.ID = val
+			case nameColumn:
+				rec.Name = val
+			case keyColumn:
+				rec.Key = val
+			case metadataColumn:
+				if val == "" {
+					continue
+				}
+				if err := json.Unmarshal([]byte(val), &rec.Metadata); err != nil {
+					return nil, err
+				}
+			case connectionsColumn:
+				for _, conn := range strings.Split(val, connectionsSep) {
+					if conn = strings.TrimSpace(conn); conn != "" {
+						rec.Connections = append(rec.Connections, conn)
+					}
+				}
+			}
+		}
+		records = append(records, rec)
+	}
+}
+
+// readNDJSON reads the records from the JSON objects, one per line, with the
+// same fields as the CSV columns.
+func readNDJSON(r io.Reader, columns []string) ([]things.Record, error) {
+	dec := json.NewDecoder(r)
+
+	var records []things.Record
+	for {
+		var fields map[string]json.RawMessage
+		err := dec.Decode(&fields)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var rec things.Record
+		for col, val := range fields {
+			if !hasColumn(columns, col) {
+				return nil, errors.Wrap(errUnknownColumn, errors.New(col))
+			}
+
+			var dst interface{}
+			switch col {
+			case idColumn:
+				dst = &rec.ID
+			case nameColumn:
+				dst = &rec.Name
+			case keyColumn:
+				dst = &rec.Key
+			case metadataColumn:
+				dst = &rec.Metadata
+			case connectionsColumn:
+				dst = &rec.Connections
+			}
+			if err := json.Unmarshal(val, dst); err != nil {
+				return nil, err
+			}
+		}
+		records = append(records, rec)
+	}
+}
+
+func hasColumn(columns []string, col string) bool {
+	for _, c := range columns {
+		if c == col {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeExport(_ context.Context, r *http.Request) (interface{}, error) {
+	t, err := httputil.ExtractAuthToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := httputil.ReadStringQuery(r, outputKey, ndjsonOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	req := exportReq{
+		token:  t,
+		output: output,
+	}
+
+	return req, nil
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", contentType)
 
@@ -613,3 +1078,67 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		}
 	}
 }
+
+// encodeExport writes the exported records in the format the import accepts.
+func encodeExport(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	res := response.(exportRes)
+
+	if res.output == csvOutput {
+		w.Header().Set("Content-Type", csvContentType)
+		w.WriteHeader(http.StatusOK)
+
+		cw := csv.NewWriter(w)
+		if err := cw.Write(res.columns); err != nil {
+			return err
+		}
+		for _, rec := range res.records {
+			row, err := csvRecord(rec, res.columns)
+			if err != nil {
+				return err
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	w.Header().Set("Content-Type", ndjsonContentType)
+	w.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(w)
+	for _, rec := range res.records {
+		if err := enc.Encode(toRecordRes(rec)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func csvRecord(rec things.Record, columns []string) ([]string, error) {
+	row := make([]string, len(columns))
+	for i, col := range columns {
+		switch col {
+		case idColumn:
+			row[i] = rec.ID
+		case nameColumn:
+			row[i] = rec.Name
+		case keyColumn:
+			row[i] = rec.Key
+		case metadataColumn:
+			if len(rec.Metadata) == 0 {
+				continue
+			}
+			md, err := json.Marshal(rec.Metadata)
+			if err != nil {
+				return nil, err
+			}
+			row[i] = string(md)
+		case connectionsColumn:
+			row[i] = strings.Join(rec.Connections, connectionsSep)
+		}
+	}
+	return row, nil
+}
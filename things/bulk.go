@@ -0,0 +1,191 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+import (
+	"fmt"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+const (
+	// ExternalIDKey is the metadata key under which the external ID, used
+	// to match the imported records with the existing entities, is stored.
+	ExternalIDKey = "external_id"
+
+	// ImportCreate reports that the imported record created a new entity.
+	ImportCreate = "create"
+
+	// ImportUpdate reports that the imported record updated the existing
+	// entity.
+	ImportUpdate = "update"
+
+	maxNameSize = 1024
+	bulkLimit   = 100
+)
+
+// ErrAmbiguousMatch indicates that the imported record matches more than one
+// existing entity.
+var ErrAmbiguousMatch = errors.New("record matches multiple entities")
+
+// Record represents a thing or a channel in the bulk import and export.
+type Record struct {
+	ID       string
+	Owner    string
+	Name     string
+	Key      string
+	Metadata Metadata
+	// Connections contains the IDs or the names of the channels the thing
+	// is connected to. It's not used for the channels.
+	Connections []string
+}
+
+// ImportResult reports the outcome of importing a single record.
+type ImportResult struct {
+	// Row is the 1-based position of the record in the import.
+	Row    int
+	Action string
+	// Record contains the created or updated entity, with the IDs of the
+	// channels the import connected the thing to. The record that failed
+	// or was only validated in the dry run is reported as provided.
+	Record Record
+	Err    error
+	// Partial is set if the entity was stored, but the import failed to
+	// connect it to the channels. The Record contains the stored entity,
+	// and Err the connection failure.
+	Partial bool
+}
+
+func (r Record) validate() error {
+	if len(r.Name) > maxNameSize {
+		return errors.ErrMalformedEntity
+	}
+
+	if r.ID != "" {
+		if _, err := uuid.FromString(r.ID); err != nil {
+			return errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+	}
+
+	return nil
+}
+
+// matcher matches the imported records with the existing entities by the ID,
+// the external ID stored in the metadata, or the name, in that order.
+type matcher struct {
+	ids    map[string]bool
+	extIDs map[string][]string
+	names  map[string][]string
+}
+
+func newMatcher() *matcher {
+	return &matcher{
+		ids:    map[string]bool{},
+		extIDs: map[string][]string{},
+		names:  map[string][]string{},
+	}
+}
+
+func (m *matcher) add(id, name string, md Metadata) {
+	if id != "" {
+		m.ids[id] = true
+	}
+	if extID := externalID(md); extID != "" {
+		m.extIDs[extID] = append(m.extIDs[extID], id)
+	}
+	if name != "" {
+		m.names[name] = append(m.names[name], id)
+	}
+}
+
+// match returns the ID of the entity the record matches, or an empty string
+// if it matches none.
+func (m *matcher) match(r Record) (string, error) {
+	if r.ID != "" {
+		if m.ids[r.ID] {
+			return r.ID, nil
+		}
+		return "", nil
+	}
+
+	if extID := externalID(r.Metadata); extID != "" {
+		return single(m.extIDs[extID])
+	}
+
+	if r.Name != "" {
+		return single(m.names[r.Name])
+	}
+
+	return "", nil
+}
+
+// resolve returns the IDs of the entities referred to by the IDs or names.
+func (m *matcher) resolve(refs []string) ([]string, error) {
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		id := ref
+		if !m.ids[ref] {
+			var err error
+			if id, err = single(m.names[ref]); err != nil {
+				return nil, err
+			}
+			if id == "" {
+				return nil, errors.Wrap(errors.ErrNotFound, errors.New(ref))
+			}
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+func single(ids []string) (string, error) {
+	switch len(ids) {
+	case 0:
+		return "", nil
+	case 1:
+		return ids[0], nil
+	default:
+		return "", ErrAmbiguousMatch
+	}
+}
+
+func externalID(md Metadata) string {
+	extID, _ := md[ExternalIDKey].(string)
+	return extID
+}
+
+// pendingID returns the ID under which the record that would be created in
+// the dry run is matched by the following records.
+func pendingID(r Record, i int) string {
+	if r.ID != "" {
+		return r.ID
+	}
+	return fmt.Sprintf("#%d", i+1)
+}
+
+func thingRecord(th Thing, chIDs []string) Record {
+	return Record{
+		ID:          th.ID,
+		Owner:       th.Owner,
+		Name:        th.Name,
+		Key:         th.Key,
+		Metadata:    th.Metadata,
+		Connections: chIDs,
+	}
+}
+
+func channelRecord(ch Channel) Record {
+	return Record{
+		ID:       ch.ID,
+		Owner:    ch.Owner,
+		Name:     ch.Name,
+		Metadata: ch.Metadata,
+	}
+}
@@ -5,6 +5,7 @@ package things
 
 import (
 	"context"
+	"time"
 )
 
 // Channel represents a Mainflux "communication group". This group contains the
@@ -14,6 +15,9 @@ type Channel struct {
 	Owner    string
 	Name     string
 	Metadata map[string]interface{}
+	// DeletedAt is the time the channel was removed at. It's zero for the
+	// channels that aren't removed.
+	DeletedAt time.Time
 }
 
 // ChannelsPage contains page related metadata as well as list of channels that
@@ -35,20 +39,35 @@ type ChannelRepository interface {
 	Update(ctx context.Context, c Channel) error
 
 	// RetrieveByID retrieves the channel having the provided identifier, that is owned
-	// by the specified user.
+	// by the specified user. Empty owner retrieves the channel regardless of its owner.
 	RetrieveByID(ctx context.Context, owner, id string) (Channel, error)
 
+	// IsOwner checks whether all the channels having the provided
+	// identifiers are owned by the specified user, using a single query.
+	IsOwner(ctx context.Context, owner string, ids []string) error
+
 	// RetrieveAll retrieves the subset of channels owned by the specified user.
+	// The removed channels are retrieved instead if the Deleted flag is set.
 	RetrieveAll(ctx context.Context, owner string, pm PageMetadata) (ChannelsPage, error)
 
 	// RetrieveByThing retrieves the subset of channels owned by the specified
 	// user and have specified thing connected or not connected to them.
 	RetrieveByThing(ctx context.Context, owner, thID string, pm PageMetadata) (ChannelsPage, error)
 
-	// Remove removes the channel having the provided identifier, that is owned
-	// by the specified user.
+	// Remove marks the channel having the provided identifier, that is owned
+	// by the specified user, as removed. The removed channel is kept along
+	// with its connections until it's purged, and it can be restored
+	// meanwhile.
 	Remove(ctx context.Context, owner, id string) error
 
+	// Restore restores the removed channel having the provided identifier,
+	// that is owned by the specified user.
+	Restore(ctx context.Context, owner, id string) error
+
+	// Purge permanently deletes the channels removed before the given time,
+	// and returns their IDs.
+	Purge(ctx context.Context, before time.Time) ([]string, error)
+
 	// Connect adds things to the channels list of connected things.
 	Connect(ctx context.Context, owner string, chIDs, thIDs []string) error
 
@@ -0,0 +1,27 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+import "time"
+
+// RotatedKeyLabel is the label of the primary key replaced by the rotation.
+const RotatedKeyLabel = "rotated"
+
+// Key represents an additional access key of the thing. Besides its primary
+// key, the thing may hold any number of labeled keys, which are accepted the
+// same way as the primary key until they expire. Rotating the primary key
+// keeps the replaced key as an additional key for the grace period.
+type Key struct {
+	Value   string
+	ThingID string
+	Label   string
+	// ExpiresAt is the time after which the key isn't accepted anymore.
+	// Zero value means that the key never expires.
+	ExpiresAt time.Time
+}
+
+// Active reports whether the key is accepted at the given time.
+func (k Key) Active(t time.Time) bool {
+	return k.ExpiresAt.IsZero() || t.Before(k.ExpiresAt)
+}
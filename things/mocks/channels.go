@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/things"
@@ -27,6 +28,7 @@ type channelRepositoryMock struct {
 	mu       sync.Mutex
 	counter  uint64
 	channels map[string]things.Channel
+	deleted  map[string]things.Channel
 	tconns   chan Connection                      // used for synchronization with thing repo
 	cconns   map[string]map[string]things.Channel // used to track connections
 	things   things.ThingRepository
@@ -36,6 +38,7 @@ type channelRepositoryMock struct {
 func NewChannelRepository(repo things.ThingRepository, tconns chan Connection) things.ChannelRepository {
 	return &channelRepositoryMock{
 		channels: make(map[string]things.Channel),
+		deleted:  make(map[string]things.Channel),
 		tconns:   tconns,
 		cconns:   make(map[string]map[string]things.Channel),
 		things:   repo,
@@ -76,9 +79,30 @@ func (crm *channelRepositoryMock) RetrieveByID(_ context.Context, owner, id stri
 		return c, nil
 	}
 
+	if owner == "" {
+		for _, c := range crm.channels {
+			if c.ID == id {
+				return c, nil
+			}
+		}
+	}
+
 	return things.Channel{}, errors.ErrNotFound
 }
 
+func (crm *channelRepositoryMock) IsOwner(_ context.Context, owner string, ids []string) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	for _, id := range ids {
+		if _, ok := crm.channels[key(owner, id)]; !ok {
+			return errors.ErrNotFound
+		}
+	}
+
+	return nil
+}
+
 func (crm *channelRepositoryMock) RetrieveAll(_ context.Context, owner string, pm things.PageMetadata) (things.ChannelsPage, error) {
 	if pm.Limit < 0 {
 		return things.ChannelsPage{}, nil
@@ -90,12 +114,17 @@ func (crm *channelRepositoryMock) RetrieveAll(_ context.Context, owner string, p
 	first := int(pm.Offset)
 	last := first + int(pm.Limit)
 
+	items := crm.channels
+	if pm.Deleted {
+		items = crm.deleted
+	}
+
 	var chs []things.Channel
 
 	// This obscure way to examine map keys is enforced by the key structure
 	// itself (see mocks/commons.go).
 	prefix := fmt.Sprintf("%s-", owner)
-	for k, v := range crm.channels {
+	for k, v := range items {
 		if strings.HasPrefix(k, prefix) {
 			chs = append(chs, v)
 		}
@@ -178,6 +207,10 @@ func (crm *channelRepositoryMock) RetrieveByThing(_ context.Context, owner, thID
 }
 
 func (crm *channelRepositoryMock) Remove(_ context.Context, owner, id string) error {
+	if ch, ok := crm.channels[key(owner, id)]; ok {
+		ch.DeletedAt = time.Now()
+		crm.deleted[key(owner, id)] = ch
+	}
 	delete(crm.channels, key(owner, id))
 	// delete channel from any thing list
 	for thk := range crm.cconns {
@@ -190,6 +223,30 @@ func (crm *channelRepositoryMock) Remove(_ context.Context, owner, id string) er
 	return nil
 }
 
+func (crm *channelRepositoryMock) Restore(_ context.Context, owner, id string) error {
+	ch, ok := crm.deleted[key(owner, id)]
+	if !ok {
+		return errors.ErrNotFound
+	}
+
+	ch.DeletedAt = time.Time{}
+	crm.channels[key(owner, id)] = ch
+	delete(crm.deleted, key(owner, id))
+	return nil
+}
+
+func (crm *channelRepositoryMock) Purge(_ context.Context, before time.Time) ([]string, error) {
+	var ids []string
+	for k, ch := range crm.deleted {
+		if ch.DeletedAt.Before(before) {
+			ids = append(ids, ch.ID)
+			delete(crm.deleted, k)
+		}
+	}
+
+	return ids, nil
+}
+
 func (crm *channelRepositoryMock) Connect(_ context.Context, owner string, chIDs, thIDs []string) error {
 	for _, chID := range chIDs {
 		ch, err := crm.RetrieveByID(context.Background(), owner, chID)
@@ -242,10 +299,11 @@ func (crm *channelRepositoryMock) Disconnect(_ context.Context, owner string, ch
 }
 
 func (crm *channelRepositoryMock) HasThing(_ context.Context, chanID, token string) (string, error) {
-	tid, err := crm.things.RetrieveByKey(context.Background(), token)
+	k, err := crm.things.RetrieveByKey(context.Background(), token)
 	if err != nil {
 		return "", err
 	}
+	tid := k.ThingID
 
 	chans, ok := crm.cconns[tid]
 	if !ok {
@@ -0,0 +1,114 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.ProfileRepository = (*profileRepositoryMock)(nil)
+
+type profileRepositoryMock struct {
+	mu       sync.Mutex
+	profiles map[string]things.Profile
+}
+
+// NewProfileRepository creates in-memory profile repository.
+func NewProfileRepository() things.ProfileRepository {
+	return &profileRepositoryMock{
+		profiles: make(map[string]things.Profile),
+	}
+}
+
+func (prm *profileRepositoryMock) Save(_ context.Context, p things.Profile) error {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	for _, pr := range prm.profiles {
+		if pr.ID == p.ID {
+			return errors.ErrConflict
+		}
+	}
+
+	prm.profiles[key(p.Owner, p.ID)] = p
+	return nil
+}
+
+func (prm *profileRepositoryMock) Update(_ context.Context, p things.Profile) error {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	dbKey := key(p.Owner, p.ID)
+	if _, ok := prm.profiles[dbKey]; !ok {
+		return errors.ErrNotFound
+	}
+
+	prm.profiles[dbKey] = p
+	return nil
+}
+
+func (prm *profileRepositoryMock) RetrieveByID(_ context.Context, owner, id string) (things.Profile, error) {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	if p, ok := prm.profiles[key(owner, id)]; ok {
+		return p, nil
+	}
+
+	return things.Profile{}, errors.ErrNotFound
+}
+
+func (prm *profileRepositoryMock) RetrieveAll(_ context.Context, owner string, pm things.PageMetadata) (things.ProfilesPage, error) {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	if pm.Limit == 0 {
+		pm.Limit = 10
+	}
+
+	var items []things.Profile
+	prefix := fmt.Sprintf("%s-", owner)
+	for k, v := range prm.profiles {
+		if strings.HasPrefix(k, prefix) {
+			items = append(items, v)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	total := uint64(len(items))
+	first := pm.Offset
+	if first > total {
+		first = total
+	}
+	last := first + pm.Limit
+	if last > total {
+		last = total
+	}
+
+	return things.ProfilesPage{
+		Profiles: items[first:last],
+		PageMetadata: things.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+	}, nil
+}
+
+func (prm *profileRepositoryMock) Remove(_ context.Context, owner, id string) error {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	delete(prm.profiles, key(owner, id))
+	return nil
+}
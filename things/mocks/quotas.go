@@ -0,0 +1,45 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.QuotaRepository = (*quotaRepositoryMock)(nil)
+
+type quotaRepositoryMock struct {
+	mu     sync.Mutex
+	quotas map[string]things.Quota
+}
+
+// NewQuotaRepository creates in-memory owner quota repository.
+func NewQuotaRepository() things.QuotaRepository {
+	return &quotaRepositoryMock{
+		quotas: make(map[string]things.Quota),
+	}
+}
+
+func (qrm *quotaRepositoryMock) Save(_ context.Context, owner string, q things.Quota) error {
+	qrm.mu.Lock()
+	defer qrm.mu.Unlock()
+
+	qrm.quotas[owner] = q
+	return nil
+}
+
+func (qrm *quotaRepositoryMock) RetrieveByOwner(_ context.Context, owner string) (things.Quota, error) {
+	qrm.mu.Lock()
+	defer qrm.mu.Unlock()
+
+	q, ok := qrm.quotas[owner]
+	if !ok {
+		return things.Quota{}, errors.ErrNotFound
+	}
+	return q, nil
+}
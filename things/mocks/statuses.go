@@ -0,0 +1,53 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.StatusRepository = (*statusRepositoryMock)(nil)
+
+type statusRepositoryMock struct {
+	mu       sync.Mutex
+	statuses map[string]things.Status
+}
+
+// NewStatusRepository creates in-memory thing status repository.
+func NewStatusRepository() things.StatusRepository {
+	return &statusRepositoryMock{
+		statuses: make(map[string]things.Status),
+	}
+}
+
+func (srm *statusRepositoryMock) Save(_ context.Context, st things.Status) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	if cur, ok := srm.statuses[st.ThingID]; ok {
+		if cur.LastSeen.After(st.LastSeen) {
+			return nil
+		}
+		if st.ClientIP == "" {
+			st.ClientIP = cur.ClientIP
+		}
+	}
+	srm.statuses[st.ThingID] = st
+	return nil
+}
+
+func (srm *statusRepositoryMock) RetrieveByThing(_ context.Context, id string) (things.Status, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	st, ok := srm.statuses[id]
+	if !ok {
+		return things.Status{}, errors.ErrNotFound
+	}
+	return st, nil
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/things"
@@ -21,14 +22,18 @@ type thingRepositoryMock struct {
 	conns   chan Connection
 	tconns  map[string]map[string]things.Thing
 	things  map[string]things.Thing
+	deleted map[string]things.Thing
+	keys    map[string]things.Key
 }
 
 // NewThingRepository creates in-memory thing repository.
 func NewThingRepository(conns chan Connection) things.ThingRepository {
 	repo := &thingRepositoryMock{
-		conns:  conns,
-		things: make(map[string]things.Thing),
-		tconns: make(map[string]map[string]things.Thing),
+		conns:   conns,
+		things:  make(map[string]things.Thing),
+		deleted: make(map[string]things.Thing),
+		tconns:  make(map[string]map[string]things.Thing),
+		keys:    make(map[string]things.Key),
 	}
 	go func(conns chan Connection, repo *thingRepositoryMock) {
 		for conn := range conns {
@@ -48,10 +53,8 @@ func (trm *thingRepositoryMock) Save(_ context.Context, ths ...things.Thing) ([]
 	defer trm.mu.Unlock()
 
 	for i := range ths {
-		for _, th := range trm.things {
-			if th.Key == ths[i].Key {
-				return []things.Thing{}, errors.ErrConflict
-			}
+		if trm.keyExists(ths[i].Key) {
+			return []things.Thing{}, errors.ErrConflict
 		}
 
 		trm.counter++
@@ -83,10 +86,8 @@ func (trm *thingRepositoryMock) UpdateKey(_ context.Context, owner, id, val stri
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
 
-	for _, th := range trm.things {
-		if th.Key == val {
-			return errors.ErrConflict
-		}
+	if trm.keyExists(val) {
+		return errors.ErrConflict
 	}
 
 	dbKey := key(owner, id)
@@ -124,12 +125,17 @@ func (trm *thingRepositoryMock) RetrieveAll(_ context.Context, owner string, pm
 	first := uint64(pm.Offset) + 1
 	last := first + uint64(pm.Limit)
 
+	items := trm.things
+	if pm.Deleted {
+		items = trm.deleted
+	}
+
 	var ths []things.Thing
 
 	// This obscure way to examine map keys is enforced by the key structure
 	// itself (see mocks/commons.go).
 	prefix := fmt.Sprintf("%s-", owner)
-	for k, v := range trm.things {
+	for k, v := range items {
 		id := parseID(v.ID)
 		if strings.HasPrefix(k, prefix) && id >= first && id < last {
 			ths = append(ths, v)
@@ -161,16 +167,12 @@ func (trm *thingRepositoryMock) RetrieveByIDs(_ context.Context, thingIDs []stri
 		return things.Page{}, nil
 	}
 
-	first := uint64(pm.Offset) + 1
-	last := first + uint64(pm.Limit)
-
 	// This obscure way to examine map keys is enforced by the key structure
 	// itself (see mocks/commons.go).
 	for _, id := range thingIDs {
 		suffix := fmt.Sprintf("-%s", id)
 		for k, v := range trm.things {
-			id := parseID(v.ID)
-			if strings.HasSuffix(k, suffix) && id >= first && id < last {
+			if strings.HasSuffix(k, suffix) {
 				items = append(items, v)
 			}
 		}
@@ -178,6 +180,17 @@ func (trm *thingRepositoryMock) RetrieveByIDs(_ context.Context, thingIDs []stri
 
 	items = sortThings(pm, items)
 
+	// Paginate the retrieved things rather than all the stored ones.
+	first := pm.Offset
+	if first > uint64(len(items)) {
+		first = uint64(len(items))
+	}
+	last := first + pm.Limit
+	if last > uint64(len(items)) {
+		last = uint64(len(items))
+	}
+	items = items[first:last]
+
 	page := things.Page{
 		Things: items,
 		PageMetadata: things.PageMetadata{
@@ -249,21 +262,158 @@ func (trm *thingRepositoryMock) RetrieveByChannel(_ context.Context, owner, chID
 func (trm *thingRepositoryMock) Remove(_ context.Context, owner, id string) error {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
-	delete(trm.things, key(owner, id))
+
+	dbKey := key(owner, id)
+	if th, ok := trm.things[dbKey]; ok {
+		th.DeletedAt = time.Now()
+		trm.deleted[dbKey] = th
+	}
+	delete(trm.things, dbKey)
 	return nil
 }
 
-func (trm *thingRepositoryMock) RetrieveByKey(_ context.Context, key string) (string, error) {
+func (trm *thingRepositoryMock) Restore(_ context.Context, owner, id string) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	dbKey := key(owner, id)
+	th, ok := trm.deleted[dbKey]
+	if !ok {
+		return errors.ErrNotFound
+	}
+
+	th.DeletedAt = time.Time{}
+	trm.things[dbKey] = th
+	delete(trm.deleted, dbKey)
+	return nil
+}
+
+func (trm *thingRepositoryMock) Purge(_ context.Context, before time.Time) ([]string, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	var ids []string
+	for k, th := range trm.deleted {
+		if th.DeletedAt.Before(before) {
+			ids = append(ids, th.ID)
+			delete(trm.deleted, k)
+		}
+	}
+
+	return ids, nil
+}
+
+func (trm *thingRepositoryMock) RotateKey(_ context.Context, id, val string, expiresAt time.Time) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	if trm.keyExists(val) {
+		return errors.ErrConflict
+	}
+
+	for k, th := range trm.things {
+		if th.ID != id {
+			continue
+		}
+		if !expiresAt.IsZero() {
+			trm.keys[th.Key] = things.Key{Value: th.Key, ThingID: id, Label: things.RotatedKeyLabel, ExpiresAt: expiresAt}
+		}
+		th.Key = val
+		trm.things[k] = th
+		return nil
+	}
+
+	return errors.ErrNotFound
+}
+
+func (trm *thingRepositoryMock) SaveKey(_ context.Context, key things.Key) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	if trm.keyExists(key.Value) {
+		return errors.ErrConflict
+	}
+	if _, ok := trm.thing(key.ThingID); !ok {
+		return errors.ErrNotFound
+	}
+
+	trm.keys[key.Value] = key
+	return nil
+}
+
+func (trm *thingRepositoryMock) RetrieveKeys(_ context.Context, id string) ([]things.Key, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	var keys []things.Key
+	for _, k := range trm.keys {
+		if k.ThingID == id && k.Active(time.Now()) {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys, nil
+}
+
+func (trm *thingRepositoryMock) RemoveKey(_ context.Context, id, val string) error {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	k, ok := trm.keys[val]
+	if !ok || k.ThingID != id {
+		return errors.ErrNotFound
+	}
+
+	delete(trm.keys, val)
+	return nil
+}
+
+func (trm *thingRepositoryMock) RetrieveByKey(_ context.Context, val string) (things.Key, error) {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
 
 	for _, thing := range trm.things {
-		if thing.Key == key {
-			return thing.ID, nil
+		if thing.Key == val {
+			return things.Key{Value: val, ThingID: thing.ID}, nil
 		}
 	}
 
-	return "", errors.ErrNotFound
+	if k, ok := trm.keys[val]; ok && k.Active(time.Now()) {
+		return k, nil
+	}
+
+	return things.Key{}, errors.ErrNotFound
+}
+
+// keyExists reports whether the key is held by any thing, including the
+// removed ones. The expired additional key is removed, so it can be reused.
+func (trm *thingRepositoryMock) keyExists(val string) bool {
+	if k, ok := trm.keys[val]; ok {
+		if k.Active(time.Now()) {
+			return true
+		}
+		delete(trm.keys, val)
+	}
+	for _, th := range trm.things {
+		if th.Key == val {
+			return true
+		}
+	}
+	for _, th := range trm.deleted {
+		if th.Key == val {
+			return true
+		}
+	}
+	return false
+}
+
+func (trm *thingRepositoryMock) thing(id string) (things.Thing, bool) {
+	for _, th := range trm.things {
+		if th.ID == id {
+			return th, true
+		}
+	}
+	return things.Thing{}, false
 }
 
 func (trm *thingRepositoryMock) connect(conn Connection) {
@@ -289,21 +439,21 @@ func (trm *thingRepositoryMock) disconnect(conn Connection) {
 
 type thingCacheMock struct {
 	mu     sync.Mutex
-	things map[string]string
+	things map[string]things.Key
 }
 
 // NewThingCache returns mock cache instance.
 func NewThingCache() things.ThingCache {
 	return &thingCacheMock{
-		things: make(map[string]string),
+		things: make(map[string]things.Key),
 	}
 }
 
-func (tcm *thingCacheMock) Save(_ context.Context, key, id string) error {
+func (tcm *thingCacheMock) Save(_ context.Context, key things.Key) error {
 	tcm.mu.Lock()
 	defer tcm.mu.Unlock()
 
-	tcm.things[key] = id
+	tcm.things[key.Value] = key
 	return nil
 }
 
@@ -311,12 +461,12 @@ func (tcm *thingCacheMock) ID(_ context.Context, key string) (string, error) {
 	tcm.mu.Lock()
 	defer tcm.mu.Unlock()
 
-	id, ok := tcm.things[key]
-	if !ok {
+	k, ok := tcm.things[key]
+	if !ok || !k.Active(time.Now()) {
 		return "", errors.ErrNotFound
 	}
 
-	return id, nil
+	return k.ThingID, nil
 }
 
 func (tcm *thingCacheMock) Remove(_ context.Context, id string) error {
@@ -324,9 +474,8 @@ func (tcm *thingCacheMock) Remove(_ context.Context, id string) error {
 	defer tcm.mu.Unlock()
 
 	for key, val := range tcm.things {
-		if val == id {
+		if val.ThingID == id {
 			delete(tcm.things, key)
-			return nil
 		}
 	}
 
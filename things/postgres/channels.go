@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/lib/pq"
@@ -73,7 +74,7 @@ func (cr channelRepository) Save(ctx context.Context, channels ...things.Channel
 }
 
 func (cr channelRepository) Update(ctx context.Context, channel things.Channel) error {
-	q := `UPDATE channels SET name = :name, metadata = :metadata WHERE owner = :owner AND id = :id;`
+	q := `UPDATE channels SET name = :name, metadata = :metadata WHERE owner = :owner AND id = :id AND deleted_at IS NULL;`
 
 	dbch := toDBChannel(channel)
 
@@ -103,7 +104,7 @@ func (cr channelRepository) Update(ctx context.Context, channel things.Channel)
 }
 
 func (cr channelRepository) RetrieveByID(ctx context.Context, owner, id string) (things.Channel, error) {
-	q := `SELECT name, metadata, owner FROM channels WHERE id = $1;`
+	q := `SELECT name, metadata, owner FROM channels WHERE id = $1 AND deleted_at IS NULL;`
 
 	dbch := dbChannel{
 		ID: id,
@@ -119,6 +120,33 @@ func (cr channelRepository) RetrieveByID(ctx context.Context, owner, id string)
 	return toChannel(dbch), nil
 }
 
+func (cr channelRepository) IsOwner(ctx context.Context, owner string, ids []string) error {
+	q := `SELECT COUNT(*) FROM channels WHERE id = ANY($1) AND owner = $2 AND deleted_at IS NULL;`
+
+	unique := map[string]bool{}
+	for _, id := range ids {
+		unique[id] = true
+	}
+	uids := make([]string, 0, len(unique))
+	for id := range unique {
+		uids = append(uids, id)
+	}
+
+	var total int
+	if err := cr.db.QueryRowxContext(ctx, q, pq.Array(uids), owner).Scan(&total); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return errors.ErrNotFound
+		}
+		return errors.Wrap(errors.ErrViewEntity, err)
+	}
+	if total != len(uids) {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
 func (cr channelRepository) RetrieveAll(ctx context.Context, owner string, pm things.PageMetadata) (things.ChannelsPage, error) {
 	nq, name := getNameQuery(pm.Name)
 	oq := getOrderQuery(pm.Order)
@@ -129,8 +157,7 @@ func (cr channelRepository) RetrieveAll(ctx context.Context, owner string, pm th
 		return things.ChannelsPage{}, errors.Wrap(errors.ErrViewEntity, err)
 	}
 
-	var whereClause string
-	var query []string
+	query := []string{getDeletedQuery(pm.Deleted)}
 	if mq != "" {
 		query = append(query, mq)
 	}
@@ -141,11 +168,9 @@ func (cr channelRepository) RetrieveAll(ctx context.Context, owner string, pm th
 		query = append(query, ownerQuery)
 	}
 
-	if len(query) > 0 {
-		whereClause = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
-	}
+	whereClause := fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
 
-	q := fmt.Sprintf(`SELECT id, name, metadata FROM channels
+	q := fmt.Sprintf(`SELECT id, name, metadata, deleted_at FROM channels
 		%s ORDER BY %s %s LIMIT :limit OFFSET :offset;`, whereClause, oq, dq)
 
 	params := map[string]interface{}{
@@ -207,7 +232,7 @@ func (cr channelRepository) RetrieveByThing(ctx context.Context, owner, thID str
 	case true:
 		q = fmt.Sprintf(`SELECT id, name, metadata
 		        FROM channels ch
-		        WHERE ch.owner = :owner AND ch.id NOT IN
+		        WHERE ch.owner = :owner AND ch.deleted_at IS NULL AND ch.id NOT IN
 		        (SELECT id FROM channels ch
 		          INNER JOIN connections conn
 		          ON ch.id = conn.channel_id
@@ -218,7 +243,7 @@ func (cr channelRepository) RetrieveByThing(ctx context.Context, owner, thID str
 
 		qc = `SELECT COUNT(*)
 		        FROM channels ch
-		        WHERE ch.owner = $1 AND ch.id NOT IN
+		        WHERE ch.owner = $1 AND ch.deleted_at IS NULL AND ch.id NOT IN
 		        (SELECT id FROM channels ch
 		          INNER JOIN connections conn
 		          ON ch.id = conn.channel_id
@@ -227,7 +252,7 @@ func (cr channelRepository) RetrieveByThing(ctx context.Context, owner, thID str
 		q = fmt.Sprintf(`SELECT id, name, metadata FROM channels ch
 		        INNER JOIN connections conn
 		        ON ch.id = conn.channel_id
-		        WHERE ch.owner = :owner AND conn.thing_id = :thing
+		        WHERE ch.owner = :owner AND ch.deleted_at IS NULL AND conn.thing_id = :thing
 		        ORDER BY %s %s
 		        LIMIT :limit
 		        OFFSET :offset;`, oq, dq)
@@ -236,7 +261,7 @@ func (cr channelRepository) RetrieveByThing(ctx context.Context, owner, thID str
 		        FROM channels ch
 		        INNER JOIN connections conn
 		        ON ch.id = conn.channel_id
-		        WHERE ch.owner = $1 AND conn.thing_id = $2`
+		        WHERE ch.owner = $1 AND ch.deleted_at IS NULL AND conn.thing_id = $2`
 	}
 
 	params := map[string]interface{}{
@@ -283,11 +308,44 @@ func (cr channelRepository) Remove(ctx context.Context, owner, id string) error
 		ID:    id,
 		Owner: owner,
 	}
-	q := `DELETE FROM channels WHERE id = :id AND owner = :owner`
+	q := `UPDATE channels SET deleted_at = NOW() WHERE id = :id AND owner = :owner AND deleted_at IS NULL;`
 	cr.db.NamedExecContext(ctx, q, dbch)
 	return nil
 }
 
+func (cr channelRepository) Restore(ctx context.Context, owner, id string) error {
+	dbch := dbChannel{
+		ID:    id,
+		Owner: owner,
+	}
+	q := `UPDATE channels SET deleted_at = NULL WHERE id = :id AND owner = :owner AND deleted_at IS NOT NULL;`
+
+	res, err := cr.db.NamedExecContext(ctx, q, dbch)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return errors.Wrap(errors.ErrNotFound, err)
+		}
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	if cnt == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (cr channelRepository) Purge(ctx context.Context, before time.Time) ([]string, error) {
+	q := `DELETE FROM channels WHERE deleted_at < :before RETURNING id;`
+	return purge(ctx, cr.db, q, before)
+}
+
 func (cr channelRepository) Connect(ctx context.Context, owner string, chIDs, thIDs []string) error {
 	tx, err := cr.db.BeginTxx(ctx, nil)
 	if err != nil {
@@ -295,7 +353,9 @@ func (cr channelRepository) Connect(ctx context.Context, owner string, chIDs, th
 	}
 
 	q := `INSERT INTO connections (channel_id, channel_owner, thing_id, thing_owner)
-	      VALUES (:channel, :owner, :thing, :owner);`
+	      SELECT :channel, :owner, :thing, :owner
+	      WHERE NOT EXISTS (SELECT 1 FROM channels WHERE id = :channel AND deleted_at IS NOT NULL)
+	      AND NOT EXISTS (SELECT 1 FROM things WHERE id = :thing AND deleted_at IS NOT NULL);`
 
 	for _, chID := range chIDs {
 		for _, thID := range thIDs {
@@ -305,7 +365,7 @@ func (cr channelRepository) Connect(ctx context.Context, owner string, chIDs, th
 				Owner:   owner,
 			}
 
-			_, err := tx.NamedExecContext(ctx, q, dbco)
+			res, err := tx.NamedExecContext(ctx, q, dbco)
 			if err != nil {
 				tx.Rollback()
 				pqErr, ok := err.(*pq.Error)
@@ -320,6 +380,18 @@ func (cr channelRepository) Connect(ctx context.Context, owner string, chIDs, th
 
 				return errors.Wrap(things.ErrConnect, err)
 			}
+
+			// The removed channel or thing can't be connected.
+			cnt, err := res.RowsAffected()
+			if err != nil {
+				tx.Rollback()
+				return errors.Wrap(things.ErrConnect, err)
+			}
+
+			if cnt == 0 {
+				tx.Rollback()
+				return errors.ErrNotFound
+			}
 		}
 	}
 
@@ -383,7 +455,11 @@ func (cr channelRepository) Disconnect(ctx context.Context, owner string, chIDs,
 
 func (cr channelRepository) HasThing(ctx context.Context, chanID, thingKey string) (string, error) {
 	var thingID string
-	q := `SELECT id FROM things WHERE key = $1`
+	q := `SELECT id FROM things WHERE key = $1 AND deleted_at IS NULL
+	      UNION ALL
+	      SELECT k.thing_id FROM thing_keys k
+	      INNER JOIN things th ON th.id = k.thing_id
+	      WHERE k.key = $1 AND (k.expires_at IS NULL OR k.expires_at > NOW()) AND th.deleted_at IS NULL`
 	if err := cr.db.QueryRowxContext(ctx, q, thingKey).Scan(&thingID); err != nil {
 		return "", errors.Wrap(errors.ErrViewEntity, err)
 	}
@@ -400,7 +476,11 @@ func (cr channelRepository) HasThingByID(ctx context.Context, chanID, thingID st
 }
 
 func (cr channelRepository) hasThing(ctx context.Context, chanID, thingID string) error {
-	q := `SELECT EXISTS (SELECT 1 FROM connections WHERE channel_id = $1 AND thing_id = $2);`
+	q := `SELECT EXISTS (SELECT 1 FROM connections conn
+	      INNER JOIN channels ch ON ch.id = conn.channel_id AND ch.owner = conn.channel_owner
+	      INNER JOIN things th ON th.id = conn.thing_id
+	      WHERE conn.channel_id = $1 AND conn.thing_id = $2
+	      AND ch.deleted_at IS NULL AND th.deleted_at IS NULL);`
 	exists := false
 	if err := cr.db.QueryRowxContext(ctx, q, chanID, thingID).Scan(&exists); err != nil {
 		return errors.Wrap(errors.ErrViewEntity, err)
@@ -452,10 +532,11 @@ func (m dbMetadata) Value() (driver.Value, error) {
 }
 
 type dbChannel struct {
-	ID       string     `db:"id"`
-	Owner    string     `db:"owner"`
-	Name     string     `db:"name"`
-	Metadata dbMetadata `db:"metadata"`
+	ID        string       `db:"id"`
+	Owner     string       `db:"owner"`
+	Name      string       `db:"name"`
+	Metadata  dbMetadata   `db:"metadata"`
+	DeletedAt sql.NullTime `db:"deleted_at"`
 }
 
 func toDBChannel(ch things.Channel) dbChannel {
@@ -469,10 +550,11 @@ func toDBChannel(ch things.Channel) dbChannel {
 
 func toChannel(ch dbChannel) things.Channel {
 	return things.Channel{
-		ID:       ch.ID,
-		Owner:    ch.Owner,
-		Name:     ch.Name,
-		Metadata: ch.Metadata,
+		ID:        ch.ID,
+		Owner:     ch.Owner,
+		Name:      ch.Name,
+		Metadata:  ch.Metadata,
+		DeletedAt: ch.DeletedAt.Time,
 	}
 }
 
@@ -541,3 +623,23 @@ func total(ctx context.Context, db Database, query string, params interface{}) (
 	}
 	return total, nil
 }
+
+// purge executes the purge query and returns the IDs of the deleted rows.
+func purge(ctx context.Context, db Database, query string, before time.Time) ([]string, error) {
+	rows, err := db.NamedQueryContext(ctx, query, map[string]interface{}{"before": before})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(errors.ErrRemoveEntity, err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -199,6 +200,66 @@ func TestSingleChannelRetrieval(t *testing.T) {
 	}
 }
 
+func TestChannelsOwnership(t *testing.T) {
+	email := "channels-ownership@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	chanRepo := postgres.NewChannelRepository(dbMiddleware)
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		chID, err := idProvider.ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		_, err = chanRepo.Save(context.Background(), things.Channel{ID: chID, Owner: email})
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		ids = append(ids, chID)
+	}
+
+	otherID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = chanRepo.Save(context.Background(), things.Channel{ID: otherID, Owner: "other-" + email})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	nonexistentChanID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := map[string]struct {
+		owner string
+		ids   []string
+		err   error
+	}{
+		"check ownership of owned channels": {
+			owner: email,
+			ids:   ids,
+			err:   nil,
+		},
+		"check ownership of duplicated owned channels": {
+			owner: email,
+			ids:   append([]string{ids[0]}, ids...),
+			err:   nil,
+		},
+		"check ownership of channel owned by other user": {
+			owner: email,
+			ids:   append([]string{otherID}, ids...),
+			err:   errors.ErrNotFound,
+		},
+		"check ownership of non-existing channel": {
+			owner: email,
+			ids:   append([]string{nonexistentChanID}, ids...),
+			err:   errors.ErrNotFound,
+		},
+		"check ownership of channel with malformed ID": {
+			owner: email,
+			ids:   []string{wrongValue},
+			err:   errors.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		err := chanRepo.IsOwner(context.Background(), tc.owner, tc.ids)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
 func TestMultiChannelRetrieval(t *testing.T) {
 	dbMiddleware := postgres.NewDatabase(db)
 	chanRepo := postgres.NewChannelRepository(dbMiddleware)
@@ -550,6 +611,74 @@ func TestChannelRemoval(t *testing.T) {
 	}
 }
 
+func TestChannelRestore(t *testing.T) {
+	email := "channel-restore@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	chanRepo := postgres.NewChannelRepository(dbMiddleware)
+
+	chID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = chanRepo.Save(context.Background(), things.Channel{ID: chID, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = chanRepo.Remove(context.Background(), email, chID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "restore removed channel of other owner",
+			owner: wrongValue,
+			id:    chID,
+			err:   errors.ErrNotFound,
+		},
+		{
+			desc:  "restore removed channel",
+			owner: email,
+			id:    chID,
+			err:   nil,
+		},
+		{
+			desc:  "restore restored channel",
+			owner: email,
+			id:    chID,
+			err:   errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := chanRepo.Restore(context.Background(), tc.owner, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = chanRepo.RetrieveByID(context.Background(), email, chID)
+	assert.Nil(t, err, fmt.Sprintf("retrieve restored channel: got unexpected error: %s", err))
+}
+
+func TestChannelPurge(t *testing.T) {
+	email := "channel-purge@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	chanRepo := postgres.NewChannelRepository(dbMiddleware)
+
+	chID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = chanRepo.Save(context.Background(), things.Channel{ID: chID, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = chanRepo.Remove(context.Background(), email, chID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	ids, err := chanRepo.Purge(context.Background(), time.Now().Add(-time.Hour))
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.NotContains(t, ids, chID, "expected channel removed after the given time not to be purged")
+
+	ids, err = chanRepo.Purge(context.Background(), time.Now().Add(time.Hour))
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Contains(t, ids, chID, "expected channel removed before the given time to be purged")
+}
+
 func TestConnect(t *testing.T) {
 	email := "channel-connect@example.com"
 	dbMiddleware := postgres.NewDatabase(db)
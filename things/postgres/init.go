@@ -99,6 +99,83 @@ func migrateDB(db *sqlx.DB) error {
 					`ALTER TABLE IF EXISTS things ADD CONSTRAINT things_id_key UNIQUE (id)`,
 				},
 			},
+			{
+				Id: "things_5",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS quotas (
+						owner        VARCHAR(254) PRIMARY KEY,
+						messages     BIGINT NOT NULL DEFAULT 0 CHECK (messages >= 0),
+						payload_size BIGINT NOT NULL DEFAULT 0 CHECK (payload_size >= 0)
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE quotas",
+				},
+			},
+			{
+				Id: "things_6",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS thing_status (
+						thing_id   UUID PRIMARY KEY REFERENCES things (id) ON DELETE CASCADE,
+						online     BOOLEAN NOT NULL DEFAULT FALSE,
+						last_seen  TIMESTAMPTZ NOT NULL,
+						protocol   VARCHAR(32) NOT NULL DEFAULT '',
+						client_ip  VARCHAR(64) NOT NULL DEFAULT '',
+						expires_at TIMESTAMPTZ
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE thing_status",
+				},
+			},
+			{
+				Id: "things_7",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS thing_keys (
+						key        VARCHAR(4096) PRIMARY KEY,
+						thing_id   UUID NOT NULL REFERENCES things (id) ON DELETE CASCADE,
+						label      VARCHAR(1024) NOT NULL DEFAULT '',
+						expires_at TIMESTAMPTZ
+					)`,
+					`CREATE INDEX IF NOT EXISTS thing_keys_thing_id_idx ON thing_keys (thing_id)`,
+				},
+				Down: []string{
+					"DROP TABLE thing_keys",
+				},
+			},
+			{
+				Id: "things_8",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS profiles (
+						id          UUID PRIMARY KEY,
+						owner       VARCHAR(254) NOT NULL,
+						name        VARCHAR(1024),
+						metadata    JSONB NOT NULL DEFAULT '{}',
+						channels    JSONB NOT NULL DEFAULT '[]',
+						connections UUID[] NOT NULL DEFAULT '{}'
+					)`,
+					`CREATE INDEX IF NOT EXISTS profiles_owner_idx ON profiles (owner)`,
+					`ALTER TABLE IF EXISTS things ADD COLUMN IF NOT EXISTS
+					 profile_id UUID REFERENCES profiles (id) ON DELETE SET NULL`,
+				},
+				Down: []string{
+					"ALTER TABLE things DROP COLUMN profile_id",
+					"DROP TABLE profiles",
+				},
+			},
+			{
+				Id: "things_9",
+				Up: []string{
+					`ALTER TABLE IF EXISTS things ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ`,
+					`ALTER TABLE IF EXISTS channels ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ`,
+					`CREATE INDEX IF NOT EXISTS things_deleted_at_idx ON things (deleted_at) WHERE deleted_at IS NOT NULL`,
+					`CREATE INDEX IF NOT EXISTS channels_deleted_at_idx ON channels (deleted_at) WHERE deleted_at IS NOT NULL`,
+				},
+				Down: []string{
+					"ALTER TABLE channels DROP COLUMN deleted_at",
+					"ALTER TABLE things DROP COLUMN deleted_at",
+				},
+			},
 		},
 	}
 
@@ -0,0 +1,210 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/things"
+)
+
+func (tr thingRepository) RotateKey(ctx context.Context, id, key string, expiresAt time.Time) error {
+	tx, err := tr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	var old string
+	q := `SELECT key FROM things WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;`
+	if err := tx.QueryRowxContext(ctx, q, id).Scan(&old); err != nil {
+		tx.Rollback()
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return errors.Wrap(errors.ErrNotFound, err)
+		}
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	if key == old {
+		tx.Rollback()
+		return errors.ErrConflict
+	}
+
+	if err := checkKey(ctx, tx, id, key); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	q = `UPDATE things SET key = $1 WHERE id = $2;`
+	if _, err := tx.ExecContext(ctx, q, key, id); err != nil {
+		tx.Rollback()
+		return keyError(errors.ErrUpdateEntity, err)
+	}
+
+	if !expiresAt.IsZero() {
+		dbk := toDBKey(things.Key{Value: old, ThingID: id, Label: things.RotatedKeyLabel, ExpiresAt: expiresAt})
+		if _, err := tx.NamedExecContext(ctx, insertKeyQuery, dbk); err != nil {
+			tx.Rollback()
+			return keyError(errors.ErrUpdateEntity, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	return nil
+}
+
+func (tr thingRepository) SaveKey(ctx context.Context, key things.Key) error {
+	tx, err := tr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	if err := checkKey(ctx, tx, key.ThingID, key.Value); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := tx.NamedExecContext(ctx, insertKeyQuery, toDBKey(key)); err != nil {
+		tx.Rollback()
+		return keyError(errors.ErrCreateEntity, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (tr thingRepository) RetrieveKeys(ctx context.Context, id string) ([]things.Key, error) {
+	q := `SELECT key, thing_id, label, expires_at FROM thing_keys
+	      WHERE thing_id = :thing_id AND (expires_at IS NULL OR expires_at > NOW())
+	      ORDER BY label, key;`
+
+	rows, err := tr.db.NamedQueryContext(ctx, q, dbKey{ThingID: id})
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return nil, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return nil, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	var keys []things.Key
+	for rows.Next() {
+		var dbk dbKey
+		if err := rows.StructScan(&dbk); err != nil {
+			return nil, errors.Wrap(errors.ErrViewEntity, err)
+		}
+		keys = append(keys, toKey(dbk))
+	}
+
+	return keys, nil
+}
+
+func (tr thingRepository) RemoveKey(ctx context.Context, id, key string) error {
+	q := `DELETE FROM thing_keys WHERE thing_id = :thing_id AND key = :key;`
+
+	res, err := tr.db.NamedExecContext(ctx, q, dbKey{Key: key, ThingID: id})
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return errors.Wrap(errors.ErrNotFound, err)
+		}
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+
+	if cnt == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+const insertKeyQuery = `INSERT INTO thing_keys (key, thing_id, label, expires_at)
+	VALUES (:key, :thing_id, :label, :expires_at);`
+
+// checkKey verifies that the key isn't held by any other thing, nor by the
+// given thing as its additional key, since the primary and the additional
+// keys share the same namespace. It has to be called by every transaction
+// that stores the key, which keeps the key locked until it's committed. The
+// expired keys of the thing and the expired keys with the same value are
+// removed along the way, so the expired keys can be reused.
+func checkKey(ctx context.Context, tx *sqlx.Tx, thingID, key string) error {
+	q := `SELECT pg_advisory_xact_lock(hashtext($1));`
+	if _, err := tx.ExecContext(ctx, q, key); err != nil {
+		return errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	q = `DELETE FROM thing_keys WHERE (thing_id = $1 OR key = $2) AND expires_at <= NOW();`
+	if _, err := tx.ExecContext(ctx, q, thingID, key); err != nil {
+		return keyError(errors.ErrUpdateEntity, err)
+	}
+
+	q = `SELECT EXISTS (SELECT 1 FROM things WHERE key = $2 AND id <> $1) OR EXISTS (SELECT 1 FROM thing_keys WHERE key = $2);`
+	exists := false
+	if err := tx.QueryRowxContext(ctx, q, thingID, key).Scan(&exists); err != nil {
+		return errors.Wrap(errors.ErrViewEntity, err)
+	}
+	if exists {
+		return errors.ErrConflict
+	}
+
+	return nil
+}
+
+func keyError(wrapper, err error) error {
+	pqErr, ok := err.(*pq.Error)
+	if ok {
+		switch pqErr.Code.Name() {
+		case errInvalid, errFK:
+			return errors.Wrap(errors.ErrNotFound, err)
+		case errTruncation:
+			return errors.Wrap(errors.ErrMalformedEntity, err)
+		case errDuplicate:
+			return errors.Wrap(errors.ErrConflict, err)
+		}
+	}
+
+	return errors.Wrap(wrapper, err)
+}
+
+type dbKey struct {
+	Key       string       `db:"key"`
+	ThingID   string       `db:"thing_id"`
+	Label     string       `db:"label"`
+	ExpiresAt sql.NullTime `db:"expires_at"`
+}
+
+func toDBKey(k things.Key) dbKey {
+	return dbKey{
+		Key:       k.Value,
+		ThingID:   k.ThingID,
+		Label:     k.Label,
+		ExpiresAt: sql.NullTime{Time: k.ExpiresAt, Valid: !k.ExpiresAt.IsZero()},
+	}
+}
+
+func toKey(dbk dbKey) things.Key {
+	return things.Key{
+		Value:     dbk.Key,
+		ThingID:   dbk.ThingID,
+		Label:     dbk.Label,
+		ExpiresAt: dbk.ExpiresAt.Time,
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThingRotateKey(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+
+	ths := createThings(t, thingRepo, "thing-rotate-key@example.com", 2)
+	th, th2 := ths[0], ths[1]
+	nonexistentID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc      string
+		id        string
+		key       string
+		expiresAt time.Time
+		old       string
+		oldActive bool
+		err       error
+	}{
+		{
+			desc:      "rotate key of existing thing with grace period",
+			id:        th.ID,
+			key:       "rotated-key",
+			expiresAt: time.Now().Add(time.Hour),
+			old:       th.Key,
+			oldActive: true,
+			err:       nil,
+		},
+		{
+			desc:      "rotate key of existing thing without grace period",
+			id:        th2.ID,
+			key:       "revoking-key",
+			old:       th2.Key,
+			oldActive: false,
+			err:       nil,
+		},
+		{
+			desc: "rotate key to the key of other thing",
+			id:   th2.ID,
+			key:  "rotated-key",
+			err:  errors.ErrConflict,
+		},
+		{
+			desc: "rotate key to the key in grace period",
+			id:   th2.ID,
+			key:  th.Key,
+			err:  errors.ErrConflict,
+		},
+		{
+			desc: "rotate key of non-existing thing",
+			id:   nonexistentID,
+			key:  "other-key",
+			err:  errors.ErrNotFound,
+		},
+		{
+			desc: "rotate key of thing with invalid ID",
+			id:   "invalid",
+			key:  "other-key",
+			err:  errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := thingRepo.RotateKey(context.Background(), tc.id, tc.key, tc.expiresAt)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		k, err := thingRepo.RetrieveByKey(context.Background(), tc.key)
+		assert.Nil(t, err, fmt.Sprintf("%s: retrieve by new key: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.id, k.ThingID, fmt.Sprintf("%s: retrieve by new key: expected %s got %s\n", tc.desc, tc.id, k.ThingID))
+
+		k, err = thingRepo.RetrieveByKey(context.Background(), tc.old)
+		assert.Equal(t, tc.oldActive, err == nil, fmt.Sprintf("%s: retrieve by old key: expected active %t got error %s\n", tc.desc, tc.oldActive, err))
+		if tc.oldActive {
+			assert.Equal(t, things.RotatedKeyLabel, k.Label, fmt.Sprintf("%s: expected label %s got %s\n", tc.desc, things.RotatedKeyLabel, k.Label))
+		}
+	}
+}
+
+func TestThingSaveKey(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+
+	th := createThings(t, thingRepo, "thing-save-key@example.com", 1)[0]
+	nonexistentID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		key  things.Key
+		err  error
+	}{
+		{
+			desc: "save key of existing thing",
+			key:  things.Key{Value: "saved-key", ThingID: th.ID, Label: "backup"},
+			err:  nil,
+		},
+		{
+			desc: "save expiring key of existing thing",
+			key:  things.Key{Value: "expiring-key", ThingID: th.ID, ExpiresAt: time.Now().Add(time.Hour)},
+			err:  nil,
+		},
+		{
+			desc: "save existing additional key",
+			key:  things.Key{Value: "saved-key", ThingID: th.ID},
+			err:  errors.ErrConflict,
+		},
+		{
+			desc: "save existing primary key",
+			key:  things.Key{Value: th.Key, ThingID: th.ID},
+			err:  errors.ErrConflict,
+		},
+		{
+			desc: "save key of non-existing thing",
+			key:  things.Key{Value: "other-key", ThingID: nonexistentID},
+			err:  errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := thingRepo.SaveKey(context.Background(), tc.key)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestThingRetrieveKeys(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+
+	ths := createThings(t, thingRepo, "thing-retrieve-keys@example.com", 2)
+	th, th2 := ths[0], ths[1]
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Millisecond)
+	keys := []things.Key{
+		{Value: "retrieved-key-a", ThingID: th.ID, Label: "a"},
+		{Value: "retrieved-key-b", ThingID: th.ID, Label: "b", ExpiresAt: expiresAt},
+	}
+	for _, k := range keys {
+		err := thingRepo.SaveKey(context.Background(), k)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+	err := thingRepo.SaveKey(context.Background(), things.Key{Value: "expired-key", ThingID: th.ID, ExpiresAt: time.Now().Add(-time.Hour)})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		id   string
+		keys []things.Key
+	}{
+		{
+			desc: "retrieve active keys of thing",
+			id:   th.ID,
+			keys: keys,
+		},
+		{
+			desc: "retrieve keys of thing without additional keys",
+			id:   th2.ID,
+			keys: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		keys, err := thingRepo.RetrieveKeys(context.Background(), tc.id)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		for i := range keys {
+			if !keys[i].ExpiresAt.IsZero() {
+				keys[i].ExpiresAt = keys[i].ExpiresAt.UTC()
+			}
+		}
+		assert.Equal(t, tc.keys, keys, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.keys, keys))
+	}
+}
+
+func TestThingRemoveKey(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+
+	ths := createThings(t, thingRepo, "thing-remove-key@example.com", 2)
+	th, th2 := ths[0], ths[1]
+	key := things.Key{Value: "removed-key", ThingID: th.ID}
+	err := thingRepo.SaveKey(context.Background(), key)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		id   string
+		key  string
+		err  error
+	}{
+		{
+			desc: "remove key of other thing",
+			id:   th2.ID,
+			key:  key.Value,
+			err:  errors.ErrNotFound,
+		},
+		{
+			desc: "remove key of existing thing",
+			id:   th.ID,
+			key:  key.Value,
+			err:  nil,
+		},
+		{
+			desc: "remove removed key",
+			id:   th.ID,
+			key:  key.Value,
+			err:  errors.ErrNotFound,
+		},
+		{
+			desc: "remove primary key",
+			id:   th.ID,
+			key:  th.Key,
+			err:  errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := thingRepo.RemoveKey(context.Background(), tc.id, tc.key)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestThingKeyNamespace(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+
+	ths := createThings(t, thingRepo, "thing-key-namespace@example.com", 2)
+	th, th2 := ths[0], ths[1]
+
+	err := thingRepo.SaveKey(context.Background(), things.Key{Value: "namespace-active-key", ThingID: th.ID})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = thingRepo.SaveKey(context.Background(), things.Key{Value: "namespace-expired-key", ThingID: th.ID, ExpiresAt: time.Now().Add(-time.Hour)})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	id, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = thingRepo.Save(context.Background(), things.Thing{ID: id, Owner: th.Owner, Key: "namespace-active-key"})
+	assert.True(t, errors.Contains(err, errors.ErrConflict), fmt.Sprintf("save thing with another thing's additional key: expected %s got %s\n", errors.ErrConflict, err))
+
+	err = thingRepo.UpdateKey(context.Background(), th2.Owner, th2.ID, "namespace-active-key")
+	assert.True(t, errors.Contains(err, errors.ErrConflict), fmt.Sprintf("update key to another thing's additional key: expected %s got %s\n", errors.ErrConflict, err))
+
+	err = thingRepo.SaveKey(context.Background(), things.Key{Value: "namespace-expired-key", ThingID: th2.ID})
+	assert.Nil(t, err, fmt.Sprintf("save another thing's expired key: unexpected error %s\n", err))
+}
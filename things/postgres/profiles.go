@@ -0,0 +1,255 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.ProfileRepository = (*profileRepository)(nil)
+
+type profileRepository struct {
+	db Database
+}
+
+// NewProfileRepository instantiates a PostgreSQL implementation of profile
+// repository.
+func NewProfileRepository(db Database) things.ProfileRepository {
+	return &profileRepository{
+		db: db,
+	}
+}
+
+func (pr profileRepository) Save(ctx context.Context, p things.Profile) error {
+	q := `INSERT INTO profiles (id, owner, name, metadata, channels, connections)
+		  VALUES (:id, :owner, :name, :metadata, :channels, :connections);`
+
+	dbp, err := toDBProfile(p)
+	if err != nil {
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	if _, err := pr.db.NamedExecContext(ctx, q, dbp); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return errors.Wrap(errors.ErrMalformedEntity, err)
+			case errDuplicate:
+				return errors.Wrap(errors.ErrConflict, err)
+			}
+		}
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (pr profileRepository) Update(ctx context.Context, p things.Profile) error {
+	q := `UPDATE profiles SET name = :name, metadata = :metadata, channels = :channels, connections = :connections
+		  WHERE owner = :owner AND id = :id;`
+
+	dbp, err := toDBProfile(p)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	res, err := pr.db.NamedExecContext(ctx, q, dbp)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return errors.Wrap(errors.ErrMalformedEntity, err)
+			}
+		}
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	if cnt == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (pr profileRepository) RetrieveByID(ctx context.Context, owner, id string) (things.Profile, error) {
+	q := `SELECT id, owner, name, metadata, channels, connections FROM profiles WHERE owner = $1 AND id = $2;`
+
+	var dbp dbProfile
+	if err := pr.db.QueryRowxContext(ctx, q, owner, id).StructScan(&dbp); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return things.Profile{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return things.Profile{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	return toProfile(dbp)
+}
+
+func (pr profileRepository) RetrieveAll(ctx context.Context, owner string, pm things.PageMetadata) (things.ProfilesPage, error) {
+	nq, name := getNameQuery(pm.Name)
+	oq := getOrderQuery(pm.Order)
+	dq := getDirQuery(pm.Dir)
+
+	query := []string{"owner = :owner"}
+	if nq != "" {
+		query = append(query, nq)
+	}
+	whereClause := fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+
+	q := fmt.Sprintf(`SELECT id, owner, name, metadata, channels, connections FROM profiles
+		%s ORDER BY %s %s LIMIT :limit OFFSET :offset;`, whereClause, oq, dq)
+
+	params := map[string]interface{}{
+		"owner":  owner,
+		"limit":  pm.Limit,
+		"offset": pm.Offset,
+		"name":   name,
+	}
+	rows, err := pr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return things.ProfilesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer rows.Close()
+
+	items := []things.Profile{}
+	for rows.Next() {
+		var dbp dbProfile
+		if err := rows.StructScan(&dbp); err != nil {
+			return things.ProfilesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+
+		p, err := toProfile(dbp)
+		if err != nil {
+			return things.ProfilesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+		}
+
+		items = append(items, p)
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM profiles %s;`, whereClause)
+
+	total, err := total(ctx, pr.db, cq, params)
+	if err != nil {
+		return things.ProfilesPage{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	page := things.ProfilesPage{
+		Profiles: items,
+		PageMetadata: things.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+			Order:  pm.Order,
+			Dir:    pm.Dir,
+		},
+	}
+
+	return page, nil
+}
+
+func (pr profileRepository) Remove(ctx context.Context, owner, id string) error {
+	q := `DELETE FROM profiles WHERE owner = :owner AND id = :id;`
+
+	dbp := dbProfile{
+		ID:    id,
+		Owner: owner,
+	}
+	if _, err := pr.db.NamedExecContext(ctx, q, dbp); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return nil
+		}
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
+type dbProfile struct {
+	ID          string         `db:"id"`
+	Owner       string         `db:"owner"`
+	Name        string         `db:"name"`
+	Metadata    []byte         `db:"metadata"`
+	Channels    []byte         `db:"channels"`
+	Connections pq.StringArray `db:"connections"`
+}
+
+func toDBProfile(p things.Profile) (dbProfile, error) {
+	md := []byte("{}")
+	if len(p.Metadata) > 0 {
+		b, err := json.Marshal(p.Metadata)
+		if err != nil {
+			return dbProfile{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		md = b
+	}
+
+	chs := []byte("[]")
+	if len(p.Channels) > 0 {
+		b, err := json.Marshal(p.Channels)
+		if err != nil {
+			return dbProfile{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		chs = b
+	}
+
+	conns := pq.StringArray{}
+	if len(p.Connections) > 0 {
+		conns = pq.StringArray(p.Connections)
+	}
+
+	return dbProfile{
+		ID:          p.ID,
+		Owner:       p.Owner,
+		Name:        p.Name,
+		Metadata:    md,
+		Channels:    chs,
+		Connections: conns,
+	}, nil
+}
+
+func toProfile(dbp dbProfile) (things.Profile, error) {
+	var md things.Metadata
+	if err := json.Unmarshal(dbp.Metadata, &md); err != nil {
+		return things.Profile{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	var chs []things.ProfileChannel
+	if err := json.Unmarshal(dbp.Channels, &chs); err != nil {
+		return things.Profile{}, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+	if len(chs) == 0 {
+		chs = nil
+	}
+
+	var conns []string
+	if len(dbp.Connections) > 0 {
+		conns = []string(dbp.Connections)
+	}
+
+	return things.Profile{
+		ID:          dbp.ID,
+		Owner:       dbp.Owner,
+		Name:        dbp.Name,
+		Metadata:    md,
+		Channels:    chs,
+		Connections: conns,
+	}, nil
+}
@@ -0,0 +1,256 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createProfile(t *testing.T, repo things.ProfileRepository, owner, name string) things.Profile {
+	id, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	p := things.Profile{
+		ID:       id,
+		Owner:    owner,
+		Name:     name,
+		Metadata: things.Metadata{"model": "t1"},
+		Channels: []things.ProfileChannel{
+			{
+				Name:      "telemetry",
+				Subtopics: []string{"temperature"},
+				Schema:    map[string]interface{}{"json": map[string]interface{}{"type": "object"}},
+			},
+		},
+	}
+	err = repo.Save(context.Background(), p)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	return p
+}
+
+func TestProfileSave(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	profileRepo := postgres.NewProfileRepository(dbMiddleware)
+
+	email := "profile-save@example.com"
+	id, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		profile things.Profile
+		err     error
+	}{
+		{
+			desc:    "save new profile",
+			profile: things.Profile{ID: id, Owner: email, Name: "profile"},
+			err:     nil,
+		},
+		{
+			desc:    "save existing profile",
+			profile: things.Profile{ID: id, Owner: email, Name: "profile"},
+			err:     errors.ErrConflict,
+		},
+		{
+			desc:    "save profile with invalid ID",
+			profile: things.Profile{ID: "invalid", Owner: email},
+			err:     errors.ErrMalformedEntity,
+		},
+		{
+			desc:    "save profile with invalid name",
+			profile: things.Profile{ID: id, Owner: email, Name: strings.Repeat("m", 1025)},
+			err:     errors.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := profileRepo.Save(context.Background(), tc.profile)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestProfileUpdate(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	profileRepo := postgres.NewProfileRepository(dbMiddleware)
+
+	email := "profile-update@example.com"
+	p := createProfile(t, profileRepo, email, "profile")
+	nonexistentID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	updated := p
+	updated.Name = "updated"
+	updated.Channels = nil
+
+	cases := []struct {
+		desc    string
+		profile things.Profile
+		err     error
+	}{
+		{
+			desc:    "update existing profile",
+			profile: updated,
+			err:     nil,
+		},
+		{
+			desc:    "update profile of other owner",
+			profile: things.Profile{ID: p.ID, Owner: "other@example.com"},
+			err:     errors.ErrNotFound,
+		},
+		{
+			desc:    "update non-existing profile",
+			profile: things.Profile{ID: nonexistentID, Owner: email},
+			err:     errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := profileRepo.Update(context.Background(), tc.profile)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	saved, err := profileRepo.RetrieveByID(context.Background(), email, p.ID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, updated, saved, fmt.Sprintf("update profile: expected %v got %v\n", updated, saved))
+}
+
+func TestProfileRetrieveByID(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	profileRepo := postgres.NewProfileRepository(dbMiddleware)
+
+	email := "profile-retrieve@example.com"
+	p := createProfile(t, profileRepo, email, "profile")
+	nonexistentID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		owner   string
+		id      string
+		profile things.Profile
+		err     error
+	}{
+		{
+			desc:    "retrieve existing profile",
+			owner:   email,
+			id:      p.ID,
+			profile: p,
+			err:     nil,
+		},
+		{
+			desc:    "retrieve profile of other owner",
+			owner:   "other@example.com",
+			id:      p.ID,
+			profile: things.Profile{},
+			err:     errors.ErrNotFound,
+		},
+		{
+			desc:    "retrieve non-existing profile",
+			owner:   email,
+			id:      nonexistentID,
+			profile: things.Profile{},
+			err:     errors.ErrNotFound,
+		},
+		{
+			desc:    "retrieve profile with invalid ID",
+			owner:   email,
+			id:      "invalid",
+			profile: things.Profile{},
+			err:     errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		profile, err := profileRepo.RetrieveByID(context.Background(), tc.owner, tc.id)
+		assert.Equal(t, tc.profile, profile, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.profile, profile))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestProfileRetrieveAll(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	profileRepo := postgres.NewProfileRepository(dbMiddleware)
+
+	email := "profile-retrieve-all@example.com"
+	n := uint64(10)
+	for i := uint64(0); i < n; i++ {
+		createProfile(t, profileRepo, email, fmt.Sprintf("profile-%d", i))
+	}
+
+	cases := []struct {
+		desc  string
+		owner string
+		pm    things.PageMetadata
+		size  uint64
+	}{
+		{
+			desc:  "retrieve all profiles",
+			owner: email,
+			pm:    things.PageMetadata{Offset: 0, Limit: n},
+			size:  n,
+		},
+		{
+			desc:  "retrieve subset of profiles",
+			owner: email,
+			pm:    things.PageMetadata{Offset: n / 2, Limit: n},
+			size:  n / 2,
+		},
+		{
+			desc:  "retrieve profiles filtered by name",
+			owner: email,
+			pm:    things.PageMetadata{Offset: 0, Limit: n, Name: "profile-1"},
+			size:  1,
+		},
+		{
+			desc:  "retrieve profiles of owner without profiles",
+			owner: "other@example.com",
+			pm:    things.PageMetadata{Offset: 0, Limit: n},
+			size:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := profileRepo.RetrieveAll(context.Background(), tc.owner, tc.pm)
+		size := uint64(len(page.Profiles))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected size %d got %d\n", tc.desc, tc.size, size))
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %d\n", tc.desc, err))
+	}
+}
+
+func TestProfileRemove(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	profileRepo := postgres.NewProfileRepository(dbMiddleware)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+
+	email := "profile-remove@example.com"
+	p := createProfile(t, profileRepo, email, "profile")
+
+	thID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thKey, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	_, err = thingRepo.Save(context.Background(), things.Thing{ID: thID, Owner: email, Key: thKey, ProfileID: p.ID})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	for i := 0; i < 2; i++ {
+		err := profileRepo.Remove(context.Background(), email, p.ID)
+		assert.Nil(t, err, fmt.Sprintf("#%d: failed to remove profile due to: %s", i, err))
+
+		_, err = profileRepo.RetrieveByID(context.Background(), email, p.ID)
+		assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("#%d: expected %s got %s", i, errors.ErrNotFound, err))
+	}
+
+	th, err := thingRepo.RetrieveByID(context.Background(), email, thID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Empty(t, th.ProfileID, "expected thing to be detached from the removed profile")
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.QuotaRepository = (*quotaRepository)(nil)
+
+type quotaRepository struct {
+	db Database
+}
+
+// NewQuotaRepository instantiates a PostgreSQL implementation of owner quota
+// repository.
+func NewQuotaRepository(db Database) things.QuotaRepository {
+	return &quotaRepository{
+		db: db,
+	}
+}
+
+func (qr quotaRepository) Save(ctx context.Context, owner string, q things.Quota) error {
+	query := `INSERT INTO quotas (owner, messages, payload_size)
+		  VALUES (:owner, :messages, :payload_size)
+		  ON CONFLICT (owner) DO UPDATE SET messages = :messages, payload_size = :payload_size;`
+
+	dbq := dbQuota{
+		Owner:       owner,
+		Messages:    int64(q.Messages),
+		PayloadSize: int64(q.PayloadSize),
+	}
+	if _, err := qr.db.NamedExecContext(ctx, query, dbq); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return errors.Wrap(errors.ErrMalformedEntity, err)
+			}
+		}
+		return errors.Wrap(errors.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (qr quotaRepository) RetrieveByOwner(ctx context.Context, owner string) (things.Quota, error) {
+	query := `SELECT owner, messages, payload_size FROM quotas WHERE owner = $1;`
+
+	var dbq dbQuota
+	if err := qr.db.QueryRowxContext(ctx, query, owner).StructScan(&dbq); err != nil {
+		if err == sql.ErrNoRows {
+			return things.Quota{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return things.Quota{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	return things.Quota{
+		Messages:    uint64(dbq.Messages),
+		PayloadSize: uint64(dbq.PayloadSize),
+	}, nil
+}
+
+type dbQuota struct {
+	Owner       string `db:"owner"`
+	Messages    int64  `db:"messages"`
+	PayloadSize int64  `db:"payload_size"`
+}
@@ -0,0 +1,90 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaSave(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	quotaRepo := postgres.NewQuotaRepository(dbMiddleware)
+
+	email := "quota-save@example.com"
+
+	cases := []struct {
+		desc  string
+		owner string
+		quota things.Quota
+		err   error
+	}{
+		{
+			desc:  "save new quota",
+			owner: email,
+			quota: things.Quota{Messages: 60, PayloadSize: 1024},
+			err:   nil,
+		},
+		{
+			desc:  "replace existing quota",
+			owner: email,
+			quota: things.Quota{Messages: 120},
+			err:   nil,
+		},
+		{
+			desc:  "save quota with invalid owner",
+			owner: strings.Repeat("m", 255),
+			quota: things.Quota{Messages: 60},
+			err:   errors.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := quotaRepo.Save(context.Background(), tc.owner, tc.quota)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestQuotaRetrieveByOwner(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	quotaRepo := postgres.NewQuotaRepository(dbMiddleware)
+
+	email := "quota-retrieve@example.com"
+	quota := things.Quota{Messages: 60, PayloadSize: 1024}
+	err := quotaRepo.Save(context.Background(), email, quota)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		quota things.Quota
+		err   error
+	}{
+		{
+			desc:  "retrieve existing quota",
+			owner: email,
+			quota: quota,
+			err:   nil,
+		},
+		{
+			desc:  "retrieve non-existing quota",
+			owner: "non-existing@example.com",
+			quota: things.Quota{},
+			err:   errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		q, err := quotaRepo.RetrieveByOwner(context.Background(), tc.owner)
+		assert.Equal(t, tc.quota, q, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.quota, q))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
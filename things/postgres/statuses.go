@@ -0,0 +1,114 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.StatusRepository = (*statusRepository)(nil)
+
+type statusRepository struct {
+	db Database
+}
+
+// NewStatusRepository instantiates a PostgreSQL implementation of thing
+// status repository.
+func NewStatusRepository(db Database) things.StatusRepository {
+	return &statusRepository{
+		db: db,
+	}
+}
+
+func (sr statusRepository) Save(ctx context.Context, st things.Status) error {
+	// The events of the different adapters may arrive out of order, so
+	// the stored status is replaced only by the more recent one.
+	query := `INSERT INTO thing_status (thing_id, online, last_seen, protocol, client_ip, expires_at)
+		  VALUES (:thing_id, :online, :last_seen, :protocol, :client_ip, :expires_at)
+		  ON CONFLICT (thing_id) DO UPDATE SET online = :online, last_seen = :last_seen, protocol = :protocol,
+		  client_ip = COALESCE(NULLIF(:client_ip, ''), thing_status.client_ip), expires_at = :expires_at
+		  WHERE thing_status.last_seen <= :last_seen;`
+
+	dbs := toDBStatus(st)
+	if _, err := sr.db.NamedExecContext(ctx, query, dbs); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return errors.Wrap(errors.ErrMalformedEntity, err)
+			case errFK:
+				return errors.Wrap(errors.ErrNotFound, err)
+			}
+		}
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	return nil
+}
+
+func (sr statusRepository) RetrieveByThing(ctx context.Context, id string) (things.Status, error) {
+	query := `SELECT thing_id, online, last_seen, protocol, client_ip, expires_at FROM thing_status WHERE thing_id = $1;`
+
+	var dbs dbStatus
+	if err := sr.db.QueryRowxContext(ctx, query, id).StructScan(&dbs); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return things.Status{}, errors.Wrap(errors.ErrNotFound, err)
+		}
+		return things.Status{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+
+	return toStatus(dbs), nil
+}
+
+// getOnlineQuery returns the condition matching the things that are online,
+// or offline, depending on the given value.
+func getOnlineQuery(online *bool) string {
+	if online == nil {
+		return ""
+	}
+
+	q := `id IN (SELECT thing_id FROM thing_status WHERE online AND (expires_at IS NULL OR expires_at > NOW()))`
+	if !*online {
+		q = "NOT " + q
+	}
+	return q
+}
+
+type dbStatus struct {
+	ThingID  string       `db:"thing_id"`
+	Online   bool         `db:"online"`
+	LastSeen time.Time    `db:"last_seen"`
+	Protocol string       `db:"protocol"`
+	ClientIP string       `db:"client_ip"`
+	Expires  sql.NullTime `db:"expires_at"`
+}
+
+func toDBStatus(st things.Status) dbStatus {
+	return dbStatus{
+		ThingID:  st.ThingID,
+		Online:   st.Online,
+		LastSeen: st.LastSeen,
+		Protocol: st.Protocol,
+		ClientIP: st.ClientIP,
+		Expires:  sql.NullTime{Time: st.Expires, Valid: !st.Expires.IsZero()},
+	}
+}
+
+func toStatus(dbs dbStatus) things.Status {
+	return things.Status{
+		ThingID:  dbs.ThingID,
+		Online:   dbs.Online,
+		LastSeen: dbs.LastSeen,
+		Protocol: dbs.Protocol,
+		ClientIP: dbs.ClientIP,
+		Expires:  dbs.Expires.Time,
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createThings(t *testing.T, repo things.ThingRepository, owner string, n int) []things.Thing {
+	var ths []things.Thing
+	for i := 0; i < n; i++ {
+		id, err := idProvider.ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		key, err := idProvider.ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		ths = append(ths, things.Thing{ID: id, Owner: owner, Key: key})
+	}
+
+	ths, err := repo.Save(context.Background(), ths...)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	return ths
+}
+
+func TestStatusSave(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+	statusRepo := postgres.NewStatusRepository(dbMiddleware)
+
+	th := createThings(t, thingRepo, "status-save@example.com", 1)[0]
+	nonexistentID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Now().UTC().Truncate(time.Second)
+	cases := []struct {
+		desc   string
+		status things.Status
+		err    error
+	}{
+		{
+			desc:   "save status of existing thing",
+			status: things.Status{ThingID: th.ID, Online: true, LastSeen: now, Protocol: "mqtt", ClientIP: "10.0.0.1"},
+			err:    nil,
+		},
+		{
+			desc:   "save more recent status of existing thing",
+			status: things.Status{ThingID: th.ID, LastSeen: now.Add(time.Second), Protocol: "mqtt"},
+			err:    nil,
+		},
+		{
+			desc:   "save status of non-existing thing",
+			status: things.Status{ThingID: nonexistentID, Online: true, LastSeen: now},
+			err:    errors.ErrNotFound,
+		},
+		{
+			desc:   "save status of thing with invalid ID",
+			status: things.Status{ThingID: "invalid", Online: true, LastSeen: now},
+			err:    errors.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := statusRepo.Save(context.Background(), tc.status)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestStatusRetrieveByThing(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+	statusRepo := postgres.NewStatusRepository(dbMiddleware)
+
+	ths := createThings(t, thingRepo, "status-retrieve@example.com", 3)
+	connected, disconnected, unseen := ths[0], ths[1], ths[2]
+
+	now := time.Now().UTC().Truncate(time.Second)
+	online := things.Status{ThingID: connected.ID, Online: true, LastSeen: now, Protocol: "http", ClientIP: "10.0.0.1", Expires: now.Add(time.Minute)}
+	statuses := []things.Status{
+		online,
+		{ThingID: disconnected.ID, Online: true, LastSeen: now.Add(-time.Minute), Protocol: "mqtt", ClientIP: "10.0.0.2"},
+		{ThingID: disconnected.ID, LastSeen: now, Protocol: "mqtt"},
+		// Stale status doesn't replace the more recent one.
+		{ThingID: connected.ID, LastSeen: now.Add(-time.Minute), Protocol: "mqtt"},
+	}
+	for _, st := range statuses {
+		err := statusRepo.Save(context.Background(), st)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		id     string
+		status things.Status
+		err    error
+	}{
+		{
+			desc:   "retrieve status of online thing",
+			id:     connected.ID,
+			status: online,
+			err:    nil,
+		},
+		{
+			desc:   "retrieve status of disconnected thing keeping last client IP",
+			id:     disconnected.ID,
+			status: things.Status{ThingID: disconnected.ID, LastSeen: now, Protocol: "mqtt", ClientIP: "10.0.0.2"},
+			err:    nil,
+		},
+		{
+			desc:   "retrieve status of never seen thing",
+			id:     unseen.ID,
+			status: things.Status{},
+			err:    errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		st, err := statusRepo.RetrieveByThing(context.Background(), tc.id)
+		st.LastSeen = st.LastSeen.UTC()
+		if !st.Expires.IsZero() {
+			st.Expires = st.Expires.UTC()
+		}
+		if tc.err != nil {
+			st.LastSeen = time.Time{}
+		}
+		assert.Equal(t, tc.status, st, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.status, st))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestThingRetrievalByStatus(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+	statusRepo := postgres.NewStatusRepository(dbMiddleware)
+
+	email := "status-filter@example.com"
+	ths := createThings(t, thingRepo, email, 4)
+
+	now := time.Now()
+	statuses := []things.Status{
+		{ThingID: ths[0].ID, Online: true, LastSeen: now, Protocol: "mqtt"},
+		{ThingID: ths[1].ID, Online: true, LastSeen: now, Protocol: "http", Expires: now.Add(time.Minute)},
+		{ThingID: ths[2].ID, Online: true, LastSeen: now.Add(-time.Hour), Protocol: "coap", Expires: now.Add(-time.Minute)},
+	}
+	for _, st := range statuses {
+		err := statusRepo.Save(context.Background(), st)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	}
+
+	online, offline := true, false
+	cases := []struct {
+		desc   string
+		online *bool
+		size   uint64
+	}{
+		{
+			desc:   "retrieve all things",
+			online: nil,
+			size:   4,
+		},
+		{
+			desc:   "retrieve online things",
+			online: &online,
+			size:   2,
+		},
+		{
+			desc:   "retrieve offline things",
+			online: &offline,
+			size:   2,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := thingRepo.RetrieveAll(context.Background(), email, things.PageMetadata{Limit: 10, Online: tc.online})
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.size, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.size, page.Total))
+		assert.Equal(t, tc.size, uint64(len(page.Things)), fmt.Sprintf("%s: expected size %d got %d\n", tc.desc, tc.size, len(page.Things)))
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/lib/pq" // required for DB access
@@ -43,8 +44,8 @@ func (tr thingRepository) Save(ctx context.Context, ths ...things.Thing) ([]thin
 		return []things.Thing{}, errors.Wrap(errors.ErrCreateEntity, err)
 	}
 
-	q := `INSERT INTO things (id, owner, name, key, metadata)
-		  VALUES (:id, :owner, :name, :key, :metadata);`
+	q := `INSERT INTO things (id, owner, name, key, metadata, profile_id)
+		  VALUES (:id, :owner, :name, :key, :metadata, :profile_id);`
 
 	for _, thing := range ths {
 		dbth, err := toDBThing(thing)
@@ -57,7 +58,7 @@ func (tr thingRepository) Save(ctx context.Context, ths ...things.Thing) ([]thin
 			pqErr, ok := err.(*pq.Error)
 			if ok {
 				switch pqErr.Code.Name() {
-				case errInvalid, errTruncation:
+				case errInvalid, errTruncation, errFK:
 					return []things.Thing{}, errors.Wrap(errors.ErrMalformedEntity, err)
 				case errDuplicate:
 					return []things.Thing{}, errors.Wrap(errors.ErrConflict, err)
@@ -66,6 +67,13 @@ func (tr thingRepository) Save(ctx context.Context, ths ...things.Thing) ([]thin
 
 			return []things.Thing{}, errors.Wrap(errors.ErrCreateEntity, err)
 		}
+
+		// The key is checked once the thing is inserted, so that the
+		// malformed thing is reported as such.
+		if err := checkKey(ctx, tx, thing.ID, thing.Key); err != nil {
+			tx.Rollback()
+			return []things.Thing{}, err
+		}
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -76,7 +84,7 @@ func (tr thingRepository) Save(ctx context.Context, ths ...things.Thing) ([]thin
 }
 
 func (tr thingRepository) Update(ctx context.Context, t things.Thing) error {
-	q := `UPDATE things SET name = :name, metadata = :metadata WHERE id = :id;`
+	q := `UPDATE things SET name = :name, metadata = :metadata WHERE id = :id AND deleted_at IS NULL;`
 
 	dbth, err := toDBThing(t)
 	if err != nil {
@@ -109,7 +117,12 @@ func (tr thingRepository) Update(ctx context.Context, t things.Thing) error {
 }
 
 func (tr thingRepository) UpdateKey(ctx context.Context, owner, id, key string) error {
-	q := `UPDATE things SET key = :key WHERE owner = :owner AND id = :id;`
+	tx, err := tr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	q := `UPDATE things SET key = :key WHERE owner = :owner AND id = :id AND deleted_at IS NULL;`
 
 	dbth := dbThing{
 		ID:    id,
@@ -117,8 +130,9 @@ func (tr thingRepository) UpdateKey(ctx context.Context, owner, id, key string)
 		Key:   key,
 	}
 
-	res, err := tr.db.NamedExecContext(ctx, q, dbth)
+	res, err := tx.NamedExecContext(ctx, q, dbth)
 	if err != nil {
+		tx.Rollback()
 		pqErr, ok := err.(*pq.Error)
 		if ok {
 			switch pqErr.Code.Name() {
@@ -134,18 +148,29 @@ func (tr thingRepository) UpdateKey(ctx context.Context, owner, id, key string)
 
 	cnt, err := res.RowsAffected()
 	if err != nil {
+		tx.Rollback()
 		return errors.Wrap(errors.ErrUpdateEntity, err)
 	}
 
 	if cnt == 0 {
+		tx.Rollback()
 		return errors.ErrNotFound
 	}
 
+	if err := checkKey(ctx, tx, id, key); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
 	return nil
 }
 
 func (tr thingRepository) RetrieveByID(ctx context.Context, owner, id string) (things.Thing, error) {
-	q := `SELECT name, key, metadata FROM things WHERE id = $1;`
+	q := `SELECT owner, name, key, metadata, profile_id FROM things WHERE id = $1 AND deleted_at IS NULL;`
 
 	dbth := dbThing{ID: id}
 
@@ -160,18 +185,23 @@ func (tr thingRepository) RetrieveByID(ctx context.Context, owner, id string) (t
 	return toThing(dbth)
 }
 
-func (tr thingRepository) RetrieveByKey(ctx context.Context, key string) (string, error) {
-	q := `SELECT id FROM things WHERE key = $1;`
+func (tr thingRepository) RetrieveByKey(ctx context.Context, key string) (things.Key, error) {
+	q := `SELECT key, id AS thing_id, '' AS label, NULL::TIMESTAMPTZ AS expires_at FROM things
+	      WHERE key = $1 AND deleted_at IS NULL
+	      UNION ALL
+	      SELECT k.key, k.thing_id, k.label, k.expires_at FROM thing_keys k
+	      INNER JOIN things th ON th.id = k.thing_id
+	      WHERE k.key = $1 AND (k.expires_at IS NULL OR k.expires_at > NOW()) AND th.deleted_at IS NULL;`
 
-	var id string
-	if err := tr.db.QueryRowxContext(ctx, q, key).Scan(&id); err != nil {
+	var dbk dbKey
+	if err := tr.db.QueryRowxContext(ctx, q, key).StructScan(&dbk); err != nil {
 		if err == sql.ErrNoRows {
-			return "", errors.Wrap(errors.ErrNotFound, err)
+			return things.Key{}, errors.Wrap(errors.ErrNotFound, err)
 		}
-		return "", errors.Wrap(errors.ErrViewEntity, err)
+		return things.Key{}, errors.Wrap(errors.ErrViewEntity, err)
 	}
 
-	return id, nil
+	return toKey(dbk), nil
 }
 
 func (tr thingRepository) RetrieveByIDs(ctx context.Context, thingIDs []string, pm things.PageMetadata) (things.Page, error) {
@@ -182,14 +212,14 @@ func (tr thingRepository) RetrieveByIDs(ctx context.Context, thingIDs []string,
 	nq, name := getNameQuery(pm.Name)
 	oq := getOrderQuery(pm.Order)
 	dq := getDirQuery(pm.Dir)
-	idq := fmt.Sprintf("WHERE id IN ('%s') ", strings.Join(thingIDs, "','"))
+	idq := fmt.Sprintf("WHERE id IN ('%s') AND deleted_at IS NULL ", strings.Join(thingIDs, "','"))
 
 	m, mq, err := getMetadataQuery(pm.Metadata)
 	if err != nil {
 		return things.Page{}, errors.Wrap(errors.ErrViewEntity, err)
 	}
 
-	q := fmt.Sprintf(`SELECT id, owner, name, key, metadata FROM things
+	q := fmt.Sprintf(`SELECT id, owner, name, key, metadata, profile_id FROM things
 					   %s%s%s ORDER BY %s %s LIMIT :limit OFFSET :offset;`, idq, mq, nq, oq, dq)
 
 	params := map[string]interface{}{
@@ -253,12 +283,13 @@ func (tr thingRepository) RetrieveAll(ctx context.Context, owner string, pm thin
 	oq := getOrderQuery(pm.Order)
 	dq := getDirQuery(pm.Dir)
 	ownerQuery := getOwnerQuery(pm.FetchSharedThings)
+	sq := getOnlineQuery(pm.Online)
 	m, mq, err := getMetadataQuery(pm.Metadata)
 	if err != nil {
 		return things.Page{}, errors.Wrap(errors.ErrViewEntity, err)
 	}
 
-	var query []string
+	query := []string{getDeletedQuery(pm.Deleted)}
 	if mq != "" {
 		query = append(query, mq)
 	}
@@ -268,13 +299,13 @@ func (tr thingRepository) RetrieveAll(ctx context.Context, owner string, pm thin
 	if ownerQuery != "" {
 		query = append(query, ownerQuery)
 	}
-
-	var whereClause string
-	if len(query) > 0 {
-		whereClause = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+	if sq != "" {
+		query = append(query, sq)
 	}
 
-	q := fmt.Sprintf(`SELECT id, name, key, metadata FROM things
+	whereClause := fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
+
+	q := fmt.Sprintf(`SELECT id, name, key, metadata, profile_id, deleted_at FROM things
 	      %s ORDER BY %s %s LIMIT :limit OFFSET :offset;`, whereClause, oq, dq)
 	params := map[string]interface{}{
 		"owner":    owner,
@@ -338,9 +369,9 @@ func (tr thingRepository) RetrieveByChannel(ctx context.Context, owner, chID str
 	var q, qc string
 	switch pm.Disconnected {
 	case true:
-		q = fmt.Sprintf(`SELECT id, name, key, metadata
+		q = fmt.Sprintf(`SELECT id, name, key, metadata, profile_id
 		        FROM things th
-		        WHERE th.owner = :owner AND th.id NOT IN
+		        WHERE th.owner = :owner AND th.deleted_at IS NULL AND th.id NOT IN
 		        (SELECT id FROM things th
 		          INNER JOIN connections conn
 		          ON th.id = conn.thing_id
@@ -351,17 +382,17 @@ func (tr thingRepository) RetrieveByChannel(ctx context.Context, owner, chID str
 
 		qc = `SELECT COUNT(*)
 		        FROM things th
-		        WHERE th.owner = $1 AND th.id NOT IN
+		        WHERE th.owner = $1 AND th.deleted_at IS NULL AND th.id NOT IN
 		        (SELECT id FROM things th
 		          INNER JOIN connections conn
 		          ON th.id = conn.thing_id
 		          WHERE th.owner = $1 AND conn.channel_id = $2);`
 	default:
-		q = fmt.Sprintf(`SELECT id, name, key, metadata
+		q = fmt.Sprintf(`SELECT id, name, key, metadata, profile_id
 		        FROM things th
 		        INNER JOIN connections conn
 		        ON th.id = conn.thing_id
-		        WHERE th.owner = :owner AND conn.channel_id = :channel
+		        WHERE th.owner = :owner AND th.deleted_at IS NULL AND conn.channel_id = :channel
 		        ORDER BY %s %s
 		        LIMIT :limit
 		        OFFSET :offset;`, oq, dq)
@@ -370,7 +401,7 @@ func (tr thingRepository) RetrieveByChannel(ctx context.Context, owner, chID str
 		        FROM things th
 		        INNER JOIN connections conn
 		        ON th.id = conn.thing_id
-		        WHERE th.owner = $1 AND conn.channel_id = $2;`
+		        WHERE th.owner = $1 AND th.deleted_at IS NULL AND conn.channel_id = $2;`
 	}
 
 	params := map[string]interface{}{
@@ -421,19 +452,54 @@ func (tr thingRepository) Remove(ctx context.Context, owner, id string) error {
 		ID:    id,
 		Owner: owner,
 	}
-	q := `DELETE FROM things WHERE id = :id`
+	q := `UPDATE things SET deleted_at = NOW() WHERE id = :id AND deleted_at IS NULL;`
 	if _, err := tr.db.NamedExecContext(ctx, q, dbth); err != nil {
 		return errors.Wrap(errors.ErrRemoveEntity, err)
 	}
 	return nil
 }
 
+func (tr thingRepository) Restore(ctx context.Context, owner, id string) error {
+	dbth := dbThing{
+		ID:    id,
+		Owner: owner,
+	}
+	q := `UPDATE things SET deleted_at = NULL WHERE id = :id AND deleted_at IS NOT NULL;`
+
+	res, err := tr.db.NamedExecContext(ctx, q, dbth)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return errors.Wrap(errors.ErrNotFound, err)
+		}
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errors.ErrUpdateEntity, err)
+	}
+
+	if cnt == 0 {
+		return errors.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tr thingRepository) Purge(ctx context.Context, before time.Time) ([]string, error) {
+	q := `DELETE FROM things WHERE deleted_at < :before RETURNING id;`
+	return purge(ctx, tr.db, q, before)
+}
+
 type dbThing struct {
-	ID       string `db:"id"`
-	Owner    string `db:"owner"`
-	Name     string `db:"name"`
-	Key      string `db:"key"`
-	Metadata []byte `db:"metadata"`
+	ID        string         `db:"id"`
+	Owner     string         `db:"owner"`
+	Name      string         `db:"name"`
+	Key       string         `db:"key"`
+	Metadata  []byte         `db:"metadata"`
+	ProfileID sql.NullString `db:"profile_id"`
+	DeletedAt sql.NullTime   `db:"deleted_at"`
 }
 
 func toDBThing(th things.Thing) (dbThing, error) {
@@ -447,11 +513,12 @@ func toDBThing(th things.Thing) (dbThing, error) {
 	}
 
 	return dbThing{
-		ID:       th.ID,
-		Owner:    th.Owner,
-		Name:     th.Name,
-		Key:      th.Key,
-		Metadata: data,
+		ID:        th.ID,
+		Owner:     th.Owner,
+		Name:      th.Name,
+		Key:       th.Key,
+		Metadata:  data,
+		ProfileID: sql.NullString{String: th.ProfileID, Valid: th.ProfileID != ""},
 	}, nil
 }
 
@@ -462,10 +529,19 @@ func toThing(dbth dbThing) (things.Thing, error) {
 	}
 
 	return things.Thing{
-		ID:       dbth.ID,
-		Owner:    dbth.Owner,
-		Name:     dbth.Name,
-		Key:      dbth.Key,
-		Metadata: metadata,
+		ID:        dbth.ID,
+		Owner:     dbth.Owner,
+		Name:      dbth.Name,
+		Key:       dbth.Key,
+		Metadata:  metadata,
+		ProfileID: dbth.ProfileID.String,
+		DeletedAt: dbth.DeletedAt.Time,
 	}, nil
 }
+
+func getDeletedQuery(deleted bool) string {
+	if deleted {
+		return "deleted_at IS NOT NULL"
+	}
+	return "deleted_at IS NULL"
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/pkg/uuid"
@@ -361,8 +362,8 @@ func TestThingRetrieveByKey(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		id, err := thingRepo.RetrieveByKey(context.Background(), tc.key)
-		assert.Equal(t, tc.ID, id, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.ID, id))
+		k, err := thingRepo.RetrieveByKey(context.Background(), tc.key)
+		assert.Equal(t, tc.ID, k.ThingID, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.ID, k.ThingID))
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
@@ -740,6 +741,68 @@ func TestThingRemoval(t *testing.T) {
 	}
 }
 
+func TestThingRestore(t *testing.T) {
+	email := "thing-restore@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+
+	th := createThings(t, thingRepo, email, 1)[0]
+	err := thingRepo.Remove(context.Background(), email, th.ID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		id   string
+		err  error
+	}{
+		{
+			desc: "restore removed thing",
+			id:   th.ID,
+			err:  nil,
+		},
+		{
+			desc: "restore restored thing",
+			id:   th.ID,
+			err:  errors.ErrNotFound,
+		},
+		{
+			desc: "restore thing with invalid id",
+			id:   "invalid",
+			err:  errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := thingRepo.Restore(context.Background(), email, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = thingRepo.RetrieveByID(context.Background(), email, th.ID)
+	assert.Nil(t, err, fmt.Sprintf("retrieve restored thing: got unexpected error: %s", err))
+}
+
+func TestThingPurge(t *testing.T) {
+	email := "thing-purge@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+
+	ths := createThings(t, thingRepo, email, 2)
+	err := thingRepo.Remove(context.Background(), email, ths[0].ID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	ids, err := thingRepo.Purge(context.Background(), time.Now().Add(-time.Hour))
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.NotContains(t, ids, ths[0].ID, "expected thing removed after the given time not to be purged")
+
+	ids, err = thingRepo.Purge(context.Background(), time.Now().Add(time.Hour))
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Contains(t, ids, ths[0].ID, "expected thing removed before the given time to be purged")
+	assert.NotContains(t, ids, ths[1].ID, "expected active thing not to be purged")
+
+	err = thingRepo.Restore(context.Background(), email, ths[0].ID)
+	assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("restore purged thing: expected %s got %s", errors.ErrNotFound, err))
+}
+
 func testSortThings(t *testing.T, pm things.PageMetadata, ths []things.Thing) {
 	switch pm.Order {
 	case "name":
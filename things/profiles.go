@@ -0,0 +1,111 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mainflux/mainflux/pkg/schema"
+)
+
+// subtopicsKey is the channel metadata key under which the expected subtopics
+// of the channel created from the profile are stored.
+const subtopicsKey = "subtopics"
+
+// Profile represents a device profile: the reusable template the things are
+// created from. The thing created from the profile gets the profile default
+// metadata, its own channels created from the profile channels, and the
+// connections to the profile channels and to the existing channels listed in
+// the profile connections.
+type Profile struct {
+	ID       string
+	Owner    string
+	Name     string
+	Metadata Metadata
+	// Channels are created for every thing created from the profile.
+	Channels []ProfileChannel
+	// Connections contains the IDs of the existing channels every thing
+	// created from the profile is connected to.
+	Connections []string
+}
+
+// ProfileChannel describes the channel created for every thing created from
+// the profile.
+type ProfileChannel struct {
+	Name      string   `json:"name"`
+	Subtopics []string `json:"subtopics,omitempty"`
+	// Schema is the payload schema of the channel, in the format of the
+	// channel metadata schema key.
+	Schema   map[string]interface{} `json:"schema,omitempty"`
+	Metadata Metadata               `json:"metadata,omitempty"`
+}
+
+// ProfilesPage contains page related metadata as well as list of profiles
+// that belong to this page.
+type ProfilesPage struct {
+	PageMetadata
+	Profiles []Profile
+}
+
+// ProfileRepository specifies a profile persistence API.
+type ProfileRepository interface {
+	// Save persists the profile.
+	Save(ctx context.Context, p Profile) error
+
+	// Update performs an update to the existing profile.
+	Update(ctx context.Context, p Profile) error
+
+	// RetrieveByID retrieves the profile having the provided identifier,
+	// that is owned by the specified user.
+	RetrieveByID(ctx context.Context, owner, id string) (Profile, error)
+
+	// RetrieveAll retrieves the subset of profiles owned by the specified user.
+	RetrieveAll(ctx context.Context, owner string, pm PageMetadata) (ProfilesPage, error)
+
+	// Remove removes the profile having the provided identifier, that is
+	// owned by the specified user.
+	Remove(ctx context.Context, owner, id string) error
+}
+
+// thingMetadata returns the thing metadata with the profile defaults applied.
+// The values set for the thing take precedence over the profile ones.
+func (p Profile) thingMetadata(md Metadata) Metadata {
+	if len(p.Metadata) == 0 {
+		return md
+	}
+
+	merged := Metadata{}
+	for k, v := range p.Metadata {
+		merged[k] = v
+	}
+	for k, v := range md {
+		merged[k] = v
+	}
+	return merged
+}
+
+// channel returns the channel created from the profile channel for the thing.
+func (pc ProfileChannel) channel(th Thing) Channel {
+	name := th.Name
+	if name == "" {
+		name = th.ID
+	}
+
+	md := map[string]interface{}{}
+	for k, v := range pc.Metadata {
+		md[k] = v
+	}
+	if len(pc.Schema) > 0 {
+		md[schema.MetadataKey] = pc.Schema
+	}
+	if len(pc.Subtopics) > 0 {
+		md[subtopicsKey] = pc.Subtopics
+	}
+
+	return Channel{
+		Name:     fmt.Sprintf("%s-%s", name, pc.Name),
+		Metadata: md,
+	}
+}
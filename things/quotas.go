@@ -0,0 +1,58 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+import (
+	"context"
+)
+
+// quotaKey is the thing metadata key under which the thing quota is stored.
+const quotaKey = "quota"
+
+// Quota represents the message-rate and payload-size limits applied to a
+// single thing or to all the things of an owner. Zero value of a limit
+// means that the limit is not applied.
+type Quota struct {
+	Messages    uint64 `json:"messages_per_minute"`
+	PayloadSize uint64 `json:"max_payload_size"`
+}
+
+// ThingQuota contains the quotas that apply to the particular thing: its own
+// quota, stored in the thing metadata, and the quota of its owner.
+type ThingQuota struct {
+	Owner      string
+	Thing      Quota
+	OwnerQuota Quota
+}
+
+// QuotaRepository specifies an owner quota persistence API.
+type QuotaRepository interface {
+	// Save persists the quota of the given owner, replacing the existing one.
+	Save(ctx context.Context, owner string, q Quota) error
+
+	// RetrieveByOwner retrieves the quota of the given owner.
+	RetrieveByOwner(ctx context.Context, owner string) (Quota, error)
+}
+
+// thingQuota extracts the quota from the thing metadata. Missing or invalid
+// values are treated as no limit.
+func thingQuota(md Metadata) Quota {
+	raw, ok := md[quotaKey].(map[string]interface{})
+	if !ok {
+		return Quota{}
+	}
+
+	return Quota{
+		Messages:    quotaLimit(raw["messages_per_minute"]),
+		PayloadSize: quotaLimit(raw["max_payload_size"]),
+	}
+}
+
+func quotaLimit(v interface{}) uint64 {
+	f, ok := v.(float64)
+	if !ok || f < 0 {
+		return 0
+	}
+	return uint64(f)
+}
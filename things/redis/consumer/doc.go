@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package consumer contains events consumer for connection and publish
+// events published by the protocol adapters.
+package consumer
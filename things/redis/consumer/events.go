@@ -0,0 +1,14 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumer
+
+import "time"
+
+type presenceEvent struct {
+	thingID   string
+	eventType string
+	protocol  string
+	clientIP  string
+	timestamp time.Time
+}
@@ -0,0 +1,163 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/things"
+)
+
+const (
+	mqttStream     = "mainflux.mqtt"
+	presenceStream = "mainflux.presence"
+	group          = "mainflux.things"
+
+	connectEvent    = "connect"
+	disconnectEvent = "disconnect"
+	publishEvent    = "publish"
+
+	mqttProtocol = "mqtt"
+
+	exists = "BUSYGROUP Consumer Group name already exists"
+
+	// minBackoff and maxBackoff bound the delay before reading the
+	// streams again after the read fails, e.g. while Redis is down.
+	minBackoff = 100 * time.Millisecond
+	maxBackoff = 10 * time.Second
+)
+
+// Subscriber represents event source for things presence.
+type Subscriber interface {
+	// Subscribe subscribes to the connection and publish events of the
+	// protocol adapters.
+	Subscribe(context.Context) error
+}
+
+type eventStore struct {
+	svc      things.Service
+	client   *redis.Client
+	consumer string
+	timeout  time.Duration
+	logger   logger.Logger
+}
+
+// NewEventStore returns new event store instance. The things seen publishing
+// over the connectionless protocols are considered online for the given
+// timeout.
+func NewEventStore(svc things.Service, client *redis.Client, consumer string, timeout time.Duration, log logger.Logger) Subscriber {
+	return eventStore{
+		svc:      svc,
+		client:   client,
+		consumer: consumer,
+		timeout:  timeout,
+		logger:   log,
+	}
+}
+
+func (es eventStore) Subscribe(ctx context.Context) error {
+	for _, stream := range []string{mqttStream, presenceStream} {
+		err := es.client.XGroupCreateMkStream(ctx, stream, group, "$").Err()
+		if err != nil && err.Error() != exists {
+			return err
+		}
+	}
+
+	backoff := minBackoff
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		streams, err := es.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: es.consumer,
+			Streams:  []string{mqttStream, presenceStream, ">", ">"},
+			Count:    100,
+		}).Result()
+		if err != nil && err != redis.Nil && ctx.Err() == nil {
+			es.logger.Warn(fmt.Sprintf("Failed to read presence events, retrying in %s: %s", backoff, err))
+			select {
+			case <-ctx.Done():
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+		backoff = minBackoff
+		if len(streams) == 0 {
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				pe, err := decodePresence(msg.Values)
+				if err == nil {
+					err = es.handlePresence(ctx, pe)
+				}
+				if err != nil {
+					es.logger.Warn(fmt.Sprintf("Failed to handle presence event: %s", err.Error()))
+				}
+				// The failed events are acknowledged as well, since
+				// retrying them would overwrite the newer status.
+				es.client.XAck(ctx, stream.Stream, group, msg.ID)
+			}
+		}
+	}
+}
+
+func decodePresence(event map[string]interface{}) (presenceEvent, error) {
+	ts, err := strconv.ParseInt(read(event, "timestamp", ""), 10, 64)
+	if err != nil {
+		return presenceEvent{}, err
+	}
+
+	return presenceEvent{
+		thingID:   read(event, "thing_id", ""),
+		eventType: read(event, "event_type", ""),
+		protocol:  read(event, "protocol", mqttProtocol),
+		clientIP:  read(event, "client_ip", ""),
+		timestamp: time.Unix(ts, 0),
+	}, nil
+}
+
+func (es eventStore) handlePresence(ctx context.Context, pe presenceEvent) error {
+	st := things.Status{
+		ThingID:  pe.thingID,
+		LastSeen: pe.timestamp,
+		Protocol: pe.protocol,
+		ClientIP: pe.clientIP,
+	}
+
+	switch pe.eventType {
+	case connectEvent:
+		st.Online = true
+	case disconnectEvent:
+		st.Online = false
+	case publishEvent:
+		st.Online = true
+		st.Expires = pe.timestamp.Add(es.timeout)
+	default:
+		return nil
+	}
+
+	return es.svc.UpdateStatus(ctx, st)
+}
+
+func read(event map[string]interface{}, key, def string) string {
+	val, ok := event[key].(string)
+	if !ok {
+		return def
+	}
+
+	return val
+}
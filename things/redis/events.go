@@ -1,19 +1,28 @@
 package redis
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
 
 const (
 	thingPrefix     = "thing."
 	thingCreate     = thingPrefix + "create"
 	thingUpdate     = thingPrefix + "update"
 	thingRemove     = thingPrefix + "remove"
+	thingRestore    = thingPrefix + "restore"
+	thingPurge      = thingPrefix + "purge"
 	thingConnect    = thingPrefix + "connect"
 	thingDisconnect = thingPrefix + "disconnect"
-
-	channelPrefix = "channel."
-	channelCreate = channelPrefix + "create"
-	channelUpdate = channelPrefix + "update"
-	channelRemove = channelPrefix + "remove"
+	thingStatus     = thingPrefix + "status"
+
+	channelPrefix  = "channel."
+	channelCreate  = channelPrefix + "create"
+	channelUpdate  = channelPrefix + "update"
+	channelRemove  = channelPrefix + "remove"
+	channelRestore = channelPrefix + "restore"
+	channelPurge   = channelPrefix + "purge"
 )
 
 type event interface {
@@ -24,11 +33,16 @@ var (
 	_ event = (*createThingEvent)(nil)
 	_ event = (*updateThingEvent)(nil)
 	_ event = (*removeThingEvent)(nil)
+	_ event = (*restoreThingEvent)(nil)
+	_ event = (*purgeThingEvent)(nil)
 	_ event = (*createChannelEvent)(nil)
 	_ event = (*updateChannelEvent)(nil)
 	_ event = (*removeChannelEvent)(nil)
+	_ event = (*restoreChannelEvent)(nil)
+	_ event = (*purgeChannelEvent)(nil)
 	_ event = (*connectThingEvent)(nil)
 	_ event = (*disconnectThingEvent)(nil)
+	_ event = (*thingStatusEvent)(nil)
 )
 
 type createThingEvent struct {
@@ -100,6 +114,47 @@ func (rte removeThingEvent) Encode() map[string]interface{} {
 	}
 }
 
+type restoreThingEvent struct {
+	id       string
+	owner    string
+	name     string
+	metadata map[string]interface{}
+}
+
+func (rte restoreThingEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        rte.id,
+		"owner":     rte.owner,
+		"operation": thingRestore,
+	}
+
+	if rte.name != "" {
+		val["name"] = rte.name
+	}
+
+	if rte.metadata != nil {
+		metadata, err := json.Marshal(rte.metadata)
+		if err != nil {
+			return val
+		}
+
+		val["metadata"] = string(metadata)
+	}
+
+	return val
+}
+
+type purgeThingEvent struct {
+	id string
+}
+
+func (pte purgeThingEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        pte.id,
+		"operation": thingPurge,
+	}
+}
+
 type createChannelEvent struct {
 	id       string
 	owner    string
@@ -169,6 +224,47 @@ func (rce removeChannelEvent) Encode() map[string]interface{} {
 	}
 }
 
+type restoreChannelEvent struct {
+	id       string
+	owner    string
+	name     string
+	metadata map[string]interface{}
+}
+
+func (rce restoreChannelEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        rce.id,
+		"owner":     rce.owner,
+		"operation": channelRestore,
+	}
+
+	if rce.name != "" {
+		val["name"] = rce.name
+	}
+
+	if rce.metadata != nil {
+		metadata, err := json.Marshal(rce.metadata)
+		if err != nil {
+			return val
+		}
+
+		val["metadata"] = string(metadata)
+	}
+
+	return val
+}
+
+type purgeChannelEvent struct {
+	id string
+}
+
+func (pce purgeChannelEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        pce.id,
+		"operation": channelPurge,
+	}
+}
+
 type connectThingEvent struct {
 	chanID  string
 	thingID string
@@ -194,3 +290,27 @@ func (dte disconnectThingEvent) Encode() map[string]interface{} {
 		"operation": thingDisconnect,
 	}
 }
+
+type thingStatusEvent struct {
+	id       string
+	online   bool
+	lastSeen time.Time
+	protocol string
+	clientIP string
+}
+
+func (tse thingStatusEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        tse.id,
+		"online":    strconv.FormatBool(tse.online),
+		"last_seen": strconv.FormatInt(tse.lastSeen.Unix(), 10),
+		"protocol":  tse.protocol,
+		"operation": thingStatus,
+	}
+
+	if tse.clientIP != "" {
+		val["client_ip"] = tse.clientIP
+	}
+
+	return val
+}
@@ -5,6 +5,7 @@ package redis
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/mainflux/mainflux/things"
@@ -13,6 +14,11 @@ import (
 const (
 	streamID  = "mainflux.things"
 	streamLen = 1000
+
+	// minBackoff and maxBackoff bound the delay between the attempts to
+	// publish the purge event.
+	minBackoff = 100 * time.Millisecond
+	maxBackoff = 10 * time.Second
 )
 
 var _ things.Service = (*eventStore)(nil)
@@ -82,6 +88,19 @@ func (es eventStore) UpdateKey(ctx context.Context, token, id, key string) error
 	return es.svc.UpdateKey(ctx, token, id, key)
 }
 
+// Key operations don't send events for the same reason as UpdateKey.
+func (es eventStore) RotateKey(ctx context.Context, token, id, key string, grace time.Duration) (string, error) {
+	return es.svc.RotateKey(ctx, token, id, key, grace)
+}
+
+func (es eventStore) AddKey(ctx context.Context, token, id string, key things.Key) (things.Key, error) {
+	return es.svc.AddKey(ctx, token, id, key)
+}
+
+func (es eventStore) RemoveKey(ctx context.Context, token, id, key string) error {
+	return es.svc.RemoveKey(ctx, token, id, key)
+}
+
 func (es eventStore) ShareThing(ctx context.Context, token, thingID string, actions, userIDs []string) error {
 	return es.svc.ShareThing(ctx, token, thingID, actions, userIDs)
 }
@@ -116,6 +135,37 @@ func (es eventStore) RemoveThing(ctx context.Context, token, id string) error {
 	return nil
 }
 
+func (es eventStore) RestoreThing(ctx context.Context, token, id string) (things.Thing, error) {
+	th, err := es.svc.RestoreThing(ctx, token, id)
+	if err != nil {
+		return th, err
+	}
+
+	es.add(ctx, restoreThingEvent{
+		id:       th.ID,
+		owner:    th.Owner,
+		name:     th.Name,
+		metadata: th.Metadata,
+	})
+
+	return th, nil
+}
+
+func (es eventStore) PurgeThings(ctx context.Context, before time.Time) ([]string, error) {
+	ids, err := es.svc.PurgeThings(ctx, before)
+	if err != nil {
+		return ids, err
+	}
+
+	for _, id := range ids {
+		if err := es.addDurably(ctx, purgeThingEvent{id: id}); err != nil {
+			return ids, err
+		}
+	}
+
+	return ids, nil
+}
+
 func (es eventStore) CreateChannels(ctx context.Context, token string, channels ...things.Channel) ([]things.Channel, error) {
 	schs, err := es.svc.CreateChannels(ctx, token, channels...)
 	if err != nil {
@@ -190,6 +240,37 @@ func (es eventStore) RemoveChannel(ctx context.Context, token, id string) error
 	return nil
 }
 
+func (es eventStore) RestoreChannel(ctx context.Context, token, id string) (things.Channel, error) {
+	ch, err := es.svc.RestoreChannel(ctx, token, id)
+	if err != nil {
+		return ch, err
+	}
+
+	es.add(ctx, restoreChannelEvent{
+		id:       ch.ID,
+		owner:    ch.Owner,
+		name:     ch.Name,
+		metadata: ch.Metadata,
+	})
+
+	return ch, nil
+}
+
+func (es eventStore) PurgeChannels(ctx context.Context, before time.Time) ([]string, error) {
+	ids, err := es.svc.PurgeChannels(ctx, before)
+	if err != nil {
+		return ids, err
+	}
+
+	for _, id := range ids {
+		if err := es.addDurably(ctx, purgeChannelEvent{id: id}); err != nil {
+			return ids, err
+		}
+	}
+
+	return ids, nil
+}
+
 func (es eventStore) Connect(ctx context.Context, token string, chIDs, thIDs []string) error {
 	if err := es.svc.Connect(ctx, token, chIDs, thIDs); err != nil {
 		return err
@@ -248,10 +329,200 @@ func (es eventStore) IsChannelOwner(ctx context.Context, owner, chanID string) e
 	return es.svc.IsChannelOwner(ctx, owner, chanID)
 }
 
+func (es eventStore) IsChannelsOwner(ctx context.Context, owner string, chanIDs []string) error {
+	return es.svc.IsChannelsOwner(ctx, owner, chanIDs)
+}
+
 func (es eventStore) Identify(ctx context.Context, key string) (string, error) {
 	return es.svc.Identify(ctx, key)
 }
 
+func (es eventStore) ViewThingByID(ctx context.Context, id string) (things.Thing, error) {
+	return es.svc.ViewThingByID(ctx, id)
+}
+
+func (es eventStore) ViewChannelByID(ctx context.Context, id string) (things.Channel, error) {
+	return es.svc.ViewChannelByID(ctx, id)
+}
+
 func (es eventStore) ListMembers(ctx context.Context, token, groupID string, pm things.PageMetadata) (things.Page, error) {
 	return es.svc.ListMembers(ctx, token, groupID, pm)
 }
+
+func (es eventStore) UpdateQuota(ctx context.Context, token, owner string, q things.Quota) error {
+	return es.svc.UpdateQuota(ctx, token, owner, q)
+}
+
+func (es eventStore) ViewQuota(ctx context.Context, token, owner string) (things.Quota, error) {
+	return es.svc.ViewQuota(ctx, token, owner)
+}
+
+func (es eventStore) ViewThingQuota(ctx context.Context, id string) (things.ThingQuota, error) {
+	return es.svc.ViewThingQuota(ctx, id)
+}
+
+func (es eventStore) ViewStatus(ctx context.Context, token, id string) (things.Status, error) {
+	return es.svc.ViewStatus(ctx, token, id)
+}
+
+func (es eventStore) UpdateStatus(ctx context.Context, st things.Status) error {
+	if err := es.svc.UpdateStatus(ctx, st); err != nil {
+		return err
+	}
+
+	event := thingStatusEvent{
+		id:       st.ThingID,
+		online:   st.Online,
+		lastSeen: st.LastSeen,
+		protocol: st.Protocol,
+		clientIP: st.ClientIP,
+	}
+	record := &redis.XAddArgs{
+		Stream:       streamID,
+		MaxLenApprox: streamLen,
+		Values:       event.Encode(),
+	}
+	es.client.XAdd(ctx, record).Err()
+
+	return nil
+}
+
+func (es eventStore) CreateProfile(ctx context.Context, token string, p things.Profile) (things.Profile, error) {
+	return es.svc.CreateProfile(ctx, token, p)
+}
+
+func (es eventStore) UpdateProfile(ctx context.Context, token string, p things.Profile) error {
+	return es.svc.UpdateProfile(ctx, token, p)
+}
+
+func (es eventStore) ViewProfile(ctx context.Context, token, id string) (things.Profile, error) {
+	return es.svc.ViewProfile(ctx, token, id)
+}
+
+func (es eventStore) ListProfiles(ctx context.Context, token string, pm things.PageMetadata) (things.ProfilesPage, error) {
+	return es.svc.ListProfiles(ctx, token, pm)
+}
+
+func (es eventStore) RemoveProfile(ctx context.Context, token, id string) error {
+	return es.svc.RemoveProfile(ctx, token, id)
+}
+
+func (es eventStore) ImportThings(ctx context.Context, token string, records []things.Record, dryRun bool) ([]things.ImportResult, error) {
+	results, err := es.svc.ImportThings(ctx, token, records, dryRun)
+	if err != nil || dryRun {
+		return results, err
+	}
+
+	for _, res := range results {
+		if res.Err != nil && !res.Partial {
+			continue
+		}
+
+		var event event
+		switch res.Action {
+		case things.ImportCreate:
+			event = createThingEvent{
+				id:       res.Record.ID,
+				owner:    res.Record.Owner,
+				name:     res.Record.Name,
+				metadata: res.Record.Metadata,
+			}
+		default:
+			event = updateThingEvent{
+				id:       res.Record.ID,
+				name:     res.Record.Name,
+				metadata: res.Record.Metadata,
+			}
+		}
+		es.add(ctx, event)
+
+		for _, chID := range res.Record.Connections {
+			es.add(ctx, connectThingEvent{
+				chanID:  chID,
+				thingID: res.Record.ID,
+			})
+		}
+	}
+
+	return results, nil
+}
+
+func (es eventStore) ImportChannels(ctx context.Context, token string, records []things.Record, dryRun bool) ([]things.ImportResult, error) {
+	results, err := es.svc.ImportChannels(ctx, token, records, dryRun)
+	if err != nil || dryRun {
+		return results, err
+	}
+
+	for _, res := range results {
+		if res.Err != nil {
+			continue
+		}
+
+		var event event
+		switch res.Action {
+		case things.ImportCreate:
+			event = createChannelEvent{
+				id:       res.Record.ID,
+				owner:    res.Record.Owner,
+				name:     res.Record.Name,
+				metadata: res.Record.Metadata,
+			}
+		default:
+			event = updateChannelEvent{
+				id:       res.Record.ID,
+				name:     res.Record.Name,
+				metadata: res.Record.Metadata,
+			}
+		}
+		es.add(ctx, event)
+	}
+
+	return results, nil
+}
+
+func (es eventStore) ExportThings(ctx context.Context, token string) ([]things.Record, error) {
+	return es.svc.ExportThings(ctx, token)
+}
+
+func (es eventStore) ExportChannels(ctx context.Context, token string) ([]things.Record, error) {
+	return es.svc.ExportChannels(ctx, token)
+}
+
+func (es eventStore) add(ctx context.Context, event event) {
+	record := &redis.XAddArgs{
+		Stream:       streamID,
+		MaxLenApprox: streamLen,
+		Values:       event.Encode(),
+	}
+	es.client.XAdd(ctx, record).Err()
+}
+
+// addDurably publishes the event, retrying with the exponential backoff until
+// it succeeds or the context is done. It's used for the events that can't be
+// published again, like the purge events the other services clean up on,
+// since the purged entities are gone once the purge returns.
+func (es eventStore) addDurably(ctx context.Context, event event) error {
+	record := &redis.XAddArgs{
+		Stream:       streamID,
+		MaxLenApprox: streamLen,
+		Values:       event.Encode(),
+	}
+
+	backoff := minBackoff
+	for {
+		err := es.client.XAdd(ctx, record).Err()
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
@@ -32,6 +32,7 @@ const (
 	thingRemove     = thingPrefix + "remove"
 	thingConnect    = thingPrefix + "connect"
 	thingDisconnect = thingPrefix + "disconnect"
+	thingStatus     = thingPrefix + "status"
 
 	channelPrefix = "channel."
 	channelCreate = channelPrefix + "create"
@@ -47,11 +48,14 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	profilesRepo := mocks.NewProfileRepository()
+	quotasRepo := mocks.NewQuotaRepository()
+	statusRepo := mocks.NewStatusRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idProvider := uuid.NewMock()
 
-	return things.New(auth, thingsRepo, channelsRepo, chanCache, thingCache, idProvider)
+	return things.New(auth, thingsRepo, channelsRepo, profilesRepo, quotasRepo, statusRepo, chanCache, thingCache, idProvider)
 }
 
 func TestCreateThings(t *testing.T) {
@@ -652,3 +656,70 @@ func TestDisconnectEvent(t *testing.T) {
 		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
 	}
 }
+
+func TestUpdateStatus(t *testing.T) {
+	_ = redisClient.FlushAll(context.Background()).Err()
+
+	svc := newService(map[string]string{token: email})
+	// Create thing without sending event.
+	sths, err := svc.CreateThings(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	sth := sths[0]
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	lastSeen := time.Now()
+	cases := []struct {
+		desc   string
+		status things.Status
+		err    error
+		event  map[string]interface{}
+	}{
+		{
+			desc:   "update status of connected thing",
+			status: things.Status{ThingID: sth.ID, Online: true, LastSeen: lastSeen, Protocol: "mqtt", ClientIP: "10.0.0.1"},
+			err:    nil,
+			event: map[string]interface{}{
+				"id":        sth.ID,
+				"online":    "true",
+				"last_seen": strconv.FormatInt(lastSeen.Unix(), 10),
+				"protocol":  "mqtt",
+				"client_ip": "10.0.0.1",
+				"operation": thingStatus,
+			},
+		},
+		{
+			desc:   "update status of disconnected thing",
+			status: things.Status{ThingID: sth.ID, LastSeen: lastSeen, Protocol: "mqtt"},
+			err:    nil,
+			event: map[string]interface{}{
+				"id":        sth.ID,
+				"online":    "false",
+				"last_seen": strconv.FormatInt(lastSeen.Unix(), 10),
+				"protocol":  "mqtt",
+				"operation": thingStatus,
+			},
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		err := svc.UpdateStatus(context.Background(), tc.status)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		streams := redisClient.XRead(context.Background(), &r.XReadArgs{
+			Streams: []string{streamID, lastID},
+			Count:   1,
+			Block:   time.Second,
+		}).Val()
+
+		var event map[string]interface{}
+		if len(streams) > 0 && len(streams[0].Messages) > 0 {
+			msg := streams[0].Messages[0]
+			event = msg.Values
+			lastID = msg.ID
+		}
+
+		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
+	}
+}
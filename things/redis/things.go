@@ -6,6 +6,7 @@ package redis
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/mainflux/mainflux/pkg/errors"
@@ -14,7 +15,10 @@ import (
 
 const (
 	keyPrefix = "thing_key"
-	idPrefix  = "thing"
+	idPrefix  = "thing_keys"
+	// legacyIDPrefix is used by the cache entries holding the single key
+	// of the thing, which were stored before the things had multiple keys.
+	legacyIDPrefix = "thing"
 )
 
 var _ things.ThingCache = (*thingCache)(nil)
@@ -30,14 +34,21 @@ func NewThingCache(client *redis.Client) things.ThingCache {
 	}
 }
 
-func (tc *thingCache) Save(ctx context.Context, thingKey string, thingID string) error {
-	tkey := fmt.Sprintf("%s:%s", keyPrefix, thingKey)
-	if err := tc.client.Set(ctx, tkey, thingID, 0).Err(); err != nil {
+func (tc *thingCache) Save(ctx context.Context, key things.Key) error {
+	var ttl time.Duration
+	if !key.ExpiresAt.IsZero() {
+		if ttl = time.Until(key.ExpiresAt); ttl <= 0 {
+			return nil
+		}
+	}
+
+	tkey := fmt.Sprintf("%s:%s", keyPrefix, key.Value)
+	if err := tc.client.Set(ctx, tkey, key.ThingID, ttl).Err(); err != nil {
 		return errors.Wrap(errors.ErrCreateEntity, err)
 	}
 
-	tid := fmt.Sprintf("%s:%s", idPrefix, thingID)
-	if err := tc.client.Set(ctx, tid, thingKey, 0).Err(); err != nil {
+	tid := fmt.Sprintf("%s:%s", idPrefix, key.ThingID)
+	if err := tc.client.SAdd(ctx, tid, key.Value).Err(); err != nil {
 		return errors.Wrap(errors.ErrCreateEntity, err)
 	}
 	return nil
@@ -55,17 +66,26 @@ func (tc *thingCache) ID(ctx context.Context, thingKey string) (string, error) {
 
 func (tc *thingCache) Remove(ctx context.Context, thingID string) error {
 	tid := fmt.Sprintf("%s:%s", idPrefix, thingID)
-	key, err := tc.client.Get(ctx, tid).Result()
-	// Redis returns Nil Reply when key does not exist.
-	if err == redis.Nil {
-		return nil
-	}
+	keys, err := tc.client.SMembers(ctx, tid).Result()
 	if err != nil {
 		return errors.Wrap(errors.ErrRemoveEntity, err)
 	}
 
-	tkey := fmt.Sprintf("%s:%s", keyPrefix, key)
-	if err := tc.client.Del(ctx, tkey, tid).Err(); err != nil {
+	lid := fmt.Sprintf("%s:%s", legacyIDPrefix, thingID)
+	key, err := tc.client.Get(ctx, lid).Result()
+	switch err {
+	case nil:
+		keys = append(keys, key)
+	case redis.Nil:
+	default:
+		return errors.Wrap(errors.ErrRemoveEntity, err)
+	}
+
+	entries := []string{tid, lid}
+	for _, key := range keys {
+		entries = append(entries, fmt.Sprintf("%s:%s", keyPrefix, key))
+	}
+	if err := tc.client.Del(ctx, entries...).Err(); err != nil {
 		return errors.Wrap(errors.ErrRemoveEntity, err)
 	}
 	return nil
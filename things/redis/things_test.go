@@ -7,10 +7,12 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	r "github.com/go-redis/redis/v8"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/pkg/uuid"
+	"github.com/mainflux/mainflux/things"
 	"github.com/mainflux/mainflux/things/redis"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -25,14 +27,15 @@ func TestThingSave(t *testing.T) {
 	id := "123"
 	id2 := "124"
 
-	err = thingCache.Save(context.Background(), key, id2)
+	err = thingCache.Save(context.Background(), things.Key{Value: key, ThingID: id2})
 	require.Nil(t, err, fmt.Sprintf("Save thing to cache: expected nil got %s", err))
 
 	cases := []struct {
-		desc string
-		ID   string
-		key  string
-		err  error
+		desc      string
+		ID        string
+		key       string
+		expiresAt time.Time
+		err       error
 	}{
 		{
 			desc: "Save thing to cache",
@@ -46,10 +49,24 @@ func TestThingSave(t *testing.T) {
 			key:  key,
 			err:  nil,
 		},
+		{
+			desc:      "Save thing with expiring key to cache",
+			ID:        id,
+			key:       key,
+			expiresAt: time.Now().Add(time.Minute),
+			err:       nil,
+		},
+		{
+			desc:      "Save thing with expired key to cache",
+			ID:        id,
+			key:       key,
+			expiresAt: time.Now().Add(-time.Minute),
+			err:       nil,
+		},
 	}
 
 	for _, tc := range cases {
-		err := thingCache.Save(context.Background(), tc.key, tc.ID)
+		err := thingCache.Save(context.Background(), things.Key{Value: tc.key, ThingID: tc.ID, ExpiresAt: tc.expiresAt})
 		assert.Nil(t, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
 
 	}
@@ -61,7 +78,12 @@ func TestThingID(t *testing.T) {
 	key, err := idProvider.ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
 	id := "123"
-	err = thingCache.Save(context.Background(), key, id)
+	err = thingCache.Save(context.Background(), things.Key{Value: key, ThingID: id})
+	require.Nil(t, err, fmt.Sprintf("Save thing to cache: expected nil got %s", err))
+
+	expired, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = thingCache.Save(context.Background(), things.Key{Value: expired, ThingID: id, ExpiresAt: time.Now().Add(-time.Minute)})
 	require.Nil(t, err, fmt.Sprintf("Save thing to cache: expected nil got %s", err))
 
 	cases := map[string]struct {
@@ -79,6 +101,11 @@ func TestThingID(t *testing.T) {
 			key: wrongValue,
 			err: r.Nil,
 		},
+		"Get ID by expired thing-key": {
+			ID:  "",
+			key: expired,
+			err: r.Nil,
+		},
 	}
 
 	for desc, tc := range cases {
@@ -95,7 +122,11 @@ func TestThingRemove(t *testing.T) {
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
 	id := "123"
 	id2 := "321"
-	thingCache.Save(context.Background(), key, id)
+	thingCache.Save(context.Background(), things.Key{Value: key, ThingID: id})
+
+	key2, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thingCache.Save(context.Background(), things.Key{Value: key2, ThingID: id, ExpiresAt: time.Now().Add(time.Minute)})
 
 	cases := []struct {
 		desc string
@@ -119,4 +150,9 @@ func TestThingRemove(t *testing.T) {
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 
+	for _, k := range []string{key, key2} {
+		_, err := thingCache.ID(context.Background(), k)
+		assert.True(t, errors.Contains(err, r.Nil), fmt.Sprintf("Retrieve removed thing key: expected %s got %s\n", r.Nil, err))
+	}
+
 }
@@ -6,6 +6,7 @@ package things
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/mainflux/mainflux/pkg/errors"
 
@@ -26,6 +27,8 @@ const (
 // implementation, and all of its decorators (e.g. logging & metrics).
 type Service interface {
 	// CreateThings adds things to the user identified by the provided key.
+	// Things referring to the profile are created from it, with the profile
+	// default metadata, channels and connections.
 	CreateThings(ctx context.Context, token string, things ...Thing) ([]Thing, error)
 
 	// UpdateThing updates the thing identified by the provided ID, that
@@ -41,6 +44,18 @@ type Service interface {
 	// returned to indicate operation failure.
 	UpdateKey(ctx context.Context, token, id, key string) error
 
+	// RotateKey replaces the primary key of the thing with the provided or
+	// generated key, and returns the new key. The replaced key remains
+	// active for the grace period, so the devices can be updated meanwhile.
+	RotateKey(ctx context.Context, token, id, key string, grace time.Duration) (string, error)
+
+	// AddKey adds the labeled key to the thing, generating the key value if
+	// it's not provided, and returns the saved key.
+	AddKey(ctx context.Context, token, id string, key Key) (Key, error)
+
+	// RemoveKey revokes the additional key of the thing.
+	RemoveKey(ctx context.Context, token, id, key string) error
+
 	// ViewThing retrieves data about the thing identified with the provided
 	// ID, that belongs to the user identified by the provided key.
 	ViewThing(ctx context.Context, token, id string) (Thing, error)
@@ -55,9 +70,20 @@ type Service interface {
 	ListThingsByChannel(ctx context.Context, token, chID string, pm PageMetadata) (Page, error)
 
 	// RemoveThing removes the thing identified with the provided ID, that
-	// belongs to the user identified by the provided key.
+	// belongs to the user identified by the provided key. The removed thing
+	// can be restored until it's purged.
 	RemoveThing(ctx context.Context, token, id string) error
 
+	// RestoreThing restores the removed thing identified with the provided
+	// ID, that belongs to the user identified by the provided key, and
+	// returns the restored thing.
+	RestoreThing(ctx context.Context, token, id string) (Thing, error)
+
+	// PurgeThings permanently deletes the things removed before the given
+	// time, and returns their IDs. It's intended for the internal use by
+	// the purge job and it's not exposed over the HTTP API.
+	PurgeThings(ctx context.Context, before time.Time) ([]string, error)
+
 	// CreateChannels adds channels to the user identified by the provided key.
 	CreateChannels(ctx context.Context, token string, channels ...Channel) ([]Channel, error)
 
@@ -79,9 +105,20 @@ type Service interface {
 	ListChannelsByThing(ctx context.Context, token, thID string, pm PageMetadata) (ChannelsPage, error)
 
 	// RemoveChannel removes the thing identified by the provided ID, that
-	// belongs to the user identified by the provided key.
+	// belongs to the user identified by the provided key. The removed
+	// channel can be restored until it's purged.
 	RemoveChannel(ctx context.Context, token, id string) error
 
+	// RestoreChannel restores the removed channel identified by the provided
+	// ID, that belongs to the user identified by the provided key, and
+	// returns the restored channel.
+	RestoreChannel(ctx context.Context, token, id string) (Channel, error)
+
+	// PurgeChannels permanently deletes the channels removed before the
+	// given time, and returns their IDs. It's intended for the internal use
+	// by the purge job and it's not exposed over the HTTP API.
+	PurgeChannels(ctx context.Context, before time.Time) ([]string, error)
+
 	// Connect adds things to the channels list of connected things.
 	Connect(ctx context.Context, token string, chIDs, thIDs []string) error
 
@@ -101,11 +138,91 @@ type Service interface {
 	// the given user and returns error if it cannot.
 	IsChannelOwner(ctx context.Context, owner, chanID string) error
 
+	// IsChannelsOwner determines whether all the channels can be accessed
+	// by the given user and returns error if any of them cannot.
+	IsChannelsOwner(ctx context.Context, owner string, chanIDs []string) error
+
 	// Identify returns thing ID for given thing key.
 	Identify(ctx context.Context, key string) (string, error)
 
+	// ViewThingByID retrieves data about the thing identified with the provided
+	// ID, regardless of its owner. It's intended for the internal use by the
+	// other services and it's not exposed over the HTTP API.
+	ViewThingByID(ctx context.Context, id string) (Thing, error)
+
+	// ViewChannelByID retrieves data about the channel identified with the
+	// provided ID, regardless of its owner. It's intended for the internal use
+	// by the other services and it's not exposed over the HTTP API.
+	ViewChannelByID(ctx context.Context, id string) (Channel, error)
+
 	// ListMembers retrieves everything that is assigned to a group identified by groupID.
 	ListMembers(ctx context.Context, token, groupID string, pm PageMetadata) (Page, error)
+
+	// UpdateQuota sets the quota applied to all the things of the given
+	// owner. Only the administrators are allowed to update quotas.
+	UpdateQuota(ctx context.Context, token, owner string, q Quota) error
+
+	// ViewQuota retrieves the quota of the given owner. The quota can be
+	// viewed by the owner itself or by the administrators.
+	ViewQuota(ctx context.Context, token, owner string) (Quota, error)
+
+	// ViewThingQuota retrieves the quotas that apply to the thing identified
+	// with the provided ID. It's intended for the internal use by the
+	// protocol adapters and it's not exposed over the HTTP API.
+	ViewThingQuota(ctx context.Context, id string) (ThingQuota, error)
+
+	// ViewStatus retrieves the presence of the thing identified with the
+	// provided ID. Things that have never been seen are reported offline.
+	ViewStatus(ctx context.Context, token, id string) (Status, error)
+
+	// UpdateStatus records the thing presence reported by the protocol
+	// adapters. It's intended for the internal use by the event consumer
+	// and it's not exposed over the HTTP API.
+	UpdateStatus(ctx context.Context, st Status) error
+
+	// CreateProfile adds the profile to the user identified by the provided
+	// key. The channels listed in the profile connections must belong to
+	// the user.
+	CreateProfile(ctx context.Context, token string, p Profile) (Profile, error)
+
+	// UpdateProfile updates the profile identified by the provided ID, that
+	// belongs to the user identified by the provided key. The changes apply
+	// only to the things created afterwards.
+	UpdateProfile(ctx context.Context, token string, p Profile) error
+
+	// ViewProfile retrieves data about the profile identified by the
+	// provided ID, that belongs to the user identified by the provided key.
+	ViewProfile(ctx context.Context, token, id string) (Profile, error)
+
+	// ListProfiles retrieves data about subset of profiles that belongs to
+	// the user identified by the provided key.
+	ListProfiles(ctx context.Context, token string, pm PageMetadata) (ProfilesPage, error)
+
+	// RemoveProfile removes the profile identified by the provided ID, that
+	// belongs to the user identified by the provided key. The things created
+	// from the profile are kept.
+	RemoveProfile(ctx context.Context, token, id string) error
+
+	// ImportThings creates or updates the things of the user identified by
+	// the provided key from the given records, and connects them to the
+	// listed channels. The records are matched with the existing things by
+	// the ID, the external ID or the name. Each record is imported
+	// independently and its error is reported in its result. If dryRun is
+	// set, the records are only validated and matched.
+	ImportThings(ctx context.Context, token string, records []Record, dryRun bool) ([]ImportResult, error)
+
+	// ImportChannels creates or updates the channels of the user identified
+	// by the provided key from the given records, the same way as the things
+	// are imported.
+	ImportChannels(ctx context.Context, token string, records []Record, dryRun bool) ([]ImportResult, error)
+
+	// ExportThings retrieves all the things that belong to the user
+	// identified by the provided key, with their connections.
+	ExportThings(ctx context.Context, token string) ([]Record, error)
+
+	// ExportChannels retrieves all the channels that belong to the user
+	// identified by the provided key.
+	ExportChannels(ctx context.Context, token string) ([]Record, error)
 }
 
 // PageMetadata contains page metadata that helps navigation.
@@ -119,6 +236,8 @@ type PageMetadata struct {
 	Metadata          map[string]interface{} `json:"metadata,omitempty"`
 	Disconnected      bool                   // Used for connected or disconnected lists
 	FetchSharedThings bool                   // Used for identifying fetching either all or shared things.
+	Online            *bool                  `json:"online,omitempty"`  // Used for listing only online or offline things.
+	Deleted           bool                   `json:"deleted,omitempty"` // Used for listing the removed things or channels.
 }
 
 var _ Service = (*thingsService)(nil)
@@ -127,6 +246,9 @@ type thingsService struct {
 	auth         mainflux.AuthServiceClient
 	things       ThingRepository
 	channels     ChannelRepository
+	profiles     ProfileRepository
+	quotas       QuotaRepository
+	statuses     StatusRepository
 	channelCache ChannelCache
 	thingCache   ThingCache
 	idProvider   mainflux.IDProvider
@@ -134,11 +256,14 @@ type thingsService struct {
 }
 
 // New instantiates the things service implementation.
-func New(auth mainflux.AuthServiceClient, things ThingRepository, channels ChannelRepository, ccache ChannelCache, tcache ThingCache, idp mainflux.IDProvider) Service {
+func New(auth mainflux.AuthServiceClient, things ThingRepository, channels ChannelRepository, profiles ProfileRepository, quotas QuotaRepository, statuses StatusRepository, ccache ChannelCache, tcache ThingCache, idp mainflux.IDProvider) Service {
 	return &thingsService{
 		auth:         auth,
 		things:       things,
 		channels:     channels,
+		profiles:     profiles,
+		quotas:       quotas,
+		statuses:     statuses,
 		channelCache: ccache,
 		thingCache:   tcache,
 		idProvider:   idp,
@@ -191,6 +316,19 @@ func (ts *thingsService) createThing(ctx context.Context, thing *Thing, identity
 		thing.Key = key
 	}
 
+	var profile Profile
+	if thing.ProfileID != "" {
+		p, err := ts.profiles.RetrieveByID(ctx, thing.Owner, thing.ProfileID)
+		if errors.Contains(err, errors.ErrNotFound) {
+			return Thing{}, errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		if err != nil {
+			return Thing{}, err
+		}
+		profile = p
+		thing.Metadata = p.thingMetadata(thing.Metadata)
+	}
+
 	ths, err := ts.things.Save(ctx, *thing)
 	if err != nil {
 		return Thing{}, err
@@ -204,9 +342,35 @@ func (ts *thingsService) createThing(ctx context.Context, thing *Thing, identity
 		return Thing{}, err
 	}
 
+	if thing.ProfileID != "" {
+		if err := ts.applyProfile(ctx, profile, ths[0], identity); err != nil {
+			return Thing{}, err
+		}
+	}
+
 	return ths[0], nil
 }
 
+// applyProfile creates the profile channels for the thing, and connects the
+// thing to them and to the profile connections.
+func (ts *thingsService) applyProfile(ctx context.Context, p Profile, th Thing, identity *mainflux.UserIdentity) error {
+	chIDs := append([]string{}, p.Connections...)
+	for _, pc := range p.Channels {
+		ch := pc.channel(th)
+		ch, err := ts.createChannel(ctx, &ch, identity)
+		if err != nil {
+			return err
+		}
+		chIDs = append(chIDs, ch.ID)
+	}
+
+	if len(chIDs) == 0 {
+		return nil
+	}
+
+	return ts.channels.Connect(ctx, identity.GetEmail(), chIDs, []string{th.ID})
+}
+
 func (ts *thingsService) UpdateThing(ctx context.Context, token string, thing Thing) error {
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
@@ -269,7 +433,92 @@ func (ts *thingsService) UpdateKey(ctx context.Context, token, id, key string) e
 
 	owner := res.GetEmail()
 
-	return ts.things.UpdateKey(ctx, owner, id, key)
+	if err := ts.things.UpdateKey(ctx, owner, id, key); err != nil {
+		return err
+	}
+
+	return ts.thingCache.Remove(ctx, id)
+}
+
+func (ts *thingsService) RotateKey(ctx context.Context, token, id, key string, grace time.Duration) (string, error) {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", err
+	}
+
+	if err := ts.authorize(ctx, res.GetId(), id, writeRelationKey); err != nil {
+		if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err != nil {
+			return "", err
+		}
+	}
+
+	if key == "" {
+		if key, err = ts.idProvider.ID(); err != nil {
+			return "", err
+		}
+	}
+
+	var expiresAt time.Time
+	if grace > 0 {
+		expiresAt = time.Now().Add(grace)
+	}
+
+	if err := ts.things.RotateKey(ctx, id, key, expiresAt); err != nil {
+		return "", err
+	}
+
+	// The replaced key is cached as the primary one, so the cache is cleared
+	// to have it reloaded with the expiration time.
+	if err := ts.thingCache.Remove(ctx, id); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+func (ts *thingsService) AddKey(ctx context.Context, token, id string, key Key) (Key, error) {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Key{}, err
+	}
+
+	if err := ts.authorize(ctx, res.GetId(), id, writeRelationKey); err != nil {
+		if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err != nil {
+			return Key{}, err
+		}
+	}
+
+	if key.Value == "" {
+		if key.Value, err = ts.idProvider.ID(); err != nil {
+			return Key{}, err
+		}
+	}
+	key.ThingID = id
+
+	if err := ts.things.SaveKey(ctx, key); err != nil {
+		return Key{}, err
+	}
+
+	return key, nil
+}
+
+func (ts *thingsService) RemoveKey(ctx context.Context, token, id, key string) error {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return err
+	}
+
+	if err := ts.authorize(ctx, res.GetId(), id, writeRelationKey); err != nil {
+		if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err != nil {
+			return err
+		}
+	}
+
+	if err := ts.things.RemoveKey(ctx, id, key); err != nil {
+		return err
+	}
+
+	return ts.thingCache.Remove(ctx, id)
 }
 
 func (ts *thingsService) ViewThing(ctx context.Context, token, id string) (Thing, error) {
@@ -284,7 +533,16 @@ func (ts *thingsService) ViewThing(ctx context.Context, token, id string) (Thing
 		}
 	}
 
-	return ts.things.RetrieveByID(ctx, res.GetEmail(), id)
+	th, err := ts.things.RetrieveByID(ctx, res.GetEmail(), id)
+	if err != nil {
+		return Thing{}, err
+	}
+
+	if th.Keys, err = ts.things.RetrieveKeys(ctx, id); err != nil {
+		return Thing{}, err
+	}
+
+	return th, nil
 }
 
 func (ts *thingsService) ListThings(ctx context.Context, token string, pm PageMetadata) (Page, error) {
@@ -357,6 +615,29 @@ func (ts *thingsService) RemoveThing(ctx context.Context, token, id string) erro
 	return ts.things.Remove(ctx, res.GetEmail(), id)
 }
 
+func (ts *thingsService) RestoreThing(ctx context.Context, token, id string) (Thing, error) {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Thing{}, err
+	}
+
+	if err := ts.authorize(ctx, res.GetId(), id, deleteRelationKey); err != nil {
+		if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err != nil {
+			return Thing{}, err
+		}
+	}
+
+	if err := ts.things.Restore(ctx, res.GetEmail(), id); err != nil {
+		return Thing{}, err
+	}
+
+	return ts.things.RetrieveByID(ctx, res.GetEmail(), id)
+}
+
+func (ts *thingsService) PurgeThings(ctx context.Context, before time.Time) ([]string, error) {
+	return ts.things.Purge(ctx, before)
+}
+
 func (ts *thingsService) CreateChannels(ctx context.Context, token string, channels ...Channel) ([]Channel, error) {
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
@@ -478,6 +759,29 @@ func (ts *thingsService) RemoveChannel(ctx context.Context, token, id string) er
 	return ts.channels.Remove(ctx, res.GetEmail(), id)
 }
 
+func (ts *thingsService) RestoreChannel(ctx context.Context, token, id string) (Channel, error) {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Channel{}, err
+	}
+
+	if err := ts.authorize(ctx, res.GetId(), id, deleteRelationKey); err != nil {
+		if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err != nil {
+			return Channel{}, err
+		}
+	}
+
+	if err := ts.channels.Restore(ctx, res.GetEmail(), id); err != nil {
+		return Channel{}, err
+	}
+
+	return ts.channels.RetrieveByID(ctx, res.GetEmail(), id)
+}
+
+func (ts *thingsService) PurgeChannels(ctx context.Context, before time.Time) ([]string, error) {
+	return ts.channels.Purge(ctx, before)
+}
+
 func (ts *thingsService) Connect(ctx context.Context, token string, chIDs, thIDs []string) error {
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
@@ -505,20 +809,12 @@ func (ts *thingsService) Disconnect(ctx context.Context, token string, chIDs, th
 }
 
 func (ts *thingsService) CanAccessByKey(ctx context.Context, chanID, thingKey string) (string, error) {
-	thingID, err := ts.hasThing(ctx, chanID, thingKey)
-	if err == nil {
-		return thingID, nil
-	}
-
-	thingID, err = ts.channels.HasThing(ctx, chanID, thingKey)
+	thingID, err := ts.Identify(ctx, thingKey)
 	if err != nil {
 		return "", err
 	}
 
-	if err := ts.thingCache.Save(ctx, thingKey, thingID); err != nil {
-		return "", err
-	}
-	if err := ts.channelCache.Connect(ctx, chanID, thingID); err != nil {
+	if err := ts.CanAccessByID(ctx, chanID, thingID); err != nil {
 		return "", err
 	}
 	return thingID, nil
@@ -546,33 +842,41 @@ func (ts *thingsService) IsChannelOwner(ctx context.Context, owner, chanID strin
 	return nil
 }
 
+func (ts *thingsService) IsChannelsOwner(ctx context.Context, owner string, chanIDs []string) error {
+	return ts.channels.IsOwner(ctx, owner, chanIDs)
+}
+
 func (ts *thingsService) Identify(ctx context.Context, key string) (string, error) {
 	id, err := ts.thingCache.ID(ctx, key)
 	if err == nil {
 		return id, nil
 	}
 
-	id, err = ts.things.RetrieveByKey(ctx, key)
+	k, err := ts.things.RetrieveByKey(ctx, key)
 	if err != nil {
 		return "", err
 	}
 
-	if err := ts.thingCache.Save(ctx, key, id); err != nil {
+	if err := ts.thingCache.Save(ctx, k); err != nil {
 		return "", err
 	}
-	return id, nil
+	return k.ThingID, nil
 }
 
-func (ts *thingsService) hasThing(ctx context.Context, chanID, thingKey string) (string, error) {
-	thingID, err := ts.thingCache.ID(ctx, thingKey)
+func (ts *thingsService) ViewThingByID(ctx context.Context, id string) (Thing, error) {
+	tp, err := ts.things.RetrieveByIDs(ctx, []string{id}, PageMetadata{Limit: 1})
 	if err != nil {
-		return "", err
+		return Thing{}, err
 	}
-
-	if connected := ts.channelCache.HasThing(ctx, chanID, thingID); !connected {
-		return "", errors.ErrAuthorization
+	if len(tp.Things) == 0 {
+		return Thing{}, errors.ErrNotFound
 	}
-	return thingID, nil
+
+	return tp.Things[0], nil
+}
+
+func (ts *thingsService) ViewChannelByID(ctx context.Context, id string) (Channel, error) {
+	return ts.channels.RetrieveByID(ctx, "", id)
 }
 
 func (ts *thingsService) ListMembers(ctx context.Context, token, groupID string, pm PageMetadata) (Page, error) {
@@ -588,6 +892,529 @@ func (ts *thingsService) ListMembers(ctx context.Context, token, groupID string,
 	return ts.things.RetrieveByIDs(ctx, res, pm)
 }
 
+func (ts *thingsService) UpdateQuota(ctx context.Context, token, owner string, q Quota) error {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return err
+	}
+
+	if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err != nil {
+		return err
+	}
+
+	return ts.quotas.Save(ctx, owner, q)
+}
+
+func (ts *thingsService) ViewQuota(ctx context.Context, token, owner string) (Quota, error) {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Quota{}, err
+	}
+
+	if res.GetEmail() != owner {
+		if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err != nil {
+			return Quota{}, err
+		}
+	}
+
+	return ts.ownerQuota(ctx, owner)
+}
+
+func (ts *thingsService) ViewThingQuota(ctx context.Context, id string) (ThingQuota, error) {
+	th, err := ts.ViewThingByID(ctx, id)
+	if err != nil {
+		return ThingQuota{}, err
+	}
+
+	oq, err := ts.ownerQuota(ctx, th.Owner)
+	if err != nil {
+		return ThingQuota{}, err
+	}
+
+	tq := ThingQuota{
+		Owner:      th.Owner,
+		Thing:      thingQuota(th.Metadata),
+		OwnerQuota: oq,
+	}
+	return tq, nil
+}
+
+// ownerQuota retrieves the owner quota. Owners without the quota are not limited.
+func (ts *thingsService) ownerQuota(ctx context.Context, owner string) (Quota, error) {
+	q, err := ts.quotas.RetrieveByOwner(ctx, owner)
+	if errors.Contains(err, errors.ErrNotFound) {
+		return Quota{}, nil
+	}
+	return q, err
+}
+
+func (ts *thingsService) ViewStatus(ctx context.Context, token, id string) (Status, error) {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Status{}, err
+	}
+
+	if err := ts.authorize(ctx, res.GetId(), id, readRelationKey); err != nil {
+		if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err != nil {
+			return Status{}, err
+		}
+	}
+
+	if _, err := ts.ViewThingByID(ctx, id); err != nil {
+		return Status{}, err
+	}
+
+	st, err := ts.statuses.RetrieveByThing(ctx, id)
+	if errors.Contains(err, errors.ErrNotFound) {
+		return Status{ThingID: id}, nil
+	}
+	if err != nil {
+		return Status{}, err
+	}
+	st.Online = st.Active(time.Now())
+
+	return st, nil
+}
+
+func (ts *thingsService) UpdateStatus(ctx context.Context, st Status) error {
+	return ts.statuses.Save(ctx, st)
+}
+
+func (ts *thingsService) CreateProfile(ctx context.Context, token string, p Profile) (Profile, error) {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Profile{}, err
+	}
+
+	p.Owner = res.GetEmail()
+	if err := ts.checkConnections(ctx, p); err != nil {
+		return Profile{}, err
+	}
+
+	if p.ID == "" {
+		if p.ID, err = ts.idProvider.ID(); err != nil {
+			return Profile{}, err
+		}
+	}
+
+	if err := ts.profiles.Save(ctx, p); err != nil {
+		return Profile{}, err
+	}
+
+	return p, nil
+}
+
+func (ts *thingsService) UpdateProfile(ctx context.Context, token string, p Profile) error {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return err
+	}
+
+	p.Owner = res.GetEmail()
+	if err := ts.checkConnections(ctx, p); err != nil {
+		return err
+	}
+
+	return ts.profiles.Update(ctx, p)
+}
+
+func (ts *thingsService) ViewProfile(ctx context.Context, token, id string) (Profile, error) {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Profile{}, err
+	}
+
+	return ts.profiles.RetrieveByID(ctx, res.GetEmail(), id)
+}
+
+func (ts *thingsService) ListProfiles(ctx context.Context, token string, pm PageMetadata) (ProfilesPage, error) {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ProfilesPage{}, err
+	}
+
+	return ts.profiles.RetrieveAll(ctx, res.GetEmail(), pm)
+}
+
+func (ts *thingsService) RemoveProfile(ctx context.Context, token, id string) error {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return err
+	}
+
+	return ts.profiles.Remove(ctx, res.GetEmail(), id)
+}
+
+// checkConnections verifies that the channels the things created from the
+// profile are connected to belong to the profile owner.
+func (ts *thingsService) checkConnections(ctx context.Context, p Profile) error {
+	for _, chID := range p.Connections {
+		ch, err := ts.channels.RetrieveByID(ctx, p.Owner, chID)
+		if errors.Contains(err, errors.ErrNotFound) {
+			return errors.Wrap(errors.ErrMalformedEntity, err)
+		}
+		if err != nil {
+			return err
+		}
+		if ch.Owner != p.Owner {
+			return errors.ErrMalformedEntity
+		}
+	}
+	return nil
+}
+
+func (ts *thingsService) ImportThings(ctx context.Context, token string, records []Record, dryRun bool) ([]ImportResult, error) {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ts.authorize(ctx, res.GetId(), usersObjectKey, memberRelationKey); err != nil {
+		return nil, err
+	}
+
+	ths, err := ts.ownedThings(ctx, res.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+	chs, err := ts.ownedChannels(ctx, res.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string]Thing{}
+	things := newMatcher()
+	for _, th := range ths {
+		existing[th.ID] = th
+		things.add(th.ID, th.Name, th.Metadata)
+	}
+	channels := newMatcher()
+	for _, ch := range chs {
+		channels.add(ch.ID, ch.Name, ch.Metadata)
+	}
+
+	results := make([]ImportResult, len(records))
+	for i, r := range records {
+		results[i] = ImportResult{Row: i + 1, Record: r}
+		if err := r.validate(); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		id, err := things.match(r)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		chIDs, err := channels.resolve(r.Connections)
+		if err != nil {
+			results[i].Err = errors.Wrap(errors.ErrMalformedEntity, err)
+			continue
+		}
+
+		if id == "" {
+			results[i].Action = ImportCreate
+			if dryRun {
+				things.add(pendingID(r, i), r.Name, r.Metadata)
+				continue
+			}
+			th, err := ts.importThing(ctx, r, chIDs, res)
+			if th.ID == "" {
+				results[i].Err = err
+				continue
+			}
+			existing[th.ID] = th
+			things.add(th.ID, th.Name, th.Metadata)
+			if err != nil {
+				results[i].Record = thingRecord(th, nil)
+				results[i].Err = err
+				results[i].Partial = true
+				continue
+			}
+			results[i].Record = thingRecord(th, chIDs)
+			continue
+		}
+
+		results[i].Action = ImportUpdate
+		th, ok := existing[id]
+		if !ok {
+			// The record matches the thing created by the preceding
+			// record of the dry run.
+			continue
+		}
+		results[i].Record.ID = id
+		if dryRun {
+			continue
+		}
+		th, chIDs, err = ts.updateImportedThing(ctx, th, r, chIDs)
+		if th.ID == "" {
+			results[i].Err = err
+			continue
+		}
+		existing[th.ID] = th
+		results[i].Record = thingRecord(th, chIDs)
+		if err != nil {
+			results[i].Err = err
+			results[i].Partial = true
+		}
+	}
+
+	return results, nil
+}
+
+// importThing creates the thing from the imported record and connects it to
+// the listed channels. If the connection fails, it returns the created thing
+// along with the error.
+func (ts *thingsService) importThing(ctx context.Context, r Record, chIDs []string, identity *mainflux.UserIdentity) (Thing, error) {
+	th := Thing{
+		ID:       r.ID,
+		Name:     r.Name,
+		Key:      r.Key,
+		Metadata: r.Metadata,
+	}
+	th, err := ts.createThing(ctx, &th, identity)
+	if err != nil {
+		return Thing{}, err
+	}
+
+	if len(chIDs) == 0 {
+		return th, nil
+	}
+	if err := ts.channels.Connect(ctx, th.Owner, chIDs, []string{th.ID}); err != nil {
+		return th, err
+	}
+
+	return th, nil
+}
+
+// updateImportedThing updates the thing from the imported record, and
+// connects it to the listed channels it's not connected to yet. It returns
+// the updated thing and the IDs of the newly connected channels. If the
+// connection fails, it returns the updated thing along with the error.
+func (ts *thingsService) updateImportedThing(ctx context.Context, th Thing, r Record, chIDs []string) (Thing, []string, error) {
+	if r.Name != "" {
+		th.Name = r.Name
+	}
+	if r.Metadata != nil {
+		th.Metadata = r.Metadata
+	}
+	if err := ts.things.Update(ctx, th); err != nil {
+		return Thing{}, nil, err
+	}
+
+	if r.Key != "" && r.Key != th.Key {
+		if err := ts.things.UpdateKey(ctx, th.Owner, th.ID, r.Key); err != nil {
+			return Thing{}, nil, err
+		}
+		if err := ts.thingCache.Remove(ctx, th.ID); err != nil {
+			return Thing{}, nil, err
+		}
+		th.Key = r.Key
+	}
+
+	if len(chIDs) == 0 {
+		return th, nil, nil
+	}
+
+	connected, err := ts.connectedChannels(ctx, th.Owner, th.ID)
+	if err != nil {
+		return th, nil, err
+	}
+	conns := map[string]bool{}
+	for _, chID := range connected {
+		conns[chID] = true
+	}
+	var missing []string
+	for _, chID := range chIDs {
+		if !conns[chID] {
+			missing = append(missing, chID)
+		}
+	}
+
+	if len(missing) == 0 {
+		return th, nil, nil
+	}
+	if err := ts.channels.Connect(ctx, th.Owner, missing, []string{th.ID}); err != nil {
+		return th, nil, err
+	}
+
+	return th, missing, nil
+}
+
+func (ts *thingsService) ImportChannels(ctx context.Context, token string, records []Record, dryRun bool) ([]ImportResult, error) {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ts.authorize(ctx, res.GetId(), usersObjectKey, memberRelationKey); err != nil {
+		return nil, err
+	}
+
+	chs, err := ts.ownedChannels(ctx, res.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string]Channel{}
+	channels := newMatcher()
+	for _, ch := range chs {
+		existing[ch.ID] = ch
+		channels.add(ch.ID, ch.Name, ch.Metadata)
+	}
+
+	results := make([]ImportResult, len(records))
+	for i, r := range records {
+		results[i] = ImportResult{Row: i + 1, Record: r}
+		if err := r.validate(); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		id, err := channels.match(r)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		if id == "" {
+			results[i].Action = ImportCreate
+			if dryRun {
+				channels.add(pendingID(r, i), r.Name, r.Metadata)
+				continue
+			}
+			ch := Channel{
+				ID:       r.ID,
+				Name:     r.Name,
+				Metadata: r.Metadata,
+			}
+			ch, err := ts.createChannel(ctx, &ch, res)
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			existing[ch.ID] = ch
+			channels.add(ch.ID, ch.Name, ch.Metadata)
+			results[i].Record = channelRecord(ch)
+			continue
+		}
+
+		results[i].Action = ImportUpdate
+		ch, ok := existing[id]
+		if !ok {
+			continue
+		}
+		results[i].Record.ID = id
+		if dryRun {
+			continue
+		}
+		if r.Name != "" {
+			ch.Name = r.Name
+		}
+		if r.Metadata != nil {
+			ch.Metadata = r.Metadata
+		}
+		if err := ts.channels.Update(ctx, ch); err != nil {
+			results[i].Err = err
+			continue
+		}
+		existing[ch.ID] = ch
+		results[i].Record = channelRecord(ch)
+	}
+
+	return results, nil
+}
+
+func (ts *thingsService) ExportThings(ctx context.Context, token string) ([]Record, error) {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, err
+	}
+
+	ths, err := ts.ownedThings(ctx, res.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+
+	records := []Record{}
+	for _, th := range ths {
+		chIDs, err := ts.connectedChannels(ctx, res.GetEmail(), th.ID)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, thingRecord(th, chIDs))
+	}
+
+	return records, nil
+}
+
+func (ts *thingsService) ExportChannels(ctx context.Context, token string) ([]Record, error) {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, err
+	}
+
+	chs, err := ts.ownedChannels(ctx, res.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+
+	records := []Record{}
+	for _, ch := range chs {
+		records = append(records, channelRecord(ch))
+	}
+
+	return records, nil
+}
+
+// ownedThings retrieves all the things of the owner.
+func (ts *thingsService) ownedThings(ctx context.Context, owner string) ([]Thing, error) {
+	var ths []Thing
+	for offset := uint64(0); ; offset += bulkLimit {
+		page, err := ts.things.RetrieveAll(ctx, owner, PageMetadata{Offset: offset, Limit: bulkLimit})
+		if err != nil {
+			return nil, err
+		}
+		ths = append(ths, page.Things...)
+		if offset+bulkLimit >= page.Total {
+			return ths, nil
+		}
+	}
+}
+
+// ownedChannels retrieves all the channels of the owner.
+func (ts *thingsService) ownedChannels(ctx context.Context, owner string) ([]Channel, error) {
+	var chs []Channel
+	for offset := uint64(0); ; offset += bulkLimit {
+		page, err := ts.channels.RetrieveAll(ctx, owner, PageMetadata{Offset: offset, Limit: bulkLimit})
+		if err != nil {
+			return nil, err
+		}
+		chs = append(chs, page.Channels...)
+		if offset+bulkLimit >= page.Total {
+			return chs, nil
+		}
+	}
+}
+
+// connectedChannels retrieves the IDs of the channels the thing is connected
+// to.
+func (ts *thingsService) connectedChannels(ctx context.Context, owner, thID string) ([]string, error) {
+	var ids []string
+	for offset := uint64(0); ; offset += bulkLimit {
+		page, err := ts.channels.RetrieveByThing(ctx, owner, thID, PageMetadata{Offset: offset, Limit: bulkLimit})
+		if err != nil {
+			return nil, err
+		}
+		for _, ch := range page.Channels {
+			ids = append(ids, ch.ID)
+		}
+		if offset+bulkLimit >= page.Total {
+			return ids, nil
+		}
+	}
+}
+
 func (ts *thingsService) members(ctx context.Context, token, groupID, groupType string, limit, offset uint64) ([]string, error) {
 	req := mainflux.MembersReq{
 		Token:   token,
@@ -45,11 +45,14 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	profilesRepo := mocks.NewProfileRepository()
+	quotasRepo := mocks.NewQuotaRepository()
+	statusRepo := mocks.NewStatusRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idProvider := uuid.NewMock()
 
-	return things.New(auth, thingsRepo, channelsRepo, chanCache, thingCache, idProvider)
+	return things.New(auth, thingsRepo, channelsRepo, profilesRepo, quotasRepo, statusRepo, chanCache, thingCache, idProvider)
 }
 
 func TestInit(t *testing.T) {
@@ -183,6 +186,209 @@ func TestUpdateKey(t *testing.T) {
 	}
 }
 
+func TestRotateKey(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ths, err := svc.CreateThings(context.Background(), token, thingList[0], thingList[1])
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	th, th2 := ths[0], ths[1]
+
+	cases := []struct {
+		desc     string
+		token    string
+		id       string
+		key      string
+		grace    time.Duration
+		old      string
+		oldValid bool
+		err      error
+	}{
+		{
+			desc:     "rotate key of an existing thing with grace period",
+			token:    token,
+			id:       th.ID,
+			key:      "rotated-key",
+			grace:    time.Hour,
+			old:      th.Key,
+			oldValid: true,
+			err:      nil,
+		},
+		{
+			desc:     "rotate key of an existing thing without grace period",
+			token:    token,
+			id:       th2.ID,
+			key:      "",
+			old:      th2.Key,
+			oldValid: false,
+			err:      nil,
+		},
+		{
+			desc:  "rotate key to the existing key",
+			token: token,
+			id:    th2.ID,
+			key:   th.Key,
+			err:   errors.ErrConflict,
+		},
+		{
+			desc:  "rotate key with invalid credentials",
+			token: wrongValue,
+			id:    th.ID,
+			err:   errors.ErrAuthentication,
+		},
+		{
+			desc:  "rotate key of non-existing thing",
+			token: token,
+			id:    wrongID,
+			err:   errors.ErrAuthorization,
+		},
+	}
+
+	for _, tc := range cases {
+		// Cache the current key to ensure that the rotation invalidates it.
+		svc.Identify(context.Background(), tc.old)
+
+		key, err := svc.RotateKey(context.Background(), tc.token, tc.id, tc.key, tc.grace)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		if tc.key != "" {
+			assert.Equal(t, tc.key, key, fmt.Sprintf("%s: expected key %s got %s\n", tc.desc, tc.key, key))
+		}
+
+		id, err := svc.Identify(context.Background(), key)
+		assert.Nil(t, err, fmt.Sprintf("%s: identify by new key: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.id, id, fmt.Sprintf("%s: identify by new key: expected %s got %s\n", tc.desc, tc.id, id))
+
+		_, err = svc.Identify(context.Background(), tc.old)
+		assert.Equal(t, tc.oldValid, err == nil, fmt.Sprintf("%s: identify by old key: expected valid %t got error %s\n", tc.desc, tc.oldValid, err))
+	}
+}
+
+func TestAddKey(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ths, err := svc.CreateThings(context.Background(), token, thingList[0])
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	th := ths[0]
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		key   things.Key
+		err   error
+	}{
+		{
+			desc:  "add labeled key to an existing thing",
+			token: token,
+			id:    th.ID,
+			key:   things.Key{Value: "additional-key", Label: "backup"},
+			err:   nil,
+		},
+		{
+			desc:  "add generated expiring key to an existing thing",
+			token: token,
+			id:    th.ID,
+			key:   things.Key{Label: "temporary", ExpiresAt: time.Now().Add(time.Hour)},
+			err:   nil,
+		},
+		{
+			desc:  "add existing key to an existing thing",
+			token: token,
+			id:    th.ID,
+			key:   things.Key{Value: th.Key},
+			err:   errors.ErrConflict,
+		},
+		{
+			desc:  "add key with invalid credentials",
+			token: wrongValue,
+			id:    th.ID,
+			key:   things.Key{Value: "other-key"},
+			err:   errors.ErrAuthentication,
+		},
+		{
+			desc:  "add key to non-existing thing",
+			token: token,
+			id:    wrongID,
+			key:   things.Key{Value: "other-key"},
+			err:   errors.ErrAuthorization,
+		},
+	}
+
+	for _, tc := range cases {
+		key, err := svc.AddKey(context.Background(), tc.token, tc.id, tc.key)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		assert.NotEmpty(t, key.Value, fmt.Sprintf("%s: expected non-empty key\n", tc.desc))
+
+		id, err := svc.Identify(context.Background(), key.Value)
+		assert.Nil(t, err, fmt.Sprintf("%s: identify by added key: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.id, id, fmt.Sprintf("%s: identify by added key: expected %s got %s\n", tc.desc, tc.id, id))
+	}
+
+	saved, err := svc.ViewThing(context.Background(), token, th.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Len(t, saved.Keys, 2, fmt.Sprintf("view thing: expected 2 additional keys got %d\n", len(saved.Keys)))
+}
+
+func TestRemoveKey(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ths, err := svc.CreateThings(context.Background(), token, thingList[0])
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	th := ths[0]
+	key, err := svc.AddKey(context.Background(), token, th.ID, things.Key{Label: "backup"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		key   string
+		err   error
+	}{
+		{
+			desc:  "remove key with invalid credentials",
+			token: wrongValue,
+			id:    th.ID,
+			key:   key.Value,
+			err:   errors.ErrAuthentication,
+		},
+		{
+			desc:  "remove key of an existing thing",
+			token: token,
+			id:    th.ID,
+			key:   key.Value,
+			err:   nil,
+		},
+		{
+			desc:  "remove removed key of an existing thing",
+			token: token,
+			id:    th.ID,
+			key:   key.Value,
+			err:   errors.ErrNotFound,
+		},
+		{
+			desc:  "remove primary key of an existing thing",
+			token: token,
+			id:    th.ID,
+			key:   th.Key,
+			err:   errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		// Cache the key to ensure that the removal invalidates it.
+		svc.Identify(context.Background(), tc.key)
+
+		err := svc.RemoveKey(context.Background(), tc.token, tc.id, tc.key)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.Identify(context.Background(), key.Value)
+	assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("identify by removed key: expected %s got %s\n", errors.ErrNotFound, err))
+}
+
 func TestShareThing(t *testing.T) {
 	svc := newService(map[string]string{token: email, token2: email2})
 	ths, err := svc.CreateThings(context.Background(), token, thingList[0])
@@ -619,6 +825,103 @@ func TestRemoveThing(t *testing.T) {
 	}
 }
 
+func TestRestoreThing(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ths, err := svc.CreateThings(context.Background(), token, things.Thing{Name: "restored"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	sth := ths[0]
+	err = svc.RemoveThing(context.Background(), token, sth.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "restore thing with wrong credentials",
+			id:    sth.ID,
+			token: wrongValue,
+			err:   errors.ErrAuthentication,
+		},
+		{
+			desc:  "restore removed thing",
+			id:    sth.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "restore restored thing",
+			id:    sth.ID,
+			token: token,
+			err:   errors.ErrNotFound,
+		},
+		{
+			desc:  "restore non-existing thing",
+			id:    wrongID,
+			token: token,
+			err:   errors.ErrAuthorization,
+		},
+	}
+
+	for _, tc := range cases {
+		th, err := svc.RestoreThing(context.Background(), tc.token, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, sth.ID, th.ID, fmt.Sprintf("%s: expected id %s got %s\n", tc.desc, sth.ID, th.ID))
+		}
+	}
+
+	_, err = svc.ViewThing(context.Background(), token, sth.ID)
+	assert.Nil(t, err, fmt.Sprintf("view restored thing: unexpected error: %s\n", err))
+}
+
+func TestPurgeThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ths, err := svc.CreateThings(context.Background(), token, things.Thing{Name: "purged"}, things.Thing{Name: "kept"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.RemoveThing(context.Background(), token, ths[0].ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	page, err := svc.ListThings(context.Background(), token, things.PageMetadata{Limit: n, Deleted: true})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	require.Len(t, page.Things, 1, "expected one removed thing")
+	assert.Equal(t, ths[0].ID, page.Things[0].ID, fmt.Sprintf("expected removed thing %s got %s\n", ths[0].ID, page.Things[0].ID))
+	assert.False(t, page.Things[0].DeletedAt.IsZero(), "expected removed thing to have the removal time")
+
+	cases := []struct {
+		desc   string
+		before time.Time
+		ids    []string
+	}{
+		{
+			desc:   "purge things removed before the removal",
+			before: time.Now().Add(-time.Hour),
+			ids:    nil,
+		},
+		{
+			desc:   "purge things removed after the removal",
+			before: time.Now().Add(time.Hour),
+			ids:    []string{ths[0].ID},
+		},
+		{
+			desc:   "purge purged things",
+			before: time.Now().Add(time.Hour),
+			ids:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		ids, err := svc.PurgeThings(context.Background(), tc.before)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.ElementsMatch(t, tc.ids, ids, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.ids, ids))
+	}
+
+	_, err = svc.ViewThing(context.Background(), token, ths[1].ID)
+	assert.Nil(t, err, fmt.Sprintf("view active thing: unexpected error: %s\n", err))
+}
+
 func TestCreateChannels(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 
@@ -1106,6 +1409,96 @@ func TestRemoveChannel(t *testing.T) {
 	}
 }
 
+func TestRestoreChannel(t *testing.T) {
+	svc := newService(map[string]string{token: adminEmail})
+	chs, err := svc.CreateChannels(context.Background(), token, things.Channel{Name: "restored"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch := chs[0]
+	err = svc.RemoveChannel(context.Background(), token, ch.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "restore channel with wrong credentials",
+			id:    ch.ID,
+			token: wrongValue,
+			err:   errors.ErrAuthentication,
+		},
+		{
+			desc:  "restore removed channel",
+			id:    ch.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "restore restored channel",
+			id:    ch.ID,
+			token: token,
+			err:   errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		c, err := svc.RestoreChannel(context.Background(), tc.token, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, ch.ID, c.ID, fmt.Sprintf("%s: expected id %s got %s\n", tc.desc, ch.ID, c.ID))
+		}
+	}
+
+	_, err = svc.ViewChannel(context.Background(), token, ch.ID)
+	assert.Nil(t, err, fmt.Sprintf("view restored channel: unexpected error: %s\n", err))
+}
+
+func TestPurgeChannels(t *testing.T) {
+	svc := newService(map[string]string{token: adminEmail})
+	chs, err := svc.CreateChannels(context.Background(), token, things.Channel{Name: "purged"}, things.Channel{Name: "kept"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.RemoveChannel(context.Background(), token, chs[0].ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	page, err := svc.ListChannels(context.Background(), token, things.PageMetadata{Limit: n, Deleted: true})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	require.Len(t, page.Channels, 1, "expected one removed channel")
+	assert.Equal(t, chs[0].ID, page.Channels[0].ID, fmt.Sprintf("expected removed channel %s got %s\n", chs[0].ID, page.Channels[0].ID))
+
+	cases := []struct {
+		desc   string
+		before time.Time
+		ids    []string
+	}{
+		{
+			desc:   "purge channels removed before the removal",
+			before: time.Now().Add(-time.Hour),
+			ids:    nil,
+		},
+		{
+			desc:   "purge channels removed after the removal",
+			before: time.Now().Add(time.Hour),
+			ids:    []string{chs[0].ID},
+		},
+		{
+			desc:   "purge purged channels",
+			before: time.Now().Add(time.Hour),
+			ids:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		ids, err := svc.PurgeChannels(context.Background(), tc.before)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.ElementsMatch(t, tc.ids, ids, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.ids, ids))
+	}
+
+	_, err = svc.ViewChannel(context.Background(), token, chs[1].ID)
+	assert.Nil(t, err, fmt.Sprintf("view active channel: unexpected error: %s\n", err))
+}
+
 func TestConnect(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 
@@ -1344,12 +1737,46 @@ func TestIsChannelOwner(t *testing.T) {
 	}
 }
 
-func TestIdentify(t *testing.T) {
-	svc := newService(map[string]string{token: email})
+func TestIsChannelsOwner(t *testing.T) {
+	svc := newService(map[string]string{token: email, token2: "john.doe@email.net"})
 
-	ths, err := svc.CreateThings(context.Background(), token, thingList[0])
+	chs, err := svc.CreateChannels(context.Background(), token, channel, channel)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
-	th := ths[0]
+	owned := []string{chs[0].ID, chs[1].ID}
+	chs, err = svc.CreateChannels(context.Background(), token2, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	nonOwnedCh := chs[0]
+
+	cases := map[string]struct {
+		channels []string
+		err      error
+	}{
+		"user owns channels": {
+			channels: owned,
+			err:      nil,
+		},
+		"user does not own one of channels": {
+			channels: append([]string{nonOwnedCh.ID}, owned...),
+			err:      errors.ErrNotFound,
+		},
+		"access to non-existing channel": {
+			channels: append(owned, wrongID),
+			err:      errors.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		err := svc.IsChannelsOwner(context.Background(), email, tc.channels)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestIdentify(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	ths, err := svc.CreateThings(context.Background(), token, thingList[0])
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	th := ths[0]
 
 	cases := map[string]struct {
 		token string
@@ -1375,6 +1802,68 @@ func TestIdentify(t *testing.T) {
 	}
 }
 
+func TestViewThingByID(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	ths, err := svc.CreateThings(context.Background(), token, thingList[0])
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	th := ths[0]
+
+	cases := map[string]struct {
+		id    string
+		thing things.Thing
+		err   error
+	}{
+		"view existing thing": {
+			id:    th.ID,
+			thing: th,
+			err:   nil,
+		},
+		"view non-existing thing": {
+			id:    wrongID,
+			thing: things.Thing{},
+			err:   errors.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		thing, err := svc.ViewThingByID(context.Background(), tc.id)
+		assert.Equal(t, tc.thing, thing, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.thing, thing))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestViewChannelByID(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	chs, err := svc.CreateChannels(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch := chs[0]
+
+	cases := map[string]struct {
+		id      string
+		channel things.Channel
+		err     error
+	}{
+		"view existing channel": {
+			id:      ch.ID,
+			channel: ch,
+			err:     nil,
+		},
+		"view non-existing channel": {
+			id:      wrongID,
+			channel: things.Channel{},
+			err:     errors.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		channel, err := svc.ViewChannelByID(context.Background(), tc.id)
+		assert.Equal(t, tc.channel, channel, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.channel, channel))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
 func testSortThings(t *testing.T, pm things.PageMetadata, ths []things.Thing) {
 	switch pm.Order {
 	case "name":
@@ -1410,3 +1899,767 @@ func testSortChannels(t *testing.T, pm things.PageMetadata, chs []things.Channel
 		break
 	}
 }
+
+func TestUpdateQuota(t *testing.T) {
+	svc := newService(map[string]string{token: email, token2: adminEmail})
+
+	q := things.Quota{Messages: 60, PayloadSize: 1024}
+
+	cases := map[string]struct {
+		token string
+		owner string
+		err   error
+	}{
+		"update quota as admin": {
+			token: token2,
+			owner: email,
+			err:   nil,
+		},
+		"update quota as non-admin": {
+			token: token,
+			owner: email,
+			err:   errors.ErrAuthorization,
+		},
+		"update quota with wrong credentials": {
+			token: wrongValue,
+			owner: email,
+			err:   errors.ErrAuthentication,
+		},
+	}
+
+	for desc, tc := range cases {
+		err := svc.UpdateQuota(context.Background(), tc.token, tc.owner, q)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestViewQuota(t *testing.T) {
+	svc := newService(map[string]string{token: email, token2: adminEmail, "token3": email2})
+
+	q := things.Quota{Messages: 60, PayloadSize: 1024}
+	err := svc.UpdateQuota(context.Background(), token2, email, q)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := map[string]struct {
+		token string
+		owner string
+		quota things.Quota
+		err   error
+	}{
+		"view own quota": {
+			token: token,
+			owner: email,
+			quota: q,
+			err:   nil,
+		},
+		"view quota as admin": {
+			token: token2,
+			owner: email,
+			quota: q,
+			err:   nil,
+		},
+		"view quota that is not set": {
+			token: token2,
+			owner: email2,
+			quota: things.Quota{},
+			err:   nil,
+		},
+		"view quota of another owner": {
+			token: "token3",
+			owner: email,
+			quota: things.Quota{},
+			err:   errors.ErrAuthorization,
+		},
+		"view quota with wrong credentials": {
+			token: wrongValue,
+			owner: email,
+			quota: things.Quota{},
+			err:   errors.ErrAuthentication,
+		},
+	}
+
+	for desc, tc := range cases {
+		quota, err := svc.ViewQuota(context.Background(), tc.token, tc.owner)
+		assert.Equal(t, tc.quota, quota, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.quota, quota))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestViewThingQuota(t *testing.T) {
+	svc := newService(map[string]string{token: email, token2: adminEmail})
+
+	th := thingList[0]
+	th.Metadata = things.Metadata{"quota": map[string]interface{}{"messages_per_minute": float64(10)}}
+	ths, err := svc.CreateThings(context.Background(), token, th, thingList[1])
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	oq := things.Quota{Messages: 60, PayloadSize: 1024}
+	err = svc.UpdateQuota(context.Background(), token2, email, oq)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := map[string]struct {
+		id    string
+		quota things.ThingQuota
+		err   error
+	}{
+		"view quota of thing with quota": {
+			id:    ths[0].ID,
+			quota: things.ThingQuota{Owner: email, Thing: things.Quota{Messages: 10}, OwnerQuota: oq},
+			err:   nil,
+		},
+		"view quota of thing without quota": {
+			id:    ths[1].ID,
+			quota: things.ThingQuota{Owner: email, OwnerQuota: oq},
+			err:   nil,
+		},
+		"view quota of non-existing thing": {
+			id:    wrongID,
+			quota: things.ThingQuota{},
+			err:   errors.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		quota, err := svc.ViewThingQuota(context.Background(), tc.id)
+		assert.Equal(t, tc.quota, quota, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.quota, quota))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestViewStatus(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	ths, err := svc.CreateThings(context.Background(), token, thingList[0], thingList[1], thingList[2], thingList[3])
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	now := time.Now().Round(0)
+	connected := things.Status{ThingID: ths[0].ID, Online: true, LastSeen: now, Protocol: "mqtt", ClientIP: "10.0.0.1"}
+	active := things.Status{ThingID: ths[1].ID, Online: true, LastSeen: now, Protocol: "http", Expires: now.Add(time.Minute)}
+	expired := things.Status{ThingID: ths[2].ID, Online: true, LastSeen: now.Add(-time.Hour), Protocol: "coap", Expires: now.Add(-time.Minute)}
+	for _, st := range []things.Status{connected, active, expired} {
+		err := svc.UpdateStatus(context.Background(), st)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	// The stale status doesn't replace the more recent one.
+	stale := things.Status{ThingID: ths[0].ID, LastSeen: now.Add(-time.Minute), Protocol: "mqtt"}
+	err = svc.UpdateStatus(context.Background(), stale)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	expiredOffline := expired
+	expiredOffline.Online = false
+
+	cases := map[string]struct {
+		token  string
+		id     string
+		status things.Status
+		err    error
+	}{
+		"view status of connected thing": {
+			token:  token,
+			id:     ths[0].ID,
+			status: connected,
+			err:    nil,
+		},
+		"view status of recently active thing": {
+			token:  token,
+			id:     ths[1].ID,
+			status: active,
+			err:    nil,
+		},
+		"view status of thing whose activity expired": {
+			token:  token,
+			id:     ths[2].ID,
+			status: expiredOffline,
+			err:    nil,
+		},
+		"view status of never seen thing": {
+			token:  token,
+			id:     ths[3].ID,
+			status: things.Status{ThingID: ths[3].ID},
+			err:    nil,
+		},
+		"view status of non-existing thing": {
+			token:  token,
+			id:     wrongID,
+			status: things.Status{},
+			err:    errors.ErrAuthorization,
+		},
+		"view status with wrong credentials": {
+			token:  wrongValue,
+			id:     ths[0].ID,
+			status: things.Status{},
+			err:    errors.ErrAuthentication,
+		},
+	}
+
+	for desc, tc := range cases {
+		st, err := svc.ViewStatus(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.status, st, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.status, st))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestCreateThingsFromProfile(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	chs, err := svc.CreateChannels(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch := chs[0]
+
+	p := things.Profile{
+		Name:     "thermometer",
+		Metadata: things.Metadata{"model": "t1", "firmware": "1.0"},
+		Channels: []things.ProfileChannel{
+			{Name: "telemetry", Subtopics: []string{"temperature"}},
+		},
+		Connections: []string{ch.ID},
+	}
+	p, err = svc.CreateProfile(context.Background(), token, p)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc     string
+		thing    things.Thing
+		token    string
+		metadata things.Metadata
+		err      error
+	}{
+		{
+			desc:     "create thing from profile",
+			thing:    things.Thing{Name: "a", ProfileID: p.ID, Metadata: things.Metadata{"firmware": "1.1"}},
+			token:    token,
+			metadata: things.Metadata{"model": "t1", "firmware": "1.1"},
+			err:      nil,
+		},
+		{
+			desc:  "create thing from non-existing profile",
+			thing: things.Thing{Name: "b", ProfileID: wrongValue},
+			token: token,
+			err:   errors.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		ths, err := svc.CreateThings(context.Background(), tc.token, tc.thing)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		th := ths[0]
+		assert.Equal(t, tc.metadata, th.Metadata, fmt.Sprintf("%s: expected metadata %v got %v\n", tc.desc, tc.metadata, th.Metadata))
+
+		page, err := svc.ListChannelsByThing(context.Background(), tc.token, th.ID, things.PageMetadata{Offset: 0, Limit: n})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		names := map[string]bool{}
+		for _, c := range page.Channels {
+			names[c.Name] = true
+		}
+		assert.True(t, names[ch.Name], fmt.Sprintf("%s: expected connection to %s\n", tc.desc, ch.Name))
+		assert.True(t, names[th.Name+"-telemetry"], fmt.Sprintf("%s: expected profile channel %s-telemetry\n", tc.desc, th.Name))
+	}
+}
+
+func TestCreateProfile(t *testing.T) {
+	svc := newService(map[string]string{token: email, token2: email2})
+	chs, err := svc.CreateChannels(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc    string
+		profile things.Profile
+		token   string
+		err     error
+	}{
+		{
+			desc:    "create new profile",
+			profile: things.Profile{Name: "a", Connections: []string{chs[0].ID}},
+			token:   token,
+			err:     nil,
+		},
+		{
+			desc:    "create profile with wrong credentials",
+			profile: things.Profile{Name: "b"},
+			token:   wrongValue,
+			err:     errors.ErrAuthentication,
+		},
+		{
+			desc:    "create profile connected to non-existing channel",
+			profile: things.Profile{Name: "c", Connections: []string{wrongValue}},
+			token:   token,
+			err:     errors.ErrMalformedEntity,
+		},
+		{
+			desc:    "create profile connected to channel of other user",
+			profile: things.Profile{Name: "d", Connections: []string{chs[0].ID}},
+			token:   token2,
+			err:     errors.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.CreateProfile(context.Background(), tc.token, tc.profile)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	svc := newService(map[string]string{token: email, token2: email2})
+	p, err := svc.CreateProfile(context.Background(), token, things.Profile{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc    string
+		profile things.Profile
+		token   string
+		err     error
+	}{
+		{
+			desc:    "update existing profile",
+			profile: things.Profile{ID: p.ID, Name: "b"},
+			token:   token,
+			err:     nil,
+		},
+		{
+			desc:    "update profile with wrong credentials",
+			profile: things.Profile{ID: p.ID, Name: "b"},
+			token:   wrongValue,
+			err:     errors.ErrAuthentication,
+		},
+		{
+			desc:    "update profile of other user",
+			profile: things.Profile{ID: p.ID, Name: "b"},
+			token:   token2,
+			err:     errors.ErrNotFound,
+		},
+		{
+			desc:    "update non-existing profile",
+			profile: things.Profile{ID: wrongID, Name: "b"},
+			token:   token,
+			err:     errors.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UpdateProfile(context.Background(), tc.token, tc.profile)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestViewProfile(t *testing.T) {
+	svc := newService(map[string]string{token: email, token2: email2})
+	p, err := svc.CreateProfile(context.Background(), token, things.Profile{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := map[string]struct {
+		id    string
+		token string
+		err   error
+	}{
+		"view existing profile": {
+			id:    p.ID,
+			token: token,
+			err:   nil,
+		},
+		"view profile with wrong credentials": {
+			id:    p.ID,
+			token: wrongValue,
+			err:   errors.ErrAuthentication,
+		},
+		"view profile of other user": {
+			id:    p.ID,
+			token: token2,
+			err:   errors.ErrNotFound,
+		},
+		"view non-existing profile": {
+			id:    wrongID,
+			token: token,
+			err:   errors.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		_, err := svc.ViewProfile(context.Background(), tc.token, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestListProfiles(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	for i := uint64(0); i < n; i++ {
+		_, err := svc.CreateProfile(context.Background(), token, things.Profile{Name: fmt.Sprintf("profile-%d", i)})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := map[string]struct {
+		token  string
+		offset uint64
+		limit  uint64
+		size   uint64
+		err    error
+	}{
+		"list all profiles": {
+			token:  token,
+			offset: 0,
+			limit:  n,
+			size:   n,
+			err:    nil,
+		},
+		"list last profile": {
+			token:  token,
+			offset: n - 1,
+			limit:  n,
+			size:   1,
+			err:    nil,
+		},
+		"list profiles with wrong credentials": {
+			token:  wrongValue,
+			offset: 0,
+			limit:  n,
+			size:   0,
+			err:    errors.ErrAuthentication,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := svc.ListProfiles(context.Background(), tc.token, things.PageMetadata{Offset: tc.offset, Limit: tc.limit})
+		size := uint64(len(page.Profiles))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestRemoveProfile(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	p, err := svc.CreateProfile(context.Background(), token, things.Profile{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	_, err = svc.CreateThings(context.Background(), token, things.Thing{Name: "a", ProfileID: p.ID})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "remove profile with wrong credentials",
+			id:    p.ID,
+			token: wrongValue,
+			err:   errors.ErrAuthentication,
+		},
+		{
+			desc:  "remove existing profile",
+			id:    p.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "remove removed profile",
+			id:    p.ID,
+			token: token,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveProfile(context.Background(), tc.token, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.ViewProfile(context.Background(), token, p.ID)
+	assert.True(t, errors.Contains(err, errors.ErrNotFound), fmt.Sprintf("view removed profile: expected %s got %s\n", errors.ErrNotFound, err))
+}
+
+func TestImportThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	chs, err := svc.CreateChannels(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch := chs[0]
+
+	existing := things.Thing{Name: "existing", Metadata: things.Metadata{things.ExternalIDKey: "ext-1"}}
+	ths, err := svc.CreateThings(context.Background(), token, existing, things.Thing{Name: "twin"}, things.Thing{Name: "twin"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	existing = ths[0]
+
+	records := []things.Record{
+		{Name: "new", Connections: []string{ch.Name}},
+		{Name: "renamed", Metadata: things.Metadata{things.ExternalIDKey: "ext-1", "model": "t1"}},
+		{Name: "twin"},
+		{Name: "unknown", Connections: []string{wrongValue}},
+		{ID: wrongValue, Name: "invalid"},
+		{Name: "new", Key: "new-key", Connections: []string{ch.ID}},
+	}
+
+	cases := []struct {
+		desc    string
+		token   string
+		records []things.Record
+		dryRun  bool
+		actions []string
+		errs    []error
+		err     error
+	}{
+		{
+			desc:    "import things with dry run",
+			token:   token,
+			records: records,
+			dryRun:  true,
+			actions: []string{things.ImportCreate, things.ImportUpdate, "", "", "", things.ImportUpdate},
+			errs:    []error{nil, nil, things.ErrAmbiguousMatch, errors.ErrMalformedEntity, errors.ErrMalformedEntity, nil},
+			err:     nil,
+		},
+		{
+			desc:    "import things",
+			token:   token,
+			records: records,
+			actions: []string{things.ImportCreate, things.ImportUpdate, "", "", "", things.ImportUpdate},
+			errs:    []error{nil, nil, things.ErrAmbiguousMatch, errors.ErrMalformedEntity, errors.ErrMalformedEntity, nil},
+			err:     nil,
+		},
+		{
+			desc:    "import things with wrong credentials",
+			token:   wrongValue,
+			records: records,
+			err:     errors.ErrAuthentication,
+		},
+	}
+
+	for _, tc := range cases {
+		results, err := svc.ImportThings(context.Background(), tc.token, tc.records, tc.dryRun)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		require.Len(t, results, len(tc.records), fmt.Sprintf("%s: expected %d results got %d\n", tc.desc, len(tc.records), len(results)))
+		for i, res := range results {
+			assert.Equal(t, i+1, res.Row, fmt.Sprintf("%s: expected row %d got %d\n", tc.desc, i+1, res.Row))
+			assert.Equal(t, tc.actions[i], res.Action, fmt.Sprintf("%s: row %d: expected action %s got %s\n", tc.desc, i+1, tc.actions[i], res.Action))
+			assert.True(t, errors.Contains(res.Err, tc.errs[i]), fmt.Sprintf("%s: row %d: expected %s got %s\n", tc.desc, i+1, tc.errs[i], res.Err))
+		}
+	}
+
+	th, err := svc.ViewThing(context.Background(), token, existing.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, "renamed", th.Name, fmt.Sprintf("expected name renamed got %s\n", th.Name))
+	assert.Equal(t, "t1", th.Metadata["model"], fmt.Sprintf("expected model t1 got %v\n", th.Metadata["model"]))
+
+	page, err := svc.ListThings(context.Background(), token, things.PageMetadata{Offset: 0, Limit: n, Name: "new"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	var created things.Thing
+	for _, th := range page.Things {
+		if th.Name == "new" {
+			created = th
+		}
+	}
+	assert.Equal(t, "new-key", created.Key, fmt.Sprintf("expected key new-key got %s\n", created.Key))
+
+	chPage, err := svc.ListChannelsByThing(context.Background(), token, created.ID, things.PageMetadata{Offset: 0, Limit: n})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	require.Len(t, chPage.Channels, 1, fmt.Sprintf("expected 1 connection got %d\n", len(chPage.Channels)))
+	assert.Equal(t, ch.ID, chPage.Channels[0].ID, fmt.Sprintf("expected connection to %s got %s\n", ch.ID, chPage.Channels[0].ID))
+}
+
+// failingConnections fails to connect the things to the channels.
+type failingConnections struct {
+	things.ChannelRepository
+}
+
+func (failingConnections) Connect(context.Context, string, []string, []string) error {
+	return errors.ErrCreateEntity
+}
+
+func TestImportThingsConnectionFailure(t *testing.T) {
+	auth := mocks.NewAuthService(map[string]string{token: email}, map[string][]mocks.MockSubjectSet{
+		email: {{Object: "users", Relation: "member"}}})
+	conns := make(chan mocks.Connection)
+	thingsRepo := mocks.NewThingRepository(conns)
+	channelsRepo := failingConnections{mocks.NewChannelRepository(thingsRepo, conns)}
+	svc := things.New(auth, thingsRepo, channelsRepo, mocks.NewProfileRepository(), mocks.NewQuotaRepository(), mocks.NewStatusRepository(), mocks.NewChannelCache(), mocks.NewThingCache(), uuid.NewMock())
+
+	chs, err := svc.CreateChannels(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch := chs[0]
+
+	records := []things.Record{
+		{Name: "new", Metadata: things.Metadata{things.ExternalIDKey: "ext-1"}, Connections: []string{ch.ID}},
+		{Name: "renamed", Metadata: things.Metadata{things.ExternalIDKey: "ext-1"}},
+	}
+	results, err := svc.ImportThings(context.Background(), token, records, false)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	require.Len(t, results, len(records), fmt.Sprintf("expected %d results got %d\n", len(records), len(results)))
+
+	created := results[0]
+	assert.True(t, errors.Contains(created.Err, errors.ErrCreateEntity), fmt.Sprintf("expected %s got %s\n", errors.ErrCreateEntity, created.Err))
+	assert.True(t, created.Partial, "expected partial result for the thing that failed to connect")
+	assert.NotEmpty(t, created.Record.ID, "expected the ID of the created thing")
+	assert.Empty(t, created.Record.Connections, fmt.Sprintf("expected no connections got %v\n", created.Record.Connections))
+
+	th, err := svc.ViewThing(context.Background(), token, created.Record.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, "renamed", th.Name, fmt.Sprintf("expected the created thing to be matched and renamed got %s\n", th.Name))
+
+	updated := results[1]
+	assert.Nil(t, updated.Err, fmt.Sprintf("unexpected error: %s\n", updated.Err))
+	assert.Equal(t, things.ImportUpdate, updated.Action, fmt.Sprintf("expected action %s got %s\n", things.ImportUpdate, updated.Action))
+	assert.Equal(t, created.Record.ID, updated.Record.ID, fmt.Sprintf("expected ID %s got %s\n", created.Record.ID, updated.Record.ID))
+}
+
+func TestImportChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email, token2: email2})
+	chs, err := svc.CreateChannels(context.Background(), token, things.Channel{Name: "existing"}, things.Channel{Name: "twin"}, things.Channel{Name: "twin"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	existing := chs[0]
+
+	records := []things.Record{
+		{Name: "new"},
+		{ID: existing.ID, Metadata: things.Metadata{"unit": "Cel"}},
+		{Name: "twin"},
+		{Name: "new", Metadata: things.Metadata{things.ExternalIDKey: "ext-1"}},
+	}
+
+	cases := []struct {
+		desc    string
+		token   string
+		records []things.Record
+		dryRun  bool
+		actions []string
+		errs    []error
+		err     error
+	}{
+		{
+			desc:    "import channels with dry run",
+			token:   token,
+			records: records,
+			dryRun:  true,
+			actions: []string{things.ImportCreate, things.ImportUpdate, "", things.ImportCreate},
+			errs:    []error{nil, nil, things.ErrAmbiguousMatch, nil},
+			err:     nil,
+		},
+		{
+			desc:    "import channels",
+			token:   token,
+			records: records,
+			actions: []string{things.ImportCreate, things.ImportUpdate, "", things.ImportCreate},
+			errs:    []error{nil, nil, things.ErrAmbiguousMatch, nil},
+			err:     nil,
+		},
+		{
+			desc:    "import channels with wrong credentials",
+			token:   wrongValue,
+			records: records,
+			err:     errors.ErrAuthentication,
+		},
+		{
+			desc:    "import channels as unauthorized user",
+			token:   token2,
+			records: records,
+			err:     errors.ErrAuthorization,
+		},
+	}
+
+	for _, tc := range cases {
+		results, err := svc.ImportChannels(context.Background(), tc.token, tc.records, tc.dryRun)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		require.Len(t, results, len(tc.records), fmt.Sprintf("%s: expected %d results got %d\n", tc.desc, len(tc.records), len(results)))
+		for i, res := range results {
+			assert.Equal(t, tc.actions[i], res.Action, fmt.Sprintf("%s: row %d: expected action %s got %s\n", tc.desc, i+1, tc.actions[i], res.Action))
+			assert.True(t, errors.Contains(res.Err, tc.errs[i]), fmt.Sprintf("%s: row %d: expected %s got %s\n", tc.desc, i+1, tc.errs[i], res.Err))
+		}
+	}
+
+	ch, err := svc.ViewChannel(context.Background(), token, existing.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, existing.Name, ch.Name, fmt.Sprintf("expected name %s got %s\n", existing.Name, ch.Name))
+	assert.Equal(t, "Cel", ch.Metadata["unit"], fmt.Sprintf("expected unit Cel got %v\n", ch.Metadata["unit"]))
+}
+
+func TestExportThings(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	chs, err := svc.CreateChannels(context.Background(), token, things.Channel{Name: "test"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch := chs[0]
+	th := things.Thing{Name: "test"}
+	ths, err := svc.CreateThings(context.Background(), token, th, th)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{ch.ID}, []string{ths[0].ID})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc    string
+		token   string
+		records map[string]things.Record
+		err     error
+	}{
+		{
+			desc:  "export things",
+			token: token,
+			records: map[string]things.Record{
+				ths[0].ID: {ID: ths[0].ID, Owner: email, Name: th.Name, Key: ths[0].Key, Connections: []string{ch.ID}},
+				ths[1].ID: {ID: ths[1].ID, Owner: email, Name: th.Name, Key: ths[1].Key},
+			},
+			err: nil,
+		},
+		{
+			desc:  "export things with wrong credentials",
+			token: wrongValue,
+			err:   errors.ErrAuthentication,
+		},
+	}
+
+	for _, tc := range cases {
+		records, err := svc.ExportThings(context.Background(), tc.token)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		assert.Len(t, records, len(tc.records), fmt.Sprintf("%s: expected %d records got %d\n", tc.desc, len(tc.records), len(records)))
+		for _, rec := range records {
+			assert.Equal(t, tc.records[rec.ID], rec, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.records[rec.ID], rec))
+		}
+	}
+}
+
+func TestExportChannels(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ch := things.Channel{Name: "test"}
+	chs, err := svc.CreateChannels(context.Background(), token, ch, ch)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc    string
+		token   string
+		records map[string]things.Record
+		err     error
+	}{
+		{
+			desc:  "export channels",
+			token: token,
+			records: map[string]things.Record{
+				chs[0].ID: {ID: chs[0].ID, Owner: email, Name: ch.Name},
+				chs[1].ID: {ID: chs[1].ID, Owner: email, Name: ch.Name},
+			},
+			err: nil,
+		},
+		{
+			desc:  "export channels with wrong credentials",
+			token: wrongValue,
+			err:   errors.ErrAuthentication,
+		},
+	}
+
+	for _, tc := range cases {
+		records, err := svc.ExportChannels(context.Background(), tc.token)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		assert.Len(t, records, len(tc.records), fmt.Sprintf("%s: expected %d records got %d\n", tc.desc, len(tc.records), len(records)))
+		for _, rec := range records {
+			assert.Equal(t, tc.records[rec.ID], rec, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.records[rec.ID], rec))
+		}
+	}
+}
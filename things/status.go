@@ -0,0 +1,39 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+import (
+	"context"
+	"time"
+)
+
+// Status represents the presence of a thing, maintained from the connection
+// events and the publish activity reported by the protocol adapters.
+type Status struct {
+	ThingID  string
+	Online   bool
+	LastSeen time.Time
+	Protocol string
+	ClientIP string
+	// Expires is the time after which the online thing is considered
+	// offline. It's set for the connectionless protocols (e.g. HTTP and
+	// CoAP), while the connected things are online until they disconnect.
+	Expires time.Time
+}
+
+// Active reports whether the thing is online at the given time.
+func (s Status) Active(t time.Time) bool {
+	return s.Online && (s.Expires.IsZero() || t.Before(s.Expires))
+}
+
+// StatusRepository specifies a thing status persistence API.
+type StatusRepository interface {
+	// Save persists the thing status, unless the stored status has been
+	// seen more recently.
+	Save(ctx context.Context, st Status) error
+
+	// RetrieveByThing retrieves the status of the thing identified by the
+	// given ID.
+	RetrieveByThing(ctx context.Context, id string) (Status, error)
+}
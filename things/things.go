@@ -5,6 +5,7 @@ package things
 
 import (
 	"context"
+	"time"
 
 	"github.com/mainflux/mainflux/pkg/errors"
 )
@@ -31,7 +32,13 @@ type Thing struct {
 	Owner    string
 	Name     string
 	Key      string
+	Keys     []Key
 	Metadata Metadata
+	// ProfileID is the ID of the profile the thing is created from.
+	ProfileID string
+	// DeletedAt is the time the thing was removed at. It's zero for the
+	// things that aren't removed.
+	DeletedAt time.Time
 }
 
 // Page contains page related metadata as well as list of things that
@@ -60,10 +67,27 @@ type ThingRepository interface {
 	// by the specified user.
 	RetrieveByID(ctx context.Context, owner, id string) (Thing, error)
 
-	// RetrieveByKey returns thing ID for given thing key.
-	RetrieveByKey(ctx context.Context, key string) (string, error)
+	// RotateKey replaces the primary key of the existing thing, keeping the
+	// replaced key active as the additional key labeled RotatedKeyLabel until
+	// the given expiration time. Zero expiration time revokes the replaced
+	// key immediately.
+	RotateKey(ctx context.Context, id, key string, expiresAt time.Time) error
 
-	// RetrieveAll retrieves the subset of things owned by the specified user
+	// SaveKey persists the additional key of the existing thing.
+	SaveKey(ctx context.Context, key Key) error
+
+	// RetrieveKeys retrieves the active additional keys of the thing.
+	RetrieveKeys(ctx context.Context, id string) ([]Key, error)
+
+	// RemoveKey removes the additional key of the thing.
+	RemoveKey(ctx context.Context, id, key string) error
+
+	// RetrieveByKey retrieves the active thing key, either primary or
+	// additional one, along with the ID of the thing holding it.
+	RetrieveByKey(ctx context.Context, key string) (Key, error)
+
+	// RetrieveAll retrieves the subset of things owned by the specified user.
+	// The removed things are retrieved instead if the Deleted flag is set.
 	RetrieveAll(ctx context.Context, owner string, pm PageMetadata) (Page, error)
 
 	// RetrieveByIDs retrieves the subset of things specified by given thing ids.
@@ -73,19 +97,30 @@ type ThingRepository interface {
 	// user and connected or not connected to specified channel.
 	RetrieveByChannel(ctx context.Context, owner, chID string, pm PageMetadata) (Page, error)
 
-	// Remove removes the thing having the provided identifier, that is owned
-	// by the specified user.
+	// Remove marks the thing having the provided identifier, that is owned
+	// by the specified user, as removed. The removed thing is kept along with
+	// its keys and connections until it's purged, and it can be restored
+	// meanwhile.
 	Remove(ctx context.Context, owner, id string) error
+
+	// Restore restores the removed thing having the provided identifier,
+	// that is owned by the specified user.
+	Restore(ctx context.Context, owner, id string) error
+
+	// Purge permanently deletes the things removed before the given time,
+	// and returns their IDs.
+	Purge(ctx context.Context, before time.Time) ([]string, error)
 }
 
 // ThingCache contains thing caching interface.
 type ThingCache interface {
-	// Save stores pair thing key, thing id.
-	Save(context.Context, string, string) error
+	// Save stores the thing key, which is removed from the cache once
+	// the key expires.
+	Save(context.Context, Key) error
 
 	// ID returns thing ID for given key.
 	ID(context.Context, string) (string, error)
 
-	// Removes thing from cache.
+	// Removes thing and all of its keys from cache.
 	Remove(context.Context, string) error
 }
@@ -5,6 +5,7 @@ package tracing
 
 import (
 	"context"
+	"time"
 
 	"github.com/mainflux/mainflux/things"
 	opentracing "github.com/opentracing/opentracing-go"
@@ -14,9 +15,12 @@ const (
 	saveChannelsOp            = "save_channels"
 	updateChannelOp           = "update_channel"
 	retrieveChannelByIDOp     = "retrieve_channel_by_id"
+	isChannelsOwnerOp         = "is_channels_owner"
 	retrieveAllChannelsOp     = "retrieve_all_channels"
 	retrieveChannelsByThingOp = "retrieve_channels_by_thing"
 	removeChannelOp           = "retrieve_channel"
+	restoreChannelOp          = "restore_channel"
+	purgeChannelsOp           = "purge_channels"
 	connectOp                 = "connect"
 	disconnectOp              = "disconnect"
 	hasThingOp                = "has_thing"
@@ -66,6 +70,14 @@ func (crm channelRepositoryMiddleware) RetrieveByID(ctx context.Context, owner,
 	return crm.repo.RetrieveByID(ctx, owner, id)
 }
 
+func (crm channelRepositoryMiddleware) IsOwner(ctx context.Context, owner string, ids []string) error {
+	span := createSpan(ctx, crm.tracer, isChannelsOwnerOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.IsOwner(ctx, owner, ids)
+}
+
 func (crm channelRepositoryMiddleware) RetrieveAll(ctx context.Context, owner string, pm things.PageMetadata) (things.ChannelsPage, error) {
 	span := createSpan(ctx, crm.tracer, retrieveAllChannelsOp)
 	defer span.Finish()
@@ -90,6 +102,22 @@ func (crm channelRepositoryMiddleware) Remove(ctx context.Context, owner, id str
 	return crm.repo.Remove(ctx, owner, id)
 }
 
+func (crm channelRepositoryMiddleware) Restore(ctx context.Context, owner, id string) error {
+	span := createSpan(ctx, crm.tracer, restoreChannelOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.Restore(ctx, owner, id)
+}
+
+func (crm channelRepositoryMiddleware) Purge(ctx context.Context, before time.Time) ([]string, error) {
+	span := createSpan(ctx, crm.tracer, purgeChannelsOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.Purge(ctx, before)
+}
+
 func (crm channelRepositoryMiddleware) Connect(ctx context.Context, owner string, chIDs, thIDs []string) error {
 	span := createSpan(ctx, crm.tracer, connectOp)
 	defer span.Finish()
@@ -0,0 +1,75 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/things"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveProfileOp         = "save_profile"
+	updateProfileOp       = "update_profile"
+	retrieveProfileByIDOp = "retrieve_profile_by_id"
+	retrieveAllProfilesOp = "retrieve_all_profiles"
+	removeProfileOp       = "remove_profile"
+)
+
+var _ things.ProfileRepository = (*profileRepositoryMiddleware)(nil)
+
+type profileRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   things.ProfileRepository
+}
+
+// ProfileRepositoryMiddleware tracks request and their latency, and adds
+// spans to context.
+func ProfileRepositoryMiddleware(tracer opentracing.Tracer, repo things.ProfileRepository) things.ProfileRepository {
+	return profileRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (prm profileRepositoryMiddleware) Save(ctx context.Context, p things.Profile) error {
+	span := createSpan(ctx, prm.tracer, saveProfileOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return prm.repo.Save(ctx, p)
+}
+
+func (prm profileRepositoryMiddleware) Update(ctx context.Context, p things.Profile) error {
+	span := createSpan(ctx, prm.tracer, updateProfileOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return prm.repo.Update(ctx, p)
+}
+
+func (prm profileRepositoryMiddleware) RetrieveByID(ctx context.Context, owner, id string) (things.Profile, error) {
+	span := createSpan(ctx, prm.tracer, retrieveProfileByIDOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return prm.repo.RetrieveByID(ctx, owner, id)
+}
+
+func (prm profileRepositoryMiddleware) RetrieveAll(ctx context.Context, owner string, pm things.PageMetadata) (things.ProfilesPage, error) {
+	span := createSpan(ctx, prm.tracer, retrieveAllProfilesOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return prm.repo.RetrieveAll(ctx, owner, pm)
+}
+
+func (prm profileRepositoryMiddleware) Remove(ctx context.Context, owner, id string) error {
+	span := createSpan(ctx, prm.tracer, removeProfileOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return prm.repo.Remove(ctx, owner, id)
+}
@@ -0,0 +1,48 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/things"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveQuotaOp            = "save_quota"
+	retrieveQuotaByOwnerOp = "retrieve_quota_by_owner"
+)
+
+var _ things.QuotaRepository = (*quotaRepositoryMiddleware)(nil)
+
+type quotaRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   things.QuotaRepository
+}
+
+// QuotaRepositoryMiddleware tracks request and their latency, and adds spans
+// to context.
+func QuotaRepositoryMiddleware(tracer opentracing.Tracer, repo things.QuotaRepository) things.QuotaRepository {
+	return quotaRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (qrm quotaRepositoryMiddleware) Save(ctx context.Context, owner string, q things.Quota) error {
+	span := createSpan(ctx, qrm.tracer, saveQuotaOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return qrm.repo.Save(ctx, owner, q)
+}
+
+func (qrm quotaRepositoryMiddleware) RetrieveByOwner(ctx context.Context, owner string) (things.Quota, error) {
+	span := createSpan(ctx, qrm.tracer, retrieveQuotaByOwnerOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return qrm.repo.RetrieveByOwner(ctx, owner)
+}
@@ -0,0 +1,48 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/things"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveStatusOp            = "save_status"
+	retrieveStatusByThingOp = "retrieve_status_by_thing"
+)
+
+var _ things.StatusRepository = (*statusRepositoryMiddleware)(nil)
+
+type statusRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   things.StatusRepository
+}
+
+// StatusRepositoryMiddleware tracks request and their latency, and adds spans
+// to context.
+func StatusRepositoryMiddleware(tracer opentracing.Tracer, repo things.StatusRepository) things.StatusRepository {
+	return statusRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (srm statusRepositoryMiddleware) Save(ctx context.Context, st things.Status) error {
+	span := createSpan(ctx, srm.tracer, saveStatusOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.Save(ctx, st)
+}
+
+func (srm statusRepositoryMiddleware) RetrieveByThing(ctx context.Context, id string) (things.Status, error) {
+	span := createSpan(ctx, srm.tracer, retrieveStatusByThingOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.RetrieveByThing(ctx, id)
+}
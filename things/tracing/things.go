@@ -5,6 +5,7 @@ package tracing
 
 import (
 	"context"
+	"time"
 
 	"github.com/mainflux/mainflux/things"
 	opentracing "github.com/opentracing/opentracing-go"
@@ -17,9 +18,15 @@ const (
 	updateThingKeyOp          = "update_thing_by_key"
 	retrieveThingByIDOp       = "retrieve_thing_by_id"
 	retrieveThingByKeyOp      = "retrieve_thing_by_key"
+	rotateThingKeyOp          = "rotate_thing_key"
+	saveThingKeyOp            = "save_thing_key"
+	retrieveThingKeysOp       = "retrieve_thing_keys"
+	removeThingKeyOp          = "remove_thing_key"
 	retrieveAllThingsOp       = "retrieve_all_things"
 	retrieveThingsByChannelOp = "retrieve_things_by_chan"
 	removeThingOp             = "remove_thing"
+	restoreThingOp            = "restore_thing"
+	purgeThingsOp             = "purge_things"
 	retrieveThingIDByKeyOp    = "retrieve_id_by_key"
 )
 
@@ -74,7 +81,39 @@ func (trm thingRepositoryMiddleware) RetrieveByID(ctx context.Context, owner, id
 	return trm.repo.RetrieveByID(ctx, owner, id)
 }
 
-func (trm thingRepositoryMiddleware) RetrieveByKey(ctx context.Context, key string) (string, error) {
+func (trm thingRepositoryMiddleware) RotateKey(ctx context.Context, id, key string, expiresAt time.Time) error {
+	span := createSpan(ctx, trm.tracer, rotateThingKeyOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.RotateKey(ctx, id, key, expiresAt)
+}
+
+func (trm thingRepositoryMiddleware) SaveKey(ctx context.Context, key things.Key) error {
+	span := createSpan(ctx, trm.tracer, saveThingKeyOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.SaveKey(ctx, key)
+}
+
+func (trm thingRepositoryMiddleware) RetrieveKeys(ctx context.Context, id string) ([]things.Key, error) {
+	span := createSpan(ctx, trm.tracer, retrieveThingKeysOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.RetrieveKeys(ctx, id)
+}
+
+func (trm thingRepositoryMiddleware) RemoveKey(ctx context.Context, id, key string) error {
+	span := createSpan(ctx, trm.tracer, removeThingKeyOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.RemoveKey(ctx, id, key)
+}
+
+func (trm thingRepositoryMiddleware) RetrieveByKey(ctx context.Context, key string) (things.Key, error) {
 	span := createSpan(ctx, trm.tracer, retrieveThingByKeyOp)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
@@ -114,6 +153,22 @@ func (trm thingRepositoryMiddleware) Remove(ctx context.Context, owner, id strin
 	return trm.repo.Remove(ctx, owner, id)
 }
 
+func (trm thingRepositoryMiddleware) Restore(ctx context.Context, owner, id string) error {
+	span := createSpan(ctx, trm.tracer, restoreThingOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.Restore(ctx, owner, id)
+}
+
+func (trm thingRepositoryMiddleware) Purge(ctx context.Context, before time.Time) ([]string, error) {
+	span := createSpan(ctx, trm.tracer, purgeThingsOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.Purge(ctx, before)
+}
+
 type thingCacheMiddleware struct {
 	tracer opentracing.Tracer
 	cache  things.ThingCache
@@ -128,12 +183,12 @@ func ThingCacheMiddleware(tracer opentracing.Tracer, cache things.ThingCache) th
 	}
 }
 
-func (tcm thingCacheMiddleware) Save(ctx context.Context, thingKey string, thingID string) error {
+func (tcm thingCacheMiddleware) Save(ctx context.Context, key things.Key) error {
 	span := createSpan(ctx, tcm.tracer, saveThingOp)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return tcm.cache.Save(ctx, thingKey, thingID)
+	return tcm.cache.Save(ctx, key)
 }
 
 func (tcm thingCacheMiddleware) ID(ctx context.Context, thingKey string) (string, error) {
@@ -92,6 +92,19 @@ func (lm *loggingMiddleware) SaveStates(msg *messaging.Message) (err error) {
 	return lm.svc.SaveStates(msg)
 }
 
+func (lm *loggingMiddleware) RemoveChannelAttributes(ctx context.Context, chanID string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_channel_attributes for channel %s took %s to complete", chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveChannelAttributes(ctx, chanID)
+}
+
 func (lm *loggingMiddleware) ListStates(ctx context.Context, token string, offset uint64, limit uint64, twinID string) (page twins.StatesPage, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method list_states for token %s took %s to complete", token, time.Since(begin))
@@ -76,6 +76,15 @@ func (ms *metricsMiddleware) SaveStates(msg *messaging.Message) error {
 	return ms.svc.SaveStates(msg)
 }
 
+func (ms *metricsMiddleware) RemoveChannelAttributes(ctx context.Context, chanID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_channel_attributes").Add(1)
+		ms.latency.With("method", "remove_channel_attributes").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveChannelAttributes(ctx, chanID)
+}
+
 func (ms *metricsMiddleware) ListStates(ctx context.Context, token string, offset uint64, limit uint64, twinID string) (st twins.StatesPage, err error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "list_states").Add(1)
@@ -85,6 +85,23 @@ func (trm *twinRepositoryMock) RetrieveByAttribute(ctx context.Context, channel,
 	return ids, nil
 }
 
+func (trm *twinRepositoryMock) RetrieveByChannel(ctx context.Context, channel string) ([]string, error) {
+	trm.mu.Lock()
+	defer trm.mu.Unlock()
+
+	var ids []string
+	for _, twin := range trm.twins {
+		def := twin.Definitions[len(twin.Definitions)-1]
+		for _, attr := range def.Attributes {
+			if attr.Channel == channel {
+				ids = append(ids, twin.ID)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
 func (trm *twinRepositoryMock) RetrieveAll(_ context.Context, owner string, offset uint64, limit uint64, name string, metadata twins.Metadata) (twins.Page, error) {
 	trm.mu.Lock()
 	defer trm.mu.Unlock()
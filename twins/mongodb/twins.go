@@ -80,18 +80,6 @@ func (tr *twinRepository) RetrieveByID(_ context.Context, twinID string) (twins.
 }
 
 func (tr *twinRepository) RetrieveByAttribute(ctx context.Context, channel, subtopic string) ([]string, error) {
-	coll := tr.db.Collection(twinsCollection)
-
-	findOptions := options.Aggregate()
-	prj1 := bson.M{
-		"$project": bson.M{
-			"definition": bson.M{
-				"$arrayElemAt": []interface{}{"$definitions.attributes", -1},
-			},
-			"id":  true,
-			"_id": 0,
-		},
-	}
 	match := bson.M{
 		"$match": bson.M{
 			"definition.channel": channel,
@@ -101,35 +89,18 @@ func (tr *twinRepository) RetrieveByAttribute(ctx context.Context, channel, subt
 			},
 		},
 	}
-	prj2 := bson.M{
-		"$project": bson.M{
-			"id": true,
-		},
-	}
 
-	cur, err := coll.Aggregate(ctx, []bson.M{prj1, match, prj2}, findOptions)
-	if err != nil {
-		return []string{}, errors.Wrap(errors.ErrViewEntity, err)
-	}
-	defer cur.Close(ctx)
-
-	if err := cur.Err(); err != nil {
-		return []string{}, nil
-	}
+	return tr.retrieveIDs(ctx, match)
+}
 
-	var ids []string
-	for cur.Next(ctx) {
-		var elem struct {
-			ID string `json:"id"`
-		}
-		err := cur.Decode(&elem)
-		if err != nil {
-			return ids, nil
-		}
-		ids = append(ids, elem.ID)
+func (tr *twinRepository) RetrieveByChannel(ctx context.Context, channel string) ([]string, error) {
+	match := bson.M{
+		"$match": bson.M{
+			"definition.channel": channel,
+		},
 	}
 
-	return ids, nil
+	return tr.retrieveIDs(ctx, match)
 }
 
 func (tr *twinRepository) RetrieveAll(ctx context.Context, owner string, offset uint64, limit uint64, name string, metadata twins.Metadata) (twins.Page, error) {
@@ -191,6 +162,52 @@ func (tr *twinRepository) Remove(ctx context.Context, twinID string) error {
 	return nil
 }
 
+// retrieveIDs retrieves ids of the twins whose latest definition
+// attributes satisfy the given match stage.
+func (tr *twinRepository) retrieveIDs(ctx context.Context, match bson.M) ([]string, error) {
+	coll := tr.db.Collection(twinsCollection)
+
+	findOptions := options.Aggregate()
+	prj1 := bson.M{
+		"$project": bson.M{
+			"definition": bson.M{
+				"$arrayElemAt": []interface{}{"$definitions.attributes", -1},
+			},
+			"id":  true,
+			"_id": 0,
+		},
+	}
+	prj2 := bson.M{
+		"$project": bson.M{
+			"id": true,
+		},
+	}
+
+	cur, err := coll.Aggregate(ctx, []bson.M{prj1, match, prj2}, findOptions)
+	if err != nil {
+		return []string{}, errors.Wrap(errors.ErrViewEntity, err)
+	}
+	defer cur.Close(ctx)
+
+	if err := cur.Err(); err != nil {
+		return []string{}, nil
+	}
+
+	var ids []string
+	for cur.Next(ctx) {
+		var elem struct {
+			ID string `json:"id"`
+		}
+		err := cur.Decode(&elem)
+		if err != nil {
+			return ids, nil
+		}
+		ids = append(ids, elem.ID)
+	}
+
+	return ids, nil
+}
+
 func decodeTwins(ctx context.Context, cur *mongo.Cursor) ([]twins.Twin, error) {
 	defer cur.Close(ctx)
 	var results []twins.Twin
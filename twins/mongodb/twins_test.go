@@ -237,6 +237,49 @@ func TestTwinsRetrieveByAttribute(t *testing.T) {
 	}
 }
 
+func TestTwinsRetrieveByChannel(t *testing.T) {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(addr))
+	require.Nil(t, err, fmt.Sprintf("Creating new MongoDB client expected to succeed: %s.\n", err))
+
+	db := client.Database(testDB)
+	repo := mongodb.NewTwinRepository(db)
+
+	chID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	otherID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	empty := mocks.CreateTwin([]string{chID}, []string{""})
+	_, err = repo.Save(context.Background(), empty)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	nonEmpty := mocks.CreateTwin([]string{chID}, []string{subtopic})
+	_, err = repo.Save(context.Background(), nonEmpty)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		channel string
+		ids     []string
+	}{
+		{
+			desc:    "retrieve twins by channel",
+			channel: chID,
+			ids:     []string{empty.ID, nonEmpty.ID},
+		},
+		{
+			desc:    "retrieve twins by channel without twins",
+			channel: otherID,
+			ids:     []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		ids, err := repo.RetrieveByChannel(context.Background(), tc.channel)
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		assert.ElementsMatch(t, ids, tc.ids, fmt.Sprintf("%s: expected ids %v do not match received ids %v", tc.desc, tc.ids, ids))
+	}
+}
+
 func TestTwinsRetrieveAll(t *testing.T) {
 	email := "twin-multi-retrieval@example.com"
 	name := "mainflux"
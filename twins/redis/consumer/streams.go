@@ -0,0 +1,96 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/twins"
+)
+
+const (
+	stream = "mainflux.things"
+	group  = "mainflux.twins"
+
+	channelPrefix = "channel."
+	channelPurge  = channelPrefix + "purge"
+
+	exists = "BUSYGROUP Consumer Group name already exists"
+)
+
+// Subscriber represents event source for things and channels provisioning.
+type Subscriber interface {
+	// Subscribes to given subject and receives events.
+	Subscribe(context.Context, string) error
+}
+
+type eventStore struct {
+	svc      twins.Service
+	client   *redis.Client
+	consumer string
+	logger   logger.Logger
+}
+
+// NewEventStore returns new event store instance.
+func NewEventStore(svc twins.Service, client *redis.Client, consumer string, log logger.Logger) Subscriber {
+	return eventStore{
+		svc:      svc,
+		client:   client,
+		consumer: consumer,
+		logger:   log,
+	}
+}
+
+func (es eventStore) Subscribe(ctx context.Context, subject string) error {
+	err := es.client.XGroupCreateMkStream(ctx, stream, group, "$").Err()
+	if err != nil && err.Error() != exists {
+		return err
+	}
+
+	for {
+		streams, err := es.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: es.consumer,
+			Streams:  []string{stream, ">"},
+			Count:    100,
+		}).Result()
+		if err != nil || len(streams) == 0 {
+			continue
+		}
+
+		for _, msg := range streams[0].Messages {
+			event := msg.Values
+
+			var err error
+			switch event["operation"] {
+			case channelPurge:
+				pce := decodePurgeChannel(event)
+				err = es.svc.RemoveChannelAttributes(ctx, pce.id)
+			}
+			if err != nil {
+				es.logger.Warn(fmt.Sprintf("Failed to handle event sourcing: %s", err.Error()))
+				break
+			}
+			es.client.XAck(ctx, stream, group, msg.ID)
+		}
+	}
+}
+
+func decodePurgeChannel(event map[string]interface{}) purgeEvent {
+	return purgeEvent{
+		id: read(event, "id", ""),
+	}
+}
+
+func read(event map[string]interface{}, key, def string) string {
+	val, ok := event[key].(string)
+	if !ok {
+		return def
+	}
+
+	return val
+}
@@ -48,6 +48,12 @@ type Service interface {
 
 	// SaveStates persists states into database
 	SaveStates(msg *messaging.Message) error
+
+	// RemoveChannelAttributes removes the attributes of the channel with
+	// the provided ID from the definitions of all the twins, by adding the
+	// new definition without them. It is intended for internal use by the
+	// event consumer, once the channel is purged.
+	RemoveChannelAttributes(ctx context.Context, chanID string) error
 }
 
 const (
@@ -228,6 +234,45 @@ func (ts *twinsService) RemoveTwin(ctx context.Context, token, twinID string) (e
 	return ts.twinCache.Remove(ctx, twinID)
 }
 
+func (ts *twinsService) RemoveChannelAttributes(ctx context.Context, chanID string) error {
+	ids, err := ts.twins.RetrieveByChannel(ctx, chanID)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		tw, err := ts.twins.RetrieveByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		def := tw.Definitions[len(tw.Definitions)-1]
+		attrs := []Attribute{}
+		for _, attr := range def.Attributes {
+			if attr.Channel != chanID {
+				attrs = append(attrs, attr)
+			}
+		}
+
+		def.ID++
+		def.Created = time.Now()
+		def.Attributes = attrs
+		tw.Definitions = append(tw.Definitions, def)
+		tw.Updated = time.Now()
+		tw.Revision++
+
+		if err := ts.twins.Update(ctx, tw); err != nil {
+			return err
+		}
+
+		if err := ts.twinCache.Update(ctx, tw); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (ts *twinsService) ListTwins(ctx context.Context, token string, offset uint64, limit uint64, name string, metadata Metadata) (Page, error) {
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
@@ -244,6 +244,45 @@ func TestRemoveTwin(t *testing.T) {
 	}
 }
 
+func TestRemoveChannelAttributes(t *testing.T) {
+	svc := mocks.NewService(map[string]string{token: email})
+	def := mocks.CreateDefinition([]string{channels[0], channels[0], channels[1]}, subtopics)
+	saved, err := svc.AddTwin(context.Background(), token, twins.Twin{}, def)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		chanID string
+		attrs  []twins.Attribute
+	}{
+		{
+			desc:   "remove attributes of channel without twins",
+			chanID: channels[2],
+			attrs:  def.Attributes,
+		},
+		{
+			desc:   "remove attributes of channel",
+			chanID: channels[0],
+			attrs:  def.Attributes[2:],
+		},
+		{
+			desc:   "remove attributes of channel without attributes",
+			chanID: channels[0],
+			attrs:  def.Attributes[2:],
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveChannelAttributes(context.Background(), tc.chanID)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+
+		tw, err := svc.ViewTwin(context.Background(), token, saved.ID)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		attrs := tw.Definitions[len(tw.Definitions)-1].Attributes
+		assert.Equal(t, tc.attrs, attrs, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.attrs, attrs))
+	}
+}
+
 func TestSaveStates(t *testing.T) {
 	svc := mocks.NewService(map[string]string{token: email})
 
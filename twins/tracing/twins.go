@@ -17,6 +17,7 @@ const (
 	retrieveTwinByIDOp         = "retrieve_twin_by_id"
 	retrieveAllTwinsOp         = "retrieve_all_twins"
 	retrieveTwinsByAttributeOp = "retrieve_twins_by_attribute"
+	retrieveTwinsByChannelOp   = "retrieve_twins_by_channel"
 	removeTwinOp               = "remove_twin"
 )
 
@@ -75,6 +76,14 @@ func (trm twinRepositoryMiddleware) RetrieveByAttribute(ctx context.Context, cha
 	return trm.repo.RetrieveByAttribute(ctx, channel, subtopic)
 }
 
+func (trm twinRepositoryMiddleware) RetrieveByChannel(ctx context.Context, channel string) ([]string, error) {
+	span := createSpan(ctx, trm.tracer, retrieveTwinsByChannelOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.RetrieveByChannel(ctx, channel)
+}
+
 func (trm twinRepositoryMiddleware) Remove(ctx context.Context, twinID string) error {
 	span := createSpan(ctx, trm.tracer, removeTwinOp)
 	defer span.Finish()
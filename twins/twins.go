@@ -70,6 +70,10 @@ type TwinRepository interface {
 	// the attribute with given channel and subtopic
 	RetrieveByAttribute(ctx context.Context, channel, subtopic string) ([]string, error)
 
+	// RetrieveByChannel retrieves twin ids whose definition contains
+	// any attribute with given channel, regardless of its subtopic.
+	RetrieveByChannel(ctx context.Context, channel string) ([]string, error)
+
 	// RetrieveAll retrieves the subset of twins owned by the specified user.
 	RetrieveAll(ctx context.Context, owner string, offset, limit uint64, name string, metadata Metadata) (Page, error)
 
@@ -0,0 +1,206 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ws contains the domain concept definitions needed to support
+// Mainflux WebSocket adapter service functionality.
+package ws
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/quota"
+)
+
+const chansPrefix = "channels"
+
+var (
+	// ErrFailedMessagePublish indicates that message publishing failed.
+	ErrFailedMessagePublish = errors.New("failed to publish message")
+
+	// ErrFailedSubscription indicates that client couldn't subscribe to specified channel.
+	ErrFailedSubscription = errors.New("failed to subscribe to a channel")
+
+	// ErrFailedUnsubscribe indicates that client couldn't unsubscribe from specified channel.
+	ErrFailedUnsubscribe = errors.New("failed to unsubscribe from a channel")
+
+	// ErrEmptyTopic indicates an absence of channel ID in the message topic.
+	ErrEmptyTopic = errors.New("empty topic")
+)
+
+// Service specifies WebSocket service API.
+type Service interface {
+	// Publish publishes the message to the channel if the thing
+	// identified by the given key is allowed to access it.
+	Publish(ctx context.Context, key string, msg messaging.Message) error
+
+	// Subscribe subscribes the client to the channel with specified id and subtopic.
+	// Messages received from the broker are forwarded to the client.
+	Subscribe(ctx context.Context, key, chanID, subtopic string, c Client) error
+
+	// Unsubscribe removes the client with the given ID from the channel
+	// with specified id and subtopic. The client was authorized when it
+	// subscribed, so it's removed regardless of its current access rights.
+	Unsubscribe(ctx context.Context, chanID, subtopic, clientID string) error
+}
+
+var _ Service = (*adapterService)(nil)
+
+// subscription represents a client subscribed on behalf of the thing.
+type subscription struct {
+	client  Client
+	thingID string
+}
+
+type adapterService struct {
+	things  mainflux.ThingsServiceClient
+	pubsub  messaging.PubSub
+	limiter quota.Limiter
+	// subs maps broker subject to subscriptions of clients observing it.
+	subs   map[string]map[string]subscription
+	subsMu sync.Mutex
+}
+
+// New instantiates the WebSocket adapter implementation. The quotas of the
+// publishing things are enforced using the given limiter.
+func New(things mainflux.ThingsServiceClient, pubsub messaging.PubSub, limiter quota.Limiter) Service {
+	return &adapterService{
+		things:  things,
+		pubsub:  pubsub,
+		limiter: limiter,
+		subs:    make(map[string]map[string]subscription),
+	}
+}
+
+func (svc *adapterService) Publish(ctx context.Context, key string, msg messaging.Message) error {
+	if msg.Channel == "" {
+		return ErrEmptyTopic
+	}
+
+	thid, err := svc.authorize(ctx, key, msg.Channel)
+	if err != nil {
+		return err
+	}
+	msg.Publisher = thid
+
+	if err := svc.limiter.Allow(ctx, thid, len(msg.Payload)); err != nil {
+		return err
+	}
+
+	if err := svc.pubsub.Publish(msg.Channel, msg); err != nil {
+		return errors.Wrap(ErrFailedMessagePublish, err)
+	}
+
+	return nil
+}
+
+func (svc *adapterService) Subscribe(ctx context.Context, key, chanID, subtopic string, c Client) error {
+	if chanID == "" {
+		return ErrEmptyTopic
+	}
+
+	thid, err := svc.authorize(ctx, key, chanID)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("%s.%s", chansPrefix, chanID)
+	if subtopic != "" {
+		subject = fmt.Sprintf("%s.%s", subject, subtopic)
+	}
+
+	return svc.put(subject, subscription{client: c, thingID: thid})
+}
+
+func (svc *adapterService) Unsubscribe(ctx context.Context, chanID, subtopic, clientID string) error {
+	if chanID == "" {
+		return ErrEmptyTopic
+	}
+
+	subject := fmt.Sprintf("%s.%s", chansPrefix, chanID)
+	if subtopic != "" {
+		subject = fmt.Sprintf("%s.%s", subject, subtopic)
+	}
+
+	return svc.remove(subject, clientID)
+}
+
+func (svc *adapterService) authorize(ctx context.Context, key, chanID string) (string, error) {
+	ar := &mainflux.AccessByKeyReq{
+		Token:  key,
+		ChanID: chanID,
+	}
+	thid, err := svc.things.CanAccessByKey(ctx, ar)
+	if err != nil {
+		return "", errors.Wrap(errors.ErrAuthorization, err)
+	}
+
+	return thid.GetValue(), nil
+}
+
+func (svc *adapterService) put(subject string, sub subscription) error {
+	svc.subsMu.Lock()
+	defer svc.subsMu.Unlock()
+
+	subs, ok := svc.subs[subject]
+	// The broker subscription is shared among all the clients observing
+	// the same subject, so subscribe only for the first one.
+	if !ok {
+		if err := svc.pubsub.Subscribe(subject, svc.broadcast(subject)); err != nil {
+			return errors.Wrap(ErrFailedSubscription, err)
+		}
+		subs = make(map[string]subscription)
+		svc.subs[subject] = subs
+	}
+	subs[sub.client.ID()] = sub
+
+	return nil
+}
+
+func (svc *adapterService) remove(subject, clientID string) error {
+	svc.subsMu.Lock()
+	defer svc.subsMu.Unlock()
+
+	subs, ok := svc.subs[subject]
+	if !ok {
+		return nil
+	}
+	delete(subs, clientID)
+	// If there are no clients left for the subject, remove the broker subscription.
+	if len(subs) == 0 {
+		delete(svc.subs, subject)
+		if err := svc.pubsub.Unsubscribe(subject); err != nil {
+			return errors.Wrap(ErrFailedUnsubscribe, err)
+		}
+	}
+
+	return nil
+}
+
+func (svc *adapterService) broadcast(subject string) messaging.MessageHandler {
+	return func(msg messaging.Message) error {
+		svc.subsMu.Lock()
+		subs := make([]subscription, 0, len(svc.subs[subject]))
+		for _, sub := range svc.subs[subject] {
+			subs = append(subs, sub)
+		}
+		svc.subsMu.Unlock()
+
+		for _, sub := range subs {
+			// Prevent the publisher from receiving its own message.
+			if msg.Publisher == sub.thingID {
+				continue
+			}
+			// Clients that can't keep up are closed by Handle and removed
+			// once their connection terminates, so their failure doesn't
+			// cause the redelivery to the other clients.
+			sub.client.Handle(msg)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package ws_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/quota"
+	qmocks "github.com/mainflux/mainflux/pkg/quota/mocks"
+	"github.com/mainflux/mainflux/ws"
+	"github.com/mainflux/mainflux/ws/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chanID   = "1"
+	thingID  = "thing-1"
+	otherID  = "thing-2"
+	thingKey = "thing_key"
+	otherKey = "other_key"
+	wrongKey = "wrong_key"
+	subtopic = "engine"
+)
+
+var msg = messaging.Message{
+	Channel:  chanID,
+	Protocol: "websocket",
+	Payload:  []byte(`[{"n":"current","t":-1,"v":1.6}]`),
+}
+
+func newService() ws.Service {
+	things := mocks.NewThingsClient(map[string]string{thingKey: thingID, otherKey: otherID})
+	limiter := qmocks.NewLimiter(map[string]quota.Limits{otherID: {ThingMessages: 1, ThingPayloadSize: uint64(len(msg.Payload))}})
+	return ws.New(things, mocks.NewPubSub(), limiter)
+}
+
+func TestPublish(t *testing.T) {
+	svc := newService()
+
+	cases := []struct {
+		desc string
+		key  string
+		msg  messaging.Message
+		err  error
+	}{
+		{
+			desc: "publish a valid message with valid key",
+			key:  thingKey,
+			msg:  msg,
+			err:  nil,
+		},
+		{
+			desc: "publish a valid message with invalid key",
+			key:  wrongKey,
+			msg:  msg,
+			err:  errors.ErrAuthorization,
+		},
+		{
+			desc: "publish a message without channel",
+			key:  thingKey,
+			msg:  messaging.Message{Payload: msg.Payload},
+			err:  ws.ErrEmptyTopic,
+		},
+		{
+			desc: "publish an empty message",
+			key:  thingKey,
+			msg:  messaging.Message{Channel: chanID},
+			err:  ws.ErrFailedMessagePublish,
+		},
+		{
+			desc: "publish a message exceeding payload size quota",
+			key:  otherKey,
+			msg:  messaging.Message{Channel: chanID, Payload: []byte(string(msg.Payload) + " ")},
+			err:  quota.ErrPayloadTooLarge,
+		},
+		{
+			desc: "publish a message within rate quota",
+			key:  otherKey,
+			msg:  msg,
+			err:  nil,
+		},
+		{
+			desc: "publish a message exceeding rate quota",
+			key:  otherKey,
+			msg:  msg,
+			err:  quota.ErrRateLimited,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Publish(context.Background(), tc.key, tc.msg)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	svc := newService()
+
+	cases := []struct {
+		desc     string
+		key      string
+		chanID   string
+		subtopic string
+		client   ws.Client
+		err      error
+	}{
+		{
+			desc:   "subscribe to channel with valid key",
+			key:    thingKey,
+			chanID: chanID,
+			client: mocks.NewClient("1"),
+			err:    nil,
+		},
+		{
+			desc:     "subscribe to channel with subtopic",
+			key:      thingKey,
+			chanID:   chanID,
+			subtopic: subtopic,
+			client:   mocks.NewClient("2"),
+			err:      nil,
+		},
+		{
+			desc:   "subscribe another client to the same channel",
+			key:    otherKey,
+			chanID: chanID,
+			client: mocks.NewClient("3"),
+			err:    nil,
+		},
+		{
+			desc:   "subscribe to channel with invalid key",
+			key:    wrongKey,
+			chanID: chanID,
+			client: mocks.NewClient("4"),
+			err:    errors.ErrAuthorization,
+		},
+		{
+			desc:   "subscribe to channel with empty channel",
+			key:    thingKey,
+			chanID: "",
+			client: mocks.NewClient("5"),
+			err:    ws.ErrEmptyTopic,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Subscribe(context.Background(), tc.key, tc.chanID, tc.subtopic, tc.client)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	svc := newService()
+	c := mocks.NewClient("1")
+	err := svc.Subscribe(context.Background(), thingKey, chanID, subtopic, c)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc     string
+		chanID   string
+		subtopic string
+		clientID string
+		err      error
+	}{
+		{
+			desc:     "unsubscribe from channel with empty channel",
+			chanID:   "",
+			subtopic: subtopic,
+			clientID: c.ID(),
+			err:      ws.ErrEmptyTopic,
+		},
+		{
+			desc:     "unsubscribe from channel",
+			chanID:   chanID,
+			subtopic: subtopic,
+			clientID: c.ID(),
+			err:      nil,
+		},
+		{
+			desc:     "unsubscribe from channel without subscription",
+			chanID:   chanID,
+			subtopic: subtopic,
+			clientID: c.ID(),
+			err:      nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Unsubscribe(context.Background(), tc.chanID, tc.subtopic, tc.clientID)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestBroadcast(t *testing.T) {
+	svc := newService()
+	publisher := mocks.NewClient("1")
+	subscriber := mocks.NewClient("2")
+	err := svc.Subscribe(context.Background(), thingKey, chanID, "", publisher)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Subscribe(context.Background(), otherKey, chanID, "", subscriber)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = svc.Publish(context.Background(), thingKey, msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	expected := msg
+	expected.Publisher = thingID
+	assert.Empty(t, publisher.Messages(), "publisher should not receive its own message")
+	assert.Equal(t, []messaging.Message{expected}, subscriber.Messages(), "subscriber should receive published message")
+
+	err = svc.Unsubscribe(context.Background(), chanID, "", subscriber.ID())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Publish(context.Background(), thingKey, msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Len(t, subscriber.Messages(), 1, "unsubscribed client should not receive messages")
+}
@@ -0,0 +1,169 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mainflux/mainflux/logger"
+	qmocks "github.com/mainflux/mainflux/pkg/quota/mocks"
+	"github.com/mainflux/mainflux/pkg/uuid"
+	"github.com/mainflux/mainflux/ws"
+	"github.com/mainflux/mainflux/ws/api"
+	"github.com/mainflux/mainflux/ws/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chanID   = "1"
+	id       = "1"
+	thingKey = "thing_key"
+	otherKey = "other_key"
+	wrongKey = "wrong_key"
+	protocol = "ws"
+)
+
+var msg = []byte(`[{"n":"current","t":-1,"v":1.6}]`)
+
+func newService() ws.Service {
+	things := mocks.NewThingsClient(map[string]string{thingKey: id, otherKey: "2"})
+	return ws.New(things, mocks.NewPubSub(), qmocks.NewLimiter(nil))
+}
+
+func newHTTPServer(svc ws.Service) *httptest.Server {
+	mux := api.MakeHandler(svc, uuid.NewMock(), logger.NewMock())
+	return httptest.NewServer(mux)
+}
+
+func makeURL(tsURL, chanID, subtopic, key string, header bool) string {
+	u := fmt.Sprintf("%s/channels/%s/messages", strings.Replace(tsURL, "http", protocol, 1), chanID)
+	if subtopic != "" {
+		u = fmt.Sprintf("%s/%s", u, subtopic)
+	}
+	if header {
+		return u
+	}
+
+	return fmt.Sprintf("%s?authorization=%s", u, key)
+}
+
+func handshake(tsURL, chanID, subtopic, key string, header bool) (*websocket.Conn, *http.Response, error) {
+	h := http.Header{}
+	if header {
+		h.Set("Authorization", key)
+	}
+	url := makeURL(tsURL, chanID, subtopic, key, header)
+
+	return websocket.DefaultDialer.Dial(url, h)
+}
+
+func TestHandshake(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	cases := []struct {
+		desc     string
+		chanID   string
+		subtopic string
+		header   bool
+		key      string
+		status   int
+	}{
+		{
+			desc:   "connect and send message",
+			chanID: chanID,
+			header: true,
+			key:    thingKey,
+			status: http.StatusSwitchingProtocols,
+		},
+		{
+			desc:   "connect and send message with key as query parameter",
+			chanID: chanID,
+			header: false,
+			key:    thingKey,
+			status: http.StatusSwitchingProtocols,
+		},
+		{
+			desc:     "connect and send message to subtopic",
+			chanID:   chanID,
+			subtopic: "engine",
+			header:   true,
+			key:      thingKey,
+			status:   http.StatusSwitchingProtocols,
+		},
+		{
+			desc:     "connect and send message to malformed subtopic",
+			chanID:   chanID,
+			subtopic: "sub/a*b",
+			header:   true,
+			key:      thingKey,
+			status:   http.StatusBadRequest,
+		},
+		{
+			desc:   "connect with empty key",
+			chanID: chanID,
+			header: true,
+			key:    "",
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "connect with empty channel",
+			chanID: "",
+			header: true,
+			key:    thingKey,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		conn, res, err := handshake(ts.URL, tc.chanID, tc.subtopic, tc.key, tc.header)
+		require.NotNil(t, res, fmt.Sprintf("%s: unexpected nil response: %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code '%d' got '%d'\n", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusSwitchingProtocols {
+			continue
+		}
+		err = conn.WriteMessage(websocket.TextMessage, msg)
+		assert.Nil(t, err, fmt.Sprintf("%s: failed to send message: %s", tc.desc, err))
+		conn.Close()
+	}
+}
+
+func TestUnauthorizedHandshake(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	_, res, err := handshake(ts.URL, chanID, "", wrongKey, true)
+	require.NotNil(t, res, fmt.Sprintf("unexpected nil response: %s", err))
+	assert.Equal(t, http.StatusForbidden, res.StatusCode, fmt.Sprintf("expected status code '%d' got '%d'\n", http.StatusForbidden, res.StatusCode))
+}
+
+func TestForward(t *testing.T) {
+	svc := newService()
+	ts := newHTTPServer(svc)
+	defer ts.Close()
+
+	sub, _, err := handshake(ts.URL, chanID, "", otherKey, true)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer sub.Close()
+	pub, _, err := handshake(ts.URL, chanID, "", thingKey, true)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer pub.Close()
+
+	err = pub.WriteMessage(websocket.TextMessage, msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	sub.SetReadDeadline(time.Now().Add(time.Second))
+	_, payload, err := sub.ReadMessage()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, msg, payload, fmt.Sprintf("expected %s got %s", msg, payload))
+}
@@ -0,0 +1,24 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/pkg/errors"
+
+type connReq struct {
+	key      string
+	chanID   string
+	subtopic string
+}
+
+func (req connReq) validate() error {
+	if req.key == "" {
+		return errors.ErrAuthentication
+	}
+
+	if req.chanID == "" {
+		return errors.ErrMalformedEntity
+	}
+
+	return nil
+}
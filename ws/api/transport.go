@@ -0,0 +1,226 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-zoo/bone"
+	"github.com/gorilla/websocket"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/internal/httputil"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/ws"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	protocol    = "websocket"
+	contentType = "application/json"
+	authQuery   = "authorization"
+	readLimit   = 1 << 20
+)
+
+var channelPartRegExp = regexp.MustCompile(`^/channels/([\w\-]+)/messages(/[^?]*)?(\?.*)?$`)
+
+var errMalformedSubtopic = errors.New("malformed subtopic")
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Things are authorized using their keys, so requests from any origin are accepted.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(svc ws.Service, idp mainflux.IDProvider, logger log.Logger) http.Handler {
+	r := bone.New()
+	r.GetFunc("/channels/:id/messages", handshake(svc, idp, logger))
+	r.GetFunc("/channels/:id/messages/*", handshake(svc, idp, logger))
+	r.GetFunc("/health", mainflux.Health(protocol))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+func handshake(svc ws.Service, idp mainflux.IDProvider, logger log.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := decodeRequest(r)
+		if err != nil {
+			encodeError(w, err)
+			return
+		}
+
+		id, err := idp.ID()
+		if err != nil {
+			encodeError(w, err)
+			return
+		}
+
+		// Subscribe before the upgrade, so the unauthorized client gets
+		// the HTTP error instead of the established connection.
+		c := ws.NewClient(id)
+		if err := svc.Subscribe(context.Background(), req.key, req.chanID, req.subtopic, c); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to subscribe to channel %s: %s", req.chanID, err))
+			encodeError(w, err)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// Upgrader has already replied to the client with HTTP error.
+			logger.Warn(fmt.Sprintf("Failed to upgrade connection to websocket: %s", err))
+			unsubscribe(svc, req, c, logger)
+			return
+		}
+		conn.SetReadLimit(readLimit)
+		c.Serve(conn)
+
+		listen(svc, req, c, conn, logger)
+	}
+}
+
+// listen publishes messages received over WebSocket connection until the connection is closed.
+func listen(svc ws.Service, req connReq, c ws.Client, conn *websocket.Conn, logger log.Logger) {
+	defer unsubscribe(svc, req, c, logger)
+
+	for {
+		_, payload, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logger.Warn(fmt.Sprintf("Connection of client %s closed unexpectedly: %s", c.ID(), err))
+			}
+			return
+		}
+
+		msg := messaging.Message{
+			Protocol: protocol,
+			Channel:  req.chanID,
+			Subtopic: req.subtopic,
+			Payload:  payload,
+			Created:  time.Now().UnixNano(),
+		}
+		if err := svc.Publish(context.Background(), req.key, msg); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to publish message to channel %s: %s", req.chanID, err))
+			if errors.Contains(err, errors.ErrAuthorization) {
+				closeConn(conn, err)
+				return
+			}
+		}
+	}
+}
+
+func unsubscribe(svc ws.Service, req connReq, c ws.Client, logger log.Logger) {
+	if err := svc.Unsubscribe(context.Background(), req.chanID, req.subtopic, c.ID()); err != nil {
+		logger.Warn(fmt.Sprintf("Failed to unsubscribe client %s: %s", c.ID(), err))
+	}
+	c.Cancel()
+}
+
+func decodeRequest(r *http.Request) (connReq, error) {
+	channelParts := channelPartRegExp.FindStringSubmatch(r.RequestURI)
+	if len(channelParts) < 2 {
+		return connReq{}, errors.ErrMalformedEntity
+	}
+
+	subtopic, err := parseSubtopic(channelParts[2])
+	if err != nil {
+		return connReq{}, err
+	}
+
+	// Browsers can't set headers for WebSocket connections,
+	// so the key can be sent as a query parameter as well.
+	key, err := httputil.ReadStringQuery(r, authQuery, "")
+	if err != nil {
+		return connReq{}, err
+	}
+	if key == "" {
+		key = r.Header.Get("Authorization")
+		if strings.HasPrefix(key, httputil.BearerPrefix) {
+			key = strings.TrimPrefix(key, httputil.BearerPrefix)
+		}
+	}
+
+	req := connReq{
+		key:      key,
+		chanID:   bone.GetValue(r, "id"),
+		subtopic: subtopic,
+	}
+	if err := req.validate(); err != nil {
+		return connReq{}, err
+	}
+
+	return req, nil
+}
+
+func parseSubtopic(subtopic string) (string, error) {
+	if subtopic == "" {
+		return subtopic, nil
+	}
+
+	subtopic, err := url.QueryUnescape(subtopic)
+	if err != nil {
+		return "", errMalformedSubtopic
+	}
+	subtopic = strings.ReplaceAll(subtopic, "/", ".")
+
+	elems := strings.Split(subtopic, ".")
+	filteredElems := []string{}
+	for _, elem := range elems {
+		if elem == "" {
+			continue
+		}
+
+		if len(elem) > 1 && (strings.Contains(elem, "*") || strings.Contains(elem, ">")) {
+			return "", errMalformedSubtopic
+		}
+
+		filteredElems = append(filteredElems, elem)
+	}
+
+	subtopic = strings.Join(filteredElems, ".")
+	return subtopic, nil
+}
+
+func closeConn(conn *websocket.Conn, err error) {
+	code, reason := websocket.CloseInternalServerErr, ""
+	if errors.Contains(err, errors.ErrAuthorization) {
+		code, reason = websocket.ClosePolicyViolation, errors.ErrAuthorization.Msg()
+	}
+	msg := websocket.FormatCloseMessage(code, reason)
+	conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+	conn.Close()
+}
+
+func encodeError(w http.ResponseWriter, err error) {
+	if _, ok := err.(errors.Error); ok {
+		w.Header().Set("Content-Type", contentType)
+	}
+
+	switch {
+	case errors.Contains(err, errors.ErrAuthentication):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, errors.ErrAuthorization):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Contains(err, errMalformedSubtopic),
+		errors.Contains(err, errors.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrInvalidQueryParams):
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	if errorVal, ok := err.(errors.Error); ok {
+		json.NewEncoder(w).Encode(httputil.ErrorRes{Err: errorVal.Msg()})
+	}
+}
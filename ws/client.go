@@ -0,0 +1,128 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package ws
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+const (
+	queueSize    = 64
+	writeTimeout = 10 * time.Second
+)
+
+var (
+	// ErrSlowClient indicates that the client doesn't read the messages
+	// fast enough, so it's disconnected.
+	ErrSlowClient = errors.New("client is too slow to receive messages")
+
+	// ErrClientClosed indicates that the client connection is closed.
+	ErrClientClosed = errors.New("client is closed")
+)
+
+// Client wraps WebSocket client connection.
+type Client interface {
+	// ID returns the unique identifier of the client connection.
+	ID() string
+
+	// Handle queues the message received from the broker to be forwarded
+	// to the client. The client that can't keep up is cancelled.
+	Handle(msg messaging.Message) error
+
+	// Serve starts forwarding the queued messages to the connection.
+	Serve(conn *websocket.Conn)
+
+	// Cancel closes the client connection.
+	Cancel() error
+}
+
+var _ Client = (*client)(nil)
+
+type client struct {
+	id    string
+	queue chan messaging.Message
+	done  chan struct{}
+	once  sync.Once
+	mu    sync.Mutex
+	conn  *websocket.Conn
+}
+
+// NewClient returns a new WebSocket client. Messages are queued until
+// the connection is served, so the client can subscribe before the
+// connection is upgraded.
+func NewClient(id string) Client {
+	return &client{
+		id:    id,
+		queue: make(chan messaging.Message, queueSize),
+		done:  make(chan struct{}),
+	}
+}
+
+func (c *client) ID() string {
+	return c.id
+}
+
+func (c *client) Handle(msg messaging.Message) error {
+	select {
+	case <-c.done:
+		return ErrClientClosed
+	default:
+	}
+
+	select {
+	case c.queue <- msg:
+		return nil
+	default:
+		c.Cancel()
+		return ErrSlowClient
+	}
+}
+
+func (c *client) Serve(conn *websocket.Conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.conn = conn
+	select {
+	case <-c.done:
+		conn.Close()
+		return
+	default:
+	}
+
+	go c.write(conn)
+}
+
+// write is the only writer of the data messages to the connection.
+func (c *client) write(conn *websocket.Conn) {
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.queue:
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, msg.Payload); err != nil {
+				c.Cancel()
+				return
+			}
+		}
+	}
+}
+
+func (c *client) Cancel() error {
+	c.once.Do(func() { close(c.done) })
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
@@ -0,0 +1,58 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/ws"
+)
+
+var _ ws.Client = (*mockClient)(nil)
+
+// Client is a WebSocket client mock which stores received messages.
+type Client interface {
+	ws.Client
+
+	// Messages returns messages forwarded to the client.
+	Messages() []messaging.Message
+}
+
+type mockClient struct {
+	id       string
+	mu       sync.Mutex
+	messages []messaging.Message
+}
+
+// NewClient returns mock WebSocket client.
+func NewClient(id string) Client {
+	return &mockClient{id: id}
+}
+
+func (c *mockClient) ID() string {
+	return c.id
+}
+
+func (c *mockClient) Handle(msg messaging.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messages = append(c.messages, msg)
+	return nil
+}
+
+func (c *mockClient) Serve(conn *websocket.Conn) {}
+
+func (c *mockClient) Cancel() error {
+	return nil
+}
+
+func (c *mockClient) Messages() []messaging.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]messaging.Message{}, c.messages...)
+}